@@ -3,40 +3,96 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
+	"net/http"
 	"os"
+	"strings"
 	"text/template"
+	"time"
 
 	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
 
+	"github.com/Optum/dce/pkg/account"
 	"github.com/Optum/dce/pkg/common"
 	"github.com/Optum/dce/pkg/db"
+	errors2 "github.com/Optum/dce/pkg/errors"
 	"github.com/Optum/dce/pkg/reset"
-	"github.com/avast/retry-go"
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/service/athena"
+	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/sts"
 )
 
 // main will run through the reset process for an account which involves using
-// aws-nuke
+// aws-nuke.
+// The reset is recorded as a reset run, when it starts and when it completes,
+// along with a link to the report of the resources removed by aws-nuke.
 func main() {
 	// Initialize a service container
 	svc := &service{}
 	config := svc.config()
+
+	// Failing to record the reset shouldn't stop the account from being reset
+	run, err := svc.resetRunService().Start(config.childAccountID, config.resetReason, config.buildID)
+	if err != nil {
+		log.Printf("WARN: Failed to record the start of the reset for account %s: %s\n", config.childAccountID, err)
+	}
+
+	report, resetErr := resetAccount(svc)
+
+	if report != nil {
+		location, err := putNukeReport(svc, report)
+		if err != nil {
+			log.Printf("WARN: Failed to upload the nuke report for account %s: %s\n", config.childAccountID, err)
+		} else if run != nil {
+			run.Report = &location
+		}
+	}
+
+	if run != nil {
+		_, err = svc.resetRunService().End(run, resetErr)
+		if err != nil {
+			log.Printf("WARN: Failed to record the end of the reset for account %s: %s\n", config.childAccountID, err)
+		}
+	}
+
+	if resetErr != nil {
+		log.Fatalf("%s\n", resetErr)
+	}
+}
+
+// resetAccount deletes all resources from the account, and updates the DB
+// with the account and lease statuses once the account is reset.
+// Returns the aws-nuke report, if aws-nuke was run.
+func resetAccount(svc *service) (*reset.NukeReport, error) {
+	config := svc.config()
 	awsSession := svc.awsSession()
 	tokenService := svc.tokenService()
 
 	//get current Account ID
 	caller, err := tokenService.Client.GetCallerIdentity(&sts.GetCallerIdentityInput{})
 	if err != nil {
-		log.Fatalf("Failed to get code build account information: %s\n", err)
+		return nil, errors.Wrap(err, "Failed to get code build account information")
 	}
 	_config.parentAccountID = *caller.Account
 
+	// Get the nuke filters of the account, to keep its resources matching them.
+	// Deleted accounts are reset after their record is removed, so have no filters.
+	acct, err := svc.accountData().Get(config.childAccountID)
+	switch {
+	case errors2.HTTPCodeForError(err) == http.StatusNotFound:
+		log.Printf("INFO: Account %s was deleted, resetting it without nuke filters\n", config.childAccountID)
+	case err != nil:
+		return nil, errors.Wrapf(err, "Failed to get account %s", config.childAccountID)
+	default:
+		_config.nukeFilters = acct.NukeFilters
+	}
+
 	if !config.isNukeEnabled {
 		log.Println("INFO: Nuke is set in Dry Run mode and will not remove " +
 			"any resources and cannot set back the state of the DCE child account " +
@@ -44,49 +100,103 @@ func main() {
 			"mode.")
 	}
 
-	// Delete items nuke doesn't support currently
-	if config.isNukeEnabled {
-
-		roleArn := "arn:aws:iam::" + config.childAccountID + ":role/" + config.accountAdminRoleName
-
-		// Delete Athena resources
-		log.Println("Starting Athena nuking")
-
-		athenaCreds := tokenService.NewCredentials(awsSession, roleArn)
-		athenaClient := athena.New(awsSession, &aws.Config{
-			Credentials: athenaCreds,
-		})
-		athenaReset := &reset.AthenaReset{
-			Client: athenaClient,
-		}
-		err = reset.DeleteAthenaResources(athenaReset)
-		if err != nil {
-			log.Fatalf("Failed to execute aws-nuke athena on account %s: %s\n", config.childAccountID, err)
+	// Clean up resources nuke doesn't support, or can't delete alone.
+	// Resetters run in dry run mode when nuke does.
+	resetters := reset.NewDefaultRegistry(
+		awsSession,
+		tokenService.NewCredentials(awsSession, config.accountAdminRoleARN),
+	)
+	resetterInput := &reset.ResetterInput{
+		AccountID: config.childAccountID,
+		Regions:   config.nukeRegions,
+		DryRun:    !config.isNukeEnabled,
+		Filters:   config.nukeFilters,
+	}
+	// A failed resetter may leave resources behind, but aws-nuke can still
+	// remove everything else. The failures are reported once nuke has run.
+	preNukeErr := resetters.Run(reset.PhasePreNuke, resetterInput)
+	if preNukeErr != nil {
+		log.Printf("WARN: Failed to clean up account %s before aws-nuke, running aws-nuke anyway: %s\n",
+			config.childAccountID, preNukeErr)
+		if runErr, ok := preNukeErr.(*reset.RunError); ok && len(runErr.Lockouts()) > 0 {
+			flagLockouts(svc, acct, runErr.Lockouts())
 		}
 	}
 
 	// Execute aws-nuke, to delete all resources from the account
-	err = nukeAccount(
+	report, err := nukeAccount(
 		svc,
 		// Execute nuke as a dry run, if isNukeEnabled is off
 		!config.isNukeEnabled,
 	)
 	if err != nil {
-		log.Fatalf("Failed to execute aws-nuke on account %s: %s\n", config.childAccountID, err)
+		if preNukeErr != nil {
+			err = errors.Wrap(err, preNukeErr.Error())
+		}
+		return report, errors.Wrapf(err, "Failed to execute aws-nuke on account %s", config.childAccountID)
 	}
 	log.Printf("%s  :  Nuke Success\n", config.childAccountID)
 
+	// Restore resources nuke removed, which accounts are expected to have
+	err = resetters.Run(reset.PhasePostNuke, resetterInput)
+	if err != nil {
+		return report, errors.Wrapf(err, "Failed to restore account %s after aws-nuke", config.childAccountID)
+	}
+	if preNukeErr != nil {
+		return report, errors.Wrapf(preNukeErr, "Failed to clean up account %s", config.childAccountID)
+	}
+
+	// The account is no longer locked out of anything
+	if acct != nil && acct.StatusReason != nil && acct.Status != nil && *acct.Status == account.StatusNotReady {
+		setStatusReason(svc, acct, nil)
+	}
+
 	// Update the DB with Account/Lease statuses
 	err = updateDBPostReset(svc.db(), svc.snsService(), config.childAccountID, common.RequireEnv("RESET_COMPLETE_TOPIC_ARN"))
 	if err != nil {
-		log.Fatalf("Failed to update the DB post-reset for account %s:  %s", config.childAccountID, err)
+		return report, errors.Wrapf(err, "Failed to update the DB post-reset for account %s", config.childAccountID)
+	}
+
+	return report, nil
+}
+
+// flagLockouts records the resources which deny the admin role access as the
+// status reason of the account. The account stays NotReady, as every reset
+// fails until someone with access to the resources, such as the root user of
+// the account, removes them. Failing to flag the account is only logged.
+func flagLockouts(svc *service, acct *account.Account, lockouts []*reset.LockoutError) {
+	if acct == nil {
+		return
+	}
+
+	reasons := []string{}
+	for _, lockout := range lockouts {
+		reasons = append(reasons, lockout.Error())
+	}
+	reason := fmt.Sprintf("Reset blocked, remove these resources as the account root user: %s",
+		strings.Join(reasons, "; "))
+	log.Printf("WARN: Account %s is locked out of resources: %s\n", *acct.ID, reason)
+	setStatusReason(svc, acct, &reason)
+}
+
+// setStatusReason saves the status reason of the account.
+// Failing to save it is only logged.
+func setStatusReason(svc *service, acct *account.Account, reason *string) {
+	prevLastModifiedOn := acct.LastModifiedOn
+	lastModifiedOn := time.Now().Unix()
+	acct.StatusReason = reason
+	acct.LastModifiedOn = &lastModifiedOn
+	err := svc.accountData().Write(acct, prevLastModifiedOn)
+	if err != nil {
+		log.Printf("WARN: Failed to save the status reason of account %s: %s\n", *acct.ID, err)
 	}
 }
 
 // updateDBPostReset changes any leases for the Account
 // from "Status=ResetLock" to "Status=Active"
 // Also, if the account was set as "Status=NotReady",
-// will update to "Status=Ready"
+// will update to "Status=Ready", and if the account was
+// set as "Status=Retiring", will update to "Status=Retired"
 func updateDBPostReset(dbSvc db.DBer, snsSvc common.Notificationer, accountID string, snsTopicArn string) error {
 
 	// If the Account.Status=NotReady, change it back to Status=Ready
@@ -101,9 +211,20 @@ func updateDBPostReset(dbSvc db.DBer, snsSvc common.Notificationer, accountID st
 		if _, ok := err.(*db.StatusTransitionError); !ok {
 			return err
 		}
-		account, err = dbSvc.GetAccount(accountID)
+
+		// If the Account.Status=Retiring, this was its last reset
+		log.Printf("Setting Account Status from Retiring to Retired: %s", accountID)
+		account, err = dbSvc.TransitionAccountStatus(
+			accountID,
+			db.Retiring, db.Retired)
 		if err != nil {
-			return err
+			if _, ok := err.(*db.StatusTransitionError); !ok {
+				return err
+			}
+			account, err = dbSvc.GetAccount(accountID)
+			if err != nil {
+				return err
+			}
 		}
 	}
 
@@ -122,7 +243,7 @@ func updateDBPostReset(dbSvc db.DBer, snsSvc common.Notificationer, accountID st
 	return nil
 }
 
-func nukeAccount(svc *service, isDryRun bool) error {
+func nukeAccount(svc *service, isDryRun bool) (*reset.NukeReport, error) {
 	// Generate the configuration of the yaml file using the template file
 	// provided and substituting necessary phrases.
 
@@ -133,11 +254,11 @@ func nukeAccount(svc *service, isDryRun bool) error {
 	f, err := os.Create(configFile)
 	if err != nil {
 		log.Fatalf("Failed to create file %s: %s", configFile, err)
-		return err
+		return nil, err
 	}
 	err = generateNukeConfig(svc, f)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Print the contents of the config file, for logging/debugging
@@ -146,7 +267,7 @@ func nukeAccount(svc *service, isDryRun bool) error {
 	*/
 	conf, err := ioutil.ReadFile(configFile)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	log.Println("Rendered nuke file:")
 	log.Print(string(conf))
@@ -165,18 +286,36 @@ func nukeAccount(svc *service, isDryRun bool) error {
 	}
 
 	// Nukes based on the configuration file that is generated
-	// Attempt Nuke 3 times in the case not all resources get deleted
-	err = retry.Do(
-		func() error {
-			return reset.NukeAccount(&nukeAccountInput)
-		},
-		retry.Attempts(3),         // Retry 3 times
-		retry.LastErrorOnly(true), // Only return the last error
-	)
+	// Attempt Nuke 3 times in the case not all resources get deleted,
+	// retrying only the resource types and regions which failed
+	return reset.NukeAccountWithRetries(&nukeAccountInput, 3)
+}
+
+// putNukeReport writes the aws-nuke report as JSON to the reset report bucket,
+// and returns its S3 location
+func putNukeReport(svc *service, report *reset.NukeReport) (string, error) {
+	config := svc.config()
+
+	body, err := json.Marshal(report)
 	if err != nil {
-		return err
+		return "", err
 	}
-	return nil
+
+	key := fmt.Sprintf("%s/%s/%d.json", config.resetReportPrefix, config.childAccountID, time.Now().Unix())
+	_, err = svc.s3Service().Client.PutObject(&s3.PutObjectInput{
+		Bucket:               aws.String(config.resetReportBucket),
+		Key:                  aws.String(key),
+		Body:                 bytes.NewReader(body),
+		ContentType:          aws.String("application/json"),
+		ServerSideEncryption: aws.String("AES256"),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	location := fmt.Sprintf("s3://%s/%s", config.resetReportBucket, key)
+	log.Printf("Wrote nuke report to %s", location)
+	return location, nil
 }
 
 func generateNukeConfig(svc *service, f io.Writer) error {
@@ -221,7 +360,8 @@ func generateNukeConfig(svc *service, f io.Writer) error {
 		Regions         []string
 	}
 
-	err = template.ExecuteTemplate(f, templateFile, &templateParams{
+	var nukeConfig bytes.Buffer
+	err = template.ExecuteTemplate(&nukeConfig, templateFile, &templateParams{
 		ParentAccountID: config.parentAccountID,
 		ID:              config.childAccountID,
 		AdminRole:       config.accountAdminRoleName,
@@ -235,5 +375,85 @@ func generateNukeConfig(svc *service, f io.Writer) error {
 		return err
 	}
 
-	return nil
+	// Add the nuke filters of the account to the filters of the template
+	conf := nukeConfig.Bytes()
+	if len(config.nukeFilters) > 0 {
+		conf, err = addNukeFilters(conf, config.childAccountID, config.nukeFilters)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to add the nuke filters of account %s to template %s",
+				config.childAccountID, templateFile)
+		}
+	}
+
+	_, err = f.Write(conf)
+	return err
+}
+
+// addNukeFilters adds nuke filters to the filters of the account in a rendered
+// aws-nuke config, after any filters of the same resource type from the template
+func addNukeFilters(conf []byte, accountID string, filters account.NukeFilters) ([]byte, error) {
+	var nukeConfig map[string]interface{}
+	err := yaml.Unmarshal(conf, &nukeConfig)
+	if err != nil {
+		return nil, err
+	}
+	if nukeConfig == nil {
+		nukeConfig = map[string]interface{}{}
+	}
+
+	accounts := yamlMap(nukeConfig["accounts"])
+	nukeConfig["accounts"] = accounts
+	// The account ID may be parsed as a number, if it isn't quoted in the template
+	var accountKey interface{} = accountID
+	for key := range accounts {
+		if fmt.Sprint(key) == accountID {
+			accountKey = key
+		}
+	}
+	accountConfig := yamlMap(accounts[accountKey])
+	accounts[accountKey] = accountConfig
+	accountFilters := yamlMap(accountConfig["filters"])
+	accountConfig["filters"] = accountFilters
+
+	for resourceType, resourceFilters := range filters {
+		typeFilters, _ := accountFilters[resourceType].([]interface{})
+		for _, filter := range resourceFilters {
+			typeFilters = append(typeFilters, nukeFilterConfig(filter))
+		}
+		accountFilters[resourceType] = typeFilters
+	}
+
+	return yaml.Marshal(nukeConfig)
+}
+
+// yamlMap returns the value as a map, or a new map if it isn't one
+func yamlMap(value interface{}) map[interface{}]interface{} {
+	m, ok := value.(map[interface{}]interface{})
+	if !ok {
+		return map[interface{}]interface{}{}
+	}
+	return m
+}
+
+// nukeFilterConfig returns the filter as configured for aws-nuke.
+// Filters with only a value match the resource name exactly,
+// so they're rendered as the value alone.
+func nukeFilterConfig(filter account.NukeFilter) interface{} {
+	if filter.Type == nil && filter.Property == nil && filter.Invert == nil {
+		return aws.StringValue(filter.Value)
+	}
+
+	config := yaml.MapSlice{}
+	if filter.Type != nil {
+		config = append(config, yaml.MapItem{Key: "type", Value: filter.Type.String()})
+	}
+	if filter.Property != nil {
+		config = append(config, yaml.MapItem{Key: "property", Value: *filter.Property})
+	}
+	config = append(config, yaml.MapItem{Key: "value", Value: aws.StringValue(filter.Value)})
+	if aws.BoolValue(filter.Invert) {
+		// aws-nuke reads invert as a string
+		config = append(config, yaml.MapItem{Key: "invert", Value: "true"})
+	}
+	return config
 }
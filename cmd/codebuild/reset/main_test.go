@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/Optum/dce/pkg/account"
 	commonMocks "github.com/Optum/dce/pkg/common/mocks"
 	"github.com/Optum/dce/pkg/db"
 	"github.com/Optum/dce/pkg/db/mocks"
@@ -14,6 +15,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
 )
 
 func TestResetPipeline(t *testing.T) {
@@ -68,6 +70,9 @@ func TestResetPipeline(t *testing.T) {
 			dbSvc.
 				On("TransitionAccountStatus", "111", db.NotReady, db.Ready).
 				Return(nil, &db.StatusTransitionError{})
+			dbSvc.
+				On("TransitionAccountStatus", "111", db.Retiring, db.Retired).
+				Return(nil, &db.StatusTransitionError{})
 
 			dbSvc.
 				On("GetAccount", "111").
@@ -96,7 +101,41 @@ func TestResetPipeline(t *testing.T) {
 
 			err := updateDBPostReset(dbSvc, snsSvc, "111", "Topic")
 			dbSvc.AssertNumberOfCalls(t, "TransitionLeaseStatus", 0)
-			dbSvc.AssertNumberOfCalls(t, "TransitionAccountStatus", 1)
+			dbSvc.AssertNumberOfCalls(t, "TransitionAccountStatus", 2)
+			require.Nil(t, err)
+		})
+
+		t.Run("Should change account status from Retiring to Retired", func(t *testing.T) {
+			dbSvc := &mocks.DBer{}
+			snsSvc := &commonMocks.Notificationer{}
+			defer dbSvc.AssertExpectations(t)
+
+			dbSvc.
+				On("TransitionAccountStatus", "111", db.NotReady, db.Ready).
+				Return(nil, &db.StatusTransitionError{})
+			dbSvc.
+				On("TransitionAccountStatus", "111", db.Retiring, db.Retired).
+				Return(&db.Account{ID: "111", AccountStatus: db.Retired}, nil)
+
+			snsSvc.On("PublishMessage",
+				mock.MatchedBy(func(arn *string) bool {
+					return *arn == "Topic"
+				}),
+				mock.MatchedBy(func(message *string) bool {
+					messageObj := unmarshal(t, *message)
+					msgBody := unmarshal(t, messageObj["Body"].(string))
+
+					assert.Equal(t, "111", msgBody["Id"])
+					assert.Equal(t, "Retired", msgBody["AccountStatus"])
+
+					return true
+				}), true,
+			).Return(aws.String("mock message"), nil)
+			defer snsSvc.AssertExpectations(t)
+
+			err := updateDBPostReset(dbSvc, snsSvc, "111", "Topic")
+			dbSvc.AssertNumberOfCalls(t, "GetAccount", 0)
+			dbSvc.AssertNumberOfCalls(t, "TransitionAccountStatus", 2)
 			require.Nil(t, err)
 		})
 
@@ -143,6 +182,65 @@ func TestResetPipeline(t *testing.T) {
 		want := "regions:\n  - \"global\"\n  # DCE Principals roles are currently locked down\n  # to only access these two regions\n  # This significantly reduces the run time of nuke.\n  - \"us-east-1\"\n  - \"us-west-1\"\n\naccount-blacklist:\n  - \"DEF456\" # Arbitrary production account id\n\nresource-types:\n  excludes:\n    - S3Object # Let the S3Bucket delete all Objects instead of individual objects (optimization)\n\naccounts:\n  \"ABC123\": # Child Account\n    filters:\n      IAMPolicy:\n        - type: \"contains\"\n          value: \"PrincipalPolicy\"\n      IAMRole:\n        - \"AdminRole\"\n        - \"PrincipalRole\"\n      IAMRolePolicy:\n        - type: \"contains\"\n          value: \"AdminRole\"\n        - type: \"contains\"\n          value: \"PrincipalRole\"\n        - type: \"contains\"\n          value: \"PrincipalPolicy\"\n      IAMRolePolicyAttachment:\n        # Do not remove the policy from the principal user role\n        - \"PrincipalRole -> PrincipalPolicy\"\n        - property: RoleName\n          value: \"AdminRole\"\n"
 		assert.Equal(t, got, want, "Template subsitition works")
 	})
+
+	t.Run("testNukeConfigGenerationWithNukeFilters", func(t *testing.T) {
+
+		var b bytes.Buffer
+		_config = &serviceConfig{
+			parentAccountID:            "DEF456",
+			childAccountID:             "123456789012",
+			accountAdminRoleName:       "AdminRole",
+			nukeRegions:                []string{"us-east-1"},
+			accountPrincipalRoleName:   "PrincipalRole",
+			accountPrincipalPolicyName: "PrincipalPolicy",
+			nukeTemplateDefault:        "default-nuke-config-template.yml",
+			nukeTemplateBucket:         "STUB",
+			nukeTemplateKey:            "STUB",
+			nukeFilters: account.NukeFilters{
+				"S3Bucket": {
+					{Value: aws.String("s3://baseline-data")},
+				},
+				"EC2VPC": {
+					{
+						Type:     account.NukeFilterTypeGlob.NukeFilterTypePtr(),
+						Property: aws.String("tag:Name"),
+						Value:    aws.String("shared-*"),
+						Invert:   aws.Bool(true),
+					},
+				},
+				"IAMRole": {
+					{Type: account.NukeFilterTypeContains.NukeFilterTypePtr(), Value: aws.String("Baseline")},
+				},
+			},
+		}
+		svc := service{}
+
+		err := generateNukeConfig(&svc, &b)
+		require.Nil(t, err)
+
+		var got struct {
+			Regions  []string `yaml:"regions"`
+			Accounts map[string]struct {
+				Filters map[string][]interface{} `yaml:"filters"`
+			} `yaml:"accounts"`
+		}
+		err = yaml.Unmarshal(b.Bytes(), &got)
+		require.Nil(t, err)
+
+		assert.Equal(t, []string{"global", "us-east-1"}, got.Regions)
+		filters := got.Accounts["123456789012"].Filters
+		assert.Equal(t, []interface{}{"s3://baseline-data"}, filters["S3Bucket"])
+		assert.Equal(t, []interface{}{
+			map[interface{}]interface{}{"type": "glob", "property": "tag:Name", "value": "shared-*", "invert": "true"},
+		}, filters["EC2VPC"])
+		// Filters of the template are kept
+		assert.Equal(t, []interface{}{
+			"AdminRole",
+			"PrincipalRole",
+			map[interface{}]interface{}{"type": "contains", "value": "Baseline"},
+		}, filters["IAMRole"])
+		assert.Len(t, filters["IAMPolicy"], 1)
+	})
 }
 
 func unmarshal(t *testing.T, jsonStr string) map[string]interface{} {
@@ -4,9 +4,13 @@ import (
 	"log"
 	"os"
 
+	"github.com/Optum/dce/pkg/account"
 	"github.com/Optum/dce/pkg/common"
+	"github.com/Optum/dce/pkg/data"
 	"github.com/Optum/dce/pkg/db"
+	"github.com/Optum/dce/pkg/resetrun"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/aws/aws-sdk-go/service/sns"
@@ -22,6 +26,8 @@ var (
 	_s3Service    *common.S3
 	_snsService   *common.SNS
 	_db           *db.DB
+	_accountData  *data.Account
+	_resetRun     *resetrun.Service
 )
 
 // service struct holds all the services to be used by
@@ -39,11 +45,17 @@ type serviceConfig struct {
 	accountAdminRoleName       string
 	accountAdminRoleARN        string
 	nukeRegions                []string
+	resetReason                string
+	buildID                    string
+	nukeFilters                account.NukeFilters
 
 	isNukeEnabled       bool
 	nukeTemplateDefault string
 	nukeTemplateBucket  string
 	nukeTemplateKey     string
+
+	resetReportBucket string
+	resetReportPrefix string
 }
 
 func (svc *service) config() *serviceConfig {
@@ -64,6 +76,12 @@ func (svc *service) config() *serviceConfig {
 		nukeTemplateBucket:  common.RequireEnv("RESET_NUKE_TEMPLATE_BUCKET"),
 		nukeTemplateKey:     common.RequireEnv("RESET_NUKE_TEMPLATE_KEY"),
 		nukeRegions:         common.RequireEnvStringSlice("RESET_NUKE_REGIONS", ","),
+		resetReason:         os.Getenv("RESET_REASON"),
+		// Set by CodeBuild, or by the reset worker
+		buildID: common.GetEnv("RESET_BUILD_ID", os.Getenv("CODEBUILD_BUILD_ID")),
+
+		resetReportBucket: common.RequireEnv("RESET_REPORT_BUCKET"),
+		resetReportPrefix: common.GetEnv("RESET_REPORT_PREFIX", "reset-reports"),
 	}
 
 	return _config
@@ -118,6 +136,19 @@ func (svc *service) db() *db.DB {
 	return _db
 }
 
+// accountData reads the account being reset
+func (svc *service) accountData() *data.Account {
+	if _accountData == nil {
+		_accountData = &data.Account{
+			DynamoDB:       dynamodb.New(svc.awsSession()),
+			TableName:      common.RequireEnv("ACCOUNT_DB"),
+			ConsistentRead: true,
+		}
+	}
+
+	return _accountData
+}
+
 func (svc *service) snsService() *common.SNS {
 	if _snsService == nil {
 		_snsService = &common.SNS{
@@ -127,3 +158,16 @@ func (svc *service) snsService() *common.SNS {
 
 	return _snsService
 }
+
+func (svc *service) resetRunService() *resetrun.Service {
+	if _resetRun == nil {
+		_resetRun = resetrun.NewService(resetrun.NewServiceInput{
+			DataSvc: &data.ResetRun{
+				DynamoDB:  dynamodb.New(svc.awsSession()),
+				TableName: common.RequireEnv("RESET_RUN_DB"),
+			},
+		})
+	}
+
+	return _resetRun
+}
@@ -20,6 +20,8 @@ func TestService(t *testing.T) {
 		"RESET_NUKE_TEMPLATE_DEFAULT",
 		"RESET_NUKE_TEMPLATE_BUCKET",
 		"RESET_NUKE_TEMPLATE_KEY",
+		"RESET_REASON",
+		"RESET_REPORT_BUCKET",
 	}
 	for _, envKey := range envVars {
 		_ = os.Setenv(envKey, envKey+"_VAL")
@@ -48,6 +50,9 @@ func TestService(t *testing.T) {
 			require.Equal(t, "RESET_NUKE_TEMPLATE_BUCKET_VAL", config.nukeTemplateBucket)
 			require.Equal(t, "RESET_NUKE_TEMPLATE_KEY_VAL", config.nukeTemplateKey)
 			require.Equal(t, []string{"us-east-1", "us-west-1"}, config.nukeRegions)
+			require.Equal(t, "RESET_REASON_VAL", config.resetReason)
+			require.Equal(t, "RESET_REPORT_BUCKET_VAL", config.resetReportBucket)
+			require.Equal(t, "reset-reports", config.resetReportPrefix)
 
 			// Check computed config vals
 			require.Equal(t, "arn:aws:iam::RESET_ACCOUNT_VAL:role/RESET_ACCOUNT_ADMIN_ROLE_NAME_VAL", config.accountAdminRoleARN)
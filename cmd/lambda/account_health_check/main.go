@@ -0,0 +1,210 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/Optum/dce/pkg/account"
+	"github.com/Optum/dce/pkg/config"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+)
+
+type configuration struct {
+	Debug string `env:"DEBUG" envDefault:"false"`
+	// FailureThreshold is the number of consecutive failed health checks
+	// before an account is orphaned
+	FailureThreshold int64 `env:"HEALTH_CHECK_FAILURE_THRESHOLD" envDefault:"3"`
+}
+
+// checkAttempts is the number of times a check which fails to run,
+// e.g. because STS is throttled, is attempted before giving up
+const checkAttempts = 3
+
+var (
+	services *config.ServiceBuilder
+	// Settings - the configuration settings for the controller
+	settings *configuration
+	// checkRetryDelay is the delay before a check which failed to run is retried
+	checkRetryDelay = 2 * time.Second
+)
+
+func init() {
+	cfgBldr := &config.ConfigurationBuilder{}
+	settings = &configuration{}
+	if err := cfgBldr.Unmarshal(settings); err != nil {
+		log.Fatalf("Could not load configuration: %s", err.Error())
+	}
+
+	// load up the values into the various settings...
+	err := cfgBldr.WithEnv("AWS_CURRENT_REGION", "AWS_CURRENT_REGION", "us-east-1").Build()
+	if err != nil {
+		log.Printf("Error: %+v", err)
+	}
+	svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+
+	_, err = svcBldr.
+		WithAccountService().
+		WithLeaseService().
+		Build()
+	if err != nil {
+		panic(err)
+	}
+
+	services = svcBldr
+}
+
+func main() {
+	lambda.Start(handler)
+}
+
+// handler checks the health of every account in the pool. Accounts which fail
+// several checks in a row are orphaned, and Orphaned accounts which pass again
+// are returned to the pool.
+func handler(cloudWatchEvent events.CloudWatchEvent) error {
+
+	var errs []error
+
+	query := &account.Account{}
+	err := services.AccountService().ListPages(query,
+		func(accts *account.Accounts) bool {
+			for _, acct := range *accts {
+				acct := acct
+				err := checkAccount(&acct)
+				if err != nil {
+					log.Printf("Failed to check the health of account %s: %s", *acct.ID, err)
+					errs = append(errs, err)
+				}
+			}
+			return true
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	if len(errs) > 0 {
+		return errors.NewMultiError("error when checking account health", errs)
+	}
+	return nil
+}
+
+// checkAccount orphans an account which has failed its health checks
+// FailureThreshold times in a row, or recovers an Orphaned account which passes them
+func checkAccount(acct *account.Account) error {
+	if acct.Status == nil {
+		return nil
+	}
+
+	switch *acct.Status {
+	case account.StatusRetiring, account.StatusRetired:
+		// Retiring accounts are leaving the pool anyway
+		return nil
+	case account.StatusOrphaned:
+		// Principal access is restored when the account is recovered,
+		// so only the admin role is checked
+		reason, err := validateAdminAccess(acct)
+		if err != nil {
+			return err
+		}
+		if reason != "" {
+			log.Printf("Account %s is still unhealthy: %s", *acct.ID, reason)
+			return nil
+		}
+
+		_, err = services.AccountService().Recover(acct)
+		if err != nil {
+			return err
+		}
+		log.Printf("Recovered orphaned account %s", *acct.ID)
+		return nil
+	}
+
+	reason, err := validateAdminAccess(acct)
+	if err != nil {
+		return err
+	}
+	if reason == "" {
+		reason, err = retryCheck(func() error {
+			return services.AccountManager().ValidatePrincipalAccess(acct)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if reason == "" {
+		if acct.HealthCheckFailures == nil {
+			return nil
+		}
+		// The account has recovered, before it was orphaned
+		acct.HealthCheckFailures = nil
+		return services.AccountService().Save(acct)
+	}
+
+	failures := int64(1)
+	if acct.HealthCheckFailures != nil {
+		failures = *acct.HealthCheckFailures + 1
+	}
+	if failures < settings.FailureThreshold {
+		log.Printf("Account %s failed %d of %d health checks before it is orphaned: %s", *acct.ID, failures, settings.FailureThreshold, reason)
+		acct.HealthCheckFailures = &failures
+		return services.AccountService().Save(acct)
+	}
+
+	_, err = services.AccountService().Orphan(acct, reason)
+	if err != nil {
+		return err
+	}
+
+	leases, err := services.LeaseService().Orphan(*acct.ID)
+	if err != nil {
+		return err
+	}
+	for _, l := range *leases {
+		log.Printf("Inactivated lease %s for %s on orphaned account %s", *l.ID, *l.PrincipalID, *acct.ID)
+	}
+
+	return nil
+}
+
+// validateAdminAccess checks the admin role can be assumed, and that
+// no service control policy blocks it from managing the account.
+// Returns the reason the account is unhealthy, if any.
+func validateAdminAccess(acct *account.Account) (string, error) {
+	reason, err := retryCheck(func() error {
+		return services.AccountManager().ValidateAccess(acct.AdminRoleArn)
+	})
+	if reason != "" || err != nil {
+		return reason, err
+	}
+
+	return retryCheck(func() error {
+		return services.AccountManager().ValidateServiceControlPolicies(acct.AdminRoleArn)
+	})
+}
+
+// retryCheck runs a health check, and retries it when the check itself fails,
+// e.g. because STS is throttled. Returns the reason the account is unhealthy, if any.
+func retryCheck(check func() error) (string, error) {
+	reason, err := healthFailure(check())
+	for attempt := 1; err != nil && attempt < checkAttempts; attempt++ {
+		log.Printf("Retrying health check, which failed to run: %s", err)
+		time.Sleep(checkRetryDelay)
+		reason, err = healthFailure(check())
+	}
+	return reason, err
+}
+
+// healthFailure separates the account failing a check from the check
+// itself failing. Only validation errors mean the account is unhealthy.
+func healthFailure(err error) (string, error) {
+	if err == nil {
+		return "", nil
+	}
+	if errors.HTTPCodeForError(err) == http.StatusBadRequest {
+		return err.Error(), nil
+	}
+	return "", err
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Optum/dce/pkg/account"
+	accountMocks "github.com/Optum/dce/pkg/account/accountiface/mocks"
+	amMocks "github.com/Optum/dce/pkg/accountmanager/accountmanageriface/mocks"
+	"github.com/Optum/dce/pkg/arn"
+	"github.com/Optum/dce/pkg/config"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/lease"
+	leaseMocks "github.com/Optum/dce/pkg/lease/leaseiface/mocks"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLambdaHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		status         account.Status
+		failures       *int64
+		accessErr      error
+		principalErr   error
+		scpErr         error
+		expOrphan      string
+		expRecover     bool
+		expLeaseOrphan bool
+		expFailures    *int64
+		expSave        bool
+		expErr         error
+	}{
+		{
+			name:   "when a ready account is healthy. Nothing changes",
+			status: account.StatusReady,
+		},
+		{
+			name:           "when the admin role can't be assumed. The account is orphaned once it fails the threshold",
+			status:         account.StatusReady,
+			failures:       ptrInt64(2),
+			accessErr:      errors.NewValidation("account", fmt.Errorf("error")),
+			expOrphan:      "account validation error: error",
+			expLeaseOrphan: true,
+		},
+		{
+			name:           "when the principal role is missing. The account is orphaned once it fails the threshold",
+			status:         account.StatusLeased,
+			failures:       ptrInt64(2),
+			principalErr:   errors.NewValidation("account", fmt.Errorf("principal role does not exist")),
+			expOrphan:      "account validation error: principal role does not exist",
+			expLeaseOrphan: true,
+		},
+		{
+			name:           "when an SCP blocks the account. The account is orphaned once it fails the threshold",
+			status:         account.StatusReady,
+			failures:       ptrInt64(2),
+			scpErr:         errors.NewValidation("account", fmt.Errorf("action denied")),
+			expOrphan:      "account validation error: action denied",
+			expLeaseOrphan: true,
+		},
+		{
+			name:        "when an account first fails a check. The failure is counted, and it is not orphaned",
+			status:      account.StatusReady,
+			accessErr:   errors.NewValidation("account", fmt.Errorf("error")),
+			expFailures: ptrInt64(1),
+			expSave:     true,
+		},
+		{
+			name:        "when an account which failed a check is healthy again. Its failures are cleared",
+			status:      account.StatusLeased,
+			failures:    ptrInt64(2),
+			expFailures: nil,
+			expSave:     true,
+		},
+		{
+			name:         "when a check fails unexpectedly. The account is not orphaned",
+			status:       account.StatusReady,
+			principalErr: errors.NewInternalServer("failure", fmt.Errorf("error")),
+			expErr: errors.NewMultiError("error when checking account health", []error{
+				errors.NewInternalServer("failure", nil),
+			}),
+		},
+		{
+			name:       "when an orphaned account is healthy. The account is recovered",
+			status:     account.StatusOrphaned,
+			expRecover: true,
+		},
+		{
+			name:      "when an orphaned account is unhealthy. Nothing changes",
+			status:    account.StatusOrphaned,
+			accessErr: errors.NewValidation("account", fmt.Errorf("error")),
+		},
+		{
+			name:      "when an account is retiring. It is not checked",
+			status:    account.StatusRetiring,
+			accessErr: errors.NewValidation("account", fmt.Errorf("error")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfgBldr := &config.ConfigurationBuilder{}
+			svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+
+			settings = &configuration{FailureThreshold: 3}
+			checkRetryDelay = 0
+
+			acct := &account.Account{
+				ID:                  ptrString("123456789012"),
+				Status:              tt.status.StatusPtr(),
+				AdminRoleArn:        arn.New("aws", "iam", "", "123456789012", "role/AdminRole"),
+				HealthCheckFailures: tt.failures,
+			}
+
+			accountSvc := &accountMocks.Servicer{}
+			accountSvc.On("ListPages", mock.AnythingOfType("*account.Account"), mock.Anything).
+				Run(func(args mock.Arguments) {
+					fn := args.Get(1).(func(*account.Accounts) bool)
+					fn(&account.Accounts{*acct})
+				}).Return(nil)
+			accountSvc.On("Orphan", mock.AnythingOfType("*account.Account"), tt.expOrphan).Return(acct, nil)
+			accountSvc.On("Recover", mock.AnythingOfType("*account.Account")).Return(acct, nil)
+			accountSvc.On("Save", mock.AnythingOfType("*account.Account")).Return(nil)
+
+			managerSvc := &amMocks.Servicer{}
+			managerSvc.On("ValidateAccess", acct.AdminRoleArn).Return(tt.accessErr)
+			managerSvc.On("ValidateServiceControlPolicies", acct.AdminRoleArn).Return(tt.scpErr)
+			managerSvc.On("ValidatePrincipalAccess", mock.AnythingOfType("*account.Account")).Return(tt.principalErr)
+
+			leaseSvc := &leaseMocks.Servicer{}
+			leaseSvc.On("Orphan", "123456789012").Return(&lease.Leases{}, nil)
+
+			svcBldr.Config.WithService(accountSvc).WithService(managerSvc).WithService(leaseSvc)
+			_, err := svcBldr.Build()
+			assert.Nil(t, err)
+			if err == nil {
+				services = svcBldr
+			}
+
+			err = handler(events.CloudWatchEvent{})
+			assert.True(t, errors.Is(err, tt.expErr), "actual error %q doesn't match expected error %q", err, tt.expErr)
+
+			if tt.expOrphan != "" {
+				accountSvc.AssertCalled(t, "Orphan", mock.AnythingOfType("*account.Account"), tt.expOrphan)
+			} else {
+				accountSvc.AssertNotCalled(t, "Orphan", mock.Anything, mock.Anything)
+			}
+			if tt.expLeaseOrphan {
+				leaseSvc.AssertCalled(t, "Orphan", "123456789012")
+			} else {
+				leaseSvc.AssertNotCalled(t, "Orphan", mock.Anything)
+			}
+			if tt.expSave {
+				accountSvc.AssertCalled(t, "Save", mock.MatchedBy(func(a *account.Account) bool {
+					return assert.Equal(t, tt.expFailures, a.HealthCheckFailures)
+				}))
+			} else {
+				accountSvc.AssertNotCalled(t, "Save", mock.Anything)
+			}
+			if tt.expRecover {
+				accountSvc.AssertCalled(t, "Recover", mock.AnythingOfType("*account.Account"))
+			} else {
+				accountSvc.AssertNotCalled(t, "Recover", mock.Anything)
+			}
+		})
+	}
+}
+
+func TestRetryCheck(t *testing.T) {
+	checkRetryDelay = 0
+
+	t.Run("should retry a check which fails to run", func(t *testing.T) {
+		attempts := 0
+		reason, err := retryCheck(func() error {
+			attempts++
+			if attempts == 1 {
+				return errors.NewInternalServer("throttled", fmt.Errorf("Rate exceeded"))
+			}
+			return errors.NewValidation("account", fmt.Errorf("access denied"))
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, "account validation error: access denied", reason)
+		assert.Equal(t, 2, attempts)
+	})
+
+	t.Run("should give up after its attempts", func(t *testing.T) {
+		attempts := 0
+		reason, err := retryCheck(func() error {
+			attempts++
+			return errors.NewInternalServer("throttled", fmt.Errorf("Rate exceeded"))
+		})
+		assert.NotNil(t, err)
+		assert.Equal(t, "", reason)
+		assert.Equal(t, checkAttempts, attempts)
+	})
+}
+
+func ptrString(s string) *string {
+	ptr := s
+	return &ptr
+}
+
+func ptrInt64(i int64) *int64 {
+	return &i
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/cloudwatch"
 	"github.com/aws/aws-sdk-go/service/cloudwatch/cloudwatchiface"
 	"log"
+	"sort"
 )
 
 var (
@@ -29,6 +30,7 @@ func initConfig() {
 	_, err := svcBuilder.
 		WithAccountService().
 		WithCloudWatchService().
+		WithProvisionerService().
 		Build()
 	if err != nil {
 		errorMessage := fmt.Sprintf("Failed to initialize account service: %s", err)
@@ -87,6 +89,115 @@ func publishMetrics(namespace string, countMetrics CountMetric) {
 	}
 }
 
+// poolStatuses are the account statuses reported for each account pool
+var poolStatuses = []account.Status{
+	account.StatusReady,
+	account.StatusNotReady,
+	account.StatusLeased,
+	account.StatusOrphaned,
+}
+
+type PoolMetric struct {
+	pool string
+	CountMetric
+}
+
+// getPoolMetrics counts the accounts in each account pool, by status.
+// Accounts without a pool are not included.
+func getPoolMetrics() []PoolMetric {
+	counts := map[string]map[account.Status]int{}
+	query := account.Account{
+		Limit: &QueryLimit,
+	}
+	err := Services.AccountService().ListPages(&query, func(accounts *account.Accounts) bool {
+		for _, acct := range *accounts {
+			if acct.Pool == nil || acct.Status == nil {
+				continue
+			}
+			if _, ok := counts[*acct.Pool]; !ok {
+				counts[*acct.Pool] = map[account.Status]int{}
+			}
+			counts[*acct.Pool][*acct.Status]++
+		}
+		return true
+	})
+	if err != nil {
+		log.Fatal("failed to query accounts by pool, ", err)
+	}
+
+	pools := []string{}
+	for pool := range counts {
+		pools = append(pools, pool)
+	}
+	sort.Strings(pools)
+
+	poolMetrics := []PoolMetric{}
+	for _, pool := range pools {
+		for _, status := range poolStatuses {
+			poolMetrics = append(poolMetrics, PoolMetric{
+				pool: pool,
+				CountMetric: CountMetric{
+					name:  status.String(),
+					count: counts[pool][status],
+				},
+			})
+		}
+	}
+
+	return poolMetrics
+}
+
+func publishPoolMetrics(namespace string, poolMetrics []PoolMetric) {
+	if len(poolMetrics) == 0 {
+		return
+	}
+	log.Println("Publishing pool metrics to cloudwatch")
+
+	var cloudWatchSvc cloudwatchiface.CloudWatchAPI
+	if err := Services.Config.GetService(&cloudWatchSvc); err != nil {
+		panic(err)
+	}
+
+	metricData := []*cloudwatch.MetricDatum{}
+	for _, m := range poolMetrics {
+		metricData = append(metricData, &cloudwatch.MetricDatum{
+			MetricName: aws.String(m.name + "Accounts"),
+			Dimensions: []*cloudwatch.Dimension{
+				{
+					Name:  aws.String("Pool"),
+					Value: aws.String(m.pool),
+				},
+			},
+			Unit:  aws.String("Count"),
+			Value: aws.Float64(float64(m.count)),
+		})
+	}
+
+	// PutMetricData accepts at most 20 metrics per request
+	for len(metricData) > 0 {
+		n := len(metricData)
+		if n > 20 {
+			n = 20
+		}
+		_, err := cloudWatchSvc.PutMetricData(&cloudwatch.PutMetricDataInput{
+			Namespace:  aws.String(namespace),
+			MetricData: metricData[:n],
+		})
+		if err != nil {
+			log.Fatalln(err)
+		}
+		metricData = metricData[n:]
+	}
+}
+
+// replenishAccountPool creates new accounts, if there are too few Ready accounts
+func replenishAccountPool(ready CountMetric) {
+	err := Services.ProvisionerService().Replenish(ready.count)
+	if err != nil {
+		log.Printf("Failed to replenish account pool: %s", err)
+	}
+}
+
 // Handler - Handle the lambda function
 func Handler(_ events.CloudWatchEvent) {
 	log.Printf("Initializing account pool metrics lambda")
@@ -112,6 +223,18 @@ func Handler(_ events.CloudWatchEvent) {
 	log.Println("Published LeasedAccounts Metric: ", float64(Leased.count))
 	log.Println("Published OrphanedAccounts Metric: ", float64(Orphaned.count))
 
+	poolMetrics := getPoolMetrics()
+	publishPoolMetrics("DCE/AccountPool", poolMetrics)
+
+	// New accounts are created without a pool,
+	// so only replenish based on the Ready accounts outside of any pool
+	for _, m := range poolMetrics {
+		if m.name == account.StatusReady.String() {
+			Ready.count -= m.count
+		}
+	}
+	replenishAccountPool(Ready)
+
 	log.Print("Account pool metrics lambda complete")
 }
 
@@ -5,6 +5,9 @@ import (
 	accountMocks "github.com/Optum/dce/pkg/account/accountiface/mocks"
 	awsMocks "github.com/Optum/dce/pkg/awsiface/mocks"
 	"github.com/Optum/dce/pkg/config"
+	"github.com/Optum/dce/pkg/errors"
+	provisionerMocks "github.com/Optum/dce/pkg/provisioner/provisioneriface/mocks"
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/cloudwatch"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -94,3 +97,120 @@ func TestPublishMetrics(t *testing.T) {
 		publishMetrics(namespace, countMetric1)
 	})
 }
+
+func TestGetPoolMetrics(t *testing.T) {
+	t.Run("count accounts by pool and status", func(t *testing.T) {
+		// arrange
+		accountSvc := accountMocks.Servicer{}
+		accountSvc.On("ListPages", mock.MatchedBy(func(query *account.Account) bool {
+			return query.Status == nil && query.Pool == nil
+		}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				fn := args.Get(1).(func(*account.Accounts) bool)
+				fn(&account.Accounts{
+					{Pool: aws.String("gov-region"), Status: account.StatusReady.StatusPtr()},
+					{Pool: aws.String("gov-region"), Status: account.StatusLeased.StatusPtr()},
+					{Pool: aws.String("gov-region"), Status: account.StatusReady.StatusPtr()},
+					{Pool: aws.String("network-enabled"), Status: account.StatusOrphaned.StatusPtr()},
+					{Status: account.StatusReady.StatusPtr()},
+				})
+			}).
+			Return(nil)
+		cfgBldr := &config.ConfigurationBuilder{}
+		svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+		svcBldr.Config.WithService(&accountSvc)
+		_, err := svcBldr.Build()
+		assert.Nil(t, err)
+		if err == nil {
+			Services = svcBldr
+		}
+
+		// act
+		poolMetrics := getPoolMetrics()
+
+		// assert
+		assert.Equal(t, []PoolMetric{
+			{pool: "gov-region", CountMetric: CountMetric{name: "Ready", count: 2}},
+			{pool: "gov-region", CountMetric: CountMetric{name: "NotReady", count: 0}},
+			{pool: "gov-region", CountMetric: CountMetric{name: "Leased", count: 1}},
+			{pool: "gov-region", CountMetric: CountMetric{name: "Orphaned", count: 0}},
+			{pool: "network-enabled", CountMetric: CountMetric{name: "Ready", count: 0}},
+			{pool: "network-enabled", CountMetric: CountMetric{name: "NotReady", count: 0}},
+			{pool: "network-enabled", CountMetric: CountMetric{name: "Leased", count: 0}},
+			{pool: "network-enabled", CountMetric: CountMetric{name: "Orphaned", count: 1}},
+		}, poolMetrics)
+	})
+}
+
+func TestPublishPoolMetrics(t *testing.T) {
+	t.Run("publish metrics with a pool dimension", func(t *testing.T) {
+		// arrange
+		cloudwatchSvc := awsMocks.CloudWatchAPI{}
+		cloudwatchSvc.On("PutMetricData", mock.MatchedBy(func(input *cloudwatch.PutMetricDataInput) bool {
+			// assert
+			datum := input.MetricData[0]
+			return *input.Namespace == "testNamespace" &&
+				len(input.MetricData) == 1 &&
+				*datum.MetricName == "ReadyAccounts" &&
+				*datum.Dimensions[0].Name == "Pool" &&
+				*datum.Dimensions[0].Value == "gov-region" &&
+				*datum.Value == float64(3)
+		})).Return(nil, nil)
+
+		cfgBldr := &config.ConfigurationBuilder{}
+		svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+		svcBldr.Config.WithService(&cloudwatchSvc)
+		_, err := svcBldr.Build()
+		assert.Nil(t, err)
+		if err == nil {
+			Services = svcBldr
+		}
+
+		// act
+		publishPoolMetrics("testNamespace", []PoolMetric{
+			{pool: "gov-region", CountMetric: CountMetric{name: "Ready", count: 3}},
+		})
+
+		// assert
+		cloudwatchSvc.AssertExpectations(t)
+	})
+}
+
+func TestReplenishAccountPool(t *testing.T) {
+	tests := []struct {
+		name         string
+		replenishErr error
+	}{
+		{
+			name: "replenish with the ready account count",
+		},
+		{
+			name:         "replenish failures don't stop the lambda",
+			replenishErr: errors.NewInternalServer("failure", nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			provisionerSvc := provisionerMocks.Servicer{}
+			provisionerSvc.On("Replenish", 2).Return(tt.replenishErr)
+			cfgBldr := &config.ConfigurationBuilder{}
+			svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+			svcBldr.Config.WithService(&provisionerSvc)
+			_, err := svcBldr.Build()
+			assert.Nil(t, err)
+			if err == nil {
+				Services = svcBldr
+			}
+
+			// act
+			replenishAccountPool(CountMetric{
+				name:  "Ready",
+				count: 2,
+			})
+
+			// assert
+			provisionerSvc.AssertExpectations(t)
+		})
+	}
+}
@@ -7,7 +7,8 @@ import (
 	"github.com/gorilla/mux"
 )
 
-// DeleteAccount - Deletes the account
+// DeleteAccount - Retires the account, removing it from the pool
+// while keeping its record for auditing
 func DeleteAccount(w http.ResponseWriter, r *http.Request) {
 
 	accountID := mux.Vars(r)["accountId"]
@@ -18,11 +19,11 @@ func DeleteAccount(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = Services.AccountService().Delete(acct)
+	retiredAcct, err := Services.AccountService().Retire(acct)
 	if err != nil {
 		api.WriteAPIErrorResponse(w, err)
 		return
 	}
 
-	api.WriteAPIResponse(w, http.StatusNoContent, nil)
+	api.WriteAPIResponse(w, http.StatusOK, retiredAcct)
 }
@@ -27,14 +27,15 @@ func TestWhenDelete(t *testing.T) {
 		request    events.APIGatewayProxyRequest
 		getAccount *account.Account
 		getErr     error
-		deleteErr  error
+		retireAcct *account.Account
+		retireErr  error
 	}{
 		{
-			name:      "When given good account ID. Then success is returned.",
+			name:      "When given good account ID. Then the retired account is returned.",
 			accountID: "123456789012",
 			expResp: events.APIGatewayProxyResponse{
-				StatusCode:        http.StatusNoContent,
-				Body:              "",
+				StatusCode:        http.StatusOK,
+				Body:              "{\"id\":\"123456789012\",\"accountStatus\":\"Retiring\"}\n",
 				MultiValueHeaders: standardHeaders,
 			},
 			request: events.APIGatewayProxyRequest{
@@ -45,6 +46,10 @@ func TestWhenDelete(t *testing.T) {
 				ID: ptrString("123456789012"),
 			},
 			getErr: nil,
+			retireAcct: &account.Account{
+				ID:     ptrString("123456789012"),
+				Status: account.StatusRetiring.StatusPtr(),
+			},
 		},
 		{
 			name:      "When given bad account ID. Then a not found error is returned.",
@@ -62,7 +67,7 @@ func TestWhenDelete(t *testing.T) {
 			getErr:     errors.NewNotFound("account", "210987654321"),
 		},
 		{
-			name:      "Given delete failure. Then an error is returned.",
+			name:      "Given retire failure. Then an error is returned.",
 			accountID: "123456789012",
 			request: events.APIGatewayProxyRequest{
 				HTTPMethod: http.MethodDelete,
@@ -77,7 +82,7 @@ func TestWhenDelete(t *testing.T) {
 				ID: ptrString("123456789012"),
 			},
 			getErr:    nil,
-			deleteErr: errors.NewInternalServer("failure", nil),
+			retireErr: errors.NewInternalServer("failure", nil),
 		},
 	}
 
@@ -90,8 +95,8 @@ func TestWhenDelete(t *testing.T) {
 			accountSvc.On("Get", tt.accountID).Return(
 				tt.getAccount, tt.getErr,
 			)
-			accountSvc.On("Delete", mock.AnythingOfType("*account.Account")).Return(
-				tt.deleteErr,
+			accountSvc.On("Retire", mock.AnythingOfType("*account.Account")).Return(
+				tt.retireAcct, tt.retireErr,
 			)
 			svcBldr.Config.WithService(&accountSvc)
 			_, err := svcBldr.Build()
@@ -66,6 +66,13 @@ func init() {
 			api.EmptyQueryString,
 			GetAccountByID,
 		},
+		api.Route{
+			"GetAccountResets",
+			"GET",
+			"/accounts/{accountId}/resets",
+			api.EmptyQueryString,
+			GetAccountResets,
+		},
 		api.Route{
 			"UpdateAccountByID",
 			"PUT",
@@ -110,6 +117,7 @@ func initConfig() {
 
 	_, err = svcBldr.
 		WithAccountService().
+		WithResetRunService().
 		Build()
 	if err != nil {
 		panic(err)
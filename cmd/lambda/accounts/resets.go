@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Optum/dce/pkg/api"
+	"github.com/Optum/dce/pkg/api/response"
+	"github.com/Optum/dce/pkg/resetrun"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/schema"
+)
+
+// GetAccountResets - Returns the reset runs of an account, most recent first
+func GetAccountResets(w http.ResponseWriter, r *http.Request) {
+
+	accountID := mux.Vars(r)["accountId"]
+
+	var decoder = schema.NewDecoder()
+
+	query := &resetrun.ResetRun{}
+	err := decoder.Decode(query, r.URL.Query())
+	if err != nil {
+		response.WriteRequestValidationError(w, fmt.Sprintf("Error parsing query params"))
+		return
+	}
+
+	// Make sure the account exists, so an unknown account isn't
+	// reported as an account which was never reset
+	_, err = Services.AccountService().Get(accountID)
+	if err != nil {
+		api.WriteAPIErrorResponse(w, err)
+		return
+	}
+
+	query.AccountID = &accountID
+	runs, err := Services.ResetRunService().List(query)
+	if err != nil {
+		api.WriteAPIErrorResponse(w, err)
+		return
+	}
+
+	if query.NextStartedOn != nil {
+		nextURL, err := api.BuildNextURL(baseRequest, query)
+		if err != nil {
+			api.WriteAPIErrorResponse(w, err)
+			return
+		}
+		w.Header().Add("Link", fmt.Sprintf("<%s>; rel=\"next\"", nextURL.String()))
+	}
+	api.WriteAPIResponse(w, http.StatusOK, runs)
+}
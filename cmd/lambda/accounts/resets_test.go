@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/Optum/dce/pkg/account"
+	"github.com/Optum/dce/pkg/account/accountiface/mocks"
+	"github.com/Optum/dce/pkg/config"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/resetrun"
+	resetRunMocks "github.com/Optum/dce/pkg/resetrun/resetruniface/mocks"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetAccountResets(t *testing.T) {
+
+	type response struct {
+		StatusCode int
+		Body       string
+	}
+	tests := []struct {
+		name          string
+		expResp       response
+		expLink       string
+		getAccountErr error
+		retRuns       *resetrun.ResetRuns
+		retErr        error
+		nextStartedOn *int64
+	}{
+		{
+			name: "get the resets of an account",
+			expResp: response{
+				StatusCode: 200,
+				Body:       "[{\"accountId\":\"123456789012\",\"startedOn\":1573592058,\"reason\":\"LeaseEnded\",\"status\":\"Failed\",\"error\":\"Failed to execute aws-nuke\"}]\n",
+			},
+			retRuns: &resetrun.ResetRuns{
+				{
+					AccountID: ptrString("123456789012"),
+					StartedOn: ptr64(1573592058),
+					Reason:    ptrString("LeaseEnded"),
+					Status:    resetrun.StatusFailed.StatusPtr(),
+					Error:     ptrString("Failed to execute aws-nuke"),
+				},
+			},
+		},
+		{
+			name: "get paged resets of an account",
+			expResp: response{
+				StatusCode: 200,
+				Body:       "[]\n",
+			},
+			retRuns:       &resetrun.ResetRuns{},
+			nextStartedOn: ptr64(1573592058),
+			expLink:       "<https://example.com/unit/accounts/123456789012/resets?limit=1&nextStartedOn=1573592058>; rel=\"next\"",
+		},
+		{
+			name: "fail when the account doesn't exist",
+			expResp: response{
+				StatusCode: 404,
+				Body:       "{\"error\":{\"message\":\"account \\\"123456789012\\\" not found\",\"code\":\"NotFoundError\"}}\n",
+			},
+			getAccountErr: errors.NewNotFound("account", "123456789012"),
+		},
+		{
+			name: "fail to get resets",
+			expResp: response{
+				StatusCode: 500,
+				Body:       "{\"error\":{\"message\":\"unknown error\",\"code\":\"ServerError\"}}\n",
+			},
+			retErr: fmt.Errorf("failure"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "http://example.com/accounts/123456789012/resets", nil)
+			r = mux.SetURLVars(r, map[string]string{
+				"accountId": "123456789012",
+			})
+
+			baseRequest = url.URL{}
+			baseRequest.Scheme = "https"
+			baseRequest.Host = "example.com"
+			baseRequest.Path = fmt.Sprintf("%s%s", "unit", "/accounts/123456789012/resets")
+
+			w := httptest.NewRecorder()
+
+			cfgBldr := &config.ConfigurationBuilder{}
+			svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+
+			accountSvc := mocks.Servicer{}
+			accountSvc.On("Get", "123456789012").Return(&account.Account{}, tt.getAccountErr)
+
+			resetRunSvc := resetRunMocks.Servicer{}
+			resetRunSvc.On("List", mock.MatchedBy(func(input *resetrun.ResetRun) bool {
+				if input.AccountID == nil || *input.AccountID != "123456789012" {
+					return false
+				}
+				if tt.nextStartedOn != nil {
+					input.NextStartedOn = tt.nextStartedOn
+					input.Limit = ptr64(1)
+				}
+				return true
+			})).Return(tt.retRuns, tt.retErr)
+
+			svcBldr.Config.WithService(&accountSvc).WithService(&resetRunSvc)
+			_, err := svcBldr.Build()
+
+			assert.Nil(t, err)
+			if err == nil {
+				Services = svcBldr
+			}
+
+			GetAccountResets(w, r)
+
+			resp := w.Result()
+			body, err := ioutil.ReadAll(resp.Body)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expResp.StatusCode, resp.StatusCode)
+			assert.Equal(t, tt.expResp.Body, string(body))
+			assert.Equal(t, tt.expLink, w.Header().Get("Link"))
+		})
+	}
+
+}
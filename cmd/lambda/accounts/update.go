@@ -30,6 +30,7 @@ func UpdateAccountByID(w http.ResponseWriter, r *http.Request) {
 		validation.Field(&newAccount.ID, validation.NilOrNotEmpty, validation.In(accountID)),
 		validation.Field(&newAccount.LastModifiedOn, validation.By(isNil)),
 		validation.Field(&newAccount.Status, validation.By(isNil)),
+		validation.Field(&newAccount.StatusReason, validation.By(isNil)),
 		validation.Field(&newAccount.CreatedOn, validation.By(isNil)),
 		validation.Field(&newAccount.PrincipalRoleArn, validation.By(isNil)),
 		validation.Field(&newAccount.PrincipalPolicyHash, validation.By(isNil)),
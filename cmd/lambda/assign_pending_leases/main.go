@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/Optum/dce/pkg/config"
+	"github.com/aws/aws-lambda-go/lambda"
+)
+
+type configuration struct {
+	Debug string `env:"DEBUG" envDefault:"false"`
+}
+
+var (
+	services *config.ServiceBuilder
+	// Settings - the configuration settings for the controller
+	settings *configuration
+)
+
+func init() {
+	cfgBldr := &config.ConfigurationBuilder{}
+	settings = &configuration{}
+	if err := cfgBldr.Unmarshal(settings); err != nil {
+		log.Fatalf("Could not load configuration: %s", err.Error())
+	}
+
+	// load up the values into the various settings...
+	err := cfgBldr.WithEnv("AWS_CURRENT_REGION", "AWS_CURRENT_REGION", "us-east-1").Build()
+	if err != nil {
+		log.Printf("Error: %+v", err)
+	}
+	svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+
+	_, err = svcBldr.
+		WithLeaseService().
+		Build()
+	if err != nil {
+		panic(err)
+	}
+
+	services = svcBldr
+}
+
+func main() {
+	lambda.Start(handler)
+}
+
+// handler is invoked when an account finishes resetting, an account is created,
+// or a lease ends, and on a schedule. It hands out Ready accounts to Pending
+// leases, oldest first. The event itself is ignored.
+func handler(ctx context.Context, event json.RawMessage) error {
+	for {
+		assigned, err := services.LeaseService().AssignPending()
+		if err != nil {
+			return err
+		}
+		// Either the waitlist is empty, or there are no more Ready accounts
+		if assigned == nil {
+			return nil
+		}
+		log.Printf("Assigned account %s to pending lease %s for principal %s",
+			*assigned.AccountID, *assigned.ID, *assigned.PrincipalID)
+	}
+}
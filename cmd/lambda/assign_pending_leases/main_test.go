@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/Optum/dce/pkg/config"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/lease"
+	"github.com/Optum/dce/pkg/lease/leaseiface/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssignPendingLeases(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		assigned []*lease.Lease
+		err      error
+		expCalls int
+		expErr   error
+	}{
+		{
+			name:     "when there are no pending leases nothing is assigned",
+			expCalls: 1,
+		},
+		{
+			name: "when there are pending leases they are assigned until none are left",
+			assigned: []*lease.Lease{
+				{
+					ID:          ptrString("lease-1"),
+					AccountID:   ptrString("123456789012"),
+					PrincipalID: ptrString("user1"),
+				},
+				{
+					ID:          ptrString("lease-2"),
+					AccountID:   ptrString("123456789013"),
+					PrincipalID: ptrString("user2"),
+				},
+			},
+			expCalls: 3,
+		},
+		{
+			name:     "when assigning fails return error",
+			err:      errors.NewInternalServer("failure", fmt.Errorf("error")),
+			expCalls: 1,
+			expErr:   errors.NewInternalServer("failure", fmt.Errorf("error")),
+		},
+	}
+
+	// Iterate through each test in the list
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfgBldr := &config.ConfigurationBuilder{}
+			svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+			// Setup mocks
+
+			leaseServiceMock := mocks.Servicer{}
+			for _, l := range tt.assigned {
+				leaseServiceMock.On("AssignPending").Return(l, nil).Once()
+			}
+			leaseServiceMock.On("AssignPending").Return(nil, tt.err).Once()
+
+			svcBldr.Config.WithService(&leaseServiceMock)
+			_, err := svcBldr.Build()
+			assert.Nil(t, err)
+			if err == nil {
+				services = svcBldr
+			}
+
+			err = handler(context.TODO(), json.RawMessage(`{}`))
+			assert.True(t, errors.Is(err, tt.expErr))
+			leaseServiceMock.AssertNumberOfCalls(t, "AssignPending", tt.expCalls)
+		})
+	}
+}
+
+func ptrString(s string) *string {
+	ptr := s
+	return &ptr
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Optum/dce/pkg/api"
+	"github.com/Optum/dce/pkg/budgetpolicy"
+	"github.com/Optum/dce/pkg/errors"
+)
+
+// CreateBudgetPolicy - Creates a budget policy for a principal or Cognito group
+func CreateBudgetPolicy(w http.ResponseWriter, r *http.Request) {
+	// Deserialize the request JSON as an request object
+	newPolicy := &budgetpolicy.BudgetPolicy{}
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(newPolicy)
+	if err != nil {
+		api.WriteAPIErrorResponse(w,
+			errors.NewBadRequest("invalid request parameters"))
+		return
+	}
+
+	policy, err := Services.BudgetPolicyService().Create(newPolicy)
+	if err != nil {
+		api.WriteAPIErrorResponse(w, err)
+		return
+	}
+
+	api.WriteAPIResponse(w, http.StatusCreated, policy)
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Optum/dce/pkg/budgetpolicy"
+	"github.com/Optum/dce/pkg/budgetpolicy/budgetpolicyiface/mocks"
+	"github.com/Optum/dce/pkg/config"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestWhenCreate(t *testing.T) {
+	standardHeaders := map[string][]string{
+		"Access-Control-Allow-Origin": []string{"*"},
+		"Content-Type":                []string{"application/json"},
+	}
+
+	tests := []struct {
+		name      string
+		expResp   events.APIGatewayProxyResponse
+		request   events.APIGatewayProxyRequest
+		retPolicy *budgetpolicy.BudgetPolicy
+		retErr    error
+	}{
+		{
+			name: "When given good values. Then success is returned.",
+			expResp: events.APIGatewayProxyResponse{
+				StatusCode:        http.StatusCreated,
+				Body:              "{\"id\":\"f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0\",\"group\":\"Interns\"}\n",
+				MultiValueHeaders: standardHeaders,
+			},
+			request: events.APIGatewayProxyRequest{
+				HTTPMethod: http.MethodPost,
+				Path:       "/budget-policies",
+				Body:       "{ \"group\": \"Interns\", \"maxLeaseBudgetAmount\": 50 }",
+			},
+			retPolicy: &budgetpolicy.BudgetPolicy{
+				ID:    ptrString("f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0"),
+				Group: ptrString("Interns"),
+			},
+		},
+		{
+			name: "When given bad values. Then a syntax error is returned.",
+			expResp: events.APIGatewayProxyResponse{
+				StatusCode:        http.StatusBadRequest,
+				Body:              "{\"error\":{\"message\":\"invalid request parameters\",\"code\":\"ClientError\"}}\n",
+				MultiValueHeaders: standardHeaders,
+			},
+			request: events.APIGatewayProxyRequest{
+				HTTPMethod: http.MethodPost,
+				Path:       "/budget-policies",
+				Body:       "{ \"group: \"Interns\" }",
+			},
+		},
+		{
+			name: "When the group already has a policy. Then a conflict is returned.",
+			expResp: events.APIGatewayProxyResponse{
+				StatusCode:        http.StatusConflict,
+				Body:              "{\"error\":{\"message\":\"budget policy \\\"Interns\\\" already exists\",\"code\":\"AlreadyExistsError\"}}\n",
+				MultiValueHeaders: standardHeaders,
+			},
+			request: events.APIGatewayProxyRequest{
+				HTTPMethod: http.MethodPost,
+				Path:       "/budget-policies",
+				Body:       "{ \"group\": \"Interns\" }",
+			},
+			retErr: errors.NewAlreadyExists("budget policy", "Interns"),
+		},
+		{
+			name: "Given internal failure. Then an internal server error is returned.",
+			expResp: events.APIGatewayProxyResponse{
+				StatusCode:        http.StatusInternalServerError,
+				Body:              "{\"error\":{\"message\":\"unknown error\",\"code\":\"ServerError\"}}\n",
+				MultiValueHeaders: standardHeaders,
+			},
+			request: events.APIGatewayProxyRequest{
+				HTTPMethod: http.MethodPost,
+				Path:       "/budget-policies",
+				Body:       "{ \"group\": \"Interns\" }",
+			},
+			retErr: fmt.Errorf("failure"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfgBldr := &config.ConfigurationBuilder{}
+			svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+
+			policySvc := mocks.Servicer{}
+			policySvc.On("Create", mock.AnythingOfType("*budgetpolicy.BudgetPolicy")).Return(
+				tt.retPolicy, tt.retErr,
+			)
+			svcBldr.Config.WithService(&policySvc)
+			_, err := svcBldr.Build()
+
+			assert.Nil(t, err)
+			if err == nil {
+				Services = svcBldr
+			}
+
+			resp, err := Handler(context.TODO(), tt.request)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expResp, resp)
+		})
+	}
+
+}
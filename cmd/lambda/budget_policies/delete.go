@@ -0,0 +1,23 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/Optum/dce/pkg/api"
+	"github.com/gorilla/mux"
+)
+
+// DeleteBudgetPolicy - Deletes the budget policy,
+// so the default budget limits apply again
+func DeleteBudgetPolicy(w http.ResponseWriter, r *http.Request) {
+
+	policyID := mux.Vars(r)["policyId"]
+
+	policy, err := Services.BudgetPolicyService().Delete(policyID)
+	if err != nil {
+		api.WriteAPIErrorResponse(w, err)
+		return
+	}
+
+	api.WriteAPIResponse(w, http.StatusOK, policy)
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Optum/dce/pkg/budgetpolicy"
+	"github.com/Optum/dce/pkg/budgetpolicy/budgetpolicyiface/mocks"
+	"github.com/Optum/dce/pkg/config"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWhenDelete(t *testing.T) {
+	standardHeaders := map[string][]string{
+		"Access-Control-Allow-Origin": []string{"*"},
+		"Content-Type":                []string{"application/json"},
+	}
+
+	tests := []struct {
+		name      string
+		policyID  string
+		expResp   events.APIGatewayProxyResponse
+		request   events.APIGatewayProxyRequest
+		retPolicy *budgetpolicy.BudgetPolicy
+		retErr    error
+	}{
+		{
+			name:     "When given good policy ID. Then the deleted policy is returned.",
+			policyID: "f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0",
+			expResp: events.APIGatewayProxyResponse{
+				StatusCode:        http.StatusOK,
+				Body:              "{\"id\":\"f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0\",\"principalId\":\"jdoe\"}\n",
+				MultiValueHeaders: standardHeaders,
+			},
+			request: events.APIGatewayProxyRequest{
+				HTTPMethod: http.MethodDelete,
+				Path:       "/budget-policies/f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0",
+			},
+			retPolicy: &budgetpolicy.BudgetPolicy{
+				ID:          ptrString("f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0"),
+				PrincipalID: ptrString("jdoe"),
+			},
+		},
+		{
+			name:     "When given bad policy ID. Then a not found error is returned.",
+			policyID: "0c5e7a4f-3c7b-4c6d-9d4c-6a2b1a9e5f10",
+			expResp: events.APIGatewayProxyResponse{
+				StatusCode:        http.StatusNotFound,
+				Body:              "{\"error\":{\"message\":\"budget policy \\\"0c5e7a4f-3c7b-4c6d-9d4c-6a2b1a9e5f10\\\" not found\",\"code\":\"NotFoundError\"}}\n",
+				MultiValueHeaders: standardHeaders,
+			},
+			request: events.APIGatewayProxyRequest{
+				HTTPMethod: http.MethodDelete,
+				Path:       "/budget-policies/0c5e7a4f-3c7b-4c6d-9d4c-6a2b1a9e5f10",
+			},
+			retErr: errors.NewNotFound("budget policy", "0c5e7a4f-3c7b-4c6d-9d4c-6a2b1a9e5f10"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfgBldr := &config.ConfigurationBuilder{}
+			svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+
+			policySvc := mocks.Servicer{}
+			policySvc.On("Delete", tt.policyID).Return(
+				tt.retPolicy, tt.retErr,
+			)
+			svcBldr.Config.WithService(&policySvc)
+			_, err := svcBldr.Build()
+
+			assert.Nil(t, err)
+			if err == nil {
+				Services = svcBldr
+			}
+
+			resp, err := Handler(context.TODO(), tt.request)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expResp, resp)
+		})
+	}
+
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Optum/dce/pkg/api"
+)
+
+// GetBudgetPolicyByID - Returns the single budget policy by ID
+func GetBudgetPolicyByID(w http.ResponseWriter, r *http.Request) {
+
+	policyID := mux.Vars(r)["policyId"]
+
+	policy, err := Services.BudgetPolicyService().Get(policyID)
+
+	if err != nil {
+		api.WriteAPIErrorResponse(w, err)
+		return
+	}
+
+	api.WriteAPIResponse(w, http.StatusOK, policy)
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Optum/dce/pkg/budgetpolicy"
+	"github.com/Optum/dce/pkg/budgetpolicy/budgetpolicyiface/mocks"
+	"github.com/Optum/dce/pkg/config"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetBudgetPolicyByID(t *testing.T) {
+
+	type response struct {
+		StatusCode int
+		Body       string
+	}
+	tests := []struct {
+		name      string
+		expResp   response
+		policyID  string
+		retPolicy *budgetpolicy.BudgetPolicy
+		retErr    error
+	}{
+		{
+			name:     "success",
+			policyID: "f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0",
+			expResp: response{
+				StatusCode: 200,
+				Body:       "{\"id\":\"f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0\"}\n",
+			},
+			retPolicy: &budgetpolicy.BudgetPolicy{
+				ID: ptrString("f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0"),
+			},
+		},
+		{
+			name:     "not found",
+			policyID: "f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0",
+			expResp: response{
+				StatusCode: 404,
+				Body:       "{\"error\":{\"message\":\"budget policy \\\"f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0\\\" not found\",\"code\":\"NotFoundError\"}}\n",
+			},
+			retErr: errors.NewNotFound("budget policy", "f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0"),
+		},
+		{
+			name:     "failure",
+			policyID: "f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0",
+			expResp: response{
+				StatusCode: 500,
+				Body:       "{\"error\":{\"message\":\"unknown error\",\"code\":\"ServerError\"}}\n",
+			},
+			retErr: fmt.Errorf("failure"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", fmt.Sprintf("http://example.com/budget-policies/%s", tt.policyID), nil)
+
+			r = mux.SetURLVars(r, map[string]string{
+				"policyId": tt.policyID,
+			})
+			w := httptest.NewRecorder()
+
+			cfgBldr := &config.ConfigurationBuilder{}
+			svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+
+			policySvc := mocks.Servicer{}
+			policySvc.On("Get", tt.policyID).Return(
+				tt.retPolicy, tt.retErr,
+			)
+			svcBldr.Config.WithService(&policySvc)
+			_, err := svcBldr.Build()
+
+			assert.Nil(t, err)
+			if err == nil {
+				Services = svcBldr
+			}
+
+			GetBudgetPolicyByID(w, r)
+
+			resp := w.Result()
+			body, err := ioutil.ReadAll(resp.Body)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expResp.StatusCode, resp.StatusCode)
+			assert.Equal(t, tt.expResp.Body, string(body))
+		})
+	}
+
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Optum/dce/pkg/api"
+	"github.com/Optum/dce/pkg/api/response"
+	"github.com/Optum/dce/pkg/budgetpolicy"
+	"github.com/gorilla/schema"
+)
+
+// GetBudgetPolicies - Returns budget policies
+func GetBudgetPolicies(w http.ResponseWriter, r *http.Request) {
+
+	var decoder = schema.NewDecoder()
+
+	query := &budgetpolicy.BudgetPolicy{}
+	err := decoder.Decode(query, r.URL.Query())
+	if err != nil {
+		response.WriteRequestValidationError(w, fmt.Sprintf("Error parsing query params"))
+		return
+	}
+
+	policies, err := Services.BudgetPolicyService().List(query)
+	if err != nil {
+		api.WriteAPIErrorResponse(w, err)
+		return
+	}
+
+	if query.NextTarget != nil {
+		nextURL, err := api.BuildNextURL(baseRequest, query)
+		if err != nil {
+			api.WriteAPIErrorResponse(w, err)
+			return
+		}
+		w.Header().Add("Link", fmt.Sprintf("<%s>; rel=\"next\"", nextURL.String()))
+	}
+	api.WriteAPIResponse(w, http.StatusOK, policies)
+
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/Optum/dce/pkg/budgetpolicy"
+	"github.com/Optum/dce/pkg/budgetpolicy/budgetpolicyiface/mocks"
+	"github.com/Optum/dce/pkg/config"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/gorilla/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetBudgetPolicies(t *testing.T) {
+
+	type response struct {
+		StatusCode int
+		Body       string
+	}
+	tests := []struct {
+		name        string
+		expResp     response
+		expLink     string
+		query       *budgetpolicy.BudgetPolicy
+		retPolicies *budgetpolicy.BudgetPolicies
+		retErr      error
+		nextTarget  *string
+	}{
+		{
+			name:  "get all budget policies",
+			query: &budgetpolicy.BudgetPolicy{},
+			expResp: response{
+				StatusCode: 200,
+				Body:       "[]\n",
+			},
+			retPolicies: &budgetpolicy.BudgetPolicies{},
+		},
+		{
+			name: "get paged budget policies for a group",
+			query: &budgetpolicy.BudgetPolicy{
+				Group: ptrString("Interns"),
+			},
+			expResp: response{
+				StatusCode: 200,
+				Body:       "[{\"id\":\"f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0\",\"group\":\"Interns\"}]\n",
+			},
+			retPolicies: &budgetpolicy.BudgetPolicies{
+				budgetpolicy.BudgetPolicy{
+					ID:    ptrString("f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0"),
+					Group: ptrString("Interns"),
+				},
+			},
+			nextTarget: ptrString("group:SRE"),
+			expLink:    "<https://example.com/unit/budget-policies?group=Interns&limit=1&nextTarget=group%3ASRE>; rel=\"next\"",
+		},
+		{
+			name:  "fail to get budget policies",
+			query: &budgetpolicy.BudgetPolicy{},
+			expResp: response{
+				StatusCode: 500,
+				Body:       "{\"error\":{\"message\":\"unknown error\",\"code\":\"ServerError\"}}\n",
+			},
+			retErr: fmt.Errorf("failure"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "http://example.com/budget-policies", nil)
+
+			baseRequest = url.URL{}
+			baseRequest.Scheme = "https"
+			baseRequest.Host = "example.com"
+			baseRequest.Path = fmt.Sprintf("%s%s", "unit", "/budget-policies")
+
+			values := url.Values{}
+			err := schema.NewEncoder().Encode(tt.query, values)
+			assert.Nil(t, err)
+
+			r.URL.RawQuery = values.Encode()
+			w := httptest.NewRecorder()
+
+			cfgBldr := &config.ConfigurationBuilder{}
+			svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+
+			policySvc := mocks.Servicer{}
+			policySvc.On("List", mock.MatchedBy(func(input *budgetpolicy.BudgetPolicy) bool {
+				if (input.Group != nil && tt.query.Group != nil && *input.Group == *tt.query.Group) || input.Group == tt.query.Group {
+					if tt.nextTarget != nil {
+						input.NextTarget = tt.nextTarget
+						input.Limit = aws.Int64(1)
+					}
+					return true
+				}
+				return false
+			})).Return(
+				tt.retPolicies, tt.retErr,
+			)
+			svcBldr.Config.WithService(&policySvc)
+			_, err = svcBldr.Build()
+
+			assert.Nil(t, err)
+			if err == nil {
+				Services = svcBldr
+			}
+
+			GetBudgetPolicies(w, r)
+
+			resp := w.Result()
+			body, err := ioutil.ReadAll(resp.Body)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expResp.StatusCode, resp.StatusCode)
+			assert.Equal(t, tt.expResp.Body, string(body))
+			assert.Equal(t, tt.expLink, w.Header().Get("Link"))
+		})
+	}
+
+}
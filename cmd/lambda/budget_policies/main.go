@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+
+	"github.com/Optum/dce/pkg/api"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+
+	"github.com/Optum/dce/pkg/config"
+	"github.com/awslabs/aws-lambda-go-api-proxy/gorillamux"
+)
+
+type budgetPolicyControllerConfiguration struct {
+	Debug string `env:"DEBUG" envDefault:"false"`
+}
+
+var (
+	muxLambda *gorillamux.GorillaMuxAdapter
+	// Services handles the configuration of the AWS services
+	Services *config.ServiceBuilder
+	// Settings - the configuration settings for the controller
+	Settings *budgetPolicyControllerConfiguration
+)
+
+var (
+	baseRequest url.URL
+)
+
+func init() {
+	initConfig()
+
+	log.Println("Cold start; creating router for /budget-policies")
+	budgetPolicyRoutes := api.Routes{
+		// Routes with query strings always go first,
+		// because the matcher will stop on the first match
+		api.Route{
+			"GetBudgetPolicies",
+			"GET",
+			"/budget-policies",
+			api.EmptyQueryString,
+			GetBudgetPolicies,
+		},
+		api.Route{
+			"GetBudgetPolicyByID",
+			"GET",
+			"/budget-policies/{policyId}",
+			api.EmptyQueryString,
+			GetBudgetPolicyByID,
+		},
+		api.Route{
+			"UpdateBudgetPolicyByID",
+			"PUT",
+			"/budget-policies/{policyId}",
+			api.EmptyQueryString,
+			UpdateBudgetPolicyByID,
+		},
+		api.Route{
+			"DeleteBudgetPolicy",
+			"DELETE",
+			"/budget-policies/{policyId}",
+			api.EmptyQueryString,
+			DeleteBudgetPolicy,
+		},
+		api.Route{
+			"CreateBudgetPolicy",
+			"POST",
+			"/budget-policies",
+			api.EmptyQueryString,
+			CreateBudgetPolicy,
+		},
+	}
+	r := api.NewRouter(budgetPolicyRoutes)
+	muxLambda = gorillamux.New(r)
+}
+
+// initConfig configures package-level variables
+// loaded from env vars.
+func initConfig() {
+	cfgBldr := &config.ConfigurationBuilder{}
+	Settings = &budgetPolicyControllerConfiguration{}
+	if err := cfgBldr.Unmarshal(Settings); err != nil {
+		log.Fatalf("Could not load configuration: %s", err.Error())
+	}
+
+	// load up the values into the various settings...
+	err := cfgBldr.WithEnv("AWS_CURRENT_REGION", "AWS_CURRENT_REGION", "us-east-1").Build()
+	if err != nil {
+		log.Printf("Error: %+v", err)
+	}
+	svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+
+	_, err = svcBldr.
+		WithBudgetPolicyService().
+		Build()
+	if err != nil {
+		panic(err)
+	}
+
+	Services = svcBldr
+
+}
+
+// Handler - Handle the lambda function
+func Handler(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+
+	// Set baseRequest information lost by integration with gorilla mux
+	baseRequest = url.URL{}
+	baseRequest.Scheme = req.Headers["X-Forwarded-Proto"]
+	baseRequest.Host = req.Headers["Host"]
+	baseRequest.Path = fmt.Sprintf("%s%s", req.RequestContext.Stage, req.Path)
+
+	return muxLambda.ProxyWithContext(ctx, req)
+}
+
+func main() {
+	// Send Lambda requests to the router
+	lambda.Start(Handler)
+}
@@ -0,0 +1,16 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+func ptrString(s string) *string {
+	ptrS := s
+	return &ptrS
+}
+
+func TestMain(m *testing.M) {
+	os.Setenv("BUDGET_POLICY_DB", "BudgetPolicies")
+	os.Exit(m.Run())
+}
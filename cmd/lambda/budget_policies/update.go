@@ -0,0 +1,34 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Optum/dce/pkg/api"
+	"github.com/Optum/dce/pkg/budgetpolicy"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/gorilla/mux"
+)
+
+// UpdateBudgetPolicyByID updates the limits of a budget policy
+func UpdateBudgetPolicyByID(w http.ResponseWriter, r *http.Request) {
+	policyID := mux.Vars(r)["policyId"]
+
+	// Deserialize the request JSON as an request object
+	newPolicy := &budgetpolicy.BudgetPolicy{}
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(newPolicy)
+	if err != nil {
+		api.WriteAPIErrorResponse(w,
+			errors.NewBadRequest("invalid request parameters"))
+		return
+	}
+
+	policy, err := Services.BudgetPolicyService().Update(policyID, newPolicy)
+	if err != nil {
+		api.WriteAPIErrorResponse(w, err)
+		return
+	}
+
+	api.WriteAPIResponse(w, http.StatusOK, policy)
+}
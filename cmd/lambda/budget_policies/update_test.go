@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Optum/dce/pkg/budgetpolicy"
+	"github.com/Optum/dce/pkg/budgetpolicy/budgetpolicyiface/mocks"
+	"github.com/Optum/dce/pkg/config"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestWhenUpdate(t *testing.T) {
+	standardHeaders := map[string][]string{
+		"Access-Control-Allow-Origin": []string{"*"},
+		"Content-Type":                []string{"application/json"},
+	}
+
+	tests := []struct {
+		name      string
+		expResp   events.APIGatewayProxyResponse
+		request   events.APIGatewayProxyRequest
+		retPolicy *budgetpolicy.BudgetPolicy
+		retErr    error
+	}{
+		{
+			name: "When given good values. Then the updated policy is returned.",
+			expResp: events.APIGatewayProxyResponse{
+				StatusCode:        http.StatusOK,
+				Body:              "{\"id\":\"f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0\",\"group\":\"Interns\",\"maxLeaseBudgetAmount\":100}\n",
+				MultiValueHeaders: standardHeaders,
+			},
+			request: events.APIGatewayProxyRequest{
+				HTTPMethod: http.MethodPut,
+				Path:       "/budget-policies/f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0",
+				Body:       "{ \"maxLeaseBudgetAmount\": 100 }",
+			},
+			retPolicy: &budgetpolicy.BudgetPolicy{
+				ID:                   ptrString("f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0"),
+				Group:                ptrString("Interns"),
+				MaxLeaseBudgetAmount: aws.Float64(100),
+			},
+		},
+		{
+			name: "When given bad values. Then a syntax error is returned.",
+			expResp: events.APIGatewayProxyResponse{
+				StatusCode:        http.StatusBadRequest,
+				Body:              "{\"error\":{\"message\":\"invalid request parameters\",\"code\":\"ClientError\"}}\n",
+				MultiValueHeaders: standardHeaders,
+			},
+			request: events.APIGatewayProxyRequest{
+				HTTPMethod: http.MethodPut,
+				Path:       "/budget-policies/f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0",
+				Body:       "{ \"maxLeaseBudgetAmount: 100 }",
+			},
+		},
+		{
+			name: "When changing the group. Then a validation error is returned.",
+			expResp: events.APIGatewayProxyResponse{
+				StatusCode:        http.StatusBadRequest,
+				Body:              "{\"error\":{\"message\":\"budget policy validation error: group: must be empty.\",\"code\":\"RequestValidationError\"}}\n",
+				MultiValueHeaders: standardHeaders,
+			},
+			request: events.APIGatewayProxyRequest{
+				HTTPMethod: http.MethodPut,
+				Path:       "/budget-policies/f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0",
+				Body:       "{ \"group\": \"SRE\" }",
+			},
+			retErr: errors.NewValidation("budget policy", fmt.Errorf("group: must be empty.")), //nolint golint
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfgBldr := &config.ConfigurationBuilder{}
+			svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+
+			policySvc := mocks.Servicer{}
+			policySvc.On("Update", "f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0", mock.AnythingOfType("*budgetpolicy.BudgetPolicy")).Return(
+				tt.retPolicy, tt.retErr,
+			)
+			svcBldr.Config.WithService(&policySvc)
+			_, err := svcBldr.Build()
+
+			assert.Nil(t, err)
+			if err == nil {
+				Services = svcBldr
+			}
+
+			resp, err := Handler(context.TODO(), tt.request)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expResp, resp)
+		})
+	}
+
+}
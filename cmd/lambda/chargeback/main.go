@@ -0,0 +1,250 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Optum/dce/pkg/chargeback"
+	"github.com/Optum/dce/pkg/config"
+	"github.com/Optum/dce/pkg/email"
+	"github.com/Optum/dce/pkg/lease"
+	"github.com/Optum/dce/pkg/usage"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+type configuration struct {
+	Debug           string   `env:"DEBUG" envDefault:"false"`
+	ArtifactsBucket string   `env:"ARTIFACTS_BUCKET" envDefault:"DefaultArtifactBucket"`
+	ReportPrefix    string   `env:"CHARGEBACK_REPORT_PREFIX" envDefault:"chargeback"`
+	CostCenterKey   string   `env:"CHARGEBACK_COST_CENTER_KEY" envDefault:"costCenter"`
+	FromEmail       string   `env:"CHARGEBACK_FROM_EMAIL" envDefault:""`
+	ToEmails        []string `env:"CHARGEBACK_TO_EMAILS" envDefault:""`
+}
+
+var (
+	services *config.ServiceBuilder
+	// Settings - the configuration settings for the controller
+	settings *configuration
+	// tmpDir is where report attachments are written before they are emailed
+	tmpDir = "/tmp"
+)
+
+func init() {
+	cfgBldr := &config.ConfigurationBuilder{}
+	settings = &configuration{}
+	if err := cfgBldr.Unmarshal(settings); err != nil {
+		log.Fatalf("Could not load configuration: %s", err.Error())
+	}
+
+	// load up the values into the various settings...
+	err := cfgBldr.WithEnv("AWS_CURRENT_REGION", "AWS_CURRENT_REGION", "us-east-1").Build()
+	if err != nil {
+		log.Printf("Error: %+v", err)
+	}
+	svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+
+	_, err = svcBldr.
+		WithLeaseService().
+		WithUsageService().
+		WithS3().
+		WithEmailService().
+		Build()
+	if err != nil {
+		panic(err)
+	}
+
+	services = svcBldr
+}
+
+func main() {
+	lambda.Start(handler)
+}
+
+// handler builds the chargeback report of the previous month, and writes it
+// to the artifacts bucket as CSV and Parquet. The CSV report is emailed
+// to the configured recipients.
+func handler(cloudWatchEvent events.CloudWatchEvent) error {
+	now := cloudWatchEvent.Time
+	if now.IsZero() {
+		now = time.Now()
+	}
+	now = now.UTC()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -1, 0)
+
+	report, err := buildReport(month)
+	if err != nil {
+		return err
+	}
+	log.Printf("Built chargeback report %s with %d leases", report.Name(), len(report.Rows))
+
+	csvReport := &bytes.Buffer{}
+	err = report.WriteCSV(csvReport)
+	if err != nil {
+		return err
+	}
+	err = putReport(report, "csv", csvReport.Bytes())
+	if err != nil {
+		return err
+	}
+
+	parquetReport := &bytes.Buffer{}
+	err = report.WriteParquet(parquetReport)
+	if err != nil {
+		return err
+	}
+	err = putReport(report, "parquet", parquetReport.Bytes())
+	if err != nil {
+		return err
+	}
+
+	if len(settings.ToEmails) == 0 {
+		return nil
+	}
+	return emailReport(report, csvReport.Bytes())
+}
+
+// buildReport builds the chargeback report of the month from
+// every lease and the usage recorded during the month
+func buildReport(month time.Time) (*chargeback.Report, error) {
+	usages, err := services.UsageService().ListByDateRange(month, month.AddDate(0, 1, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	leases, err := listUsageLeases(*usages)
+	if err != nil {
+		return nil, err
+	}
+
+	return chargeback.NewReport(&chargeback.NewReportInput{
+		Date:          month,
+		Leases:        leases,
+		Usages:        *usages,
+		CostCenterKey: settings.CostCenterKey,
+	}), nil
+}
+
+// listUsageLeases returns the leases which usage was recorded for.
+// Usage is recorded daily for each Active lease, so these are the leases
+// active during the month. Leases are keyed by account and principal,
+// so each pair is looked up once, rather than scanning every lease.
+func listUsageLeases(usages usage.Usages) (lease.Leases, error) {
+	leases := lease.Leases{}
+	seen := map[string]bool{}
+	for _, usg := range usages {
+		if usg.PrincipalID == nil {
+			continue
+		}
+		for _, accountID := range usageAccountIDs(usg) {
+			key := *usg.PrincipalID + "/" + accountID
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			query := &lease.Lease{
+				PrincipalID: usg.PrincipalID,
+				AccountID:   aws.String(accountID),
+			}
+			err := services.LeaseService().ListPages(query, func(page *lease.Leases) bool {
+				leases = append(leases, *page...)
+				return true
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return leases, nil
+}
+
+// usageAccountIDs returns the accounts spent in by the principal of the usage.
+// A principal has a single usage record per day, whatever the number of
+// accounts it leases, so each account is a key of AccountCosts.
+// Records written before spend was tracked by account only have AccountID.
+func usageAccountIDs(usg usage.Usage) []string {
+	if len(usg.AccountCosts) == 0 {
+		if usg.AccountID == nil {
+			return nil
+		}
+		return []string{*usg.AccountID}
+	}
+
+	accountIDs := make([]string, 0, len(usg.AccountCosts))
+	for accountID := range usg.AccountCosts {
+		accountIDs = append(accountIDs, accountID)
+	}
+	sort.Strings(accountIDs)
+	return accountIDs
+}
+
+// reportKey returns the S3 key of the report, eg. "chargeback/2019-10/chargeback-2019-10.csv"
+func reportKey(report *chargeback.Report, extension string) string {
+	return fmt.Sprintf("%s/%s/%s.%s",
+		strings.TrimSuffix(settings.ReportPrefix, "/"),
+		report.StartDate.Format("2006-01"),
+		report.Name(),
+		extension,
+	)
+}
+
+// putReport writes the report file to the artifacts bucket
+func putReport(report *chargeback.Report, extension string, body []byte) error {
+	var s3Svc s3iface.S3API
+	if err := services.Config.GetService(&s3Svc); err != nil {
+		return err
+	}
+
+	key := reportKey(report, extension)
+	_, err := s3Svc.PutObject(&s3.PutObjectInput{
+		Bucket:               aws.String(settings.ArtifactsBucket),
+		Key:                  aws.String(key),
+		Body:                 bytes.NewReader(body),
+		ServerSideEncryption: aws.String("AES256"),
+	})
+	if err != nil {
+		return err
+	}
+	log.Printf("Wrote chargeback report to s3://%s/%s", settings.ArtifactsBucket, key)
+	return nil
+}
+
+// emailReport sends the CSV report as an attachment
+func emailReport(report *chargeback.Report, csvReport []byte) error {
+	// Attachments are read from disk
+	attachment := filepath.Join(tmpDir, report.Name()+".csv")
+	err := ioutil.WriteFile(attachment, csvReport, 0600)
+	if err != nil {
+		return err
+	}
+
+	month := report.StartDate.Format("January 2006")
+	currency := "USD"
+	if len(report.Rows) > 0 {
+		currency = report.Rows[0].CostCurrency
+	}
+	err = services.EmailService().SendRawEmailWithAttachment(&email.SendEmailWithAttachmentInput{
+		FromAddress: settings.FromEmail,
+		ToAddresses: settings.ToEmails,
+		Subject:     fmt.Sprintf("DCE chargeback report for %s", month),
+		BodyHTML: fmt.Sprintf("<p>The DCE chargeback report for %s is attached.</p>"+
+			"<p>%d leases spent a total of %.2f %s.</p>",
+			month, len(report.Rows), report.TotalCostAmount(), currency),
+		AttachmentFileName: attachment,
+	})
+	if err != nil {
+		return err
+	}
+	log.Printf("Emailed chargeback report %s to %s", report.Name(), strings.Join(settings.ToEmails, ", "))
+	return nil
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	awsMocks "github.com/Optum/dce/pkg/awsiface/mocks"
+	"github.com/Optum/dce/pkg/config"
+	"github.com/Optum/dce/pkg/email"
+	emailMocks "github.com/Optum/dce/pkg/email/mocks"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/lease"
+	leaseMocks "github.com/Optum/dce/pkg/lease/leaseiface/mocks"
+	"github.com/Optum/dce/pkg/usage"
+	usageMocks "github.com/Optum/dce/pkg/usage/usageiface/mocks"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLambdaHandler(t *testing.T) {
+	oct1 := time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC)
+	nov1 := time.Date(2019, 11, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		toEmails  []string
+		leasesErr error
+		usagesErr error
+		expEmail  bool
+		expErr    error
+	}{
+		{
+			name: "when the report is built. It is written to S3",
+		},
+		{
+			name:     "when recipients are configured. The report is emailed",
+			toEmails: []string{"finance@example.com"},
+			expEmail: true,
+		},
+		{
+			name:      "when leases can't be listed. No report is written",
+			leasesErr: errors.NewInternalServer("failure", nil),
+			expErr:    errors.NewInternalServer("failure", nil),
+		},
+		{
+			name:      "when usage can't be listed. No report is written",
+			usagesErr: errors.NewInternalServer("failure", nil),
+			expErr:    errors.NewInternalServer("failure", nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "chargeback")
+			assert.Nil(t, err)
+			defer os.RemoveAll(dir)
+			tmpDir = dir
+
+			settings = &configuration{
+				ArtifactsBucket: "artifacts",
+				ReportPrefix:    "chargeback",
+				CostCenterKey:   "costCenter",
+				FromEmail:       "dce@example.com",
+				ToEmails:        tt.toEmails,
+			}
+
+			cfgBldr := &config.ConfigurationBuilder{}
+			svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+
+			leaseSvc := &leaseMocks.Servicer{}
+			leaseSvc.On("ListPages", mock.MatchedBy(func(query *lease.Lease) bool {
+				return query.PrincipalID != nil && *query.PrincipalID == "user1" &&
+					query.AccountID != nil && *query.AccountID == "123456789012"
+			}), mock.Anything).
+				Run(func(args mock.Arguments) {
+					fn := args.Get(1).(func(*lease.Leases) bool)
+					fn(&lease.Leases{
+						{
+							ID:          aws.String("lease1"),
+							PrincipalID: aws.String("user1"),
+							AccountID:   aws.String("123456789012"),
+							Status:      lease.StatusActive.StatusPtr(),
+							CreatedOn:   aws.Int64(oct1.Unix()),
+							Metadata: map[string]interface{}{
+								"costCenter": "cc-1",
+							},
+						},
+					})
+				}).Return(tt.leasesErr)
+
+			usageSvc := &usageMocks.Servicer{}
+			usageSvc.On("ListByDateRange", oct1, nov1).Return(&usage.Usages{
+				{
+					PrincipalID:  aws.String("user1"),
+					AccountID:    aws.String("123456789012"),
+					StartDate:    aws.Int64(oct1.Unix()),
+					CostAmount:   aws.Float64(12.5),
+					CostCurrency: aws.String("USD"),
+				},
+				{
+					PrincipalID:  aws.String("user1"),
+					AccountID:    aws.String("123456789012"),
+					StartDate:    aws.Int64(oct1.AddDate(0, 0, 1).Unix()),
+					CostAmount:   aws.Float64(2.5),
+					CostCurrency: aws.String("USD"),
+				},
+			}, tt.usagesErr)
+
+			s3Svc := &awsMocks.S3API{}
+			s3Svc.On("PutObject", mock.AnythingOfType("*s3.PutObjectInput")).Return(&s3.PutObjectOutput{}, nil)
+
+			emailSvc := &emailMocks.Service{}
+			emailSvc.On("SendRawEmailWithAttachment", mock.AnythingOfType("*email.SendEmailWithAttachmentInput")).
+				Run(func(args mock.Arguments) {
+					input := args.Get(0).(*email.SendEmailWithAttachmentInput)
+					attachment, err := ioutil.ReadFile(input.AttachmentFileName)
+					assert.Nil(t, err)
+					assert.Contains(t, string(attachment), "lease1,user1,123456789012,cc-1,2019-10-01,,15.00,USD")
+				}).Return(nil)
+
+			svcBldr.Config.WithService(leaseSvc).WithService(usageSvc).WithService(s3Svc).WithService(emailSvc)
+			_, err = svcBldr.Build()
+			assert.Nil(t, err)
+			if err == nil {
+				services = svcBldr
+			}
+
+			err = handler(events.CloudWatchEvent{
+				Time: time.Date(2019, 11, 1, 6, 0, 0, 0, time.UTC),
+			})
+			assert.True(t, errors.Is(err, tt.expErr), "actual error %q doesn't match expected error %q", err, tt.expErr)
+
+			if tt.usagesErr != nil {
+				leaseSvc.AssertNotCalled(t, "ListPages", mock.Anything, mock.Anything)
+			}
+			if tt.expErr != nil {
+				s3Svc.AssertNotCalled(t, "PutObject", mock.Anything)
+				emailSvc.AssertNotCalled(t, "SendRawEmailWithAttachment", mock.Anything)
+				return
+			}
+
+			// Each lease is looked up once, however many days of usage it has
+			leaseSvc.AssertNumberOfCalls(t, "ListPages", 1)
+			s3Svc.AssertCalled(t, "PutObject", mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+				return *input.Bucket == "artifacts" && *input.Key == "chargeback/2019-10/chargeback-2019-10.csv"
+			}))
+			s3Svc.AssertCalled(t, "PutObject", mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+				return *input.Bucket == "artifacts" && *input.Key == "chargeback/2019-10/chargeback-2019-10.parquet"
+			}))
+			if tt.expEmail {
+				emailSvc.AssertCalled(t, "SendRawEmailWithAttachment", mock.MatchedBy(func(input *email.SendEmailWithAttachmentInput) bool {
+					return input.Subject == "DCE chargeback report for October 2019" &&
+						input.FromAddress == "dce@example.com" &&
+						input.ToAddresses[0] == "finance@example.com"
+				}))
+			} else {
+				emailSvc.AssertNotCalled(t, "SendRawEmailWithAttachment", mock.Anything)
+			}
+		})
+	}
+}
+
+func TestBuildReportWithSeveralAccounts(t *testing.T) {
+	oct1 := time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC)
+	nov1 := time.Date(2019, 11, 1, 0, 0, 0, 0, time.UTC)
+	settings = &configuration{CostCenterKey: "costCenter"}
+
+	cfgBldr := &config.ConfigurationBuilder{}
+	svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+
+	// The principal leases two accounts, so a single usage record
+	// has the spend of both
+	leaseSvc := &leaseMocks.Servicer{}
+	for _, l := range []struct {
+		id        string
+		accountID string
+	}{
+		{id: "lease1", accountID: "111111111111"},
+		{id: "lease2", accountID: "222222222222"},
+	} {
+		accountLease := lease.Lease{
+			ID:          aws.String(l.id),
+			PrincipalID: aws.String("user1"),
+			AccountID:   aws.String(l.accountID),
+			Status:      lease.StatusActive.StatusPtr(),
+			CreatedOn:   aws.Int64(oct1.Unix()),
+		}
+		leaseSvc.On("ListPages", mock.MatchedBy(func(query *lease.Lease) bool {
+			return query.PrincipalID != nil && *query.PrincipalID == "user1" &&
+				query.AccountID != nil && *query.AccountID == *accountLease.AccountID
+		}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				fn := args.Get(1).(func(*lease.Leases) bool)
+				fn(&lease.Leases{accountLease})
+			}).Return(nil)
+	}
+
+	usageSvc := &usageMocks.Servicer{}
+	usageSvc.On("ListByDateRange", oct1, nov1).Return(&usage.Usages{
+		{
+			PrincipalID:  aws.String("user1"),
+			AccountID:    aws.String("222222222222"),
+			StartDate:    aws.Int64(oct1.Unix()),
+			CostAmount:   aws.Float64(15),
+			CostCurrency: aws.String("USD"),
+			AccountCosts: map[string]float64{
+				"111111111111": 10,
+				"222222222222": 5,
+			},
+		},
+	}, nil)
+
+	svcBldr.Config.WithService(leaseSvc).WithService(usageSvc)
+	_, err := svcBldr.Build()
+	assert.Nil(t, err)
+	if err == nil {
+		services = svcBldr
+	}
+
+	report, err := buildReport(oct1)
+	assert.Nil(t, err)
+
+	// Each account of the principal is looked up
+	leaseSvc.AssertNumberOfCalls(t, "ListPages", 2)
+	costs := map[string]float64{}
+	for _, row := range report.Rows {
+		costs[row.LeaseID] = row.CostAmount
+	}
+	assert.Equal(t, map[string]float64{"lease1": 10, "lease2": 5}, costs)
+}
@@ -53,18 +53,30 @@ func init() {
 
 func handler(cloudWatchEvent events.CloudWatchEvent) error {
 
+	var errs []error
+
+	// Start any Scheduled leases which are due
+	activated, err := services.LeaseService().ActivateScheduled()
+	if err != nil {
+		log.Printf("Failed to activate scheduled leases: %s", err)
+		errs = append(errs, err)
+	}
+	if activated != nil {
+		for _, ls := range *activated {
+			log.Printf("Activated scheduled lease %s for %s @ %s", *ls.ID, *ls.PrincipalID, *ls.AccountID)
+		}
+	}
+
 	query := &lease.Lease{
 		Status: lease.StatusActive.StatusPtr(),
 	}
 
 	var lambdaSvc lambdaiface.LambdaAPI
-	err := services.Config.GetService(&lambdaSvc)
+	err = services.Config.GetService(&lambdaSvc)
 	if err != nil {
 		return err
 	}
 
-	var errs []error
-
 	err = services.LeaseService().ListPages(query,
 		func(leases *lease.Leases) bool {
 			for _, ls := range *leases {
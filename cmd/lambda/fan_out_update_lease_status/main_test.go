@@ -30,6 +30,8 @@ func TestLambdaHandler(t *testing.T) {
 		name         string
 		retLeases    *lease.Leases
 		retLeasesErr error
+		retScheduled *lease.Leases
+		retSchedErr  error
 		retLambda    []lambdaInvoke
 		expErr       error
 	}{
@@ -71,6 +73,30 @@ func TestLambdaHandler(t *testing.T) {
 			retLambda:    []lambdaInvoke{},
 			expErr:       errors.NewInternalServer("failure", fmt.Errorf("error")),
 		},
+		{
+			name: "when listing scheduled leases fails the failure is returned and execution continues.",
+			retLeases: &lease.Leases{
+				{
+					ID:          ptrString("abc-123"),
+					AccountID:   ptrString("123456789012"),
+					PrincipalID: ptrString("TestUser1"),
+				},
+			},
+			retSchedErr: fmt.Errorf("error"),
+			retLambda: []lambdaInvoke{
+				{
+					input: &lambdaSDK.InvokeInput{
+						FunctionName:   aws.String("UpdateLeaseStatusFunction"),
+						InvocationType: aws.String("Event"),
+						Payload:        []byte("{\"accountId\":\"123456789012\",\"principalId\":\"TestUser1\",\"id\":\"abc-123\"}"),
+					},
+				},
+			},
+			expErr: errors.NewMultiError("error when processing accounts",
+				[]error{
+					fmt.Errorf("error"),
+				}),
+		},
 		{
 			name: "when given good leases. Lambda execution failure is returned and execution continues.",
 			retLeases: &lease.Leases{
@@ -130,6 +156,13 @@ func TestLambdaHandler(t *testing.T) {
 			dataSvc.On("List", &lease.Lease{
 				Status: lease.StatusActive.StatusPtr(),
 			}).Return(tt.retLeases, tt.retLeasesErr)
+			retScheduled := tt.retScheduled
+			if retScheduled == nil {
+				retScheduled = &lease.Leases{}
+			}
+			dataSvc.On("List", &lease.Lease{
+				Status: lease.StatusScheduled.StatusPtr(),
+			}).Return(retScheduled, tt.retSchedErr)
 			lambdaSvc := awsMocks.LambdaAPI{}
 			for _, m := range tt.retLambda {
 				lambdaSvc.On("Invoke", m.input).Return(nil, m.err)
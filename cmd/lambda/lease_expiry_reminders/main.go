@@ -0,0 +1,257 @@
+package main
+
+import (
+	"bytes"
+	htmlTemplate "html/template"
+	"log"
+	"math"
+	"net/url"
+	"strings"
+	textTemplate "text/template"
+	"time"
+
+	"github.com/Optum/dce/pkg/config"
+	"github.com/Optum/dce/pkg/email"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/lease"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+)
+
+type configuration struct {
+	Debug           string          `env:"DEBUG" envDefault:"false"`
+	Reminders       []time.Duration `env:"LEASE_EXPIRY_REMINDERS" envDefault:"24h,1h"`
+	FromEmail       string          `env:"LEASE_EXPIRY_REMINDER_FROM_EMAIL" envDefault:"dce@example.com"`
+	TemplateSubject string          `env:"LEASE_EXPIRY_REMINDER_TEMPLATE_SUBJECT" envDefault:"Lease expires in {{.HoursRemaining}} hour{{if ne .HoursRemaining 1}}s{{end}} [{{.Lease.AccountID}}]"`
+	TemplateText    string          `env:"LEASE_EXPIRY_REMINDER_TEMPLATE_TEXT" envDefault:"Lease for principal {{.Lease.PrincipalID}} in AWS Account {{.Lease.AccountID}} expires on {{.ExpiresOn}}."`
+	TemplateHTML    string          `env:"LEASE_EXPIRY_REMINDER_TEMPLATE_HTML" envDefault:"<p>Lease for principal {{.Lease.PrincipalID}} in AWS Account {{.Lease.AccountID}} expires on {{.ExpiresOn}}.</p>"`
+	APIBaseURL      string          `env:"API_BASE_URL" envDefault:""`
+	// ExtensionSecret is loaded from the SSM parameter named by LEASE_EXTENSION_SECRET_PARAMETER
+	ExtensionSecret string
+	ExtensionPeriod time.Duration `env:"LEASE_EXTENSION_PERIOD" envDefault:"24h"`
+}
+
+var (
+	services *config.ServiceBuilder
+	// Settings - the configuration settings for the controller
+	settings *configuration
+)
+
+func init() {
+	cfgBldr := &config.ConfigurationBuilder{}
+	settings = &configuration{}
+	if err := cfgBldr.Unmarshal(settings); err != nil {
+		log.Fatalf("Could not load configuration: %s", err.Error())
+	}
+
+	// load up the values into the various settings...
+	err := cfgBldr.WithEnv("AWS_CURRENT_REGION", "AWS_CURRENT_REGION", "us-east-1").Build()
+	if err != nil {
+		log.Printf("Error: %+v", err)
+	}
+	cfgBldr.WithParameterStoreEnv("LEASE_EXTENSION_SECRET", "LEASE_EXTENSION_SECRET_PARAMETER", "")
+	svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+
+	_, err = svcBldr.
+		WithSSM().
+		WithLeaseService().
+		WithEmailService().
+		Build()
+	if err != nil {
+		panic(err)
+	}
+
+	settings.ExtensionSecret, err = cfgBldr.GetStringVal("LEASE_EXTENSION_SECRET")
+	if err != nil {
+		log.Fatalf("Could not load the lease extension secret: %s", err)
+	}
+
+	services = svcBldr
+}
+
+func main() {
+	lambda.Start(handler)
+}
+
+// handler sends a reminder to the principal of each Active lease which
+// is about to expire, and publishes an ExpiringSoon event.
+// Each reminder is sent once, so reminders are sent again after a lease is extended.
+func handler(cloudWatchEvent events.CloudWatchEvent) error {
+	now := cloudWatchEvent.Time
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	// Remind every lease, even if reminding another lease fails
+	errs := []error{}
+	err := services.LeaseService().ListPages(&lease.Lease{
+		Status: lease.StatusActive.StatusPtr(),
+	}, func(page *lease.Leases) bool {
+		for _, l := range *page {
+			l := l
+			reminderOn := dueReminder(&l, settings.Reminders, now)
+			if reminderOn == nil {
+				continue
+			}
+			err := remind(&l, *reminderOn, now)
+			if err != nil {
+				log.Printf("Failed to send expiry reminder for lease %s: %s", *l.ID, err)
+				errs = append(errs, err)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(errs) > 0 {
+		return errors.NewMultiError("Failed to send lease expiry reminders", errs)
+	}
+	return nil
+}
+
+// dueReminder returns the time the latest expiry reminder was due for the lease,
+// or nil if no reminder is due, or it has already been sent
+func dueReminder(l *lease.Lease, reminders []time.Duration, now time.Time) *time.Time {
+	if l.ID == nil || l.ExpiresOn == nil {
+		return nil
+	}
+	expiresOn := time.Unix(*l.ExpiresOn, 0)
+	if !now.Before(expiresOn) {
+		return nil
+	}
+
+	var latest *time.Time
+	for _, reminder := range reminders {
+		reminderOn := expiresOn.Add(-reminder)
+		if reminderOn.After(now) {
+			continue
+		}
+		if latest == nil || reminderOn.After(*latest) {
+			latest = &reminderOn
+		}
+	}
+
+	if latest == nil || (l.ExpiryReminderOn != nil && *l.ExpiryReminderOn >= latest.Unix()) {
+		return nil
+	}
+	return latest
+}
+
+// remind emails the lease's notification recipients, and records the reminder.
+// The reminder is only recorded once the email is sent, so it is retried on failure.
+func remind(l *lease.Lease, reminderOn time.Time, now time.Time) error {
+	if l.BudgetNotificationEmails != nil && len(*l.BudgetNotificationEmails) > 0 {
+		msg, err := renderReminder(l, now)
+		if err != nil {
+			return err
+		}
+
+		log.Printf("Sending expiry reminder for lease %s to %s",
+			*l.ID, strings.Join(*l.BudgetNotificationEmails, ","))
+		err = services.EmailService().SendEmail(&email.SendEmailInput{
+			FromAddress: settings.FromEmail,
+			ToAddresses: *l.BudgetNotificationEmails,
+			Subject:     msg.subject,
+			BodyHTML:    msg.bodyHTML,
+			BodyText:    msg.bodyText,
+		})
+		if err != nil {
+			return err
+		}
+	} else {
+		log.Printf("No notification emails were provided for lease %s, skipping expiry reminder email", *l.ID)
+	}
+
+	_, err := services.LeaseService().ExpiringSoon(*l.ID, reminderOn.Unix())
+	return err
+}
+
+type reminder struct {
+	subject  string
+	bodyHTML string
+	bodyText string
+}
+
+// renderReminder renders the reminder email templates
+func renderReminder(l *lease.Lease, now time.Time) (*reminder, error) {
+	expiresOn := time.Unix(*l.ExpiresOn, 0).UTC()
+
+	extendURL, err := extensionURL(l)
+	if err != nil {
+		return nil, err
+	}
+
+	templateData := struct {
+		Lease          lease.Lease
+		ExpiresOn      string
+		HoursRemaining int
+		ExtendURL      string
+		ExtensionHours int
+	}{
+		Lease:          *l,
+		ExpiresOn:      expiresOn.Format(time.RFC1123),
+		HoursRemaining: int(math.Ceil(expiresOn.Sub(now).Hours())),
+		ExtendURL:      extendURL,
+		ExtensionHours: int(settings.ExtensionPeriod.Hours()),
+	}
+
+	msg := &reminder{}
+	msg.subject, err = renderTextTemplate("subject", settings.TemplateSubject, templateData)
+	if err != nil {
+		return nil, err
+	}
+	msg.bodyText, err = renderTextTemplate("text", settings.TemplateText, templateData)
+	if err != nil {
+		return nil, err
+	}
+	msg.bodyHTML, err = renderHTMLTemplate("html", settings.TemplateHTML, templateData)
+	if err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// renderTextTemplate renders a plain text template
+func renderTextTemplate(id string, templateStr string, data interface{}) (string, error) {
+	tmpl, err := textTemplate.New(id).Parse(templateStr)
+	if err != nil {
+		return "", err
+	}
+
+	buf := &bytes.Buffer{}
+	err = tmpl.Execute(buf, data)
+
+	return strings.TrimSpace(buf.String()), err
+}
+
+// renderHTMLTemplate renders an HTML template, escaping the data
+func renderHTMLTemplate(id string, templateStr string, data interface{}) (string, error) {
+	tmpl, err := htmlTemplate.New(id).Parse(templateStr)
+	if err != nil {
+		return "", err
+	}
+
+	buf := &bytes.Buffer{}
+	err = tmpl.Execute(buf, data)
+
+	return strings.TrimSpace(buf.String()), err
+}
+
+// extensionURL returns the link which extends the lease, eg.
+// https://abc123.execute-api.us-east-1.amazonaws.com/api/leases/{id}/extend?token=...
+// Returns an empty string if one-click extensions aren't configured.
+func extensionURL(l *lease.Lease) (string, error) {
+	if settings.APIBaseURL == "" || settings.ExtensionSecret == "" {
+		return "", nil
+	}
+
+	// The link is no use once the lease has expired
+	token, err := lease.ExtensionToken(settings.ExtensionSecret, l, time.Unix(*l.ExpiresOn, 0))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(settings.APIBaseURL, "/") + "/leases/" + url.PathEscape(*l.ID) +
+		"/extend?token=" + url.QueryEscape(token), nil
+}
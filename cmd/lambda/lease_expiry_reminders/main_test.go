@@ -0,0 +1,175 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Optum/dce/pkg/config"
+	"github.com/Optum/dce/pkg/email"
+	emailMocks "github.com/Optum/dce/pkg/email/mocks"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/lease"
+	leaseMocks "github.com/Optum/dce/pkg/lease/leaseiface/mocks"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLambdaHandler(t *testing.T) {
+	now := time.Date(2019, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		expiresOn     time.Time
+		reminderOn    *time.Time
+		emailErr      error
+		expReminderOn *time.Time
+		expSubject    string
+		expErr        error
+	}{
+		{
+			name:          "when a lease expires within the hour. The 1h reminder is sent",
+			expiresOn:     now.Add(30 * time.Minute),
+			expReminderOn: aws.Time(now.Add(-30 * time.Minute)),
+			expSubject:    "Lease expires in 1 hours [123456789012]",
+		},
+		{
+			name:          "when the 24h reminder was sent. The 1h reminder is sent",
+			expiresOn:     now.Add(30 * time.Minute),
+			reminderOn:    aws.Time(now.Add(-23*time.Hour - 30*time.Minute)),
+			expReminderOn: aws.Time(now.Add(-30 * time.Minute)),
+			expSubject:    "Lease expires in 1 hours [123456789012]",
+		},
+		{
+			name:       "when the 1h reminder was sent. No reminder is sent",
+			expiresOn:  now.Add(30 * time.Minute),
+			reminderOn: aws.Time(now.Add(-30 * time.Minute)),
+		},
+		{
+			name:          "when a lease expires within a day. The 24h reminder is sent",
+			expiresOn:     now.Add(12 * time.Hour),
+			expReminderOn: aws.Time(now.Add(-12 * time.Hour)),
+			expSubject:    "Lease expires in 12 hours [123456789012]",
+		},
+		{
+			name:       "when the lease was extended. The 24h reminder is sent again",
+			expiresOn:  now.Add(12 * time.Hour),
+			reminderOn: aws.Time(now.Add(-13 * time.Hour)),
+			// The previous reminder was for an earlier expiresOn date
+			expReminderOn: aws.Time(now.Add(-12 * time.Hour)),
+			expSubject:    "Lease expires in 12 hours [123456789012]",
+		},
+		{
+			name:      "when a lease expires in more than a day. No reminder is sent",
+			expiresOn: now.Add(72 * time.Hour),
+		},
+		{
+			name:       "when the email fails. The reminder isn't recorded",
+			expiresOn:  now.Add(30 * time.Minute),
+			emailErr:   errors.NewInternalServer("failure", nil),
+			expSubject: "Lease expires in 1 hours [123456789012]",
+			expErr: errors.NewMultiError("Failed to send lease expiry reminders", []error{
+				errors.NewInternalServer("failure", nil),
+			}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings = &configuration{
+				Reminders:       []time.Duration{24 * time.Hour, time.Hour},
+				FromEmail:       "dce@example.com",
+				TemplateSubject: "Lease expires in {{.HoursRemaining}} hours [{{.Lease.AccountID}}]",
+				TemplateText:    "Extend the lease by {{.ExtensionHours}} hours: {{.ExtendURL}}",
+				TemplateHTML:    "<a href=\"{{.ExtendURL}}\">Extend</a>",
+				APIBaseURL:      "https://example.com/api/",
+				ExtensionSecret: "secret",
+				ExtensionPeriod: 24 * time.Hour,
+			}
+
+			l := lease.Lease{
+				ID:                       aws.String("lease1"),
+				PrincipalID:              aws.String("user1"),
+				AccountID:                aws.String("123456789012"),
+				Status:                   lease.StatusActive.StatusPtr(),
+				ExpiresOn:                aws.Int64(tt.expiresOn.Unix()),
+				BudgetNotificationEmails: &[]string{"user1@example.com"},
+			}
+			if tt.reminderOn != nil {
+				l.ExpiryReminderOn = aws.Int64(tt.reminderOn.Unix())
+			}
+			token, err := lease.ExtensionToken("secret", &l, tt.expiresOn)
+			assert.Nil(t, err)
+
+			cfgBldr := &config.ConfigurationBuilder{}
+			svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+
+			leaseSvc := &leaseMocks.Servicer{}
+			leaseSvc.On("ListPages", mock.MatchedBy(func(query *lease.Lease) bool {
+				return *query.Status == lease.StatusActive
+			}), mock.Anything).
+				Run(func(args mock.Arguments) {
+					fn := args.Get(1).(func(*lease.Leases) bool)
+					fn(&lease.Leases{l})
+				}).Return(nil)
+			leaseSvc.On("ExpiringSoon", "lease1", mock.AnythingOfType("int64")).Return(&l, nil)
+
+			emailSvc := &emailMocks.Service{}
+			emailSvc.On("SendEmail", mock.AnythingOfType("*email.SendEmailInput")).Return(tt.emailErr)
+
+			svcBldr.Config.WithService(leaseSvc).WithService(emailSvc)
+			_, err = svcBldr.Build()
+			assert.Nil(t, err)
+			if err == nil {
+				services = svcBldr
+			}
+
+			err = handler(events.CloudWatchEvent{
+				Time: now,
+			})
+			assert.True(t, errors.Is(err, tt.expErr), "actual error %q doesn't match expected error %q", err, tt.expErr)
+
+			if tt.expSubject != "" {
+				emailSvc.AssertCalled(t, "SendEmail", &email.SendEmailInput{
+					FromAddress: "dce@example.com",
+					ToAddresses: []string{"user1@example.com"},
+					Subject:     tt.expSubject,
+					BodyText:    "Extend the lease by 24 hours: https://example.com/api/leases/lease1/extend?token=" + token,
+					BodyHTML:    "<a href=\"https://example.com/api/leases/lease1/extend?token=" + token + "\">Extend</a>",
+				})
+			} else {
+				emailSvc.AssertNotCalled(t, "SendEmail", mock.Anything)
+			}
+
+			if tt.expReminderOn != nil {
+				leaseSvc.AssertCalled(t, "ExpiringSoon", "lease1", tt.expReminderOn.Unix())
+			} else {
+				leaseSvc.AssertNotCalled(t, "ExpiringSoon", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestExtensionURL(t *testing.T) {
+	settings = &configuration{}
+	l := &lease.Lease{
+		ID:        aws.String("lease1"),
+		ExpiresOn: aws.Int64(1570000000),
+	}
+
+	// One-click extensions aren't configured
+	extendURL, err := extensionURL(l)
+	assert.Nil(t, err)
+	assert.Equal(t, "", extendURL)
+
+	settings = &configuration{
+		APIBaseURL:      "https://example.com/api",
+		ExtensionSecret: "secret",
+	}
+	extendURL, err = extensionURL(l)
+	assert.Nil(t, err)
+	// The link expires with the lease
+	assert.True(t, strings.HasPrefix(extendURL, "https://example.com/api/leases/lease1/extend?token=1570000000."))
+}
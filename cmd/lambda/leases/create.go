@@ -1,19 +1,15 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
 	"github.com/Optum/dce/pkg/api"
 	"log"
 	"net/http"
+	"strings"
 	"time"
 
-	"github.com/google/uuid"
-	"github.com/pkg/errors"
-
 	"github.com/Optum/dce/pkg/api/response"
-	"github.com/Optum/dce/pkg/common"
-	"github.com/Optum/dce/pkg/db"
+	"github.com/Optum/dce/pkg/lease"
 )
 
 type createLeaseRequest struct {
@@ -21,18 +17,23 @@ type createLeaseRequest struct {
 	BudgetAmount             float64                `json:"budgetAmount"`
 	BudgetCurrency           string                 `json:"budgetCurrency"`
 	BudgetNotificationEmails []string               `json:"budgetNotificationEmails"`
+	NotificationTargets      []string               `json:"notificationTargets"`
 	ExpiresOn                int64                  `json:"expiresOn"`
+	StartsOn                 int64                  `json:"startsOn"`
+	Pool                     string                 `json:"pool"`
 	Metadata                 map[string]interface{} `json:"metadata"`
 }
 
 // CreateLease - Creates the lease
 func CreateLease(w http.ResponseWriter, r *http.Request) {
 	c := leaseValidationContext{
-		maxLeaseBudgetAmount:     maxLeaseBudgetAmount,
-		maxLeasePeriod:           maxLeasePeriod,
-		defaultLeaseLengthInDays: defaultLeaseLengthInDays,
-		principalBudgetPeriod:    principalBudgetPeriod,
-		principalBudgetAmount:    principalBudgetAmount,
+		maxLeaseBudgetAmount:           maxLeaseBudgetAmount,
+		maxLeasePeriod:                 maxLeasePeriod,
+		defaultLeaseLengthInDays:       defaultLeaseLengthInDays,
+		principalBudgetPeriod:          principalBudgetPeriod,
+		principalBudgetAmount:          principalBudgetAmount,
+		exchangeRates:                  exchangeRates,
+		notificationTargetAllowedHosts: Settings.NotificationTargetAllowedHosts,
 	}
 
 	// Extract the Body from the Request
@@ -60,145 +61,80 @@ func CreateLease(w http.ResponseWriter, r *http.Request) {
 
 	log.Printf("Creating lease for Principal %s", principalID)
 
-	// Fail if the Principal already has an active lease
-	principalLeases, err := dao.FindLeasesByPrincipal(requestBody.PrincipalID)
-	if err != nil {
-		log.Printf("Failed to list leases for principal %s: %s", requestBody.PrincipalID, err)
-		response.WriteServerErrorWithResponse(w, "Internal server error")
-		return
-	}
-
-	for _, lease := range principalLeases {
-		if lease.LeaseStatus == db.Active {
-			msg := fmt.Sprintf("Principal already has an active lease for account %s", lease.AccountID)
-			response.WriteConflictError(w, msg)
+	// Fail if the Principal already has the maximum number of leases.
+	// Pending and Scheduled leases count towards the maximum, as they
+	// become Active without passing through this check again.
+	heldLeases := []string{}
+	for _, status := range []lease.Status{lease.StatusActive, lease.StatusPending, lease.StatusScheduled} {
+		err = Services.LeaseService().ListPages(&lease.Lease{
+			PrincipalID: &principalID,
+			Status:      status.StatusPtr(),
+		}, func(leases *lease.Leases) bool {
+			for _, l := range *leases {
+				if status == lease.StatusActive {
+					heldLeases = append(heldLeases, "account "+*l.AccountID)
+					continue
+				}
+				heldLeases = append(heldLeases, fmt.Sprintf("%s lease %s", status, *l.ID))
+			}
+			return true
+		})
+		if err != nil {
+			api.WriteAPIErrorResponse(w, err)
 			return
 		}
 	}
-
-	// Get the First Ready Account
-	// Exit if there's an error or no ready accounts
-	account, err := dao.GetReadyAccount()
-	if err != nil {
-		log.Printf("Failed to Check Ready Accounts: %s", err)
-		response.WriteServerErrorWithResponse(
-			w,
-			fmt.Sprintf("Failed to find a Ready Account: %s", err),
-		)
-		return
-	} else if account == nil {
-		errStr := "No Available accounts at this moment"
-		log.Println(errStr)
-		response.WriteServiceUnavailableError(w, errStr)
+	if len(heldLeases) >= maxActiveLeasesPerPrincipal {
+		msg := fmt.Sprintf("Principal already has the maximum of %d active, pending or scheduled lease(s): %s",
+			maxActiveLeasesPerPrincipal, strings.Join(heldLeases, ", "))
+		response.WriteConflictError(w, msg)
 		return
 	}
-	log.Printf("Principal %s will be Leased to Account: %s\n", principalID,
-		account.ID)
-
-	// Create/Update lease record
-	now := time.Now()
-	lease, err := dao.UpsertLease(db.Lease{
-		AccountID:                account.ID,
-		PrincipalID:              requestBody.PrincipalID,
-		ID:                       uuid.New().String(),
-		LeaseStatus:              db.Active,
-		LeaseStatusReason:        db.LeaseActive,
-		BudgetAmount:             requestBody.BudgetAmount,
-		BudgetCurrency:           requestBody.BudgetCurrency,
-		BudgetNotificationEmails: requestBody.BudgetNotificationEmails,
-		CreatedOn:                now.Unix(),
-		LastModifiedOn:           now.Unix(),
-		LeaseStatusModifiedOn:    now.Unix(),
-		ExpiresOn:                requestBody.ExpiresOn,
+
+	// Claim a Ready account and create the lease,
+	// or reserve one for later if the lease has a start date
+	newLease := &lease.Lease{
+		PrincipalID:              &principalID,
+		BudgetAmount:             &requestBody.BudgetAmount,
+		BudgetCurrency:           &requestBody.BudgetCurrency,
+		BudgetNotificationEmails: &requestBody.BudgetNotificationEmails,
+		ExpiresOn:                &requestBody.ExpiresOn,
 		Metadata:                 requestBody.Metadata,
-	})
-	if err != nil {
-		log.Printf("Failed to create lease DB record for %s @ %s: %s",
-			requestBody.PrincipalID, account.ID, err)
-		response.WriteServerError(w)
-		return
 	}
-
-	// Mark the accOunt as Status=Leased
-	_, err = dao.TransitionAccountStatus(account.ID, db.Ready, db.Leased)
+	if len(requestBody.NotificationTargets) > 0 {
+		newLease.NotificationTargets = &requestBody.NotificationTargets
+	}
+	if requestBody.StartsOn != 0 {
+		newLease.StartsOn = &requestBody.StartsOn
+	}
+	// Only accounts in the requested pool may be leased
+	if requestBody.Pool != "" {
+		newLease.Pool = &requestBody.Pool
+	}
+	newLease, err = Services.LeaseService().Create(newLease)
 	if err != nil {
-		log.Printf("ERROR Failed to transition account %s to Leased for lease for %s. Attemping to deactivate lease...",
-			lease.AccountID, lease.PrincipalID)
-		// If setting the account status fails, attempt to deactivate the lease
-		// before returning a 500 error
-		_, err = dao.TransitionLeaseStatus(
-			lease.AccountID, lease.PrincipalID,
-			db.Active, db.Inactive, db.LeaseRolledBack,
-		)
-		if err != nil {
-			log.Printf("Failed to deactivate lease on DB error for %s / %s: %s",
-				lease.AccountID, lease.PrincipalID, err)
-		}
-
-		response.WriteServerError(w)
+		api.WriteAPIErrorResponse(w, err)
 		return
 	}
 
-	// Publish Lease to the topic
-	message, err := publishLease(snsSvc, lease, &leaseAddedTopicARN)
-	if err != nil {
-		log.Print(err.Error())
-
-		// Attempt to rollback the lease
-		_, err := dao.TransitionLeaseStatus(lease.AccountID, lease.PrincipalID, db.Active, db.Inactive, db.LeaseRolledBack)
-		if err != nil {
-			log.Printf("Failed to deactivate lease on SNS error for %s / %s: %s",
-				lease.AccountID, lease.PrincipalID, err)
-		} else {
-			_, err := dao.TransitionAccountStatus(lease.AccountID, db.Leased, db.Ready)
-			log.Printf("Failed to rollback account status on SNS error for %s / %s: %s",
-				lease.AccountID, lease.PrincipalID, err)
-		}
-
-		response.WriteServerError(w)
+	if *newLease.Status == lease.StatusScheduled {
+		log.Printf("Principal %s was scheduled lease %s starting at %d", principalID,
+			*newLease.ID, *newLease.StartsOn)
+		api.WriteAPIResponse(w, http.StatusCreated, newLease.Redacted())
 		return
 	}
-
-	response.WriteAPIResponse(w, http.StatusCreated, *message)
-}
-
-// publishLease is a helper function to create and publish an lease
-// structured message to an SNS Topic
-func publishLease(snsSvc common.Notificationer,
-	lease *db.Lease, topic *string) (*string, error) {
-	// Create a LeaseResponse based on the lease
-	leaseResp := response.CreateLeaseResponse(lease)
-
-	// Create the message to send to the topic from the Lease
-	messageBytes, err := json.Marshal(leaseResp)
-	if err != nil {
-		// Rollback
-		log.Printf("Error to Marshal Account Lease: %s", err)
-		return nil, err
+	// No account was available, so the lease is waiting in the queue
+	if *newLease.Status == lease.StatusPending {
+		log.Printf("Principal %s was queued with pending lease %s at position %d", principalID,
+			*newLease.ID, *newLease.QueuePosition)
+		api.WriteAPIResponse(w, http.StatusAccepted, newLease.Redacted())
+		return
 	}
-	message := string(messageBytes)
 
-	// Create the messageBody to make it compatible with SNS JSON
-	leaseMsgBody := messageBody{
-		Default: message,
-		Body:    message,
-	}
-	leaseMsgBytes, err := json.Marshal(leaseMsgBody)
-	if err != nil {
-		// Rollback
-		log.Printf("Error to Marshal Message Body: %s", err)
-		return nil, err
-	}
-	leaseMsg := string(leaseMsgBytes)
+	log.Printf("Principal %s was Leased Account %s with lease %s", principalID,
+		*newLease.AccountID, *newLease.ID)
 
-	// Publish message to the lease topic on the success of the lease creation
-	log.Printf("Sending Lease Message to SNS Topic %s\n", *topic)
-	messageID, err := snsSvc.PublishMessage(topic, &leaseMsg, true)
-	if err != nil {
-		return nil, errors.Wrapf(err, "Error to Send Message to SNS Topic %s", *topic)
-	}
-	log.Printf("Success Message Sent to SNS Topic %s: %s\n", *topic, *messageID)
-	return &message, nil
+	api.WriteAPIResponse(w, http.StatusCreated, newLease.Redacted())
 }
 
 // getBeginningOfCurrentBillingPeriod returns starts of the billing period based on budget period
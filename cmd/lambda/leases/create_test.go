@@ -3,261 +3,523 @@ package main
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"net/http"
-	"reflect"
-	"strings"
 	"testing"
 	"time"
 
-	"github.com/Optum/dce/pkg/usage"
-	util "github.com/Optum/dce/tests/testutils"
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/stretchr/testify/assert"
-	"github.com/stretchr/testify/require"
-
-	"github.com/stretchr/testify/mock"
-
+	"github.com/Optum/dce/pkg/api"
+	apiMocks "github.com/Optum/dce/pkg/api/mocks"
 	"github.com/Optum/dce/pkg/api/response"
-	"github.com/Optum/dce/pkg/common"
-	commonMock "github.com/Optum/dce/pkg/common/mocks"
-	"github.com/Optum/dce/pkg/db"
-	mockDB "github.com/Optum/dce/pkg/db/mocks"
+	"github.com/Optum/dce/pkg/budget"
+	"github.com/Optum/dce/pkg/budgetpolicy"
+	policyMocks "github.com/Optum/dce/pkg/budgetpolicy/budgetpolicyiface/mocks"
+	"github.com/Optum/dce/pkg/config"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/lease"
+	"github.com/Optum/dce/pkg/lease/leaseiface/mocks"
 	mockUsage "github.com/Optum/dce/pkg/usage/mocks"
 	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCreateController_Call(t *testing.T) {
 
 	t.Run("should create leases", func(t *testing.T) {
-		type (
-			fields struct {
-				Dao                   db.DBer
-				SNS                   common.Notificationer
-				LeaseTopicARN         *string
-				UsageSvc              usage.DBer
-				PrincipalBudgetAmount *float64
-				PrincipalBudgetPeriod *string
-				MaxLeaseBudgetAmount  *float64
-				MaxLeasePeriod        *int64
-			}
-		)
-		type args struct {
-			ctx context.Context
-			req *events.APIGatewayProxyRequest
-		}
-
-		leaseTopicARN := "some-topic-arn"
-		messageID := "message123456789"
-
-		amount := 1000.00
-		period := "WEEKLY"
-		leasePeriod := int64(704800)
-
-		principalBudgetAmount = amount
-		principalBudgetPeriod = period
-		maxLeaseBudgetAmount = amount
-		maxLeasePeriod = leasePeriod
-
-		dbMock := stubDb()
-		snsMock := &commonMock.Notificationer{}
-		usageMock := &mockUsage.DBer{}
 		sevenDaysOut := time.Now().AddDate(0, 0, 7).Unix()
 
-		// Should create/update the lease record
-		util.ReplaceMock(&dbMock.Mock, "UpsertLease", mock.MatchedBy(func(lease db.Lease) bool {
-			assert.Equal(t, "123456789012", lease.AccountID)
-			assert.Equal(t, "jdoe123", lease.PrincipalID)
-			assert.IsType(t, "string", lease.ID)
-			assert.Equal(t, db.Active, lease.LeaseStatus)
-			assert.Equal(t, db.LeaseActive, lease.LeaseStatusReason)
-			assert.Equal(t, float64(50), lease.BudgetAmount)
-			assert.Equal(t, "USD", lease.BudgetCurrency)
-			assert.Equal(t, []string{"user3@example.com", "user2@example.com"}, lease.BudgetNotificationEmails)
-			assert.Equal(t, sevenDaysOut, lease.ExpiresOn)
-
-			assert.InDelta(t, time.Now().Unix(), lease.CreatedOn, 2)
-			assert.Equal(t, lease.CreatedOn, lease.LastModifiedOn)
-			assert.Equal(t, lease.CreatedOn, lease.LeaseStatusModifiedOn)
-
-			return true
-		})).
-			Return(&db.Lease{}, nil)
-
-		// Should publish SNS message
-		snsMock.On("PublishMessage", &leaseTopicARN, mock.Anything, true).Return(&messageID, nil)
-		usageMock.On("GetUsageByPrincipal", mock.Anything, mock.Anything).Return(nil, nil)
-
-		testFields := &fields{
-			Dao:                   dbMock,
-			SNS:                   snsMock,
-			LeaseTopicARN:         &leaseTopicARN,
-			UsageSvc:              usageMock,
-			PrincipalBudgetAmount: &principalBudgetAmount,
-			PrincipalBudgetPeriod: &principalBudgetPeriod,
-			MaxLeaseBudgetAmount:  &maxLeaseBudgetAmount,
-			MaxLeasePeriod:        &maxLeasePeriod,
-		}
-
-		successResponse := createSuccessCreateResponse()
 		badRequestResponse := response.CreateMultiValueHeaderAPIErrorResponse(http.StatusBadRequest, "RequestValidationError", "invalid request parameters")
 		pastRequestResponse := response.RequestValidationError("Requested lease has a desired expiry date less than today: 1570627876")
 		invalidBudgetRequestResponse := response.RequestValidationError("Requested lease has a budget amount of 5000.000000, which is greater than max lease budget amount of 1000.000000")
-		invalidBudgetPeriodRequestResponse := response.RequestValidationError("Requested lease has a budget expires on of 1577745392, which is greater than max lease period of 1573176192")
-
-		successArgs := &args{ctx: context.Background(), req: createSuccessfulCreateRequest()}
-		pastArgs := &args{ctx: context.Background(), req: createPastCreateRequest()}
-		invalidBudgetArgs := &args{ctx: context.Background(), req: invalidBudgetAmountCreateRequest()}
-		invalidBudgetPeriodArgs := &args{ctx: context.Background(), req: invalidBudgetPeriodCreateRequest()}
-		badArgs := &args{ctx: context.Background(), req: createBadCreateRequest()}
 
 		tests := []struct {
-			name    string
-			fields  fields
-			args    args
-			want    events.APIGatewayProxyResponse
-			wantErr bool
+			name                  string
+			req                   *events.APIGatewayProxyRequest
+			principalBudgetAmount float64
+			maxLeaseBudgetAmount  float64
+			maxLeasePeriod        int64
+			expStatusCode         int
+			expResp               *events.APIGatewayProxyResponse
+			expCreate             bool
 		}{
 			{
-				name:    "Bad request.",
-				fields:  *testFields,
-				args:    *badArgs,
-				want:    badRequestResponse,
-				wantErr: false,
+				name:                  "Bad request.",
+				req:                   createBadCreateRequest(),
+				principalBudgetAmount: 1000,
+				maxLeaseBudgetAmount:  1000,
+				maxLeasePeriod:        704800,
+				expStatusCode:         400,
+				expResp:               &badRequestResponse,
 			},
 			{
-				name:    "Past request.",
-				fields:  *testFields,
-				args:    *pastArgs,
-				want:    pastRequestResponse,
-				wantErr: false,
+				name:                  "Past request.",
+				req:                   createPastCreateRequest(),
+				principalBudgetAmount: 1000,
+				maxLeaseBudgetAmount:  1000,
+				maxLeasePeriod:        704800,
+				expStatusCode:         400,
+				expResp:               &pastRequestResponse,
 			},
 			{
-				name:    "Invalid budget amount request.",
-				fields:  *testFields,
-				args:    *invalidBudgetArgs,
-				want:    invalidBudgetRequestResponse,
-				wantErr: false,
+				name:                  "Invalid budget amount request.",
+				req:                   invalidBudgetAmountCreateRequest(),
+				principalBudgetAmount: 1000,
+				maxLeaseBudgetAmount:  1000,
+				maxLeasePeriod:        704800,
+				expStatusCode:         400,
+				expResp:               &invalidBudgetRequestResponse,
 			},
 			{
-				name:    "Invalid budget period request.",
-				fields:  *testFields,
-				args:    *invalidBudgetPeriodArgs,
-				want:    invalidBudgetPeriodRequestResponse,
-				wantErr: false,
+				name:                  "Invalid budget period request.",
+				req:                   invalidBudgetPeriodCreateRequest(),
+				principalBudgetAmount: 1000,
+				maxLeaseBudgetAmount:  1000,
+				maxLeasePeriod:        704800,
+				expStatusCode:         400,
 			},
 			{
-				name: "Successful create.",
-				fields: fields{
-					Dao:                   dbMock,
-					SNS:                   snsMock,
-					LeaseTopicARN:         &leaseTopicARN,
-					UsageSvc:              usageMock,
-					PrincipalBudgetAmount: aws.Float64(9999999999),
-					PrincipalBudgetPeriod: &principalBudgetPeriod,
-					MaxLeaseBudgetAmount:  aws.Float64(9999999999),
-					MaxLeasePeriod:        aws.Int64(600000000),
-				},
-				args:    *successArgs,
-				want:    *successResponse,
-				wantErr: false,
+				name:                  "Successful create.",
+				req:                   createSuccessfulCreateRequest(),
+				principalBudgetAmount: 9999999999,
+				maxLeaseBudgetAmount:  9999999999,
+				maxLeasePeriod:        600000000,
+				expStatusCode:         201,
+				expCreate:             true,
 			},
 		}
+
 		for _, tt := range tests {
 			t.Run(tt.name, func(t *testing.T) {
-				dao = tt.fields.Dao
-				snsSvc = tt.fields.SNS
-				leaseAddedTopicARN = *tt.fields.LeaseTopicARN
-				usageSvc = tt.fields.UsageSvc
-				principalBudgetAmount = *tt.fields.PrincipalBudgetAmount
-				principalBudgetPeriod = *tt.fields.PrincipalBudgetPeriod
-				maxLeaseBudgetAmount = *tt.fields.MaxLeaseBudgetAmount
-				maxLeasePeriod = *tt.fields.MaxLeasePeriod
-				got, err := Handler(tt.args.ctx, *tt.args.req)
-				if (err != nil) != tt.wantErr {
-					t.Errorf("CreateController.Call() error = %v, wantErr %v", err, tt.wantErr)
-					return
+				principalBudgetAmount = tt.principalBudgetAmount
+				principalBudgetPeriod = Weekly
+				maxLeaseBudgetAmount = tt.maxLeaseBudgetAmount
+				maxLeasePeriod = tt.maxLeasePeriod
+
+				leaseSvc := stubLeaseService(nil)
+				setupCreateServices(t, leaseSvc)
+
+				got, err := Handler(context.TODO(), *tt.req)
+				require.Nil(t, err)
+				assert.Equal(t, tt.expStatusCode, got.StatusCode)
+				if tt.expResp != nil {
+					assert.Equal(t, *tt.expResp, got)
 				}
-				if !reflect.DeepEqual(got, tt.want) {
 
-					//comparing first 50 characters of error message for invalid budget period error
-					if strings.HasPrefix(tt.want.Body, got.Body[:30]) {
-						return
-					}
-					t.Errorf("CreateController.Call() = %v, want %v", got, tt.want)
+				if tt.expCreate {
+					leaseSvc.AssertCalled(t, "Create", mock.MatchedBy(func(input *lease.Lease) bool {
+						return *input.PrincipalID == "jdoe123" &&
+							input.AccountID == nil &&
+							*input.BudgetAmount == float64(50) &&
+							*input.BudgetCurrency == "USD" &&
+							assert.ObjectsAreEqual([]string{"user3@example.com", "user2@example.com"}, *input.BudgetNotificationEmails) &&
+							*input.ExpiresOn-sevenDaysOut < 2
+					}))
+				} else {
+					leaseSvc.AssertNotCalled(t, "Create", mock.Anything)
+				}
+			})
+		}
+	})
+
+	t.Run("should accept a pending lease when no accounts are available", func(t *testing.T) {
+		leaseSvc := &mocks.Servicer{}
+		leaseSvc.On("ListPages", mock.Anything, mock.Anything).Return(nil)
+		leaseSvc.On("Create", mock.AnythingOfType("*lease.Lease")).
+			Return(func(input *lease.Lease) *lease.Lease {
+				input.ID = ptrString("70c2d96d-7938-4ec9-917d-476f2b09cc04")
+				input.AccountID = ptrString(lease.PendingAccountID("70c2d96d-7938-4ec9-917d-476f2b09cc04"))
+				input.Status = lease.StatusPending.StatusPtr()
+				input.QueuePosition = ptrInt64(3)
+				return input
+			}, nil)
+		setupCreateServices(t, leaseSvc)
+
+		principalBudgetAmount = 9999999999
+		maxLeaseBudgetAmount = 9999999999
+		maxLeasePeriod = 600000000
+
+		got, err := Handler(context.TODO(), *createSuccessfulCreateRequest())
+		require.Nil(t, err)
+		require.Equal(t, 202, got.StatusCode)
+
+		resLease := unmarshal(t, got.Body)
+		require.Equal(t, "Pending", resLease["leaseStatus"])
+		require.Equal(t, float64(3), resLease["queuePosition"])
+	})
+
+	t.Run("should schedule a lease with a start date", func(t *testing.T) {
+		leaseSvc := &mocks.Servicer{}
+		leaseSvc.On("ListPages", mock.Anything, mock.Anything).Return(nil)
+		leaseSvc.On("Create", mock.AnythingOfType("*lease.Lease")).
+			Return(func(input *lease.Lease) *lease.Lease {
+				input.ID = ptrString("70c2d96d-7938-4ec9-917d-476f2b09cc04")
+				input.AccountID = ptrString(lease.ScheduledAccountID("70c2d96d-7938-4ec9-917d-476f2b09cc04"))
+				input.Status = lease.StatusScheduled.StatusPtr()
+				return input
+			}, nil)
+		setupCreateServices(t, leaseSvc)
+
+		principalBudgetAmount = 9999999999
+		maxLeaseBudgetAmount = 9999999999
+		maxLeasePeriod = 704800
+		defaultLeaseLengthInDays = 7
+
+		startsOn := time.Now().AddDate(0, 0, 30).Unix()
+		got, err := Handler(context.TODO(), *apiGatewayRequest(t, map[string]interface{}{
+			"principalId":    "jdoe123",
+			"budgetAmount":   50,
+			"budgetCurrency": "USD",
+			"startsOn":       startsOn,
+		}))
+		require.Nil(t, err)
+		require.Equal(t, 201, got.StatusCode)
+
+		resLease := unmarshal(t, got.Body)
+		require.Equal(t, "Scheduled", resLease["leaseStatus"])
+		require.Equal(t, float64(startsOn), resLease["startsOn"])
+
+		// The lease period is measured from the start date
+		leaseSvc.AssertCalled(t, "Create", mock.MatchedBy(func(input *lease.Lease) bool {
+			return *input.StartsOn == startsOn &&
+				*input.ExpiresOn == time.Unix(startsOn, 0).AddDate(0, 0, 7).Unix()
+		}))
+	})
+
+	t.Run("should request a lease from an account pool", func(t *testing.T) {
+		leaseSvc := &mocks.Servicer{}
+		leaseSvc.On("ListPages", mock.Anything, mock.Anything).Return(nil)
+		leaseSvc.On("Create", mock.AnythingOfType("*lease.Lease")).
+			Return(func(input *lease.Lease) *lease.Lease {
+				input.ID = ptrString("70c2d96d-7938-4ec9-917d-476f2b09cc04")
+				input.AccountID = ptrString("123456789012")
+				input.Status = lease.StatusActive.StatusPtr()
+				return input
+			}, nil)
+		setupCreateServices(t, leaseSvc)
+
+		principalBudgetAmount = 9999999999
+		maxLeaseBudgetAmount = 9999999999
+		maxLeasePeriod = 704800
+
+		got, err := Handler(context.TODO(), *apiGatewayRequest(t, map[string]interface{}{
+			"principalId":    "jdoe123",
+			"budgetAmount":   50,
+			"budgetCurrency": "USD",
+			"pool":           "gov-region",
+		}))
+		require.Nil(t, err)
+		require.Equal(t, 201, got.StatusCode)
+
+		resLease := unmarshal(t, got.Body)
+		require.Equal(t, "gov-region", resLease["pool"])
+		leaseSvc.AssertCalled(t, "Create", mock.MatchedBy(func(input *lease.Lease) bool {
+			return input.Pool != nil && *input.Pool == "gov-region"
+		}))
+	})
+
+	t.Run("should create a lease with notification targets", func(t *testing.T) {
+		leaseSvc := stubLeaseService(nil)
+		setupCreateServices(t, leaseSvc)
+
+		principalBudgetAmount = 9999999999
+		maxLeaseBudgetAmount = 9999999999
+		maxLeasePeriod = 704800
+		Settings.NotificationTargetAllowedHosts = []string{"hooks.slack.com"}
+
+		got, err := Handler(context.TODO(), *apiGatewayRequest(t, map[string]interface{}{
+			"principalId":         "jdoe123",
+			"budgetAmount":        50,
+			"notificationTargets": []string{"slack:https://hooks.slack.com/services/T000/B000/XXXX"},
+		}))
+		require.Nil(t, err)
+		require.Equal(t, 201, got.StatusCode)
+		// Webhook URLs are secrets, so they're redacted in the response
+		resLease := unmarshal(t, got.Body)
+		require.Equal(t, []interface{}{"slack:https://hooks.slack.com/***"}, resLease["notificationTargets"])
+
+		leaseSvc.AssertCalled(t, "Create", mock.MatchedBy(func(input *lease.Lease) bool {
+			return input.NotificationTargets != nil &&
+				(*input.NotificationTargets)[0] == "slack:https://hooks.slack.com/services/T000/B000/XXXX"
+		}))
+	})
+
+	t.Run("should not create a lease with an invalid notification target", func(t *testing.T) {
+		leaseSvc := stubLeaseService(nil)
+		setupCreateServices(t, leaseSvc)
+
+		got, err := Handler(context.TODO(), *apiGatewayRequest(t, map[string]interface{}{
+			"principalId":         "jdoe123",
+			"budgetAmount":        50,
+			"notificationTargets": []string{"slack:http://hooks.slack.com/services/T000/B000/XXXX"},
+		}))
+		require.Nil(t, err)
+		require.Equal(t, 400, got.StatusCode)
+		require.Contains(t, got.Body, "Requested lease has an invalid notification target: notification target URL must be an HTTPS URL")
+		leaseSvc.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("should not create a lease with a notification target host which isn't allowed", func(t *testing.T) {
+		leaseSvc := stubLeaseService(nil)
+		setupCreateServices(t, leaseSvc)
+
+		Settings.NotificationTargetAllowedHosts = []string{"hooks.slack.com"}
+
+		got, err := Handler(context.TODO(), *apiGatewayRequest(t, map[string]interface{}{
+			"principalId":         "jdoe123",
+			"budgetAmount":        50,
+			"notificationTargets": []string{"webhook:https://internal.example.com/admin"},
+		}))
+		require.Nil(t, err)
+		require.Equal(t, 400, got.StatusCode)
+		require.Contains(t, got.Body, "Requested lease has an invalid notification target: notification target host \\\"internal.example.com\\\" is not allowed")
+		leaseSvc.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("should not schedule a lease with a start date in the past", func(t *testing.T) {
+		leaseSvc := stubLeaseService(nil)
+		setupCreateServices(t, leaseSvc)
+
+		got, err := Handler(context.TODO(), *apiGatewayRequest(t, map[string]interface{}{
+			"principalId": "jdoe123",
+			"startsOn":    1570627876,
+		}))
+		require.Nil(t, err)
+		require.Equal(t, 400, got.StatusCode)
+		require.Contains(t, got.Body, "Requested lease has a desired start date less than today: 1570627876")
+		leaseSvc.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("should apply the budget policy of the principal", func(t *testing.T) {
+		leaseSvc := stubLeaseService(nil)
+		setupCreateServicesWithPolicy(t, leaseSvc, &budgetpolicy.BudgetPolicy{
+			Group:                ptrString("Interns"),
+			MaxLeaseBudgetAmount: aws.Float64(20),
+		})
+
+		principalBudgetAmount = 9999999999
+		maxLeaseBudgetAmount = 9999999999
+		maxLeasePeriod = 704800
+
+		got, err := Handler(context.TODO(), *apiGatewayRequest(t, map[string]interface{}{
+			"principalId":    "jdoe123",
+			"budgetAmount":   50,
+			"budgetCurrency": "USD",
+		}))
+		require.Nil(t, err)
+		require.Equal(t,
+			response.RequestValidationError("Requested lease has a budget amount of 50.000000, which is greater than max lease budget amount of 20.000000"),
+			got,
+		)
+		leaseSvc.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("should validate the budget in the requested currency", func(t *testing.T) {
+		defer func() { exchangeRates = budget.ExchangeRates{} }()
+		exchangeRates = budget.ExchangeRates{"EUR": 0.5}
+
+		principalBudgetAmount = 9999999999
+		maxLeaseBudgetAmount = 1000
+		maxLeasePeriod = 704800
+
+		tests := []struct {
+			name           string
+			budgetAmount   float64
+			budgetCurrency string
+			expResp        events.APIGatewayProxyResponse
+		}{
+			{
+				name:           "should create leases within the max lease budget amount",
+				budgetAmount:   400,
+				budgetCurrency: "eur",
+			},
+			{
+				name:           "should convert the budget amount to USD",
+				budgetAmount:   600,
+				budgetCurrency: "EUR",
+				expResp:        response.RequestValidationError("Requested lease has a budget amount of 1200.000000, which is greater than max lease budget amount of 1000.000000"),
+			},
+			{
+				name:           "should fail for currencies without an exchange rate",
+				budgetAmount:   400,
+				budgetCurrency: "GBP",
+				expResp:        response.RequestValidationError("Requested lease has an invalid budget currency: No exchange rate is configured for currency GBP"),
+			},
+			{
+				name:           "should fail for invalid currencies",
+				budgetAmount:   400,
+				budgetCurrency: "Euros",
+				expResp:        response.RequestValidationError("Requested lease has an invalid budget currency: EUROS is not a valid ISO 4217 currency code"),
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				leaseSvc := stubLeaseService(nil)
+				setupCreateServices(t, leaseSvc)
+
+				got, err := Handler(context.TODO(), *apiGatewayRequest(t, map[string]interface{}{
+					"principalId":    "jdoe123",
+					"budgetAmount":   tt.budgetAmount,
+					"budgetCurrency": tt.budgetCurrency,
+				}))
+				require.Nil(t, err)
+				if tt.expResp.StatusCode != 0 {
+					require.Equal(t, tt.expResp, got)
+					leaseSvc.AssertNotCalled(t, "Create", mock.Anything)
+					return
 				}
+				require.Equal(t, http.StatusCreated, got.StatusCode)
+				leaseSvc.AssertCalled(t, "Create", mock.MatchedBy(func(input *lease.Lease) bool {
+					return *input.BudgetAmount == tt.budgetAmount &&
+						*input.BudgetCurrency == "EUR"
+				}))
 			})
 		}
 	})
 
 	t.Run("should fail if the principal already has a lease", func(t *testing.T) {
 		// Mock active lease for the principal
-		dbMock := stubDb()
-		util.ReplaceMock(&dbMock.Mock, "FindLeasesByPrincipal", "jdoe123").
-			Return([]*db.Lease{{
-				AccountID:   "123456789012",
-				LeaseStatus: db.Active,
-			}}, nil)
-
-		// Create the controller
-		dao = dbMock
+		leaseSvc := stubLeaseService(&lease.Leases{
+			{AccountID: ptrString("123456789012"), Status: lease.StatusActive.StatusPtr()},
+		})
+		setupCreateServices(t, leaseSvc)
 
 		// Call the controller
 		res, err := Handler(context.TODO(), *apiGatewayRequest(t, map[string]interface{}{
 			"principalId":    "jdoe123",
 			"budgetAmount":   100,
 			"budgetCurrency": "USD",
-			"expiresOn":      time.Now().AddDate(0, 0, 7).Unix(),
 		}))
 		require.Nil(t, err)
-		// Check HTTP error response
 		require.Equal(t,
-			response.CreateMultiValueHeaderAPIErrorResponse(http.StatusConflict, "ClientError", "Principal already has an active lease for account 123456789012"),
+			response.CreateMultiValueHeaderAPIErrorResponse(http.StatusConflict, "ClientError", "Principal already has the maximum of 1 active, pending or scheduled lease(s): account 123456789012"),
 			res,
 		)
+		leaseSvc.AssertCalled(t, "ListPages", &lease.Lease{
+			PrincipalID: ptrString("jdoe123"),
+			Status:      lease.StatusActive.StatusPtr(),
+		}, mock.Anything)
+		leaseSvc.AssertNotCalled(t, "Create", mock.Anything)
 	})
 
-	t.Run("should mark the account.Status=Leased", func(t *testing.T) {
-		// Setup the controller
-		dbMock := stubDb()
-		dao = dbMock
+	t.Run("should count pending and scheduled leases towards the maximum", func(t *testing.T) {
+		defer func() { maxActiveLeasesPerPrincipal = 1 }()
+		maxActiveLeasesPerPrincipal = 2
 
-		// Return a ready account
-		util.ReplaceMock(&dbMock.Mock, "GetReadyAccount").
-			Return(&db.Account{ID: "123456789012"}, nil)
+		leaseSvc := stubLeaseService(&lease.Leases{
+			{
+				ID:        ptrString("a1b2c3d4-0000-0000-0000-000000000001"),
+				AccountID: ptrString(lease.PendingAccountID("a1b2c3d4-0000-0000-0000-000000000001")),
+				Status:    lease.StatusPending.StatusPtr(),
+			},
+			{
+				ID:        ptrString("a1b2c3d4-0000-0000-0000-000000000002"),
+				AccountID: ptrString(lease.ScheduledAccountID("a1b2c3d4-0000-0000-0000-000000000002")),
+				Status:    lease.StatusScheduled.StatusPtr(),
+			},
+		})
+		setupCreateServices(t, leaseSvc)
 
-		// Should set account.Status=Leased
-		util.ReplaceMock(&dbMock.Mock, "TransitionAccountStatus",
-			"123456789012", db.Ready, db.Leased,
-		).Return(&db.Account{}, nil)
+		res, err := Handler(context.TODO(), *apiGatewayRequest(t, map[string]interface{}{
+			"principalId":    "jdoe123",
+			"budgetAmount":   100,
+			"budgetCurrency": "USD",
+		}))
+		require.Nil(t, err)
+		require.Equal(t,
+			response.CreateMultiValueHeaderAPIErrorResponse(http.StatusConflict, "ClientError", "Principal already has the maximum of 2 active, pending or scheduled lease(s): Pending lease a1b2c3d4-0000-0000-0000-000000000001, Scheduled lease a1b2c3d4-0000-0000-0000-000000000002"),
+			res,
+		)
+		leaseSvc.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("should count the active leases of every page towards the maximum", func(t *testing.T) {
+		defer func() { maxActiveLeasesPerPrincipal = 1 }()
+		maxActiveLeasesPerPrincipal = 2
+
+		// The stubbed lease service returns each lease on its own page
+		leaseSvc := stubLeaseService(&lease.Leases{
+			{AccountID: ptrString("123456789012"), Status: lease.StatusActive.StatusPtr()},
+			{AccountID: ptrString("210987654321"), Status: lease.StatusActive.StatusPtr()},
+		})
+		setupCreateServices(t, leaseSvc)
+
+		res, err := Handler(context.TODO(), *apiGatewayRequest(t, map[string]interface{}{
+			"principalId":    "jdoe123",
+			"budgetAmount":   100,
+			"budgetCurrency": "USD",
+		}))
+		require.Nil(t, err)
+		require.Equal(t,
+			response.CreateMultiValueHeaderAPIErrorResponse(http.StatusConflict, "ClientError", "Principal already has the maximum of 2 active, pending or scheduled lease(s): account 123456789012, account 210987654321"),
+			res,
+		)
+		leaseSvc.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("should allow multiple active leases up to the configured maximum", func(t *testing.T) {
+		defer func() { maxActiveLeasesPerPrincipal = 1 }()
+		maxActiveLeasesPerPrincipal = 3
+
+		leaseSvc := stubLeaseService(&lease.Leases{
+			{AccountID: ptrString("123456789012"), Status: lease.StatusActive.StatusPtr()},
+			{AccountID: ptrString("210987654321"), Status: lease.StatusActive.StatusPtr()},
+		})
+		setupCreateServices(t, leaseSvc)
 
-		// Call the controller
 		res, err := Handler(context.TODO(), *apiGatewayRequest(t, map[string]interface{}{
 			"principalId":    "jdoe123",
 			"budgetAmount":   100,
 			"budgetCurrency": "USD",
-			"expiresOn":      time.Now().AddDate(0, 0, 7).Unix(),
 		}))
 		require.Nil(t, err)
 		require.Equal(t, 201, res.StatusCode)
 
-		dbMock.AssertNumberOfCalls(t, "TransitionAccountStatus", 1)
+		// Fail once the principal reaches the maximum
+		leaseSvc = stubLeaseService(&lease.Leases{
+			{AccountID: ptrString("123456789012"), Status: lease.StatusActive.StatusPtr()},
+			{AccountID: ptrString("210987654321"), Status: lease.StatusActive.StatusPtr()},
+			{AccountID: ptrString("111111111111"), Status: lease.StatusActive.StatusPtr()},
+		})
+		setupCreateServices(t, leaseSvc)
+
+		res, err = Handler(context.TODO(), *apiGatewayRequest(t, map[string]interface{}{
+			"principalId":    "jdoe123",
+			"budgetAmount":   100,
+			"budgetCurrency": "USD",
+		}))
+		require.Nil(t, err)
+		require.Equal(t,
+			response.CreateMultiValueHeaderAPIErrorResponse(http.StatusConflict, "ClientError", "Principal already has the maximum of 3 active, pending or scheduled lease(s): account 123456789012, account 210987654321, account 111111111111"),
+			res,
+		)
 	})
 
-	t.Run("should set default expiresOn", func(t *testing.T) {
-		dbMock := stubDb()
+	t.Run("should respond with the created lease", func(t *testing.T) {
+		leaseSvc := stubLeaseService(nil)
+		setupCreateServices(t, leaseSvc)
 
-		// Should set expiresOn to 7 days from now
-		util.ReplaceMock(&dbMock.Mock, "UpsertLease", mock.MatchedBy(func(lease db.Lease) bool {
-			assert.InDelta(t, time.Now().Add(7*time.Hour*24).Unix(), lease.ExpiresOn, 2)
+		// Call the controller
+		res, err := Handler(context.TODO(), *apiGatewayRequest(t, map[string]interface{}{
+			"principalId":    "jdoe123",
+			"budgetAmount":   100,
+			"budgetCurrency": "USD",
+			"expiresOn":      time.Now().AddDate(0, 0, 7).Unix(),
+		}))
+		require.Nil(t, err)
+		require.Equal(t, 201, res.StatusCode)
 
-			return true
-		})).Return(func(lease db.Lease) *db.Lease {
-			return &lease
-		}, nil)
+		resJSON := unmarshal(t, res.Body)
+		require.Equal(t, "123456789012", resJSON["accountId"])
+		require.Equal(t, "jdoe123", resJSON["principalId"])
+		require.Equal(t, "Active", resJSON["leaseStatus"])
+	})
 
-		dao = dbMock
+	t.Run("should set default expiresOn", func(t *testing.T) {
+		leaseSvc := stubLeaseService(nil)
+		setupCreateServices(t, leaseSvc)
 
 		// Call the controller
 		res, err := Handler(context.TODO(), *apiGatewayRequest(t, map[string]interface{}{
@@ -273,22 +535,8 @@ func TestCreateController_Call(t *testing.T) {
 	})
 
 	t.Run("should create lease with metadata", func(t *testing.T) {
-		// Setup the controller
-		dbMock := stubDb()
-		dao = dbMock
-
-		// Should put lease metadata to DB
-		util.ReplaceMock(&dbMock.Mock, "UpsertLease",
-			mock.MatchedBy(func(lease db.Lease) bool {
-				assert.Equal(t, map[string]interface{}{
-					"foo": "bar",
-					"faz": "baz",
-				}, lease.Metadata)
-				return true
-			}),
-		).Return(func(lease db.Lease) *db.Lease {
-			return &lease
-		}, nil)
+		leaseSvc := stubLeaseService(nil)
+		setupCreateServices(t, leaseSvc)
 
 		// Call the controller with some metadata
 		res, err := Handler(context.TODO(), *apiGatewayRequest(t, map[string]interface{}{
@@ -311,35 +559,18 @@ func TestCreateController_Call(t *testing.T) {
 			"faz": "baz",
 		}, resJSON["metadata"])
 
-		// Verify our DB call
-		dbMock.AssertExpectations(t)
+		// Should pass lease metadata to the lease service
+		leaseSvc.AssertCalled(t, "Create", mock.MatchedBy(func(input *lease.Lease) bool {
+			return assert.ObjectsAreEqual(map[string]interface{}{
+				"foo": "bar",
+				"faz": "baz",
+			}, input.Metadata)
+		}))
 	})
 
 	t.Run("should allow complex types in metadata", func(t *testing.T) {
-		// Setup the controller
-		// Setup the controller
-		dbMock := stubDb()
-		dao = dbMock
-
-		// Should put lease metadata to DB
-		util.ReplaceMock(&dbMock.Mock, "UpsertLease",
-			mock.MatchedBy(func(lease db.Lease) bool {
-				assert.Equal(t, map[string]interface{}{
-					"foo": map[string]interface{}{
-						"bar": map[string]interface{}{
-							"faz":   "baz",
-							"int":   float64(5),
-							"float": 1.2345,
-							"bool":  true,
-							"nil":   nil,
-						},
-					},
-				}, lease.Metadata)
-				return true
-			}),
-		).Return(func(lease db.Lease) *db.Lease {
-			return &lease
-		}, nil)
+		leaseSvc := stubLeaseService(nil)
+		setupCreateServices(t, leaseSvc)
 
 		// Call the controller with some metadata
 		res, err := Handler(context.TODO(), *apiGatewayRequest(t, map[string]interface{}{
@@ -375,27 +606,11 @@ func TestCreateController_Call(t *testing.T) {
 				},
 			},
 		}, resJSON["metadata"])
-
-		// Verify our DB call
-		dbMock.AssertExpectations(t)
 	})
 
 	t.Run("should default to an empty metadata object", func(t *testing.T) {
-		// Setup the controller
-		// Setup the controller
-		dbMock := stubDb()
-		dao = dbMock
-
-		// Should put lease metadata to DB
-		util.ReplaceMock(&dbMock.Mock, "UpsertLease",
-			mock.MatchedBy(func(lease db.Lease) bool {
-				// Should save empty metadata to DB
-				assert.Equal(t, map[string]interface{}{}, lease.Metadata)
-				return true
-			}),
-		).Return(func(lease db.Lease) *db.Lease {
-			return &lease
-		}, nil)
+		leaseSvc := stubLeaseService(nil)
+		setupCreateServices(t, leaseSvc)
 
 		// Call the controller with no metadata
 		res, err := Handler(context.TODO(), *apiGatewayRequest(t, map[string]interface{}{
@@ -406,16 +621,15 @@ func TestCreateController_Call(t *testing.T) {
 		require.Nil(t, err)
 		require.Equal(t, 201, res.StatusCode)
 
-		// Check that controller responded with empty metadata
-		resJSON := unmarshal(t, res.Body)
-		require.Contains(t, resJSON, "metadata")
-		require.Equal(t, map[string]interface{}{}, resJSON["metadata"])
-
-		// Verify our DB call
-		dbMock.AssertExpectations(t)
+		// Should save empty metadata
+		leaseSvc.AssertCalled(t, "Create", mock.MatchedBy(func(input *lease.Lease) bool {
+			return assert.ObjectsAreEqual(map[string]interface{}{}, input.Metadata)
+		}))
 	})
 
 	t.Run("should not allow non-object types for metadata", func(t *testing.T) {
+		leaseSvc := stubLeaseService(nil)
+		setupCreateServices(t, leaseSvc)
 
 		// Metadata must be a JSON object
 		invalidMetadatas := []interface{}{
@@ -441,110 +655,108 @@ func TestCreateController_Call(t *testing.T) {
 				"should fail for metadata: %s", metadata,
 			)
 		}
+		leaseSvc.AssertNotCalled(t, "Create", mock.Anything)
 	})
 
-	t.Run("should deactivate the lease if the account status update fails", func(t *testing.T) {
-		// Setup the controller
-		dbMock := stubDb()
-		dao = dbMock
-
-		// Mock account status update to fail
-		util.ReplaceMock(&dbMock.Mock, "TransitionAccountStatus",
-			mock.Anything, mock.Anything, mock.Anything,
-		).Return(nil, errors.New("test error"))
+	t.Run("should return a 503 when no accounts are available", func(t *testing.T) {
+		leaseSvc := &mocks.Servicer{}
+		leaseSvc.On("ListPages", mock.Anything, mock.Anything).Return(nil)
+		leaseSvc.On("Create", mock.Anything).Return(nil, errors.NewServiceUnavailable("No Available accounts at this moment"))
+		setupCreateServices(t, leaseSvc)
 
-		// Should deactivate the lease
-		util.ReplaceMock(&dbMock.Mock, "TransitionLeaseStatus",
-			"123456789012", "jdoe123",
-			db.Active, db.Inactive, db.LeaseRolledBack,
-		).Return(&db.Lease{}, nil)
-
-		// Call the controller
 		res, err := Handler(context.TODO(), *apiGatewayRequest(t, map[string]interface{}{
 			"principalId":    "jdoe123",
 			"budgetAmount":   100,
 			"budgetCurrency": "USD",
 		}))
 		require.Nil(t, err)
-
-		// Should return a 500 error
-		require.Equal(t, response.CreateMultiValueHeaderAPIErrorResponse(http.StatusInternalServerError, "ServerError", "Internal server error"), res)
-
-		// Should have deactivated lease
-		dbMock.AssertNumberOfCalls(t, "TransitionLeaseStatus", 1)
+		require.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+		require.Equal(t, "{\"error\":{\"message\":\"No Available accounts at this moment\",\"code\":\"ServerError\"}}\n", res.Body)
 	})
 
-	t.Run("should deactivate the lease and update the account status if the SNS publish fails", func(t *testing.T) {
-		// Mock SNS to fail
-		snsMock := &commonMock.Notificationer{}
-		snsMock.On("PublishMessage", mock.Anything, mock.Anything, mock.Anything).
-			Return(nil, errors.New("test error"))
-
-		dbMock := stubDb()
-		snsSvc = snsMock
-		dao = dbMock
-
-		// Should deactivate the lease
-		util.ReplaceMock(&dbMock.Mock, "TransitionLeaseStatus",
-			"123456789012", "jdoe123",
-			db.Active, db.Inactive, db.LeaseRolledBack,
-		).Return(&db.Lease{}, nil)
-
-		// Should mark Account.Status=Ready
-		dbMock.On("TransitionAccountStatus",
-			"123456789012", db.Leased, db.Ready,
-		).Return(&db.Account{}, nil)
+	t.Run("should return a 500 when the lease cannot be created", func(t *testing.T) {
+		leaseSvc := &mocks.Servicer{}
+		leaseSvc.On("ListPages", mock.Anything, mock.Anything).Return(nil)
+		leaseSvc.On("Create", mock.Anything).Return(nil, errors.NewInternalServer("failure", fmt.Errorf("original failure")))
+		setupCreateServices(t, leaseSvc)
 
-		// Call the controller
 		res, err := Handler(context.TODO(), *apiGatewayRequest(t, map[string]interface{}{
 			"principalId":    "jdoe123",
 			"budgetAmount":   100,
 			"budgetCurrency": "USD",
 		}))
 		require.Nil(t, err)
+		require.Equal(t, http.StatusInternalServerError, res.StatusCode)
+	})
 
-		// Should return a 500 error
-		require.Equal(t, response.CreateMultiValueHeaderAPIErrorResponse(http.StatusInternalServerError, "ServerError", "Internal server error"), res)
+}
 
-		// Should have deactivated lease
-		dbMock.AssertNumberOfCalls(t, "TransitionLeaseStatus", 1)
-		dbMock.AssertNumberOfCalls(t, "TransitionAccountStatus", 2)
-	})
+// stubLeaseService creates a mock lease Servicer, where the principal
+// holds the given active leases, and Create succeeds on account 123456789012
+func stubLeaseService(principalLeases *lease.Leases) *mocks.Servicer {
+	if principalLeases == nil {
+		principalLeases = &lease.Leases{}
+	}
 
-	// ...otherwise, we'd end up with a Lease=Active, Account=Ready state.
-	t.Run("should not rollback accounts status on SNS rollback, if the lease deactivation fails", func(t *testing.T) {
-		// Mock SNS to fail
-		snsMock := &commonMock.Notificationer{}
-		snsMock.On("PublishMessage", mock.Anything, mock.Anything, mock.Anything).
-			Return(nil, errors.New("test error"))
+	leaseSvc := &mocks.Servicer{}
+	// Return the leases matching the queried status, a page per lease
+	leaseSvc.On("ListPages", mock.AnythingOfType("*lease.Lease"), mock.Anything).
+		Run(func(args mock.Arguments) {
+			query := args.Get(0).(*lease.Lease)
+			fn := args.Get(1).(func(*lease.Leases) bool)
+			for _, l := range *principalLeases {
+				if query.Status == nil || (l.Status != nil && *l.Status == *query.Status) {
+					if !fn(&lease.Leases{l}) {
+						return
+					}
+				}
+			}
+		}).Return(nil)
+	leaseSvc.On("Create", mock.AnythingOfType("*lease.Lease")).
+		// Return a copy of the lease object that was passed to this method,
+		// with the account claimed for it
+		Return(func(input *lease.Lease) *lease.Lease {
+			created := *input
+			created.ID = ptrString("70c2d96d-7938-4ec9-917d-476f2b09cc04")
+			created.AccountID = ptrString("123456789012")
+			created.Status = lease.StatusActive.StatusPtr()
+			return &created
+		}, nil)
 
-		dbMock := stubDb()
-		snsSvc = snsMock
-		dao = dbMock
+	return leaseSvc
+}
 
-		// Should deactivate the lease (FAILS)
-		util.ReplaceMock(&dbMock.Mock, "TransitionLeaseStatus",
-			"123456789012", "jdoe123",
-			db.Active, db.Inactive, db.LeaseRolledBack,
-		).Return(nil, errors.New("test error"))
+// setupCreateServices configures the controller Services with the given
+// lease service, and an admin user
+func setupCreateServices(t *testing.T, leaseSvc *mocks.Servicer) {
+	setupCreateServicesWithPolicy(t, leaseSvc, nil)
+}
 
-		// Call the controller
-		res, err := Handler(context.TODO(), *apiGatewayRequest(t, map[string]interface{}{
-			"principalId":    "jdoe123",
-			"budgetAmount":   100,
-			"budgetCurrency": "USD",
-		}))
-		require.Nil(t, err)
+// setupCreateServicesWithPolicy configures the controller Services like
+// setupCreateServices, with a budget policy matching every principal
+func setupCreateServicesWithPolicy(t *testing.T, leaseSvc *mocks.Servicer, policy *budgetpolicy.BudgetPolicy) {
+	cfgBldr := &config.ConfigurationBuilder{}
+	svcBldr := &config.ServiceBuilder{Config: cfgBldr}
 
-		// Should return a 500 error
-		require.Equal(t, response.CreateMultiValueHeaderAPIErrorResponse(http.StatusInternalServerError, "ServerError", "Internal server error"), res)
+	usageMock := &mockUsage.DBer{}
+	usageMock.On("GetUsageByPrincipal", mock.Anything, mock.Anything).Return(nil, nil)
+	usageSvc = usageMock
 
-		// Should have deactivated lease
-		dbMock.AssertNumberOfCalls(t, "TransitionLeaseStatus", 1)
-		// Should only call this once, for the original Account Status=Leased
-		dbMock.AssertNumberOfCalls(t, "TransitionAccountStatus", 1)
+	userDetailSvc := apiMocks.UserDetailer{}
+	userDetailSvc.On("GetUser", mock.Anything).Return(&api.User{
+		Username: "admin1",
+		Role:     api.AdminGroupName,
 	})
+	policySvc := policyMocks.Servicer{}
+	policySvc.On("Match", mock.Anything).Return(policy, nil)
 
+	svcBldr.Config.WithService(&userDetailSvc)
+	svcBldr.Config.WithService(leaseSvc)
+	svcBldr.Config.WithService(&policySvc)
+	_, err := svcBldr.Build()
+	require.Nil(t, err)
+
+	Services = svcBldr
 }
 
 func createSuccessfulCreateRequest() *events.APIGatewayProxyRequest {
@@ -598,13 +810,6 @@ func createPastCreateRequest() *events.APIGatewayProxyRequest {
 	}
 }
 
-func createSuccessCreateResponse() *events.APIGatewayProxyResponse {
-	return &events.APIGatewayProxyResponse{
-		StatusCode: 201,
-		Body:       "{\"accountId\":\"\",\"principalId\":\"\",\"id\":\"\",\"leaseStatus\":\"\",\"leaseStatusReason\":\"\",\"createdOn\":0,\"lastModifiedOn\":0,\"budgetAmount\":0,\"budgetCurrency\":\"\",\"budgetNotificationEmails\":null,\"leaseStatusModifiedOn\":0,\"expiresOn\":0,\"metadata\":{}}",
-	}
-}
-
 func unmarshal(t *testing.T, jsonStr string) map[string]interface{} {
 	var data map[string]interface{}
 	err := json.Unmarshal([]byte(jsonStr), &data)
@@ -646,29 +851,3 @@ func invalidBudgetPeriodCreateRequest() *events.APIGatewayProxyRequest {
 		Body:       string(requestBodyBytes),
 	}
 }
-
-// stubDb creates a mock DBer,
-// with stub/no-op mocks for each method used by the create lease controller
-func stubDb() *mockDB.DBer {
-	dbMock := &mockDB.DBer{}
-
-	// Return a ready account
-	dbMock.On("GetReadyAccount").
-		Return(&db.Account{ID: "123456789012"}, nil)
-
-	// Requesting principal has no leases in the DB
-	dbMock.On("FindLeasesByPrincipal", mock.Anything).Return(nil, nil)
-
-	// Should upsert the lease DB record
-	dbMock.On("UpsertLease", mock.Anything).
-		// Return the same lease object that was passed to this method
-		Return(func(lease db.Lease) *db.Lease {
-			return &lease
-		}, nil)
-
-	// Transitions account to status=Leased
-	dbMock.On("TransitionAccountStatus", mock.Anything, mock.Anything, mock.Anything).
-		Return(&db.Account{}, nil)
-
-	return dbMock
-}
@@ -36,7 +36,7 @@ func DeleteLeaseByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	api.WriteAPIResponse(w, http.StatusOK, deletedLease)
+	api.WriteAPIResponse(w, http.StatusOK, deletedLease.Redacted())
 }
 
 // DeleteLease - Deletes the given lease
@@ -96,5 +96,5 @@ func DeleteLease(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	api.WriteAPIResponse(w, http.StatusOK, deletedLease)
+	api.WriteAPIResponse(w, http.StatusOK, deletedLease.Redacted())
 }
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"html/template"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/Optum/dce/pkg/api"
+	"github.com/Optum/dce/pkg/api/response"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/lease"
+	"github.com/gorilla/mux"
+)
+
+var extensionConfirmationPage = template.Must(template.New("confirm").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Extend lease</title></head>
+<body>
+<p>Extend lease {{.LeaseID}} by {{.Period}}?</p>
+<form method="POST" action="extend">
+<input type="hidden" name="token" value="{{.Token}}">
+<button type="submit">Extend lease</button>
+</form>
+</body>
+</html>
+`))
+
+// ConfirmExtendLeaseByID - Shows a confirmation page for the one-click link
+// in a lease expiry reminder. Links may be opened by email scanners, so the
+// page POSTs the token back to ExtendLeaseByID instead of extending the lease.
+func ConfirmExtendLeaseByID(w http.ResponseWriter, r *http.Request) {
+	leaseID := mux.Vars(r)["leaseID"]
+	token := r.URL.Query().Get("token")
+
+	if _, ok := getExtendableLease(w, leaseID, token); !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	err := extensionConfirmationPage.Execute(w, struct {
+		LeaseID string
+		Period  time.Duration
+		Token   string
+	}{
+		LeaseID: leaseID,
+		Period:  Settings.LeaseExtensionPeriod,
+		Token:   token,
+	})
+	if err != nil {
+		log.Printf("Failed to render the lease extension page for %s: %s", leaseID, err)
+	}
+}
+
+// ExtendLeaseByID - Extends an Active lease by LEASE_EXTENSION_PERIOD, from the
+// confirmation page for a lease expiry reminder.
+// The request is not signed, so it is authorized by the token in the form.
+func ExtendLeaseByID(w http.ResponseWriter, r *http.Request) {
+	leaseID := mux.Vars(r)["leaseID"]
+	token := r.PostFormValue("token")
+
+	existingLease, ok := getExtendableLease(w, leaseID, token)
+	if !ok {
+		return
+	}
+
+	expiresOn := time.Unix(*existingLease.ExpiresOn, 0).Add(Settings.LeaseExtensionPeriod).Unix()
+	updateLease := &lease.Lease{
+		ExpiresOn: &expiresOn,
+	}
+
+	c := leaseValidationContext{
+		maxLeaseBudgetAmount:     maxLeaseBudgetAmount,
+		maxLeasePeriod:           maxLeasePeriod,
+		defaultLeaseLengthInDays: defaultLeaseLengthInDays,
+		principalBudgetPeriod:    principalBudgetPeriod,
+		principalBudgetAmount:    principalBudgetAmount,
+		exchangeRates:            exchangeRates,
+	}
+
+	isValid, validationErrorMessage, err := validateLeaseUpdate(&c, existingLease, updateLease)
+	if err != nil {
+		response.WriteServerErrorWithResponse(w, err.Error())
+		return
+	}
+
+	if !isValid {
+		response.WriteRequestValidationError(w, validationErrorMessage)
+		return
+	}
+
+	updatedLease, err := Services.LeaseService().Update(leaseID, updateLease)
+	if err != nil {
+		api.WriteAPIErrorResponse(w, err)
+		return
+	}
+
+	api.WriteAPIResponse(w, http.StatusOK, updatedLease.Redacted())
+}
+
+// getExtendableLease gets the lease and checks the extension token, writing
+// an error response if either fails.
+// Tokens are only valid for the lease's current expiresOn date, and expire
+// with it, so each link may only be used once.
+func getExtendableLease(w http.ResponseWriter, leaseID string, token string) (*lease.Lease, bool) {
+	existingLease, err := Services.LeaseService().Get(leaseID)
+	if err != nil {
+		api.WriteAPIErrorResponse(w, err)
+		return nil, false
+	}
+
+	if !lease.IsValidExtensionToken(Settings.LeaseExtensionSecret, existingLease, token, time.Now()) {
+		api.WriteAPIErrorResponse(w,
+			errors.NewUnathorizedError("Lease extension link is invalid, has expired, or has already been used"))
+		return nil, false
+	}
+
+	return existingLease, true
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Optum/dce/pkg/api"
+	apiMocks "github.com/Optum/dce/pkg/api/mocks"
+	policyMocks "github.com/Optum/dce/pkg/budgetpolicy/budgetpolicyiface/mocks"
+	"github.com/Optum/dce/pkg/config"
+	"github.com/Optum/dce/pkg/lease"
+	"github.com/Optum/dce/pkg/lease/leaseiface/mocks"
+	"github.com/Optum/dce/pkg/usage"
+	mockUsage "github.com/Optum/dce/pkg/usage/mocks"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestExtendLeaseByID(t *testing.T) {
+
+	type response struct {
+		StatusCode int
+		Body       string
+	}
+
+	expiresOn := time.Now().Add(time.Hour).Unix()
+	extendedOn := time.Unix(expiresOn, 0).Add(24 * time.Hour).Unix()
+	existingLease := &lease.Lease{
+		ID:          ptrString("abc123"),
+		PrincipalID: ptrString("user1"),
+		ExpiresOn:   &expiresOn,
+	}
+	validToken, err := lease.ExtensionToken("secret", existingLease, time.Unix(expiresOn, 0))
+	assert.Nil(t, err)
+	expiredToken, err := lease.ExtensionToken("secret", existingLease, time.Now().Add(-time.Minute))
+	assert.Nil(t, err)
+
+	tests := []struct {
+		name           string
+		method         string
+		token          string
+		principalSpend float64
+		expResp        response
+		expUpdate      bool
+	}{
+		{
+			name:   "When the link is opened it shows a confirmation page",
+			method: http.MethodGet,
+			token:  validToken,
+			expResp: response{
+				StatusCode: 200,
+				Body:       "<input type=\"hidden\" name=\"token\" value=\"" + validToken + "\">",
+			},
+		},
+		{
+			name:   "When the link is opened with an invalid token",
+			method: http.MethodGet,
+			token:  "invalid",
+			expResp: response{
+				StatusCode: 401,
+				Body:       "{\"error\":{\"message\":\"Lease extension link is invalid, has expired, or has already been used\",\"code\":\"UnauthorizedError\"}}\n",
+			},
+		},
+		{
+			name:   "When the link is valid",
+			method: http.MethodPost,
+			token:  validToken,
+			expResp: response{
+				StatusCode: 200,
+				Body:       "{\"principalId\":\"user1\",\"id\":\"abc123\",\"expiresOn\":" + jsonInt(extendedOn) + "}\n",
+			},
+			expUpdate: true,
+		},
+		{
+			name:   "When the link is invalid or was already used",
+			method: http.MethodPost,
+			token:  "invalid",
+			expResp: response{
+				StatusCode: 401,
+				Body:       "{\"error\":{\"message\":\"Lease extension link is invalid, has expired, or has already been used\",\"code\":\"UnauthorizedError\"}}\n",
+			},
+		},
+		{
+			name:   "When the link has expired",
+			method: http.MethodPost,
+			token:  expiredToken,
+			expResp: response{
+				StatusCode: 401,
+				Body:       "{\"error\":{\"message\":\"Lease extension link is invalid, has expired, or has already been used\",\"code\":\"UnauthorizedError\"}}\n",
+			},
+		},
+		{
+			name:           "When the principal is over their principal budget",
+			method:         http.MethodPost,
+			token:          validToken,
+			principalSpend: 1500,
+			expResp: response{
+				StatusCode: 400,
+				Body:       "{\"error\":{\"code\":\"RequestValidationError\",\"message\":\"Unable to create lease: User principal user1 has already spent 1500.00 of their 1000.00 principal budget\"}}",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfgBldr := &config.ConfigurationBuilder{}
+			svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+
+			Settings.LeaseExtensionSecret = "secret"
+			Settings.LeaseExtensionPeriod = 24 * time.Hour
+			principalBudgetAmount = 1000
+			principalBudgetPeriod = Weekly
+			maxLeaseBudgetAmount = 1000
+			maxLeasePeriod = 704800
+
+			usageMock := &mockUsage.DBer{}
+			usageMock.On("GetUsageByPrincipal", mock.Anything, mock.Anything).Return(
+				[]*usage.Usage{
+					{CostAmount: aws.Float64(tt.principalSpend)},
+				}, nil,
+			)
+			usageSvc = usageMock
+
+			leaseSvc := mocks.Servicer{}
+			leaseSvc.On("Get", "abc123").Return(existingLease, nil)
+			leaseSvc.On("Update", "abc123", &lease.Lease{ExpiresOn: &extendedOn}).Return(
+				&lease.Lease{
+					ID:          ptrString("abc123"),
+					PrincipalID: ptrString("user1"),
+					ExpiresOn:   &extendedOn,
+				}, nil,
+			)
+			policySvc := policyMocks.Servicer{}
+			policySvc.On("Match", mock.Anything).Return(nil, nil)
+			// Extension links aren't signed, so the user is not used
+			userDetailSvc := apiMocks.UserDetailer{}
+			userDetailSvc.On("GetUser", mock.Anything).Return(&api.User{})
+			svcBldr.Config.WithService(&userDetailSvc)
+			svcBldr.Config.WithService(&leaseSvc)
+			svcBldr.Config.WithService(&policySvc)
+			_, err := svcBldr.Build()
+
+			assert.Nil(t, err)
+			if err == nil {
+				Services = svcBldr
+			}
+
+			mockRequest := events.APIGatewayProxyRequest{
+				HTTPMethod: tt.method,
+				Path:       "/leases/abc123/extend",
+			}
+			if tt.method == http.MethodGet {
+				mockRequest.QueryStringParameters = map[string]string{
+					"token": tt.token,
+				}
+			} else {
+				mockRequest.Headers = map[string]string{
+					"Content-Type": "application/x-www-form-urlencoded",
+				}
+				mockRequest.Body = url.Values{"token": {tt.token}}.Encode()
+			}
+			actualResponse, err := Handler(context.TODO(), mockRequest)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expResp.StatusCode, actualResponse.StatusCode)
+			if tt.method == http.MethodGet && tt.expResp.StatusCode == http.StatusOK {
+				assert.Contains(t, actualResponse.Body, tt.expResp.Body)
+				assert.Equal(t, "text/html; charset=utf-8", actualResponse.MultiValueHeaders["Content-Type"][0])
+			} else {
+				assert.Equal(t, tt.expResp.Body, actualResponse.Body)
+			}
+			if tt.expUpdate {
+				leaseSvc.AssertCalled(t, "Update", "abc123", &lease.Lease{ExpiresOn: &extendedOn})
+			} else {
+				leaseSvc.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
@@ -28,5 +28,5 @@ func GetLeaseByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	api.WriteAPIResponse(w, http.StatusOK, lease)
+	api.WriteAPIResponse(w, http.StatusOK, lease.Redacted())
 }
@@ -43,6 +43,6 @@ func GetLeases(w http.ResponseWriter, r *http.Request) {
 		}
 		w.Header().Add("Link", fmt.Sprintf("<%s>; rel=\"next\"", nextURL.String()))
 	}
-	api.WriteAPIResponse(w, http.StatusOK, leases)
+	api.WriteAPIResponse(w, http.StatusOK, leases.Redacted())
 
 }
@@ -4,15 +4,14 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"time"
 
 	"log"
 
 	"github.com/Optum/dce/pkg/api"
+	"github.com/Optum/dce/pkg/budget"
 	"github.com/Optum/dce/pkg/common"
-	"github.com/Optum/dce/pkg/db"
 	"github.com/Optum/dce/pkg/usage"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/sns"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
@@ -22,16 +21,20 @@ import (
 )
 
 type leaseControllerConfiguration struct {
-	Debug                    string  `env:"DEBUG" defaultEnv:"false"`
-	LeaseAddedTopicARN       string  `env:"LEASE_ADDED_TOPIC" defaultEnv:"DCEDefaultProvisionTopic"`
-	DecommissionTopicARN     string  `env:"DECOMMISSION_TOPIC" defaultEnv:"DefaultDecommissionTopicArn"`
-	CognitoUserPoolID        string  `env:"COGNITO_USER_POOL_ID" defaultEnv:"DefaultCognitoUserPoolId"`
-	CognitoAdminName         string  `env:"COGNITO_ROLES_ATTRIBUTE_ADMIN_NAME" defaultEnv:"DefaultCognitoAdminName"`
-	PrincipalBudgetAmount    float64 `env:"PRINCIPAL_BUDGET_AMOUNT" defaultEnv:"1000.00"`
-	PrincipalBudgetPeriod    string  `env:"PRINCIPAL_BUDGET_PERIOD" defaultEnv:"Weekly"`
-	MaxLeaseBudgetAmount     float64 `env:"MAX_LEASE_BUDGET_AMOUNT" defaultEnv:"1000.00"`
-	MaxLeasePeriod           int64   `env:"MAX_LEASE_PERIOD" defaultEnv:"704800"`
-	DefaultLeaseLengthInDays int     `env:"DEFAULT_LEASE_LENGTH_IN_DAYS" defaultEnv:"7"`
+	Debug                          string        `env:"DEBUG" defaultEnv:"false"`
+	LeaseAddedTopicARN             string        `env:"LEASE_ADDED_TOPIC" defaultEnv:"DCEDefaultProvisionTopic"`
+	DecommissionTopicARN           string        `env:"DECOMMISSION_TOPIC" defaultEnv:"DefaultDecommissionTopicArn"`
+	CognitoUserPoolID              string        `env:"COGNITO_USER_POOL_ID" defaultEnv:"DefaultCognitoUserPoolId"`
+	CognitoAdminName               string        `env:"COGNITO_ROLES_ATTRIBUTE_ADMIN_NAME" defaultEnv:"DefaultCognitoAdminName"`
+	PrincipalBudgetAmount          float64       `env:"PRINCIPAL_BUDGET_AMOUNT" defaultEnv:"1000.00"`
+	PrincipalBudgetPeriod          string        `env:"PRINCIPAL_BUDGET_PERIOD" defaultEnv:"Weekly"`
+	MaxLeaseBudgetAmount           float64       `env:"MAX_LEASE_BUDGET_AMOUNT" defaultEnv:"1000.00"`
+	MaxLeasePeriod                 int64         `env:"MAX_LEASE_PERIOD" defaultEnv:"704800"`
+	DefaultLeaseLengthInDays       int           `env:"DEFAULT_LEASE_LENGTH_IN_DAYS" defaultEnv:"7"`
+	MaxActiveLeasesPerPrincipal    int           `env:"MAX_ACTIVE_LEASES_PER_PRINCIPAL" defaultEnv:"1"`
+	LeaseExtensionSecret           string        // Loaded from the SSM parameter named by LEASE_EXTENSION_SECRET_PARAMETER
+	LeaseExtensionPeriod           time.Duration `env:"LEASE_EXTENSION_PERIOD" envDefault:"24h"`
+	NotificationTargetAllowedHosts []string      `env:"NOTIFICATION_TARGET_ALLOWED_HOSTS" envDefault:""`
 }
 
 const (
@@ -49,31 +52,22 @@ var (
 
 var (
 	// Soon to be deprecated - Legacy support
-	Config             common.DefaultEnvConfig
-	awsSession         *session.Session
-	dao                db.DBer
-	snsSvc             common.Notificationer
-	usageSvc           usage.DBer
-	leaseAddedTopicARN string
+	Config   common.DefaultEnvConfig
+	usageSvc usage.DBer
 	//decommissionTopicARN     string
-	principalBudgetAmount    float64
-	principalBudgetPeriod    string
-	maxLeaseBudgetAmount     float64
-	maxLeasePeriod           int64
-	defaultLeaseLengthInDays int
-	baseRequest              url.URL
+	principalBudgetAmount       float64
+	principalBudgetPeriod       string
+	maxLeaseBudgetAmount        float64
+	maxLeasePeriod              int64
+	defaultLeaseLengthInDays    int
+	maxActiveLeasesPerPrincipal int
+	exchangeRates               budget.ExchangeRates
+	baseRequest                 url.URL
 	//cognitoUserPoolId        string
 	//cognitoAdminName         string
 	userDetailsMiddleware api.UserDetailsMiddleware
 )
 
-// messageBody is the structured object of the JSON Message to send
-// to an SNS Topic for lease creation/destruction
-type messageBody struct {
-	Default string `json:"default"`
-	Body    string `json:"Body"`
-}
-
 func init() {
 	initConfig()
 	log.Println("Cold start; creating router for /leases")
@@ -93,6 +87,27 @@ func init() {
 			api.EmptyQueryString,
 			GetLeaseByID,
 		},
+		api.Route{
+			"UpdateLeaseByID",
+			"PATCH",
+			"/leases/{leaseID}",
+			api.EmptyQueryString,
+			UpdateLeaseByID,
+		},
+		api.Route{
+			"ConfirmExtendLeaseByID",
+			"GET",
+			"/leases/{leaseID}/extend",
+			[]string{"token"},
+			ConfirmExtendLeaseByID,
+		},
+		api.Route{
+			"ExtendLeaseByID",
+			"POST",
+			"/leases/{leaseID}/extend",
+			api.EmptyQueryString,
+			ExtendLeaseByID,
+		},
 		api.Route{
 			"DeleteLeaseByID",
 			"DELETE",
@@ -135,19 +150,27 @@ func initConfig() {
 	if err != nil {
 		log.Printf("Error: %+v", err)
 	}
+
+	cfgBldr.WithParameterStoreEnv("LEASE_EXTENSION_SECRET", "LEASE_EXTENSION_SECRET_PARAMETER", "")
 	svcBldr := &config.ServiceBuilder{Config: cfgBldr}
 
 	_, err = svcBldr.
+		WithSSM().
 		WithLeaseService().
 		WithUserDetailer().
+		WithBudgetPolicyService().
 		Build()
 	if err != nil {
 		panic(err)
 	}
 
+	Settings.LeaseExtensionSecret, err = cfgBldr.GetStringVal("LEASE_EXTENSION_SECRET")
+	if err != nil {
+		log.Fatalf("Could not load the lease extension secret: %s", err)
+	}
+
 	Services = svcBldr
 
-	leaseAddedTopicARN = Config.GetEnvVar("LEASE_ADDED_TOPIC", "DCEDefaultProvisionTopic")
 	//decommissionTopicARN = Config.GetEnvVar("DECOMMISSION_TOPIC", "DefaultDecommissionTopicArn")
 	//cognitoUserPoolId = Config.GetEnvVar("COGNITO_USER_POOL_ID", "DefaultCognitoUserPoolId")
 	//cognitoAdminName = Config.GetEnvVar("COGNITO_ROLES_ATTRIBUTE_ADMIN_NAME", "DefaultCognitoAdminName")
@@ -156,6 +179,11 @@ func initConfig() {
 	maxLeaseBudgetAmount = Config.GetEnvFloatVar("MAX_LEASE_BUDGET_AMOUNT", 1000.00)
 	maxLeasePeriod = int64(Config.GetEnvIntVar("MAX_LEASE_PERIOD", 704800))
 	defaultLeaseLengthInDays = Config.GetEnvIntVar("DEFAULT_LEASE_LENGTH_IN_DAYS", 7)
+	maxActiveLeasesPerPrincipal = Config.GetEnvIntVar("MAX_ACTIVE_LEASES_PER_PRINCIPAL", 1)
+	exchangeRates, err = budget.NewExchangeRatesFromEnv()
+	if err != nil {
+		log.Fatalf("Could not load exchange rates: %s", err.Error())
+	}
 }
 
 // Handler - Handle the lambda function
@@ -175,11 +203,6 @@ func Handler(_ context.Context, req events.APIGatewayProxyRequest) (events.APIGa
 
 func main() {
 
-	awsSession = newAWSSession()
-	// Create the Database Service from the environment
-	dao = newDBer()
-	snsSvc = &common.SNS{Client: sns.New(awsSession)}
-
 	usageService, err := usage.NewFromEnv()
 	if err != nil {
 		errorMessage := fmt.Sprintf("Failed to initialize usage service: %s", err)
@@ -190,22 +213,3 @@ func main() {
 
 	lambda.Start(Handler)
 }
-
-func newDBer() db.DBer {
-	dao, err := db.NewFromEnv()
-	if err != nil {
-		errorMessage := fmt.Sprintf("Failed to initialize database: %s", err)
-		log.Fatal(errorMessage)
-	}
-
-	return dao
-}
-
-func newAWSSession() *session.Session {
-	awsSession, err := session.NewSession()
-	if err != nil {
-		errorMessage := fmt.Sprintf("Failed to create AWS session: %s", err)
-		log.Fatal(errorMessage)
-	}
-	return awsSession
-}
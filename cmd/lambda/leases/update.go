@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Optum/dce/pkg/api"
+	"github.com/Optum/dce/pkg/api/response"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/lease"
+	"github.com/gorilla/mux"
+)
+
+// UpdateLeaseByID - Extends the expiration and/or budget of an Active lease
+func UpdateLeaseByID(w http.ResponseWriter, r *http.Request) {
+	leaseID := mux.Vars(r)["leaseID"]
+
+	// Deserialize the request JSON as an request object
+	updateLease := &lease.Lease{}
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(updateLease)
+	if err != nil {
+		api.WriteAPIErrorResponse(w,
+			errors.NewBadRequest("invalid request parameters"))
+		return
+	}
+
+	existingLease, err := Services.LeaseService().Get(leaseID)
+	if err != nil {
+		api.WriteAPIErrorResponse(w, err)
+		return
+	}
+
+	// If user is not an admin, they can't update leases for other users
+	user := r.Context().Value(api.User{}).(*api.User)
+	err = user.Authorize(*existingLease.PrincipalID)
+	if err != nil {
+		api.WriteAPIErrorResponse(w, err)
+		return
+	}
+
+	c := leaseValidationContext{
+		maxLeaseBudgetAmount:           maxLeaseBudgetAmount,
+		maxLeasePeriod:                 maxLeasePeriod,
+		defaultLeaseLengthInDays:       defaultLeaseLengthInDays,
+		principalBudgetPeriod:          principalBudgetPeriod,
+		principalBudgetAmount:          principalBudgetAmount,
+		exchangeRates:                  exchangeRates,
+		notificationTargetAllowedHosts: Settings.NotificationTargetAllowedHosts,
+	}
+
+	isValid, validationErrorMessage, err := validateLeaseUpdate(&c, existingLease, updateLease)
+	if err != nil {
+		response.WriteServerErrorWithResponse(w, err.Error())
+		return
+	}
+
+	if !isValid {
+		response.WriteRequestValidationError(w, validationErrorMessage)
+		return
+	}
+
+	updatedLease, err := Services.LeaseService().Update(leaseID, updateLease)
+	if err != nil {
+		api.WriteAPIErrorResponse(w, err)
+		return
+	}
+
+	api.WriteAPIResponse(w, http.StatusOK, updatedLease.Redacted())
+}
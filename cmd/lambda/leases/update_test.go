@@ -0,0 +1,366 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Optum/dce/pkg/api"
+	apiMocks "github.com/Optum/dce/pkg/api/mocks"
+	"github.com/Optum/dce/pkg/budgetpolicy"
+	policyMocks "github.com/Optum/dce/pkg/budgetpolicy/budgetpolicyiface/mocks"
+	"github.com/Optum/dce/pkg/config"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/lease"
+	"github.com/Optum/dce/pkg/lease/leaseiface/mocks"
+	"github.com/Optum/dce/pkg/usage"
+	mockUsage "github.com/Optum/dce/pkg/usage/mocks"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUpdateLeaseByID(t *testing.T) {
+
+	type response struct {
+		StatusCode int
+		Body       string
+	}
+
+	tomorrow := time.Now().AddDate(0, 0, 1).Unix()
+	inFourDays := time.Now().AddDate(0, 0, 4).Unix()
+	fiveDaysAgo := time.Now().AddDate(0, 0, -5).Unix()
+	nextYear := time.Now().AddDate(1, 0, 0).Unix()
+
+	tests := []struct {
+		name           string
+		user           *api.User
+		leaseID        string
+		reqBody        map[string]interface{}
+		principalSpend float64
+		policy         *budgetpolicy.BudgetPolicy
+		getLease       *lease.Lease
+		retLease       *lease.Lease
+		retErr         error
+		expResp        response
+		expUpdate      bool
+	}{
+		{
+			name: "When user extends their own lease",
+			user: &api.User{
+				Username: "user1",
+				Role:     api.UserGroupName,
+			},
+			leaseID: "abc123",
+			reqBody: map[string]interface{}{
+				"expiresOn":    tomorrow,
+				"budgetAmount": 200,
+			},
+			getLease: &lease.Lease{
+				PrincipalID: ptrString("user1"),
+			},
+			retLease: &lease.Lease{
+				PrincipalID:  ptrString("user1"),
+				ExpiresOn:    &tomorrow,
+				BudgetAmount: aws.Float64(200),
+			},
+			expResp: response{
+				StatusCode: 200,
+				Body:       "{\"principalId\":\"user1\",\"budgetAmount\":200,\"expiresOn\":" + jsonInt(tomorrow) + "}\n",
+			},
+			expUpdate: true,
+		},
+		{
+			name: "When user extends a lease for other user",
+			user: &api.User{
+				Username: "user1",
+				Role:     api.UserGroupName,
+			},
+			leaseID: "abc123",
+			reqBody: map[string]interface{}{
+				"expiresOn": tomorrow,
+			},
+			getLease: &lease.Lease{
+				PrincipalID: ptrString("user2"),
+			},
+			expResp: response{
+				StatusCode: 401,
+				Body:       "{\"error\":{\"message\":\"User [user1] with role: [User] attempted to act on a lease for [user2], but was not authorized\",\"code\":\"UnauthorizedError\"}}\n",
+			},
+		},
+		{
+			name: "When the budget amount is over the max lease budget",
+			user: &api.User{
+				Username: "admin1",
+				Role:     api.AdminGroupName,
+			},
+			leaseID: "abc123",
+			reqBody: map[string]interface{}{
+				"budgetAmount": 5000,
+			},
+			getLease: &lease.Lease{
+				PrincipalID: ptrString("user1"),
+			},
+			expResp: response{
+				StatusCode: 400,
+				Body:       "{\"error\":{\"code\":\"RequestValidationError\",\"message\":\"Requested lease has a budget amount of 5000.000000, which is greater than max lease budget amount of 1000.000000\"}}",
+			},
+		},
+		{
+			name: "When the expiration is over the max lease period",
+			user: &api.User{
+				Username: "admin1",
+				Role:     api.AdminGroupName,
+			},
+			leaseID: "abc123",
+			reqBody: map[string]interface{}{
+				"expiresOn": nextYear,
+			},
+			getLease: &lease.Lease{
+				PrincipalID: ptrString("user1"),
+			},
+			expResp: response{
+				StatusCode: 400,
+			},
+		},
+		{
+			name: "When the expiration is over the max lease period from the lease creation",
+			user: &api.User{
+				Username: "admin1",
+				Role:     api.AdminGroupName,
+			},
+			leaseID: "abc123",
+			reqBody: map[string]interface{}{
+				"expiresOn": inFourDays,
+			},
+			getLease: &lease.Lease{
+				PrincipalID: ptrString("user1"),
+				CreatedOn:   &fiveDaysAgo,
+			},
+			expResp: response{
+				StatusCode: 400,
+			},
+		},
+		{
+			name: "When raising the lease budget is over the principal budget",
+			user: &api.User{
+				Username: "admin1",
+				Role:     api.AdminGroupName,
+			},
+			leaseID: "abc123",
+			reqBody: map[string]interface{}{
+				"budgetAmount": 700,
+			},
+			principalSpend: 500,
+			getLease: &lease.Lease{
+				PrincipalID:  ptrString("user1"),
+				BudgetAmount: aws.Float64(100),
+			},
+			expResp: response{
+				StatusCode: 400,
+				Body:       "{\"error\":{\"code\":\"RequestValidationError\",\"message\":\"Unable to update lease: User principal user1 has already spent 500.00 of their 1000.00 principal budget, and can't raise the lease budget by 600.00\"}}",
+			},
+		},
+		{
+			name: "When the principal is over their principal budget",
+			user: &api.User{
+				Username: "admin1",
+				Role:     api.AdminGroupName,
+			},
+			leaseID: "abc123",
+			reqBody: map[string]interface{}{
+				"expiresOn": tomorrow,
+			},
+			principalSpend: 1500,
+			getLease: &lease.Lease{
+				PrincipalID: ptrString("user1"),
+			},
+			expResp: response{
+				StatusCode: 400,
+				Body:       "{\"error\":{\"code\":\"RequestValidationError\",\"message\":\"Unable to create lease: User principal user1 has already spent 1500.00 of their 1000.00 principal budget\"}}",
+			},
+		},
+		{
+			name: "When a budget policy allows a larger lease budget",
+			user: &api.User{
+				Username: "admin1",
+				Role:     api.AdminGroupName,
+			},
+			leaseID: "abc123",
+			reqBody: map[string]interface{}{
+				"budgetAmount": 5000,
+			},
+			policy: &budgetpolicy.BudgetPolicy{
+				Group:                 ptrString("SRE"),
+				MaxLeaseBudgetAmount:  aws.Float64(10000),
+				PrincipalBudgetAmount: aws.Float64(10000),
+			},
+			getLease: &lease.Lease{
+				PrincipalID: ptrString("user1"),
+			},
+			retLease: &lease.Lease{
+				PrincipalID:  ptrString("user1"),
+				BudgetAmount: aws.Float64(5000),
+			},
+			expResp: response{
+				StatusCode: 200,
+				Body:       "{\"principalId\":\"user1\",\"budgetAmount\":5000}\n",
+			},
+			expUpdate: true,
+		},
+		{
+			name: "When a budget policy lowers the principal budget",
+			user: &api.User{
+				Username: "admin1",
+				Role:     api.AdminGroupName,
+			},
+			leaseID: "abc123",
+			reqBody: map[string]interface{}{
+				"expiresOn": tomorrow,
+			},
+			principalSpend: 150,
+			policy: &budgetpolicy.BudgetPolicy{
+				PrincipalID:           ptrString("user1"),
+				PrincipalBudgetAmount: aws.Float64(100),
+			},
+			getLease: &lease.Lease{
+				PrincipalID: ptrString("user1"),
+			},
+			expResp: response{
+				StatusCode: 400,
+				Body:       "{\"error\":{\"code\":\"RequestValidationError\",\"message\":\"Unable to create lease: User principal user1 has already spent 150.00 of their 100.00 principal budget\"}}",
+			},
+		},
+		{
+			name: "When user replaces their notification targets",
+			user: &api.User{
+				Username: "user1",
+				Role:     api.UserGroupName,
+			},
+			leaseID: "abc123",
+			reqBody: map[string]interface{}{
+				"notificationTargets": []string{"slack:https://hooks.slack.com/services/T000/B000/XXXX"},
+			},
+			getLease: &lease.Lease{
+				PrincipalID: ptrString("user1"),
+			},
+			retLease: &lease.Lease{
+				PrincipalID:         ptrString("user1"),
+				NotificationTargets: &[]string{"slack:https://hooks.slack.com/services/T000/B000/XXXX"},
+			},
+			expResp: response{
+				StatusCode: 200,
+				Body:       "{\"principalId\":\"user1\",\"notificationTargets\":[\"slack:https://hooks.slack.com/***\"]}\n",
+			},
+			expUpdate: true,
+		},
+		{
+			name: "When a notification target host isn't allowed",
+			user: &api.User{
+				Username: "user1",
+				Role:     api.UserGroupName,
+			},
+			leaseID: "abc123",
+			reqBody: map[string]interface{}{
+				"notificationTargets": []string{"webhook:https://169.254.169.254/latest/meta-data"},
+			},
+			getLease: &lease.Lease{
+				PrincipalID: ptrString("user1"),
+			},
+			expResp: response{
+				StatusCode: 400,
+				Body:       "{\"error\":{\"code\":\"RequestValidationError\",\"message\":\"Requested lease has an invalid notification target: notification target host \\\"169.254.169.254\\\" is not allowed\"}}",
+			},
+		},
+		{
+			name: "When the update service returns a conflict",
+			user: &api.User{
+				Username: "admin1",
+				Role:     api.AdminGroupName,
+			},
+			leaseID: "abc123",
+			reqBody: map[string]interface{}{
+				"expiresOn": tomorrow,
+			},
+			getLease: &lease.Lease{
+				PrincipalID: ptrString("user1"),
+			},
+			retErr: errors.NewConflict("lease", "abc123", fmt.Errorf("leaseStatus: must be active lease.")), //nolint golint
+			expResp: response{
+				StatusCode: 409,
+				Body:       "{\"error\":{\"message\":\"operation cannot be fulfilled on lease \\\"abc123\\\": leaseStatus: must be active lease.\",\"code\":\"ConflictError\"}}\n",
+			},
+			expUpdate: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfgBldr := &config.ConfigurationBuilder{}
+			svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+
+			principalBudgetAmount = 1000
+			principalBudgetPeriod = Weekly
+			maxLeaseBudgetAmount = 1000
+			maxLeasePeriod = 704800
+			Settings.NotificationTargetAllowedHosts = []string{"hooks.slack.com"}
+
+			usageMock := &mockUsage.DBer{}
+			usageMock.On("GetUsageByPrincipal", mock.Anything, mock.Anything).Return(
+				[]*usage.Usage{
+					{CostAmount: aws.Float64(tt.principalSpend)},
+				}, nil,
+			)
+			usageSvc = usageMock
+
+			leaseSvc := mocks.Servicer{}
+			leaseSvc.On("Get", tt.leaseID).Return(tt.getLease, nil)
+			leaseSvc.On("Update", tt.leaseID, mock.AnythingOfType("*lease.Lease")).Return(
+				tt.retLease, tt.retErr,
+			)
+			policySvc := policyMocks.Servicer{}
+			policySvc.On("Match", mock.Anything).Return(tt.policy, nil)
+			userDetailSvc := apiMocks.UserDetailer{}
+			userDetailSvc.On("GetUser", mock.Anything).Return(tt.user)
+			svcBldr.Config.WithService(&userDetailSvc)
+			svcBldr.Config.WithService(&leaseSvc)
+			svcBldr.Config.WithService(&policySvc)
+			_, err := svcBldr.Build()
+
+			assert.Nil(t, err)
+			if err == nil {
+				Services = svcBldr
+			}
+
+			body, err := json.Marshal(tt.reqBody)
+			assert.Nil(t, err)
+
+			mockRequest := events.APIGatewayProxyRequest{
+				HTTPMethod: http.MethodPatch,
+				Path:       "/leases/" + tt.leaseID,
+				Body:       string(body),
+			}
+			actualResponse, err := Handler(context.TODO(), mockRequest)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expResp.StatusCode, actualResponse.StatusCode)
+			if tt.expResp.Body != "" {
+				assert.Equal(t, tt.expResp.Body, actualResponse.Body)
+			}
+			if tt.expUpdate {
+				leaseSvc.AssertCalled(t, "Update", tt.leaseID, mock.AnythingOfType("*lease.Lease"))
+			} else {
+				leaseSvc.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func jsonInt(i int64) string {
+	b, _ := json.Marshal(i)
+	return string(b)
+}
@@ -6,7 +6,12 @@ import (
 	"fmt"
 	"math"
 	"net/http"
+	"strings"
 	"time"
+
+	"github.com/Optum/dce/pkg/budget"
+	"github.com/Optum/dce/pkg/lease"
+	"github.com/Optum/dce/pkg/notification"
 )
 
 type leaseValidationContext struct {
@@ -15,6 +20,9 @@ type leaseValidationContext struct {
 	maxLeasePeriod           int64
 	principalBudgetPeriod    string
 	defaultLeaseLengthInDays int
+	exchangeRates            budget.ExchangeRates
+	// Hosts notification target URLs may post to
+	notificationTargetAllowedHosts []string
 }
 
 // ValidateLease validates lease budget amount and period
@@ -32,9 +40,37 @@ func validateLeaseFromRequest(context *leaseValidationContext, req *http.Request
 		return requestBody, false, validationErrStr, nil
 	}
 
+	err = applyBudgetPolicy(context, requestBody.PrincipalID)
+	if err != nil {
+		return requestBody, true, "", err
+	}
+
+	// Budgets are in USD, unless another currency is requested
+	requestBody.BudgetCurrency = strings.ToUpper(requestBody.BudgetCurrency)
+	if requestBody.BudgetCurrency == "" {
+		requestBody.BudgetCurrency = budget.DefaultCurrency
+	}
+	// Budget limits are in USD
+	budgetAmount, err := context.exchangeRates.Convert(requestBody.BudgetAmount, requestBody.BudgetCurrency, budget.DefaultCurrency)
+	if err != nil {
+		validationErrStr := fmt.Sprintf("Requested lease has an invalid budget currency: %s", err)
+		return requestBody, false, validationErrStr, nil
+	}
+
+	// Leases start now, unless they are scheduled for later
+	startTime := time.Now()
+	if requestBody.StartsOn != 0 {
+		// Validate requested lease start date is greater than today
+		if requestBody.StartsOn <= startTime.Unix() {
+			validationErrStr := fmt.Sprintf("Requested lease has a desired start date less than today: %d", requestBody.StartsOn)
+			return requestBody, false, validationErrStr, nil
+		}
+		startTime = time.Unix(requestBody.StartsOn, 0)
+	}
+
 	// Set default expiresOn
 	if requestBody.ExpiresOn == 0 {
-		requestBody.ExpiresOn = time.Now().AddDate(0, 0, context.defaultLeaseLengthInDays).Unix()
+		requestBody.ExpiresOn = startTime.AddDate(0, 0, context.defaultLeaseLengthInDays).Unix()
 	}
 
 	// Set default metadata (empty object)
@@ -48,27 +84,146 @@ func validateLeaseFromRequest(context *leaseValidationContext, req *http.Request
 		return requestBody, false, validationErrStr, nil
 	}
 
+	// Validate requested lease end date is after its start date
+	if requestBody.StartsOn != 0 && requestBody.ExpiresOn <= requestBody.StartsOn {
+		validationErrStr := fmt.Sprintf("Requested lease has a desired expiry date less than its start date: %d", requestBody.ExpiresOn)
+		return requestBody, false, validationErrStr, nil
+	}
+
+	// Validate requested notification targets, eg. "slack:https://hooks.slack.com/..."
+	isValid, validationErrStr := validateNotificationTargets(context, requestBody.NotificationTargets)
+	if !isValid {
+		return requestBody, false, validationErrStr, nil
+	}
+
 	// Validate requested lease budget amount is less than MAX_LEASE_BUDGET_AMOUNT
-	if requestBody.BudgetAmount > context.maxLeaseBudgetAmount {
-		validationErrStr := fmt.Sprintf("Requested lease has a budget amount of %f, which is greater than max lease budget amount of %f", math.Round(requestBody.BudgetAmount), math.Round(context.maxLeaseBudgetAmount))
+	if budgetAmount > context.maxLeaseBudgetAmount {
+		validationErrStr := fmt.Sprintf("Requested lease has a budget amount of %f, which is greater than max lease budget amount of %f", math.Round(budgetAmount), math.Round(context.maxLeaseBudgetAmount))
 		return requestBody, false, validationErrStr, nil
 	}
 
 	// Validate requested lease budget period is less than MAX_LEASE_BUDGET_PERIOD
-	currentTime := time.Now()
-	maxLeaseExpiresOn := currentTime.Add(time.Second * time.Duration(context.maxLeasePeriod))
+	maxLeaseExpiresOn := startTime.Add(time.Second * time.Duration(context.maxLeasePeriod))
 	if requestBody.ExpiresOn > maxLeaseExpiresOn.Unix() {
 		validationErrStr := fmt.Sprintf("Requested lease has a budget expires on of %d, which is greater than max lease period of %d", requestBody.ExpiresOn, maxLeaseExpiresOn.Unix())
 		return requestBody, false, validationErrStr, nil
 	}
 
 	// Validate requested lease budget amount is less than PRINCIPAL_BUDGET_AMOUNT for current principal billing period
+	isValid, validationErrStr, err = validatePrincipalBudget(context, requestBody.PrincipalID, 0)
+	if err != nil || !isValid {
+		return requestBody, isValid, validationErrStr, err
+	}
+
+	return requestBody, true, "", nil
+}
+
+// validateLeaseUpdate validates a lease extension against the same
+// budget and period limits that are applied on lease creation
+func validateLeaseUpdate(context *leaseValidationContext, existing *lease.Lease, update *lease.Lease) (bool, string, error) {
+
+	err := applyBudgetPolicy(context, *existing.PrincipalID)
+	if err != nil {
+		return true, "", err
+	}
+
+	// Validate requested lease budget amount is less than MAX_LEASE_BUDGET_AMOUNT
+	budgetIncrease := 0.0
+	if update.BudgetAmount != nil {
+		// Budget limits are in USD, while the lease budget may be in another currency
+		budgetCurrency := budget.DefaultCurrency
+		if existing.BudgetCurrency != nil && *existing.BudgetCurrency != "" {
+			budgetCurrency = *existing.BudgetCurrency
+		}
+		existingBudgetAmount := 0.0
+		if existing.BudgetAmount != nil {
+			existingBudgetAmount = *existing.BudgetAmount
+		}
+		budgetAmount, err := context.exchangeRates.Convert(*update.BudgetAmount, budgetCurrency, budget.DefaultCurrency)
+		if err == nil {
+			existingBudgetAmount, err = context.exchangeRates.Convert(existingBudgetAmount, budgetCurrency, budget.DefaultCurrency)
+		}
+		if err != nil {
+			validationErrStr := fmt.Sprintf("Requested lease has an invalid budget currency: %s", err)
+			return false, validationErrStr, nil
+		}
+		if budgetAmount > context.maxLeaseBudgetAmount {
+			validationErrStr := fmt.Sprintf("Requested lease has a budget amount of %f, which is greater than max lease budget amount of %f", math.Round(budgetAmount), math.Round(context.maxLeaseBudgetAmount))
+			return false, validationErrStr, nil
+		}
+		budgetIncrease = math.Max(budgetAmount-existingBudgetAmount, 0)
+	}
+
+	if update.NotificationTargets != nil {
+		isValid, validationErrStr := validateNotificationTargets(context, *update.NotificationTargets)
+		if !isValid {
+			return false, validationErrStr, nil
+		}
+	}
+
+	if update.ExpiresOn != nil {
+		// Validate requested lease end date is greater than today
+		if *update.ExpiresOn <= time.Now().Unix() {
+			validationErrStr := fmt.Sprintf("Requested lease has a desired expiry date less than today: %d", *update.ExpiresOn)
+			return false, validationErrStr, nil
+		}
+
+		// Validate requested lease budget period is less than MAX_LEASE_BUDGET_PERIOD.
+		// The period is measured from the start of the lease, as on creation,
+		// so a lease can't be extended past it.
+		leaseStart := time.Now()
+		if existing.StartsOn != nil {
+			leaseStart = time.Unix(*existing.StartsOn, 0)
+		} else if existing.CreatedOn != nil {
+			leaseStart = time.Unix(*existing.CreatedOn, 0)
+		}
+		maxLeaseExpiresOn := leaseStart.Add(time.Second * time.Duration(context.maxLeasePeriod))
+		if *update.ExpiresOn > maxLeaseExpiresOn.Unix() {
+			validationErrStr := fmt.Sprintf("Requested lease has a budget expires on of %d, which is greater than max lease period of %d", *update.ExpiresOn, maxLeaseExpiresOn.Unix())
+			return false, validationErrStr, nil
+		}
+	}
+
+	return validatePrincipalBudget(context, *existing.PrincipalID, budgetIncrease)
+}
+
+// applyBudgetPolicy overrides the default budget limits with
+// the budget policy of the principal or their group, if there is one
+func applyBudgetPolicy(context *leaseValidationContext, principalID string) error {
+	policy, err := Services.BudgetPolicyService().Match(principalID)
+	if err != nil {
+		errStr := fmt.Sprintf("Failed to retrieve budget policy: %s", err)
+		return errors.New(errStr)
+	}
+	if policy == nil {
+		return nil
+	}
+
+	if policy.MaxLeaseBudgetAmount != nil {
+		context.maxLeaseBudgetAmount = *policy.MaxLeaseBudgetAmount
+	}
+	if policy.MaxLeasePeriod != nil {
+		context.maxLeasePeriod = *policy.MaxLeasePeriod
+	}
+	if policy.PrincipalBudgetAmount != nil {
+		context.principalBudgetAmount = *policy.PrincipalBudgetAmount
+	}
+	if policy.PrincipalBudgetPeriod != nil {
+		context.principalBudgetPeriod = *policy.PrincipalBudgetPeriod
+	}
+	return nil
+}
+
+// validatePrincipalBudget validates the principal has not spent more than
+// PRINCIPAL_BUDGET_AMOUNT for the current principal billing period,
+// and that raising a lease budget by budgetIncrease (USD) keeps them within it
+func validatePrincipalBudget(context *leaseValidationContext, principalID string, budgetIncrease float64) (bool, string, error) {
 	usageStartTime := getBeginningOfCurrentBillingPeriod(context.principalBudgetPeriod)
 
-	usageRecords, err := usageSvc.GetUsageByPrincipal(usageStartTime, requestBody.PrincipalID)
+	usageRecords, err := usageSvc.GetUsageByPrincipal(usageStartTime, principalID)
 	if err != nil {
 		errStr := fmt.Sprintf("Failed to retrieve usage: %s", err)
-		return requestBody, true, "", errors.New(errStr)
+		return true, "", errors.New(errStr)
 	}
 
 	// Group by PrincipalID to get sum of total spent for current billing period
@@ -80,10 +235,30 @@ func validateLeaseFromRequest(context *leaseValidationContext, req *http.Request
 	if spent > context.principalBudgetAmount {
 		validationErrStr := fmt.Sprintf(
 			"Unable to create lease: User principal %s has already spent %.2f of their %.2f principal budget",
-			requestBody.PrincipalID, spent, context.principalBudgetAmount,
+			principalID, spent, context.principalBudgetAmount,
 		)
-		return requestBody, false, validationErrStr, nil
+		return false, validationErrStr, nil
 	}
 
-	return requestBody, true, "", nil
+	if budgetIncrease > 0 && spent+budgetIncrease > context.principalBudgetAmount {
+		validationErrStr := fmt.Sprintf(
+			"Unable to update lease: User principal %s has already spent %.2f of their %.2f principal budget, and can't raise the lease budget by %.2f",
+			principalID, spent, context.principalBudgetAmount, budgetIncrease,
+		)
+		return false, validationErrStr, nil
+	}
+
+	return true, "", nil
+}
+
+// validateNotificationTargets validates the format of notification targets,
+// and that they only post to the allowed hosts
+func validateNotificationTargets(context *leaseValidationContext, targets []string) (bool, string) {
+	for _, target := range targets {
+		_, err := notification.ParseTarget(target, context.notificationTargetAllowedHosts)
+		if err != nil {
+			return false, fmt.Sprintf("Requested lease has an invalid notification target: %s", err)
+		}
+	}
+	return true, ""
 }
@@ -59,6 +59,7 @@ func Handler(cloudWatchEvent events.CloudWatchEvent) error {
 
 			for _, acct := range *accts {
 				// Send Message
+				acct.ResetReason = account.ResetReasonNotReady.ResetReasonPtr()
 				err := services.AccountService().Reset(&acct)
 				if err != nil {
 					errs = append(errs, err)
@@ -10,6 +10,7 @@ import (
 	"github.com/Optum/dce/pkg/account"
 	"github.com/Optum/dce/pkg/config"
 	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/reset"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
@@ -81,30 +82,26 @@ func processMessage(codeBuildSvc codebuildiface.CodeBuildAPI, event events.SQSMe
 
 	log.Printf("Start Account: %s\nMessage ID: %s\n", *acct.ID, event.MessageId)
 
-	buildEnvironmentVars := []*codebuild.EnvironmentVariable{
-		{
-			Name:  aws.String("RESET_ACCOUNT"),
-			Value: acct.ID,
-		},
-		{
-			Name:  aws.String("RESET_ACCOUNT_ADMIN_ROLE_NAME"),
-			Value: acct.AdminRoleArn.IAMResourceName(),
-		},
-		{
-			Name:  aws.String("RESET_ACCOUNT_PRINCIPAL_ROLE_NAME"),
-			Value: acct.PrincipalRoleArn.IAMResourceName(),
-		},
+	buildEnvironmentVars := []*codebuild.EnvironmentVariable{}
+	for _, env := range reset.AccountEnvironment(acct) {
+		buildEnvironmentVars = append(buildEnvironmentVars, &codebuild.EnvironmentVariable{
+			Name:  aws.String(env.Name),
+			Value: aws.String(env.Value),
+		})
 	}
 
 	// Trigger Code Pipeline
 	log.Printf("Triggering Reset Build %s for Account %s\n", settings.BuildName, *acct.ID)
-	_, err := codeBuildSvc.StartBuild(&codebuild.StartBuildInput{
+	output, err := codeBuildSvc.StartBuild(&codebuild.StartBuildInput{
 		EnvironmentVariablesOverride: buildEnvironmentVars,
 		ProjectName:                  aws.String(settings.BuildName),
 	})
 	if err != nil {
 		return errors.NewInternalServer("unexpected error starting code build", err)
 	}
+	if output.Build != nil && output.Build.Id != nil {
+		log.Printf("Started Reset Build %s for Account %s\n", *output.Build.Id, *acct.ID)
+	}
 
 	return nil
 }
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"log"
 
+	"github.com/Optum/dce/pkg/account"
 	"github.com/Optum/dce/pkg/common"
 	"github.com/Optum/dce/pkg/db"
 	errors2 "github.com/Optum/dce/pkg/errors"
@@ -77,6 +78,13 @@ type handleRecordInput struct {
 	resetQueueURL         string
 }
 
+// resetMessage is the account sent to the reset queue,
+// with the reason for the reset
+type resetMessage struct {
+	*db.Account
+	ResetReason account.ResetReason
+}
+
 func handleRecord(input *handleRecordInput) error {
 	record := input.record
 	lease, err := leaseFromImage(record.Change.NewImage)
@@ -131,7 +139,10 @@ func handleRecord(input *handleRecordInput) error {
 			// Put the message on the SQS queue ONLY IF the status has gone
 			// to Inactive.
 			log.Printf("Adding account %s to the reset queue", lease.AccountID)
-			body, err := json.Marshal(acct)
+			body, err := json.Marshal(resetMessage{
+				Account:     acct,
+				ResetReason: account.ResetReasonLeaseEnded,
+			})
 			if err != nil {
 				return err
 			}
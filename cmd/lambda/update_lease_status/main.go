@@ -8,10 +8,13 @@ import (
 
 	"github.com/Optum/dce/pkg/awsiface"
 	"github.com/Optum/dce/pkg/budget"
+	"github.com/Optum/dce/pkg/budgetpolicy/budgetpolicyiface"
 	"github.com/Optum/dce/pkg/common"
+	"github.com/Optum/dce/pkg/config"
 	"github.com/Optum/dce/pkg/db"
 	"github.com/Optum/dce/pkg/email"
 	multierrors "github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/notification"
 	"github.com/Optum/dce/pkg/usage"
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/aws/aws-sdk-go/aws/session"
@@ -22,15 +25,93 @@ import (
 	"github.com/pkg/errors"
 )
 
+const (
+	// forecastActionNone disables budget forecasts
+	forecastActionNone = "NONE"
+	// forecastActionWarn notifies the lease owner when the lease
+	// is forecasted to exceed its budget before it expires
+	forecastActionWarn = "WARN"
+	// forecastActionTerminate ends the lease when it
+	// is forecasted to exceed its budget before it expires
+	forecastActionTerminate = "TERMINATE"
+)
+
 type leaseContext struct {
 	expireDate  int64
 	actualSpend float64
 }
 
 func main() {
-	lambda.Start(func(event interface{}) {
-		log.Printf("Initializing budget check")
+	// Services are configured once per container, rather than for each lease,
+	// so warm invocations reuse them (eg. the reports read by the CUR budget service)
+	log.Printf("Initializing budget check")
+
+	// Configure the DB service
+	dbSvc, err := db.NewFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure DB service %s", err)
+	}
 
+	// Configure the STS Token service
+	awsSession := session.Must(session.NewSession())
+	stsSvc := sts.New(awsSession)
+	tokenSvc := &common.STS{Client: stsSvc}
+
+	// Configure the Budget service, for the configured cost provider
+	budgetSvc, err := budget.NewFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure Budget service %s", err)
+	}
+
+	usageSvc, err := usage.NewFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure Usage service %s", err)
+	}
+
+	exchangeRates, err := budget.NewExchangeRatesFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure exchange rates %s", err)
+	}
+
+	// Configure the Budget Policy service
+	cfgBldr := &config.ConfigurationBuilder{}
+	_ = cfgBldr.
+		WithEnv("AWS_CURRENT_REGION", "AWS_CURRENT_REGION", "us-east-1").
+		Build()
+	svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+	_, err = svcBldr.
+		WithBudgetPolicyService().
+		Build()
+	if err != nil {
+		log.Fatalf("Failed to configure Budget Policy service %s", err)
+	}
+
+	handlerInput := lambdaHandlerInput{
+		dbSvc:                                  dbSvc,
+		awsSession:                             awsSession,
+		tokenSvc:                               tokenSvc,
+		budgetSvc:                              budgetSvc,
+		usageSvc:                               usageSvc,
+		budgetPolicySvc:                        svcBldr.BudgetPolicyService(),
+		exchangeRates:                          exchangeRates,
+		sqsSvc:                                 sqs.New(awsSession),
+		snsSvc:                                 &common.SNS{Client: sns.New(awsSession)},
+		leaseLockedTopicArn:                    common.RequireEnv("LEASE_LOCKED_TOPIC_ARN"),
+		emailSvc:                               &email.SESEmailService{SES: ses.New(awsSession)},
+		notificationSvc:                        notification.NewService(common.RequireEnvStringSlice("NOTIFICATION_TARGET_ALLOWED_HOSTS", ",")),
+		budgetNotificationFromEmail:            common.RequireEnv("BUDGET_NOTIFICATION_FROM_EMAIL"),
+		budgetNotificationBCCEmails:            common.RequireEnvStringSlice("BUDGET_NOTIFICATION_BCC_EMAILS", ","),
+		budgetNotificationTemplateHTML:         common.RequireEnv("BUDGET_NOTIFICATION_TEMPLATE_HTML"),
+		budgetNotificationTemplateText:         common.RequireEnv("BUDGET_NOTIFICATION_TEMPLATE_TEXT"),
+		budgetNotificationTemplateSubject:      common.RequireEnv("BUDGET_NOTIFICATION_TEMPLATE_SUBJECT"),
+		budgetNotificationThresholdPercentiles: common.RequireEnvFloatSlice("BUDGET_NOTIFICATION_THRESHOLD_PERCENTILES", ","),
+		principalBudgetAmount:                  common.RequireEnvFloat("PRINCIPAL_BUDGET_AMOUNT"),
+		principalBudgetPeriod:                  common.RequireEnv("PRINCIPAL_BUDGET_PERIOD"),
+		usageTTL:                               common.RequireEnvInt("USAGE_TTL"),
+		budgetForecastAction:                   common.GetEnv("BUDGET_FORECAST_ACTION", forecastActionNone),
+	}
+
+	lambda.Start(func(event interface{}) {
 		// Cast the event as a Lease object
 		lease, err := eventToLease(event)
 		if err != nil {
@@ -38,43 +119,10 @@ func main() {
 		}
 		log.Printf("Checking budget for lease %s @ %s", lease.PrincipalID, lease.AccountID)
 
-		// Configure the DB service
-		dbSvc, err := db.NewFromEnv()
-		if err != nil {
-			log.Fatalf("Failed to configure DB service %s", err)
-		}
-
-		// Configure the STS Token service
-		awsSession := session.Must(session.NewSession())
-		stsSvc := sts.New(awsSession)
-		tokenSvc := &common.STS{Client: stsSvc}
-
-		usageSvc, err := usage.NewFromEnv()
-		if err != nil {
-			log.Fatalf("Failed to configure Usage service %s", err)
-		}
-
-		err = lambdaHandler(&lambdaHandlerInput{
-			dbSvc:                                  dbSvc,
-			lease:                                  lease,
-			awsSession:                             awsSession,
-			tokenSvc:                               tokenSvc,
-			budgetSvc:                              &budget.AWSBudgetService{},
-			usageSvc:                               usageSvc,
-			sqsSvc:                                 sqs.New(awsSession),
-			snsSvc:                                 &common.SNS{Client: sns.New(awsSession)},
-			leaseLockedTopicArn:                    common.RequireEnv("LEASE_LOCKED_TOPIC_ARN"),
-			emailSvc:                               &email.SESEmailService{SES: ses.New(awsSession)},
-			budgetNotificationFromEmail:            common.RequireEnv("BUDGET_NOTIFICATION_FROM_EMAIL"),
-			budgetNotificationBCCEmails:            common.RequireEnvStringSlice("BUDGET_NOTIFICATION_BCC_EMAILS", ","),
-			budgetNotificationTemplateHTML:         common.RequireEnv("BUDGET_NOTIFICATION_TEMPLATE_HTML"),
-			budgetNotificationTemplateText:         common.RequireEnv("BUDGET_NOTIFICATION_TEMPLATE_TEXT"),
-			budgetNotificationTemplateSubject:      common.RequireEnv("BUDGET_NOTIFICATION_TEMPLATE_SUBJECT"),
-			budgetNotificationThresholdPercentiles: common.RequireEnvFloatSlice("BUDGET_NOTIFICATION_THRESHOLD_PERCENTILES", ","),
-			principalBudgetAmount:                  common.RequireEnvFloat("PRINCIPAL_BUDGET_AMOUNT"),
-			principalBudgetPeriod:                  common.RequireEnv("PRINCIPAL_BUDGET_PERIOD"),
-			usageTTL:                               common.RequireEnvInt("USAGE_TTL"),
-		})
+		// Copy the input, as the handler applies the lease's budget policy to it
+		input := handlerInput
+		input.lease = lease
+		err = lambdaHandler(&input)
 		if err != nil {
 			log.Fatalf("Failed check budget: %s", err)
 		}
@@ -106,10 +154,13 @@ type lambdaHandlerInput struct {
 	tokenSvc                               common.TokenService
 	budgetSvc                              budget.Service
 	usageSvc                               usage.DBer
+	budgetPolicySvc                        budgetpolicyiface.Servicer
+	exchangeRates                          budget.ExchangeRates
 	snsSvc                                 common.Notificationer
 	leaseLockedTopicArn                    string
 	sqsSvc                                 awsiface.SQSAPI
 	emailSvc                               email.Service
+	notificationSvc                        notification.Service
 	budgetNotificationFromEmail            string
 	budgetNotificationBCCEmails            []string
 	budgetNotificationTemplateHTML         string
@@ -118,7 +169,8 @@ type lambdaHandlerInput struct {
 	budgetNotificationThresholdPercentiles []float64
 	principalBudgetAmount                  float64
 	principalBudgetPeriod                  string
-	usageTTL                               int // TTL in seconds for Usage DynamoDB records
+	usageTTL                               int    // TTL in seconds for Usage DynamoDB records
+	budgetForecastAction                   string // NONE, WARN or TERMINATE
 }
 
 func lambdaHandler(input *lambdaHandlerInput) error {
@@ -136,6 +188,20 @@ func lambdaHandler(input *lambdaHandlerInput) error {
 			input.lease.AccountID, input.lease.PrincipalID)
 	}
 
+	// Apply the budget policy of the principal, or their group
+	policy, err := input.budgetPolicySvc.Match(input.lease.PrincipalID)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to lookup budget policy for lease %s", leaseLogID)
+	}
+	if policy != nil {
+		if policy.PrincipalBudgetAmount != nil {
+			input.principalBudgetAmount = *policy.PrincipalBudgetAmount
+		}
+		if policy.PrincipalBudgetPeriod != nil {
+			input.principalBudgetPeriod = *policy.PrincipalBudgetPeriod
+		}
+	}
+
 	// Calculate actual spend for the lease
 	actualLeaseSpend, err := calculateLeaseSpend(&calculateSpendInput{
 		account:               account,
@@ -151,6 +217,13 @@ func lambdaHandler(input *lambdaHandlerInput) error {
 		return errors.Wrapf(err, "Failed to calculate spend for lease %s", leaseLogID)
 	}
 
+	// Spend is calculated in USD, while the lease budget may be in another currency
+	leaseCurrency := leaseBudgetCurrency(input.lease)
+	actualLeaseSpend, err = input.exchangeRates.Convert(actualLeaseSpend, budget.DefaultCurrency, leaseCurrency)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to convert spend for lease %s", leaseLogID)
+	}
+
 	// Calculate actual spend for the principal
 	actualPrincipalSpend, err := calculatePrincipalSpend(&calculateSpendInput{
 		account:               account,
@@ -171,6 +244,21 @@ func lambdaHandler(input *lambdaHandlerInput) error {
 
 	expired, reason := isLeaseExpired(input.lease, &leaseContext{currentTimeEpoch, actualLeaseSpend}, actualPrincipalSpend, input.principalBudgetAmount)
 
+	// Forecast the lease spend, so we don't have to wait for the next
+	// budget check to find a lease burning through its budget
+	forecastedLeaseSpend := 0.0
+	if !expired && (input.budgetForecastAction == forecastActionWarn || input.budgetForecastAction == forecastActionTerminate) {
+		forecastedLeaseSpend = forecastLeaseSpend(&forecastSpendInput{
+			lease:            input.lease,
+			budgetSvc:        input.budgetSvc,
+			exchangeRates:    input.exchangeRates,
+			actualLeaseSpend: actualLeaseSpend,
+		})
+		if forecastedLeaseSpend > input.lease.BudgetAmount && input.budgetForecastAction == forecastActionTerminate {
+			expired, reason = true, db.LeaseForecastOverBudget
+		}
+	}
+
 	if expired {
 		// Update the lease status with the inactive status and current end time.
 		input.lease.LeaseStatus = db.Inactive
@@ -181,10 +269,12 @@ func lambdaHandler(input *lambdaHandlerInput) error {
 		}
 	}
 
-	// Send notification emails, for budget thresholds
-	err = sendBudgetNotificationEmail(&sendBudgetNotificationEmailInput{
+	// Send notifications, for budget thresholds
+	err = sendBudgetNotification(&sendBudgetNotificationInput{
 		lease:                                  input.lease,
+		dbSvc:                                  input.dbSvc,
 		emailSvc:                               input.emailSvc,
+		notificationSvc:                        input.notificationSvc,
 		budgetNotificationFromEmail:            input.budgetNotificationFromEmail,
 		budgetNotificationBCCEmails:            input.budgetNotificationBCCEmails,
 		budgetNotificationTemplateHTML:         input.budgetNotificationTemplateHTML,
@@ -193,9 +283,10 @@ func lambdaHandler(input *lambdaHandlerInput) error {
 		budgetNotificationThresholdPercentiles: input.budgetNotificationThresholdPercentiles,
 		actualLeaseSpend:                       actualLeaseSpend,
 		actualPrincipalSpend:                   actualPrincipalSpend,
+		forecastedLeaseSpend:                   forecastedLeaseSpend,
 	})
 	if err != nil {
-		log.Printf("Failed to send budget notification emails for lease %s @ %s: %s",
+		log.Printf("Failed to send budget notifications for lease %s @ %s: %s",
 			input.lease.PrincipalID, input.lease.AccountID, err)
 		deferredErrors = append(deferredErrors, err)
 	}
@@ -208,6 +299,15 @@ func lambdaHandler(input *lambdaHandlerInput) error {
 	return nil
 }
 
+// leaseBudgetCurrency returns the currency of the lease budget.
+// Leases without a currency are budgeted in USD.
+func leaseBudgetCurrency(lease *db.Lease) string {
+	if lease.BudgetCurrency == "" {
+		return budget.DefaultCurrency
+	}
+	return lease.BudgetCurrency
+}
+
 // isLeaseExpried contains the logic for determining if a lease has already
 // expired, given the context.
 func isLeaseExpired(lease *db.Lease, context *leaseContext, actualPrincipalSpend float64, principalBudgetAmount float64) (bool, db.LeaseStatusReason) {
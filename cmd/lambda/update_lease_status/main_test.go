@@ -7,14 +7,20 @@ import (
 	"time"
 
 	awsMocks "github.com/Optum/dce/pkg/awsiface/mocks"
+	"github.com/Optum/dce/pkg/budget"
 	budgetMocks "github.com/Optum/dce/pkg/budget/mocks"
+	"github.com/Optum/dce/pkg/budgetpolicy"
+	policyMocks "github.com/Optum/dce/pkg/budgetpolicy/budgetpolicyiface/mocks"
 	commonMocks "github.com/Optum/dce/pkg/common/mocks"
 	"github.com/Optum/dce/pkg/db"
 	dbMocks "github.com/Optum/dce/pkg/db/mocks"
 	"github.com/Optum/dce/pkg/email"
 	emailMocks "github.com/Optum/dce/pkg/email/mocks"
+	"github.com/Optum/dce/pkg/notification"
+	notificationMocks "github.com/Optum/dce/pkg/notification/mocks"
 	"github.com/Optum/dce/pkg/usage"
 	usageMocks "github.com/Optum/dce/pkg/usage/mocks"
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -28,6 +34,10 @@ func TestCheckBudget(t *testing.T) {
 {{if .IsOverBudget}}
 Lease for principal {{.Lease.PrincipalID}} in AWS Account {{.Lease.AccountID}}
 has exceeded its budget of ${{.Lease.BudgetAmount}}. Actual spend is ${{.ActualSpend}}
+{{else if .IsForecastOverBudget}}
+Lease for principal {{.Lease.PrincipalID}} in AWS Account {{.Lease.AccountID}}
+is forecasted to exceed its budget of ${{.Lease.BudgetAmount}} before it expires.
+Actual spend is ${{.ActualSpend}}, forecasted spend is ${{printf "%.2f" .ForecastedSpend}}
 {{else}}
 Lease for principal {{.Lease.PrincipalID}} in AWS Account {{.Lease.AccountID}}
 has exceeded the {{.ThresholdPercentile}}% threshold limit for its budget of ${{.Lease.BudgetAmount}}.
@@ -39,6 +49,10 @@ Actual spend is ${{.ActualSpend}}
 {{if .IsOverBudget}}
 Lease for principal {{.Lease.PrincipalID}} in AWS Account {{.Lease.AccountID}}
 has exceeded its budget of ${{.Lease.BudgetAmount}}. Actual spend is ${{.ActualSpend}}
+{{else if .IsForecastOverBudget}}
+Lease for principal {{.Lease.PrincipalID}} in AWS Account {{.Lease.AccountID}}
+is forecasted to exceed its budget of ${{.Lease.BudgetAmount}} before it expires.
+Actual spend is ${{.ActualSpend}}, forecasted spend is ${{printf "%.2f" .ForecastedSpend}}
 {{else}}
 Lease for principal {{.Lease.PrincipalID}} in AWS Account {{.Lease.AccountID}}
 has exceeded the {{.ThresholdPercentile}}% threshold limit for its budget of ${{.Lease.BudgetAmount}}.
@@ -46,7 +60,7 @@ Actual spend is ${{.ActualSpend}}
 {{end}}
 `
 	emailTemplateSubject := `
-Lease {{if .IsOverBudget}}over budget{{else}}at {{.ThresholdPercentile}}% of budget{{end}} [{{.Lease.AccountID}}]
+Lease {{if .IsOverBudget}}over budget{{else if .IsForecastOverBudget}}forecasted over budget{{else}}at {{.ThresholdPercentile}}% of budget{{end}} [{{.Lease.AccountID}}]
 `
 
 	expectedOverBudgetEmailHTML := strings.TrimSpace(`
@@ -62,11 +76,34 @@ Lease for principal test-user in AWS Account 1234567890
 has exceeded its budget of $100. Actual spend is $150
 `)
 	expectedOverBudgetText := "Lease over budget [1234567890]"
+	expectedForecastOverBudgetEmailHTML := strings.TrimSpace(`
+<p>
+
+Lease for principal test-user in AWS Account 1234567890
+is forecasted to exceed its budget of $100 before it expires.
+Actual spend is $50, forecasted spend is $130.00
+
+</p>
+`)
+	expectedForecastOverBudgetEmailText := strings.TrimSpace(`
+Lease for principal test-user in AWS Account 1234567890
+is forecasted to exceed its budget of $100 before it expires.
+Actual spend is $50, forecasted spend is $130.00
+`)
+	expectedForecastOverBudgetText := "Lease forecasted over budget [1234567890]"
 
 	type checkBudgetTestInput struct {
 		budgetAmount                  float64
+		budgetCurrency                string
+		exchangeRates                 budget.ExchangeRates
 		actualSpend                   float64
+		principalSpend                float64
+		budgetPolicy                  *budgetpolicy.BudgetPolicy
+		budgetForecastAction          string
+		forecastSpend                 float64
+		forecastError                 error
 		leaseStatus                   db.LeaseStatus
+		sentNotifications             []db.LeaseBudgetNotification
 		expectedLeaseStatusTransition db.LeaseStatus
 		shouldTransitionLeaseStatus   bool
 		transitionLeaseError          error
@@ -80,6 +117,9 @@ has exceeded its budget of $100. Actual spend is $150
 	}
 
 	checkBudgetTest := func(test *checkBudgetTestInput) {
+		if test.budgetCurrency == "" {
+			test.budgetCurrency = "USD"
+		}
 		dbSvc := &dbMocks.DBer{}
 		tokenSvc := &commonMocks.TokenService{}
 		budgetSvc := &budgetMocks.Service{}
@@ -87,15 +127,20 @@ has exceeded its budget of $100. Actual spend is $150
 		snsSvc := &commonMocks.Notificationer{}
 		sqsSvc := &awsMocks.SQSAPI{}
 		emailSvc := &emailMocks.Service{}
+		notificationSvc := &notificationMocks.Service{}
+		policySvc := &policyMocks.Servicer{}
 		input := &lambdaHandlerInput{
 			dbSvc: dbSvc,
 			lease: &db.Lease{
 				AccountID:                "1234567890",
 				PrincipalID:              "test-user",
+				ID:                       "test-lease",
 				LeaseStatus:              test.leaseStatus,
 				BudgetAmount:             test.budgetAmount,
-				BudgetCurrency:           "USD",
+				BudgetCurrency:           test.budgetCurrency,
 				BudgetNotificationEmails: []string{"recipA@example.com", "recipB@example.com"},
+				NotificationTargets:      []string{"slack:https://hooks.slack.com/services/T000/B000/XXXX"},
+				BudgetNotifications:      test.sentNotifications,
 				LeaseStatusModifiedOn:    time.Unix(100, 0).Unix(),
 				ExpiresOn:                time.Now().AddDate(0, 0, +1000).Unix(), //Make sure it expires in the distant future as we aren't testing that
 			},
@@ -103,10 +148,13 @@ has exceeded its budget of $100. Actual spend is $150
 			tokenSvc:                               tokenSvc,
 			budgetSvc:                              budgetSvc,
 			usageSvc:                               usageSvc,
+			budgetPolicySvc:                        policySvc,
+			exchangeRates:                          test.exchangeRates,
 			snsSvc:                                 snsSvc,
 			leaseLockedTopicArn:                    "lease-locked",
 			sqsSvc:                                 sqsSvc,
 			emailSvc:                               emailSvc,
+			notificationSvc:                        notificationSvc,
 			budgetNotificationFromEmail:            "from@example.com",
 			budgetNotificationBCCEmails:            []string{"bcc@example.com"},
 			budgetNotificationTemplateHTML:         emailTemplateHTML,
@@ -115,6 +163,7 @@ has exceeded its budget of $100. Actual spend is $150
 			budgetNotificationThresholdPercentiles: []float64{75, 100},
 			principalBudgetAmount:                  1000,
 			usageTTL:                               3600,
+			budgetForecastAction:                   test.budgetForecastAction,
 		}
 
 		// Should grab the account from the DB, to get it's adminRoleArn
@@ -123,6 +172,9 @@ has exceeded its budget of $100. Actual spend is $150
 				AdminRoleArn: "mock:admin:role:arn",
 			}, nil)
 
+		// Should apply the budget policy of the principal
+		policySvc.On("Match", "test-user").Return(test.budgetPolicy, nil)
+
 		// Mock the TokenService
 		// Should assume Account.AdminRoleArn
 		tokenSvc.MockNewSession("mock:admin:role:arn")
@@ -130,20 +182,31 @@ has exceeded its budget of $100. Actual spend is $150
 		// Mock the BudgetService, actualSpend=150 (over budget)
 		// Should use assumed role
 		budgetSvc.On("SetCostExplorer", mock.Anything)
+		budgetSvc.On("SetAccountID", "")
 		currentTime := time.Now()
 		startDate := time.Date(currentTime.Year(), currentTime.Month(), currentTime.Day(), 0, 0, 0, 0, time.UTC)
 		usageEndDate := time.Date(currentTime.Year(), currentTime.Month(), currentTime.Day(), 23, 59, 59, 0, time.UTC)
 		endDate := startDate.AddDate(0, 0, 1)
-		budgetSvc.On("CalculateTotalSpend",
+		budgetSvc.On("CalculateSpendByService",
 			startDate,
 			endDate,
-		).Return(test.actualSpend, nil)
+		).Return(map[string]float64{"Amazon Elastic Compute Cloud - Compute": test.actualSpend}, nil)
+
+		// Should forecast the spend until the lease expires
+		if test.budgetForecastAction != "" {
+			expiresOn := time.Unix(input.lease.ExpiresOn, 0).UTC()
+			budgetSvc.On("ForecastSpend",
+				endDate,
+				time.Date(expiresOn.Year(), expiresOn.Month(), expiresOn.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1),
+			).Return(test.forecastSpend, test.forecastError)
+		}
 
 		// Expected Usage DB entry
 		inputUsage, err := usage.NewUsage(
 			usage.NewUsageInput{
 				PrincipalID:  "test-user",
 				AccountID:    "",
+				LeaseID:      "test-lease",
 				StartDate:    startDate.Unix(),
 				EndDate:      usageEndDate.Unix(),
 				CostAmount:   test.actualSpend,
@@ -152,11 +215,17 @@ has exceeded its budget of $100. Actual spend is $150
 			},
 		)
 		assert.Nil(t, err)
+		inputUsage.ServiceCosts = map[string]float64{"Amazon Elastic Compute Cloud - Compute": test.actualSpend}
 
 		budgetStartTime := time.Unix(input.lease.LeaseStatusModifiedOn, 0)
-		usageSvc.On("PutUsage", *inputUsage).Return(nil)
-		usageSvc.On("GetUsageByDateRange", budgetStartTime, usageEndDate.AddDate(0, 0, -1)).Return(nil, nil)
-		usageSvc.On("GetUsageByDateRange", mock.Anything, mock.Anything).Return(nil, nil)
+		usageSvc.On("UpdateLeaseUsage", *inputUsage).Return(nil)
+		usageSvc.On("GetUsageByLease", "test-lease", budgetStartTime, usageEndDate.AddDate(0, 0, -1)).Return(nil, nil)
+		usageSvc.On("GetUsageByDateRange", mock.Anything, mock.Anything).Return([]*usage.Usage{
+			{
+				PrincipalID: aws.String("test-user"),
+				CostAmount:  &test.principalSpend,
+			},
+		}, nil)
 
 		// Should transition from "Active" --> "FinanceLock"
 		if test.shouldTransitionLeaseStatus {
@@ -181,6 +250,16 @@ has exceeded its budget of $100. Actual spend is $150
 				BodyHTML:     test.expectedEmailBodyHTML,
 				BodyText:     test.expectedEmailBodyText,
 			}).Return(nil)
+			notificationSvc.On("Notify", []string{"slack:https://hooks.slack.com/services/T000/B000/XXXX"}, &notification.Message{
+				Subject: test.expectedEmailSubject,
+				Text:    test.expectedEmailBodyText,
+			}).Return(nil)
+			// Should record the notification, so it isn't sent again
+			dbSvc.On("AddLeaseBudgetNotification", "1234567890", "test-user",
+				mock.MatchedBy(func(sent db.LeaseBudgetNotification) bool {
+					return sent.BudgetAmount == test.budgetAmount && sent.SentOn > 0
+				}),
+			).Return(input.lease, nil)
 		}
 
 		// Call Lambda handler
@@ -198,6 +277,8 @@ has exceeded its budget of $100. Actual spend is $150
 		snsSvc.AssertExpectations(t)
 		sqsSvc.AssertExpectations(t)
 		emailSvc.AssertExpectations(t)
+		notificationSvc.AssertExpectations(t)
+		policySvc.AssertExpectations(t)
 	}
 
 	t.Run("Scenario: Over Budget Lease", func(t *testing.T) {
@@ -240,6 +321,65 @@ Lease for principal test-user in AWS Account 1234567890
 has exceeded the 75% threshold limit for its budget of $100.
 Actual spend is $76
 
+</p>
+`),
+			expectedEmailBodyText: strings.TrimSpace(`
+Lease for principal test-user in AWS Account 1234567890
+has exceeded the 75% threshold limit for its budget of $100.
+Actual spend is $76
+`),
+		})
+	})
+
+	t.Run("Scenario: Over Threshold Lease, already notified", func(t *testing.T) {
+		checkBudgetTest(&checkBudgetTestInput{
+			// >75% of budget
+			budgetAmount: 100,
+			actualSpend:  80,
+			leaseStatus:  db.Active,
+			// The 75% threshold was notified on a previous check
+			sentNotifications: []db.LeaseBudgetNotification{
+				{
+					Type:                db.LeaseBudgetThresholdNotification,
+					ThresholdPercentile: 75,
+					BudgetAmount:        100,
+					ActualSpend:         76,
+					SentOn:              time.Now().Add(-time.Hour).Unix(),
+				},
+			},
+			shouldTransitionLeaseStatus: false,
+			// Should not send the notification again
+			shouldSendEmail: false,
+		})
+	})
+
+	t.Run("Scenario: Over Threshold Lease, notified before the budget was raised", func(t *testing.T) {
+		checkBudgetTest(&checkBudgetTestInput{
+			// >75% of the raised budget
+			budgetAmount: 100,
+			actualSpend:  76,
+			leaseStatus:  db.Active,
+			// The 75% threshold was notified for a lower budget
+			sentNotifications: []db.LeaseBudgetNotification{
+				{
+					Type:                db.LeaseBudgetThresholdNotification,
+					ThresholdPercentile: 75,
+					BudgetAmount:        50,
+					ActualSpend:         38,
+					SentOn:              time.Now().Add(-time.Hour).Unix(),
+				},
+			},
+			shouldTransitionLeaseStatus: false,
+			// Should notify for the new budget
+			shouldSendEmail:      true,
+			expectedEmailSubject: "Lease at 75% of budget [1234567890]",
+			expectedEmailBodyHTML: strings.TrimSpace(`
+<p>
+
+Lease for principal test-user in AWS Account 1234567890
+has exceeded the 75% threshold limit for its budget of $100.
+Actual spend is $76
+
 </p>
 `),
 			expectedEmailBodyText: strings.TrimSpace(`
@@ -265,6 +405,133 @@ Actual spend is $76
 		})
 	})
 
+	t.Run("Scenario: Over Budget Lease in EUR", func(t *testing.T) {
+		checkBudgetTest(&checkBudgetTestInput{
+			// Under budget in USD, but over budget in EUR
+			budgetAmount:   50,
+			budgetCurrency: "EUR",
+			exchangeRates:  budget.ExchangeRates{"EUR": 0.5},
+			actualSpend:    150,
+			// Should transition from Active --> Inactive
+			leaseStatus:                   db.Active,
+			expectedLeaseStatusTransition: db.Inactive,
+			shouldTransitionLeaseStatus:   true,
+			// Should send notification email, in EUR
+			shouldSendEmail:      true,
+			expectedEmailSubject: expectedOverBudgetText,
+			expectedEmailBodyHTML: strings.TrimSpace(`
+<p>
+
+Lease for principal test-user in AWS Account 1234567890
+has exceeded its budget of $50. Actual spend is $75
+
+</p>
+`),
+			expectedEmailBodyText: strings.TrimSpace(`
+Lease for principal test-user in AWS Account 1234567890
+has exceeded its budget of $50. Actual spend is $75
+`),
+		})
+	})
+
+	t.Run("Scenario: Lease in a currency without an exchange rate", func(t *testing.T) {
+		checkBudgetTest(&checkBudgetTestInput{
+			budgetAmount:   100,
+			budgetCurrency: "GBP",
+			actualSpend:    50,
+			leaseStatus:    db.Active,
+			// Should not finance lock or reset
+			shouldTransitionLeaseStatus: false,
+			shouldSendEmail:             false,
+			expectedError:               "No exchange rate is configured for currency GBP",
+		})
+	})
+
+	t.Run("Scenario: Over Principal Budget Policy", func(t *testing.T) {
+		checkBudgetTest(&checkBudgetTestInput{
+			// Under the lease budget and the default principal budget,
+			// but over the principal budget of the policy
+			budgetAmount:   100,
+			actualSpend:    50,
+			principalSpend: 500,
+			budgetPolicy: &budgetpolicy.BudgetPolicy{
+				Group:                 aws.String("Interns"),
+				PrincipalBudgetAmount: aws.Float64(400),
+			},
+			// Should transition from Active --> Inactive
+			leaseStatus:                   db.Active,
+			expectedLeaseStatusTransition: db.Inactive,
+			shouldTransitionLeaseStatus:   true,
+			// Should notify that the principal spend is over the lease budget
+			shouldSendEmail:      true,
+			expectedEmailSubject: expectedOverBudgetText,
+			expectedEmailBodyHTML: strings.TrimSpace(`
+<p>
+
+Lease for principal test-user in AWS Account 1234567890
+has exceeded its budget of $100. Actual spend is $500
+
+</p>
+`),
+			expectedEmailBodyText: strings.TrimSpace(`
+Lease for principal test-user in AWS Account 1234567890
+has exceeded its budget of $100. Actual spend is $500
+`),
+		})
+	})
+
+	t.Run("Scenario: Forecasted Over Budget Lease", func(t *testing.T) {
+		checkBudgetTest(&checkBudgetTestInput{
+			// Under budget, but forecasted to go over budget
+			budgetAmount:         100,
+			actualSpend:          50,
+			budgetForecastAction: "WARN",
+			forecastSpend:        80,
+			leaseStatus:          db.Active,
+			// Should not finance lock or reset
+			shouldTransitionLeaseStatus: false,
+			// Should send notification email
+			shouldSendEmail:       true,
+			expectedEmailSubject:  expectedForecastOverBudgetText,
+			expectedEmailBodyHTML: expectedForecastOverBudgetEmailHTML,
+			expectedEmailBodyText: expectedForecastOverBudgetEmailText,
+		})
+	})
+
+	t.Run("Scenario: Forecasted Over Budget Lease, with TERMINATE action", func(t *testing.T) {
+		checkBudgetTest(&checkBudgetTestInput{
+			// Under budget, but forecasted to go over budget
+			budgetAmount:         100,
+			actualSpend:          50,
+			budgetForecastAction: "TERMINATE",
+			forecastSpend:        80,
+			// Should transition from Active --> Inactive
+			leaseStatus:                   db.Active,
+			expectedLeaseStatusTransition: db.Inactive,
+			shouldTransitionLeaseStatus:   true,
+			// Should send notification email
+			shouldSendEmail:       true,
+			expectedEmailSubject:  expectedForecastOverBudgetText,
+			expectedEmailBodyHTML: expectedForecastOverBudgetEmailHTML,
+			expectedEmailBodyText: expectedForecastOverBudgetEmailText,
+		})
+	})
+
+	t.Run("Scenario: Forecast Unavailable", func(t *testing.T) {
+		checkBudgetTest(&checkBudgetTestInput{
+			// Under budget, and the projected spend is under budget
+			budgetAmount:         100,
+			actualSpend:          50,
+			budgetForecastAction: "TERMINATE",
+			forecastError:        errors.New("insufficient amount of historical data"),
+			leaseStatus:          db.Active,
+			// Should not finance lock or reset
+			shouldTransitionLeaseStatus: false,
+			// Should not send notification email
+			shouldSendEmail: false,
+		})
+	})
+
 	t.Run("should handle errors and continue", func(t *testing.T) {
 		// Continue if DB fails
 		checkBudgetTest(&checkBudgetTestInput{
@@ -4,15 +4,20 @@ import (
 	"bytes"
 	"github.com/Optum/dce/pkg/db"
 	"github.com/Optum/dce/pkg/email"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/notification"
 	"html/template"
 	"log"
 	"sort"
 	"strings"
+	"time"
 )
 
-type sendBudgetNotificationEmailInput struct {
+type sendBudgetNotificationInput struct {
 	lease                                  *db.Lease
+	dbSvc                                  db.DBer
 	emailSvc                               email.Service
+	notificationSvc                        notification.Service
 	budgetNotificationFromEmail            string
 	budgetNotificationBCCEmails            []string
 	budgetNotificationTemplateHTML         string
@@ -21,9 +26,13 @@ type sendBudgetNotificationEmailInput struct {
 	budgetNotificationThresholdPercentiles []float64
 	actualLeaseSpend                       float64
 	actualPrincipalSpend                   float64
+	forecastedLeaseSpend                   float64
 }
 
-func sendBudgetNotificationEmail(input *sendBudgetNotificationEmailInput) error {
+// sendBudgetNotification notifies the lease's notification emails and
+// notification targets (eg. Slack), when a budget threshold is reached.
+// Each threshold is only notified once: sent notifications are recorded on the lease.
+func sendBudgetNotification(input *sendBudgetNotificationInput) error {
 
 	// Determine the highest lease budget threshold passed
 	thresholdLeasePercentile := determineThresholdPercentile(&determineThresholdPercentileInput{
@@ -39,13 +48,14 @@ func sendBudgetNotificationEmail(input *sendBudgetNotificationEmailInput) error
 		actualSpend:          input.actualPrincipalSpend,
 	})
 
-	if thresholdLeasePercentile == 0 && thresholdPrincipalPercentile == 0 {
+	isForecastOverBudget := input.forecastedLeaseSpend > input.lease.BudgetAmount
+	if thresholdLeasePercentile == 0 && thresholdPrincipalPercentile == 0 && !isForecastOverBudget {
 		return nil
 	}
 
-	if len(input.lease.BudgetNotificationEmails)+len(input.budgetNotificationBCCEmails) == 0 {
-		log.Printf("Skipping budget notification emails: "+
-			"no notification emails addressses were provided for lease %s @ %s",
+	if len(input.lease.BudgetNotificationEmails)+len(input.budgetNotificationBCCEmails)+len(input.lease.NotificationTargets) == 0 {
+		log.Printf("Skipping budget notifications: "+
+			"no notification emails addressses or targets were provided for lease %s @ %s",
 			input.lease.PrincipalID, input.lease.AccountID)
 		return nil
 	}
@@ -53,28 +63,110 @@ func sendBudgetNotificationEmail(input *sendBudgetNotificationEmailInput) error
 	// if both lease budget threshold and principal budget threshold passed, notify for lease budget threshold only
 	thresholdPercentile := 0.0
 	actualSpend := 0.0
+	notificationType := db.ForecastOverBudgetNotification
 	if (thresholdLeasePercentile > 0 && thresholdPrincipalPercentile > 0) || thresholdLeasePercentile > 0 {
 		thresholdPercentile = thresholdLeasePercentile
 		actualSpend = input.actualLeaseSpend
+		notificationType = db.LeaseBudgetThresholdNotification
 	} else if thresholdPrincipalPercentile > 0 {
 		thresholdPercentile = thresholdPrincipalPercentile
 		actualSpend = input.actualPrincipalSpend
+		notificationType = db.PrincipalBudgetThresholdNotification
 	}
 
-	log.Printf("Budget notification threshold hit at %.0f%%", thresholdPercentile)
-	log.Printf("Sending budget notification emails for lease %s @ %s to %s",
-		input.lease.PrincipalID, input.lease.AccountID, strings.Join(input.lease.BudgetNotificationEmails, ","))
+	if hasSentBudgetNotification(input.lease, notificationType, thresholdPercentile) {
+		log.Printf("Skipping budget notifications: "+
+			"%s notification at %.0f%% was already sent for lease %s @ %s",
+			notificationType, thresholdPercentile, input.lease.PrincipalID, input.lease.AccountID)
+		return nil
+	}
 
-	return sendEmail(&sendEmailInput{
+	if thresholdPercentile > 0 {
+		log.Printf("Budget notification threshold hit at %.0f%%", thresholdPercentile)
+	} else {
+		log.Printf("Lease forecasted to exceed its budget, with a forecasted spend of $%.2f", input.forecastedLeaseSpend)
+		actualSpend = input.actualLeaseSpend
+	}
+	msg, err := renderBudgetNotification(&renderBudgetNotificationInput{
 		lease:                             input.lease,
-		emailSvc:                          input.emailSvc,
-		budgetNotificationFromEmail:       input.budgetNotificationFromEmail,
-		budgetNotificationBCCEmails:       input.budgetNotificationBCCEmails,
 		budgetNotificationTemplateHTML:    input.budgetNotificationTemplateHTML,
 		budgetNotificationTemplateText:    input.budgetNotificationTemplateText,
 		budgetNotificationTemplateSubject: input.budgetNotificationTemplateSubject,
 		actualSpend:                       actualSpend,
+		forecastedSpend:                   input.forecastedLeaseSpend,
 	}, thresholdPercentile)
+	if err != nil {
+		return err
+	}
+
+	// Send to every channel, even if one fails
+	errs := []error{}
+	sent := false
+	if len(input.lease.BudgetNotificationEmails)+len(input.budgetNotificationBCCEmails) > 0 {
+		log.Printf("Sending budget notification emails for lease %s @ %s to %s",
+			input.lease.PrincipalID, input.lease.AccountID, strings.Join(input.lease.BudgetNotificationEmails, ","))
+		err = input.emailSvc.SendEmail(&email.SendEmailInput{
+			FromAddress:  input.budgetNotificationFromEmail,
+			ToAddresses:  input.lease.BudgetNotificationEmails,
+			BCCAddresses: input.budgetNotificationBCCEmails,
+			BodyHTML:     msg.bodyHTML,
+			BodyText:     msg.bodyText,
+			Subject:      msg.subject,
+		})
+		if err != nil {
+			errs = append(errs, err)
+		} else {
+			sent = true
+		}
+	}
+
+	if len(input.lease.NotificationTargets) > 0 {
+		log.Printf("Sending budget notifications for lease %s @ %s to %d notification targets",
+			input.lease.PrincipalID, input.lease.AccountID, len(input.lease.NotificationTargets))
+		err = input.notificationSvc.Notify(input.lease.NotificationTargets, &notification.Message{
+			Subject: msg.subject,
+			Text:    msg.bodyText,
+		})
+		if err != nil {
+			errs = append(errs, err)
+		} else {
+			sent = true
+		}
+	}
+
+	// Record the notification, so it isn't sent again.
+	// If every channel failed, it will be retried on the next check.
+	if sent {
+		_, err = input.dbSvc.AddLeaseBudgetNotification(input.lease.AccountID, input.lease.PrincipalID, db.LeaseBudgetNotification{
+			Type:                notificationType,
+			ThresholdPercentile: thresholdPercentile,
+			BudgetAmount:        input.lease.BudgetAmount,
+			ActualSpend:         actualSpend,
+			SentOn:              time.Now().Unix(),
+		})
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.NewMultiError("Failed to send budget notifications", errs)
+	}
+	return nil
+}
+
+// hasSentBudgetNotification returns true if a notification of the same type and
+// threshold was already sent for the lease's current budget amount.
+// Raising the budget amount allows the thresholds to be notified again.
+func hasSentBudgetNotification(lease *db.Lease, notificationType db.LeaseBudgetNotificationType, thresholdPercentile float64) bool {
+	for _, sent := range lease.BudgetNotifications {
+		if sent.Type == notificationType &&
+			sent.ThresholdPercentile == thresholdPercentile &&
+			sent.BudgetAmount == lease.BudgetAmount {
+			return true
+		}
+	}
+	return false
 }
 
 func renderTemplate(id string, templateStr string, data interface{}) (string, error) {
@@ -111,51 +203,59 @@ func determineThresholdPercentile(input *determineThresholdPercentileInput) floa
 	return thresholdPassed
 }
 
-type sendEmailInput struct {
+type renderBudgetNotificationInput struct {
 	lease                             *db.Lease
-	emailSvc                          email.Service
-	budgetNotificationFromEmail       string
-	budgetNotificationBCCEmails       []string
 	budgetNotificationTemplateHTML    string
 	budgetNotificationTemplateText    string
 	budgetNotificationTemplateSubject string
 	actualSpend                       float64
+	forecastedSpend                   float64
+}
+
+type budgetNotification struct {
+	subject  string
+	bodyHTML string
+	bodyText string
 }
 
-func sendEmail(input *sendEmailInput, thresholdPercentile float64) error {
+// renderBudgetNotification renders the notification templates.
+// The text template is also used for chat notifications.
+func renderBudgetNotification(input *renderBudgetNotificationInput, thresholdPercentile float64) (*budgetNotification, error) {
 
 	// Render email templates
+	// The forecast is only reported when no threshold has been reached
 	templateData := struct {
-		Lease               db.Lease
-		ActualSpend         float64
-		IsOverBudget        bool
-		ThresholdPercentile int
+		Lease                db.Lease
+		ActualSpend          float64
+		ForecastedSpend      float64
+		IsOverBudget         bool
+		IsForecastOverBudget bool
+		ThresholdPercentile  int
 	}{
-		Lease:               *input.lease,
-		ActualSpend:         input.actualSpend,
-		IsOverBudget:        input.actualSpend >= input.lease.BudgetAmount,
-		ThresholdPercentile: int(thresholdPercentile),
+		Lease:                *input.lease,
+		ActualSpend:          input.actualSpend,
+		ForecastedSpend:      input.forecastedSpend,
+		IsOverBudget:         input.actualSpend >= input.lease.BudgetAmount,
+		IsForecastOverBudget: thresholdPercentile == 0 && input.forecastedSpend > input.lease.BudgetAmount,
+		ThresholdPercentile:  int(thresholdPercentile),
 	}
 	bodyHTML, err := renderTemplate("htmlEmail", input.budgetNotificationTemplateHTML, templateData)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	bodyText, err := renderTemplate("textEmail", input.budgetNotificationTemplateText, templateData)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	subject, err := renderTemplate("emailSubject", input.budgetNotificationTemplateSubject, templateData)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return input.emailSvc.SendEmail(&email.SendEmailInput{
-		FromAddress:  input.budgetNotificationFromEmail,
-		ToAddresses:  input.lease.BudgetNotificationEmails,
-		BCCAddresses: input.budgetNotificationBCCEmails,
-		BodyHTML:     bodyHTML,
-		BodyText:     bodyText,
-		Subject:      subject,
-	})
+	return &budgetNotification{
+		subject:  subject,
+		bodyHTML: bodyHTML,
+		bodyText: bodyText,
+	}, nil
 }
@@ -37,6 +37,7 @@ func calculateLeaseSpend(input *calculateSpendInput) (float64, error) {
 	input.budgetSvc.SetCostExplorer(
 		costexplorer.New(assumedSession),
 	)
+	input.budgetSvc.SetAccountID(input.account.ID)
 
 	//Get usage for current date and add it to Usage cache db
 	currentTime := time.Now()
@@ -44,10 +45,14 @@ func calculateLeaseSpend(input *calculateSpendInput) (float64, error) {
 	usageEndTime := time.Date(currentTime.Year(), currentTime.Month(), currentTime.Day(), 23, 59, 59, 0, time.UTC)
 
 	log.Printf("usageStart: %d and usageEnd :%d", usageStartTime.Unix(), usageEndTime.Unix())
-	todayCostAmount, err := input.budgetSvc.CalculateTotalSpend(usageStartTime, usageStartTime.AddDate(0, 0, 1))
+	todayServiceCosts, err := input.budgetSvc.CalculateSpendByService(usageStartTime, usageStartTime.AddDate(0, 0, 1))
 	if err != nil {
 		return 0, errors.Wrapf(err, "Failed to calculate spend for account %s", input.lease.AccountID)
 	}
+	todayCostAmount := 0.0
+	for _, costAmount := range todayServiceCosts {
+		todayCostAmount = todayCostAmount + costAmount
+	}
 
 	log.Printf("usage for today: %f", todayCostAmount)
 
@@ -57,17 +62,21 @@ func calculateLeaseSpend(input *calculateSpendInput) (float64, error) {
 		EndDate:      usageEndTime.Unix(),
 		PrincipalID:  input.lease.PrincipalID,
 		AccountID:    input.account.ID,
+		LeaseID:      input.lease.ID,
 		CostAmount:   todayCostAmount,
-		CostCurrency: "USD",
+		CostCurrency: budget.DefaultCurrency,
 		TimeToLive:   usageStartTime.Add(time.Duration(input.usageTTL) * time.Second).Unix(),
 	})
 	if err != nil {
 		return 0, nil
 	}
 
-	err = input.usageSvc.PutUsage(*usageItem)
+	// A principal may have several active leases, which all share a single
+	// usage record for the day. Keep the spend of the principal's other accounts.
+	usageItem.ServiceCosts = todayServiceCosts
+	err = input.usageSvc.UpdateLeaseUsage(*usageItem)
 	if err != nil {
-		return 0, nil
+		return 0, errors.Wrapf(err, "Failed to update usage for principal %s", input.lease.PrincipalID)
 	}
 
 	// Budget period starts last time the lease was reset.
@@ -82,27 +91,78 @@ func calculateLeaseSpend(input *calculateSpendInput) (float64, error) {
 		budgetStartTime.Format("2006-01-02"), budgetEndTime.Format("2006-01-02"),
 	)
 
-	// Query Usage cache DB
-	usageRecords, err := input.usageSvc.GetUsageByDateRange(budgetStartTime, budgetEndTime)
+	// Query the lease's own usage records
+	usageRecords, err := input.usageSvc.GetUsageByLease(input.lease.ID, budgetStartTime, budgetEndTime)
 	if err != nil {
-		return 0, errors.Wrapf(err, "Failed to retrieve usage for account %s", input.lease.AccountID)
+		return 0, errors.Wrapf(err, "Failed to retrieve usage for lease %s", input.lease.ID)
 	}
 
 	// DynDB is eventually consistent. Pull cache DB for SUN-->yesterday, then add the known value for today
 	spend := todayCostAmount
 	for _, usage := range usageRecords {
 		log.Printf("usage records retrieved: %v", usage)
-		if *usage.PrincipalID == input.lease.PrincipalID && *usage.AccountID == input.lease.AccountID {
+		if usage.CostAmount != nil {
 			spend = spend + *usage.CostAmount
 		}
 	}
 
-	log.Printf("Lease for %s @ %s has spent $%.2f of their $%.2f budget",
-		input.lease.PrincipalID, input.lease.AccountID, spend, input.lease.BudgetAmount)
+	log.Printf("Lease for %s @ %s has spent $%.2f USD of their %.2f %s budget",
+		input.lease.PrincipalID, input.lease.AccountID, spend, input.lease.BudgetAmount, leaseBudgetCurrency(input.lease))
 
 	return spend, nil
 }
 
+type forecastSpendInput struct {
+	lease            *db.Lease
+	budgetSvc        budget.Service
+	exchangeRates    budget.ExchangeRates
+	actualLeaseSpend float64 // in the currency of the lease budget
+}
+
+// forecastLeaseSpend forecasts the amount spent by User principal for current lease,
+// by the time the lease expires.
+// Expects the budget service to be configured for the leased account.
+func forecastLeaseSpend(input *forecastSpendInput) float64 {
+	currentTime := time.Now()
+	expiresOn := time.Unix(input.lease.ExpiresOn, 0).UTC()
+
+	// Today's spend is part of the actual lease spend,
+	// so forecast from tomorrow until the end of the day the lease expires
+	forecastStartTime := time.Date(currentTime.Year(), currentTime.Month(), currentTime.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+	forecastEndTime := time.Date(expiresOn.Year(), expiresOn.Month(), expiresOn.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+
+	// The account forecast is based on the account's recent spend, which
+	// includes the spend of its previous lease until the current lease has
+	// been active for the whole forecast history. Until then, only the
+	// spend of the current lease is projected.
+	leaseStartTime := time.Unix(input.lease.LeaseStatusModifiedOn, 0)
+	projectSpend := func() float64 {
+		return budget.ProjectSpend(input.actualLeaseSpend, leaseStartTime, currentTime, expiresOn)
+	}
+	if leaseStartTime.After(forecastStartTime.AddDate(0, 0, -budget.ForecastHistoryDays)) {
+		log.Printf("Lease %s @ %s is newer than the forecast history, projecting spend instead",
+			input.lease.PrincipalID, input.lease.AccountID)
+		return projectSpend()
+	}
+
+	forecastSpend, err := input.budgetSvc.ForecastSpend(forecastStartTime, forecastEndTime)
+	if err == nil {
+		forecastSpend, err = input.exchangeRates.Convert(forecastSpend, budget.DefaultCurrency, leaseBudgetCurrency(input.lease))
+	}
+	if err != nil {
+		// Cost Explorer needs some spend history before it can forecast
+		log.Printf("Failed to forecast spend for lease %s @ %s, projecting spend instead: %s",
+			input.lease.PrincipalID, input.lease.AccountID, err)
+		return projectSpend()
+	}
+
+	spend := input.actualLeaseSpend + forecastSpend
+	log.Printf("Lease for %s @ %s is forecasted to spend $%.2f of their $%.2f budget",
+		input.lease.PrincipalID, input.lease.AccountID, spend, input.lease.BudgetAmount)
+
+	return spend
+}
+
 // calculatePrincipalSpend calculates the amount spent by User principal for current billing period
 func calculatePrincipalSpend(input *calculateSpendInput) (float64, error) {
 
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	awsMocks "github.com/Optum/dce/pkg/awsiface/mocks"
+	"github.com/Optum/dce/pkg/budget"
+	budgetMocks "github.com/Optum/dce/pkg/budget/mocks"
+	commonMocks "github.com/Optum/dce/pkg/common/mocks"
+	"github.com/Optum/dce/pkg/db"
+	"github.com/Optum/dce/pkg/usage"
+	usageMocks "github.com/Optum/dce/pkg/usage/mocks"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalculateLeaseSpend(t *testing.T) {
+
+	t.Run("should add today's spend to the lease's recorded usage", func(t *testing.T) {
+		tokenSvc := &commonMocks.TokenService{}
+		budgetSvc := &budgetMocks.Service{}
+		usageSvc := &usageMocks.DBer{}
+
+		currentTime := time.Now()
+		startDate := time.Date(currentTime.Year(), currentTime.Month(), currentTime.Day(), 0, 0, 0, 0, time.UTC)
+		yesterday := startDate.AddDate(0, 0, -1)
+
+		tokenSvc.MockNewSession("mock:admin:role:arn")
+		budgetSvc.On("SetCostExplorer", mock.Anything)
+		budgetSvc.On("SetAccountID", "123456789012")
+		budgetSvc.On("CalculateSpendByService", startDate, startDate.AddDate(0, 0, 1)).Return(map[string]float64{
+			"Amazon Elastic Compute Cloud - Compute": 8,
+			"EC2 - Other":                            2,
+		}, nil)
+
+		// Should record this lease's spend on today's usage record
+		usageSvc.On("UpdateLeaseUsage", mock.MatchedBy(func(u usage.Usage) bool {
+			assert.Equal(t, "test-user", *u.PrincipalID)
+			assert.Equal(t, "123456789012", *u.AccountID)
+			assert.Equal(t, "test-lease", *u.LeaseID)
+			assert.Equal(t, startDate.Unix(), *u.StartDate)
+			assert.Equal(t, 10.0, *u.CostAmount)
+			assert.Equal(t, map[string]float64{
+				"Amazon Elastic Compute Cloud - Compute": 8,
+				"EC2 - Other":                            2,
+			}, u.ServiceCosts)
+			return true
+		})).Return(nil)
+
+		// The lease's own usage, excluding a previous lease of the same account
+		usageSvc.On("GetUsageByLease", "test-lease", time.Unix(yesterday.Unix(), 0), mock.Anything).Return([]*usage.Usage{
+			{
+				PrincipalID: aws.String("test-user"),
+				AccountID:   aws.String("123456789012"),
+				LeaseID:     aws.String("test-lease"),
+				StartDate:   aws.Int64(yesterday.Unix()),
+				CostAmount:  aws.Float64(5),
+			},
+		}, nil)
+
+		spend, err := calculateLeaseSpend(&calculateSpendInput{
+			account: &db.Account{
+				ID:           "123456789012",
+				AdminRoleArn: "mock:admin:role:arn",
+			},
+			lease: &db.Lease{
+				AccountID:             "123456789012",
+				PrincipalID:           "test-user",
+				ID:                    "test-lease",
+				LeaseStatusModifiedOn: yesterday.Unix(),
+			},
+			tokenSvc:   tokenSvc,
+			budgetSvc:  budgetSvc,
+			usageSvc:   usageSvc,
+			awsSession: &awsMocks.AwsSession{},
+			usageTTL:   3600,
+		})
+		require.Nil(t, err)
+
+		// Today's spend plus yesterday's spend in this lease
+		assert.Equal(t, 15.0, spend)
+		usageSvc.AssertExpectations(t)
+	})
+}
+
+func TestForecastLeaseSpend(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name           string
+		leaseStartedOn time.Time
+		forecastSpend  float64
+		expectForecast bool
+		expectedSpend  float64
+	}{
+		{
+			name:           "should forecast from the account spend of a lease older than the forecast history",
+			leaseStartedOn: now.AddDate(0, 0, -30),
+			forecastSpend:  30,
+			expectForecast: true,
+			expectedSpend:  50,
+		},
+		{
+			name:           "should project the lease's own spend when the forecast history precedes the lease",
+			leaseStartedOn: now.AddDate(0, 0, -2),
+			expectForecast: false,
+			// 20 spent over 2 days, with 2 days remaining
+			expectedSpend: 40,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			budgetSvc := &budgetMocks.Service{}
+			budgetSvc.On("ForecastSpend", mock.Anything, mock.Anything).Return(test.forecastSpend, nil)
+
+			spend := forecastLeaseSpend(&forecastSpendInput{
+				lease: &db.Lease{
+					AccountID:             "123456789012",
+					PrincipalID:           "test-user",
+					BudgetCurrency:        "USD",
+					LeaseStatusModifiedOn: test.leaseStartedOn.Unix(),
+					ExpiresOn:             now.AddDate(0, 0, 2).Unix(),
+				},
+				budgetSvc:        budgetSvc,
+				exchangeRates:    budget.ExchangeRates{},
+				actualLeaseSpend: 20,
+			})
+
+			assert.InDelta(t, test.expectedSpend, spend, 0.01)
+			if test.expectForecast {
+				budgetSvc.AssertCalled(t, "ForecastSpend", mock.Anything, mock.Anything)
+			} else {
+				budgetSvc.AssertNotCalled(t, "ForecastSpend", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
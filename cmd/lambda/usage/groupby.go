@@ -0,0 +1,155 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Optum/dce/pkg/api/response"
+	"github.com/Optum/dce/pkg/usage"
+)
+
+const (
+	// GroupByService groups usage by AWS service
+	GroupByService = "service"
+	// UnknownServiceName is used for usage recorded before
+	// the service breakdown was tracked
+	UnknownServiceName = "Unknown"
+)
+
+// GetUsageGroupedByService - Returns the cost of each AWS service, by principal.
+// Accepts the same filters as the other usage endpoints.
+func GetUsageGroupedByService(w http.ResponseWriter, r *http.Request) {
+
+	groupBy := r.FormValue(GroupByParam)
+	if groupBy != GroupByService {
+		response.WriteRequestValidationError(w, fmt.Sprintf("Unsupported groupBy value %q. Must be %q", groupBy, GroupByService))
+		return
+	}
+
+	var usageRecords []*usage.Usage
+	if len(r.FormValue(EndDateParam)) > 0 {
+		// Use the date range index, if we have one
+		i, err := strconv.ParseInt(r.FormValue(StartDateParam), 10, 64)
+		if err != nil {
+			response.WriteRequestValidationError(w, fmt.Sprintf("Failed to parse usage start date: %s", err))
+			return
+		}
+		j, err := strconv.ParseInt(r.FormValue(EndDateParam), 10, 64)
+		if err != nil {
+			response.WriteRequestValidationError(w, fmt.Sprintf("Failed to parse usage end date: %s", err))
+			return
+		}
+
+		usageRecords, err = getUsageByDateRange(time.Unix(i, 0), time.Unix(j, 0), r.FormValue(PrincipalIDParam))
+		if err != nil {
+			response.WriteServerErrorWithResponse(w, fmt.Sprintf("Error querying usage: %s", err))
+			return
+		}
+	} else {
+		getUsageInput, err := parseGetUsageInput(r)
+		if err != nil {
+			response.WriteRequestValidationError(w, fmt.Sprintf("Error parsing query params"))
+			return
+		}
+
+		usageRecords, err = getAllUsage(getUsageInput)
+		if err != nil {
+			response.WriteServerErrorWithResponse(w, fmt.Sprintf("Error querying usage: %s", err))
+			return
+		}
+	}
+
+	err := json.NewEncoder(w).Encode(SumCostAmountByService(usageRecords))
+	if err != nil {
+		log.Print(err)
+		response.WriteServerError(w)
+	}
+}
+
+// getUsageByDateRange returns the usage records in the date range,
+// optionally filtered by principal
+func getUsageByDateRange(startDate time.Time, endDate time.Time, principalID string) ([]*usage.Usage, error) {
+	usageRecords, err := UsageSvc.GetUsageByDateRange(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []*usage.Usage{}
+	for _, usageItem := range usageRecords {
+		if principalID == "" || *usageItem.PrincipalID == principalID {
+			results = append(results, usageItem)
+		}
+	}
+	return results, nil
+}
+
+// getAllUsage returns every usage record matching the query, across all pages
+func getAllUsage(input usage.GetUsageInput) ([]*usage.Usage, error) {
+	results := []*usage.Usage{}
+	for {
+		output, err := UsageSvc.GetUsage(input)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, output.Results...)
+
+		if len(output.NextKeys) == 0 {
+			break
+		}
+		input.StartKeys = output.NextKeys
+	}
+	return results, nil
+}
+
+// SumCostAmountByService adds up the cost amount of each AWS service, by principal.
+// Results are sorted by principal, with their most expensive services first.
+func SumCostAmountByService(input []*usage.Usage) []*response.ServiceUsageResponse {
+	type serviceKey struct {
+		principalID string
+		serviceName string
+	}
+	sums := map[serviceKey]*response.ServiceUsageResponse{}
+
+	addCost := func(usageItem *usage.Usage, serviceName string, costAmount float64) {
+		key := serviceKey{*usageItem.PrincipalID, serviceName}
+		if _, ok := sums[key]; !ok {
+			sums[key] = &response.ServiceUsageResponse{
+				PrincipalID:  *usageItem.PrincipalID,
+				ServiceName:  serviceName,
+				CostCurrency: *usageItem.CostCurrency,
+			}
+		}
+		sums[key].CostAmount = sums[key].CostAmount + costAmount
+	}
+
+	for _, usageItem := range input {
+		if usageItem.ServiceCosts == nil {
+			addCost(usageItem, UnknownServiceName, *usageItem.CostAmount)
+			continue
+		}
+		for serviceName, costAmount := range usageItem.ServiceCosts {
+			addCost(usageItem, serviceName, costAmount)
+		}
+	}
+
+	output := make([]*response.ServiceUsageResponse, 0, len(sums))
+	for _, item := range sums {
+		output = append(output, item)
+	}
+	sort.Slice(output, func(i, j int) bool {
+		if output[i].PrincipalID != output[j].PrincipalID {
+			return output[i].PrincipalID < output[j].PrincipalID
+		}
+		if output[i].CostAmount != output[j].CostAmount {
+			return output[i].CostAmount > output[j].CostAmount
+		}
+		return output[i].ServiceName < output[j].ServiceName
+	})
+
+	return output
+}
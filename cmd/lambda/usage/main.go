@@ -8,6 +8,7 @@ import (
 	"log"
 
 	"github.com/Optum/dce/pkg/api"
+	"github.com/Optum/dce/pkg/config"
 	"github.com/Optum/dce/pkg/usage"
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
@@ -23,13 +24,16 @@ const (
 	NextPrincipalIDParam = "nextPrincipalId"
 	NextStartDateParam   = "nextStartDate"
 	LimitParam           = "limit"
+	GroupByParam         = "groupBy"
 )
 
 var muxLambda *gorillamux.GorillaMuxAdapter
 
 var (
 	// UsageSvc - Service for getting usage
-	UsageSvc    *usage.DB
+	UsageSvc *usage.DB
+	// Services handles the configuration of the AWS services
+	Services    *config.ServiceBuilder
 	baseRequest url.URL
 )
 
@@ -38,6 +42,13 @@ func init() {
 
 	usageRoutes := api.Routes{
 
+		api.Route{
+			"GetUsageGroupedByService",
+			"GET",
+			"/usage",
+			[]string{GroupByParam},
+			GetUsageGroupedByService,
+		},
 		api.Route{
 			"GetUsageByStartDateAndEndDate",
 			"GET",
@@ -52,6 +63,13 @@ func init() {
 			[]string{StartDateParam, PrincipalIDParam},
 			GetUsageByStartDateAndPrincipalID,
 		},
+		api.Route{
+			"GetUsageSummary",
+			"GET",
+			"/usage/summary",
+			api.EmptyQueryString,
+			GetUsageSummary,
+		},
 		api.Route{
 			"GetAllUsage",
 			"GET",
@@ -80,6 +98,7 @@ func Handler(ctx context.Context, req events.APIGatewayProxyRequest) (events.API
 func main() {
 
 	UsageSvc = newUsage()
+	Services = newServices()
 
 	lambda.Start(Handler)
 }
@@ -93,3 +112,22 @@ func newUsage() *usage.DB {
 
 	return usageSvc
 }
+
+func newServices() *config.ServiceBuilder {
+	cfgBldr := &config.ConfigurationBuilder{}
+	err := cfgBldr.WithEnv("AWS_CURRENT_REGION", "AWS_CURRENT_REGION", "us-east-1").Build()
+	if err != nil {
+		log.Printf("Error: %+v", err)
+	}
+	svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+
+	_, err = svcBldr.
+		WithUsageService().
+		WithLeaseService().
+		Build()
+	if err != nil {
+		log.Fatalf("Failed to initialize services: %s", err)
+	}
+
+	return svcBldr
+}
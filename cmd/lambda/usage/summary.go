@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Optum/dce/pkg/api"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/lease"
+	"github.com/Optum/dce/pkg/usage"
+)
+
+const (
+	// PeriodParam is the period to summarize usage over
+	PeriodParam = "period"
+)
+
+// GetUsageSummary - Returns the total, average and top spenders
+// of a weekly or monthly period, by principal, account or lease.
+// The period containing the startDate param (default: now) is summarized.
+func GetUsageSummary(w http.ResponseWriter, r *http.Request) {
+
+	input := &usage.SummaryInput{
+		GroupBy: usage.SummaryGroupBy(r.FormValue(GroupByParam)),
+		Period:  usage.SummaryPeriod(r.FormValue(PeriodParam)),
+		Date:    time.Now(),
+	}
+	if input.Period == "" {
+		input.Period = usage.SummaryPeriodMonthly
+	}
+
+	if len(r.FormValue(StartDateParam)) > 0 {
+		i, err := strconv.ParseInt(r.FormValue(StartDateParam), 10, 64)
+		if err != nil {
+			api.WriteAPIErrorResponse(w, errors.NewBadRequest(fmt.Sprintf("Failed to parse usage start date: %s", err)))
+			return
+		}
+		input.Date = time.Unix(i, 0)
+	}
+
+	if len(r.FormValue(LimitParam)) > 0 {
+		limit, err := strconv.Atoi(r.FormValue(LimitParam))
+		if err != nil {
+			api.WriteAPIErrorResponse(w, errors.NewBadRequest(fmt.Sprintf("Failed to parse limit: %s", err)))
+			return
+		}
+		input.Limit = limit
+	}
+
+	if input.GroupBy == usage.SummaryGroupByLease {
+		input.LeaseID = newLeaseIDResolver()
+	}
+
+	summary, err := Services.UsageService().Summarize(input)
+	if err != nil {
+		api.WriteAPIErrorResponse(w, err)
+		return
+	}
+
+	api.WriteAPIResponse(w, http.StatusOK, summary)
+}
+
+// newLeaseIDResolver returns a function to find the lease
+// a principal had on an account, on the day usage was recorded.
+// Only used for usage recorded before spend was tracked by lease.
+// Leases are keyed by account and principal, so each pair is looked up once.
+func newLeaseIDResolver() func(principalID string, accountID string, startDate int64) (string, error) {
+	leases := map[string]*lease.Lease{}
+	return func(principalID string, accountID string, startDate int64) (string, error) {
+		key := principalID + "/" + accountID
+		l, ok := leases[key]
+		if !ok {
+			query := &lease.Lease{
+				PrincipalID: &principalID,
+				AccountID:   &accountID,
+			}
+			err := Services.LeaseService().ListPages(query, func(page *lease.Leases) bool {
+				if len(*page) > 0 {
+					l = &(*page)[0]
+				}
+				return l == nil
+			})
+			if err != nil {
+				return "", err
+			}
+			leases[key] = l
+		}
+		if l == nil || l.ID == nil {
+			return "", nil
+		}
+
+		// The lease must overlap the day usage was recorded
+		endDate := time.Unix(startDate, 0).AddDate(0, 0, 1).Unix()
+		start := int64(0)
+		if l.CreatedOn != nil {
+			start = *l.CreatedOn
+		}
+		if l.StartsOn != nil {
+			start = *l.StartsOn
+		}
+		if start >= endDate {
+			return "", nil
+		}
+		if l.Status != nil && *l.Status == lease.StatusInactive &&
+			l.StatusModifiedOn != nil && *l.StatusModifiedOn < startDate {
+			return "", nil
+		}
+		return *l.ID, nil
+	}
+}
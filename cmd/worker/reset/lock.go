@@ -0,0 +1,129 @@
+package main
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+)
+
+// accountLocker allows only one reset of each account at a time
+type accountLocker interface {
+	// Lock returns false if the account is already locked
+	Lock(accountID string) (bool, error)
+	// Extend keeps the lock from expiring, while the reset is in progress
+	Extend(accountID string) error
+	Unlock(accountID string) error
+}
+
+// memoryLocker locks accounts within a single worker
+type memoryLocker struct {
+	mu     sync.Mutex
+	locked map[string]bool
+}
+
+func (l *memoryLocker) Lock(accountID string) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.locked == nil {
+		l.locked = map[string]bool{}
+	}
+	if l.locked[accountID] {
+		return false, nil
+	}
+	l.locked[accountID] = true
+	return true, nil
+}
+
+func (l *memoryLocker) Extend(accountID string) error {
+	return nil
+}
+
+func (l *memoryLocker) Unlock(accountID string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.locked, accountID)
+	return nil
+}
+
+// dynamoLocker locks accounts across workers, with an item per locked account.
+// Locks expire after the TTL, so the accounts of a worker which stopped
+// without unlocking them can be reset by another worker.
+type dynamoLocker struct {
+	DynamoDB  dynamodbiface.DynamoDBAPI
+	TableName string
+	// Owner identifies the worker, so it only extends and releases its own locks
+	Owner string
+	TTL   time.Duration
+}
+
+func (l *dynamoLocker) Lock(accountID string) (bool, error) {
+	now := time.Now()
+	_, err := l.DynamoDB.PutItem(&dynamodb.PutItemInput{
+		TableName: aws.String(l.TableName),
+		Item: map[string]*dynamodb.AttributeValue{
+			"AccountId": {S: aws.String(accountID)},
+			"Owner":     {S: aws.String(l.Owner)},
+			"ExpiresOn": {N: aws.String(strconv.FormatInt(now.Add(l.TTL).Unix(), 10))},
+		},
+		ConditionExpression: aws.String("attribute_not_exists(AccountId) OR ExpiresOn < :now"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":now": {N: aws.String(strconv.FormatInt(now.Unix(), 10))},
+		},
+	})
+	if isConditionalCheckFailed(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (l *dynamoLocker) Extend(accountID string) error {
+	_, err := l.DynamoDB.UpdateItem(&dynamodb.UpdateItemInput{
+		TableName: aws.String(l.TableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"AccountId": {S: aws.String(accountID)},
+		},
+		UpdateExpression:    aws.String("SET ExpiresOn = :expiresOn"),
+		ConditionExpression: aws.String("#owner = :owner"),
+		ExpressionAttributeNames: map[string]*string{
+			"#owner": aws.String("Owner"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":expiresOn": {N: aws.String(strconv.FormatInt(time.Now().Add(l.TTL).Unix(), 10))},
+			":owner":     {S: aws.String(l.Owner)},
+		},
+	})
+	return err
+}
+
+func (l *dynamoLocker) Unlock(accountID string) error {
+	_, err := l.DynamoDB.DeleteItem(&dynamodb.DeleteItemInput{
+		TableName: aws.String(l.TableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"AccountId": {S: aws.String(accountID)},
+		},
+		ConditionExpression: aws.String("#owner = :owner"),
+		ExpressionAttributeNames: map[string]*string{
+			"#owner": aws.String("Owner"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":owner": {S: aws.String(l.Owner)},
+		},
+	})
+	// The lock expired, and was taken by another worker
+	if isConditionalCheckFailed(err) {
+		return nil
+	}
+	return err
+}
+
+func isConditionalCheckFailed(err error) bool {
+	aerr, ok := err.(awserr.Error)
+	return ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException
+}
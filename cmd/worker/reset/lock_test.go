@@ -0,0 +1,94 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	awsMocks "github.com/Optum/dce/pkg/awsiface/mocks"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMemoryLocker(t *testing.T) {
+	locker := &memoryLocker{}
+
+	locked, err := locker.Lock("123456789012")
+	assert.Nil(t, err)
+	assert.True(t, locked)
+
+	locked, err = locker.Lock("123456789012")
+	assert.Nil(t, err)
+	assert.False(t, locked, "the account is already locked")
+
+	locked, err = locker.Lock("210987654321")
+	assert.Nil(t, err)
+	assert.True(t, locked, "other accounts can be locked")
+
+	assert.Nil(t, locker.Unlock("123456789012"))
+	locked, err = locker.Lock("123456789012")
+	assert.Nil(t, err)
+	assert.True(t, locked, "the account can be locked again once unlocked")
+}
+
+func TestDynamoLocker(t *testing.T) {
+	conditionFailed := awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "The conditional request failed", nil)
+
+	newLocker := func(dynamoSvc *awsMocks.DynamoDBAPI) *dynamoLocker {
+		return &dynamoLocker{
+			DynamoDB:  dynamoSvc,
+			TableName: "ResetLocks",
+			Owner:     "worker-1",
+			TTL:       5 * time.Minute,
+		}
+	}
+
+	t.Run("should lock an account which isn't locked", func(t *testing.T) {
+		dynamoSvc := &awsMocks.DynamoDBAPI{}
+		dynamoSvc.On("PutItem", mock.MatchedBy(func(input *dynamodb.PutItemInput) bool {
+			return *input.TableName == "ResetLocks" &&
+				*input.Item["AccountId"].S == "123456789012" &&
+				*input.Item["Owner"].S == "worker-1" &&
+				*input.ConditionExpression == "attribute_not_exists(AccountId) OR ExpiresOn < :now"
+		})).Return(&dynamodb.PutItemOutput{}, nil)
+
+		locked, err := newLocker(dynamoSvc).Lock("123456789012")
+		assert.Nil(t, err)
+		assert.True(t, locked)
+	})
+
+	t.Run("should not lock an account which is locked", func(t *testing.T) {
+		dynamoSvc := &awsMocks.DynamoDBAPI{}
+		dynamoSvc.On("PutItem", mock.Anything).Return(nil, conditionFailed)
+
+		locked, err := newLocker(dynamoSvc).Lock("123456789012")
+		assert.Nil(t, err)
+		assert.False(t, locked)
+	})
+
+	t.Run("should return errors locking the account", func(t *testing.T) {
+		dynamoSvc := &awsMocks.DynamoDBAPI{}
+		dynamoSvc.On("PutItem", mock.Anything).Return(nil, errors.New("throttled"))
+
+		locked, err := newLocker(dynamoSvc).Lock("123456789012")
+		assert.EqualError(t, err, "throttled")
+		assert.False(t, locked)
+	})
+
+	t.Run("should only extend and unlock its own locks", func(t *testing.T) {
+		dynamoSvc := &awsMocks.DynamoDBAPI{}
+		dynamoSvc.On("UpdateItem", mock.MatchedBy(func(input *dynamodb.UpdateItemInput) bool {
+			return *input.ExpressionAttributeValues[":owner"].S == "worker-1"
+		})).Return(&dynamodb.UpdateItemOutput{}, nil)
+		dynamoSvc.On("DeleteItem", mock.MatchedBy(func(input *dynamodb.DeleteItemInput) bool {
+			return *input.ExpressionAttributeValues[":owner"].S == "worker-1"
+		})).Return(nil, conditionFailed)
+
+		locker := newLocker(dynamoSvc)
+		assert.Nil(t, locker.Extend("123456789012"))
+		// The lock expired and was taken by another worker, so there's nothing to unlock
+		assert.Nil(t, locker.Unlock("123456789012"))
+	})
+}
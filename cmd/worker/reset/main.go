@@ -0,0 +1,118 @@
+// Package main is the Reset Worker, a long running alternative to
+// resetting accounts with CodeBuild. It polls the reset queue, and runs the
+// reset of each account (cmd/codebuild/reset) as a separate process.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/Optum/dce/pkg/config"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+	"github.com/google/uuid"
+)
+
+type configuration struct {
+	QueueURL          string `env:"RESET_SQS_URL" envDefault:"DefaultResetSQSUrl"`
+	Concurrency       int    `env:"RESET_WORKER_CONCURRENCY" envDefault:"10"`
+	VisibilityTimeout int64  `env:"RESET_WORKER_VISIBILITY_TIMEOUT" envDefault:"300"`
+	ResetCommand      string `env:"RESET_WORKER_COMMAND" envDefault:"./reset"`
+	ResetTimeout      int    `env:"RESET_WORKER_TIMEOUT_MINUTES" envDefault:"480"`
+	LockTable         string `env:"RESET_WORKER_LOCK_DB" envDefault:""`
+}
+
+// validate checks the settings the worker can't run with
+func (c *configuration) validate() error {
+	if c.Concurrency < 1 {
+		return fmt.Errorf("RESET_WORKER_CONCURRENCY must be at least 1, got %d", c.Concurrency)
+	}
+	if c.VisibilityTimeout < 1 {
+		return fmt.Errorf("RESET_WORKER_VISIBILITY_TIMEOUT must be at least 1 second, got %d", c.VisibilityTimeout)
+	}
+	if c.ResetTimeout < 1 {
+		return fmt.Errorf("RESET_WORKER_TIMEOUT_MINUTES must be at least 1 minute, got %d", c.ResetTimeout)
+	}
+	return nil
+}
+
+func main() {
+	cfgBldr := &config.ConfigurationBuilder{}
+	settings := &configuration{}
+	if err := cfgBldr.Unmarshal(settings); err != nil {
+		log.Fatalf("Could not load configuration: %s", err.Error())
+	}
+	if err := settings.validate(); err != nil {
+		log.Fatalf("Invalid configuration: %s", err)
+	}
+
+	// load up the values into the various settings...
+	err := cfgBldr.WithEnv("AWS_CURRENT_REGION", "AWS_CURRENT_REGION", "us-east-1").Build()
+	if err != nil {
+		log.Printf("Error: %+v", err)
+	}
+	svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+
+	_, err = svcBldr.
+		WithSQS().
+		WithDynamoDB().
+		Build()
+	if err != nil {
+		log.Fatalf("Could not build services: %s", err)
+	}
+
+	var sqsSvc sqsiface.SQSAPI
+	if err := svcBldr.Config.GetService(&sqsSvc); err != nil {
+		log.Fatalf("Could not get SQS service: %s", err)
+	}
+
+	// Lock accounts across every worker task, when there is a lock table
+	var locker accountLocker = &memoryLocker{}
+	if settings.LockTable != "" {
+		var dynamoSvc dynamodbiface.DynamoDBAPI
+		if err := svcBldr.Config.GetService(&dynamoSvc); err != nil {
+			log.Fatalf("Could not get DynamoDB service: %s", err)
+		}
+		locker = &dynamoLocker{
+			DynamoDB:  dynamoSvc,
+			TableName: settings.LockTable,
+			Owner:     uuid.New().String(),
+			TTL:       time.Duration(settings.VisibilityTimeout) * time.Second,
+		}
+	}
+
+	w := &worker{
+		SQS:               sqsSvc,
+		QueueURL:          settings.QueueURL,
+		Concurrency:       settings.Concurrency,
+		VisibilityTimeout: settings.VisibilityTimeout,
+		// Extend the visibility timeout well before it expires
+		HeartbeatInterval: time.Duration(settings.VisibilityTimeout) * time.Second / 3,
+		WaitTimeSeconds:   20,
+		Reset:             execReset(settings.ResetCommand, time.Duration(settings.ResetTimeout)*time.Minute),
+		Locker:            locker,
+	}
+
+	// Stop polling when the worker is stopped, and wait for the resets in
+	// progress until the worker is killed. ECS kills the worker after the stop
+	// timeout of its container, which is far shorter than a reset may take.
+	// The messages of resets which are killed become visible again once their
+	// heartbeats stop, and the resets are retried by another worker.
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		sig := <-signals
+		log.Printf("Received %s, stopped polling. Resets in progress are retried if the worker is killed before they complete", sig)
+		cancel()
+	}()
+
+	log.Printf("Starting reset worker for %s, with concurrency %d", settings.QueueURL, settings.Concurrency)
+	w.Run(ctx)
+	log.Println("Stopped reset worker")
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigurationValidate(t *testing.T) {
+	valid := configuration{Concurrency: 1, VisibilityTimeout: 300, ResetTimeout: 480}
+	assert.Nil(t, valid.validate())
+
+	noConcurrency := valid
+	noConcurrency.Concurrency = 0
+	assert.EqualError(t, noConcurrency.validate(), "RESET_WORKER_CONCURRENCY must be at least 1, got 0")
+
+	negativeConcurrency := valid
+	negativeConcurrency.Concurrency = -1
+	assert.EqualError(t, negativeConcurrency.validate(), "RESET_WORKER_CONCURRENCY must be at least 1, got -1")
+
+	noVisibilityTimeout := valid
+	noVisibilityTimeout.VisibilityTimeout = 0
+	assert.NotNil(t, noVisibilityTimeout.validate())
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/Optum/dce/pkg/account"
+	"github.com/Optum/dce/pkg/reset"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+)
+
+// resetFunc resets a single account, with the environment variables
+// which configure cmd/codebuild/reset
+type resetFunc func(ctx context.Context, accountID string, env []string) error
+
+// worker polls the reset queue, and resets up to Concurrency accounts at once.
+// Messages are kept invisible with heartbeats while their account is reset,
+// and are deleted once the reset succeeds. Failed resets are retried
+// once their message becomes visible again, until the queue moves the
+// message to its dead letter queue.
+// Each account is reset once at a time, using Locker. Messages for an account
+// which is already being reset are deleted, as are messages which can't be parsed.
+type worker struct {
+	SQS               sqsiface.SQSAPI
+	QueueURL          string
+	Concurrency       int
+	VisibilityTimeout int64
+	HeartbeatInterval time.Duration
+	WaitTimeSeconds   int64
+	Reset             resetFunc
+	// Locker defaults to locking accounts within the worker
+	Locker accountLocker
+
+	wg sync.WaitGroup
+}
+
+// Run polls the queue until the context is done,
+// then waits for the resets in progress to complete
+func (w *worker) Run(ctx context.Context) {
+	if w.Locker == nil {
+		w.Locker = &memoryLocker{}
+	}
+	slots := make(chan struct{}, w.Concurrency)
+
+	for ctx.Err() == nil {
+		// Wait for a free slot before polling,
+		// so messages aren't held by the worker before they can be handled
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			continue
+		}
+		free := int64(1 + cap(slots) - len(slots))
+		if free > 10 {
+			free = 10
+		}
+
+		output, err := w.SQS.ReceiveMessageWithContext(ctx, &sqs.ReceiveMessageInput{
+			QueueUrl:            aws.String(w.QueueURL),
+			MaxNumberOfMessages: aws.Int64(free),
+			VisibilityTimeout:   aws.Int64(w.VisibilityTimeout),
+			WaitTimeSeconds:     aws.Int64(w.WaitTimeSeconds),
+		})
+		if err != nil {
+			<-slots
+			if ctx.Err() == nil {
+				log.Printf("Failed to receive reset messages: %s", err)
+				sleep(ctx, 5*time.Second)
+			}
+			continue
+		}
+		if len(output.Messages) == 0 {
+			<-slots
+			continue
+		}
+
+		for i, message := range output.Messages {
+			// The first slot was taken before polling
+			if i > 0 {
+				slots <- struct{}{}
+			}
+			w.wg.Add(1)
+			go func(message *sqs.Message) {
+				defer func() { <-slots }()
+				defer w.wg.Done()
+				w.handle(message)
+			}(message)
+		}
+	}
+
+	w.wg.Wait()
+}
+
+// handle resets the account of a message, and deletes the message if the reset succeeds.
+// Resets aren't cancelled when the worker stops, so they can complete.
+func (w *worker) handle(message *sqs.Message) {
+	acct := &account.Account{}
+	if err := json.Unmarshal([]byte(aws.StringValue(message.Body)), &acct); err != nil || acct.ID == nil {
+		// Retrying the message won't make it any more readable
+		log.Printf("Deleting reset message %s, it could not be unmarshalled: %v", aws.StringValue(message.MessageId), err)
+		w.deleteMessage(message)
+		return
+	}
+
+	// Concurrent resets of the same account would run aws-nuke against each other.
+	// The reset in progress covers the duplicate message, and its own
+	// message is retried if it fails.
+	locked, err := w.Locker.Lock(*acct.ID)
+	if err != nil {
+		log.Printf("Failed to lock account %s, it will be retried: %s", *acct.ID, err)
+		return
+	}
+	if !locked {
+		log.Printf("Deleting reset message %s, account %s is already being reset", aws.StringValue(message.MessageId), *acct.ID)
+		w.deleteMessage(message)
+		return
+	}
+	defer func() {
+		if err := w.Locker.Unlock(*acct.ID); err != nil {
+			log.Printf("Failed to unlock account %s: %s", *acct.ID, err)
+		}
+	}()
+
+	env := []string{"RESET_BUILD_ID=reset-worker:" + aws.StringValue(message.MessageId)}
+	for _, v := range reset.AccountEnvironment(acct) {
+		env = append(env, v.Name+"="+v.Value)
+	}
+
+	heartbeatCtx, stopHeartbeat := context.WithCancel(context.Background())
+	go w.heartbeat(heartbeatCtx, *acct.ID, message)
+
+	log.Printf("Starting reset of account %s, message %s", *acct.ID, aws.StringValue(message.MessageId))
+	err = w.Reset(context.Background(), *acct.ID, env)
+	stopHeartbeat()
+	if err != nil {
+		log.Printf("Failed to reset account %s, it will be retried: %s", *acct.ID, err)
+		return
+	}
+
+	log.Printf("Completed reset of account %s", *acct.ID)
+	w.deleteMessage(message)
+}
+
+// deleteMessage removes the message from the queue
+func (w *worker) deleteMessage(message *sqs.Message) {
+	_, err := w.SQS.DeleteMessage(&sqs.DeleteMessageInput{
+		QueueUrl:      aws.String(w.QueueURL),
+		ReceiptHandle: message.ReceiptHandle,
+	})
+	if err != nil {
+		log.Printf("Failed to delete reset message %s: %s", aws.StringValue(message.MessageId), err)
+	}
+}
+
+// heartbeat extends the visibility timeout of the message and the lock of the
+// account until the context is done, so the account isn't reset again while
+// its reset is in progress
+func (w *worker) heartbeat(ctx context.Context, accountID string, message *sqs.Message) {
+	ticker := time.NewTicker(w.HeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_, err := w.SQS.ChangeMessageVisibility(&sqs.ChangeMessageVisibilityInput{
+				QueueUrl:          aws.String(w.QueueURL),
+				ReceiptHandle:     message.ReceiptHandle,
+				VisibilityTimeout: aws.Int64(w.VisibilityTimeout),
+			})
+			if err != nil {
+				log.Printf("Failed to extend visibility of reset message %s: %s", aws.StringValue(message.MessageId), err)
+			}
+			err = w.Locker.Extend(accountID)
+			if err != nil {
+				log.Printf("Failed to extend the reset lock of account %s: %s", accountID, err)
+			}
+		}
+	}
+}
+
+// execReset returns a resetFunc which runs the reset command for each account,
+// in a separate process. The reset command is configured for a single account
+// by its environment variables (RESET_ACCOUNT, RESET_REASON, ...), so concurrent
+// resets can't share one process. A separate process also keeps a timed out
+// reset from outliving its context.
+func execReset(command string, timeout time.Duration) resetFunc {
+	return func(ctx context.Context, accountID string, env []string) error {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		stdout := newPrefixWriter(os.Stdout, accountID)
+		defer stdout.Flush()
+		stderr := newPrefixWriter(os.Stderr, accountID)
+		defer stderr.Flush()
+
+		/*
+			#nosec CWE-78: The command is set by the worker configuration. I.e. it is not populated with data from external users.
+		*/
+		cmd := exec.CommandContext(ctx, command)
+		cmd.Env = append(os.Environ(), env...)
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
+		return cmd.Run()
+	}
+}
+
+// prefixWriter prefixes each line with the account ID,
+// so the output of concurrent resets can be told apart
+type prefixWriter struct {
+	out    io.Writer
+	prefix []byte
+	buf    []byte
+	mu     sync.Mutex
+}
+
+func newPrefixWriter(out io.Writer, accountID string) *prefixWriter {
+	return &prefixWriter{
+		out:    out,
+		prefix: []byte("[" + accountID + "] "),
+	}
+}
+
+// Write buffers partial lines, and writes complete lines
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		line := append(append([]byte{}, w.prefix...), w.buf[:i+1]...)
+		if _, err := w.out.Write(line); err != nil {
+			return len(p), err
+		}
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush writes any remaining partial line
+func (w *prefixWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.buf) > 0 {
+		_, _ = w.out.Write(append(append(append([]byte{}, w.prefix...), w.buf...), '\n'))
+		w.buf = nil
+	}
+}
+
+// sleep waits for the duration, or until the context is done
+func sleep(ctx context.Context, d time.Duration) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(d):
+	}
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	awsMocks "github.com/Optum/dce/pkg/awsiface/mocks"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testMessageBody = `{"id":"%[1]s","adminRoleArn":"arn:aws:iam::%[1]s:role/AdminRole","principalRoleArn":"arn:aws:iam::%[1]s:role/PrincipalRole","resetReason":"LeaseEnded"}`
+
+func testMessage(id string) *sqs.Message {
+	return testAccountMessage(id, "123456789012")
+}
+
+// testAccountMessage returns a message to reset the account
+func testAccountMessage(id string, accountID string) *sqs.Message {
+	return &sqs.Message{
+		MessageId:     aws.String(id),
+		ReceiptHandle: aws.String("receipt-" + id),
+		Body:          aws.String(fmt.Sprintf(testMessageBody, accountID)),
+	}
+}
+
+// mockReceive returns the messages on the first receive,
+// then waits for the worker to stop
+func mockReceive(sqsSvc *awsMocks.SQSAPI, messages ...*sqs.Message) {
+	sqsSvc.On("ReceiveMessageWithContext", mock.Anything, mock.Anything).
+		Return(&sqs.ReceiveMessageOutput{Messages: messages}, nil).
+		Once()
+	sqsSvc.On("ReceiveMessageWithContext", mock.Anything, mock.Anything).
+		Return(
+			func(ctx context.Context, input *sqs.ReceiveMessageInput, opts ...request.Option) *sqs.ReceiveMessageOutput {
+				<-ctx.Done()
+				return nil
+			},
+			func(ctx context.Context, input *sqs.ReceiveMessageInput, opts ...request.Option) error {
+				return ctx.Err()
+			},
+		)
+}
+
+func TestWorker(t *testing.T) {
+
+	t.Run("should reset the account and delete the message", func(t *testing.T) {
+		sqsSvc := &awsMocks.SQSAPI{}
+		mockReceive(sqsSvc, testMessage("msg1"))
+		sqsSvc.On("DeleteMessage", mock.MatchedBy(func(input *sqs.DeleteMessageInput) bool {
+			return *input.QueueUrl == "QueueURL" && *input.ReceiptHandle == "receipt-msg1"
+		})).Return(&sqs.DeleteMessageOutput{}, nil)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		var resetEnv []string
+		w := &worker{
+			SQS:               sqsSvc,
+			QueueURL:          "QueueURL",
+			Concurrency:       2,
+			VisibilityTimeout: 300,
+			HeartbeatInterval: time.Minute,
+			Reset: func(ctx context.Context, accountID string, env []string) error {
+				assert.Equal(t, "123456789012", accountID)
+				resetEnv = env
+				cancel()
+				return nil
+			},
+		}
+		w.Run(ctx)
+
+		assert.Equal(t, []string{
+			"RESET_BUILD_ID=reset-worker:msg1",
+			"RESET_ACCOUNT=123456789012",
+			"RESET_ACCOUNT_ADMIN_ROLE_NAME=AdminRole",
+			"RESET_ACCOUNT_PRINCIPAL_ROLE_NAME=PrincipalRole",
+			"RESET_REASON=LeaseEnded",
+		}, resetEnv)
+		sqsSvc.AssertCalled(t, "ReceiveMessageWithContext", mock.Anything, mock.MatchedBy(func(input *sqs.ReceiveMessageInput) bool {
+			return *input.MaxNumberOfMessages == 2 && *input.VisibilityTimeout == 300
+		}))
+		sqsSvc.AssertNumberOfCalls(t, "DeleteMessage", 1)
+	})
+
+	t.Run("should keep the message when the reset fails", func(t *testing.T) {
+		sqsSvc := &awsMocks.SQSAPI{}
+		mockReceive(sqsSvc, testMessage("msg1"))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		w := &worker{
+			SQS:               sqsSvc,
+			QueueURL:          "QueueURL",
+			Concurrency:       1,
+			VisibilityTimeout: 300,
+			HeartbeatInterval: time.Minute,
+			Reset: func(ctx context.Context, accountID string, env []string) error {
+				cancel()
+				return errors.New("nuke failed")
+			},
+		}
+		w.Run(ctx)
+
+		sqsSvc.AssertNotCalled(t, "DeleteMessage", mock.Anything)
+	})
+
+	t.Run("should delete messages which can't be unmarshalled", func(t *testing.T) {
+		sqsSvc := &awsMocks.SQSAPI{}
+		message := testMessage("msg1")
+		message.Body = aws.String("not json")
+		mockReceive(sqsSvc, message)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		sqsSvc.On("DeleteMessage", mock.MatchedBy(func(input *sqs.DeleteMessageInput) bool {
+			return *input.ReceiptHandle == "receipt-msg1"
+		})).Run(func(args mock.Arguments) {
+			cancel()
+		}).Return(&sqs.DeleteMessageOutput{}, nil)
+
+		w := &worker{
+			SQS:               sqsSvc,
+			QueueURL:          "QueueURL",
+			Concurrency:       1,
+			VisibilityTimeout: 300,
+			HeartbeatInterval: time.Minute,
+			Reset: func(ctx context.Context, accountID string, env []string) error {
+				assert.Fail(t, "reset should not run")
+				return nil
+			},
+		}
+		w.Run(ctx)
+
+		sqsSvc.AssertNumberOfCalls(t, "DeleteMessage", 1)
+	})
+
+	t.Run("should reset an account once at a time", func(t *testing.T) {
+		sqsSvc := &awsMocks.SQSAPI{}
+		mockReceive(sqsSvc, testMessage("msg1"), testMessage("msg2"))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		release := make(chan struct{})
+		// The duplicate message is deleted while the first reset is in progress
+		var duplicateDeleted sync.Once
+		sqsSvc.On("DeleteMessage", mock.Anything).Run(func(args mock.Arguments) {
+			duplicateDeleted.Do(func() { close(release) })
+		}).Return(&sqs.DeleteMessageOutput{}, nil)
+
+		resets := 0
+		w := &worker{
+			SQS:               sqsSvc,
+			QueueURL:          "QueueURL",
+			Concurrency:       2,
+			VisibilityTimeout: 300,
+			HeartbeatInterval: time.Minute,
+			Reset: func(ctx context.Context, accountID string, env []string) error {
+				resets++
+				<-release
+				cancel()
+				return nil
+			},
+		}
+		w.Run(ctx)
+
+		assert.Equal(t, 1, resets)
+		sqsSvc.AssertNumberOfCalls(t, "DeleteMessage", 2)
+	})
+
+	t.Run("should extend the visibility timeout during the reset", func(t *testing.T) {
+		sqsSvc := &awsMocks.SQSAPI{}
+		mockReceive(sqsSvc, testMessage("msg1"))
+		sqsSvc.On("ChangeMessageVisibility", mock.MatchedBy(func(input *sqs.ChangeMessageVisibilityInput) bool {
+			return *input.ReceiptHandle == "receipt-msg1" && *input.VisibilityTimeout == 300
+		})).Return(&sqs.ChangeMessageVisibilityOutput{}, nil)
+		sqsSvc.On("DeleteMessage", mock.Anything).Return(&sqs.DeleteMessageOutput{}, nil)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		w := &worker{
+			SQS:               sqsSvc,
+			QueueURL:          "QueueURL",
+			Concurrency:       1,
+			VisibilityTimeout: 300,
+			HeartbeatInterval: 5 * time.Millisecond,
+			Reset: func(ctx context.Context, accountID string, env []string) error {
+				time.Sleep(50 * time.Millisecond)
+				cancel()
+				return nil
+			},
+		}
+		w.Run(ctx)
+
+		sqsSvc.AssertCalled(t, "ChangeMessageVisibility", mock.Anything)
+	})
+
+	t.Run("should not receive more messages than it can reset", func(t *testing.T) {
+		sqsSvc := &awsMocks.SQSAPI{}
+		mockReceive(sqsSvc, testMessage("msg1"), testAccountMessage("msg2", "210987654321"))
+		sqsSvc.On("DeleteMessage", mock.Anything).Return(&sqs.DeleteMessageOutput{}, nil)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		release := make(chan struct{})
+		var started sync.WaitGroup
+		started.Add(2)
+		w := &worker{
+			SQS:               sqsSvc,
+			QueueURL:          "QueueURL",
+			Concurrency:       2,
+			VisibilityTimeout: 300,
+			HeartbeatInterval: time.Minute,
+			Reset: func(ctx context.Context, accountID string, env []string) error {
+				started.Done()
+				<-release
+				return nil
+			},
+		}
+
+		done := make(chan struct{})
+		go func() {
+			w.Run(ctx)
+			close(done)
+		}()
+
+		// Both slots are busy, so the worker must not poll again
+		started.Wait()
+		time.Sleep(20 * time.Millisecond)
+		sqsSvc.AssertNumberOfCalls(t, "ReceiveMessageWithContext", 1)
+
+		// Stopping the worker waits for the resets in progress
+		cancel()
+		select {
+		case <-done:
+			t.Fatal("worker stopped before its resets completed")
+		case <-time.After(20 * time.Millisecond):
+		}
+		close(release)
+		<-done
+		sqsSvc.AssertNumberOfCalls(t, "DeleteMessage", 2)
+	})
+}
+
+func TestPrefixWriter(t *testing.T) {
+	var out bytes.Buffer
+	w := newPrefixWriter(&out, "123456789012")
+
+	_, _ = w.Write([]byte("first line\nsecond "))
+	_, _ = w.Write([]byte("line\nlast"))
+	w.Flush()
+
+	assert.Equal(t, "[123456789012] first line\n[123456789012] second line\n[123456789012] last\n", out.String())
+}
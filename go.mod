@@ -4,11 +4,11 @@ go 1.13x
 
 require (
 	github.com/asaskevich/govalidator v0.0.0-20200108200545-475eaeb16496 // indirect
-	github.com/avast/retry-go v2.3.0+incompatible
 	github.com/aws/aws-lambda-go v1.11.1
 	github.com/aws/aws-sdk-go v1.25.36
 	github.com/awslabs/aws-lambda-go-api-proxy v0.5.0
 	github.com/caarlos0/env v3.5.0+incompatible
+	github.com/fatih/color v1.7.0 // indirect
 	github.com/go-ozzo/ozzo-validation v3.6.0+incompatible
 	github.com/go-sql-driver/mysql v1.4.1 // indirect
 	github.com/google/uuid v1.1.1
@@ -19,10 +19,12 @@ require (
 	github.com/imdario/mergo v0.3.8
 	github.com/mitchellh/mapstructure v1.1.2
 	github.com/oleiade/reflections v1.0.0 // indirect
-	github.com/pkg/errors v0.8.1
+	github.com/pkg/errors v0.9.1
 	github.com/pquerna/otp v1.2.0 // indirect
 	github.com/rebuy-de/aws-nuke v0.0.0-00010101000000-000000000000
-	github.com/stretchr/testify v1.4.0
+	github.com/stretchr/testify v1.5.1
+	github.com/xitongsys/parquet-go v1.5.3
+	github.com/xitongsys/parquet-go-source v0.0.0-20200326031722-42b453e70c3b
 	golang.org/x/crypto v0.0.0-20190418165655-df01cb2cc480 // indirect
 	golang.org/x/text v0.3.2 // indirect
 	google.golang.org/appengine v1.4.0 // indirect
@@ -30,6 +32,7 @@ require (
 	gopkg.in/check.v1 v1.0.0-20180628173108-788fd7840127 // indirect
 	gopkg.in/gomail.v2 v2.0.0-20160411212932-81ebce5c23df
 	gopkg.in/oleiade/reflections.v1 v1.0.0
+	gopkg.in/yaml.v2 v2.2.2
 )
 
 replace github.com/rebuy-de/aws-nuke => github.com/Optum/aws-nuke v1.1.0
@@ -0,0 +1,76 @@
+locals {
+  account_health_check_count = var.account_health_check_toggle == "true" ? 1 : 0
+}
+
+module "account_health_check_lambda" {
+  source          = "./lambda"
+  name            = "account_health_check-${var.namespace}"
+  namespace       = var.namespace
+  description     = "Orphans accounts which fail health checks, and recovers orphaned accounts which pass"
+  global_tags     = var.global_tags
+  handler         = "account_health_check"
+  alarm_topic_arn = aws_sns_topic.alarms_topic.arn
+
+  environment = {
+    DEBUG                          = "false"
+    ACCOUNT_ID                     = local.account_id
+    NAMESPACE                      = var.namespace
+    AWS_CURRENT_REGION             = var.aws_region
+    ACCOUNT_DB                     = aws_dynamodb_table.accounts.id
+    LEASE_DB                       = aws_dynamodb_table.leases.id
+    ARTIFACTS_BUCKET               = aws_s3_bucket.artifacts.id
+    RESET_SQS_URL                  = aws_sqs_queue.account_reset.id
+    PRINCIPAL_ROLE_NAME            = local.principal_role_name
+    PRINCIPAL_POLICY_NAME          = local.principal_policy_name
+    PRINCIPAL_IAM_DENY_TAGS        = join(",", var.principal_iam_deny_tags)
+    ALLOWED_REGIONS                = join(",", var.allowed_regions)
+    PRINCIPAL_MAX_SESSION_DURATION = 14400
+    TAG_ENVIRONMENT                = var.namespace == "prod" ? "PROD" : "NON-PROD"
+    TAG_APP_NAME                   = lookup(var.global_tags, "AppName")
+    PRINCIPAL_POLICY_S3_KEY        = aws_s3_bucket_object.principal_policy.key
+    HEALTH_CHECK_ACTIONS           = join(",", var.account_health_check_actions)
+    HEALTH_CHECK_FAILURE_THRESHOLD = var.account_health_check_failure_threshold
+  }
+}
+
+# Allow the health check to assume each account's admin role
+resource "aws_iam_role_policy" "account_health_check" {
+  role   = module.account_health_check_lambda.execution_role_name
+  policy = <<POLICY
+{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+        "Effect": "Allow",
+        "Action": [
+            "sts:AssumeRole"
+        ],
+        "Resource": "*"
+    }
+  ]
+}
+POLICY
+}
+
+resource "aws_cloudwatch_event_rule" "account_health_check" {
+  count               = local.account_health_check_count
+  name                = "account-health-check-${var.namespace}"
+  description         = "Checks the health of each account in the pool"
+  schedule_expression = var.account_health_check_rate_expression
+}
+
+resource "aws_cloudwatch_event_target" "account_health_check" {
+  count     = local.account_health_check_count
+  rule      = aws_cloudwatch_event_rule.account_health_check[0].name
+  target_id = "account_health_check_lambda"
+  arn       = module.account_health_check_lambda.arn
+}
+
+resource "aws_lambda_permission" "allow_cloudwatch_to_call_account_health_check_lambda" {
+  count         = local.account_health_check_count
+  statement_id  = "AllowExecutionFromCloudWatch"
+  action        = "lambda:InvokeFunction"
+  function_name = module.account_health_check_lambda.name
+  principal     = "events.amazonaws.com"
+  source_arn    = aws_cloudwatch_event_rule.account_health_check[0].arn
+}
@@ -12,14 +12,60 @@ module "account_pool_metrics_lambda" {
   alarm_topic_arn = aws_sns_topic.alarms_topic.arn
 
   environment = {
-    DEBUG              = "false"
-    ACCOUNT_ID         = local.account_id
-    NAMESPACE          = var.namespace
-    AWS_CURRENT_REGION = var.aws_region
-    ACCOUNT_DB         = aws_dynamodb_table.accounts.id
+    DEBUG                            = "false"
+    ACCOUNT_ID                       = local.account_id
+    NAMESPACE                        = var.namespace
+    AWS_CURRENT_REGION               = var.aws_region
+    ACCOUNT_DB                       = aws_dynamodb_table.accounts.id
+    ARTIFACTS_BUCKET                 = aws_s3_bucket.artifacts.id
+    RESET_SQS_URL                    = aws_sqs_queue.account_reset.id
+    ACCOUNT_CREATED_TOPIC_ARN        = aws_sns_topic.account_created.arn
+    PRINCIPAL_ROLE_NAME              = local.principal_role_name
+    PRINCIPAL_POLICY_NAME            = local.principal_policy_name
+    PRINCIPAL_IAM_DENY_TAGS          = join(",", var.principal_iam_deny_tags)
+    ALLOWED_REGIONS                  = join(",", var.allowed_regions)
+    PRINCIPAL_MAX_SESSION_DURATION   = 14400
+    TAG_ENVIRONMENT                  = var.namespace == "prod" ? "PROD" : "NON-PROD"
+    TAG_APP_NAME                     = lookup(var.global_tags, "AppName")
+    PRINCIPAL_POLICY_S3_KEY          = aws_s3_bucket_object.principal_policy.key
+    PROVISIONER_MIN_READY_ACCOUNTS   = var.account_pool_min_ready_accounts
+    PROVISIONER_MAX_ACCOUNTS_PER_RUN = var.account_pool_max_accounts_per_run
+    PROVISIONER_ACCOUNT_NAME_PREFIX  = "dce-${var.namespace}-"
+    PROVISIONER_ACCOUNT_EMAIL_FORMAT = var.account_pool_account_email_format
+    PROVISIONER_ADMIN_ROLE_NAME      = var.account_pool_admin_role_name
+    PROVISIONER_REQUEST_DB           = aws_dynamodb_table.provisioner_requests.id
   }
 }
 
+# Allow the account pool to be replenished with new accounts from AWS Organizations
+resource "aws_iam_role_policy" "account_pool_provisioner" {
+  role   = module.account_pool_metrics_lambda.execution_role_name
+  policy = <<POLICY
+{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+        "Effect": "Allow",
+        "Action": [
+            "organizations:CreateAccount",
+            "organizations:ListCreateAccountStatus",
+            "organizations:DescribeCreateAccountStatus",
+            "iam:CreateServiceLinkedRole"
+        ],
+        "Resource": "*"
+    },
+    {
+        "Effect": "Allow",
+        "Action": [
+            "sts:AssumeRole"
+        ],
+        "Resource": "*"
+    }
+  ]
+}
+POLICY
+}
+
 resource "aws_cloudwatch_event_rule" "every_x_minutes" {
   count               = local.account_pool_metrics_count
   name                = "every-one-minutes"
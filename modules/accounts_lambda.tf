@@ -20,6 +20,7 @@ module "accounts_lambda" {
     ACCOUNT_DB                     = aws_dynamodb_table.accounts.id
     ARTIFACTS_BUCKET               = aws_s3_bucket.artifacts.id
     LEASE_DB                       = aws_dynamodb_table.leases.id
+    RESET_RUN_DB                   = aws_dynamodb_table.reset_runs.id
     RESET_SQS_URL                  = aws_sqs_queue.account_reset.id
     ACCOUNT_CREATED_TOPIC_ARN      = aws_sns_topic.account_created.arn
     ACCOUNT_DELETED_TOPIC_ARN      = aws_sns_topic.account_deleted.arn
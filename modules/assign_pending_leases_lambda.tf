@@ -0,0 +1,84 @@
+module "assign_pending_leases" {
+  source          = "./lambda"
+  name            = "assign_pending_leases-${var.namespace}"
+  namespace       = var.namespace
+  description     = "Assigns Ready accounts to Pending leases"
+  global_tags     = var.global_tags
+  handler         = "assign_pending_leases"
+  alarm_topic_arn = aws_sns_topic.alarms_topic.arn
+
+  environment = {
+    DEBUG               = "false"
+    NAMESPACE           = var.namespace
+    AWS_CURRENT_REGION  = var.aws_region
+    ACCOUNT_DB          = aws_dynamodb_table.accounts.id
+    LEASE_DB            = aws_dynamodb_table.leases.id
+    LEASE_ADDED_TOPIC   = aws_sns_topic.lease_added.arn
+    LEASE_UPDATED_TOPIC = aws_sns_topic.lease_updated.arn
+  }
+}
+
+# Pending leases may be assigned an account whenever an account becomes
+# Ready, or a lease ends and frees up capacity reserved for it.
+# The schedule catches anything the events miss.
+resource "aws_sns_topic_subscription" "assign_pending_leases" {
+  topic_arn = aws_sns_topic.reset_complete.arn
+  protocol  = "lambda"
+  endpoint  = module.assign_pending_leases.arn
+}
+
+resource "aws_lambda_permission" "assign_pending_leases" {
+  statement_id  = "AllowExecutionFromSNS"
+  action        = "lambda:InvokeFunction"
+  function_name = module.assign_pending_leases.name
+  principal     = "sns.amazonaws.com"
+  source_arn    = aws_sns_topic.reset_complete.arn
+}
+
+resource "aws_sns_topic_subscription" "assign_pending_leases_account_created" {
+  topic_arn = aws_sns_topic.account_created.arn
+  protocol  = "lambda"
+  endpoint  = module.assign_pending_leases.arn
+}
+
+resource "aws_lambda_permission" "assign_pending_leases_account_created" {
+  statement_id  = "AllowExecutionFromAccountCreatedSNS"
+  action        = "lambda:InvokeFunction"
+  function_name = module.assign_pending_leases.name
+  principal     = "sns.amazonaws.com"
+  source_arn    = aws_sns_topic.account_created.arn
+}
+
+resource "aws_sns_topic_subscription" "assign_pending_leases_lease_locked" {
+  topic_arn = aws_sns_topic.lease_locked.arn
+  protocol  = "lambda"
+  endpoint  = module.assign_pending_leases.arn
+}
+
+resource "aws_lambda_permission" "assign_pending_leases_lease_locked" {
+  statement_id  = "AllowExecutionFromLeaseLockedSNS"
+  action        = "lambda:InvokeFunction"
+  function_name = module.assign_pending_leases.name
+  principal     = "sns.amazonaws.com"
+  source_arn    = aws_sns_topic.lease_locked.arn
+}
+
+resource "aws_cloudwatch_event_rule" "assign_pending_leases" {
+  name                = "assign-pending-leases-${var.namespace}"
+  description         = "Assigns Ready accounts to Pending leases"
+  schedule_expression = var.assign_pending_leases_schedule_expression
+}
+
+resource "aws_cloudwatch_event_target" "assign_pending_leases" {
+  rule      = aws_cloudwatch_event_rule.assign_pending_leases.name
+  target_id = "assign_pending_leases_lambda"
+  arn       = module.assign_pending_leases.arn
+}
+
+resource "aws_lambda_permission" "allow_cloudwatch_to_call_assign_pending_leases_lambda" {
+  statement_id  = "AllowExecutionFromCloudWatch"
+  action        = "lambda:InvokeFunction"
+  function_name = module.assign_pending_leases.name
+  principal     = "events.amazonaws.com"
+  source_arn    = aws_cloudwatch_event_rule.assign_pending_leases.arn
+}
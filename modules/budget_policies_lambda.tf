@@ -0,0 +1,17 @@
+module "budget_policies_lambda" {
+  source          = "./lambda"
+  name            = "budget_policies-${var.namespace}"
+  namespace       = var.namespace
+  description     = "API /budget-policies endpoints"
+  global_tags     = var.global_tags
+  handler         = "budget_policies"
+  alarm_topic_arn = aws_sns_topic.alarms_topic.arn
+
+  environment = {
+    DEBUG                = "false"
+    NAMESPACE            = var.namespace
+    AWS_CURRENT_REGION   = var.aws_region
+    BUDGET_POLICY_DB     = aws_dynamodb_table.budget_policies.id
+    COGNITO_USER_POOL_ID = module.api_gateway_authorizer.user_pool_id
+  }
+}
@@ -0,0 +1,64 @@
+locals {
+  chargeback_count = var.chargeback_toggle == "true" ? 1 : 0
+}
+
+module "chargeback_lambda" {
+  source          = "./lambda"
+  name            = "chargeback-${var.namespace}"
+  namespace       = var.namespace
+  description     = "Writes a monthly chargeback report of the spend of each lease to the artifacts bucket"
+  global_tags     = var.global_tags
+  handler         = "chargeback"
+  alarm_topic_arn = aws_sns_topic.alarms_topic.arn
+
+  environment = {
+    DEBUG                      = "false"
+    NAMESPACE                  = var.namespace
+    AWS_CURRENT_REGION         = var.aws_region
+    LEASE_DB                   = aws_dynamodb_table.leases.id
+    USAGE_DB                   = aws_dynamodb_table.usage.id
+    ARTIFACTS_BUCKET           = aws_s3_bucket.artifacts.id
+    CHARGEBACK_REPORT_PREFIX   = "chargeback"
+    CHARGEBACK_COST_CENTER_KEY = var.chargeback_cost_center_key
+    CHARGEBACK_FROM_EMAIL      = var.budget_notification_from_email
+    CHARGEBACK_TO_EMAILS       = join(",", var.chargeback_report_emails)
+  }
+}
+
+// Allow the chargeback lambda to email reports with SES
+resource "aws_iam_role_policy" "chargeback_ses" {
+  role   = module.chargeback_lambda.execution_role_name
+  policy = <<POLICY
+{
+    "Version": "2012-10-17",
+    "Statement": [{
+      "Effect": "Allow",
+      "Action": ["ses:SendRawEmail"],
+      "Resource": "*"
+    }]
+}
+POLICY
+}
+
+resource "aws_cloudwatch_event_rule" "chargeback" {
+  count               = local.chargeback_count
+  name                = "chargeback-${var.namespace}"
+  description         = "Writes the chargeback report of the previous month"
+  schedule_expression = var.chargeback_schedule_expression
+}
+
+resource "aws_cloudwatch_event_target" "chargeback" {
+  count     = local.chargeback_count
+  rule      = aws_cloudwatch_event_rule.chargeback[0].name
+  target_id = "chargeback_lambda"
+  arn       = module.chargeback_lambda.arn
+}
+
+resource "aws_lambda_permission" "allow_cloudwatch_to_call_chargeback_lambda" {
+  count         = local.chargeback_count
+  statement_id  = "AllowExecutionFromCloudWatch"
+  action        = "lambda:InvokeFunction"
+  function_name = module.chargeback_lambda.name
+  principal     = "events.amazonaws.com"
+  source_arn    = aws_cloudwatch_event_rule.chargeback[0].arn
+}
@@ -22,6 +22,14 @@ resource "aws_dynamodb_table" "accounts" {
     write_capacity  = var.accounts_table_wcu
   }
 
+  global_secondary_index {
+    name            = "Pool"
+    hash_key        = "Pool"
+    projection_type = "ALL"
+    read_capacity   = var.accounts_table_rcu
+    write_capacity  = var.accounts_table_wcu
+  }
+
   server_side_encryption {
     enabled = true
   }
@@ -42,6 +50,13 @@ resource "aws_dynamodb_table" "accounts" {
     type = "S"
   }
 
+  # Account pool, used to lease specific classes of accounts
+  # (eg. "gov-region", "network-enabled")
+  attribute {
+    name = "Pool"
+    type = "S"
+  }
+
   tags = var.global_tags
   /*
   Other attributes:
@@ -137,6 +152,16 @@ resource "aws_dynamodb_table" "usage" {
     enabled = true
   }
 
+  # Lists usage by date range, one month at a time
+  global_secondary_index {
+    name            = "StartMonth"
+    hash_key        = "StartMonth"
+    range_key       = "StartDate"
+    projection_type = "ALL"
+    read_capacity   = var.usage_table_rcu
+    write_capacity  = var.usage_table_wcu
+  }
+
   # User Principal ID
   attribute {
     name = "PrincipalId"
@@ -149,6 +174,12 @@ resource "aws_dynamodb_table" "usage" {
     type = "N"
   }
 
+  # Month of the start date, e.g. "2019-10"
+  attribute {
+    name = "StartMonth"
+    type = "S"
+  }
+
   # TTL enabled attribute
   ttl {
     attribute_name = "TimeToLive"
@@ -157,3 +188,130 @@ resource "aws_dynamodb_table" "usage" {
 
   tags = var.global_tags
 }
+
+# Lease Usage table
+# Usage records are shared by all the leases of a principal,
+# so the usage of each lease is also recorded here, by lease ID
+resource "aws_dynamodb_table" "lease_usage" {
+  name           = "LeaseUsage${local.table_suffix}"
+  read_capacity  = var.usage_table_rcu
+  write_capacity = var.usage_table_wcu
+  hash_key       = "LeaseId"
+  range_key      = "StartDate"
+
+  server_side_encryption {
+    enabled = true
+  }
+
+  # Lease ID
+  attribute {
+    name = "LeaseId"
+    type = "S"
+  }
+
+  # AWS usage cost amount for start date as epoch timestamp
+  attribute {
+    name = "StartDate"
+    type = "N"
+  }
+
+  # TTL enabled attribute
+  ttl {
+    attribute_name = "TimeToLive"
+    enabled        = true
+  }
+
+  tags = var.global_tags
+}
+
+# Budget Policy table
+# Overrides the default budget limits for a principal or Cognito group.
+# Keyed by the principal or group, so each may only have a single policy.
+resource "aws_dynamodb_table" "budget_policies" {
+  name           = "BudgetPolicies${local.table_suffix}"
+  read_capacity  = var.budget_policies_table_rcu
+  write_capacity = var.budget_policies_table_wcu
+  hash_key       = "Target"
+
+  global_secondary_index {
+    name            = "BudgetPolicyId"
+    hash_key        = "Id"
+    projection_type = "ALL"
+    read_capacity   = var.budget_policies_table_rcu
+    write_capacity  = var.budget_policies_table_wcu
+  }
+
+  server_side_encryption {
+    enabled = true
+  }
+
+  # Principal or group the policy applies to, eg. "principal:jdoe" or "group:Interns"
+  attribute {
+    name = "Target"
+    type = "S"
+  }
+
+  # Budget Policy ID
+  attribute {
+    name = "Id"
+    type = "S"
+  }
+
+  tags = var.global_tags
+}
+
+# Reset Run table
+# Records each reset of an account, and its result
+resource "aws_dynamodb_table" "reset_runs" {
+  name           = "ResetRuns${local.table_suffix}"
+  read_capacity  = var.reset_runs_table_rcu
+  write_capacity = var.reset_runs_table_wcu
+  hash_key       = "AccountId"
+  range_key      = "StartedOn"
+
+  server_side_encryption {
+    enabled = true
+  }
+
+  # AWS Account ID
+  attribute {
+    name = "AccountId"
+    type = "S"
+  }
+
+  # Reset start date, as an epoch timestamp
+  attribute {
+    name = "StartedOn"
+    type = "N"
+  }
+
+  tags = var.global_tags
+}
+
+# Provisioner Requests table
+# Records the requests to create accounts made by the account pool provisioner,
+# so each created account is only registered once
+resource "aws_dynamodb_table" "provisioner_requests" {
+  name           = "ProvisionerRequests${local.table_suffix}"
+  read_capacity  = var.provisioner_requests_table_rcu
+  write_capacity = var.provisioner_requests_table_wcu
+  hash_key       = "Id"
+
+  server_side_encryption {
+    enabled = true
+  }
+
+  # Organizations CreateAccountStatus ID
+  attribute {
+    name = "Id"
+    type = "S"
+  }
+
+  # Requests are listed by Organizations for 90 days
+  ttl {
+    attribute_name = "TimeToLive"
+    enabled        = true
+  }
+
+  tags = var.global_tags
+}
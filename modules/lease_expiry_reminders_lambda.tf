@@ -0,0 +1,82 @@
+locals {
+  lease_expiry_reminders_count = var.lease_expiry_reminders_toggle == "true" ? 1 : 0
+}
+
+module "lease_expiry_reminders_lambda" {
+  source          = "./lambda"
+  name            = "lease_expiry_reminders-${var.namespace}"
+  namespace       = var.namespace
+  description     = "Reminds principals before their leases expire"
+  global_tags     = var.global_tags
+  handler         = "lease_expiry_reminders"
+  alarm_topic_arn = aws_sns_topic.alarms_topic.arn
+
+  environment = {
+    DEBUG                                  = "false"
+    NAMESPACE                              = var.namespace
+    AWS_CURRENT_REGION                     = var.aws_region
+    ACCOUNT_DB                             = aws_dynamodb_table.accounts.id
+    LEASE_DB                               = aws_dynamodb_table.leases.id
+    LEASE_ADDED_TOPIC                      = aws_sns_topic.lease_added.arn
+    LEASE_UPDATED_TOPIC                    = aws_sns_topic.lease_updated.arn
+    LEASE_EXPIRY_REMINDERS                 = join(",", var.lease_expiry_reminders)
+    LEASE_EXPIRY_REMINDER_FROM_EMAIL       = var.budget_notification_from_email
+    LEASE_EXPIRY_REMINDER_TEMPLATE_SUBJECT = var.lease_expiry_reminder_template_subject
+    LEASE_EXPIRY_REMINDER_TEMPLATE_TEXT    = var.lease_expiry_reminder_template_text
+    LEASE_EXPIRY_REMINDER_TEMPLATE_HTML    = var.lease_expiry_reminder_template_html
+    API_BASE_URL                           = aws_api_gateway_stage.api.invoke_url
+    LEASE_EXTENSION_SECRET_PARAMETER       = module.ssm_parameter_names.lease_extension_secret
+    LEASE_EXTENSION_PERIOD                 = var.lease_extension_period
+  }
+}
+
+// Signs the one-click extension links in expiry reminders.
+// The lambdas read it from SSM, so it isn't visible in their configuration
+resource "random_password" "lease_extension_secret" {
+  length  = 32
+  special = false
+}
+
+resource "aws_ssm_parameter" "lease_extension_secret" {
+  name  = module.ssm_parameter_names.lease_extension_secret
+  type  = "SecureString"
+  value = random_password.lease_extension_secret.result
+}
+
+// Allow the lease_expiry_reminders lambda to send emails with SES
+resource "aws_iam_role_policy" "lease_expiry_reminders_ses" {
+  role   = module.lease_expiry_reminders_lambda.execution_role_name
+  policy = <<POLICY
+{
+    "Version": "2012-10-17",
+    "Statement": [{
+      "Effect": "Allow",
+      "Action": ["ses:SendEmail"],
+      "Resource": "*"
+    }]
+}
+POLICY
+}
+
+resource "aws_cloudwatch_event_rule" "lease_expiry_reminders" {
+  count               = local.lease_expiry_reminders_count
+  name                = "lease-expiry-reminders-${var.namespace}"
+  description         = "Reminds principals before their leases expire"
+  schedule_expression = var.lease_expiry_reminders_schedule_expression
+}
+
+resource "aws_cloudwatch_event_target" "lease_expiry_reminders" {
+  count     = local.lease_expiry_reminders_count
+  rule      = aws_cloudwatch_event_rule.lease_expiry_reminders[0].name
+  target_id = "lease_expiry_reminders_lambda"
+  arn       = module.lease_expiry_reminders_lambda.arn
+}
+
+resource "aws_lambda_permission" "allow_cloudwatch_to_call_lease_expiry_reminders_lambda" {
+  count         = local.lease_expiry_reminders_count
+  statement_id  = "AllowExecutionFromCloudWatch"
+  action        = "lambda:InvokeFunction"
+  function_name = module.lease_expiry_reminders_lambda.name
+  principal     = "events.amazonaws.com"
+  source_arn    = aws_cloudwatch_event_rule.lease_expiry_reminders[0].arn
+}
@@ -15,14 +15,22 @@ module "leases_lambda" {
     ACCOUNT_DB                         = aws_dynamodb_table.accounts.id
     LEASE_DB                           = aws_dynamodb_table.leases.id
     LEASE_ADDED_TOPIC                  = aws_sns_topic.lease_added.arn
+    LEASE_UPDATED_TOPIC                = aws_sns_topic.lease_updated.arn
     DECOMMISSION_TOPIC                 = aws_sns_topic.lease_removed.arn
     COGNITO_USER_POOL_ID               = module.api_gateway_authorizer.user_pool_id
     COGNITO_ROLES_ATTRIBUTE_ADMIN_NAME = var.cognito_roles_attribute_admin_name
     MAX_LEASE_BUDGET_AMOUNT            = var.max_lease_budget_amount
     MAX_LEASE_PERIOD                   = var.max_lease_period
+    MAX_ACTIVE_LEASES_PER_PRINCIPAL    = var.max_active_leases_per_principal
     PRINCIPAL_BUDGET_AMOUNT            = var.principal_budget_amount
     PRINCIPAL_BUDGET_PERIOD            = var.principal_budget_period
     USAGE_CACHE_DB                     = aws_dynamodb_table.usage.id
+    LEASE_USAGE_DB                     = aws_dynamodb_table.lease_usage.id
+    BUDGET_POLICY_DB                   = aws_dynamodb_table.budget_policies.id
+    CURRENCY_EXCHANGE_RATES            = jsonencode(var.currency_exchange_rates)
+    LEASE_EXTENSION_SECRET_PARAMETER   = module.ssm_parameter_names.lease_extension_secret
+    LEASE_EXTENSION_PERIOD             = var.lease_extension_period
+    NOTIFICATION_TARGET_ALLOWED_HOSTS  = join(",", var.notification_target_allowed_hosts)
   }
 }
 
@@ -31,6 +39,11 @@ resource "aws_sns_topic" "lease_added" {
   tags = var.global_tags
 }
 
+resource "aws_sns_topic" "lease_updated" {
+  name = "lease-updated-${var.namespace}"
+  tags = var.global_tags
+}
+
 resource "aws_sns_topic" "lease_removed" {
   name = "lease-removed-${var.namespace}"
   tags = var.global_tags
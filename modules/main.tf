@@ -7,6 +7,10 @@ provider "aws" {
   version = "2.43.0"
 }
 
+provider "random" {
+  version = "~> 2.2"
+}
+
 # Current AWS Account User
 data "aws_caller_identity" "current" {
 }
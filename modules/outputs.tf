@@ -22,6 +22,14 @@ output "usage_table_arn" {
   value = aws_dynamodb_table.usage.arn
 }
 
+output "lease_usage_table_name" {
+  value = aws_dynamodb_table.lease_usage.name
+}
+
+output "lease_usage_table_arn" {
+  value = aws_dynamodb_table.lease_usage.arn
+}
+
 output "sqs_reset_queue_url" {
   value = aws_sqs_queue.account_reset.id
 }
@@ -30,6 +38,14 @@ output "sqs_reset_queue_arn" {
   value = aws_sqs_queue.account_reset.arn
 }
 
+output "sqs_reset_dlq_url" {
+  value = aws_sqs_queue.account_reset_dlq.id
+}
+
+output "sqs_reset_dlq_arn" {
+  value = aws_sqs_queue.account_reset_dlq.arn
+}
+
 output "artifacts_bucket_name" {
   value = aws_s3_bucket.artifacts.id
 }
@@ -62,6 +78,14 @@ output "lease_removed_topic_arn" {
   value = aws_sns_topic.lease_removed.arn
 }
 
+output "lease_updated_topic_id" {
+  value = aws_sns_topic.lease_updated.id
+}
+
+output "lease_updated_topic_arn" {
+  value = aws_sns_topic.lease_updated.arn
+}
+
 output "lease_locked_topic_id" {
   value = aws_sns_topic.lease_locked.id
 }
@@ -3,6 +3,36 @@ resource "aws_sqs_queue" "account_reset" {
   name                       = "account-reset-${var.namespace}"
   tags                       = var.global_tags
   visibility_timeout_seconds = 30
+  # Stop retrying resets which keep failing
+  redrive_policy = jsonencode({
+    deadLetterTargetArn = aws_sqs_queue.account_reset_dlq.arn
+    maxReceiveCount     = var.reset_max_receive_count
+  })
+}
+
+# Reset messages which failed reset_max_receive_count times
+resource "aws_sqs_queue" "account_reset_dlq" {
+  name                      = "account-reset-dlq-${var.namespace}"
+  tags                      = var.global_tags
+  message_retention_seconds = 1209600
+}
+
+resource "aws_cloudwatch_metric_alarm" "account_reset_dlq" {
+  alarm_name          = "account-reset-dlq-${var.namespace}"
+  alarm_description   = "Account resets failed too many times, and were moved to the dead letter queue"
+  comparison_operator = "GreaterThanOrEqualToThreshold"
+  evaluation_periods  = 1
+  metric_name         = "ApproximateNumberOfMessagesVisible"
+  namespace           = "AWS/SQS"
+  period              = 300
+  statistic           = "Maximum"
+  threshold           = 1
+  alarm_actions       = [aws_sns_topic.alarms_topic.arn]
+  tags                = var.global_tags
+
+  dimensions = {
+    QueueName = aws_sqs_queue.account_reset_dlq.name
+  }
 }
 
 # Lambda function to add all NotReady accounts to the reset queue
@@ -75,7 +105,8 @@ resource "aws_lambda_event_source_mapping" "process_reset_events_from_sqs" {
   event_source_arn = aws_sqs_queue.account_reset.arn
   function_name    = module.process_reset_queue.arn
   batch_size       = 1
-  enabled          = true
+  # The reset worker polls the queue itself, when it is enabled
+  enabled = var.reset_executor == "CodeBuild"
 }
 
 # Lambda code deployments are managed outside of Terraform,
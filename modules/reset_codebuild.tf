@@ -95,6 +95,26 @@ resource "aws_codebuild_project" "reset_build" {
       type  = "PLAINTEXT"
     }
 
+    environment_variable {
+      name  = "RESET_RUN_DB"
+      value = aws_dynamodb_table.reset_runs.id
+      type  = "PLAINTEXT"
+    }
+
+    environment_variable {
+      name = "RESET_REASON"
+      // This value will be passed in by the process_reset_queue
+      // lambda, from the reset queue message
+      value = "Unknown"
+      type  = "PLAINTEXT"
+    }
+
+    environment_variable {
+      name  = "RESET_REPORT_BUCKET"
+      value = aws_s3_bucket.artifacts.id
+      type  = "PLAINTEXT"
+    }
+
     environment_variable {
       name  = "AWS_CURRENT_REGION"
       value = var.aws_region
@@ -174,6 +194,7 @@ resource "aws_iam_role_policy" "codebuild_reset" {
         "dynamodb:GetItem",
         "dynamodb:Scan",
         "dynamodb:Query",
+        "dynamodb:PutItem",
         "dynamodb:UpdateItem",
         "sns:Publish"
       ]
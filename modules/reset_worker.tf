@@ -0,0 +1,232 @@
+/**
+ * Run account resets with a long running worker on ECS Fargate,
+ * instead of a CodeBuild build for every reset.
+ * Enabled with `reset_executor = "Worker"`.
+ *
+ * The worker polls the reset queue, so the process_reset_queue
+ * lambda is disabled while the worker is enabled.
+ */
+
+locals {
+  reset_worker_count = var.reset_executor == "Worker" ? 1 : 0
+
+  reset_worker_environment = {
+    RESET_SQS_URL                       = aws_sqs_queue.account_reset.id
+    RESET_WORKER_CONCURRENCY            = var.reset_worker_concurrency
+    RESET_WORKER_VISIBILITY_TIMEOUT     = 300
+    RESET_WORKER_TIMEOUT_MINUTES        = aws_codebuild_project.reset_build.build_timeout
+    RESET_ACCOUNT_PRINCIPAL_POLICY_NAME = local.principal_policy_name
+    RESET_NUKE_TEMPLATE_BUCKET          = var.reset_nuke_template_bucket
+    RESET_NUKE_TEMPLATE_KEY             = var.reset_nuke_template_key
+    ACCOUNT_DB                          = aws_dynamodb_table.accounts.id
+    LEASE_DB                            = aws_dynamodb_table.leases.id
+    RESET_RUN_DB                        = aws_dynamodb_table.reset_runs.id
+    RESET_WORKER_LOCK_DB                = aws_dynamodb_table.reset_locks[0].id
+    RESET_REPORT_BUCKET                 = aws_s3_bucket.artifacts.id
+    AWS_CURRENT_REGION                  = var.aws_region
+    RESET_NUKE_TOGGLE                   = var.reset_nuke_toggle
+    RESET_NUKE_REGIONS                  = join(",", var.allowed_regions)
+    RESET_COMPLETE_TOPIC_ARN            = aws_sns_topic.reset_complete.arn
+  }
+}
+
+# Each account is reset by one worker task at a time
+resource "aws_dynamodb_table" "reset_locks" {
+  count        = local.reset_worker_count
+  name         = "ResetLocks${local.table_suffix}"
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "AccountId"
+
+  server_side_encryption {
+    enabled = true
+  }
+
+  attribute {
+    name = "AccountId"
+    type = "S"
+  }
+
+  # Expired locks are no longer held, and are cleaned up by DynamoDB
+  ttl {
+    attribute_name = "ExpiresOn"
+    enabled        = true
+  }
+
+  tags = var.global_tags
+}
+
+resource "aws_ecs_cluster" "reset_worker" {
+  count = local.reset_worker_count
+  name  = "account-reset-${var.namespace}"
+  tags  = var.global_tags
+}
+
+resource "aws_cloudwatch_log_group" "reset_worker" {
+  count             = local.reset_worker_count
+  name              = "/ecs/account-reset-worker-${var.namespace}"
+  retention_in_days = 90
+  tags              = var.global_tags
+}
+
+resource "aws_ecs_task_definition" "reset_worker" {
+  count                    = local.reset_worker_count
+  family                   = "account-reset-worker-${var.namespace}"
+  requires_compatibilities = ["FARGATE"]
+  network_mode             = "awsvpc"
+  cpu                      = var.reset_worker_cpu
+  memory                   = var.reset_worker_memory
+  execution_role_arn       = aws_iam_role.reset_worker_execution[0].arn
+  task_role_arn            = aws_iam_role.reset_worker[0].arn
+
+  container_definitions = jsonencode([
+    {
+      name      = "reset-worker"
+      image     = var.reset_worker_image
+      essential = true
+      # Give short resets in progress time to complete, when the worker is
+      # stopped. 120 seconds is the most Fargate allows, so longer resets are
+      # killed, and retried from the queue once their messages become visible.
+      stopTimeout = 120
+      environment = [
+        for name, value in local.reset_worker_environment : {
+          name  = name
+          value = tostring(value)
+        }
+      ]
+      logConfiguration = {
+        logDriver = "awslogs"
+        options = {
+          awslogs-group         = aws_cloudwatch_log_group.reset_worker[0].name
+          awslogs-region        = var.aws_region
+          awslogs-stream-prefix = "reset-worker"
+        }
+      }
+    }
+  ])
+
+  tags = var.global_tags
+}
+
+resource "aws_ecs_service" "reset_worker" {
+  count           = local.reset_worker_count
+  name            = "account-reset-worker-${var.namespace}"
+  cluster         = aws_ecs_cluster.reset_worker[0].id
+  task_definition = aws_ecs_task_definition.reset_worker[0].arn
+  desired_count   = var.reset_worker_count
+  launch_type     = "FARGATE"
+
+  network_configuration {
+    subnets          = var.reset_worker_subnet_ids
+    assign_public_ip = var.reset_worker_assign_public_ip
+  }
+}
+
+# Role used by ECS to pull the image and write logs
+resource "aws_iam_role" "reset_worker_execution" {
+  count = local.reset_worker_count
+  name  = "account-reset-worker-execution-${var.namespace}"
+
+  assume_role_policy = <<JSON
+{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Effect": "Allow",
+      "Principal": {
+        "Service": "ecs-tasks.amazonaws.com"
+      },
+      "Action": "sts:AssumeRole"
+    }
+  ]
+}
+JSON
+
+  tags = var.global_tags
+}
+
+resource "aws_iam_role_policy_attachment" "reset_worker_execution" {
+  count      = local.reset_worker_count
+  role       = aws_iam_role.reset_worker_execution[0].name
+  policy_arn = "arn:aws:iam::aws:policy/service-role/AmazonECSTaskExecutionRolePolicy"
+}
+
+# Role used by the worker to reset accounts,
+# with the same permissions as the reset CodeBuild
+resource "aws_iam_role" "reset_worker" {
+  count = local.reset_worker_count
+  name  = "account-reset-worker-${var.namespace}"
+
+  assume_role_policy = <<JSON
+{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Effect": "Allow",
+      "Principal": {
+        "Service": "ecs-tasks.amazonaws.com"
+      },
+      "Action": "sts:AssumeRole"
+    }
+  ]
+}
+JSON
+
+  tags = var.global_tags
+}
+
+resource "aws_iam_role_policy" "reset_worker" {
+  count = local.reset_worker_count
+  role  = aws_iam_role.reset_worker[0].name
+  name  = "account-reset-worker-${var.namespace}"
+
+  policy = <<POLICY
+{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Effect": "Allow",
+      "Resource": [
+        "*"
+      ],
+      "Action": [
+        "sts:AssumeRole",
+        "ssm:GetParameter",
+        "dynamodb:GetItem",
+        "dynamodb:Scan",
+        "dynamodb:Query",
+        "dynamodb:PutItem",
+        "dynamodb:UpdateItem",
+        "dynamodb:DeleteItem",
+        "sns:Publish"
+      ]
+    },
+    {
+      "Effect": "Allow",
+      "Action": [
+        "sqs:ReceiveMessage",
+        "sqs:DeleteMessage",
+        "sqs:ChangeMessageVisibility"
+      ],
+      "Resource": [
+        "${aws_sqs_queue.account_reset.arn}"
+      ]
+    },
+    {
+      "Effect": "Allow",
+      "Action": [
+        "s3:PutObject",
+        "s3:GetObject",
+        "s3:GetObjectVersion",
+        "s3:GetBucketAcl",
+        "s3:GetBucketLocation"
+      ],
+      "Resource": [
+        "${aws_s3_bucket.artifacts.arn}",
+        "${aws_s3_bucket.artifacts.arn}/*"
+      ]
+    }
+  ]
+}
+POLICY
+
+}
@@ -16,4 +16,8 @@ output user_pool_id {
 
 output user_pool_endpoint {
   value = "/${var.namespace}/auth/user_pool_endpoint"
+}
+
+output lease_extension_secret {
+  value = "/${var.namespace}/leases/extension_secret"
 }
\ No newline at end of file
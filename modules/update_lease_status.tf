@@ -2,7 +2,7 @@ module "fan_out_update_lease_status_lambda" {
   source          = "./lambda"
   name            = "fan_out_update_lease_status-${var.namespace}"
   namespace       = var.namespace
-  description     = "Initiates the budget check lambda. Invokes a check-budget lamdba for each active lease"
+  description     = "Activates scheduled leases, and initiates the budget check lambda. Invokes a check-budget lamdba for each active lease"
   global_tags     = var.global_tags
   handler         = "fan_out_update_lease_status"
   alarm_topic_arn = aws_sns_topic.alarms_topic.arn
@@ -12,6 +12,8 @@ module "fan_out_update_lease_status_lambda" {
     ACCOUNT_DB                        = aws_dynamodb_table.accounts.id
     LEASE_DB                          = aws_dynamodb_table.leases.id
     UPDATE_LEASE_STATUS_FUNCTION_NAME = module.update_lease_status_lambda.name
+    LEASE_ADDED_TOPIC                 = aws_sns_topic.lease_added.arn
+    LEASE_UPDATED_TOPIC               = aws_sns_topic.lease_updated.arn
   }
 }
 
@@ -35,6 +37,7 @@ module "update_lease_status_lambda" {
     ACCOUNT_DB                                = aws_dynamodb_table.accounts.id
     LEASE_DB                                  = aws_dynamodb_table.leases.id
     USAGE_CACHE_DB                            = aws_dynamodb_table.usage.id
+    LEASE_USAGE_DB                            = aws_dynamodb_table.lease_usage.id
     RESET_QUEUE_URL                           = aws_sqs_queue.account_reset.id
     LEASE_LOCKED_TOPIC_ARN                    = aws_sns_topic.lease_locked.arn
     BUDGET_NOTIFICATION_FROM_EMAIL            = var.budget_notification_from_email
@@ -43,12 +46,38 @@ module "update_lease_status_lambda" {
     BUDGET_NOTIFICATION_TEMPLATE_TEXT         = var.budget_notification_template_text
     BUDGET_NOTIFICATION_TEMPLATE_SUBJECT      = var.budget_notification_template_subject
     BUDGET_NOTIFICATION_THRESHOLD_PERCENTILES = join(",", var.budget_notification_threshold_percentiles)
+    NOTIFICATION_TARGET_ALLOWED_HOSTS         = join(",", var.notification_target_allowed_hosts)
     PRINCIPAL_BUDGET_AMOUNT                   = var.principal_budget_amount
     PRINCIPAL_BUDGET_PERIOD                   = var.principal_budget_period
     USAGE_TTL                                 = var.usage_ttl
+    BUDGET_POLICY_DB                          = aws_dynamodb_table.budget_policies.id
+    COGNITO_USER_POOL_ID                      = module.api_gateway_authorizer.user_pool_id
+    BUDGET_POLICY_GROUPS_TTL                  = "5m"
+    BUDGET_FORECAST_ACTION                    = var.budget_forecast_action
+    COST_PROVIDER                             = var.cost_provider
+    CUR_BUCKET                                = var.cur_bucket
+    CUR_PREFIX                                = var.cur_prefix
+    CUR_REPORT_NAME                           = var.cur_report_name
+    CURRENCY_EXCHANGE_RATES                   = jsonencode(var.currency_exchange_rates)
   }
 }
 
+// Allow update_lease_status lambda to read Cost and Usage Reports
+resource "aws_iam_role_policy" "update_lease_status_cur" {
+  count  = var.cost_provider == "CUR" ? 1 : 0
+  role   = module.update_lease_status_lambda.execution_role_name
+  policy = <<POLICY
+{
+    "Version": "2012-10-17",
+    "Statement": [{
+      "Effect": "Allow",
+      "Action": ["s3:GetObject"],
+      "Resource": "arn:aws:s3:::${var.cur_bucket}/*"
+    }]
+}
+POLICY
+}
+
 // Allow update_lease_status lambda to send emails with SES
 resource "aws_iam_role_policy" "check_buget_ses" {
   role   = module.update_lease_status_lambda.execution_role_name
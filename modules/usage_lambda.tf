@@ -12,5 +12,9 @@ module "usage_lambda" {
     NAMESPACE          = var.namespace
     AWS_CURRENT_REGION = var.aws_region
     USAGE_CACHE_DB     = aws_dynamodb_table.usage.id
+    USAGE_DB           = aws_dynamodb_table.usage.id
+    LEASE_USAGE_DB     = aws_dynamodb_table.lease_usage.id
+    LEASE_DB           = aws_dynamodb_table.leases.id
+    ACCOUNT_DB         = aws_dynamodb_table.accounts.id
   }
 }
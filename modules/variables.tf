@@ -67,6 +67,58 @@ variable "reset_nuke_toggle" {
   default     = "true"
 }
 
+variable "reset_executor" {
+  description = "How accounts are reset: 'CodeBuild' starts a CodeBuild build for each reset, 'Worker' runs a long running reset worker on ECS Fargate. Defaults to 'CodeBuild'"
+  default     = "CodeBuild"
+}
+
+variable "reset_max_receive_count" {
+  type        = number
+  description = "Number of times a reset is attempted from the reset queue, before it is moved to the reset dead letter queue"
+  default     = 5
+}
+
+variable "reset_worker_image" {
+  description = "Docker image of the reset worker, built from bin/worker/reset.zip. Required when reset_executor is 'Worker'"
+  default     = ""
+}
+
+variable "reset_worker_subnet_ids" {
+  type        = list(string)
+  description = "Subnets to run the reset worker in, which must have access to the AWS APIs. Required when reset_executor is 'Worker'"
+  default     = []
+}
+
+variable "reset_worker_assign_public_ip" {
+  type        = bool
+  description = "Assign a public IP to the reset worker, for subnets without a NAT gateway"
+  default     = false
+}
+
+variable "reset_worker_count" {
+  type        = number
+  description = "Number of reset worker tasks to run"
+  default     = 1
+}
+
+variable "reset_worker_concurrency" {
+  type        = number
+  description = "Number of accounts each reset worker task resets at once"
+  default     = 10
+}
+
+variable "reset_worker_cpu" {
+  type        = number
+  description = "CPU units of each reset worker task"
+  default     = 2048
+}
+
+variable "reset_worker_memory" {
+  type        = number
+  description = "Memory (MiB) of each reset worker task"
+  default     = 8192
+}
+
 variable "cloudwatch_dashboard_toggle" {
   description = "Set to 'true' to enable an out of the box cloudwatch dashboard. Defaults to 'false."
   default     = "false"
@@ -97,6 +149,12 @@ variable "budget_notification_from_email" {
   default = "notifications@example.com"
 }
 
+variable "notification_target_allowed_hosts" {
+  type        = list(string)
+  description = "Hosts which lease notification targets (Slack, Teams and webhook URLs) may post to. Hosts starting with \"*.\" allow any subdomain"
+  default     = ["hooks.slack.com", "*.webhook.office.com", "outlook.office.com"]
+}
+
 variable "budget_notification_bcc_emails" {
   type        = list(string)
   description = "Budget notifications emails will be bcc-d to these addresses"
@@ -111,6 +169,10 @@ variable "budget_notification_template_html" {
 {{if .IsOverBudget}}
 Lease for principal {{.Lease.PrincipalID}} in AWS Account {{.Lease.AccountID}}
 has exceeded its budget of $${{.Lease.BudgetAmount}}. Actual spend is $${{.ActualSpend}}
+{{else if .IsForecastOverBudget}}
+Lease for principal {{.Lease.PrincipalID}} in AWS Account {{.Lease.AccountID}}
+is forecasted to exceed its budget of $${{.Lease.BudgetAmount}} before it expires.
+Actual spend is $${{.ActualSpend}}, forecasted spend is $${{printf "%.2f" .ForecastedSpend}}
 {{else}}
 Lease for principal {{.Lease.PrincipalID}} in AWS Account {{.Lease.AccountID}}
 has exceeded the {{.ThresholdPercentile}}% threshold limit for its budget of $${{.Lease.BudgetAmount}}.
@@ -127,6 +189,10 @@ variable "budget_notification_template_text" {
 {{if .IsOverBudget}}
 Lease for principal {{.Lease.PrincipalID}} in AWS Account {{.Lease.AccountID}}
 has exceeded its budget of $${{.Lease.BudgetAmount}}. Actual spend is $${{.ActualSpend}}
+{{else if .IsForecastOverBudget}}
+Lease for principal {{.Lease.PrincipalID}} in AWS Account {{.Lease.AccountID}}
+is forecasted to exceed its budget of $${{.Lease.BudgetAmount}} before it expires.
+Actual spend is $${{.ActualSpend}}, forecasted spend is $${{printf "%.2f" .ForecastedSpend}}
 {{else}}
 Lease for principal {{.Lease.PrincipalID}} in AWS Account {{.Lease.AccountID}}
 has exceeded the {{.ThresholdPercentile}}% threshold limit for its budget of $${{.Lease.BudgetAmount}}.
@@ -139,7 +205,7 @@ variable "budget_notification_template_subject" {
   type        = string
   description = "Template for budget notification email subject"
   default     = <<SUBJ
-Lease {{if .IsOverBudget}}over budget{{else}}at {{.ThresholdPercentile}}% of budget{{end}} [{{.Lease.AccountID}}]
+Lease {{if .IsOverBudget}}over budget{{else if .IsForecastOverBudget}}forecasted over budget{{else}}at {{.ThresholdPercentile}}% of budget{{end}} [{{.Lease.AccountID}}]
 SUBJ
 }
 
@@ -204,6 +270,12 @@ variable "max_lease_period" {
   default     = 604800
 }
 
+variable "max_active_leases_per_principal" {
+  type        = number
+  description = "Maximum number of Active, Pending and Scheduled leases a single principal may hold at once"
+  default     = 1
+}
+
 variable "principal_budget_amount" {
   type        = number
   description = "User Principal's budget amount for given principal budget period"
@@ -216,6 +288,42 @@ variable "principal_budget_period" {
   default     = "WEEKLY"
 }
 
+variable "budget_forecast_action" {
+  type        = string
+  description = "Action to take when a lease is forecasted to exceed its budget before it expires. Must be NONE, WARN or TERMINATE"
+  default     = "NONE"
+}
+
+variable "currency_exchange_rates" {
+  type        = map(number)
+  description = "Units of each currency worth one USD, by ISO 4217 currency code (eg. { EUR = 0.92 }). Leases may only be budgeted in USD, or in these currencies"
+  default     = {}
+}
+
+variable "cost_provider" {
+  type        = string
+  description = "Source of lease spend. Must be CostExplorer (AWS Cost Explorer API) or CUR (AWS Cost and Usage Reports)"
+  default     = "CostExplorer"
+}
+
+variable "cur_bucket" {
+  type        = string
+  description = "S3 bucket AWS Cost and Usage Reports are delivered to. Required for the CUR cost provider"
+  default     = ""
+}
+
+variable "cur_prefix" {
+  type        = string
+  description = "S3 prefix AWS Cost and Usage Reports are delivered to"
+  default     = ""
+}
+
+variable "cur_report_name" {
+  type        = string
+  description = "Name of the AWS Cost and Usage Report. Required for the CUR cost provider"
+  default     = ""
+}
+
 variable "allowed_regions" {
   type = list(string)
   default = [
@@ -236,6 +344,30 @@ variable "ready_accounts_alarm_threshold" {
   default     = "20"
 }
 
+variable "account_pool_min_ready_accounts" {
+  type        = number
+  description = "Create new accounts in AWS Organizations when the number of Ready accounts drops below this number. Set to 0 to disable."
+  default     = 0
+}
+
+variable "account_pool_max_accounts_per_run" {
+  type        = number
+  description = "The maximum number of accounts to request from AWS Organizations each time the account pool is checked."
+  default     = 5
+}
+
+variable "account_pool_account_email_format" {
+  type        = string
+  description = "Email address for new accounts created in AWS Organizations. Must contain %s, which is replaced with the account name, e.g. \"aws+%s@example.com\""
+  default     = ""
+}
+
+variable "account_pool_admin_role_name" {
+  type        = string
+  description = "Name of the admin role AWS Organizations creates in new accounts. Used as the account's adminRoleArn."
+  default     = "OrganizationAccountAccessRole"
+}
+
 variable "usage_ttl" {
   type = number
   # 30 days
@@ -277,4 +409,143 @@ variable "usage_table_wcu" {
   type        = number
   default     = 5
   description = "DynamoDB Usage table provisioned Write Capacity Units (WCUs). See https://aws.amazon.com/dynamodb/pricing/provisioned/"
-}
\ No newline at end of file
+}
+
+variable "budget_policies_table_rcu" {
+  type        = number
+  default     = 5
+  description = "DynamoDB BudgetPolicies table provisioned Read Capacity Units (RCUs). See https://aws.amazon.com/dynamodb/pricing/provisioned/"
+}
+
+variable "budget_policies_table_wcu" {
+  type        = number
+  default     = 5
+  description = "DynamoDB BudgetPolicies table provisioned Write Capacity Units (WCUs). See https://aws.amazon.com/dynamodb/pricing/provisioned/"
+}
+
+variable "reset_runs_table_rcu" {
+  type        = number
+  default     = 5
+  description = "DynamoDB ResetRuns table provisioned Read Capacity Units (RCUs). See https://aws.amazon.com/dynamodb/pricing/provisioned/"
+}
+
+variable "reset_runs_table_wcu" {
+  type        = number
+  default     = 5
+  description = "DynamoDB ResetRuns table provisioned Write Capacity Units (WCUs). See https://aws.amazon.com/dynamodb/pricing/provisioned/"
+}
+
+variable "provisioner_requests_table_rcu" {
+  type        = number
+  default     = 5
+  description = "DynamoDB ProvisionerRequests table provisioned Read Capacity Units (RCUs). See https://aws.amazon.com/dynamodb/pricing/provisioned/"
+}
+
+variable "provisioner_requests_table_wcu" {
+  type        = number
+  default     = 5
+  description = "DynamoDB ProvisionerRequests table provisioned Write Capacity Units (WCUs). See https://aws.amazon.com/dynamodb/pricing/provisioned/"
+}
+
+variable "account_health_check_toggle" {
+  description = "Set to 'true' to periodically check the health of every account, orphaning accounts which DCE can no longer manage. Defaults to 'false'"
+  default     = "false"
+}
+
+variable "account_health_check_rate_expression" {
+  description = "The rate at which account health is checked. Defaults to rate(1 hour). See https://docs.aws.amazon.com/AmazonCloudWatch/latest/events/ScheduledEvents.html"
+  default     = "rate(1 hour)"
+}
+
+variable "account_health_check_failure_threshold" {
+  type        = number
+  description = "Number of consecutive failed health checks before an account is orphaned"
+  default     = 3
+}
+
+variable "account_health_check_actions" {
+  type        = list(string)
+  description = "IAM actions which must not be denied to the admin role by a service control policy"
+  default     = ["iam:CreateRole", "iam:CreatePolicy", "ec2:DescribeRegions", "sts:AssumeRole"]
+}
+
+variable "chargeback_toggle" {
+  description = "Set to 'true' to write a monthly chargeback report of the spend of each lease to the artifacts bucket. Defaults to 'false'"
+  default     = "false"
+}
+
+variable "chargeback_schedule_expression" {
+  description = "When the chargeback report of the previous month is written. Defaults to 6am UTC on the first day of each month. See https://docs.aws.amazon.com/AmazonCloudWatch/latest/events/ScheduledEvents.html"
+  default     = "cron(0 6 1 * ? *)"
+}
+
+variable "chargeback_cost_center_key" {
+  description = "Lease metadata key holding the cost center of the lease, for chargeback reports"
+  default     = "costCenter"
+}
+
+variable "chargeback_report_emails" {
+  type        = list(string)
+  description = "Email addresses the monthly chargeback report is sent to. Emails are sent from the budget_notification_from_email address."
+  default     = []
+}
+
+variable "lease_expiry_reminders_toggle" {
+  description = "Set to 'true' to email principals before their leases expire. Defaults to 'false'"
+  default     = "false"
+}
+
+variable "assign_pending_leases_schedule_expression" {
+  description = "How often to assign Ready accounts to Pending leases, in addition to when accounts finish resetting"
+  default     = "rate(5 minutes)"
+}
+
+variable "lease_expiry_reminders_schedule_expression" {
+  description = "How often to check for leases which are about to expire"
+  default     = "rate(15 minutes)"
+}
+
+variable "lease_expiry_reminders" {
+  type        = list(string)
+  description = "How long before a lease expires reminders are sent, as durations (eg. \"24h\", \"30m\")"
+  default     = ["24h", "1h"]
+}
+
+variable "lease_extension_period" {
+  description = "How long the one-click link in expiry reminders extends a lease by, as a duration (eg. \"24h\")"
+  default     = "24h"
+}
+
+variable "lease_expiry_reminder_template_subject" {
+  type        = string
+  description = "Template for lease expiry reminder email subject"
+  default     = <<SUBJ
+Lease expires in {{.HoursRemaining}} hour{{if ne .HoursRemaining 1}}s{{end}} [{{.Lease.AccountID}}]
+SUBJ
+}
+
+variable "lease_expiry_reminder_template_text" {
+  type        = string
+  description = "Text template for lease expiry reminder emails"
+  default     = <<TMPL
+Lease for principal {{.Lease.PrincipalID}} in AWS Account {{.Lease.AccountID}}
+expires on {{.ExpiresOn}}. Resources in the account will be deleted when the lease expires.
+{{if .ExtendURL}}
+To extend the lease by {{.ExtensionHours}} hours, visit {{.ExtendURL}}
+{{end}}
+TMPL
+}
+
+variable "lease_expiry_reminder_template_html" {
+  type        = string
+  description = "HTML template for lease expiry reminder emails"
+  default     = <<TMPL
+<p>
+Lease for principal {{.Lease.PrincipalID}} in AWS Account {{.Lease.AccountID}}
+expires on {{.ExpiresOn}}. Resources in the account will be deleted when the lease expires.
+</p>
+{{if .ExtendURL}}
+<p><a href="{{.ExtendURL}}">Extend the lease by {{.ExtensionHours}} hours</a></p>
+{{end}}
+TMPL
+}
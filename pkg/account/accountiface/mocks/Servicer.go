@@ -34,20 +34,6 @@ func (_m *Servicer) Create(data *account.Account) (*account.Account, error) {
 	return r0, r1
 }
 
-// Delete provides a mock function with given fields: data
-func (_m *Servicer) Delete(data *account.Account) error {
-	ret := _m.Called(data)
-
-	var r0 error
-	if rf, ok := ret.Get(0).(func(*account.Account) error); ok {
-		r0 = rf(data)
-	} else {
-		r0 = ret.Error(0)
-	}
-
-	return r0
-}
-
 // Get provides a mock function with given fields: ID
 func (_m *Servicer) Get(ID string) (*account.Account, error) {
 	ret := _m.Called(ID)
@@ -108,6 +94,52 @@ func (_m *Servicer) ListPages(query *account.Account, fn func(*account.Accounts)
 	return r0
 }
 
+// Orphan provides a mock function with given fields: data, reason
+func (_m *Servicer) Orphan(data *account.Account, reason string) (*account.Account, error) {
+	ret := _m.Called(data, reason)
+
+	var r0 *account.Account
+	if rf, ok := ret.Get(0).(func(*account.Account, string) *account.Account); ok {
+		r0 = rf(data, reason)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*account.Account)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*account.Account, string) error); ok {
+		r1 = rf(data, reason)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Recover provides a mock function with given fields: data
+func (_m *Servicer) Recover(data *account.Account) (*account.Account, error) {
+	ret := _m.Called(data)
+
+	var r0 *account.Account
+	if rf, ok := ret.Get(0).(func(*account.Account) *account.Account); ok {
+		r0 = rf(data)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*account.Account)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*account.Account) error); ok {
+		r1 = rf(data)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Reset provides a mock function with given fields: data
 func (_m *Servicer) Reset(data *account.Account) error {
 	ret := _m.Called(data)
@@ -122,6 +154,29 @@ func (_m *Servicer) Reset(data *account.Account) error {
 	return r0
 }
 
+// Retire provides a mock function with given fields: data
+func (_m *Servicer) Retire(data *account.Account) (*account.Account, error) {
+	ret := _m.Called(data)
+
+	var r0 *account.Account
+	if rf, ok := ret.Get(0).(func(*account.Account) *account.Account); ok {
+		r0 = rf(data)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*account.Account)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*account.Account) error); ok {
+		r1 = rf(data)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Save provides a mock function with given fields: data
 func (_m *Servicer) Save(data *account.Account) error {
 	ret := _m.Called(data)
@@ -14,8 +14,12 @@ type Servicer interface {
 	Save(data *account.Account) error
 	// Update the Account record in DynamoDB
 	Update(ID string, data *account.Account) (*account.Account, error)
-	// Delete finds a given account and deletes it if it is not of status `Leased`. Returns the account.
-	Delete(data *account.Account) error
+	// Retire marks the account Retiring, to be Retired after its current lease and last reset
+	Retire(data *account.Account) (*account.Account, error)
+	// Orphan takes the account out of the pool, recording the reason
+	Orphan(data *account.Account, reason string) (*account.Account, error)
+	// Recover returns an Orphaned account to the pool, after it is reset
+	Recover(data *account.Account) (*account.Account, error)
 	// List Get a list of accounts based on Principal ID
 	List(query *account.Account) (*account.Accounts, error)
 	// ListPages Execute a function per page of accounts
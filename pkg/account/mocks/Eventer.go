@@ -24,20 +24,6 @@ func (_m *Eventer) AccountCreate(_a0 *account.Account) error {
 	return r0
 }
 
-// AccountDelete provides a mock function with given fields: _a0
-func (_m *Eventer) AccountDelete(_a0 *account.Account) error {
-	ret := _m.Called(_a0)
-
-	var r0 error
-	if rf, ok := ret.Get(0).(func(*account.Account) error); ok {
-		r0 = rf(_a0)
-	} else {
-		r0 = ret.Error(0)
-	}
-
-	return r0
-}
-
 // AccountReset provides a mock function with given fields: _a0
 func (_m *Eventer) AccountReset(_a0 *account.Account) error {
 	ret := _m.Called(_a0)
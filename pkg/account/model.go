@@ -18,12 +18,14 @@ var (
 )
 
 // ValidStatuses has the valid status options
-var ValidStatuses = [5]Status{
+var ValidStatuses = [7]Status{
 	StatusNone,
 	StatusLeased,
 	StatusNotReady,
 	StatusOrphaned,
 	StatusReady,
+	StatusRetiring,
+	StatusRetired,
 }
 
 func init() {
@@ -38,12 +40,17 @@ func init() {
 type Account struct {
 	ID                  *string                `json:"id,omitempty" dynamodbav:"Id" schema:"id,omitempty"`                                                              // AWS Account ID
 	Status              *Status                `json:"accountStatus,omitempty" dynamodbav:"AccountStatus,omitempty" schema:"status,omitempty"`                          // Status of the AWS Account
+	StatusReason        *string                `json:"statusReason,omitempty" dynamodbav:"StatusReason,omitempty" schema:"-"`                                           // Why the account is in its current status
+	HealthCheckFailures *int64                 `json:"healthCheckFailures,omitempty" dynamodbav:"HealthCheckFailures,omitempty" schema:"-"`                             // Consecutive failed health checks, before the account is orphaned
 	LastModifiedOn      *int64                 `json:"lastModifiedOn,omitempty" dynamodbav:"LastModifiedOn" schema:"lastModifiedOn,omitempty"`                          // Last Modified Epoch Timestamp
 	CreatedOn           *int64                 `json:"createdOn,omitempty"  dynamodbav:"CreatedOn,omitempty" schema:"createdOn,omitempty"`                              // Account CreatedOn
 	AdminRoleArn        *arn.ARN               `json:"adminRoleArn,omitempty"  dynamodbav:"AdminRoleArn" schema:"adminRoleArn,omitempty"`                               // Assumed by the master account, to manage this user account
 	PrincipalRoleArn    *arn.ARN               `json:"principalRoleArn,omitempty"  dynamodbav:"PrincipalRoleArn,omitempty" schema:"principalRoleArn,omitempty"`         // Assumed by principal users
 	PrincipalPolicyHash *string                `json:"principalPolicyHash,omitempty" dynamodbav:"PrincipalPolicyHash,omitempty" schema:"principalPolicyHash,omitempty"` // The the hash of the policy version deployed
 	Metadata            map[string]interface{} `json:"metadata,omitempty"  dynamodbav:"Metadata,omitempty" schema:"-"`                                                  // Any org specific metadata pertaining to the account
+	Pool                *string                `json:"pool,omitempty" dynamodbav:"Pool,omitempty" schema:"pool,omitempty"`                                              // The class of account, e.g. by organizational unit or SCP
+	NukeFilters         NukeFilters            `json:"nukeFilters,omitempty" dynamodbav:"NukeFilters,omitempty" schema:"-"`                                             // aws-nuke filters of resources to keep when the account is reset
+	ResetReason         *ResetReason           `json:"resetReason,omitempty" dynamodbav:"-" schema:"-"`                                                                 // Why the account is being reset, only sent to the reset queue
 	Limit               *int64                 `json:"-" dynamodbav:"-" schema:"limit,omitempty"`
 	NextID              *string                `json:"-" dynamodbav:"-" schema:"nextId,omitempty"`
 	PrincipalPolicyArn  *arn.ARN               `json:"-"  dynamodbav:"-" schema:"-"`
@@ -59,6 +66,8 @@ func (a *Account) Validate() error {
 		validation.Field(&a.CreatedOn, validateInt64...),
 		validation.Field(&a.PrincipalRoleArn, validatePrincipalRoleArn...),
 		validation.Field(&a.PrincipalPolicyHash, validatePrincipalPolicyHash...),
+		validation.Field(&a.Pool, validatePool...),
+		validation.Field(&a.NukeFilters, validateNukeFilters...),
 	)
 	if err != nil {
 		return errors.NewValidation("account", err)
@@ -77,12 +86,17 @@ func (a *Account) UnmarshalJSON(data []byte) error {
 
 	a.ID = alias.ID
 	a.Status = alias.Status
+	a.StatusReason = alias.StatusReason
+	a.HealthCheckFailures = alias.HealthCheckFailures
 	a.LastModifiedOn = alias.LastModifiedOn
 	a.CreatedOn = alias.CreatedOn
 	a.PrincipalRoleArn = alias.PrincipalRoleArn
 	a.AdminRoleArn = alias.AdminRoleArn
 	a.Metadata = alias.Metadata
+	a.Pool = alias.Pool
 	a.PrincipalPolicyHash = alias.PrincipalPolicyHash
+	a.NukeFilters = alias.NukeFilters
+	a.ResetReason = alias.ResetReason
 
 	if alias.ID != nil {
 		principalPolicyArn := arn.New("aws", "iam", "", *alias.ID, fmt.Sprintf("policy/%s", PrincipalPolicyName))
@@ -104,12 +118,16 @@ func (a *Account) UnmarshalDynamoDBAttributeValue(av *dynamodb.AttributeValue) e
 
 	a.ID = alias.ID
 	a.Status = alias.Status
+	a.StatusReason = alias.StatusReason
+	a.HealthCheckFailures = alias.HealthCheckFailures
 	a.LastModifiedOn = alias.LastModifiedOn
 	a.CreatedOn = alias.CreatedOn
 	a.PrincipalRoleArn = alias.PrincipalRoleArn
 	a.AdminRoleArn = alias.AdminRoleArn
 	a.Metadata = alias.Metadata
+	a.Pool = alias.Pool
 	a.PrincipalPolicyHash = alias.PrincipalPolicyHash
+	a.NukeFilters = alias.NukeFilters
 
 	if a.ID != nil {
 		principalPolicyArn := arn.New("aws", "iam", "", *alias.ID, fmt.Sprintf("policy/%s", PrincipalPolicyName))
@@ -123,6 +141,8 @@ type NewAccountInput struct {
 	ID                string
 	AdminRoleArn      arn.ARN
 	Metadata          map[string]interface{}
+	Pool              *string
+	NukeFilters       NukeFilters
 	PrincipalRoleName string
 }
 
@@ -139,6 +159,8 @@ func NewAccount(input NewAccountInput) (*Account, error) {
 		PrincipalRoleArn:   roleArn,
 		PrincipalPolicyArn: policyArn,
 		Metadata:           input.Metadata,
+		Pool:               input.Pool,
+		NukeFilters:        input.NukeFilters,
 		Status:             StatusNotReady.StatusPtr(),
 	}, nil
 }
@@ -160,6 +182,12 @@ const (
 	StatusLeased Status = "Leased"
 	// StatusOrphaned status
 	StatusOrphaned Status = "Orphaned"
+	// StatusRetiring status, the account is being removed from the pool
+	// once its current lease and its last reset are done
+	StatusRetiring Status = "Retiring"
+	// StatusRetired status, the account is no longer in the pool
+	// and is kept for auditing
+	StatusRetired Status = "Retired"
 )
 
 // String returns the string value of AccountStatus
@@ -178,3 +206,83 @@ func (c Status) StatusPtr() *Status {
 	v := c
 	return &v
 }
+
+// ResetReason is what triggered the reset of an account
+type ResetReason string
+
+const (
+	// ResetReasonAccountCreated means the account was added to the pool
+	ResetReasonAccountCreated ResetReason = "AccountCreated"
+	// ResetReasonAccountDeleted means the account was removed from the pool.
+	// Accounts are now retired instead, so only earlier resets have this reason.
+	ResetReasonAccountDeleted ResetReason = "AccountDeleted"
+	// ResetReasonAccountRetired means the account is being retired from the pool
+	ResetReasonAccountRetired ResetReason = "AccountRetired"
+	// ResetReasonAccountRecovered means an Orphaned account was returned to the pool
+	ResetReasonAccountRecovered ResetReason = "AccountRecovered"
+	// ResetReasonLeaseEnded means the lease of the account became Inactive
+	ResetReasonLeaseEnded ResetReason = "LeaseEnded"
+	// ResetReasonNotReady means the account was still NotReady, and its reset is retried
+	ResetReasonNotReady ResetReason = "NotReady"
+)
+
+// String returns the string value of ResetReason
+func (c ResetReason) String() string {
+	return string(c)
+}
+
+// ResetReasonPtr returns a pointer to the value of ResetReason
+func (c ResetReason) ResetReasonPtr() *ResetReason {
+	v := c
+	return &v
+}
+
+// NukeFilters are aws-nuke filters by resource type, e.g. `S3Bucket` or `EC2VPC`.
+// Resources matching the filters are kept when the account is reset,
+// in addition to the resources filtered by the nuke template.
+// See https://github.com/rebuy-de/aws-nuke#filtering-resources
+type NukeFilters map[string][]NukeFilter
+
+// NukeFilter matches resources of a type to keep when the account is reset
+type NukeFilter struct {
+	Type     *NukeFilterType `json:"type,omitempty" dynamodbav:"Type,omitempty"`         // How the value is matched, exact if empty
+	Property *string         `json:"property,omitempty" dynamodbav:"Property,omitempty"` // The resource property to match, the resource name if empty
+	Value    *string         `json:"value,omitempty" dynamodbav:"Value,omitempty"`       // The value to match
+	Invert   *bool           `json:"invert,omitempty" dynamodbav:"Invert,omitempty"`     // Keep the resources which don't match instead
+}
+
+// NukeFilterType is how an aws-nuke filter matches its value
+type NukeFilterType string
+
+const (
+	// NukeFilterTypeExact matches the value exactly
+	NukeFilterTypeExact NukeFilterType = "exact"
+	// NukeFilterTypeContains matches values containing the value
+	NukeFilterTypeContains NukeFilterType = "contains"
+	// NukeFilterTypeGlob matches the value as a glob pattern
+	NukeFilterTypeGlob NukeFilterType = "glob"
+	// NukeFilterTypeRegex matches the value as a regular expression
+	NukeFilterTypeRegex NukeFilterType = "regex"
+	// NukeFilterTypeDateOlderThan matches dates older than the value, as a duration
+	NukeFilterTypeDateOlderThan NukeFilterType = "dateOlderThan"
+)
+
+// ValidNukeFilterTypes has the valid nuke filter type options
+var ValidNukeFilterTypes = [5]NukeFilterType{
+	NukeFilterTypeExact,
+	NukeFilterTypeContains,
+	NukeFilterTypeGlob,
+	NukeFilterTypeRegex,
+	NukeFilterTypeDateOlderThan,
+}
+
+// String returns the string value of NukeFilterType
+func (c NukeFilterType) String() string {
+	return string(c)
+}
+
+// NukeFilterTypePtr returns a pointer to the value of NukeFilterType
+func (c NukeFilterType) NukeFilterTypePtr() *NukeFilterType {
+	v := c
+	return &v
+}
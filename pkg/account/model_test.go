@@ -36,6 +36,22 @@ func TestAccountUnmarshalJSON(t *testing.T) {
 				PrincipalRoleArn:   arn.New("aws", "iam", "", "123456789012", "role/DCEPrincipal"),
 			},
 		},
+		{
+			name:  "should be able to unmarshal with nuke filters",
+			input: "{\"id\":\"123456789012\", \"nukeFilters\": {\"S3Bucket\": [{\"type\": \"glob\", \"value\": \"baseline-*\"}]}}",
+			expAccount: &account.Account{
+				ID:                 ptrString("123456789012"),
+				PrincipalPolicyArn: arn.New("aws", "iam", "", "123456789012", "policy/DCEPrincipalDefaultPolicy"),
+				NukeFilters: account.NukeFilters{
+					"S3Bucket": {
+						{
+							Type:  account.NukeFilterTypeGlob.NukeFilterTypePtr(),
+							Value: ptrString("baseline-*"),
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
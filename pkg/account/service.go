@@ -51,7 +51,6 @@ type ReaderWriterDeleter interface {
 // Eventer for publishing events
 type Eventer interface {
 	AccountCreate(account *Account) error
-	AccountDelete(account *Account) error
 	AccountUpdate(account *Account) error
 	AccountReset(account *Account) error
 }
@@ -114,6 +113,8 @@ func (a *Service) Update(ID string, data *Account) (*Account, error) {
 		validation.Field(&data.AdminRoleArn, validation.By(isNilOrUsableAdminRole(a.managerSvc))),
 		validation.Field(&data.LastModifiedOn, validation.By(isNil)),
 		validation.Field(&data.CreatedOn, validation.By(isNil)),
+		validation.Field(&data.ResetReason, validation.By(isNil)),
+		validation.Field(&data.NukeFilters, validateNukeFilters...),
 	)
 	if err != nil {
 		return nil, errors.NewValidation("account", err)
@@ -128,6 +129,11 @@ func (a *Service) Update(ID string, data *Account) (*Account, error) {
 	if err != nil {
 		return nil, errors.NewInternalServer("unexpected error updating account", err)
 	}
+	// Nuke filters are replaced instead of merged,
+	// so filters can be removed from the account
+	if data.NukeFilters != nil {
+		account.NukeFilters = data.NukeFilters
+	}
 
 	err = a.Save(account)
 	if err != nil {
@@ -146,9 +152,11 @@ func (a *Service) Create(data *Account) (*Account, error) {
 		validation.Field(&data.AdminRoleArn, validateAdminRoleArn...),
 		validation.Field(&data.LastModifiedOn, validation.By(isNil)),
 		validation.Field(&data.Status, validation.By(isNil)),
+		validation.Field(&data.StatusReason, validation.By(isNil)),
 		validation.Field(&data.CreatedOn, validation.By(isNil)),
 		validation.Field(&data.PrincipalRoleArn, validation.By(isNil)),
 		validation.Field(&data.PrincipalPolicyHash, validation.By(isNil)),
+		validation.Field(&data.NukeFilters, validateNukeFilters...),
 	)
 	if err != nil {
 		return nil, errors.NewValidation("account", err)
@@ -169,6 +177,8 @@ func (a *Service) Create(data *Account) (*Account, error) {
 		ID:                *data.ID,
 		AdminRoleArn:      *data.AdminRoleArn,
 		Metadata:          data.Metadata,
+		Pool:              data.Pool,
+		NukeFilters:       data.NukeFilters,
 		PrincipalRoleName: a.principalRoleName,
 	})
 	if err != nil {
@@ -190,7 +200,9 @@ func (a *Service) Create(data *Account) (*Account, error) {
 		return nil, err
 	}
 
+	new.ResetReason = ResetReasonAccountCreated.ResetReasonPtr()
 	err = a.eventSvc.AccountReset(new)
+	new.ResetReason = nil
 	if err != nil {
 		return nil, err
 	}
@@ -198,39 +210,119 @@ func (a *Service) Create(data *Account) (*Account, error) {
 	return new, nil
 }
 
-// Delete finds a given account and deletes it if it is not of status `Leased`. Returns the account.
-func (a *Service) Delete(data *Account) error {
+// Retire removes an account from the pool, without deleting its record.
+// The account is marked Retiring, and becomes Retired after its last reset.
+// A Leased account is reset when its lease ends, otherwise principal access
+// is removed and the reset is started now. If retiring fails, the account
+// is left in the pool, so it may be retired again. Returns the account.
+func (a *Service) Retire(data *Account) (*Account, error) {
+	err := validation.ValidateStruct(data,
+		validation.Field(&data.Status, validation.NotNil, validation.By(isAccountNotRetired)),
+	)
+	if err != nil {
+		return nil, errors.NewConflict("account", *data.ID, err)
+	}
 
+	prevStatus := *data.Status
+	if prevStatus == StatusLeased {
+		// The account is reset when its lease ends
+		data.Status = StatusRetiring.StatusPtr()
+		err = a.Save(data)
+		if err != nil {
+			data.Status = prevStatus.StatusPtr()
+			return nil, err
+		}
+		return data, nil
+	}
+
+	err = a.managerSvc.DeletePrincipalAccess(data)
+	if err != nil {
+		return nil, err
+	}
+
+	data.Status = StatusRetiring.StatusPtr()
+	err = a.Save(data)
+	if err != nil {
+		// The account may have been leased since principal access was removed
+		data.Status = prevStatus.StatusPtr()
+		a.restorePrincipalAccess(data)
+		return nil, err
+	}
+
+	err = a.resetFor(data, ResetReasonAccountRetired)
+	if err != nil {
+		data.Status = prevStatus.StatusPtr()
+		if saveErr := a.Save(data); saveErr != nil {
+			log.Printf("Failed to return account %q to the pool after it failed to retire: %s\n", *data.ID, saveErr)
+			return nil, err
+		}
+		a.restorePrincipalAccess(data)
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// restorePrincipalAccess gives principal access back to an account which failed to retire.
+// Failures are only logged, so the error which stopped the account from retiring is returned.
+func (a *Service) restorePrincipalAccess(data *Account) {
+	err := a.managerSvc.UpsertPrincipalAccess(data)
+	if err != nil {
+		log.Printf("Failed to restore principal access to account %q after it failed to retire: %s\n", *data.ID, err)
+	}
+}
+
+// Orphan takes an account out of the pool, because its access has been
+// compromised. The reason is kept with the account. Returns the account.
+func (a *Service) Orphan(data *Account, reason string) (*Account, error) {
 	err := validation.ValidateStruct(data,
-		validation.Field(&data.Status, validation.NotNil, validation.By(isAccountNotLeased)),
-		validation.Field(&data.AdminRoleArn, validation.NotNil),
-		validation.Field(&data.PrincipalRoleArn, validation.NotNil),
+		validation.Field(&data.Status, validation.NotNil, validation.By(isAccountNotRetired)),
 	)
 	if err != nil {
-		return errors.NewConflict("account", *data.ID, err)
+		return nil, errors.NewConflict("account", *data.ID, err)
 	}
 
-	err = a.dataSvc.Delete(data)
+	data.Status = StatusOrphaned.StatusPtr()
+	data.StatusReason = &reason
+	data.HealthCheckFailures = nil
+	err = a.Save(data)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	log.Printf("Orphaned account %q: %s\n", *data.ID, reason)
 
-	err = a.managerSvc.DeletePrincipalAccess(data)
+	return data, nil
+}
+
+// Recover returns an Orphaned account to the pool. Principal access is restored
+// and the account is reset, before it becomes Ready. Returns the account.
+func (a *Service) Recover(data *Account) (*Account, error) {
+	err := validation.ValidateStruct(data,
+		validation.Field(&data.Status, validation.NotNil, validation.By(isAccountOrphaned)),
+	)
 	if err != nil {
-		return err
+		return nil, errors.NewConflict("account", *data.ID, err)
 	}
 
-	err = a.eventSvc.AccountDelete(data)
+	err = a.managerSvc.UpsertPrincipalAccess(data)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	err = a.Reset(data)
+	data.Status = StatusNotReady.StatusPtr()
+	data.StatusReason = nil
+	data.HealthCheckFailures = nil
+	err = a.Save(data)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	err = a.resetFor(data, ResetReasonAccountRecovered)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
 }
 
 // List Get a list of accounts based on a query
@@ -284,6 +376,17 @@ func (a *Service) Reset(data *Account) error {
 	return nil
 }
 
+// resetFor initiates the Reset account process, recording what triggered the reset.
+// The reason is only sent to the reset queue, and is not kept with the account.
+func (a *Service) resetFor(data *Account, reason ResetReason) error {
+	data.ResetReason = &reason
+	defer func() {
+		data.ResetReason = nil
+	}()
+
+	return a.Reset(data)
+}
+
 // UpsertPrincipalAccess merges principal access to make sure its in sync with expectations
 func (a *Service) UpsertPrincipalAccess(data *Account) error {
 	err := validation.ValidateStruct(data,
@@ -81,45 +81,220 @@ func TestGetAccountByID(t *testing.T) {
 	}
 }
 
-func TestDelete(t *testing.T) {
+func TestRetire(t *testing.T) {
 	tests := []struct {
-		name              string
-		expErr            error
-		returnErr         error
-		accountDeletedErr error
-		accountResetErr   error
-		account           account.Account
+		name       string
+		status     account.Status
+		writeErr   error
+		deleteErr  error
+		resetErr   error
+		expDelete  bool
+		expReset   bool
+		expRestore bool
+		expWrites  int
+		expStatus  account.Status
+		expErr     error
 	}{
 		{
-			name: "should delete an account",
-			account: account.Account{
-				ID:               ptrString("123456789012"),
-				Status:           account.StatusReady.StatusPtr(),
-				AdminRoleArn:     arn.New("aws", "iam", "", "123456789012", "role/AdminRole"),
-				PrincipalRoleArn: arn.New("aws", "iam", "", "123456789012", "role/PrincipalRole"),
-			},
-			returnErr: nil,
+			name:      "should retire a ready account and reset it",
+			status:    account.StatusReady,
+			expDelete: true,
+			expReset:  true,
+			expWrites: 1,
+			expStatus: account.StatusRetiring,
+		},
+		{
+			name:      "should retire a leased account and wait for the lease to end",
+			status:    account.StatusLeased,
+			expWrites: 1,
+			expStatus: account.StatusRetiring,
+		},
+		{
+			name:      "should leave the account in the pool when removing principal access fails",
+			status:    account.StatusReady,
+			deleteErr: errors.NewInternalServer("failure", fmt.Errorf("original failure")),
+			expDelete: true,
+			expWrites: 0,
+			expStatus: account.StatusReady,
+			expErr:    errors.NewInternalServer("failure", nil),
+		},
+		{
+			name:       "should return the account to the pool when the reset fails",
+			status:     account.StatusNotReady,
+			resetErr:   errors.NewInternalServer("failure", fmt.Errorf("original failure")),
+			expDelete:  true,
+			expReset:   true,
+			expRestore: true,
+			expWrites:  2,
+			expStatus:  account.StatusNotReady,
+			expErr:     errors.NewInternalServer("failure", nil),
+		},
+		{
+			name:      "should error when account is already retiring",
+			status:    account.StatusRetiring,
+			expStatus: account.StatusRetiring,
+			expErr:    errors.NewConflict("account", "123456789012", fmt.Errorf("accountStatus: must not be retiring or retired.")), //nolint golint
 		},
 		{
-			name: "should error when account leased",
-			account: account.Account{
+			name:      "should error when account is retired",
+			status:    account.StatusRetired,
+			expStatus: account.StatusRetired,
+			expErr:    errors.NewConflict("account", "123456789012", fmt.Errorf("accountStatus: must not be retiring or retired.")), //nolint golint
+		},
+		{
+			name:       "should restore principal access when write fails",
+			status:     account.StatusReady,
+			writeErr:   errors.NewInternalServer("failure", fmt.Errorf("original failure")),
+			expDelete:  true,
+			expRestore: true,
+			expWrites:  1,
+			expStatus:  account.StatusReady,
+			expErr:     errors.NewInternalServer("failure", nil),
+		},
+		{
+			name:      "should error when write fails for a leased account",
+			status:    account.StatusLeased,
+			writeErr:  errors.NewInternalServer("failure", fmt.Errorf("original failure")),
+			expWrites: 1,
+			expStatus: account.StatusLeased,
+			expErr:    errors.NewInternalServer("failure", nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocksRwd := &mocks.ReaderWriterDeleter{}
+			mocksRwd.On("Write", mock.AnythingOfType("*account.Account"), mock.AnythingOfType("*int64")).
+				Return(tt.writeErr)
+
+			mocksManager := &mocks.Manager{}
+			mocksManager.On("DeletePrincipalAccess", mock.AnythingOfType("*account.Account")).Return(tt.deleteErr)
+			mocksManager.On("UpsertPrincipalAccess", mock.AnythingOfType("*account.Account")).Return(nil)
+			mocksEventer := &mocks.Eventer{}
+			mocksEventer.On("AccountReset", mock.AnythingOfType("*account.Account")).Return(tt.resetErr)
+
+			accountSvc := account.NewService(
+				account.NewServiceInput{
+					DataSvc:    mocksRwd,
+					ManagerSvc: mocksManager,
+					EventSvc:   mocksEventer,
+				},
+			)
+			acct := &account.Account{
 				ID:               ptrString("123456789012"),
-				Status:           account.StatusLeased.StatusPtr(),
+				Status:           tt.status.StatusPtr(),
 				AdminRoleArn:     arn.New("aws", "iam", "", "123456789012", "role/AdminRole"),
 				PrincipalRoleArn: arn.New("aws", "iam", "", "123456789012", "role/PrincipalRole"),
-			},
-			returnErr: nil,
-			expErr:    errors.NewConflict("account", "123456789012", fmt.Errorf("accountStatus: must not be leased.")), //nolint golint
+				LastModifiedOn:   aws.Int64(1561149393),
+				CreatedOn:        aws.Int64(1561149393),
+			}
+			retired, err := accountSvc.Retire(acct)
+			assert.True(t, errors.Is(err, tt.expErr), "actual error %q doesn't match expected error %q", err, tt.expErr)
+			if tt.expErr == nil {
+				assert.Equal(t, acct, retired)
+			}
+			assert.Equal(t, tt.expStatus, *acct.Status)
+			// The record is kept, for auditing
+			mocksRwd.AssertNotCalled(t, "Delete", mock.Anything)
+			mocksRwd.AssertNumberOfCalls(t, "Write", tt.expWrites)
+
+			if tt.expDelete {
+				mocksManager.AssertCalled(t, "DeletePrincipalAccess", acct)
+			} else {
+				mocksManager.AssertNotCalled(t, "DeletePrincipalAccess", mock.Anything)
+			}
+			if tt.expReset {
+				mocksEventer.AssertCalled(t, "AccountReset", acct)
+			} else {
+				mocksEventer.AssertNotCalled(t, "AccountReset", mock.Anything)
+			}
+			if tt.expRestore {
+				mocksManager.AssertCalled(t, "UpsertPrincipalAccess", acct)
+			} else {
+				mocksManager.AssertNotCalled(t, "UpsertPrincipalAccess", mock.Anything)
+			}
+		})
+	}
+}
+
+func TestOrphan(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   account.Status
+		writeErr error
+		expErr   error
+	}{
+		{
+			name:   "should orphan a ready account",
+			status: account.StatusReady,
+		},
+		{
+			name:   "should orphan a leased account",
+			status: account.StatusLeased,
+		},
+		{
+			name:   "should error when account is retired",
+			status: account.StatusRetired,
+			expErr: errors.NewConflict("account", "123456789012", fmt.Errorf("accountStatus: must not be retiring or retired.")), //nolint golint
 		},
 		{
-			name: "should error when delete fails",
-			account: account.Account{
+			name:     "should error when write fails",
+			status:   account.StatusReady,
+			writeErr: errors.NewInternalServer("failure", fmt.Errorf("original failure")),
+			expErr:   errors.NewInternalServer("failure", nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocksRwd := &mocks.ReaderWriterDeleter{}
+			mocksRwd.On("Write", mock.AnythingOfType("*account.Account"), mock.AnythingOfType("*int64")).
+				Return(tt.writeErr)
+
+			accountSvc := account.NewService(
+				account.NewServiceInput{
+					DataSvc:    mocksRwd,
+					ManagerSvc: &mocks.Manager{},
+					EventSvc:   &mocks.Eventer{},
+				},
+			)
+			orphaned, err := accountSvc.Orphan(&account.Account{
 				ID:               ptrString("123456789012"),
-				Status:           account.StatusReady.StatusPtr(),
+				Status:           tt.status.StatusPtr(),
 				AdminRoleArn:     arn.New("aws", "iam", "", "123456789012", "role/AdminRole"),
 				PrincipalRoleArn: arn.New("aws", "iam", "", "123456789012", "role/PrincipalRole"),
-			},
-			returnErr: errors.NewInternalServer("failure", fmt.Errorf("original failure")),
+				LastModifiedOn:   aws.Int64(1561149393),
+				CreatedOn:        aws.Int64(1561149393),
+			}, "admin role cannot be assumed")
+			assert.True(t, errors.Is(err, tt.expErr), "actual error %q doesn't match expected error %q", err, tt.expErr)
+			if tt.expErr == nil {
+				assert.Equal(t, account.StatusOrphaned, *orphaned.Status)
+				assert.Equal(t, "admin role cannot be assumed", *orphaned.StatusReason)
+			}
+		})
+	}
+}
+
+func TestRecover(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    account.Status
+		upsertErr error
+		expErr    error
+	}{
+		{
+			name:   "should recover an orphaned account and reset it",
+			status: account.StatusOrphaned,
+		},
+		{
+			name:   "should error when account is not orphaned",
+			status: account.StatusReady,
+			expErr: errors.NewConflict("account", "123456789012", fmt.Errorf("accountStatus: must be orphaned.")), //nolint golint
+		},
+		{
+			name:      "should error when principal access can't be restored",
+			status:    account.StatusOrphaned,
+			upsertErr: errors.NewInternalServer("failure", fmt.Errorf("original failure")),
 			expErr:    errors.NewInternalServer("failure", nil),
 		},
 	}
@@ -127,15 +302,13 @@ func TestDelete(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mocksRwd := &mocks.ReaderWriterDeleter{}
-			mocksRwd.On("Delete", mock.Anything).
-				Return(tt.returnErr)
+			mocksRwd.On("Write", mock.AnythingOfType("*account.Account"), mock.AnythingOfType("*int64")).
+				Return(nil)
 
 			mocksManager := &mocks.Manager{}
+			mocksManager.On("UpsertPrincipalAccess", mock.AnythingOfType("*account.Account")).Return(tt.upsertErr)
 			mocksEventer := &mocks.Eventer{}
-
-			mocksManager.On("DeletePrincipalAccess", mock.AnythingOfType("*account.Account")).Return(nil)
-			mocksEventer.On("AccountDelete", mock.AnythingOfType("*account.Account")).Return(tt.accountDeletedErr)
-			mocksEventer.On("AccountReset", mock.AnythingOfType("*account.Account")).Return(tt.accountResetErr)
+			mocksEventer.On("AccountReset", mock.AnythingOfType("*account.Account")).Return(nil)
 
 			accountSvc := account.NewService(
 				account.NewServiceInput{
@@ -144,9 +317,23 @@ func TestDelete(t *testing.T) {
 					EventSvc:   mocksEventer,
 				},
 			)
-			err := accountSvc.Delete(&tt.account)
+			recovered, err := accountSvc.Recover(&account.Account{
+				ID:               ptrString("123456789012"),
+				Status:           tt.status.StatusPtr(),
+				StatusReason:     ptrString("admin role cannot be assumed"),
+				AdminRoleArn:     arn.New("aws", "iam", "", "123456789012", "role/AdminRole"),
+				PrincipalRoleArn: arn.New("aws", "iam", "", "123456789012", "role/PrincipalRole"),
+				LastModifiedOn:   aws.Int64(1561149393),
+				CreatedOn:        aws.Int64(1561149393),
+			})
 			assert.True(t, errors.Is(err, tt.expErr), "actual error %q doesn't match expected error %q", err, tt.expErr)
-
+			if tt.expErr == nil {
+				assert.Equal(t, account.StatusNotReady, *recovered.Status)
+				assert.Nil(t, recovered.StatusReason)
+				mocksEventer.AssertCalled(t, "AccountReset", recovered)
+			} else {
+				mocksEventer.AssertNotCalled(t, "AccountReset", mock.Anything)
+			}
 		})
 	}
 }
@@ -197,6 +384,53 @@ func TestUpdate(t *testing.T) {
 			},
 			returnErr: nil,
 		},
+		{
+			name: "should replace nuke filters on update",
+			origAccount: account.Account{
+				ID:           ptrString("123456789012"),
+				Status:       account.StatusReady.StatusPtr(),
+				AdminRoleArn: arn.New("aws", "iam", "", "123456789012", "role/AdminRoleArn"),
+				NukeFilters: account.NukeFilters{
+					"S3Bucket": {{Value: ptrString("old-bucket")}},
+				},
+				CreatedOn:      &now,
+				LastModifiedOn: &now,
+			},
+			updAccount: account.Account{
+				NukeFilters: account.NukeFilters{
+					"EC2VPC": {{Property: ptrString("tag:Name"), Value: ptrString("shared")}},
+				},
+			},
+			exp: response{
+				data: &account.Account{
+					ID:           ptrString("123456789012"),
+					Status:       account.StatusReady.StatusPtr(),
+					AdminRoleArn: arn.New("aws", "iam", "", "123456789012", "role/AdminRoleArn"),
+					NukeFilters: account.NukeFilters{
+						"EC2VPC": {{Property: ptrString("tag:Name"), Value: ptrString("shared")}},
+					},
+					LastModifiedOn: &now,
+					CreatedOn:      &now,
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "should fail validation of nuke filters on update",
+			origAccount: account.Account{
+				ID:     ptrString("123456789012"),
+				Status: account.StatusReady.StatusPtr(),
+			},
+			updAccount: account.Account{
+				NukeFilters: account.NukeFilters{
+					"EC2VPC": {{Property: ptrString("tag:Name")}},
+				},
+			},
+			exp: response{
+				data: nil,
+				err:  errors.NewValidation("account", fmt.Errorf("nukeFilters: EC2VPC[0] must have a value.")), //nolint golint
+			},
+		},
 		{
 			name: "should fail validation on update",
 			origAccount: account.Account{
@@ -480,6 +714,49 @@ func TestCreate(t *testing.T) {
 			accountCreateErr: nil,
 			accountResetErr:  nil,
 		},
+		{
+			name: "should create with nuke filters",
+			req: &account.Account{
+				ID:           ptrString("123456789012"),
+				AdminRoleArn: arn.New("aws", "iam", "", "123456789012", "role/AdminRole"),
+				NukeFilters: account.NukeFilters{
+					"Route53HostedZone": {{Type: account.NukeFilterTypeContains.NukeFilterTypePtr(), Value: ptrString("example.com")}},
+				},
+			},
+			exp: response{
+				data: &account.Account{
+					ID:             ptrString("123456789012"),
+					Status:         account.StatusNotReady.StatusPtr(),
+					AdminRoleArn:   arn.New("aws", "iam", "", "123456789012", "role/AdminRole"),
+					LastModifiedOn: &now,
+					CreatedOn:      &now,
+					NukeFilters: account.NukeFilters{
+						"Route53HostedZone": {{Type: account.NukeFilterTypeContains.NukeFilterTypePtr(), Value: ptrString("example.com")}},
+					},
+					PrincipalRoleArn:   arn.New("aws", "iam", "", "123456789012", "role/DCEPrincipal"),
+					PrincipalPolicyArn: arn.New("aws", "iam", "", "123456789012", "policy/DCEPrincipalDefaultPolicy"),
+				},
+				err: nil,
+			},
+			getResponse: response{
+				data: nil,
+				err:  errors.NewNotFound("account", "123456789012"),
+			},
+		},
+		{
+			name: "should fail on invalid nuke filters",
+			req: &account.Account{
+				ID:           ptrString("123456789012"),
+				AdminRoleArn: arn.New("aws", "iam", "", "123456789012", "role/AdminRole"),
+				NukeFilters: account.NukeFilters{
+					"Route53HostedZone": {},
+				},
+			},
+			exp: response{
+				data: nil,
+				err:  errors.NewValidation("account", fmt.Errorf("nukeFilters: Route53HostedZone must have at least one filter.")), //nolint golint
+			},
+		},
 		{
 			name: "should fail on account already exists",
 			req: &account.Account{
@@ -2,6 +2,7 @@ package account
 
 import (
 	"errors"
+	"fmt"
 	"reflect"
 	"regexp"
 
@@ -33,6 +34,15 @@ var validatePrincipalPolicyHash = []validation.Rule{
 	validation.NilOrNotEmpty.Error("must be a hash or empty"),
 }
 
+var validatePool = []validation.Rule{
+	validation.NilOrNotEmpty.Error("must be a pool name or empty"),
+	validation.Match(regexp.MustCompile("^[a-zA-Z0-9_-]{1,64}$")).Error("must only contain letters, numbers, hyphens and underscores"),
+}
+
+var validateNukeFilters = []validation.Rule{
+	validation.By(isValidNukeFilters),
+}
+
 var validateStatus = []validation.Rule{
 	validation.NotNil.Error("must be a valid account status"),
 }
@@ -44,6 +54,51 @@ func isNil(value interface{}) error {
 	return nil
 }
 
+var nukeResourceTypeRegex = regexp.MustCompile("^[A-Z][A-Za-z0-9]+$")
+
+// isValidNukeFilters validates the filters have aws-nuke resource types,
+// and each filter has a value which aws-nuke can match
+func isValidNukeFilters(value interface{}) error {
+	filters, _ := value.(NukeFilters)
+	for resourceType, resourceFilters := range filters {
+		if !nukeResourceTypeRegex.MatchString(resourceType) {
+			return fmt.Errorf("%q must be an aws-nuke resource type", resourceType)
+		}
+		if len(resourceFilters) == 0 {
+			return fmt.Errorf("%s must have at least one filter", resourceType)
+		}
+		for i, filter := range resourceFilters {
+			if filter.Value == nil || *filter.Value == "" {
+				return fmt.Errorf("%s[%d] must have a value", resourceType, i)
+			}
+			if filter.Property != nil && *filter.Property == "" {
+				return fmt.Errorf("%s[%d] must have a property name or no property", resourceType, i)
+			}
+			if filter.Type == nil {
+				continue
+			}
+			if !isValidNukeFilterType(*filter.Type) {
+				return fmt.Errorf("%s[%d] must have a type of %v", resourceType, i, ValidNukeFilterTypes)
+			}
+			if *filter.Type == NukeFilterTypeRegex {
+				if _, err := regexp.Compile(*filter.Value); err != nil {
+					return fmt.Errorf("%s[%d] must have a valid regular expression", resourceType, i)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func isValidNukeFilterType(filterType NukeFilterType) bool {
+	for _, t := range ValidNukeFilterTypes {
+		if filterType == t {
+			return true
+		}
+	}
+	return false
+}
+
 func isNilOrUsableAdminRole(am Manager) validation.RuleFunc {
 	return func(value interface{}) error {
 		if !reflect.ValueOf(value).IsNil() {
@@ -64,3 +119,19 @@ func isAccountNotLeased(value interface{}) error {
 	}
 	return nil
 }
+
+func isAccountNotRetired(value interface{}) error {
+	s, _ := value.(*Status)
+	if s.String() == StatusRetiring.String() || s.String() == StatusRetired.String() {
+		return errors.New("must not be retiring or retired")
+	}
+	return nil
+}
+
+func isAccountOrphaned(value interface{}) error {
+	s, _ := value.(*Status)
+	if s.String() != StatusOrphaned.String() {
+		return errors.New("must be orphaned")
+	}
+	return nil
+}
@@ -39,6 +39,98 @@ func TestValidate(t *testing.T) {
 			},
 			expErr: errors.NewValidation("account", fmt.Errorf("adminRoleArn: must be a string.")), //nolint golint
 		},
+		{
+			name: "should validate nuke filters",
+			account: account.Account{
+				ID:             ptrString("123456789012"),
+				Status:         account.StatusReady.StatusPtr(),
+				AdminRoleArn:   arn.New("aws", "iam", "", "123456789012", "role/AdminRoleArn"),
+				CreatedOn:      &now,
+				LastModifiedOn: &now,
+				NukeFilters: account.NukeFilters{
+					"S3Bucket": {
+						{Value: ptrString("s3://baseline-data")},
+					},
+					"EC2VPC": {
+						{
+							Type:     account.NukeFilterTypeRegex.NukeFilterTypePtr(),
+							Property: ptrString("tag:Name"),
+							Value:    ptrString("^shared-.*"),
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "should not validate nuke filters without a value",
+			account: account.Account{
+				ID:             ptrString("123456789012"),
+				Status:         account.StatusReady.StatusPtr(),
+				AdminRoleArn:   arn.New("aws", "iam", "", "123456789012", "role/AdminRoleArn"),
+				CreatedOn:      &now,
+				LastModifiedOn: &now,
+				NukeFilters: account.NukeFilters{
+					"S3Bucket": {
+						{Property: ptrString("Name")},
+					},
+				},
+			},
+			expErr: errors.NewValidation("account", fmt.Errorf("nukeFilters: S3Bucket[0] must have a value.")), //nolint golint
+		},
+		{
+			name: "should not validate nuke filters with an invalid resource type",
+			account: account.Account{
+				ID:             ptrString("123456789012"),
+				Status:         account.StatusReady.StatusPtr(),
+				AdminRoleArn:   arn.New("aws", "iam", "", "123456789012", "role/AdminRoleArn"),
+				CreatedOn:      &now,
+				LastModifiedOn: &now,
+				NukeFilters: account.NukeFilters{
+					"s3 bucket": {
+						{Value: ptrString("baseline-data")},
+					},
+				},
+			},
+			expErr: errors.NewValidation("account", fmt.Errorf("nukeFilters: \"s3 bucket\" must be an aws-nuke resource type.")), //nolint golint
+		},
+		{
+			name: "should not validate nuke filters with an invalid type",
+			account: account.Account{
+				ID:             ptrString("123456789012"),
+				Status:         account.StatusReady.StatusPtr(),
+				AdminRoleArn:   arn.New("aws", "iam", "", "123456789012", "role/AdminRoleArn"),
+				CreatedOn:      &now,
+				LastModifiedOn: &now,
+				NukeFilters: account.NukeFilters{
+					"S3Bucket": {
+						{
+							Type:  account.NukeFilterType("prefix").NukeFilterTypePtr(),
+							Value: ptrString("baseline-"),
+						},
+					},
+				},
+			},
+			expErr: errors.NewValidation("account", fmt.Errorf("nukeFilters: S3Bucket[0] must have a type of [exact contains glob regex dateOlderThan].")), //nolint golint
+		},
+		{
+			name: "should not validate nuke filters with an invalid regex",
+			account: account.Account{
+				ID:             ptrString("123456789012"),
+				Status:         account.StatusReady.StatusPtr(),
+				AdminRoleArn:   arn.New("aws", "iam", "", "123456789012", "role/AdminRoleArn"),
+				CreatedOn:      &now,
+				LastModifiedOn: &now,
+				NukeFilters: account.NukeFilters{
+					"S3Bucket": {
+						{
+							Type:  account.NukeFilterTypeRegex.NukeFilterTypePtr(),
+							Value: ptrString("baseline-(data"),
+						},
+					},
+				},
+			},
+			expErr: errors.NewValidation("account", fmt.Errorf("nukeFilters: S3Bucket[0] must have a valid regular expression.")), //nolint golint
+		},
 	}
 
 	for _, tt := range tests {
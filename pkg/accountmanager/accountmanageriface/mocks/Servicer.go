@@ -53,3 +53,31 @@ func (_m *Servicer) ValidateAccess(role *arn.ARN) error {
 
 	return r0
 }
+
+// ValidatePrincipalAccess provides a mock function with given fields: _a0
+func (_m *Servicer) ValidatePrincipalAccess(_a0 *account.Account) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*account.Account) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ValidateServiceControlPolicies provides a mock function with given fields: role
+func (_m *Servicer) ValidateServiceControlPolicies(role *arn.ARN) error {
+	ret := _m.Called(role)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*arn.ARN) error); ok {
+		r0 = rf(role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -15,4 +15,8 @@ type Servicer interface {
 	UpsertPrincipalAccess(account *account.Account) error
 	// DeletePrincipalAccess removes all the principal roles and policies
 	DeletePrincipalAccess(account *account.Account) error
+	// ValidatePrincipalAccess makes sure the principal role and policy exist
+	ValidatePrincipalAccess(account *account.Account) error
+	// ValidateServiceControlPolicies makes sure no SCP denies the role
+	ValidateServiceControlPolicies(role *arn.ARN) error
 }
@@ -5,6 +5,20 @@ import (
 	"github.com/aws/aws-sdk-go/service/iam"
 )
 
+// isAWSAccessDeniedError is true when a role can't be assumed,
+// because it doesn't exist or doesn't trust the caller
+func isAWSAccessDeniedError(err error) bool {
+	aerr, ok := err.(awserr.Error)
+	if ok {
+		switch aerr.Code() {
+		case "AccessDenied", "AccessDeniedException":
+			return true
+		}
+	}
+
+	return false
+}
+
 func isAWSAlreadyExistsError(err error) bool {
 	aerr, ok := err.(awserr.Error)
 	if ok {
@@ -3,6 +3,8 @@ package accountmanager
 import (
 	"fmt"
 	"log"
+	"net/url"
+	"strings"
 
 	"github.com/Optum/dce/pkg/account"
 	"github.com/Optum/dce/pkg/common"
@@ -180,6 +182,65 @@ func (p *principalService) DetachRoleWithPolicy() error {
 	return nil
 }
 
+func (p *principalService) ValidateRole() error {
+
+	_, err := p.iamSvc.GetRole(&iam.GetRoleInput{
+		RoleName: p.account.PrincipalRoleArn.IAMResourceName(),
+	})
+	if err != nil {
+		if isAWSNoSuchEntityError(err) {
+			return errors.NewValidation("account", fmt.Errorf("principal role %q does not exist", p.account.PrincipalRoleArn.String()))
+		}
+		return errors.NewInternalServer(fmt.Sprintf("unexpected error getting role %q", p.account.PrincipalRoleArn.String()), err)
+	}
+
+	return nil
+}
+
+func (p *principalService) ValidatePolicy() error {
+
+	policyOutput, err := p.iamSvc.GetPolicy(&iam.GetPolicyInput{
+		PolicyArn: aws.String(p.account.PrincipalPolicyArn.String()),
+	})
+	if err != nil {
+		if isAWSNoSuchEntityError(err) {
+			return errors.NewValidation("account", fmt.Errorf("principal policy %q does not exist", p.account.PrincipalPolicyArn.String()))
+		}
+		return errors.NewInternalServer(fmt.Sprintf("unexpected error getting policy %q", p.account.PrincipalPolicyArn.String()), err)
+	}
+
+	policy, policyHash, err := p.buildPolicy()
+	if err != nil {
+		return err
+	}
+
+	// The deployed policy can only be compared when it was built from the current template.
+	// Otherwise it is out of date, and will be updated the next time it is merged.
+	if p.account.PrincipalPolicyHash == nil || *p.account.PrincipalPolicyHash != *policyHash {
+		log.Printf("Principal policy %q is out of date (Ignoring)", p.account.PrincipalPolicyArn.String())
+		return nil
+	}
+
+	versionOutput, err := p.iamSvc.GetPolicyVersion(&iam.GetPolicyVersionInput{
+		PolicyArn: aws.String(p.account.PrincipalPolicyArn.String()),
+		VersionId: policyOutput.Policy.DefaultVersionId,
+	})
+	if err != nil {
+		return errors.NewInternalServer(fmt.Sprintf("unexpected error getting policy version on %q", p.account.PrincipalPolicyArn.String()), err)
+	}
+
+	// Policy documents are returned URL encoded
+	document, err := url.QueryUnescape(aws.StringValue(versionOutput.PolicyVersion.Document))
+	if err != nil {
+		return errors.NewInternalServer(fmt.Sprintf("unexpected error decoding policy version on %q", p.account.PrincipalPolicyArn.String()), err)
+	}
+	if strings.TrimSpace(document) != *policy {
+		return errors.NewValidation("account", fmt.Errorf("principal policy %q does not match hash %q", p.account.PrincipalPolicyArn.String(), *policyHash))
+	}
+
+	return nil
+}
+
 func (p *principalService) buildPolicy() (*string, *string, error) {
 
 	type principalPolicyInput struct {
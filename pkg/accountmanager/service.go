@@ -28,6 +28,7 @@ type ServiceConfig struct {
 	TagAppName                  string   `env:"TAG_APP_NAME" envDefault:"DefaultTagAppName"`
 	PrincipalRoleDescription    string   `env:"PRINCIPAL_ROLE_DESCRIPTION" envDefault:"Role for principal users of DCE"`
 	PrincipalPolicyDescription  string   `env:"PRINCIPAL_POLICY_DESCRIPTION" envDefault:"Policy for principal users of DCE"`
+	HealthCheckActions          []string `env:"HEALTH_CHECK_ACTIONS" envDefault:"iam:CreateRole,iam:CreatePolicy,ec2:DescribeRegions,sts:AssumeRole"`
 	tags                        []*iam.Tag
 	assumeRolePolicy            string
 }
@@ -39,14 +40,22 @@ type Service struct {
 	config   ServiceConfig
 }
 
-// ValidateAccess creates a new Account instance
+// ValidateAccess makes sure the role can be assumed.
+// Only access being denied is a validation error. Other errors,
+// like STS being throttled, are internal errors, which may be retried.
 func (s *Service) ValidateAccess(role *arn.ARN) error {
-	err := validation.Validate(role,
-		validation.NotNil,
-		validation.By(isAssumable(s.client)))
+	err := validation.Validate(role, validation.NotNil)
 	if err != nil {
 		return errors.NewValidation("account", err)
 	}
+
+	err = isAssumable(s.client)(role)
+	if err != nil {
+		if isAWSAccessDeniedError(err) {
+			return errors.NewValidation("account", err)
+		}
+		return errors.NewInternalServer(fmt.Sprintf("unexpected error assuming role %q", role.String()), err)
+	}
 	return nil
 }
 
@@ -123,6 +132,69 @@ func (s *Service) DeletePrincipalAccess(account *account.Account) error {
 	return nil
 }
 
+// ValidatePrincipalAccess makes sure the principal role and policy exist,
+// and the policy matches the principal policy hash
+func (s *Service) ValidatePrincipalAccess(account *account.Account) error {
+	err := validation.ValidateStruct(account,
+		validation.Field(&account.AdminRoleArn, validation.NotNil),
+		validation.Field(&account.PrincipalRoleArn, validation.NotNil),
+		validation.Field(&account.PrincipalPolicyArn, validation.NotNil),
+	)
+	if err != nil {
+		return errors.NewValidation("account", err)
+	}
+
+	iamSvc := s.client.IAM(account.AdminRoleArn)
+
+	principalSvc := principalService{
+		iamSvc:   iamSvc,
+		storager: s.storager,
+		account:  account,
+		config:   s.config,
+	}
+
+	err = principalSvc.ValidateRole()
+	if err != nil {
+		return err
+	}
+
+	err = principalSvc.ValidatePolicy()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ValidateServiceControlPolicies makes sure no service control policy
+// denies the role the actions needed to manage the account
+func (s *Service) ValidateServiceControlPolicies(role *arn.ARN) error {
+	err := validation.Validate(role, validation.NotNil)
+	if err != nil {
+		return errors.NewValidation("account", err)
+	}
+
+	iamSvc := s.client.IAM(role)
+
+	res, err := iamSvc.SimulatePrincipalPolicy(&iam.SimulatePrincipalPolicyInput{
+		PolicySourceArn: aws.String(role.String()),
+		ActionNames:     aws.StringSlice(s.config.HealthCheckActions),
+	})
+	if err != nil {
+		return errors.NewInternalServer(fmt.Sprintf("unexpected error simulating policies for %q", role.String()), err)
+	}
+
+	for _, result := range res.EvaluationResults {
+		// The decision detail is only returned for accounts within an organization
+		if result.OrganizationsDecisionDetail != nil && !aws.BoolValue(result.OrganizationsDecisionDetail.AllowedByOrganizations) {
+			return errors.NewValidation("account",
+				fmt.Errorf("action %q is denied by a service control policy", aws.StringValue(result.EvalActionName)))
+		}
+	}
+
+	return nil
+}
+
 // NewServiceInput are the items needed to create a new service
 type NewServiceInput struct {
 	Session  *session.Session
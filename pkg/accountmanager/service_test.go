@@ -49,13 +49,22 @@ func TestValidateAccess(t *testing.T) {
 			},
 		},
 		{
-			name: "should get an account by ID",
+			name: "should fail validation when access is denied",
 			arn:  arn.New("aws", "iam", "", "123456789012", "role/AdminAccess"),
 			assumeResp: assumeRoleOutput{
 				assumeRoleOutput: nil,
-				err:              fmt.Errorf("error"),
+				err:              awserr.New("AccessDenied", "not authorized to perform sts:AssumeRole", nil),
 			},
-			exp: errors.NewValidation("account", fmt.Errorf("error")),
+			exp: errors.NewValidation("account", awserr.New("AccessDenied", "not authorized to perform sts:AssumeRole", nil)),
+		},
+		{
+			name: "should fail with an internal error when STS fails",
+			arn:  arn.New("aws", "iam", "", "123456789012", "role/AdminAccess"),
+			assumeResp: assumeRoleOutput{
+				assumeRoleOutput: nil,
+				err:              awserr.New("Throttling", "Rate exceeded", nil),
+			},
+			exp: errors.NewInternalServer("unexpected error assuming role \"arn:aws:iam::123456789012:role/AdminAccess\"", nil),
 		},
 	}
 
@@ -479,3 +488,223 @@ func TestDeletePrincipalAccess(t *testing.T) {
 		})
 	}
 }
+
+func TestValidatePrincipalAccess(t *testing.T) {
+
+	type getRoleOutput struct {
+		output *iam.GetRoleOutput
+		err    error
+	}
+
+	type getPolicyOutput struct {
+		output *iam.GetPolicyOutput
+		err    error
+	}
+
+	type getPolicyVersionOutput struct {
+		output *iam.GetPolicyVersionOutput
+		err    error
+	}
+
+	tests := []struct {
+		name                   string
+		exp                    error
+		policyHash             *string
+		getRoleOutput          getRoleOutput
+		getPolicyOutput        getPolicyOutput
+		getPolicyVersionOutput getPolicyVersionOutput
+	}{
+		{
+			name:       "should pass when role and policy match",
+			policyHash: aws.String("123"),
+			getRoleOutput: getRoleOutput{
+				output: &iam.GetRoleOutput{},
+			},
+			getPolicyOutput: getPolicyOutput{
+				output: &iam.GetPolicyOutput{
+					Policy: &iam.Policy{DefaultVersionId: aws.String("v1")},
+				},
+			},
+			getPolicyVersionOutput: getPolicyVersionOutput{
+				output: &iam.GetPolicyVersionOutput{
+					PolicyVersion: &iam.PolicyVersion{Document: aws.String("%7B%22Version%22%3A%222012-10-17%22%7D")},
+				},
+			},
+		},
+		{
+			name:       "should pass when policy is out of date",
+			policyHash: aws.String("122"),
+			getRoleOutput: getRoleOutput{
+				output: &iam.GetRoleOutput{},
+			},
+			getPolicyOutput: getPolicyOutput{
+				output: &iam.GetPolicyOutput{
+					Policy: &iam.Policy{DefaultVersionId: aws.String("v1")},
+				},
+			},
+		},
+		{
+			name:       "should fail when role does not exist",
+			policyHash: aws.String("123"),
+			getRoleOutput: getRoleOutput{
+				err: awserr.New(iam.ErrCodeNoSuchEntityException, "No Such Entity", nil),
+			},
+			exp: errors.NewValidation("account", fmt.Errorf("principal role \"arn:aws:iam::123456789012:role/DCEPrincipal\" does not exist")),
+		},
+		{
+			name:       "should fail when policy does not exist",
+			policyHash: aws.String("123"),
+			getRoleOutput: getRoleOutput{
+				output: &iam.GetRoleOutput{},
+			},
+			getPolicyOutput: getPolicyOutput{
+				err: awserr.New(iam.ErrCodeNoSuchEntityException, "No Such Entity", nil),
+			},
+			exp: errors.NewValidation("account", fmt.Errorf("principal policy \"arn:aws:iam::123456789012:policy/DCEPrincipalDefaultPolicy\" does not exist")),
+		},
+		{
+			name:       "should fail when policy has been changed",
+			policyHash: aws.String("123"),
+			getRoleOutput: getRoleOutput{
+				output: &iam.GetRoleOutput{},
+			},
+			getPolicyOutput: getPolicyOutput{
+				output: &iam.GetPolicyOutput{
+					Policy: &iam.Policy{DefaultVersionId: aws.String("v2")},
+				},
+			},
+			getPolicyVersionOutput: getPolicyVersionOutput{
+				output: &iam.GetPolicyVersionOutput{
+					PolicyVersion: &iam.PolicyVersion{Document: aws.String("%7B%7D")},
+				},
+			},
+			exp: errors.NewValidation("account", fmt.Errorf("principal policy \"arn:aws:iam::123456789012:policy/DCEPrincipalDefaultPolicy\" does not match hash \"123\"")),
+		},
+		{
+			name:       "should fail when getting the role fails",
+			policyHash: aws.String("123"),
+			getRoleOutput: getRoleOutput{
+				err: awserr.New(iam.ErrCodeServiceFailureException, "Service Failure", nil),
+			},
+			exp: errors.NewInternalServer("unexpected error getting role \"arn:aws:iam::123456789012:role/DCEPrincipal\"", nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			iamSvc := &awsMocks.IAM{}
+			iamSvc.On("GetRole", mock.AnythingOfType("*iam.GetRoleInput")).
+				Return(tt.getRoleOutput.output, tt.getRoleOutput.err)
+			iamSvc.On("GetPolicy", mock.AnythingOfType("*iam.GetPolicyInput")).
+				Return(tt.getPolicyOutput.output, tt.getPolicyOutput.err)
+			iamSvc.On("GetPolicyVersion", mock.AnythingOfType("*iam.GetPolicyVersionInput")).
+				Return(tt.getPolicyVersionOutput.output, tt.getPolicyVersionOutput.err)
+
+			storagerSvc := &commonMocks.Storager{}
+			storagerSvc.On(
+				"GetTemplateObject", "DefaultArtifactBucket", "DefaultPrincipalPolicyS3Key",
+				mock.Anything).Return("{\"Version\":\"2012-10-17\"}", "123", nil)
+
+			clientSvc := &mocks.Clienter{}
+			clientSvc.On("IAM", mock.Anything).Return(iamSvc)
+
+			amSvc, err := NewService(NewServiceInput{
+				Session:  session.Must(session.NewSession()),
+				Storager: storagerSvc,
+				Config:   testConfig,
+			})
+			amSvc.client = clientSvc
+
+			assert.Nil(t, err)
+
+			err = amSvc.ValidatePrincipalAccess(&account.Account{
+				ID:                  aws.String("123456789012"),
+				PrincipalRoleArn:    arn.New("aws", "iam", "", "123456789012", "role/DCEPrincipal"),
+				AdminRoleArn:        arn.New("aws", "iam", "", "123456789012", "role/AdminAccess"),
+				PrincipalPolicyArn:  arn.New("aws", "iam", "", "123456789012", "policy/DCEPrincipalDefaultPolicy"),
+				PrincipalPolicyHash: tt.policyHash,
+			})
+
+			assert.True(t, errors.Is(err, tt.exp), "actual error %+v doesn't match expected error %+v", err, tt.exp)
+		})
+	}
+}
+
+func TestValidateServiceControlPolicies(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		exp    error
+		output *iam.SimulatePolicyResponse
+		err    error
+	}{
+		{
+			name: "should pass when organizations allows all actions",
+			output: &iam.SimulatePolicyResponse{
+				EvaluationResults: []*iam.EvaluationResult{
+					{
+						EvalActionName:              aws.String("iam:CreateRole"),
+						OrganizationsDecisionDetail: &iam.OrganizationsDecisionDetail{AllowedByOrganizations: aws.Bool(true)},
+					},
+				},
+			},
+		},
+		{
+			name: "should pass when account is not within an organization",
+			output: &iam.SimulatePolicyResponse{
+				EvaluationResults: []*iam.EvaluationResult{
+					{
+						EvalActionName: aws.String("iam:CreateRole"),
+					},
+				},
+			},
+		},
+		{
+			name: "should fail when an action is denied by an SCP",
+			output: &iam.SimulatePolicyResponse{
+				EvaluationResults: []*iam.EvaluationResult{
+					{
+						EvalActionName:              aws.String("iam:CreateRole"),
+						OrganizationsDecisionDetail: &iam.OrganizationsDecisionDetail{AllowedByOrganizations: aws.Bool(true)},
+					},
+					{
+						EvalActionName:              aws.String("ec2:DescribeRegions"),
+						OrganizationsDecisionDetail: &iam.OrganizationsDecisionDetail{AllowedByOrganizations: aws.Bool(false)},
+					},
+				},
+			},
+			exp: errors.NewValidation("account", fmt.Errorf("action \"ec2:DescribeRegions\" is denied by a service control policy")),
+		},
+		{
+			name: "should fail when simulating fails",
+			err:  awserr.New(iam.ErrCodeInvalidInputException, "Invalid Input", nil),
+			exp:  errors.NewInternalServer("unexpected error simulating policies for \"arn:aws:iam::123456789012:role/AdminAccess\"", nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			iamSvc := &awsMocks.IAM{}
+			iamSvc.On("SimulatePrincipalPolicy", mock.MatchedBy(func(input *iam.SimulatePrincipalPolicyInput) bool {
+				return *input.PolicySourceArn == "arn:aws:iam::123456789012:role/AdminAccess" &&
+					len(input.ActionNames) == 2
+			})).Return(tt.output, tt.err)
+
+			clientSvc := &mocks.Clienter{}
+			clientSvc.On("IAM", mock.Anything).Return(iamSvc)
+
+			cfg := testConfig
+			cfg.HealthCheckActions = []string{"iam:CreateRole", "ec2:DescribeRegions"}
+			amSvc, err := NewService(NewServiceInput{
+				Session: session.Must(session.NewSession()),
+				Config:  cfg,
+			})
+			amSvc.client = clientSvc
+
+			assert.Nil(t, err)
+
+			err = amSvc.ValidateServiceControlPolicies(arn.New("aws", "iam", "", "123456789012", "role/AdminAccess"))
+			assert.True(t, errors.Is(err, tt.exp), "actual error %+v doesn't match expected error %+v", err, tt.exp)
+		})
+	}
+}
@@ -26,3 +26,26 @@ func (_m *UserDetailer) GetUser(reqCtx *events.APIGatewayProxyRequestContext) *a
 
 	return r0
 }
+
+// ListGroups provides a mock function with given fields: username
+func (_m *UserDetailer) ListGroups(username string) ([]string, error) {
+	ret := _m.Called(username)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(string) []string); ok {
+		r0 = rf(username)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
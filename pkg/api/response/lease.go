@@ -2,12 +2,16 @@ package response
 
 import (
 	"github.com/Optum/dce/pkg/db"
+	"github.com/Optum/dce/pkg/notification"
 )
 
 // CreateLeaseResponse creates an Lease Response based
-// on the provided Lease
+// on the provided Lease. Notification target URLs are secrets, so they are redacted.
 func CreateLeaseResponse(lease *db.Lease) *LeaseResponse {
 	response := LeaseResponse(*lease)
+	if response.NotificationTargets != nil {
+		response.NotificationTargets = notification.RedactTargets(response.NotificationTargets)
+	}
 	return &response
 }
 
@@ -24,17 +28,19 @@ func CreateLeaseResponse(lease *db.Lease) *LeaseResponse {
 // 	"BudgetNotificationEmails": ["usermsid@test.com", "managersmsid@test.com"]
 // }
 type LeaseResponse struct {
-	AccountID                string                 `json:"accountId"`
-	PrincipalID              string                 `json:"principalId"`
-	ID                       string                 `json:"id"`
-	LeaseStatus              db.LeaseStatus         `json:"leaseStatus"`
-	LeaseStatusReason        db.LeaseStatusReason   `json:"leaseStatusReason"`
-	CreatedOn                int64                  `json:"createdOn"`
-	LastModifiedOn           int64                  `json:"lastModifiedOn"`
-	BudgetAmount             float64                `json:"budgetAmount"`
-	BudgetCurrency           string                 `json:"budgetCurrency"`
-	BudgetNotificationEmails []string               `json:"budgetNotificationEmails"`
-	LeaseStatusModifiedOn    int64                  `json:"leaseStatusModifiedOn"`
-	ExpiresOn                int64                  `json:"expiresOn"`
-	Metadata                 map[string]interface{} `json:"metadata"`
+	AccountID                string                       `json:"accountId"`
+	PrincipalID              string                       `json:"principalId"`
+	ID                       string                       `json:"id"`
+	LeaseStatus              db.LeaseStatus               `json:"leaseStatus"`
+	LeaseStatusReason        db.LeaseStatusReason         `json:"leaseStatusReason"`
+	CreatedOn                int64                        `json:"createdOn"`
+	LastModifiedOn           int64                        `json:"lastModifiedOn"`
+	BudgetAmount             float64                      `json:"budgetAmount"`
+	BudgetCurrency           string                       `json:"budgetCurrency"`
+	BudgetNotificationEmails []string                     `json:"budgetNotificationEmails"`
+	NotificationTargets      []string                     `json:"notificationTargets"`
+	BudgetNotifications      []db.LeaseBudgetNotification `json:"budgetNotifications"`
+	LeaseStatusModifiedOn    int64                        `json:"leaseStatusModifiedOn"`
+	ExpiresOn                int64                        `json:"expiresOn"`
+	Metadata                 map[string]interface{}       `json:"metadata"`
 }
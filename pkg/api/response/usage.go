@@ -11,3 +11,12 @@ type UsageResponse struct {
 	CostCurrency string  `json:"costCurrency"` // Cost currency
 	TimeToLive   int64   `json:"timeToLive"`   // ttl attribute
 }
+
+// ServiceUsageResponse is the serialized JSON Response for the usage
+// of a single AWS service, returned by the usage API when grouping by service
+type ServiceUsageResponse struct {
+	PrincipalID  string  `json:"principalId"`  // User Principal ID
+	ServiceName  string  `json:"serviceName"`  // AWS service name
+	CostAmount   float64 `json:"costAmount"`   // Cost Amount for given period
+	CostCurrency string  `json:"costCurrency"` // Cost currency
+}
@@ -7,11 +7,13 @@ import (
 	"github.com/awslabs/aws-lambda-go-api-proxy/gorillamux"
 	"log"
 	"net/http"
+	"sort"
 	"strings"
 
 	"github.com/Optum/dce/pkg/awsiface"
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/cognitoidentityprovider"
 )
 
@@ -46,6 +48,7 @@ func (u *User) Authorize(principalID string) error {
 //go:generate mockery -name UserDetailer
 type UserDetailer interface {
 	GetUser(reqCtx *events.APIGatewayProxyRequestContext) *User
+	ListGroups(username string) ([]string, error)
 }
 
 // UserDetails - Gets User information
@@ -113,22 +116,64 @@ func (u *UserDetails) GetUser(reqCtx *events.APIGatewayProxyRequestContext) *Use
 
 func (u *UserDetails) isUserInAdminGroup(username string) (bool, error) {
 
-	groups, err := u.CognitoClient.AdminListGroupsForUser(&cognitoidentityprovider.AdminListGroupsForUserInput{
-		Username:   aws.String(username),
-		UserPoolId: aws.String(u.CognitoUserPoolID),
-	})
+	groups, err := u.ListGroups(username)
 	if err != nil {
 		log.Printf("Was not abile to query a users for its groups: %s", err)
 		return false, fmt.Errorf("Was not abile to query a users for its groups: %s", err)
 	}
-	for _, group := range groups.Groups {
-		if *group.GroupName == "Admins" {
+	for _, group := range groups {
+		if group == "Admins" {
 			return true, nil
 		}
 	}
 	return false, nil
 }
 
+// ListGroups returns the names of the Cognito groups the user belongs to,
+// in order of group precedence.
+// Returns no groups for principals which are not Cognito users.
+func (u *UserDetails) ListGroups(username string) ([]string, error) {
+	if u.CognitoUserPoolID == "" {
+		return []string{}, nil
+	}
+
+	// Cognito returns the groups a page at a time
+	cognitoGroups := []*cognitoidentityprovider.GroupType{}
+	input := &cognitoidentityprovider.AdminListGroupsForUserInput{
+		Username:   aws.String(username),
+		UserPoolId: aws.String(u.CognitoUserPoolID),
+	}
+	for {
+		res, err := u.CognitoClient.AdminListGroupsForUser(input)
+		if err != nil {
+			if aerr, ok := err.(awserr.Error); ok && aerr.Code() == cognitoidentityprovider.ErrCodeUserNotFoundException {
+				return []string{}, nil
+			}
+			return nil, err
+		}
+		cognitoGroups = append(cognitoGroups, res.Groups...)
+		if aws.StringValue(res.NextToken) == "" {
+			break
+		}
+		input.NextToken = res.NextToken
+	}
+
+	// Groups with a lower precedence value take priority.
+	// Groups without a precedence come last.
+	sort.SliceStable(cognitoGroups, func(i, j int) bool {
+		if cognitoGroups[j].Precedence == nil {
+			return cognitoGroups[i].Precedence != nil
+		}
+		return cognitoGroups[i].Precedence != nil && *cognitoGroups[i].Precedence < *cognitoGroups[j].Precedence
+	})
+
+	groups := []string{}
+	for _, group := range cognitoGroups {
+		groups = append(groups, *group.GroupName)
+	}
+	return groups, nil
+}
+
 func (u *UserDetails) isUserInAdminFromList(groups string) bool {
 
 	for _, group := range strings.Split(groups, ",") {
@@ -9,6 +9,7 @@ import (
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/cognitoidentityprovider"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -370,3 +371,38 @@ func TestUser(t *testing.T) {
 		require.Equal(t, user.Role, api.UserGroupName)
 	})
 }
+
+func TestListGroups(t *testing.T) {
+	mockCognitoIdp := &mocks.CognitoIdentityProviderAPI{}
+	userDetails := api.UserDetails{
+		CognitoUserPoolID: "us_east_1-test",
+		CognitoClient:     mockCognitoIdp,
+	}
+
+	mockCognitoIdp.On("AdminListGroupsForUser", mock.MatchedBy(func(input *cognitoidentityprovider.AdminListGroupsForUserInput) bool {
+		return input.NextToken == nil
+	})).Return(&cognitoidentityprovider.AdminListGroupsForUserOutput{
+		Groups: []*cognitoidentityprovider.GroupType{
+			{
+				GroupName:  aws.String("Users"),
+				Precedence: aws.Int64(2),
+			},
+		},
+		NextToken: aws.String("next"),
+	}, nil).Once()
+	mockCognitoIdp.On("AdminListGroupsForUser", mock.MatchedBy(func(input *cognitoidentityprovider.AdminListGroupsForUserInput) bool {
+		return aws.StringValue(input.NextToken) == "next"
+	})).Return(&cognitoidentityprovider.AdminListGroupsForUserOutput{
+		Groups: []*cognitoidentityprovider.GroupType{
+			{
+				GroupName:  aws.String("Admins"),
+				Precedence: aws.Int64(1),
+			},
+		},
+	}, nil).Once()
+
+	groups, err := userDetails.ListGroups("testuser")
+	require.Nil(t, err)
+	require.Equal(t, []string{"Admins", "Users"}, groups)
+	mockCognitoIdp.AssertExpectations(t)
+}
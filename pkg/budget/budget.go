@@ -1,20 +1,68 @@
 package budget
 
 import (
+	"fmt"
 	"github.com/Optum/dce/pkg/awsiface"
+	"github.com/Optum/dce/pkg/common"
 	"strconv"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/costexplorer"
+	"github.com/aws/aws-sdk-go/service/s3"
 )
 
+const (
+	// CostProviderCostExplorer calculates spend with the AWS Cost Explorer API
+	CostProviderCostExplorer = "CostExplorer"
+	// CostProviderCUR calculates spend from AWS Cost and Usage Reports in S3
+	CostProviderCUR = "CUR"
+)
+
+// ForecastHistoryDays is the number of days of the account's spend
+// history that ForecastSpend is based on. Spend forecasts are only
+// representative of a lease once it has been active this long.
+const ForecastHistoryDays = 7
+
 // Define a Service, so we can mock this service from other components
 // (eg, if I'm testing a Lambda controller that uses this Service)
 //go:generate mockery -name Service
 type Service interface {
 	CalculateTotalSpend(startDate time.Time, endDate time.Time) (float64, error)
+	CalculateSpendByService(startDate time.Time, endDate time.Time) (map[string]float64, error)
+	ForecastSpend(startDate time.Time, endDate time.Time) (float64, error)
 	SetCostExplorer(costExplorer awsiface.CostExplorerAPI)
+	SetAccountID(accountID string)
+}
+
+/*
+NewFromEnv creates a Service for the cost provider configured
+in environment variables.
+Requires env vars for:
+
+- COST_PROVIDER (CostExplorer or CUR, defaults to CostExplorer)
+- CUR_BUCKET, CUR_PREFIX and CUR_REPORT_NAME, for the CUR cost provider
+*/
+func NewFromEnv() (Service, error) {
+	costProvider := common.GetEnv("COST_PROVIDER", CostProviderCostExplorer)
+	switch costProvider {
+	case CostProviderCostExplorer:
+		return &AWSBudgetService{}, nil
+	case CostProviderCUR:
+		awsSession, err := session.NewSession()
+		if err != nil {
+			return nil, err
+		}
+		return &CURBudgetService{
+			S3:         s3.New(awsSession),
+			Bucket:     common.RequireEnv("CUR_BUCKET"),
+			Prefix:     common.GetEnv("CUR_PREFIX", ""),
+			ReportName: common.RequireEnv("CUR_REPORT_NAME"),
+		}, nil
+	}
+	return nil, fmt.Errorf("Invalid cost provider %s, must be %s or %s",
+		costProvider, CostProviderCostExplorer, CostProviderCUR)
 }
 
 // Define a concrete implementation of the Service interface
@@ -26,6 +74,10 @@ func (budgetSvc *AWSBudgetService) SetCostExplorer(costExplorer awsiface.CostExp
 	budgetSvc.CostExplorer = costExplorer
 }
 
+// SetAccountID does nothing, as Cost Explorer is scoped to the account
+// of the CostExplorer client
+func (budgetSvc *AWSBudgetService) SetAccountID(accountID string) {}
+
 // Implement the CalculateTotalSpend method of the Service interface
 func (budgetSvc *AWSBudgetService) CalculateTotalSpend(startDate time.Time, endDate time.Time) (float64, error) {
 
@@ -66,3 +118,95 @@ func (budgetSvc *AWSBudgetService) CalculateTotalSpend(startDate time.Time, endD
 	}
 	return totalCost, nil
 }
+
+// CalculateSpendByService returns the spend between the start and end dates,
+// grouped by AWS service name (eg. "Amazon Elastic Compute Cloud - Compute")
+func (budgetSvc *AWSBudgetService) CalculateSpendByService(startDate time.Time, endDate time.Time) (map[string]float64, error) {
+	timeFormat := "2006-01-02"
+	timePeriod := costexplorer.DateInterval{
+		Start: aws.String(startDate.UTC().Format(timeFormat)),
+		End:   aws.String(endDate.UTC().Format(timeFormat)),
+	}
+
+	getCostAndUsageInput := costexplorer.GetCostAndUsageInput{
+		Metrics:     []*string{aws.String("UnblendedCost")},
+		TimePeriod:  &timePeriod,
+		Granularity: aws.String("DAILY"),
+		GroupBy: []*costexplorer.GroupDefinition{
+			{
+				Type: aws.String("DIMENSION"),
+				Key:  aws.String("SERVICE"),
+			},
+		},
+	}
+
+	serviceCosts := map[string]float64{}
+	for {
+		output, err := budgetSvc.CostExplorer.GetCostAndUsage(&getCostAndUsageInput)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, result := range output.ResultsByTime {
+			for _, group := range result.Groups {
+				if len(group.Keys) == 0 {
+					continue
+				}
+				cost, err := strconv.ParseFloat(*group.Metrics["UnblendedCost"].Amount, 64)
+				if err != nil {
+					return nil, err
+				}
+				serviceCosts[*group.Keys[0]] = serviceCosts[*group.Keys[0]] + cost
+			}
+		}
+
+		if output.NextPageToken == nil {
+			break
+		}
+		getCostAndUsageInput.NextPageToken = output.NextPageToken
+	}
+
+	return serviceCosts, nil
+}
+
+// ForecastSpend returns the spend Cost Explorer forecasts between the start and
+// end dates. The start date may not be earlier than today.
+func (budgetSvc *AWSBudgetService) ForecastSpend(startDate time.Time, endDate time.Time) (float64, error) {
+	timeFormat := "2006-01-02"
+	start := startDate.UTC().Format(timeFormat)
+	end := endDate.UTC().Format(timeFormat)
+	// Cost Explorer rejects empty time periods
+	if end <= start {
+		return 0, nil
+	}
+
+	output, err := budgetSvc.CostExplorer.GetCostForecast(&costexplorer.GetCostForecastInput{
+		Metric:      aws.String("UNBLENDED_COST"),
+		Granularity: aws.String("DAILY"),
+		TimePeriod: &costexplorer.DateInterval{
+			Start: aws.String(start),
+			End:   aws.String(end),
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+	if output.Total == nil || output.Total.Amount == nil {
+		return 0, nil
+	}
+
+	return strconv.ParseFloat(*output.Total.Amount, 64)
+}
+
+// ProjectSpend linearly projects the spend at the end date, given the spend
+// between the start date and the current date.
+// Used when Cost Explorer does not have enough history to forecast spend.
+func ProjectSpend(actualSpend float64, startDate time.Time, currentDate time.Time, endDate time.Time) float64 {
+	elapsed := currentDate.Sub(startDate)
+	if elapsed <= 0 || !endDate.After(currentDate) {
+		return actualSpend
+	}
+
+	remaining := endDate.Sub(currentDate)
+	return actualSpend + actualSpend*(remaining.Seconds()/elapsed.Seconds())
+}
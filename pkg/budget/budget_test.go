@@ -51,3 +51,118 @@ func TestCalculateTotalSpend(t *testing.T) {
 	assert.Nil(t, err, "There should be no errors")
 	assert.Equal(t, cost, float64(150))
 }
+
+func TestCalculateSpendByService(t *testing.T) {
+	input := &costexplorer.GetCostAndUsageInput{
+		Metrics:     []*string{aws.String("UnblendedCost")},
+		Granularity: aws.String("DAILY"),
+		TimePeriod: &costexplorer.DateInterval{
+			Start: aws.String("1970-01-01"),
+			End:   aws.String("1970-01-03"),
+		},
+		GroupBy: []*costexplorer.GroupDefinition{
+			{
+				Type: aws.String("DIMENSION"),
+				Key:  aws.String("SERVICE"),
+			},
+		},
+	}
+	nextPageInput := *input
+	nextPageInput.NextPageToken = aws.String("next-page")
+
+	// Mock the CostExplorer SDK, with two pages of results
+	costExplorer := &mocks.CostExplorerAPI{}
+	costExplorer.On("GetCostAndUsage", input).Return(&costexplorer.GetCostAndUsageOutput{
+		ResultsByTime: []*costexplorer.ResultByTime{
+			{
+				Groups: []*costexplorer.Group{
+					{
+						Keys: []*string{aws.String("Amazon Elastic Compute Cloud - Compute")},
+						Metrics: map[string]*costexplorer.MetricValue{
+							"UnblendedCost": {Amount: aws.String("100"), Unit: aws.String("USD")},
+						},
+					},
+					{
+						Keys: []*string{aws.String("EC2 - Other")},
+						Metrics: map[string]*costexplorer.MetricValue{
+							"UnblendedCost": {Amount: aws.String("25"), Unit: aws.String("USD")},
+						},
+					},
+				},
+			},
+		},
+		NextPageToken: aws.String("next-page"),
+	}, nil).Once()
+	costExplorer.On("GetCostAndUsage", &nextPageInput).Return(&costexplorer.GetCostAndUsageOutput{
+		ResultsByTime: []*costexplorer.ResultByTime{
+			{
+				Groups: []*costexplorer.Group{
+					{
+						Keys: []*string{aws.String("Amazon Elastic Compute Cloud - Compute")},
+						Metrics: map[string]*costexplorer.MetricValue{
+							"UnblendedCost": {Amount: aws.String("50"), Unit: aws.String("USD")},
+						},
+					},
+				},
+			},
+		},
+	}, nil).Once()
+
+	budgetSvc := AWSBudgetService{
+		CostExplorer: costExplorer,
+	}
+	costs, err := budgetSvc.CalculateSpendByService(
+		time.Unix(0, 0),
+		time.Unix(0, 0).Add(time.Hour*48),
+	)
+	assert.Nil(t, err, "There should be no errors")
+	assert.Equal(t, map[string]float64{
+		"Amazon Elastic Compute Cloud - Compute": 150,
+		"EC2 - Other":                            25,
+	}, costs)
+}
+
+func TestForecastSpend(t *testing.T) {
+	// Mock the CostExplorer SDK
+	costExplorer := &mocks.CostExplorerAPI{}
+	costExplorer.On("GetCostForecast", &costexplorer.GetCostForecastInput{
+		Metric:      aws.String("UNBLENDED_COST"),
+		Granularity: aws.String("DAILY"),
+		TimePeriod: &costexplorer.DateInterval{
+			Start: aws.String("1970-01-02"),
+			End:   aws.String("1970-01-05"),
+		},
+	}).Return(&costexplorer.GetCostForecastOutput{
+		Total: &costexplorer.MetricValue{
+			Amount: aws.String("75.5"),
+			Unit:   aws.String("USD"),
+		},
+	}, nil)
+
+	budgetSvc := AWSBudgetService{
+		CostExplorer: costExplorer,
+	}
+	cost, err := budgetSvc.ForecastSpend(
+		time.Unix(0, 0).Add(time.Hour*24),
+		time.Unix(0, 0).Add(time.Hour*24*4),
+	)
+	assert.Nil(t, err, "There should be no errors")
+	assert.Equal(t, float64(75.5), cost)
+
+	// Should not call Cost Explorer for an empty time period
+	cost, err = budgetSvc.ForecastSpend(time.Unix(0, 0), time.Unix(0, 0))
+	assert.Nil(t, err, "There should be no errors")
+	assert.Equal(t, float64(0), cost)
+	costExplorer.AssertNumberOfCalls(t, "GetCostForecast", 1)
+}
+
+func TestProjectSpend(t *testing.T) {
+	startDate := time.Unix(0, 0)
+
+	// $10 over one day, projected over four days
+	assert.Equal(t, float64(40), ProjectSpend(10, startDate, startDate.AddDate(0, 0, 1), startDate.AddDate(0, 0, 4)))
+	// Past the end date, nothing is projected
+	assert.Equal(t, float64(10), ProjectSpend(10, startDate, startDate.AddDate(0, 0, 4), startDate.AddDate(0, 0, 1)))
+	// No elapsed time, nothing is projected
+	assert.Equal(t, float64(10), ProjectSpend(10, startDate, startDate, startDate.AddDate(0, 0, 1)))
+}
@@ -0,0 +1,279 @@
+package budget
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Optum/dce/pkg/awsiface"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// CUR report columns used to attribute spend
+const (
+	curUsageAccountIDColumn = "lineItem/UsageAccountId"
+	curUsageStartDateColumn = "lineItem/UsageStartDate"
+	curUnblendedCostColumn  = "lineItem/UnblendedCost"
+	curProductNameColumn    = "product/ProductName"
+	curProductCodeColumn    = "lineItem/ProductCode"
+)
+
+// curManifestTTL is how long a report is used for, before checking
+// its manifest for a new delivery. Reports are delivered up to three times a day.
+const curManifestTTL = time.Hour
+
+// CURBudgetService implements the Service interface, by reading
+// AWS Cost and Usage Reports (CUR) from S3.
+// Reports are delivered to the master account with hourly line items
+// for all linked accounts, so spend is attributed by the linked account ID.
+//
+// Reports are large, so each delivery is only read once, and aggregated into
+// daily spend for every linked account. Reuse the service across leases,
+// so that other accounts' spend is read from the aggregated report.
+type CURBudgetService struct {
+	S3 awsiface.S3API
+	// Bucket and prefix the reports are delivered to
+	Bucket string
+	Prefix string
+	// Name of the Cost and Usage Report
+	ReportName string
+	// Linked account to calculate spend for
+	AccountID string
+	// Aggregated reports, by manifest key
+	reports map[string]*curReport
+}
+
+type curManifest struct {
+	AssemblyID string   `json:"assemblyId"`
+	ReportKeys []string `json:"reportKeys"`
+}
+
+// curDailyCosts is spend by linked account ID, then by day (as Epoch),
+// then by AWS product name
+type curDailyCosts map[string]map[int64]map[string]float64
+
+// curReport is the aggregated spend of a report delivery
+type curReport struct {
+	assemblyID string
+	checkedOn  time.Time
+	dailyCosts curDailyCosts
+}
+
+// SetCostExplorer does nothing, as Cost and Usage Reports are read from S3
+func (budgetSvc *CURBudgetService) SetCostExplorer(costExplorer awsiface.CostExplorerAPI) {}
+
+// SetAccountID sets the linked account to calculate spend for
+func (budgetSvc *CURBudgetService) SetAccountID(accountID string) {
+	budgetSvc.AccountID = accountID
+}
+
+// CalculateTotalSpend returns the spend of the account between the start and end dates
+func (budgetSvc *CURBudgetService) CalculateTotalSpend(startDate time.Time, endDate time.Time) (float64, error) {
+	serviceCosts, err := budgetSvc.CalculateSpendByService(startDate, endDate)
+	if err != nil {
+		return 0, err
+	}
+
+	var totalCost float64
+	for _, cost := range serviceCosts {
+		totalCost = totalCost + cost
+	}
+	return totalCost, nil
+}
+
+// CalculateSpendByService returns the spend of the account between the start and end dates,
+// grouped by AWS product name (eg. "Amazon Elastic Compute Cloud")
+// As with Cost Explorer, the start and end dates are whole days, and the end date is exclusive.
+func (budgetSvc *CURBudgetService) CalculateSpendByService(startDate time.Time, endDate time.Time) (map[string]float64, error) {
+	start := truncateToDay(startDate)
+	end := truncateToDay(endDate)
+
+	serviceCosts := map[string]float64{}
+	// Reports are delivered for each billing period (calendar month)
+	for billingPeriod := time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC); billingPeriod.Before(end); billingPeriod = billingPeriod.AddDate(0, 1, 0) {
+		report, err := budgetSvc.getReport(billingPeriod)
+		if err != nil {
+			return nil, err
+		}
+
+		for day, dayCosts := range report.dailyCosts[budgetSvc.AccountID] {
+			if day < start.Unix() || day >= end.Unix() {
+				continue
+			}
+			for serviceName, cost := range dayCosts {
+				serviceCosts[serviceName] = serviceCosts[serviceName] + cost
+			}
+		}
+	}
+
+	return serviceCosts, nil
+}
+
+// ForecastSpend projects the average daily spend of the account
+// over the last ForecastHistoryDays, between the start and end dates.
+func (budgetSvc *CURBudgetService) ForecastSpend(startDate time.Time, endDate time.Time) (float64, error) {
+	start := truncateToDay(startDate)
+	end := truncateToDay(endDate)
+	if !end.After(start) {
+		return 0, nil
+	}
+
+	historySpend, err := budgetSvc.CalculateTotalSpend(start.AddDate(0, 0, -ForecastHistoryDays), start)
+	if err != nil {
+		return 0, err
+	}
+
+	days := end.Sub(start).Hours() / 24
+	return historySpend / ForecastHistoryDays * days, nil
+}
+
+// getReport returns the aggregated report for the billing period starting
+// at the given date. The report is only read again once a new report has
+// been delivered, which is checked for at most every curManifestTTL.
+func (budgetSvc *CURBudgetService) getReport(billingPeriod time.Time) (*curReport, error) {
+	manifestKey := path.Join(
+		budgetSvc.Prefix,
+		budgetSvc.ReportName,
+		fmt.Sprintf("%s-%s", billingPeriod.Format("20060102"), billingPeriod.AddDate(0, 1, 0).Format("20060102")),
+		budgetSvc.ReportName+"-Manifest.json",
+	)
+	report, ok := budgetSvc.reports[manifestKey]
+	if ok && time.Since(report.checkedOn) < curManifestTTL {
+		return report, nil
+	}
+
+	manifest := curManifest{}
+	manifestBody, err := budgetSvc.getObject(manifestKey)
+	if err != nil {
+		// Reports for the billing period have not been delivered yet
+		if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != s3.ErrCodeNoSuchKey {
+			return nil, err
+		}
+	} else {
+		defer manifestBody.Close()
+		err = json.NewDecoder(manifestBody).Decode(&manifest)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse CUR manifest %s: %s", manifestKey, err)
+		}
+	}
+
+	// Each delivery of the report has a new assembly ID
+	if ok && report.assemblyID == manifest.AssemblyID {
+		report.checkedOn = time.Now()
+		return report, nil
+	}
+
+	report = &curReport{
+		assemblyID: manifest.AssemblyID,
+		checkedOn:  time.Now(),
+		dailyCosts: curDailyCosts{},
+	}
+	for _, reportKey := range manifest.ReportKeys {
+		err := budgetSvc.readReport(reportKey, report.dailyCosts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if budgetSvc.reports == nil {
+		budgetSvc.reports = map[string]*curReport{}
+	}
+	budgetSvc.reports[manifestKey] = report
+
+	return report, nil
+}
+
+// readReport adds the spend of every linked account in a CUR csv file
+// to the daily costs
+func (budgetSvc *CURBudgetService) readReport(reportKey string, dailyCosts curDailyCosts) error {
+	reportBody, err := budgetSvc.getObject(reportKey)
+	if err != nil {
+		return err
+	}
+	defer reportBody.Close()
+
+	var reader io.Reader = reportBody
+	if strings.HasSuffix(reportKey, ".gz") {
+		gzipReader, err := gzip.NewReader(reportBody)
+		if err != nil {
+			return fmt.Errorf("Failed to decompress CUR report %s: %s", reportKey, err)
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+
+	csvReader := csv.NewReader(reader)
+	header, err := csvReader.Read()
+	if err != nil {
+		return fmt.Errorf("Failed to read CUR report %s: %s", reportKey, err)
+	}
+	columns := map[string]int{}
+	for i, column := range header {
+		columns[column] = i
+	}
+	for _, column := range []string{curUsageAccountIDColumn, curUsageStartDateColumn, curUnblendedCostColumn, curProductNameColumn} {
+		if _, ok := columns[column]; !ok {
+			return fmt.Errorf("CUR report %s is missing the %s column", reportKey, column)
+		}
+	}
+
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("Failed to read CUR report %s: %s", reportKey, err)
+		}
+
+		usageStartDate, err := time.Parse(time.RFC3339, record[columns[curUsageStartDateColumn]])
+		if err != nil {
+			return fmt.Errorf("Failed to parse usage start date in CUR report %s: %s", reportKey, err)
+		}
+		cost, err := strconv.ParseFloat(record[columns[curUnblendedCostColumn]], 64)
+		if err != nil {
+			return fmt.Errorf("Failed to parse unblended cost in CUR report %s: %s", reportKey, err)
+		}
+		// Some line items (eg. taxes) don't have a product name
+		serviceName := record[columns[curProductNameColumn]]
+		if i, ok := columns[curProductCodeColumn]; ok && serviceName == "" {
+			serviceName = record[i]
+		}
+
+		accountID := record[columns[curUsageAccountIDColumn]]
+		day := truncateToDay(usageStartDate).Unix()
+		if dailyCosts[accountID] == nil {
+			dailyCosts[accountID] = map[int64]map[string]float64{}
+		}
+		if dailyCosts[accountID][day] == nil {
+			dailyCosts[accountID][day] = map[string]float64{}
+		}
+		dailyCosts[accountID][day][serviceName] = dailyCosts[accountID][day][serviceName] + cost
+	}
+
+	return nil
+}
+
+func (budgetSvc *CURBudgetService) getObject(key string) (io.ReadCloser, error) {
+	output, err := budgetSvc.S3.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(budgetSvc.Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output.Body, nil
+}
+
+func truncateToDay(date time.Time) time.Time {
+	date = date.UTC()
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,181 @@
+package budget
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Optum/dce/pkg/awsiface/mocks"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newCURTestService returns a CURBudgetService, which reads
+// the October 2019 CUR report from local fixtures
+func newCURTestService(t *testing.T, accountID string) (*CURBudgetService, *mocks.S3API) {
+	reportDir := "cur/dce-cur/20191001-20191101/"
+	fixtures := map[string]string{
+		reportDir + "dce-cur-Manifest.json":                                 "dce-cur-Manifest.json",
+		reportDir + "4b2d6e4a-2c3f-4c1a-9f1e-0d5b3c1f0a7e/dce-cur-1.csv.gz": "dce-cur-1.csv.gz",
+		reportDir + "4b2d6e4a-2c3f-4c1a-9f1e-0d5b3c1f0a7e/dce-cur-2.csv":    "dce-cur-2.csv",
+	}
+
+	s3Svc := &mocks.S3API{}
+	s3Svc.On("GetObject", mock.MatchedBy(func(input *s3.GetObjectInput) bool {
+		return *input.Bucket == "dce-cur-bucket"
+	})).Return(
+		func(input *s3.GetObjectInput) *s3.GetObjectOutput {
+			fixture, ok := fixtures[*input.Key]
+			if !ok {
+				return nil
+			}
+			file, err := os.Open(filepath.Join("testdata", "cur", fixture))
+			require.Nil(t, err)
+			return &s3.GetObjectOutput{Body: file}
+		},
+		func(input *s3.GetObjectInput) error {
+			if _, ok := fixtures[*input.Key]; !ok {
+				return awserr.New(s3.ErrCodeNoSuchKey, "The specified key does not exist.", nil)
+			}
+			return nil
+		},
+	)
+
+	return &CURBudgetService{
+		S3:         s3Svc,
+		Bucket:     "dce-cur-bucket",
+		Prefix:     "cur",
+		ReportName: "dce-cur",
+		AccountID:  accountID,
+	}, s3Svc
+}
+
+func TestCURCalculateSpendByService(t *testing.T) {
+	budgetSvc, _ := newCURTestService(t, "123456789012")
+
+	serviceCosts, err := budgetSvc.CalculateSpendByService(
+		time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2019, 10, 2, 0, 0, 0, 0, time.UTC),
+	)
+	require.Nil(t, err)
+	assert.Equal(t, map[string]float64{
+		"Amazon Elastic Compute Cloud":  4,
+		"Amazon Simple Storage Service": 0.25,
+		"AWSSupportBusiness":            1,
+	}, serviceCosts)
+}
+
+func TestCURCalculateTotalSpend(t *testing.T) {
+
+	t.Run("should sum the spend of the linked account", func(t *testing.T) {
+		budgetSvc, s3Svc := newCURTestService(t, "123456789012")
+
+		cost, err := budgetSvc.CalculateTotalSpend(
+			time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2019, 10, 3, 0, 0, 0, 0, time.UTC),
+		)
+		require.Nil(t, err)
+		assert.Equal(t, float64(9), cost)
+
+		// Should read the reports from cache
+		cost, err = budgetSvc.CalculateTotalSpend(
+			time.Date(2019, 10, 2, 0, 0, 0, 0, time.UTC),
+			time.Date(2019, 10, 3, 0, 0, 0, 0, time.UTC),
+		)
+		require.Nil(t, err)
+		assert.Equal(t, float64(3.75), cost)
+		s3Svc.AssertNumberOfCalls(t, "GetObject", 3)
+	})
+
+	t.Run("should attribute spend by linked account", func(t *testing.T) {
+		budgetSvc, _ := newCURTestService(t, "123456789012")
+		budgetSvc.SetAccountID("210987654321")
+
+		cost, err := budgetSvc.CalculateTotalSpend(
+			time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2019, 10, 3, 0, 0, 0, 0, time.UTC),
+		)
+		require.Nil(t, err)
+		assert.Equal(t, float64(150), cost)
+	})
+
+	t.Run("should reuse the reports for other linked accounts", func(t *testing.T) {
+		budgetSvc, s3Svc := newCURTestService(t, "123456789012")
+
+		cost, err := budgetSvc.CalculateTotalSpend(
+			time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2019, 10, 3, 0, 0, 0, 0, time.UTC),
+		)
+		require.Nil(t, err)
+		assert.Equal(t, float64(9), cost)
+
+		budgetSvc.SetAccountID("210987654321")
+		cost, err = budgetSvc.CalculateTotalSpend(
+			time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2019, 10, 3, 0, 0, 0, 0, time.UTC),
+		)
+		require.Nil(t, err)
+		assert.Equal(t, float64(150), cost)
+		s3Svc.AssertNumberOfCalls(t, "GetObject", 3)
+	})
+
+	t.Run("should only read the reports again when a new report is delivered", func(t *testing.T) {
+		budgetSvc, s3Svc := newCURTestService(t, "123456789012")
+		start := time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC)
+		end := time.Date(2019, 10, 3, 0, 0, 0, 0, time.UTC)
+		manifestKey := "cur/dce-cur/20191001-20191101/dce-cur-Manifest.json"
+
+		_, err := budgetSvc.CalculateTotalSpend(start, end)
+		require.Nil(t, err)
+		s3Svc.AssertNumberOfCalls(t, "GetObject", 3)
+
+		// The manifest is checked again after the TTL,
+		// but the report hasn't changed
+		budgetSvc.reports[manifestKey].checkedOn = time.Now().Add(-curManifestTTL)
+		cost, err := budgetSvc.CalculateTotalSpend(start, end)
+		require.Nil(t, err)
+		assert.Equal(t, float64(9), cost)
+		s3Svc.AssertNumberOfCalls(t, "GetObject", 4)
+
+		// A new report was delivered
+		budgetSvc.reports[manifestKey].checkedOn = time.Now().Add(-curManifestTTL)
+		budgetSvc.reports[manifestKey].assemblyID = "previous-assembly-id"
+		cost, err = budgetSvc.CalculateTotalSpend(start, end)
+		require.Nil(t, err)
+		assert.Equal(t, float64(9), cost)
+		s3Svc.AssertNumberOfCalls(t, "GetObject", 7)
+	})
+
+	t.Run("should skip billing periods without reports", func(t *testing.T) {
+		budgetSvc, s3Svc := newCURTestService(t, "123456789012")
+
+		cost, err := budgetSvc.CalculateTotalSpend(
+			time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2019, 11, 2, 0, 0, 0, 0, time.UTC),
+		)
+		require.Nil(t, err)
+		assert.Equal(t, float64(9), cost)
+		s3Svc.AssertCalled(t, "GetObject", &s3.GetObjectInput{
+			Bucket: aws.String("dce-cur-bucket"),
+			Key:    aws.String("cur/dce-cur/20191101-20191201/dce-cur-Manifest.json"),
+		})
+	})
+}
+
+func TestCURForecastSpend(t *testing.T) {
+	budgetSvc, _ := newCURTestService(t, "123456789012")
+
+	// $9 was spent in the week before the forecast,
+	// so we should forecast $9 for the next week
+	cost, err := budgetSvc.ForecastSpend(
+		time.Date(2019, 10, 3, 0, 0, 0, 0, time.UTC),
+		time.Date(2019, 10, 10, 0, 0, 0, 0, time.UTC),
+	)
+	require.Nil(t, err)
+	assert.InDelta(t, float64(9), cost, 0.0001)
+}
@@ -0,0 +1,115 @@
+package budget
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Optum/dce/pkg/common"
+)
+
+// DefaultCurrency is the currency AWS reports spend in,
+// and the currency of budgets without a currency
+const DefaultCurrency = "USD"
+
+// iso4217Currencies are the active ISO 4217 currency codes
+var iso4217Currencies = map[string]bool{
+	"AED": true, "AFN": true, "ALL": true, "AMD": true, "ANG": true, "AOA": true, "ARS": true, "AUD": true,
+	"AWG": true, "AZN": true, "BAM": true, "BBD": true, "BDT": true, "BGN": true, "BHD": true, "BIF": true,
+	"BMD": true, "BND": true, "BOB": true, "BOV": true, "BRL": true, "BSD": true, "BTN": true, "BWP": true,
+	"BYN": true, "BZD": true, "CAD": true, "CDF": true, "CHE": true, "CHF": true, "CHW": true, "CLF": true,
+	"CLP": true, "CNY": true, "COP": true, "COU": true, "CRC": true, "CUC": true, "CUP": true, "CVE": true,
+	"CZK": true, "DJF": true, "DKK": true, "DOP": true, "DZD": true, "EGP": true, "ERN": true, "ETB": true,
+	"EUR": true, "FJD": true, "FKP": true, "GBP": true, "GEL": true, "GHS": true, "GIP": true, "GMD": true,
+	"GNF": true, "GTQ": true, "GYD": true, "HKD": true, "HNL": true, "HTG": true, "HUF": true, "IDR": true,
+	"ILS": true, "INR": true, "IQD": true, "IRR": true, "ISK": true, "JMD": true, "JOD": true, "JPY": true,
+	"KES": true, "KGS": true, "KHR": true, "KMF": true, "KPW": true, "KRW": true, "KWD": true, "KYD": true,
+	"KZT": true, "LAK": true, "LBP": true, "LKR": true, "LRD": true, "LSL": true, "LYD": true, "MAD": true,
+	"MDL": true, "MGA": true, "MKD": true, "MMK": true, "MNT": true, "MOP": true, "MRU": true, "MUR": true,
+	"MVR": true, "MWK": true, "MXN": true, "MXV": true, "MYR": true, "MZN": true, "NAD": true, "NGN": true,
+	"NIO": true, "NOK": true, "NPR": true, "NZD": true, "OMR": true, "PAB": true, "PEN": true, "PGK": true,
+	"PHP": true, "PKR": true, "PLN": true, "PYG": true, "QAR": true, "RON": true, "RSD": true, "RUB": true,
+	"RWF": true, "SAR": true, "SBD": true, "SCR": true, "SDG": true, "SEK": true, "SGD": true, "SHP": true,
+	"SLE": true, "SLL": true, "SOS": true, "SRD": true, "SSP": true, "STN": true, "SVC": true, "SYP": true,
+	"SZL": true, "THB": true, "TJS": true, "TMT": true, "TND": true, "TOP": true, "TRY": true, "TTD": true,
+	"TWD": true, "TZS": true, "UAH": true, "UGX": true, "USD": true, "USN": true, "UYI": true, "UYU": true,
+	"UYW": true, "UZS": true, "VED": true, "VES": true, "VND": true, "VUV": true, "WST": true, "XAF": true,
+	"XAG": true, "XAU": true, "XBA": true, "XBB": true, "XBC": true, "XBD": true, "XCD": true, "XDR": true,
+	"XOF": true, "XPD": true, "XPF": true, "XPT": true, "XSU": true, "XTS": true, "XUA": true, "XXX": true,
+	"YER": true, "ZAR": true, "ZMW": true, "ZWL": true,
+}
+
+// IsValidCurrency returns true for ISO 4217 currency codes (eg. "USD", "EUR")
+func IsValidCurrency(currency string) bool {
+	return iso4217Currencies[currency]
+}
+
+// ExchangeRates are the units of each currency worth one USD.
+// USD does not need to be included.
+type ExchangeRates map[string]float64
+
+// Convert converts an amount from one currency to another
+func (rates ExchangeRates) Convert(amount float64, from string, to string) (float64, error) {
+	fromRate, err := rates.rate(from)
+	if err != nil {
+		return 0, err
+	}
+	toRate, err := rates.rate(to)
+	if err != nil {
+		return 0, err
+	}
+	if from == to {
+		return amount, nil
+	}
+
+	return amount / fromRate * toRate, nil
+}
+
+func (rates ExchangeRates) rate(currency string) (float64, error) {
+	if !IsValidCurrency(currency) {
+		return 0, fmt.Errorf("%s is not a valid ISO 4217 currency code", currency)
+	}
+	if currency == DefaultCurrency {
+		return 1, nil
+	}
+	rate, ok := rates[currency]
+	if !ok {
+		return 0, fmt.Errorf("No exchange rate is configured for currency %s", currency)
+	}
+	return rate, nil
+}
+
+// ParseExchangeRates parses a JSON object of exchange rates, by currency code
+// (eg. `{"EUR": 0.92, "GBP": 0.79}`)
+func ParseExchangeRates(ratesJSON string) (ExchangeRates, error) {
+	rates := ExchangeRates{}
+	err := json.Unmarshal([]byte(ratesJSON), &rates)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse exchange rates: %s", err)
+	}
+
+	for currency, rate := range rates {
+		if !IsValidCurrency(currency) {
+			return nil, fmt.Errorf("Invalid exchange rate: %s is not a valid ISO 4217 currency code", currency)
+		}
+		if rate <= 0 {
+			return nil, fmt.Errorf("Invalid exchange rate for %s: must be greater than 0", currency)
+		}
+	}
+
+	return rates, nil
+}
+
+/*
+NewExchangeRatesFromEnv creates ExchangeRates configured from environment variables.
+Reads env vars for:
+
+- CURRENCY_EXCHANGE_RATES (optional, a JSON object of exchange rates by currency code)
+*/
+func NewExchangeRatesFromEnv() (ExchangeRates, error) {
+	ratesJSON := strings.TrimSpace(common.GetEnv("CURRENCY_EXCHANGE_RATES", ""))
+	if ratesJSON == "" {
+		return ExchangeRates{}, nil
+	}
+	return ParseExchangeRates(ratesJSON)
+}
@@ -0,0 +1,63 @@
+package budget
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsValidCurrency(t *testing.T) {
+	assert.True(t, IsValidCurrency("USD"))
+	assert.True(t, IsValidCurrency("EUR"))
+	assert.False(t, IsValidCurrency("usd"))
+	assert.False(t, IsValidCurrency("XYZ"))
+	assert.False(t, IsValidCurrency(""))
+}
+
+func TestExchangeRatesConvert(t *testing.T) {
+	rates := ExchangeRates{"EUR": 0.5, "GBP": 0.25}
+
+	tests := []struct {
+		name   string
+		amount float64
+		from   string
+		to     string
+		exp    float64
+		expErr string
+	}{
+		{"USD to EUR", 100, "USD", "EUR", 50, ""},
+		{"EUR to USD", 50, "EUR", "USD", 100, ""},
+		{"EUR to GBP", 50, "EUR", "GBP", 25, ""},
+		{"Same currency", 100, "EUR", "EUR", 100, ""},
+		{"Missing exchange rate", 100, "USD", "JPY", 0, "No exchange rate is configured for currency JPY"},
+		{"Invalid currency", 100, "XYZ", "XYZ", 0, "XYZ is not a valid ISO 4217 currency code"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rates.Convert(tt.amount, tt.from, tt.to)
+			if tt.expErr != "" {
+				require.NotNil(t, err)
+				assert.Equal(t, tt.expErr, err.Error())
+				return
+			}
+			require.Nil(t, err)
+			assert.Equal(t, tt.exp, got)
+		})
+	}
+}
+
+func TestParseExchangeRates(t *testing.T) {
+	rates, err := ParseExchangeRates(`{"EUR": 0.92, "GBP": 0.79}`)
+	require.Nil(t, err)
+	assert.Equal(t, ExchangeRates{"EUR": 0.92, "GBP": 0.79}, rates)
+
+	_, err = ParseExchangeRates(`{"EURO": 0.92}`)
+	assert.EqualError(t, err, "Invalid exchange rate: EURO is not a valid ISO 4217 currency code")
+
+	_, err = ParseExchangeRates(`{"EUR": 0}`)
+	assert.EqualError(t, err, "Invalid exchange rate for EUR: must be greater than 0")
+
+	_, err = ParseExchangeRates(`EUR=0.92`)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,23 @@
+//
+
+package budgetpolicyiface
+
+import (
+	"github.com/Optum/dce/pkg/budgetpolicy"
+)
+
+// Servicer makes working with the Budget Policy Service struct easier
+type Servicer interface {
+	// Get returns a budget policy from ID
+	Get(ID string) (*budgetpolicy.BudgetPolicy, error)
+	// Create creates a new budget policy for a principal or group
+	Create(data *budgetpolicy.BudgetPolicy) (*budgetpolicy.BudgetPolicy, error)
+	// Update changes the limits of a budget policy
+	Update(ID string, data *budgetpolicy.BudgetPolicy) (*budgetpolicy.BudgetPolicy, error)
+	// Delete removes a budget policy
+	Delete(ID string) (*budgetpolicy.BudgetPolicy, error)
+	// List Get a list of budget policies based on a query
+	List(query *budgetpolicy.BudgetPolicy) (*budgetpolicy.BudgetPolicies, error)
+	// Match returns the budget policy which applies to the principal, or nil
+	Match(principalID string) (*budgetpolicy.BudgetPolicy, error)
+}
@@ -0,0 +1,103 @@
+package budgetpolicy
+
+import (
+	"github.com/Optum/dce/pkg/errors"
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+const (
+	// PeriodWeekly measures principal budgets from the beginning of the week
+	PeriodWeekly = "WEEKLY"
+	// PeriodMonthly measures principal budgets from the beginning of the month
+	PeriodMonthly = "MONTHLY"
+)
+
+// BudgetPolicy overrides the default lease and principal budget limits,
+// for either a single principal or a Cognito group.
+// Limits which are not set fall back to the defaults.
+type BudgetPolicy struct {
+	ID                    *string  `json:"id,omitempty" dynamodbav:"Id" schema:"id,omitempty"`                                      // Budget Policy ID
+	PrincipalID           *string  `json:"principalId,omitempty" dynamodbav:"PrincipalId,omitempty" schema:"principalId,omitempty"` // Principal the policy applies to
+	Group                 *string  `json:"group,omitempty" dynamodbav:"Group,omitempty" schema:"group,omitempty"`                   // Cognito group the policy applies to
+	MaxLeaseBudgetAmount  *float64 `json:"maxLeaseBudgetAmount,omitempty" dynamodbav:"MaxLeaseBudgetAmount,omitempty" schema:"-"`   // Max budget amount of a single lease
+	MaxLeasePeriod        *int64   `json:"maxLeasePeriod,omitempty" dynamodbav:"MaxLeasePeriod,omitempty" schema:"-"`               // Max lease period, in seconds
+	PrincipalBudgetAmount *float64 `json:"principalBudgetAmount,omitempty" dynamodbav:"PrincipalBudgetAmount,omitempty" schema:"-"` // Max spend of the principal, per budget period
+	PrincipalBudgetPeriod *string  `json:"principalBudgetPeriod,omitempty" dynamodbav:"PrincipalBudgetPeriod,omitempty" schema:"-"` // WEEKLY or MONTHLY
+	Priority              *int64   `json:"priority,omitempty" dynamodbav:"Priority,omitempty" schema:"-"`                           // Precedence of a group policy, lowest first
+	TargetKey             *string  `json:"-" dynamodbav:"Target" schema:"-"`                                                        // Principal or group the policy applies to, eg. "group:Interns"
+	CreatedOn             *int64   `json:"createdOn,omitempty" dynamodbav:"CreatedOn,omitempty" schema:"createdOn,omitempty"`       // Budget Policy CreatedOn
+	LastModifiedOn        *int64   `json:"lastModifiedOn,omitempty" dynamodbav:"LastModifiedOn" schema:"lastModifiedOn,omitempty"`  // Last Modified Epoch Timestamp
+	Limit                 *int64   `json:"-" dynamodbav:"-" schema:"limit,omitempty"`
+	NextTarget            *string  `json:"-" dynamodbav:"-" schema:"nextTarget,omitempty"`
+}
+
+// Validate the budget policy data
+func (p *BudgetPolicy) Validate() error {
+	err := validation.ValidateStruct(p,
+		validation.Field(&p.ID, validateID...),
+		validation.Field(&p.PrincipalID, validation.NilOrNotEmpty, validation.By(isRequiredWithout(p.Group))),
+		validation.Field(&p.Group, validation.NilOrNotEmpty, validation.By(isNilWith(p.PrincipalID))),
+		validation.Field(&p.MaxLeaseBudgetAmount, validateAmount...),
+		validation.Field(&p.MaxLeasePeriod, validatePeriod...),
+		validation.Field(&p.PrincipalBudgetAmount, validateAmount...),
+		validation.Field(&p.PrincipalBudgetPeriod, validateBudgetPeriod...),
+		validation.Field(&p.Priority, validation.Min(int64(0)).Error("must not be negative"), validation.By(isNilWith(p.PrincipalID))),
+		validation.Field(&p.CreatedOn, validateInt64...),
+		validation.Field(&p.LastModifiedOn, validateInt64...),
+	)
+	if err != nil {
+		return errors.NewValidation("budget policy", err)
+	}
+	return nil
+}
+
+// Target returns the principal or group the policy applies to
+func (p *BudgetPolicy) Target() string {
+	if p.PrincipalID != nil {
+		return *p.PrincipalID
+	}
+	if p.Group != nil {
+		return *p.Group
+	}
+	return ""
+}
+
+// PrincipalTarget returns the target key of the policy for a principal
+func PrincipalTarget(principalID string) string {
+	return "principal:" + principalID
+}
+
+// GroupTarget returns the target key of the policy for a group
+func GroupTarget(group string) string {
+	return "group:" + group
+}
+
+// targetKey returns the key of the principal or group the policy applies to.
+// Principals and groups are keyed separately, as they may share a name.
+func (p *BudgetPolicy) targetKey() *string {
+	var key string
+	if p.PrincipalID != nil {
+		key = PrincipalTarget(*p.PrincipalID)
+	} else if p.Group != nil {
+		key = GroupTarget(*p.Group)
+	} else {
+		return nil
+	}
+	return &key
+}
+
+// precedes returns true if the group policy takes precedence over the other.
+// Policies with the lowest priority come first, then policies without a priority.
+// Ties are broken by group name, so the same policy is always matched.
+func (p *BudgetPolicy) precedes(other *BudgetPolicy) bool {
+	if p.Priority != nil && other.Priority != nil && *p.Priority != *other.Priority {
+		return *p.Priority < *other.Priority
+	}
+	if (p.Priority == nil) != (other.Priority == nil) {
+		return p.Priority != nil
+	}
+	return p.Target() < other.Target()
+}
+
+// BudgetPolicies is a list of type BudgetPolicy
+type BudgetPolicies []BudgetPolicy
@@ -0,0 +1,277 @@
+package budgetpolicy
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Optum/dce/pkg/errors"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/google/uuid"
+	"github.com/imdario/mergo"
+)
+
+// Writer put an item into the data store
+type Writer interface {
+	Write(i *BudgetPolicy, lastModifiedOn *int64) error
+}
+
+// Deleter Deletes a Budget Policy from the data store
+type Deleter interface {
+	Delete(i *BudgetPolicy) error
+}
+
+// SingleReader Reads Budget Policy information from the data store
+type SingleReader interface {
+	Get(ID string) (*BudgetPolicy, error)
+	GetByTarget(target string) (*BudgetPolicy, error)
+}
+
+// MultipleReader reads multiple budget policies from the data store
+type MultipleReader interface {
+	List(query *BudgetPolicy) (*BudgetPolicies, error)
+}
+
+// Reader data Layer
+type Reader interface {
+	SingleReader
+	MultipleReader
+}
+
+// WriterDeleter data layer
+type WriterDeleter interface {
+	Writer
+	Deleter
+}
+
+// ReaderWriterDeleter includes Reader and Writer interfaces
+type ReaderWriterDeleter interface {
+	Reader
+	WriterDeleter
+}
+
+// GroupLister lists the groups a principal belongs to
+type GroupLister interface {
+	ListGroups(principalID string) ([]string, error)
+}
+
+// Service is a type corresponding to a Budget Policy table record
+type Service struct {
+	dataSvc   ReaderWriterDeleter
+	groupSvc  GroupLister
+	groupsTTL time.Duration
+	groupsMu  sync.Mutex
+	groups    map[string]cachedGroups
+}
+
+// cachedGroups are the groups of a principal, until they expire
+type cachedGroups struct {
+	groups    []string
+	expiresOn time.Time
+}
+
+// Get returns a budget policy from ID
+func (a *Service) Get(ID string) (*BudgetPolicy, error) {
+
+	new, err := a.dataSvc.Get(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return new, err
+}
+
+// Save writes the record to the dataSvc
+func (a *Service) Save(data *BudgetPolicy) error {
+	var lastModifiedOn *int64
+	now := time.Now().Unix()
+	data.TargetKey = data.targetKey()
+	if data.LastModifiedOn == nil {
+		lastModifiedOn = nil
+		data.CreatedOn = &now
+		data.LastModifiedOn = &now
+	} else {
+		lastModifiedOn = data.LastModifiedOn
+		data.LastModifiedOn = &now
+	}
+
+	err := data.Validate()
+	if err != nil {
+		return err
+	}
+	err = a.dataSvc.Write(data, lastModifiedOn)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Create creates a new budget policy for a principal or group.
+// Each principal and group may only have a single policy,
+// which the data store enforces when the policy is written.
+func (a *Service) Create(data *BudgetPolicy) (*BudgetPolicy, error) {
+	// Validate the incoming record doesn't have unneeded fields
+	err := validation.ValidateStruct(data,
+		validation.Field(&data.ID, validation.By(isNil)),
+		validation.Field(&data.CreatedOn, validation.By(isNil)),
+		validation.Field(&data.LastModifiedOn, validation.By(isNil)),
+	)
+	if err != nil {
+		return nil, errors.NewValidation("budget policy", err)
+	}
+
+	id := uuid.New().String()
+	data.ID = &id
+	err = a.Save(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Update changes the limits of a budget policy.
+// The principal or group the policy applies to may not be changed.
+func (a *Service) Update(ID string, data *BudgetPolicy) (*BudgetPolicy, error) {
+	err := validation.ValidateStruct(data,
+		validation.Field(&data.ID, validation.NilOrNotEmpty, validation.In(ID)),
+		validation.Field(&data.PrincipalID, validation.By(isNil)),
+		validation.Field(&data.Group, validation.By(isNil)),
+		validation.Field(&data.CreatedOn, validation.By(isNil)),
+		validation.Field(&data.LastModifiedOn, validation.By(isNil)),
+	)
+	if err != nil {
+		return nil, errors.NewValidation("budget policy", err)
+	}
+
+	policy, err := a.dataSvc.Get(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = mergo.Merge(policy, *data, mergo.WithOverride)
+	if err != nil {
+		return nil, errors.NewInternalServer("unexpected error updating budget policy", err)
+	}
+
+	err = a.Save(policy)
+	if err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// Delete removes a budget policy, so the default limits apply again
+func (a *Service) Delete(ID string) (*BudgetPolicy, error) {
+	policy, err := a.dataSvc.Get(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.dataSvc.Delete(policy)
+	if err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// List Get a list of budget policies based on a query
+func (a *Service) List(query *BudgetPolicy) (*BudgetPolicies, error) {
+	// A principal or group has at most one policy, found by its key
+	query.TargetKey = query.targetKey()
+
+	policies, err := a.dataSvc.List(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return policies, nil
+}
+
+// Match returns the budget policy which applies to the principal,
+// or nil if no policy applies.
+// A policy for the principal takes priority over policies for their groups.
+// When several of their groups have a policy, the policy with the lowest priority applies.
+func (a *Service) Match(principalID string) (*BudgetPolicy, error) {
+	policy, err := a.getByTarget(PrincipalTarget(principalID))
+	if err != nil || policy != nil {
+		return policy, err
+	}
+
+	groups, err := a.listGroups(principalID)
+	if err != nil {
+		return nil, errors.NewInternalServer(
+			fmt.Sprintf("failed to list groups for principal %q", principalID),
+			err,
+		)
+	}
+	for _, group := range groups {
+		groupPolicy, err := a.getByTarget(GroupTarget(group))
+		if err != nil {
+			return nil, err
+		}
+		if groupPolicy != nil && (policy == nil || groupPolicy.precedes(policy)) {
+			policy = groupPolicy
+		}
+	}
+
+	return policy, nil
+}
+
+// listGroups returns the groups of the principal,
+// which are cached for the groups TTL, if there is one
+func (a *Service) listGroups(principalID string) ([]string, error) {
+	if a.groupsTTL <= 0 {
+		return a.groupSvc.ListGroups(principalID)
+	}
+
+	a.groupsMu.Lock()
+	defer a.groupsMu.Unlock()
+
+	now := time.Now()
+	if cached, ok := a.groups[principalID]; ok && now.Before(cached.expiresOn) {
+		return cached.groups, nil
+	}
+	groups, err := a.groupSvc.ListGroups(principalID)
+	if err != nil {
+		return nil, err
+	}
+	a.groups[principalID] = cachedGroups{
+		groups:    groups,
+		expiresOn: now.Add(a.groupsTTL),
+	}
+	return groups, nil
+}
+
+// getByTarget returns the policy for a principal or group, or nil if there isn't one
+func (a *Service) getByTarget(target string) (*BudgetPolicy, error) {
+	policy, err := a.dataSvc.GetByTarget(target)
+	if errors.Is(err, errors.NewNotFound("budget policy", target)) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// NewServiceInput Input for creating a new Service
+type NewServiceInput struct {
+	DataSvc  ReaderWriterDeleter
+	GroupSvc GroupLister
+	// GroupsTTL is how long the groups of a principal are cached for,
+	// when matching policies. Groups aren't cached when it is zero.
+	GroupsTTL time.Duration `env:"BUDGET_POLICY_GROUPS_TTL" envDefault:"0s"`
+}
+
+// NewService creates a new instance of the Service
+func NewService(input NewServiceInput) *Service {
+	return &Service{
+		dataSvc:   input.DataSvc,
+		groupSvc:  input.GroupSvc,
+		groupsTTL: input.GroupsTTL,
+		groups:    map[string]cachedGroups{},
+	}
+}
@@ -0,0 +1,386 @@
+package budgetpolicy_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Optum/dce/pkg/budgetpolicy"
+	"github.com/Optum/dce/pkg/budgetpolicy/mocks"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func ptrString(s string) *string {
+	ptrS := s
+	return &ptrS
+}
+
+func TestCreate(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       *budgetpolicy.BudgetPolicy
+		writeErr  error
+		expTarget string
+		expErr    error
+	}{
+		{
+			name: "should create a policy for a principal",
+			req: &budgetpolicy.BudgetPolicy{
+				PrincipalID:           ptrString("jdoe"),
+				MaxLeaseBudgetAmount:  aws.Float64(5000),
+				PrincipalBudgetPeriod: ptrString(budgetpolicy.PeriodMonthly),
+			},
+			expTarget: "principal:jdoe",
+		},
+		{
+			name: "should create a policy for a group",
+			req: &budgetpolicy.BudgetPolicy{
+				Group:          ptrString("SRE"),
+				MaxLeasePeriod: aws.Int64(2592000),
+				Priority:       aws.Int64(10),
+			},
+			expTarget: "group:SRE",
+		},
+		{
+			name: "should fail when the group already has a policy",
+			req: &budgetpolicy.BudgetPolicy{
+				Group: ptrString("Interns"),
+			},
+			writeErr: errors.NewAlreadyExists("budget policy", "Interns"),
+			expErr:   errors.NewAlreadyExists("budget policy", "Interns"),
+		},
+		{
+			name: "should fail with a priority for a principal",
+			req: &budgetpolicy.BudgetPolicy{
+				PrincipalID: ptrString("jdoe"),
+				Priority:    aws.Int64(10),
+			},
+			expErr: errors.NewValidation("budget policy", fmt.Errorf("priority: must be empty when principalId is set.")), //nolint golint
+		},
+		{
+			name: "should fail without a principal or group",
+			req: &budgetpolicy.BudgetPolicy{
+				MaxLeaseBudgetAmount: aws.Float64(5000),
+			},
+			expErr: errors.NewValidation("budget policy", fmt.Errorf("principalId: must be set when group is empty.")), //nolint golint
+		},
+		{
+			name: "should fail with both a principal and group",
+			req: &budgetpolicy.BudgetPolicy{
+				PrincipalID: ptrString("jdoe"),
+				Group:       ptrString("SRE"),
+			},
+			expErr: errors.NewValidation("budget policy", fmt.Errorf("group: must be empty when principalId is set.")), //nolint golint
+		},
+		{
+			name: "should fail with an invalid budget period",
+			req: &budgetpolicy.BudgetPolicy{
+				PrincipalID:           ptrString("jdoe"),
+				PrincipalBudgetPeriod: ptrString("DAILY"),
+			},
+			expErr: errors.NewValidation("budget policy", fmt.Errorf("principalBudgetPeriod: must be WEEKLY or MONTHLY.")), //nolint golint
+		},
+		{
+			name: "should fail when an ID is provided",
+			req: &budgetpolicy.BudgetPolicy{
+				ID:          ptrString("f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0"),
+				PrincipalID: ptrString("jdoe"),
+			},
+			expErr: errors.NewValidation("budget policy", fmt.Errorf("id: must be empty.")), //nolint golint
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocksRwd := &mocks.ReaderWriterDeleter{}
+			mocksRwd.On("Write", mock.AnythingOfType("*budgetpolicy.BudgetPolicy"), mock.Anything).Return(tt.writeErr)
+
+			policySvc := budgetpolicy.NewService(budgetpolicy.NewServiceInput{
+				DataSvc: mocksRwd,
+			})
+
+			policy, err := policySvc.Create(tt.req)
+			assert.Truef(t, errors.Is(err, tt.expErr), "actual error %q doesn't match expected error %q", err, tt.expErr)
+			if tt.expErr == nil {
+				assert.NotNil(t, policy.ID)
+				assert.NotNil(t, policy.CreatedOn)
+				assert.Equal(t, tt.expTarget, *policy.TargetKey)
+				mocksRwd.AssertCalled(t, "Write", policy, (*int64)(nil))
+			} else if tt.writeErr == nil {
+				mocksRwd.AssertNotCalled(t, "Write", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       *budgetpolicy.BudgetPolicy
+		getPolicy *budgetpolicy.BudgetPolicy
+		getErr    error
+		expPolicy *budgetpolicy.BudgetPolicy
+		expErr    error
+	}{
+		{
+			name: "should update the limits",
+			req: &budgetpolicy.BudgetPolicy{
+				MaxLeaseBudgetAmount: aws.Float64(100),
+			},
+			getPolicy: &budgetpolicy.BudgetPolicy{
+				ID:                    ptrString("f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0"),
+				Group:                 ptrString("Interns"),
+				MaxLeaseBudgetAmount:  aws.Float64(50),
+				PrincipalBudgetAmount: aws.Float64(200),
+				CreatedOn:             aws.Int64(1573592058),
+				LastModifiedOn:        aws.Int64(1573592058),
+			},
+			expPolicy: &budgetpolicy.BudgetPolicy{
+				ID:                    ptrString("f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0"),
+				Group:                 ptrString("Interns"),
+				MaxLeaseBudgetAmount:  aws.Float64(100),
+				PrincipalBudgetAmount: aws.Float64(200),
+			},
+		},
+		{
+			name: "should not change the group",
+			req: &budgetpolicy.BudgetPolicy{
+				Group: ptrString("SRE"),
+			},
+			expErr: errors.NewValidation("budget policy", fmt.Errorf("group: must be empty.")), //nolint golint
+		},
+		{
+			name: "should fail when the policy doesn't exist",
+			req: &budgetpolicy.BudgetPolicy{
+				MaxLeaseBudgetAmount: aws.Float64(100),
+			},
+			getErr: errors.NewNotFound("budget policy", "f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0"),
+			expErr: errors.NewNotFound("budget policy", "f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocksRwd := &mocks.ReaderWriterDeleter{}
+			mocksRwd.On("Get", "f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0").Return(tt.getPolicy, tt.getErr)
+			mocksRwd.On("Write", mock.AnythingOfType("*budgetpolicy.BudgetPolicy"), aws.Int64(1573592058)).Return(nil)
+
+			policySvc := budgetpolicy.NewService(budgetpolicy.NewServiceInput{
+				DataSvc: mocksRwd,
+			})
+
+			policy, err := policySvc.Update("f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0", tt.req)
+			assert.Truef(t, errors.Is(err, tt.expErr), "actual error %q doesn't match expected error %q", err, tt.expErr)
+			if tt.expPolicy != nil {
+				assert.Equal(t, tt.expPolicy.MaxLeaseBudgetAmount, policy.MaxLeaseBudgetAmount)
+				assert.Equal(t, tt.expPolicy.PrincipalBudgetAmount, policy.PrincipalBudgetAmount)
+				assert.Equal(t, tt.expPolicy.Group, policy.Group)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	policy := &budgetpolicy.BudgetPolicy{
+		ID:          ptrString("f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0"),
+		PrincipalID: ptrString("jdoe"),
+	}
+
+	mocksRwd := &mocks.ReaderWriterDeleter{}
+	mocksRwd.On("Get", "f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0").Return(policy, nil)
+	mocksRwd.On("Delete", policy).Return(nil)
+
+	policySvc := budgetpolicy.NewService(budgetpolicy.NewServiceInput{
+		DataSvc: mocksRwd,
+	})
+
+	deleted, err := policySvc.Delete("f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0")
+	assert.Nil(t, err)
+	assert.Equal(t, policy, deleted)
+	mocksRwd.AssertExpectations(t)
+}
+
+func TestMatch(t *testing.T) {
+	policies := map[string]*budgetpolicy.BudgetPolicy{
+		"group:Interns": {
+			ID:                   ptrString("f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0"),
+			Group:                ptrString("Interns"),
+			MaxLeaseBudgetAmount: aws.Float64(50),
+		},
+		"group:SRE": {
+			ID:                   ptrString("0c5e7a4f-3c7b-4c6d-9d4c-6a2b1a9e5f10"),
+			Group:                ptrString("SRE"),
+			MaxLeaseBudgetAmount: aws.Float64(5000),
+			Priority:             aws.Int64(20),
+		},
+		"group:Admins": {
+			ID:                   ptrString("5b2d7e9a-4c1f-4e3a-9b8d-2f6a1c0e7d3b"),
+			Group:                ptrString("Admins"),
+			MaxLeaseBudgetAmount: aws.Float64(20000),
+			Priority:             aws.Int64(10),
+		},
+		"group:Contractors": {
+			ID:                   ptrString("9a8b7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d"),
+			Group:                ptrString("Contractors"),
+			MaxLeaseBudgetAmount: aws.Float64(25),
+		},
+		"principal:jdoe": {
+			ID:                   ptrString("8d6b4c2a-1f3e-4a5b-8c7d-9e0f1a2b3c4d"),
+			PrincipalID:          ptrString("jdoe"),
+			MaxLeaseBudgetAmount: aws.Float64(10000),
+		},
+	}
+
+	tests := []struct {
+		name        string
+		principalID string
+		groups      []string
+		groupsErr   error
+		getErr      error
+		expPolicyID *string
+		expErr      error
+	}{
+		{
+			name:        "should match the principal before their groups",
+			principalID: "jdoe",
+			groups:      []string{"SRE"},
+			expPolicyID: ptrString("8d6b4c2a-1f3e-4a5b-8c7d-9e0f1a2b3c4d"),
+		},
+		{
+			name:        "should match the group policy with the lowest priority",
+			principalID: "asmith",
+			groups:      []string{"Users", "SRE", "Interns", "Admins"},
+			expPolicyID: ptrString("5b2d7e9a-4c1f-4e3a-9b8d-2f6a1c0e7d3b"),
+		},
+		{
+			name:        "should match group policies without a priority by group name",
+			principalID: "asmith",
+			groups:      []string{"Interns", "Contractors"},
+			expPolicyID: ptrString("9a8b7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d"),
+		},
+		{
+			name:        "should return nil when no policy matches",
+			principalID: "asmith",
+			groups:      []string{"Users"},
+		},
+		{
+			name:        "should fail when groups can't be listed",
+			principalID: "asmith",
+			groupsErr:   fmt.Errorf("failure"),
+			expErr:      errors.NewInternalServer("failed to list groups for principal \"asmith\"", fmt.Errorf("failure")),
+		},
+		{
+			name:        "should fail when policies can't be read",
+			principalID: "asmith",
+			getErr:      errors.NewInternalServer("failure", nil),
+			expErr:      errors.NewInternalServer("failure", nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocksRwd := &mocks.ReaderWriterDeleter{}
+			mocksRwd.On("GetByTarget", mock.AnythingOfType("string")).Return(
+				func(target string) *budgetpolicy.BudgetPolicy {
+					return policies[target]
+				},
+				func(target string) error {
+					if tt.getErr != nil {
+						return tt.getErr
+					}
+					if policies[target] == nil {
+						return errors.NewNotFound("budget policy", target)
+					}
+					return nil
+				},
+			)
+			mocksGroups := &mocks.GroupLister{}
+			mocksGroups.On("ListGroups", tt.principalID).Return(tt.groups, tt.groupsErr)
+
+			policySvc := budgetpolicy.NewService(budgetpolicy.NewServiceInput{
+				DataSvc:  mocksRwd,
+				GroupSvc: mocksGroups,
+			})
+
+			policy, err := policySvc.Match(tt.principalID)
+			assert.Truef(t, errors.Is(err, tt.expErr), "actual error %q doesn't match expected error %q", err, tt.expErr)
+			if tt.expPolicyID == nil {
+				assert.Nil(t, policy)
+			} else {
+				assert.Equal(t, *tt.expPolicyID, *policy.ID)
+			}
+			mocksRwd.AssertNotCalled(t, "List", mock.Anything)
+		})
+	}
+}
+
+func TestMatchCachesGroups(t *testing.T) {
+	mocksRwd := &mocks.ReaderWriterDeleter{}
+	mocksRwd.On("GetByTarget", mock.AnythingOfType("string")).Return(nil,
+		func(target string) error {
+			return errors.NewNotFound("budget policy", target)
+		},
+	)
+	mocksGroups := &mocks.GroupLister{}
+	mocksGroups.On("ListGroups", "user1").Return([]string{"group1"}, nil)
+	mocksGroups.On("ListGroups", "user2").Return([]string{"group2"}, nil)
+
+	policySvc := budgetpolicy.NewService(budgetpolicy.NewServiceInput{
+		DataSvc:   mocksRwd,
+		GroupSvc:  mocksGroups,
+		GroupsTTL: time.Minute,
+	})
+
+	for _, principalID := range []string{"user1", "user1", "user2"} {
+		_, err := policySvc.Match(principalID)
+		assert.Nil(t, err)
+	}
+	mocksGroups.AssertNumberOfCalls(t, "ListGroups", 2)
+	mocksRwd.AssertCalled(t, "GetByTarget", budgetpolicy.GroupTarget("group1"))
+}
+
+func TestList(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     *budgetpolicy.BudgetPolicy
+		expTarget *string
+	}{
+		{
+			name:  "should list every policy",
+			query: &budgetpolicy.BudgetPolicy{},
+		},
+		{
+			name: "should list the policy of a principal by its target",
+			query: &budgetpolicy.BudgetPolicy{
+				PrincipalID: ptrString("jdoe"),
+			},
+			expTarget: ptrString("principal:jdoe"),
+		},
+		{
+			name: "should list the policy of a group by its target",
+			query: &budgetpolicy.BudgetPolicy{
+				Group: ptrString("Interns"),
+			},
+			expTarget: ptrString("group:Interns"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocksRwd := &mocks.ReaderWriterDeleter{}
+			mocksRwd.On("List", tt.query).Return(&budgetpolicy.BudgetPolicies{}, nil)
+
+			policySvc := budgetpolicy.NewService(budgetpolicy.NewServiceInput{
+				DataSvc: mocksRwd,
+			})
+
+			_, err := policySvc.List(tt.query)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expTarget, tt.query.TargetKey)
+			mocksRwd.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package budgetpolicy
+
+import (
+	"errors"
+	"reflect"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+)
+
+// We don't use the internal errors package here because validation will rewrite it anyways
+// Just spit out errors and turn them into validation errors inside the appropriate functions
+
+var validateID = []validation.Rule{
+	validation.NotNil.Error("must be a string"),
+	is.UUIDv4.Error("must be a UUIDv4"),
+}
+
+var validateInt64 = []validation.Rule{
+	validation.NotNil.Error("must be an epoch timestamp"),
+}
+
+var validateAmount = []validation.Rule{
+	validation.Min(float64(0)).Error("must not be negative"),
+}
+
+var validatePeriod = []validation.Rule{
+	validation.Min(int64(0)).Error("must not be negative"),
+}
+
+var validateBudgetPeriod = []validation.Rule{
+	validation.NilOrNotEmpty.Error("must be WEEKLY or MONTHLY"),
+	validation.In(PeriodWeekly, PeriodMonthly).Error("must be WEEKLY or MONTHLY"),
+}
+
+func isNil(value interface{}) error {
+	if !reflect.ValueOf(value).IsNil() {
+		return errors.New("must be empty")
+	}
+	return nil
+}
+
+// isRequiredWithout requires a value, when the other value is nil
+func isRequiredWithout(other *string) validation.RuleFunc {
+	return func(value interface{}) error {
+		v, _ := value.(*string)
+		if v == nil && other == nil {
+			return errors.New("must be set when group is empty")
+		}
+		return nil
+	}
+}
+
+// isNilWith requires the value to be nil, when the other value is set
+func isNilWith(other *string) validation.RuleFunc {
+	return func(value interface{}) error {
+		if !reflect.ValueOf(value).IsNil() && other != nil {
+			return errors.New("must be empty when principalId is set")
+		}
+		return nil
+	}
+}
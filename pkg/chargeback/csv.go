@@ -0,0 +1,35 @@
+package chargeback
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+// WriteCSV writes the report as CSV, with a header row
+func (r *Report) WriteCSV(w io.Writer) error {
+	csvWriter := csv.NewWriter(w)
+
+	err := csvWriter.Write(Columns)
+	if err != nil {
+		return err
+	}
+	for _, row := range r.Rows {
+		err = csvWriter.Write([]string{
+			row.LeaseID,
+			row.PrincipalID,
+			row.AccountID,
+			row.CostCenter,
+			row.StartDate,
+			row.EndDate,
+			strconv.FormatFloat(row.CostAmount, 'f', 2, 64),
+			row.CostCurrency,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
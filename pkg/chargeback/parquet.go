@@ -0,0 +1,49 @@
+package chargeback
+
+import (
+	"io"
+
+	"github.com/xitongsys/parquet-go-source/writerfile"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// parquetRow is a row of the report, with the Parquet schema of its columns.
+// Column names match the CSV report.
+type parquetRow struct {
+	LeaseID      string  `parquet:"name=leaseId, type=UTF8"`
+	PrincipalID  string  `parquet:"name=principalId, type=UTF8"`
+	AccountID    string  `parquet:"name=accountId, type=UTF8"`
+	CostCenter   string  `parquet:"name=costCenter, type=UTF8"`
+	StartDate    string  `parquet:"name=startDate, type=UTF8"`
+	EndDate      string  `parquet:"name=endDate, type=UTF8"`
+	CostAmount   float64 `parquet:"name=costAmount, type=DOUBLE"`
+	CostCurrency string  `parquet:"name=costCurrency, type=UTF8"`
+}
+
+// WriteParquet writes the report as a Snappy compressed Parquet file
+func (r *Report) WriteParquet(w io.Writer) error {
+	pw, err := writer.NewParquetWriter(writerfile.NewWriterFile(w), new(parquetRow), 1)
+	if err != nil {
+		return err
+	}
+	pw.CompressionType = parquet.CompressionCodec_SNAPPY
+
+	for _, row := range r.Rows {
+		err = pw.Write(parquetRow{
+			LeaseID:      row.LeaseID,
+			PrincipalID:  row.PrincipalID,
+			AccountID:    row.AccountID,
+			CostCenter:   row.CostCenter,
+			StartDate:    row.StartDate,
+			EndDate:      row.EndDate,
+			CostAmount:   row.CostAmount,
+			CostCurrency: row.CostCurrency,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return pw.WriteStop()
+}
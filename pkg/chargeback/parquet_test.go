@@ -0,0 +1,92 @@
+package chargeback
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+func TestWriteParquet(t *testing.T) {
+	report := &Report{
+		Rows: []*Row{
+			{
+				LeaseID:      "lease1",
+				PrincipalID:  "user1",
+				AccountID:    "111111111111",
+				CostCenter:   "cc-1",
+				StartDate:    "2019-10-01",
+				EndDate:      "2019-10-10",
+				CostAmount:   12.5,
+				CostCurrency: "USD",
+			},
+			{
+				LeaseID:      "lease2",
+				PrincipalID:  "user2",
+				AccountID:    "222222222222",
+				StartDate:    "2019-10-05",
+				EndDate:      "2019-10-31",
+				CostAmount:   7,
+				CostCurrency: "USD",
+			},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	err := report.WriteParquet(buf)
+	assert.Nil(t, err)
+
+	// Read the file back
+	pf, err := buffer.NewBufferFile(buf.Bytes())
+	assert.Nil(t, err)
+	pr, err := reader.NewParquetReader(pf, new(parquetRow), 1)
+	assert.Nil(t, err)
+	defer pr.ReadStop()
+
+	// The columns are named as in the CSV report, after the schema root
+	for i, column := range Columns {
+		assert.Equal(t, column, pr.SchemaHandler.Infos[i+1].ExName)
+	}
+
+	numRows := int(pr.GetNumRows())
+	assert.Equal(t, 2, numRows)
+	rows := make([]parquetRow, numRows)
+	err = pr.Read(&rows)
+	assert.Nil(t, err)
+	assert.Equal(t, []parquetRow{
+		{
+			LeaseID:      "lease1",
+			PrincipalID:  "user1",
+			AccountID:    "111111111111",
+			CostCenter:   "cc-1",
+			StartDate:    "2019-10-01",
+			EndDate:      "2019-10-10",
+			CostAmount:   12.5,
+			CostCurrency: "USD",
+		},
+		{
+			LeaseID:      "lease2",
+			PrincipalID:  "user2",
+			AccountID:    "222222222222",
+			StartDate:    "2019-10-05",
+			EndDate:      "2019-10-31",
+			CostAmount:   7,
+			CostCurrency: "USD",
+		},
+	}, rows)
+}
+
+func TestWriteParquetWithoutRows(t *testing.T) {
+	buf := &bytes.Buffer{}
+	err := (&Report{}).WriteParquet(buf)
+	assert.Nil(t, err)
+
+	pf, err := buffer.NewBufferFile(buf.Bytes())
+	assert.Nil(t, err)
+	pr, err := reader.NewParquetReader(pf, new(parquetRow), 1)
+	assert.Nil(t, err)
+	defer pr.ReadStop()
+	assert.Equal(t, int64(0), pr.GetNumRows())
+}
@@ -0,0 +1,177 @@
+// Package chargeback builds monthly reports of the spend of each lease,
+// so spend can be charged back to the cost center which requested the lease.
+package chargeback
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Optum/dce/pkg/lease"
+	"github.com/Optum/dce/pkg/usage"
+)
+
+// DefaultCostCenterKey is the lease metadata key holding the cost center of a lease
+const DefaultCostCenterKey = "costCenter"
+
+// dateFormat is the format of dates in the report
+const dateFormat = "2006-01-02"
+
+// Columns are the names of the report columns, in order
+var Columns = []string{
+	"leaseId",
+	"principalId",
+	"accountId",
+	"costCenter",
+	"startDate",
+	"endDate",
+	"costAmount",
+	"costCurrency",
+}
+
+// Row is the spend of a single lease within the report month
+type Row struct {
+	LeaseID      string
+	PrincipalID  string
+	AccountID    string
+	CostCenter   string
+	StartDate    string // Lease start date, as YYYY-MM-DD
+	EndDate      string // Lease end date, as YYYY-MM-DD. Empty if the lease is still active.
+	CostAmount   float64
+	CostCurrency string
+}
+
+// Report is the spend of each lease within a calendar month
+type Report struct {
+	StartDate time.Time // Month start date
+	EndDate   time.Time // Month end date (exclusive)
+	Rows      []*Row
+}
+
+// NewReportInput is the input for building a Report
+type NewReportInput struct {
+	// Any date within the month to report on
+	Date time.Time
+	// Every lease which may have been active during the month, eg. the leases usage was recorded for
+	Leases lease.Leases
+	// Usage recorded during the month
+	Usages usage.Usages
+	// Lease metadata key holding the cost center of the lease
+	CostCenterKey string
+}
+
+// NewReport builds a report with a row for each lease active during the month
+func NewReport(input *NewReportInput) *Report {
+	start, end := usage.SummaryPeriodMonthly.Range(input.Date)
+	costCenterKey := input.CostCenterKey
+	if costCenterKey == "" {
+		costCenterKey = DefaultCostCenterKey
+	}
+
+	report := &Report{
+		StartDate: start,
+		EndDate:   end,
+		Rows:      []*Row{},
+	}
+
+	for _, l := range input.Leases {
+		if l.ID == nil || l.AccountID == nil || l.PrincipalID == nil {
+			continue
+		}
+		// Pending and Scheduled leases haven't been used yet
+		if l.Status != nil && (*l.Status == lease.StatusPending || *l.Status == lease.StatusScheduled) {
+			continue
+		}
+		leaseStart, leaseEnd := leaseRange(&l)
+		if leaseStart >= end.Unix() || (leaseEnd != 0 && leaseEnd <= start.Unix()) {
+			continue
+		}
+
+		row := &Row{
+			LeaseID:      *l.ID,
+			PrincipalID:  *l.PrincipalID,
+			AccountID:    *l.AccountID,
+			CostCenter:   costCenter(l.Metadata, costCenterKey),
+			StartDate:    time.Unix(leaseStart, 0).UTC().Format(dateFormat),
+			CostCurrency: "USD",
+		}
+		if leaseEnd != 0 {
+			row.EndDate = time.Unix(leaseEnd, 0).UTC().Format(dateFormat)
+		}
+
+		for _, usg := range input.Usages {
+			if usg.PrincipalID == nil || *usg.PrincipalID != row.PrincipalID || usg.StartDate == nil {
+				continue
+			}
+			// Usage is recorded daily, so the day must overlap the month and the lease
+			usageStart := *usg.StartDate
+			usageEnd := time.Unix(usageStart, 0).AddDate(0, 0, 1).Unix()
+			if usageStart < start.Unix() || usageStart >= end.Unix() ||
+				usageEnd <= leaseStart || (leaseEnd != 0 && usageStart >= leaseEnd) {
+				continue
+			}
+			row.CostAmount = row.CostAmount + usg.LeaseCostAmount(row.LeaseID, row.AccountID)
+			if usg.CostCurrency != nil && *usg.CostCurrency != "" {
+				row.CostCurrency = *usg.CostCurrency
+			}
+		}
+
+		report.Rows = append(report.Rows, row)
+	}
+
+	sort.Slice(report.Rows, func(i, j int) bool {
+		a, b := report.Rows[i], report.Rows[j]
+		if a.CostCenter != b.CostCenter {
+			return a.CostCenter < b.CostCenter
+		}
+		if a.PrincipalID != b.PrincipalID {
+			return a.PrincipalID < b.PrincipalID
+		}
+		return a.LeaseID < b.LeaseID
+	})
+
+	return report
+}
+
+// Name returns the name of the report, eg. "chargeback-2019-10"
+func (r *Report) Name() string {
+	return fmt.Sprintf("chargeback-%s", r.StartDate.Format("2006-01"))
+}
+
+// TotalCostAmount returns the spend of every lease in the report
+func (r *Report) TotalCostAmount() float64 {
+	total := float64(0)
+	for _, row := range r.Rows {
+		total = total + row.CostAmount
+	}
+	return total
+}
+
+// leaseRange returns the start and end date of the lease.
+// The end date is 0 if the lease hasn't ended.
+func leaseRange(l *lease.Lease) (int64, int64) {
+	start := int64(0)
+	if l.CreatedOn != nil {
+		start = *l.CreatedOn
+	}
+	if l.StartsOn != nil {
+		start = *l.StartsOn
+	}
+
+	end := int64(0)
+	if l.Status != nil && *l.Status == lease.StatusInactive && l.StatusModifiedOn != nil {
+		end = *l.StatusModifiedOn
+	} else if l.ExpiresOn != nil {
+		end = *l.ExpiresOn
+	}
+	return start, end
+}
+
+// costCenter returns the cost center from the lease metadata, if any
+func costCenter(metadata map[string]interface{}, key string) string {
+	value, ok := metadata[key]
+	if !ok || value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
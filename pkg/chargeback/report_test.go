@@ -0,0 +1,190 @@
+package chargeback_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/Optum/dce/pkg/chargeback"
+	"github.com/Optum/dce/pkg/lease"
+	"github.com/Optum/dce/pkg/usage"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewReport(t *testing.T) {
+	date := time.Date(2019, 10, 16, 0, 0, 0, 0, time.UTC)
+	sep20 := time.Date(2019, 9, 20, 0, 0, 0, 0, time.UTC).Unix()
+	sep30 := time.Date(2019, 9, 30, 0, 0, 0, 0, time.UTC).Unix()
+	oct1 := time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC).Unix()
+	oct2 := time.Date(2019, 10, 2, 0, 0, 0, 0, time.UTC).Unix()
+	oct10 := time.Date(2019, 10, 10, 0, 0, 0, 0, time.UTC).Unix()
+	oct10Noon := time.Date(2019, 10, 10, 12, 0, 0, 0, time.UTC).Unix()
+	oct11 := time.Date(2019, 10, 11, 0, 0, 0, 0, time.UTC).Unix()
+	nov1 := time.Date(2019, 11, 1, 0, 0, 0, 0, time.UTC).Unix()
+
+	leases := lease.Leases{
+		{
+			ID:          aws.String("lease1"),
+			PrincipalID: aws.String("user1"),
+			AccountID:   aws.String("111111111111"),
+			Status:      lease.StatusActive.StatusPtr(),
+			CreatedOn:   aws.Int64(sep20),
+			Metadata: map[string]interface{}{
+				"costCenter": "cc-2",
+			},
+		},
+		{
+			ID:               aws.String("lease2"),
+			PrincipalID:      aws.String("user2"),
+			AccountID:        aws.String("222222222222"),
+			Status:           lease.StatusInactive.StatusPtr(),
+			CreatedOn:        aws.Int64(oct1),
+			ExpiresOn:        aws.Int64(nov1),
+			StatusModifiedOn: aws.Int64(oct10Noon),
+			Metadata: map[string]interface{}{
+				"costCenter": float64(1234),
+			},
+		},
+		{
+			ID:               aws.String("lease3"),
+			PrincipalID:      aws.String("user3"),
+			AccountID:        aws.String("333333333333"),
+			Status:           lease.StatusInactive.StatusPtr(),
+			CreatedOn:        aws.Int64(sep20),
+			StatusModifiedOn: aws.Int64(sep30),
+		},
+		{
+			ID:          aws.String("lease4"),
+			PrincipalID: aws.String("user4"),
+			Status:      lease.StatusPending.StatusPtr(),
+			CreatedOn:   aws.Int64(oct1),
+		},
+	}
+
+	usages := usage.Usages{
+		{
+			PrincipalID:  aws.String("user1"),
+			AccountID:    aws.String("111111111111"),
+			StartDate:    aws.Int64(sep30),
+			CostAmount:   aws.Float64(100),
+			CostCurrency: aws.String("USD"),
+		},
+		{
+			PrincipalID:  aws.String("user1"),
+			AccountID:    aws.String("111111111111"),
+			StartDate:    aws.Int64(oct1),
+			CostAmount:   aws.Float64(15),
+			CostCurrency: aws.String("USD"),
+			AccountCosts: map[string]float64{
+				"111111111111": 10,
+				"444444444444": 5,
+			},
+		},
+		{
+			PrincipalID:  aws.String("user1"),
+			AccountID:    aws.String("111111111111"),
+			LeaseID:      aws.String("lease1"),
+			StartDate:    aws.Int64(oct2),
+			CostAmount:   aws.Float64(20),
+			CostCurrency: aws.String("USD"),
+			LeaseCosts: map[string]float64{
+				"lease1": 12,
+				"other":  8,
+			},
+		},
+		{
+			PrincipalID:  aws.String("user2"),
+			AccountID:    aws.String("222222222222"),
+			StartDate:    aws.Int64(oct10),
+			CostAmount:   aws.Float64(7),
+			CostCurrency: aws.String("USD"),
+		},
+		{
+			PrincipalID:  aws.String("user2"),
+			AccountID:    aws.String("222222222222"),
+			StartDate:    aws.Int64(oct11),
+			CostAmount:   aws.Float64(50),
+			CostCurrency: aws.String("USD"),
+		},
+	}
+
+	report := chargeback.NewReport(&chargeback.NewReportInput{
+		Date:   date,
+		Leases: leases,
+		Usages: usages,
+	})
+
+	assert.Equal(t, &chargeback.Report{
+		StartDate: time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2019, 11, 1, 0, 0, 0, 0, time.UTC),
+		Rows: []*chargeback.Row{
+			{
+				LeaseID:      "lease2",
+				PrincipalID:  "user2",
+				AccountID:    "222222222222",
+				CostCenter:   "1234",
+				StartDate:    "2019-10-01",
+				EndDate:      "2019-10-10",
+				CostAmount:   7,
+				CostCurrency: "USD",
+			},
+			{
+				LeaseID:      "lease1",
+				PrincipalID:  "user1",
+				AccountID:    "111111111111",
+				CostCenter:   "cc-2",
+				StartDate:    "2019-09-20",
+				CostAmount:   22,
+				CostCurrency: "USD",
+			},
+		},
+	}, report)
+	assert.Equal(t, "chargeback-2019-10", report.Name())
+	assert.Equal(t, float64(29), report.TotalCostAmount())
+}
+
+func TestNewReportCostCenterKey(t *testing.T) {
+	report := chargeback.NewReport(&chargeback.NewReportInput{
+		Date: time.Date(2019, 10, 16, 0, 0, 0, 0, time.UTC),
+		Leases: lease.Leases{
+			{
+				ID:          aws.String("lease1"),
+				PrincipalID: aws.String("user1"),
+				AccountID:   aws.String("111111111111"),
+				CreatedOn:   aws.Int64(time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC).Unix()),
+				Metadata: map[string]interface{}{
+					"costCenter": "cc-1",
+					"department": "finance",
+				},
+			},
+		},
+		CostCenterKey: "department",
+	})
+
+	assert.Len(t, report.Rows, 1)
+	assert.Equal(t, "finance", report.Rows[0].CostCenter)
+}
+
+func TestWriteCSV(t *testing.T) {
+	report := &chargeback.Report{
+		Rows: []*chargeback.Row{
+			{
+				LeaseID:      "lease1",
+				PrincipalID:  "user1",
+				AccountID:    "111111111111",
+				CostCenter:   "cc, 1",
+				StartDate:    "2019-10-01",
+				EndDate:      "2019-10-10",
+				CostAmount:   12.5,
+				CostCurrency: "USD",
+			},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	err := report.WriteCSV(buf)
+	assert.Nil(t, err)
+	assert.Equal(t, "leaseId,principalId,accountId,costCenter,startDate,endDate,costAmount,costCurrency\n"+
+		"lease1,user1,111111111111,\"cc, 1\",2019-10-01,2019-10-10,12.50,USD\n", buf.String())
+}
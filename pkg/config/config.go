@@ -3,6 +3,7 @@ package config
 import (
 	"errors"
 	"fmt"
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ssm"
 	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
 	"log"
@@ -219,8 +220,9 @@ func (config *ConfigurationBuilder) RetrieveParameterStoreVals() error {
 			return err
 		}
 
-		// Using bulk api to reduce number of SSM requests
-		withDecryption := false
+		// Using bulk api to reduce number of SSM requests.
+		// Decryption is needed for SecureString parameters, and is ignored for String parameters
+		withDecryption := true
 		getParametersOutput, err := ssmClient.GetParameters(&ssm.GetParametersInput{
 			Names:          getKeyPtrs(valsToRetrieve),
 			WithDecryption: &withDecryption,
@@ -232,7 +234,8 @@ func (config *ConfigurationBuilder) RetrieveParameterStoreVals() error {
 		// Overwrite config.values.vals {Config Key: Param Name} -> {Config Key: Param Value}
 		params := getParametersOutput.Parameters
 		for _, param := range params {
-			log.Print("Retrieved SSM Parameter: ", param.GoString())
+			// Only log the name, as the value may be a secret
+			log.Print("Retrieved SSM Parameter: ", aws.StringValue(param.Name))
 			key := valsToRetrieve[*param.Name].Key
 			config.WithVal(key, *param.Value)
 		}
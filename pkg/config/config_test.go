@@ -210,7 +210,7 @@ func TestConfigBuilder_BuildWithParameterStoreEnvVar(t *testing.T) {
 		},
 	}
 	mockSSMClient.On("GetParameters", mock.MatchedBy(func(input *ssm.GetParametersInput) bool {
-		return *input.Names[0] == ExpectedEnvStrVal && *input.WithDecryption == false
+		return *input.Names[0] == ExpectedEnvStrVal && *input.WithDecryption == true
 	})).Return(&getParametersOutput, nil)
 
 	// Act
@@ -237,7 +237,7 @@ func TestConfigBuilder_BuildWithParameterStoreEnvVar_UsesDefaultWhenInvalidParam
 	}
 	defaultValue := "defaultValue"
 	mockSSMClient.On("GetParameters", mock.MatchedBy(func(input *ssm.GetParametersInput) bool {
-		return *input.Names[0] == ExpectedEnvStrVal && *input.WithDecryption == false
+		return *input.Names[0] == ExpectedEnvStrVal && *input.WithDecryption == true
 	})).Return(&getParametersOutput, nil)
 
 	// Act
@@ -11,19 +11,29 @@ import (
 	"github.com/Optum/dce/pkg/account/accountiface"
 	"github.com/Optum/dce/pkg/accountmanager"
 	"github.com/Optum/dce/pkg/accountmanager/accountmanageriface"
+	"github.com/Optum/dce/pkg/budgetpolicy"
+	"github.com/Optum/dce/pkg/budgetpolicy/budgetpolicyiface"
 	"github.com/Optum/dce/pkg/common"
 	"github.com/Optum/dce/pkg/data"
 	"github.com/Optum/dce/pkg/data/dataiface"
+	"github.com/Optum/dce/pkg/email"
 	"github.com/Optum/dce/pkg/event"
 	"github.com/Optum/dce/pkg/event/eventiface"
 	"github.com/Optum/dce/pkg/lease"
 	"github.com/Optum/dce/pkg/lease/leaseiface"
+	"github.com/Optum/dce/pkg/provisioner"
+	"github.com/Optum/dce/pkg/provisioner/provisioneriface"
+	"github.com/Optum/dce/pkg/resetrun"
+	"github.com/Optum/dce/pkg/resetrun/resetruniface"
+	"github.com/Optum/dce/pkg/usage"
+	"github.com/Optum/dce/pkg/usage/usageiface"
 
 	"github.com/aws/aws-sdk-go/service/codebuild/codebuildiface"
 	"github.com/aws/aws-sdk-go/service/cognitoidentityprovider/cognitoidentityprovideriface"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
 	"github.com/aws/aws-sdk-go/service/lambda"
 	"github.com/aws/aws-sdk-go/service/lambda/lambdaiface"
+	"github.com/aws/aws-sdk-go/service/organizations/organizationsiface"
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/aws/aws-sdk-go/service/sns/snsiface"
@@ -37,7 +47,9 @@ import (
 	"github.com/aws/aws-sdk-go/service/codebuild"
 	"github.com/aws/aws-sdk-go/service/cognitoidentityprovider"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/organizations"
 	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/ses"
 	"github.com/aws/aws-sdk-go/service/sns"
 	"github.com/aws/aws-sdk-go/service/sqs"
 	"github.com/aws/aws-sdk-go/service/sts"
@@ -130,6 +142,12 @@ func (bldr *ServiceBuilder) WithLambda() *ServiceBuilder {
 	return bldr
 }
 
+// WithOrganizations tells the builder to add an AWS Organizations service to the `DefaultConfigurater`
+func (bldr *ServiceBuilder) WithOrganizations() *ServiceBuilder {
+	bldr.handlers = append(bldr.handlers, bldr.createOrganizations)
+	return bldr
+}
+
 // WithStorageService tells the builder to add the DCE DAO (DBer) service to the `ConfigurationBuilder`
 func (bldr *ServiceBuilder) WithStorageService() *ServiceBuilder {
 	bldr.WithS3()
@@ -187,7 +205,7 @@ func (bldr *ServiceBuilder) AccountService() accountiface.Servicer {
 
 // WithLeaseService tells the builder to add the Account service to the `ConfigurationBuilder`
 func (bldr *ServiceBuilder) WithLeaseService() *ServiceBuilder {
-	bldr.WithLeaseDataService().WithEventService()
+	bldr.WithLeaseDataService().WithAccountDataService().WithEventService()
 	bldr.handlers = append(bldr.handlers, bldr.createLeaseService)
 	return bldr
 }
@@ -203,6 +221,123 @@ func (bldr *ServiceBuilder) LeaseService() leaseiface.Servicer {
 	return leaseSvc
 }
 
+// WithProvisionerRequestDataService tells the builder to add the Data service to the `ConfigurationBuilder`
+func (bldr *ServiceBuilder) WithProvisionerRequestDataService() *ServiceBuilder {
+	bldr.WithDynamoDB()
+	bldr.handlers = append(bldr.handlers, bldr.createProvisionerRequestDataService)
+	return bldr
+}
+
+// WithProvisionerService tells the builder to add the Provisioner service to the `ConfigurationBuilder`
+func (bldr *ServiceBuilder) WithProvisionerService() *ServiceBuilder {
+	bldr.WithOrganizations().WithAccountService().WithProvisionerRequestDataService()
+	bldr.handlers = append(bldr.handlers, bldr.createProvisionerService)
+	return bldr
+}
+
+// ProvisionerService returns the provisioner Service for you
+func (bldr *ServiceBuilder) ProvisionerService() provisioneriface.Servicer {
+
+	var provisionerSvc provisioneriface.Servicer
+	if err := bldr.Config.GetService(&provisionerSvc); err != nil {
+		panic(err)
+	}
+
+	return provisionerSvc
+}
+
+// WithBudgetPolicyDataService tells the builder to add the Data service to the `ConfigurationBuilder`
+func (bldr *ServiceBuilder) WithBudgetPolicyDataService() *ServiceBuilder {
+	bldr.WithDynamoDB()
+	bldr.handlers = append(bldr.handlers, bldr.createBudgetPolicyDataService)
+	return bldr
+}
+
+// WithBudgetPolicyService tells the builder to add the Budget Policy service to the `ConfigurationBuilder`
+func (bldr *ServiceBuilder) WithBudgetPolicyService() *ServiceBuilder {
+	bldr.WithBudgetPolicyDataService().WithUserDetailer()
+	bldr.handlers = append(bldr.handlers, bldr.createBudgetPolicyService)
+	return bldr
+}
+
+// BudgetPolicyService returns the budget policy Service for you
+func (bldr *ServiceBuilder) BudgetPolicyService() budgetpolicyiface.Servicer {
+
+	var budgetPolicySvc budgetpolicyiface.Servicer
+	if err := bldr.Config.GetService(&budgetPolicySvc); err != nil {
+		panic(err)
+	}
+
+	return budgetPolicySvc
+}
+
+// WithUsageDataService tells the builder to add the Data service to the `ConfigurationBuilder`
+func (bldr *ServiceBuilder) WithUsageDataService() *ServiceBuilder {
+	bldr.WithDynamoDB()
+	bldr.handlers = append(bldr.handlers, bldr.createUsageDataService)
+	return bldr
+}
+
+// WithUsageService tells the builder to add the Usage service to the `ConfigurationBuilder`
+func (bldr *ServiceBuilder) WithUsageService() *ServiceBuilder {
+	bldr.WithUsageDataService()
+	bldr.handlers = append(bldr.handlers, bldr.createUsageService)
+	return bldr
+}
+
+// UsageService returns the usage Service for you
+func (bldr *ServiceBuilder) UsageService() usageiface.Servicer {
+
+	var usageSvc usageiface.Servicer
+	if err := bldr.Config.GetService(&usageSvc); err != nil {
+		panic(err)
+	}
+
+	return usageSvc
+}
+
+// WithResetRunDataService tells the builder to add the Data service to the `ConfigurationBuilder`
+func (bldr *ServiceBuilder) WithResetRunDataService() *ServiceBuilder {
+	bldr.WithDynamoDB()
+	bldr.handlers = append(bldr.handlers, bldr.createResetRunDataService)
+	return bldr
+}
+
+// WithResetRunService tells the builder to add the Reset Run service to the `ConfigurationBuilder`
+func (bldr *ServiceBuilder) WithResetRunService() *ServiceBuilder {
+	bldr.WithResetRunDataService()
+	bldr.handlers = append(bldr.handlers, bldr.createResetRunService)
+	return bldr
+}
+
+// ResetRunService returns the reset run Service for you
+func (bldr *ServiceBuilder) ResetRunService() resetruniface.Servicer {
+
+	var resetRunSvc resetruniface.Servicer
+	if err := bldr.Config.GetService(&resetRunSvc); err != nil {
+		panic(err)
+	}
+
+	return resetRunSvc
+}
+
+// WithEmailService tells the builder to add the SES Email service to the `ConfigurationBuilder`
+func (bldr *ServiceBuilder) WithEmailService() *ServiceBuilder {
+	bldr.handlers = append(bldr.handlers, bldr.createEmailService)
+	return bldr
+}
+
+// EmailService returns the Email Service for you
+func (bldr *ServiceBuilder) EmailService() email.Service {
+
+	var emailSvc email.Service
+	if err := bldr.Config.GetService(&emailSvc); err != nil {
+		panic(err)
+	}
+
+	return emailSvc
+}
+
 // WithEventService tells the builder to add the Account service to the `ConfigurationBuilder`
 func (bldr *ServiceBuilder) WithEventService() *ServiceBuilder {
 	bldr.WithSQS().WithSNS()
@@ -414,6 +549,20 @@ func (bldr *ServiceBuilder) createLambda(config ConfigurationServiceBuilder) err
 	return nil
 }
 
+func (bldr *ServiceBuilder) createOrganizations(config ConfigurationServiceBuilder) error {
+	// Don't add the service twice
+	var organizationsAPI organizationsiface.OrganizationsAPI
+	err := bldr.Config.GetService(&organizationsAPI)
+	if err == nil {
+		log.Printf("Already added Organizations service")
+		return nil
+	}
+
+	organizationsSvc := organizations.New(bldr.awsSession)
+	config.WithService(organizationsSvc)
+	return nil
+}
+
 func (bldr *ServiceBuilder) createStorageService(config ConfigurationServiceBuilder) error {
 	// Don't add the service twice
 	var api common.Storager
@@ -654,12 +803,293 @@ func (bldr *ServiceBuilder) createLeaseService(config ConfigurationServiceBuilde
 		return err
 	}
 
+	var accountDataSvc dataiface.AccountData
+	err = bldr.Config.GetService(&accountDataSvc)
+	if err != nil {
+		return err
+	}
+
+	var eventSvc eventiface.Servicer
+	err = bldr.Config.GetService(&eventSvc)
+	if err != nil {
+		return err
+	}
+
 	leaseSvc := lease.NewService(
 		lease.NewServiceInput{
-			DataSvc: dataSvc,
+			DataSvc:    dataSvc,
+			AccountSvc: accountDataSvc,
+			EventSvc:   eventSvc,
 		},
 	)
 
 	config.WithService(leaseSvc)
 	return nil
 }
+
+func (bldr *ServiceBuilder) createBudgetPolicyDataService(config ConfigurationServiceBuilder) error {
+	// Don't add the service twice
+	var api dataiface.BudgetPolicyData
+	err := bldr.Config.GetService(&api)
+	if err == nil {
+		log.Printf("Already added Budget Policy Data service")
+		return nil
+	}
+
+	var dynamodbSvc dynamodbiface.DynamoDBAPI
+	err = bldr.Config.GetService(&dynamodbSvc)
+
+	if err != nil {
+		return err
+	}
+
+	dataSvcImpl := &data.BudgetPolicy{}
+
+	err = bldr.Config.Unmarshal(dataSvcImpl)
+	if err != nil {
+		return err
+	}
+
+	dataSvcImpl.DynamoDB = dynamodbSvc
+
+	config.WithService(dataSvcImpl)
+	return nil
+}
+
+func (bldr *ServiceBuilder) createBudgetPolicyService(config ConfigurationServiceBuilder) error {
+	// Don't add the service twice
+	var budgetPolicyAPI budgetpolicyiface.Servicer
+	err := bldr.Config.GetService(&budgetPolicyAPI)
+	if err == nil {
+		log.Printf("Already added Budget Policy service")
+		return nil
+	}
+
+	var dataSvc dataiface.BudgetPolicyData
+	err = bldr.Config.GetService(&dataSvc)
+	if err != nil {
+		return err
+	}
+
+	var userDetailer api.UserDetailer
+	err = bldr.Config.GetService(&userDetailer)
+	if err != nil {
+		return err
+	}
+
+	input := budgetpolicy.NewServiceInput{}
+	err = bldr.Config.Unmarshal(&input)
+	if err != nil {
+		return err
+	}
+	input.DataSvc = dataSvc
+	input.GroupSvc = userDetailer
+
+	budgetPolicySvc := budgetpolicy.NewService(input)
+
+	config.WithService(budgetPolicySvc)
+	return nil
+}
+
+func (bldr *ServiceBuilder) createUsageDataService(config ConfigurationServiceBuilder) error {
+	// Don't add the service twice
+	var api usage.ReaderWriter
+	err := bldr.Config.GetService(&api)
+	if err == nil {
+		log.Printf("Already added Usage Data service")
+		return nil
+	}
+
+	var dynamodbSvc dynamodbiface.DynamoDBAPI
+	err = bldr.Config.GetService(&dynamodbSvc)
+
+	if err != nil {
+		return err
+	}
+
+	dataSvcImpl := &data.Usage{}
+
+	err = bldr.Config.Unmarshal(dataSvcImpl)
+	if err != nil {
+		return err
+	}
+
+	dataSvcImpl.DynamoDB = dynamodbSvc
+
+	config.WithService(dataSvcImpl)
+	return nil
+}
+
+func (bldr *ServiceBuilder) createUsageService(config ConfigurationServiceBuilder) error {
+	// Don't add the service twice
+	var usageAPI usageiface.Servicer
+	err := bldr.Config.GetService(&usageAPI)
+	if err == nil {
+		log.Printf("Already added Usage service")
+		return nil
+	}
+
+	var dataSvc usage.ReaderWriter
+	err = bldr.Config.GetService(&dataSvc)
+	if err != nil {
+		return err
+	}
+
+	usageSvc := usage.NewService(
+		usage.NewServiceInput{
+			DataSvc: dataSvc,
+		},
+	)
+
+	config.WithService(usageSvc)
+	return nil
+}
+
+func (bldr *ServiceBuilder) createResetRunDataService(config ConfigurationServiceBuilder) error {
+	// Don't add the service twice
+	var api dataiface.ResetRunData
+	err := bldr.Config.GetService(&api)
+	if err == nil {
+		log.Printf("Already added Reset Run Data service")
+		return nil
+	}
+
+	var dynamodbSvc dynamodbiface.DynamoDBAPI
+	err = bldr.Config.GetService(&dynamodbSvc)
+
+	if err != nil {
+		return err
+	}
+
+	dataSvcImpl := &data.ResetRun{}
+
+	err = bldr.Config.Unmarshal(dataSvcImpl)
+	if err != nil {
+		return err
+	}
+
+	dataSvcImpl.DynamoDB = dynamodbSvc
+
+	config.WithService(dataSvcImpl)
+	return nil
+}
+
+func (bldr *ServiceBuilder) createResetRunService(config ConfigurationServiceBuilder) error {
+	// Don't add the service twice
+	var resetRunAPI resetruniface.Servicer
+	err := bldr.Config.GetService(&resetRunAPI)
+	if err == nil {
+		log.Printf("Already added Reset Run service")
+		return nil
+	}
+
+	var dataSvc dataiface.ResetRunData
+	err = bldr.Config.GetService(&dataSvc)
+	if err != nil {
+		return err
+	}
+
+	resetRunSvc := resetrun.NewService(
+		resetrun.NewServiceInput{
+			DataSvc: dataSvc,
+		},
+	)
+
+	config.WithService(resetRunSvc)
+	return nil
+}
+
+func (bldr *ServiceBuilder) createEmailService(config ConfigurationServiceBuilder) error {
+	// Don't add the service twice
+	var emailAPI email.Service
+	err := bldr.Config.GetService(&emailAPI)
+	if err == nil {
+		log.Printf("Already added Email service")
+		return nil
+	}
+
+	emailSvc := &email.SESEmailService{
+		SES: ses.New(bldr.awsSession),
+	}
+
+	config.WithService(emailSvc)
+	return nil
+}
+
+func (bldr *ServiceBuilder) createProvisionerRequestDataService(config ConfigurationServiceBuilder) error {
+	// Don't add the service twice
+	var api dataiface.ProvisionerRequestData
+	err := bldr.Config.GetService(&api)
+	if err == nil {
+		log.Printf("Already added Provisioner Request Data service")
+		return nil
+	}
+
+	var dynamodbSvc dynamodbiface.DynamoDBAPI
+	err = bldr.Config.GetService(&dynamodbSvc)
+
+	if err != nil {
+		return err
+	}
+
+	dataSvcImpl := &data.ProvisionerRequest{}
+
+	err = bldr.Config.Unmarshal(dataSvcImpl)
+	if err != nil {
+		return err
+	}
+
+	dataSvcImpl.DynamoDB = dynamodbSvc
+
+	config.WithService(dataSvcImpl)
+	return nil
+}
+
+func (bldr *ServiceBuilder) createProvisionerService(config ConfigurationServiceBuilder) error {
+	// Don't add the service twice
+	var api provisioneriface.Servicer
+	err := bldr.Config.GetService(&api)
+	if err == nil {
+		log.Printf("Already added Provisioner service")
+		return nil
+	}
+
+	provisionerSvcConfig := provisioner.ServiceConfig{}
+	err = bldr.Config.Unmarshal(&provisionerSvcConfig)
+	if err != nil {
+		return err
+	}
+
+	var organizationsSvc organizationsiface.OrganizationsAPI
+	err = bldr.Config.GetService(&organizationsSvc)
+	if err != nil {
+		return err
+	}
+
+	var accountSvc accountiface.Servicer
+	err = bldr.Config.GetService(&accountSvc)
+	if err != nil {
+		return err
+	}
+
+	var requestSvc dataiface.ProvisionerRequestData
+	err = bldr.Config.GetService(&requestSvc)
+	if err != nil {
+		return err
+	}
+
+	provisionerSvc, err := provisioner.NewService(
+		provisioner.NewServiceInput{
+			OrgSvc:     organizationsSvc,
+			AccountSvc: accountSvc,
+			RequestSvc: requestSvc,
+			Config:     provisionerSvcConfig,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	config.WithService(provisionerSvc)
+	return nil
+}
@@ -15,14 +15,14 @@ type queryScanOutput struct {
 }
 
 // queryAccounts for doing a query against dynamodb
-func (a *Account) queryAccounts(query *account.Account, keyName string, index string) (*queryScanOutput, error) {
+func (a *Account) queryAccounts(query *account.Account, keyName string, keyValue *string, index string) (*queryScanOutput, error) {
 	var expr expression.Expression
 	var bldr expression.Builder
 	var err error
 	var res *dynamodb.QueryOutput
 
-	keyCondition, filters := getFiltersFromStruct(query, &keyName)
-	bldr = expression.NewBuilder().WithKeyCondition(*keyCondition)
+	kb, filters := getFiltersFromStruct(query, &keyName)
+	bldr = expression.NewBuilder().WithKeyCondition(*kb)
 	if filters != nil {
 		bldr = bldr.WithFilter(*filters)
 	}
@@ -44,12 +44,18 @@ func (a *Account) queryAccounts(query *account.Account, keyName string, index st
 
 	queryInput.SetLimit(*query.Limit)
 	if query.NextID != nil {
-		// Should be more dynamic
-		queryInput.SetExclusiveStartKey(map[string]*dynamodb.AttributeValue{
+		// Index queries need the index key to start from, as well as the table key
+		startKey := map[string]*dynamodb.AttributeValue{
 			"Id": &dynamodb.AttributeValue{
 				S: query.NextID,
 			},
-		})
+		}
+		if keyValue != nil {
+			startKey[keyName] = &dynamodb.AttributeValue{
+				S: keyValue,
+			}
+		}
+		queryInput.SetExclusiveStartKey(startKey)
 	}
 
 	res, err = a.DynamoDB.Query(queryInput)
@@ -120,8 +126,10 @@ func (a *Account) List(query *account.Account) (*account.Accounts, error) {
 		query.Limit = &a.Limit
 	}
 
-	if query.Status != nil {
-		outputs, err = a.queryAccounts(query, "AccountStatus", "AccountStatus")
+	if query.Pool != nil {
+		outputs, err = a.queryAccounts(query, "Pool", query.Pool, "Pool")
+	} else if query.Status != nil {
+		outputs, err = a.queryAccounts(query, "AccountStatus", query.Status.StringPtr(), "AccountStatus")
 	} else {
 		outputs, err = a.scanAccounts(query)
 	}
@@ -130,7 +138,7 @@ func (a *Account) List(query *account.Account) (*account.Accounts, error) {
 	}
 
 	query.NextID = nil
-	for _, v := range outputs.lastEvaluatedKey {
+	if v, ok := outputs.lastEvaluatedKey["Id"]; ok {
 		query.NextID = v.S
 	}
 
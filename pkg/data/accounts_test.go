@@ -221,6 +221,61 @@ func TestGetAccountsQuery(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "query accounts by pool with status filter",
+			query: &account.Account{
+				Status: account.StatusReady.StatusPtr(),
+				Pool:   ptrString("gov-region"),
+				NextID: ptrString("123456789011"),
+			},
+			qInput: &dynamodb.QueryInput{
+				ConsistentRead: aws.Bool(false),
+				TableName:      aws.String("Accounts"),
+				IndexName:      aws.String("Pool"),
+				ExpressionAttributeNames: map[string]*string{
+					"#0": aws.String("AccountStatus"),
+					"#1": aws.String("Pool"),
+				},
+				ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+					":0": {
+						S: aws.String("Ready"),
+					},
+					":1": {
+						S: aws.String("gov-region"),
+					},
+				},
+				ExclusiveStartKey: map[string]*dynamodb.AttributeValue{
+					"Id": {
+						S: aws.String("123456789011"),
+					},
+					"Pool": {
+						S: aws.String("gov-region"),
+					},
+				},
+				KeyConditionExpression: aws.String("#1 = :1"),
+				FilterExpression:       aws.String("#0 = :0"),
+				Limit:                  aws.Int64(5),
+			},
+			qOutputRec: &dynamodb.QueryOutput{
+				Items: []map[string]*dynamodb.AttributeValue{
+					map[string]*dynamodb.AttributeValue{
+						"Id": {
+							S: aws.String("123456789012"),
+						},
+						"Pool": {
+							S: aws.String("gov-region"),
+						},
+					},
+				},
+			},
+			expAccounts: &account.Accounts{
+				{
+					ID:                 ptrString("123456789012"),
+					Pool:               ptrString("gov-region"),
+					PrincipalPolicyArn: arn.New("aws", "iam", "", "123456789012", "policy/DCEPrincipalDefaultPolicy"),
+				},
+			},
+		},
 		{
 			name: "query internal error",
 			query: &account.Account{
@@ -0,0 +1,266 @@
+package data
+
+import (
+	"fmt"
+
+	"github.com/Optum/dce/pkg/budgetpolicy"
+	"github.com/Optum/dce/pkg/errors"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+)
+
+// BudgetPolicy - Data Layer Struct
+type BudgetPolicy struct {
+	DynamoDB       dynamodbiface.DynamoDBAPI
+	TableName      string `env:"BUDGET_POLICY_DB"`
+	ConsistentRead bool   `env:"USE_CONSISTENT_READS" envDefault:"false"`
+	Limit          int64  `env:"LIMIT" envDefault:"25"`
+}
+
+// Write the Budget Policy record in DynamoDB
+// This is an upsert operation in which the record will either
+// be inserted or updated
+// prevLastModifiedOn parameter is the original lastModifiedOn
+// Policies are keyed by their target, so a principal or group
+// which already has a policy can't be given another.
+func (a *BudgetPolicy) Write(policy *budgetpolicy.BudgetPolicy, prevLastModifiedOn *int64) error {
+
+	var expr expression.Expression
+	var err error
+	returnValue := "NONE"
+	// lastModifiedOn is nil on a create
+	if prevLastModifiedOn != nil {
+		modExpr := expression.Name("LastModifiedOn").Equal(expression.Value(prevLastModifiedOn))
+		expr, err = expression.NewBuilder().WithCondition(modExpr).Build()
+		if err != nil {
+			return errors.NewInternalServer("error building query", err)
+		}
+	} else {
+		modExpr := expression.Name("Target").AttributeNotExists()
+		expr, err = expression.NewBuilder().WithCondition(modExpr).Build()
+		if err != nil {
+			return errors.NewInternalServer("error building query", err)
+		}
+	}
+
+	putMap, _ := dynamodbattribute.Marshal(policy)
+	input := &dynamodb.PutItemInput{
+		TableName:                 aws.String(a.TableName),
+		Item:                      putMap.M,
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		ReturnValues:              aws.String(returnValue),
+	}
+	err = putItem(input, a.DynamoDB)
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) {
+		if awsErr.Code() == "ConditionalCheckFailedException" {
+			if prevLastModifiedOn == nil {
+				return errors.NewAlreadyExists("budget policy", policy.Target())
+			}
+			return errors.NewConflict(
+				"budget policy",
+				*policy.ID,
+				fmt.Errorf("unable to update budget policy: budget policy has been modified since request was made"))
+		}
+	}
+	if err != nil {
+		return errors.NewInternalServer(
+			fmt.Sprintf("update failed for budget policy %q", *policy.ID),
+			err,
+		)
+	}
+
+	return nil
+}
+
+// Delete the Budget Policy record in DynamoDB
+func (a *BudgetPolicy) Delete(policy *budgetpolicy.BudgetPolicy) error {
+
+	_, err := a.DynamoDB.DeleteItem(
+		&dynamodb.DeleteItemInput{
+			TableName:    aws.String(a.TableName),
+			ReturnValues: aws.String("NONE"),
+			Key: map[string]*dynamodb.AttributeValue{
+				"Target": {
+					S: policy.TargetKey,
+				},
+			},
+		},
+	)
+
+	if err != nil {
+		return errors.NewInternalServer(
+			fmt.Sprintf("delete failed for budget policy %q", *policy.ID),
+			err,
+		)
+	}
+
+	return nil
+}
+
+// Get the Budget Policy record by ID
+func (a *BudgetPolicy) Get(ID string) (*budgetpolicy.BudgetPolicy, error) {
+	res, err := query(
+		&dynamodb.QueryInput{
+			ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+				":id": {
+					S: aws.String(ID),
+				},
+			},
+			KeyConditionExpression: aws.String("Id = :id"),
+			TableName:              aws.String(a.TableName),
+			IndexName:              aws.String("BudgetPolicyId"),
+		},
+		a.DynamoDB,
+	)
+
+	if err != nil {
+		return nil, errors.NewInternalServer(
+			fmt.Sprintf("get failed for budget policy %q", ID),
+			err,
+		)
+	}
+
+	if len(res.Items) == 0 {
+		return nil, errors.NewNotFound("budget policy", ID)
+	}
+
+	policy := &budgetpolicy.BudgetPolicy{}
+	err = dynamodbattribute.UnmarshalMap(res.Items[0], policy)
+	if err != nil {
+		return nil, errors.NewInternalServer(
+			fmt.Sprintf("failure unmarshaling budget policy %q", ID),
+			err,
+		)
+	}
+	return policy, nil
+}
+
+// GetByTarget gets the Budget Policy record of a principal or group,
+// eg. "principal:jdoe" or "group:Interns"
+func (a *BudgetPolicy) GetByTarget(target string) (*budgetpolicy.BudgetPolicy, error) {
+	res, err := getItem(
+		&dynamodb.GetItemInput{
+			TableName: aws.String(a.TableName),
+			Key: map[string]*dynamodb.AttributeValue{
+				"Target": {
+					S: aws.String(target),
+				},
+			},
+			ConsistentRead: aws.Bool(a.ConsistentRead),
+		},
+		a.DynamoDB,
+	)
+
+	if err != nil {
+		return nil, errors.NewInternalServer(
+			fmt.Sprintf("get failed for budget policy %q", target),
+			err,
+		)
+	}
+
+	if len(res.Item) == 0 {
+		return nil, errors.NewNotFound("budget policy", target)
+	}
+
+	policy := &budgetpolicy.BudgetPolicy{}
+	err = dynamodbattribute.UnmarshalMap(res.Item, policy)
+	if err != nil {
+		return nil, errors.NewInternalServer(
+			fmt.Sprintf("failure unmarshaling budget policy %q", target),
+			err,
+		)
+	}
+	return policy, nil
+}
+
+// List Get a list of budget policies.
+// Policies for a principal or group are queried by their target,
+// otherwise the table is scanned.
+func (a *BudgetPolicy) List(query *budgetpolicy.BudgetPolicy) (*budgetpolicy.BudgetPolicies, error) {
+	var expr expression.Expression
+	var err error
+
+	if query.Limit == nil {
+		query.Limit = &a.Limit
+	}
+
+	keyName := "Target"
+	keyCondition, filters := getFiltersFromStruct(query, &keyName)
+	if keyCondition != nil {
+		builder := expression.NewBuilder().WithKeyCondition(*keyCondition)
+		if filters != nil {
+			builder = builder.WithFilter(*filters)
+		}
+		expr, err = builder.Build()
+	} else if filters != nil {
+		expr, err = expression.NewBuilder().WithFilter(*filters).Build()
+	}
+	if err != nil {
+		return nil, errors.NewInternalServer("unable to build query", err)
+	}
+
+	var startKey map[string]*dynamodb.AttributeValue
+	if query.NextTarget != nil {
+		startKey = map[string]*dynamodb.AttributeValue{
+			"Target": {
+				S: query.NextTarget,
+			},
+		}
+	}
+
+	var items []map[string]*dynamodb.AttributeValue
+	var lastEvaluatedKey map[string]*dynamodb.AttributeValue
+	if keyCondition != nil {
+		queryInput := &dynamodb.QueryInput{
+			TableName:                 aws.String(a.TableName),
+			ConsistentRead:            aws.Bool(a.ConsistentRead),
+			KeyConditionExpression:    expr.KeyCondition(),
+			FilterExpression:          expr.Filter(),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expr.Values(),
+			ExclusiveStartKey:         startKey,
+		}
+		queryInput.SetLimit(*query.Limit)
+		res, err := a.DynamoDB.Query(queryInput)
+		if err != nil {
+			return nil, errors.NewInternalServer("error getting budget policies", err)
+		}
+		items, lastEvaluatedKey = res.Items, res.LastEvaluatedKey
+	} else {
+		scanInput := &dynamodb.ScanInput{
+			TableName:                 aws.String(a.TableName),
+			ConsistentRead:            aws.Bool(a.ConsistentRead),
+			FilterExpression:          expr.Filter(),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expr.Values(),
+			ExclusiveStartKey:         startKey,
+		}
+		scanInput.SetLimit(*query.Limit)
+		res, err := a.DynamoDB.Scan(scanInput)
+		if err != nil {
+			return nil, errors.NewInternalServer("error getting budget policies", err)
+		}
+		items, lastEvaluatedKey = res.Items, res.LastEvaluatedKey
+	}
+
+	query.NextTarget = nil
+	if v, ok := lastEvaluatedKey["Target"]; ok {
+		query.NextTarget = v.S
+	}
+
+	policies := &budgetpolicy.BudgetPolicies{}
+	err = dynamodbattribute.UnmarshalListOfMaps(items, policies)
+	if err != nil {
+		return nil, errors.NewInternalServer("failed unmarshaling of budget policies", err)
+	}
+
+	return policies, nil
+}
@@ -0,0 +1,385 @@
+package data
+
+import (
+	gErrors "errors"
+	"fmt"
+	"strconv"
+	"testing"
+
+	awsmocks "github.com/Optum/dce/pkg/awsiface/mocks"
+	"github.com/Optum/dce/pkg/budgetpolicy"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetBudgetPolicyByID(t *testing.T) {
+	tests := []struct {
+		name         string
+		policyID     string
+		dynamoErr    error
+		dynamoOutput *dynamodb.QueryOutput
+		expErr       error
+		expPolicy    *budgetpolicy.BudgetPolicy
+	}{
+		{
+			name:     "should return a budget policy object",
+			policyID: "f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0",
+			expPolicy: &budgetpolicy.BudgetPolicy{
+				ID:                   ptrString("f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0"),
+				Group:                ptrString("Interns"),
+				MaxLeaseBudgetAmount: aws.Float64(50),
+				TargetKey:            ptrString("group:Interns"),
+				LastModifiedOn:       ptrInt64(1573592058),
+			},
+			dynamoOutput: &dynamodb.QueryOutput{
+				Items: []map[string]*dynamodb.AttributeValue{
+					{
+						"Id": {
+							S: aws.String("f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0"),
+						},
+						"Group": {
+							S: aws.String("Interns"),
+						},
+						"MaxLeaseBudgetAmount": {
+							N: aws.String("50"),
+						},
+						"Target": {
+							S: aws.String("group:Interns"),
+						},
+						"LastModifiedOn": {
+							N: aws.String(strconv.Itoa(1573592058)),
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "should return not found error when missing",
+			policyID: "f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0",
+			dynamoOutput: &dynamodb.QueryOutput{
+				Items: []map[string]*dynamodb.AttributeValue{},
+			},
+			expErr: errors.NewNotFound("budget policy", "f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0"),
+		},
+		{
+			name:         "should return internal server error when dynamodb fails",
+			policyID:     "f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0",
+			dynamoErr:    gErrors.New("failure"),
+			dynamoOutput: &dynamodb.QueryOutput{},
+			expErr:       errors.NewInternalServer("get failed for budget policy \"f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0\"", gErrors.New("failure")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamo := awsmocks.DynamoDBAPI{}
+			mockDynamo.On("Query", mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+				return *input.TableName == "BudgetPolicies" &&
+					*input.IndexName == "BudgetPolicyId" &&
+					*input.ExpressionAttributeValues[":id"].S == tt.policyID
+			})).Return(tt.dynamoOutput, tt.dynamoErr)
+
+			policyData := &BudgetPolicy{
+				DynamoDB:  &mockDynamo,
+				TableName: "BudgetPolicies",
+			}
+
+			policy, err := policyData.Get(tt.policyID)
+			assert.True(t, errors.Is(err, tt.expErr))
+			assert.Equal(t, tt.expPolicy, policy)
+		})
+	}
+}
+
+func TestGetBudgetPolicyByTarget(t *testing.T) {
+	tests := []struct {
+		name         string
+		target       string
+		dynamoErr    error
+		dynamoOutput *dynamodb.GetItemOutput
+		expErr       error
+		expPolicy    *budgetpolicy.BudgetPolicy
+	}{
+		{
+			name:   "should return the policy of the principal",
+			target: "principal:jdoe",
+			expPolicy: &budgetpolicy.BudgetPolicy{
+				ID:          ptrString("f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0"),
+				PrincipalID: ptrString("jdoe"),
+				TargetKey:   ptrString("principal:jdoe"),
+			},
+			dynamoOutput: &dynamodb.GetItemOutput{
+				Item: map[string]*dynamodb.AttributeValue{
+					"Id": {
+						S: aws.String("f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0"),
+					},
+					"PrincipalId": {
+						S: aws.String("jdoe"),
+					},
+					"Target": {
+						S: aws.String("principal:jdoe"),
+					},
+				},
+			},
+		},
+		{
+			name:   "should return not found error when missing",
+			target: "group:Interns",
+			dynamoOutput: &dynamodb.GetItemOutput{
+				Item: map[string]*dynamodb.AttributeValue{},
+			},
+			expErr: errors.NewNotFound("budget policy", "group:Interns"),
+		},
+		{
+			name:         "should return internal server error when dynamodb fails",
+			target:       "group:Interns",
+			dynamoErr:    gErrors.New("failure"),
+			dynamoOutput: &dynamodb.GetItemOutput{},
+			expErr:       errors.NewInternalServer("get failed for budget policy \"group:Interns\"", gErrors.New("failure")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamo := awsmocks.DynamoDBAPI{}
+			mockDynamo.On("GetItem", mock.MatchedBy(func(input *dynamodb.GetItemInput) bool {
+				return *input.TableName == "BudgetPolicies" &&
+					*input.Key["Target"].S == tt.target
+			})).Return(tt.dynamoOutput, tt.dynamoErr)
+
+			policyData := &BudgetPolicy{
+				DynamoDB:  &mockDynamo,
+				TableName: "BudgetPolicies",
+			}
+
+			policy, err := policyData.GetByTarget(tt.target)
+			assert.True(t, errors.Is(err, tt.expErr))
+			assert.Equal(t, tt.expPolicy, policy)
+		})
+	}
+}
+
+func TestListBudgetPolicies(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         *budgetpolicy.BudgetPolicy
+		sInput        *dynamodb.ScanInput
+		sOutputRec    *dynamodb.ScanOutput
+		sOutputErr    error
+		qInput        *dynamodb.QueryInput
+		qOutputRec    *dynamodb.QueryOutput
+		expPolicies   *budgetpolicy.BudgetPolicies
+		expNextTarget *string
+		expErr        error
+	}{
+		{
+			name:  "scan all budget policies",
+			query: &budgetpolicy.BudgetPolicy{},
+			sInput: &dynamodb.ScanInput{
+				ConsistentRead: aws.Bool(false),
+				TableName:      aws.String("BudgetPolicies"),
+				Limit:          aws.Int64(5),
+			},
+			sOutputRec: &dynamodb.ScanOutput{
+				Items: []map[string]*dynamodb.AttributeValue{
+					{
+						"Id": {
+							S: aws.String("f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0"),
+						},
+					},
+				},
+				LastEvaluatedKey: map[string]*dynamodb.AttributeValue{
+					"Target": {
+						S: aws.String("group:Interns"),
+					},
+				},
+			},
+			expPolicies: &budgetpolicy.BudgetPolicies{
+				{
+					ID: ptrString("f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0"),
+				},
+			},
+			expNextTarget: ptrString("group:Interns"),
+		},
+		{
+			name: "scan the next page of budget policies",
+			query: &budgetpolicy.BudgetPolicy{
+				NextTarget: ptrString("group:Interns"),
+			},
+			sInput: &dynamodb.ScanInput{
+				ConsistentRead: aws.Bool(false),
+				TableName:      aws.String("BudgetPolicies"),
+				Limit:          aws.Int64(5),
+				ExclusiveStartKey: map[string]*dynamodb.AttributeValue{
+					"Target": {
+						S: aws.String("group:Interns"),
+					},
+				},
+			},
+			sOutputRec: &dynamodb.ScanOutput{
+				Items: []map[string]*dynamodb.AttributeValue{},
+			},
+			expPolicies: &budgetpolicy.BudgetPolicies{},
+		},
+		{
+			name: "query the budget policy of a group",
+			query: &budgetpolicy.BudgetPolicy{
+				Group:     ptrString("Interns"),
+				TargetKey: ptrString("group:Interns"),
+			},
+			qInput: &dynamodb.QueryInput{
+				ConsistentRead:         aws.Bool(false),
+				TableName:              aws.String("BudgetPolicies"),
+				KeyConditionExpression: aws.String("#1 = :1"),
+				FilterExpression:       aws.String("#0 = :0"),
+				ExpressionAttributeNames: map[string]*string{
+					"#0": aws.String("Group"),
+					"#1": aws.String("Target"),
+				},
+				ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+					":0": {
+						S: aws.String("Interns"),
+					},
+					":1": {
+						S: aws.String("group:Interns"),
+					},
+				},
+				Limit: aws.Int64(5),
+			},
+			qOutputRec: &dynamodb.QueryOutput{
+				Items: []map[string]*dynamodb.AttributeValue{
+					{
+						"Id": {
+							S: aws.String("f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0"),
+						},
+						"Group": {
+							S: aws.String("Interns"),
+						},
+					},
+				},
+			},
+			expPolicies: &budgetpolicy.BudgetPolicies{
+				{
+					ID:    ptrString("f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0"),
+					Group: ptrString("Interns"),
+				},
+			},
+		},
+		{
+			name:  "scan failure with internal server error",
+			query: &budgetpolicy.BudgetPolicy{},
+			sInput: &dynamodb.ScanInput{
+				ConsistentRead: aws.Bool(false),
+				TableName:      aws.String("BudgetPolicies"),
+				Limit:          aws.Int64(5),
+			},
+			sOutputErr: fmt.Errorf("failure"),
+			expErr:     errors.NewInternalServer("error getting budget policies", fmt.Errorf("failure")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamo := awsmocks.DynamoDBAPI{}
+			if tt.sInput != nil {
+				mockDynamo.On("Scan", tt.sInput).Return(
+					tt.sOutputRec, tt.sOutputErr,
+				)
+			}
+			if tt.qInput != nil {
+				mockDynamo.On("Query", tt.qInput).Return(
+					tt.qOutputRec, nil,
+				)
+			}
+
+			policyData := &BudgetPolicy{
+				DynamoDB:  &mockDynamo,
+				TableName: "BudgetPolicies",
+				Limit:     5,
+			}
+			policies, err := policyData.List(tt.query)
+			assert.True(t, errors.Is(err, tt.expErr))
+			assert.Equal(t, tt.expPolicies, policies)
+			if tt.expErr == nil {
+				assert.Equal(t, tt.expNextTarget, tt.query.NextTarget)
+			}
+			mockDynamo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWriteBudgetPolicy(t *testing.T) {
+	tests := []struct {
+		name              string
+		policy            budgetpolicy.BudgetPolicy
+		oldLastModifiedOn *int64
+		dynamoErr         error
+		expErr            error
+	}{
+		{
+			name: "create",
+			policy: budgetpolicy.BudgetPolicy{
+				ID:             ptrString("f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0"),
+				PrincipalID:    ptrString("jdoe"),
+				TargetKey:      ptrString("principal:jdoe"),
+				LastModifiedOn: ptrInt64(1573592058),
+			},
+		},
+		{
+			name: "create when the principal already has a policy",
+			policy: budgetpolicy.BudgetPolicy{
+				ID:             ptrString("f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0"),
+				PrincipalID:    ptrString("jdoe"),
+				TargetKey:      ptrString("principal:jdoe"),
+				LastModifiedOn: ptrInt64(1573592058),
+			},
+			dynamoErr: awserr.New("ConditionalCheckFailedException", "Message", fmt.Errorf("Bad")),
+			expErr:    errors.NewAlreadyExists("budget policy", "jdoe"),
+		},
+		{
+			name: "conditional failure",
+			policy: budgetpolicy.BudgetPolicy{
+				ID:             ptrString("f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0"),
+				PrincipalID:    ptrString("jdoe"),
+				TargetKey:      ptrString("principal:jdoe"),
+				LastModifiedOn: ptrInt64(1573592058),
+			},
+			oldLastModifiedOn: ptrInt64(1573592057),
+			dynamoErr:         awserr.New("ConditionalCheckFailedException", "Message", fmt.Errorf("Bad")),
+			expErr: errors.NewConflict(
+				"budget policy",
+				"f3ae1ecc-1bd6-4b48-9d6b-1ef2f3e2f6b0",
+				fmt.Errorf("unable to update budget policy: budget policy has been modified since request was made")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamo := awsmocks.DynamoDBAPI{}
+			mockDynamo.On("PutItem", mock.MatchedBy(func(input *dynamodb.PutItemInput) bool {
+				if tt.oldLastModifiedOn == nil {
+					return *input.TableName == "BudgetPolicies" &&
+						*input.Item["Id"].S == *tt.policy.ID &&
+						*input.Item["Target"].S == *tt.policy.TargetKey &&
+						*input.ConditionExpression == "attribute_not_exists (#0)" &&
+						*input.ExpressionAttributeNames["#0"] == "Target"
+				}
+				return *input.TableName == "BudgetPolicies" &&
+					*input.Item["Id"].S == *tt.policy.ID &&
+					*input.ExpressionAttributeValues[":0"].N == strconv.FormatInt(*tt.oldLastModifiedOn, 10)
+			})).Return(&dynamodb.PutItemOutput{}, tt.dynamoErr)
+
+			policyData := &BudgetPolicy{
+				DynamoDB:  &mockDynamo,
+				TableName: "BudgetPolicies",
+			}
+
+			err := policyData.Write(&tt.policy, tt.oldLastModifiedOn)
+			assert.Truef(t, errors.Is(err, tt.expErr), "actual error %q doesn't match expected error %q", err, tt.expErr)
+		})
+	}
+}
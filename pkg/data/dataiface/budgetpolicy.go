@@ -0,0 +1,25 @@
+//
+
+package dataiface
+
+import (
+	"github.com/Optum/dce/pkg/budgetpolicy"
+)
+
+// BudgetPolicyData makes working with the Budget Policy Data Layer easier
+type BudgetPolicyData interface {
+	// Write the Budget Policy record in DynamoDB
+	// This is an upsert operation in which the record will either
+	// be inserted or updated
+	// prevLastModifiedOn parameter is the original lastModifiedOn
+	Write(policy *budgetpolicy.BudgetPolicy, prevLastModifiedOn *int64) error
+	// Delete the Budget Policy record in DynamoDB
+	Delete(policy *budgetpolicy.BudgetPolicy) error
+	// Get the Budget Policy record by ID
+	Get(ID string) (*budgetpolicy.BudgetPolicy, error)
+	// GetByTarget gets the Budget Policy record of a principal or group,
+	// eg. "principal:jdoe" or "group:Interns"
+	GetByTarget(target string) (*budgetpolicy.BudgetPolicy, error)
+	// List Get a list of budget policies
+	List(query *budgetpolicy.BudgetPolicy) (*budgetpolicy.BudgetPolicies, error)
+}
@@ -19,4 +19,13 @@ type LeaseData interface {
 	// be inserted or updated
 	// prevLastModifiedOn parameter is the original lastModifiedOn
 	Write(lease *lease.Lease, prevLastModifiedOn *int64) error
+	// Create writes a new Lease record and transitions its account from
+	// Ready to Leased, in a single transaction
+	Create(lease *lease.Lease) error
+	// Promote replaces a Pending lease with an Active lease on a newly
+	// assigned account, in a single transaction
+	Promote(pending *lease.Lease, lease *lease.Lease) error
+	// Rollback undoes a Create, marking the Lease record as Inactive and
+	// returning its account to Ready, in a single transaction
+	Rollback(lease *lease.Lease) error
 }
@@ -10,6 +10,20 @@ type LeaseData struct {
 	mock.Mock
 }
 
+// Create provides a mock function with given fields: _a0
+func (_m *LeaseData) Create(_a0 *lease.Lease) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*lease.Lease) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Get provides a mock function with given fields: ID
 func (_m *LeaseData) Get(ID string) (*lease.Lease, error) {
 	ret := _m.Called(ID)
@@ -56,6 +70,34 @@ func (_m *LeaseData) List(query *lease.Lease) (*lease.Leases, error) {
 	return r0, r1
 }
 
+// Promote provides a mock function with given fields: pending, input
+func (_m *LeaseData) Promote(pending *lease.Lease, input *lease.Lease) error {
+	ret := _m.Called(pending, input)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*lease.Lease, *lease.Lease) error); ok {
+		r0 = rf(pending, input)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Rollback provides a mock function with given fields: _a0
+func (_m *LeaseData) Rollback(_a0 *lease.Lease) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*lease.Lease) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Write provides a mock function with given fields: _a0, prevLastModifiedOn
 func (_m *LeaseData) Write(_a0 *lease.Lease, prevLastModifiedOn *int64) error {
 	ret := _m.Called(_a0, prevLastModifiedOn)
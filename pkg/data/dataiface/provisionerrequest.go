@@ -0,0 +1,17 @@
+//
+
+package dataiface
+
+import (
+	"github.com/Optum/dce/pkg/provisioner"
+)
+
+// ProvisionerRequestData makes working with the Provisioner Request Data Layer easier
+type ProvisionerRequestData interface {
+	// Write the provisioner request record in DynamoDB
+	// This is an upsert operation in which the record will either
+	// be inserted or updated
+	Write(request *provisioner.Request) error
+	// List Get every provisioner request
+	List() (*provisioner.Requests, error)
+}
@@ -0,0 +1,18 @@
+//
+
+package dataiface
+
+import (
+	"github.com/Optum/dce/pkg/resetrun"
+)
+
+// ResetRunData makes working with the Reset Run Data Layer easier
+type ResetRunData interface {
+	// Write the Reset Run record in DynamoDB
+	// This is an upsert operation in which the record will either
+	// be inserted or updated
+	// prevLastModifiedOn parameter is the original lastModifiedOn
+	Write(run *resetrun.ResetRun, prevLastModifiedOn *int64) error
+	// List Get a list of the reset runs of an account, most recent first
+	List(query *resetrun.ResetRun) (*resetrun.ResetRuns, error)
+}
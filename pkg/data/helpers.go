@@ -4,6 +4,7 @@ import (
 	"reflect"
 	"strings"
 
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
 	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
@@ -65,3 +66,22 @@ func getItem(input *dynamodb.GetItemInput, dataInterface dynamodbiface.DynamoDBA
 	output, err := dataInterface.GetItem(input)
 	return output, err
 }
+
+// transactionCancellationReason returns the reason code why the item at index i
+// of a cancelled transaction failed, eg. "ConditionalCheckFailed" or "None".
+// DynamoDB lists the reasons of each item in the error message, in the order of the items:
+// "Transaction cancelled, please refer cancellation reasons for specific reasons [None, ConditionalCheckFailed]"
+// Returns an empty string if the reason isn't known.
+func transactionCancellationReason(err awserr.Error, i int) string {
+	msg := err.Message()
+	start := strings.LastIndex(msg, "[")
+	end := strings.LastIndex(msg, "]")
+	if start < 0 || end < start {
+		return ""
+	}
+	reasons := strings.Split(msg[start+1:end], ",")
+	if i < 0 || i >= len(reasons) {
+		return ""
+	}
+	return strings.TrimSpace(reasons[i])
+}
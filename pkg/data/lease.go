@@ -3,6 +3,7 @@ package data
 import (
 	"fmt"
 
+	"github.com/Optum/dce/pkg/account"
 	"github.com/Optum/dce/pkg/errors"
 	"github.com/Optum/dce/pkg/lease"
 	"github.com/aws/aws-sdk-go/aws"
@@ -15,10 +16,11 @@ import (
 
 // Lease - Data Layer Struct
 type Lease struct {
-	DynamoDB       dynamodbiface.DynamoDBAPI
-	TableName      string `env:"LEASE_DB"`
-	ConsistentRead bool   `env:"USE_CONSISTENT_READS" envDefault:"false"`
-	Limit          int64  `env:"LIMIT" envDefault:"25"`
+	DynamoDB         dynamodbiface.DynamoDBAPI
+	TableName        string `env:"LEASE_DB"`
+	AccountTableName string `env:"ACCOUNT_DB"`
+	ConsistentRead   bool   `env:"USE_CONSISTENT_READS" envDefault:"false"`
+	Limit            int64  `env:"LIMIT" envDefault:"25"`
 }
 
 // Write the Lease record in DynamoDB
@@ -75,6 +77,280 @@ func (a *Lease) Write(lease *lease.Lease, prevLastModifiedOn *int64) error {
 
 }
 
+// Create writes a new Lease record and transitions its account from
+// Ready to Leased, in a single transaction.
+// Returns a conflict error if the account is no longer Ready, or the principal
+// already has a lease with the same status on the account.
+func (a *Lease) Create(input *lease.Lease) error {
+	putMap, _ := dynamodbattribute.Marshal(input)
+
+	leaseCond := expression.Name("LeaseStatus").AttributeNotExists().
+		Or(expression.Name("LeaseStatus").NotEqual(expression.Value(input.Status.String())))
+	leaseExpr, err := expression.NewBuilder().WithCondition(leaseCond).Build()
+	if err != nil {
+		return errors.NewInternalServer("error building query", err)
+	}
+
+	accountExpr, err := expression.NewBuilder().
+		WithCondition(expression.Name("AccountStatus").Equal(expression.Value(account.StatusReady.String()))).
+		WithUpdate(expression.
+			Set(expression.Name("AccountStatus"), expression.Value(account.StatusLeased.String())).
+			Set(expression.Name("LastModifiedOn"), expression.Value(input.LastModifiedOn))).
+		Build()
+	if err != nil {
+		return errors.NewInternalServer("error building query", err)
+	}
+
+	items := []*dynamodb.TransactWriteItem{
+		{
+			Put: &dynamodb.Put{
+				TableName:                 aws.String(a.TableName),
+				Item:                      putMap.M,
+				ConditionExpression:       leaseExpr.Condition(),
+				ExpressionAttributeNames:  leaseExpr.Names(),
+				ExpressionAttributeValues: leaseExpr.Values(),
+			},
+		},
+	}
+	// Pending and Scheduled leases don't have an account to claim yet
+	if *input.Status == lease.StatusActive {
+		items = append([]*dynamodb.TransactWriteItem{
+			{
+				Update: &dynamodb.Update{
+					TableName: aws.String(a.AccountTableName),
+					Key: map[string]*dynamodb.AttributeValue{
+						"Id": {
+							S: input.AccountID,
+						},
+					},
+					ConditionExpression:       accountExpr.Condition(),
+					UpdateExpression:          accountExpr.Update(),
+					ExpressionAttributeNames:  accountExpr.Names(),
+					ExpressionAttributeValues: accountExpr.Values(),
+				},
+			},
+		}, items...)
+	}
+
+	_, err = a.DynamoDB.TransactWriteItems(&dynamodb.TransactWriteItemsInput{
+		TransactItems: items,
+	})
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) {
+		if awsErr.Code() == dynamodb.ErrCodeTransactionCanceledException {
+			// The lease is the last item, after the account claim of Active leases
+			leaseReason := transactionCancellationReason(awsErr, len(items)-1)
+			accountReason := ""
+			if len(items) > 1 {
+				accountReason = transactionCancellationReason(awsErr, 0)
+			}
+			return errors.NewConflict(
+				"lease",
+				*input.AccountID,
+				createConflictCause(input, accountReason, leaseReason, awsErr))
+		}
+	}
+	if err != nil {
+		return errors.NewInternalServer(
+			fmt.Sprintf("create failed for lease with AccountID %q and PrincipalID %q", *input.AccountID, *input.PrincipalID),
+			err,
+		)
+	}
+
+	return nil
+}
+
+// createConflictCause explains why the transaction of a Create was cancelled,
+// from the cancellation reasons of the account claim and of the lease
+func createConflictCause(input *lease.Lease, accountReason string, leaseReason string, awsErr awserr.Error) error {
+	switch {
+	case accountReason == "ConditionalCheckFailed":
+		return fmt.Errorf("unable to create lease: account is no longer available")
+	case leaseReason == "ConditionalCheckFailed":
+		return fmt.Errorf("unable to create lease: principal %q already has a lease with status %s for account %q",
+			*input.PrincipalID, input.Status.String(), *input.AccountID)
+	case accountReason == "TransactionConflict" || leaseReason == "TransactionConflict":
+		return fmt.Errorf("unable to create lease: account or lease is being modified by another request")
+	}
+	return fmt.Errorf("unable to create lease: %s", awsErr.Message())
+}
+
+// Promote replaces a Pending or Scheduled lease with an Active lease on a newly
+// assigned account, and transitions the account from Ready to Leased, in a single transaction.
+// Returns a conflict error if the account is no longer Ready, or the Pending
+// or Scheduled lease was already promoted.
+func (a *Lease) Promote(pending *lease.Lease, input *lease.Lease) error {
+	putMap, _ := dynamodbattribute.Marshal(input)
+
+	pendingExpr, err := expression.NewBuilder().
+		WithCondition(expression.Name("LeaseStatus").Equal(expression.Value(pending.Status.String()))).
+		Build()
+	if err != nil {
+		return errors.NewInternalServer("error building query", err)
+	}
+
+	leaseCond := expression.Name("LeaseStatus").AttributeNotExists().
+		Or(expression.Name("LeaseStatus").NotEqual(expression.Value(input.Status.String())))
+	leaseExpr, err := expression.NewBuilder().WithCondition(leaseCond).Build()
+	if err != nil {
+		return errors.NewInternalServer("error building query", err)
+	}
+
+	accountExpr, err := expression.NewBuilder().
+		WithCondition(expression.Name("AccountStatus").Equal(expression.Value(account.StatusReady.String()))).
+		WithUpdate(expression.
+			Set(expression.Name("AccountStatus"), expression.Value(account.StatusLeased.String())).
+			Set(expression.Name("LastModifiedOn"), expression.Value(input.LastModifiedOn))).
+		Build()
+	if err != nil {
+		return errors.NewInternalServer("error building query", err)
+	}
+
+	_, err = a.DynamoDB.TransactWriteItems(&dynamodb.TransactWriteItemsInput{
+		TransactItems: []*dynamodb.TransactWriteItem{
+			{
+				Update: &dynamodb.Update{
+					TableName: aws.String(a.AccountTableName),
+					Key: map[string]*dynamodb.AttributeValue{
+						"Id": {
+							S: input.AccountID,
+						},
+					},
+					ConditionExpression:       accountExpr.Condition(),
+					UpdateExpression:          accountExpr.Update(),
+					ExpressionAttributeNames:  accountExpr.Names(),
+					ExpressionAttributeValues: accountExpr.Values(),
+				},
+			},
+			{
+				Delete: &dynamodb.Delete{
+					TableName: aws.String(a.TableName),
+					Key: map[string]*dynamodb.AttributeValue{
+						"AccountId": {
+							S: pending.AccountID,
+						},
+						"PrincipalId": {
+							S: pending.PrincipalID,
+						},
+					},
+					ConditionExpression:       pendingExpr.Condition(),
+					ExpressionAttributeNames:  pendingExpr.Names(),
+					ExpressionAttributeValues: pendingExpr.Values(),
+				},
+			},
+			{
+				Put: &dynamodb.Put{
+					TableName:                 aws.String(a.TableName),
+					Item:                      putMap.M,
+					ConditionExpression:       leaseExpr.Condition(),
+					ExpressionAttributeNames:  leaseExpr.Names(),
+					ExpressionAttributeValues: leaseExpr.Values(),
+				},
+			},
+		},
+	})
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) {
+		if awsErr.Code() == dynamodb.ErrCodeTransactionCanceledException {
+			return errors.NewConflict(
+				"lease",
+				*pending.ID,
+				promoteConflictCause(pending, input, awsErr))
+		}
+	}
+	if err != nil {
+		return errors.NewInternalServer(
+			fmt.Sprintf("promote failed for lease %q", *pending.ID),
+			err,
+		)
+	}
+
+	return nil
+}
+
+// promoteConflictCause explains why the transaction of a Promote was cancelled,
+// from the cancellation reasons of the account claim, of the delete of the
+// waiting lease, and of the put of the Active lease
+func promoteConflictCause(pending *lease.Lease, input *lease.Lease, awsErr awserr.Error) error {
+	switch {
+	case transactionCancellationReason(awsErr, 0) == "ConditionalCheckFailed":
+		return fmt.Errorf("unable to promote lease: account %q is no longer available", *input.AccountID)
+	case transactionCancellationReason(awsErr, 1) == "ConditionalCheckFailed":
+		return fmt.Errorf("unable to promote lease: lease is no longer %s", pending.Status.String())
+	case transactionCancellationReason(awsErr, 2) == "ConditionalCheckFailed":
+		return fmt.Errorf("unable to promote lease: principal %q already has a lease with status %s for account %q",
+			*input.PrincipalID, input.Status.String(), *input.AccountID)
+	}
+	return fmt.Errorf("unable to promote lease: %s", awsErr.Message())
+}
+
+// Rollback undoes a Create, marking the Lease record as Inactive and
+// returning its account from Leased to Ready, in a single transaction.
+func (a *Lease) Rollback(input *lease.Lease) error {
+	leaseExpr, err := expression.NewBuilder().
+		WithCondition(expression.Name("Id").Equal(expression.Value(input.ID))).
+		WithUpdate(expression.
+			Set(expression.Name("LeaseStatus"), expression.Value(lease.StatusInactive.String())).
+			Set(expression.Name("LeaseStatusReason"), expression.Value(string(lease.StatusReasonRolledBack)))).
+		Build()
+	if err != nil {
+		return errors.NewInternalServer("error building query", err)
+	}
+
+	accountExpr, err := expression.NewBuilder().
+		WithCondition(expression.Name("AccountStatus").Equal(expression.Value(account.StatusLeased.String()))).
+		WithUpdate(expression.
+			Set(expression.Name("AccountStatus"), expression.Value(account.StatusReady.String()))).
+		Build()
+	if err != nil {
+		return errors.NewInternalServer("error building query", err)
+	}
+
+	_, err = a.DynamoDB.TransactWriteItems(&dynamodb.TransactWriteItemsInput{
+		TransactItems: []*dynamodb.TransactWriteItem{
+			{
+				Update: &dynamodb.Update{
+					TableName: aws.String(a.TableName),
+					Key: map[string]*dynamodb.AttributeValue{
+						"AccountId": {
+							S: input.AccountID,
+						},
+						"PrincipalId": {
+							S: input.PrincipalID,
+						},
+					},
+					ConditionExpression:       leaseExpr.Condition(),
+					UpdateExpression:          leaseExpr.Update(),
+					ExpressionAttributeNames:  leaseExpr.Names(),
+					ExpressionAttributeValues: leaseExpr.Values(),
+				},
+			},
+			{
+				Update: &dynamodb.Update{
+					TableName: aws.String(a.AccountTableName),
+					Key: map[string]*dynamodb.AttributeValue{
+						"Id": {
+							S: input.AccountID,
+						},
+					},
+					ConditionExpression:       accountExpr.Condition(),
+					UpdateExpression:          accountExpr.Update(),
+					ExpressionAttributeNames:  accountExpr.Names(),
+					ExpressionAttributeValues: accountExpr.Values(),
+				},
+			},
+		},
+	})
+	if err != nil {
+		return errors.NewInternalServer(
+			fmt.Sprintf("rollback failed for lease %q", *input.ID),
+			err,
+		)
+	}
+
+	return nil
+}
+
 // GetByAccountIDAndPrincipalID gets the Lease record by AccountID and PrincipalID
 func (a *Lease) GetByAccountIDAndPrincipalID(accountID string, principalID string) (*lease.Lease, error) {
 
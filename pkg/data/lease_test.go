@@ -199,6 +199,272 @@ func TestLeaseUpdate(t *testing.T) {
 
 }
 
+func TestLeaseCreate(t *testing.T) {
+	tests := []struct {
+		name        string
+		lease       *lease.Lease
+		dynamoErr   error
+		expectedErr error
+	}{
+		{
+			name: "should claim the account and write the lease",
+			lease: &lease.Lease{
+				ID:             ptrString("70c2d96d-7938-4ec9-917d-476f2b09cc04"),
+				AccountID:      ptrString("123456789012"),
+				PrincipalID:    ptrString("User1"),
+				Status:         lease.StatusActive.StatusPtr(),
+				LastModifiedOn: ptrInt64(1573592058),
+			},
+		},
+		{
+			name: "should return a conflict when the transaction is cancelled",
+			lease: &lease.Lease{
+				ID:             ptrString("70c2d96d-7938-4ec9-917d-476f2b09cc04"),
+				AccountID:      ptrString("123456789012"),
+				PrincipalID:    ptrString("User1"),
+				Status:         lease.StatusActive.StatusPtr(),
+				LastModifiedOn: ptrInt64(1573592058),
+			},
+			dynamoErr: awserr.New("TransactionCanceledException", "Transaction cancelled, please refer cancellation reasons for specific reasons [ConditionalCheckFailed, None]", fmt.Errorf("Bad")),
+			expectedErr: errors.NewConflict(
+				"lease",
+				"123456789012",
+				fmt.Errorf("unable to create lease: account is no longer available")),
+		},
+		{
+			name: "should return a conflict when the principal already has the lease",
+			lease: &lease.Lease{
+				ID:             ptrString("70c2d96d-7938-4ec9-917d-476f2b09cc04"),
+				AccountID:      ptrString("123456789012"),
+				PrincipalID:    ptrString("User1"),
+				Status:         lease.StatusActive.StatusPtr(),
+				LastModifiedOn: ptrInt64(1573592058),
+			},
+			dynamoErr: awserr.New("TransactionCanceledException", "Transaction cancelled, please refer cancellation reasons for specific reasons [None, ConditionalCheckFailed]", fmt.Errorf("Bad")),
+			expectedErr: errors.NewConflict(
+				"lease",
+				"123456789012",
+				fmt.Errorf("unable to create lease: principal \"User1\" already has a lease with status Active for account \"123456789012\"")),
+		},
+		{
+			name: "should return a conflict with the message when the reason is unknown",
+			lease: &lease.Lease{
+				ID:             ptrString("70c2d96d-7938-4ec9-917d-476f2b09cc04"),
+				AccountID:      ptrString("123456789012"),
+				PrincipalID:    ptrString("User1"),
+				Status:         lease.StatusActive.StatusPtr(),
+				LastModifiedOn: ptrInt64(1573592058),
+			},
+			dynamoErr: awserr.New("TransactionCanceledException", "Message", fmt.Errorf("Bad")),
+			expectedErr: errors.NewConflict(
+				"lease",
+				"123456789012",
+				fmt.Errorf("unable to create lease: Message")),
+		},
+		{
+			name: "other dynamo error",
+			lease: &lease.Lease{
+				ID:             ptrString("70c2d96d-7938-4ec9-917d-476f2b09cc04"),
+				AccountID:      ptrString("123456789012"),
+				PrincipalID:    ptrString("User2"),
+				Status:         lease.StatusActive.StatusPtr(),
+				LastModifiedOn: ptrInt64(1573592058),
+			},
+			dynamoErr:   gErrors.New("failure"),
+			expectedErr: errors.NewInternalServer("create failed for lease with AccountID \"123456789012\" and PrincipalID \"User2\"", gErrors.New("failure")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamo := awsmocks.DynamoDBAPI{}
+
+			mockDynamo.On("TransactWriteItems", mock.MatchedBy(func(input *dynamodb.TransactWriteItemsInput) bool {
+				accountUpdate := input.TransactItems[0].Update
+				leasePut := input.TransactItems[1].Put
+				return (len(input.TransactItems) == 2 &&
+					*accountUpdate.TableName == "Accounts" &&
+					*accountUpdate.Key["Id"].S == *tt.lease.AccountID &&
+					*accountUpdate.ExpressionAttributeValues[":0"].S == "Ready" &&
+					*leasePut.TableName == "Leases" &&
+					*leasePut.Item["AccountId"].S == *tt.lease.AccountID &&
+					*leasePut.Item["PrincipalId"].S == *tt.lease.PrincipalID &&
+					*leasePut.Item["LeaseStatus"].S == "Active")
+			})).Return(
+				&dynamodb.TransactWriteItemsOutput{}, tt.dynamoErr,
+			)
+			leaseData := &Lease{
+				DynamoDB:         &mockDynamo,
+				TableName:        "Leases",
+				AccountTableName: "Accounts",
+			}
+
+			err := leaseData.Create(tt.lease)
+			assert.Truef(t, errors.Is(err, tt.expectedErr), "actual error %q doesn't match expected error %q", err, tt.expectedErr)
+			mockDynamo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLeaseCreatePending(t *testing.T) {
+	mockDynamo := awsmocks.DynamoDBAPI{}
+
+	// Should only write the lease, with no account to claim
+	mockDynamo.On("TransactWriteItems", mock.MatchedBy(func(input *dynamodb.TransactWriteItemsInput) bool {
+		leasePut := input.TransactItems[0].Put
+		return (len(input.TransactItems) == 1 &&
+			*leasePut.TableName == "Leases" &&
+			*leasePut.Item["AccountId"].S == lease.PendingAccountID("70c2d96d-7938-4ec9-917d-476f2b09cc04") &&
+			*leasePut.Item["LeaseStatus"].S == "Pending" &&
+			*leasePut.ExpressionAttributeValues[":0"].S == "Pending")
+	})).Return(
+		&dynamodb.TransactWriteItemsOutput{}, nil,
+	)
+	leaseData := &Lease{
+		DynamoDB:         &mockDynamo,
+		TableName:        "Leases",
+		AccountTableName: "Accounts",
+	}
+
+	err := leaseData.Create(&lease.Lease{
+		ID:             ptrString("70c2d96d-7938-4ec9-917d-476f2b09cc04"),
+		AccountID:      ptrString(lease.PendingAccountID("70c2d96d-7938-4ec9-917d-476f2b09cc04")),
+		PrincipalID:    ptrString("User1"),
+		Status:         lease.StatusPending.StatusPtr(),
+		LastModifiedOn: ptrInt64(1573592058),
+	})
+	assert.Nil(t, err)
+	mockDynamo.AssertExpectations(t)
+}
+
+func TestLeasePromote(t *testing.T) {
+	tests := []struct {
+		name        string
+		dynamoErr   error
+		expectedErr error
+	}{
+		{
+			name: "should claim the account and replace the pending lease",
+		},
+		{
+			name:      "should return a conflict when the account is no longer available",
+			dynamoErr: awserr.New("TransactionCanceledException", "Transaction cancelled, please refer cancellation reasons for specific reasons [ConditionalCheckFailed, None, None]", fmt.Errorf("Bad")),
+			expectedErr: errors.NewConflict(
+				"lease",
+				"70c2d96d-7938-4ec9-917d-476f2b09cc04",
+				fmt.Errorf("unable to promote lease: account \"123456789012\" is no longer available")),
+		},
+		{
+			name:      "should return a conflict when the lease is no longer pending",
+			dynamoErr: awserr.New("TransactionCanceledException", "Transaction cancelled, please refer cancellation reasons for specific reasons [None, ConditionalCheckFailed, None]", fmt.Errorf("Bad")),
+			expectedErr: errors.NewConflict(
+				"lease",
+				"70c2d96d-7938-4ec9-917d-476f2b09cc04",
+				fmt.Errorf("unable to promote lease: lease is no longer Pending")),
+		},
+		{
+			name:        "other dynamo error",
+			dynamoErr:   gErrors.New("failure"),
+			expectedErr: errors.NewInternalServer("promote failed for lease \"70c2d96d-7938-4ec9-917d-476f2b09cc04\"", gErrors.New("failure")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamo := awsmocks.DynamoDBAPI{}
+
+			mockDynamo.On("TransactWriteItems", mock.MatchedBy(func(input *dynamodb.TransactWriteItemsInput) bool {
+				accountUpdate := input.TransactItems[0].Update
+				pendingDelete := input.TransactItems[1].Delete
+				leasePut := input.TransactItems[2].Put
+				return (len(input.TransactItems) == 3 &&
+					*accountUpdate.TableName == "Accounts" &&
+					*accountUpdate.Key["Id"].S == "123456789012" &&
+					*pendingDelete.TableName == "Leases" &&
+					*pendingDelete.Key["AccountId"].S == lease.PendingAccountID("70c2d96d-7938-4ec9-917d-476f2b09cc04") &&
+					*pendingDelete.Key["PrincipalId"].S == "User1" &&
+					*leasePut.TableName == "Leases" &&
+					*leasePut.Item["AccountId"].S == "123456789012" &&
+					*leasePut.Item["LeaseStatus"].S == "Active")
+			})).Return(
+				&dynamodb.TransactWriteItemsOutput{}, tt.dynamoErr,
+			)
+			leaseData := &Lease{
+				DynamoDB:         &mockDynamo,
+				TableName:        "Leases",
+				AccountTableName: "Accounts",
+			}
+
+			err := leaseData.Promote(
+				&lease.Lease{
+					ID:          ptrString("70c2d96d-7938-4ec9-917d-476f2b09cc04"),
+					AccountID:   ptrString(lease.PendingAccountID("70c2d96d-7938-4ec9-917d-476f2b09cc04")),
+					PrincipalID: ptrString("User1"),
+					Status:      lease.StatusPending.StatusPtr(),
+				},
+				&lease.Lease{
+					ID:             ptrString("70c2d96d-7938-4ec9-917d-476f2b09cc04"),
+					AccountID:      ptrString("123456789012"),
+					PrincipalID:    ptrString("User1"),
+					Status:         lease.StatusActive.StatusPtr(),
+					LastModifiedOn: ptrInt64(1573592058),
+				},
+			)
+			assert.Truef(t, errors.Is(err, tt.expectedErr), "actual error %q doesn't match expected error %q", err, tt.expectedErr)
+			mockDynamo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLeaseRollback(t *testing.T) {
+	tests := []struct {
+		name        string
+		dynamoErr   error
+		expectedErr error
+	}{
+		{
+			name: "should deactivate the lease and release the account",
+		},
+		{
+			name:        "should return an error when the transaction fails",
+			dynamoErr:   gErrors.New("failure"),
+			expectedErr: errors.NewInternalServer("rollback failed for lease \"70c2d96d-7938-4ec9-917d-476f2b09cc04\"", gErrors.New("failure")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamo := awsmocks.DynamoDBAPI{}
+
+			mockDynamo.On("TransactWriteItems", mock.MatchedBy(func(input *dynamodb.TransactWriteItemsInput) bool {
+				leaseUpdate := input.TransactItems[0].Update
+				accountUpdate := input.TransactItems[1].Update
+				return (len(input.TransactItems) == 2 &&
+					*leaseUpdate.TableName == "Leases" &&
+					*leaseUpdate.Key["AccountId"].S == "123456789012" &&
+					*leaseUpdate.Key["PrincipalId"].S == "User1" &&
+					*accountUpdate.TableName == "Accounts" &&
+					*accountUpdate.Key["Id"].S == "123456789012")
+			})).Return(
+				&dynamodb.TransactWriteItemsOutput{}, tt.dynamoErr,
+			)
+			leaseData := &Lease{
+				DynamoDB:         &mockDynamo,
+				TableName:        "Leases",
+				AccountTableName: "Accounts",
+			}
+
+			err := leaseData.Rollback(&lease.Lease{
+				ID:          ptrString("70c2d96d-7938-4ec9-917d-476f2b09cc04"),
+				AccountID:   ptrString("123456789012"),
+				PrincipalID: ptrString("User1"),
+			})
+			assert.Truef(t, errors.Is(err, tt.expectedErr), "actual error %q doesn't match expected error %q", err, tt.expectedErr)
+			mockDynamo.AssertExpectations(t)
+		})
+	}
+}
+
 func TestGetLeaseByID(t *testing.T) {
 	tests := []struct {
 		name          string
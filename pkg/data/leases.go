@@ -10,8 +10,9 @@ import (
 	"strings"
 )
 
-// queryLeases for doing a query against dynamodb
-func (a *Lease) queryLeases(query *lease.Lease, keyName string, index string) (*queryScanOutput, error) {
+// queryLeases for doing a query against dynamodb.
+// The table is queried when index is empty.
+func (a *Lease) queryLeases(query *lease.Lease, keyName string, keyValue *string, index string) (*queryScanOutput, error) {
 	var expr expression.Expression
 	var bldr expression.Builder
 	var err error
@@ -30,7 +31,6 @@ func (a *Lease) queryLeases(query *lease.Lease, keyName string, index string) (*
 
 	queryInput := &dynamodb.QueryInput{
 		TableName:                 aws.String(a.TableName),
-		IndexName:                 aws.String(index),
 		KeyConditionExpression:    expr.KeyCondition(),
 		ConsistentRead:            aws.Bool(a.ConsistentRead),
 		FilterExpression:          expr.Filter(),
@@ -38,17 +38,27 @@ func (a *Lease) queryLeases(query *lease.Lease, keyName string, index string) (*
 		ExpressionAttributeValues: expr.Values(),
 	}
 
+	if index != "" {
+		queryInput.SetIndexName(index)
+	}
+
 	queryInput.SetLimit(*query.Limit)
 	if query.NextAccountID != nil && query.NextPrincipalID != nil {
-		// Should be more dynamic
-		queryInput.SetExclusiveStartKey(map[string]*dynamodb.AttributeValue{
+		// Index queries need the index key to start from, as well as the table key
+		startKey := map[string]*dynamodb.AttributeValue{
 			"AccountId": &dynamodb.AttributeValue{
 				S: query.NextAccountID,
 			},
 			"PrincipalId": &dynamodb.AttributeValue{
 				S: query.NextPrincipalID,
 			},
-		})
+		}
+		if keyValue != nil {
+			startKey[keyName] = &dynamodb.AttributeValue{
+				S: keyValue,
+			}
+		}
+		queryInput.SetExclusiveStartKey(startKey)
 	}
 
 	res, err = a.DynamoDB.Query(queryInput)
@@ -123,11 +133,13 @@ func (a *Lease) List(query *lease.Lease) (*lease.Leases, error) {
 	}
 
 	if query.ID != nil {
-		outputs, err = a.queryLeases(query, "Id", "LeaseId")
+		outputs, err = a.queryLeases(query, "Id", query.ID, "LeaseId")
 	} else if query.PrincipalID != nil {
-		outputs, err = a.queryLeases(query, "PrincipalId", "PrincipalId")
+		outputs, err = a.queryLeases(query, "PrincipalId", query.PrincipalID, "PrincipalId")
+	} else if query.AccountID != nil {
+		outputs, err = a.queryLeases(query, "AccountId", query.AccountID, "")
 	} else if query.Status != nil {
-		outputs, err = a.queryLeases(query, "LeaseStatus", "LeaseStatus")
+		outputs, err = a.queryLeases(query, "LeaseStatus", query.Status.StringPtr(), "LeaseStatus")
 	} else {
 		outputs, err = a.scanLeases(query)
 	}
@@ -62,44 +62,6 @@ func TestGetLeasesScan(t *testing.T) {
 				},
 			},
 		},
-		{
-			name: "scan get all leases with accountId",
-			query: &lease.Lease{
-				AccountID: ptrString("1"),
-			},
-			sInput: &dynamodb.ScanInput{
-				ConsistentRead:   aws.Bool(false),
-				TableName:        aws.String("Leases"),
-				FilterExpression: aws.String("#0 = :0"),
-				ExpressionAttributeNames: map[string]*string{
-					"#0": aws.String("AccountId"),
-				},
-				ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
-					":0": {
-						S: aws.String("1"),
-					},
-				},
-				Limit: ptrInt64(25),
-			},
-			sOutputRec: &dynamodb.ScanOutput{
-				Items: []map[string]*dynamodb.AttributeValue{
-					map[string]*dynamodb.AttributeValue{
-						"AccountId": {
-							S: aws.String("1"),
-						},
-						"PrincipalId": {
-							S: aws.String("User1"),
-						},
-					},
-				},
-			},
-			expLeases: &lease.Leases{
-				{
-					AccountID:   ptrString("1"),
-					PrincipalID: ptrString("User1"),
-				},
-			},
-		},
 		{
 			name:  "scan failure with internal server error",
 			query: &lease.Lease{},
@@ -187,6 +149,102 @@ func TestGetLeasesQuery(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "query the next page of leases by status",
+			query: &lease.Lease{
+				Status:          lease.StatusActive.StatusPtr(),
+				NextAccountID:   aws.String("1"),
+				NextPrincipalID: aws.String("User1"),
+			},
+			qInput: &dynamodb.QueryInput{
+				ConsistentRead: aws.Bool(false),
+				TableName:      aws.String("Leases"),
+				IndexName:      aws.String("LeaseStatus"),
+				ExpressionAttributeNames: map[string]*string{
+					"#0": aws.String("LeaseStatus"),
+				},
+				ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+					":0": {
+						S: aws.String("Active"),
+					},
+				},
+				KeyConditionExpression: aws.String("#0 = :0"),
+				Limit:                  ptrInt64(25),
+				ExclusiveStartKey: map[string]*dynamodb.AttributeValue{
+					"AccountId": {
+						S: aws.String("1"),
+					},
+					"PrincipalId": {
+						S: aws.String("User1"),
+					},
+					"LeaseStatus": {
+						S: aws.String("Active"),
+					},
+				},
+			},
+			qOutputRec: &dynamodb.QueryOutput{
+				Items: []map[string]*dynamodb.AttributeValue{
+					map[string]*dynamodb.AttributeValue{
+						"AccountId": {
+							S: aws.String("2"),
+						},
+						"PrincipalId": {
+							S: aws.String("User2"),
+						},
+					},
+				},
+			},
+			expLeases: &lease.Leases{
+				{
+					AccountID:   ptrString("2"),
+					PrincipalID: ptrString("User2"),
+				},
+			},
+		},
+		{
+			name: "query leases of an account by the table key",
+			query: &lease.Lease{
+				AccountID: ptrString("1"),
+				Status:    lease.StatusActive.StatusPtr(),
+			},
+			qInput: &dynamodb.QueryInput{
+				ConsistentRead: aws.Bool(false),
+				TableName:      aws.String("Leases"),
+				ExpressionAttributeNames: map[string]*string{
+					"#0": aws.String("LeaseStatus"),
+					"#1": aws.String("AccountId"),
+				},
+				ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+					":0": {
+						S: aws.String("Active"),
+					},
+					":1": {
+						S: aws.String("1"),
+					},
+				},
+				KeyConditionExpression: aws.String("#1 = :1"),
+				FilterExpression:       aws.String("#0 = :0"),
+				Limit:                  ptrInt64(25),
+			},
+			qOutputRec: &dynamodb.QueryOutput{
+				Items: []map[string]*dynamodb.AttributeValue{
+					map[string]*dynamodb.AttributeValue{
+						"AccountId": {
+							S: aws.String("1"),
+						},
+						"PrincipalId": {
+							S: aws.String("User1"),
+						},
+					},
+				},
+			},
+			expLeases: &lease.Leases{
+				{
+					AccountID:   ptrString("1"),
+					PrincipalID: ptrString("User1"),
+				},
+			},
+		},
 		{
 			name: "query all leases by status with filter",
 			query: &lease.Lease{
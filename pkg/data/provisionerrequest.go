@@ -0,0 +1,71 @@
+package data
+
+import (
+	"fmt"
+
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/provisioner"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+)
+
+// ProvisionerRequest - Data Layer Struct
+type ProvisionerRequest struct {
+	DynamoDB       dynamodbiface.DynamoDBAPI
+	TableName      string `env:"PROVISIONER_REQUEST_DB"`
+	ConsistentRead bool   `env:"USE_CONSISTENT_READS" envDefault:"false"`
+}
+
+// Write the provisioner request record in DynamoDB
+// This is an upsert operation in which the record will either
+// be inserted or updated
+func (a *ProvisionerRequest) Write(request *provisioner.Request) error {
+	putMap, _ := dynamodbattribute.Marshal(request)
+	input := &dynamodb.PutItemInput{
+		TableName: aws.String(a.TableName),
+		Item:      putMap.M,
+	}
+	err := putItem(input, a.DynamoDB)
+	if err != nil {
+		return errors.NewInternalServer(
+			fmt.Sprintf("update failed for provisioner request %q", *request.ID),
+			err,
+		)
+	}
+
+	return nil
+}
+
+// List Get every provisioner request.
+// Requests expire with the Organizations request they record, so the table stays small.
+func (a *ProvisionerRequest) List() (*provisioner.Requests, error) {
+	requests := provisioner.Requests{}
+	scanInput := &dynamodb.ScanInput{
+		TableName:      aws.String(a.TableName),
+		ConsistentRead: aws.Bool(a.ConsistentRead),
+	}
+
+	for {
+		res, err := a.DynamoDB.Scan(scanInput)
+		if err != nil {
+			return nil, errors.NewInternalServer("error getting provisioner requests", err)
+		}
+
+		page := provisioner.Requests{}
+		err = dynamodbattribute.UnmarshalListOfMaps(res.Items, &page)
+		if err != nil {
+			return nil, errors.NewInternalServer("failed unmarshaling of provisioner requests", err)
+		}
+		requests = append(requests, page...)
+
+		if len(res.LastEvaluatedKey) == 0 {
+			break
+		}
+		scanInput.SetExclusiveStartKey(res.LastEvaluatedKey)
+	}
+
+	return &requests, nil
+}
@@ -0,0 +1,104 @@
+package data
+
+import (
+	gErrors "errors"
+	"testing"
+
+	awsmocks "github.com/Optum/dce/pkg/awsiface/mocks"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/provisioner"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestListProvisionerRequests(t *testing.T) {
+	t.Run("should return the requests from every page", func(t *testing.T) {
+		mockDynamo := awsmocks.DynamoDBAPI{}
+		mockDynamo.On("Scan", mock.MatchedBy(func(input *dynamodb.ScanInput) bool {
+			return *input.TableName == "ProvisionerRequests" && input.ExclusiveStartKey == nil
+		})).Return(&dynamodb.ScanOutput{
+			Items: []map[string]*dynamodb.AttributeValue{
+				{
+					"Id":          {S: aws.String("car-1")},
+					"AccountName": {S: aws.String("dce-test-aaaa")},
+				},
+			},
+			LastEvaluatedKey: map[string]*dynamodb.AttributeValue{
+				"Id": {S: aws.String("car-1")},
+			},
+		}, nil).Once()
+		mockDynamo.On("Scan", mock.MatchedBy(func(input *dynamodb.ScanInput) bool {
+			return *input.TableName == "ProvisionerRequests" && input.ExclusiveStartKey != nil &&
+				*input.ExclusiveStartKey["Id"].S == "car-1"
+		})).Return(&dynamodb.ScanOutput{
+			Items: []map[string]*dynamodb.AttributeValue{
+				{
+					"Id":           {S: aws.String("car-2")},
+					"AccountName":  {S: aws.String("dce-test-bbbb")},
+					"AccountId":    {S: aws.String("123456789012")},
+					"RegisteredOn": {N: aws.String("1573592058")},
+				},
+			},
+		}, nil).Once()
+
+		requestData := &ProvisionerRequest{
+			DynamoDB:  &mockDynamo,
+			TableName: "ProvisionerRequests",
+		}
+
+		requests, err := requestData.List()
+		assert.Nil(t, err)
+		assert.Equal(t, &provisioner.Requests{
+			{
+				ID:          ptrString("car-1"),
+				AccountName: ptrString("dce-test-aaaa"),
+			},
+			{
+				ID:           ptrString("car-2"),
+				AccountName:  ptrString("dce-test-bbbb"),
+				AccountID:    ptrString("123456789012"),
+				RegisteredOn: ptrInt64(1573592058),
+			},
+		}, requests)
+		mockDynamo.AssertNumberOfCalls(t, "Scan", 2)
+	})
+
+	t.Run("should return internal server error when dynamodb fails", func(t *testing.T) {
+		mockDynamo := awsmocks.DynamoDBAPI{}
+		mockDynamo.On("Scan", mock.Anything).Return(nil, gErrors.New("failure"))
+
+		requestData := &ProvisionerRequest{
+			DynamoDB:  &mockDynamo,
+			TableName: "ProvisionerRequests",
+		}
+
+		_, err := requestData.List()
+		expErr := errors.NewInternalServer("error getting provisioner requests", gErrors.New("failure"))
+		assert.Truef(t, errors.Is(err, expErr), "actual error %q doesn't match expected error %q", err, expErr)
+	})
+}
+
+func TestWriteProvisionerRequest(t *testing.T) {
+	mockDynamo := awsmocks.DynamoDBAPI{}
+	mockDynamo.On("PutItem", mock.MatchedBy(func(input *dynamodb.PutItemInput) bool {
+		return *input.TableName == "ProvisionerRequests" &&
+			*input.Item["Id"].S == "car-1" &&
+			*input.Item["TimeToLive"].N == "1581368058" &&
+			input.ConditionExpression == nil
+	})).Return(&dynamodb.PutItemOutput{}, nil)
+
+	requestData := &ProvisionerRequest{
+		DynamoDB:  &mockDynamo,
+		TableName: "ProvisionerRequests",
+	}
+
+	err := requestData.Write(&provisioner.Request{
+		ID:          ptrString("car-1"),
+		AccountName: ptrString("dce-test-aaaa"),
+		CreatedOn:   ptrInt64(1573592058),
+		TimeToLive:  ptrInt64(1581368058),
+	})
+	assert.Nil(t, err)
+}
@@ -0,0 +1,147 @@
+package data
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/resetrun"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+)
+
+// ResetRun - Data Layer Struct
+type ResetRun struct {
+	DynamoDB       dynamodbiface.DynamoDBAPI
+	TableName      string `env:"RESET_RUN_DB"`
+	ConsistentRead bool   `env:"USE_CONSISTENT_READS" envDefault:"false"`
+	Limit          int64  `env:"LIMIT" envDefault:"25"`
+}
+
+// Write the Reset Run record in DynamoDB
+// This is an upsert operation in which the record will either
+// be inserted or updated
+// prevLastModifiedOn parameter is the original lastModifiedOn
+func (a *ResetRun) Write(run *resetrun.ResetRun, prevLastModifiedOn *int64) error {
+
+	var expr expression.Expression
+	var err error
+	returnValue := "NONE"
+	// lastModifiedOn is nil on a create
+	if prevLastModifiedOn != nil {
+		modExpr := expression.Name("LastModifiedOn").Equal(expression.Value(prevLastModifiedOn))
+		expr, err = expression.NewBuilder().WithCondition(modExpr).Build()
+		if err != nil {
+			return errors.NewInternalServer("error building query", err)
+		}
+	} else {
+		modExpr := expression.Name("LastModifiedOn").AttributeNotExists()
+		expr, err = expression.NewBuilder().WithCondition(modExpr).Build()
+		if err != nil {
+			return errors.NewInternalServer("error building query", err)
+		}
+	}
+
+	putMap, _ := dynamodbattribute.Marshal(run)
+	input := &dynamodb.PutItemInput{
+		TableName:                 aws.String(a.TableName),
+		Item:                      putMap.M,
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		ReturnValues:              aws.String(returnValue),
+	}
+	err = putItem(input, a.DynamoDB)
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) {
+		if awsErr.Code() == "ConditionalCheckFailedException" {
+			return errors.NewConflict(
+				"reset run",
+				*run.AccountID,
+				fmt.Errorf("unable to update reset run: reset run has been modified since request was made"))
+		}
+	}
+	if err != nil {
+		return errors.NewInternalServer(
+			fmt.Sprintf("update failed for reset run of account %q", *run.AccountID),
+			err,
+		)
+	}
+
+	return nil
+}
+
+// List Get a list of the reset runs of an account, most recent first.
+// The account ID is required, as it is the hash key of the table.
+func (a *ResetRun) List(query *resetrun.ResetRun) (*resetrun.ResetRuns, error) {
+	if query.AccountID == nil {
+		return nil, errors.NewValidation("reset run", fmt.Errorf("accountId: must be set"))
+	}
+
+	if query.Limit == nil {
+		query.Limit = &a.Limit
+	}
+
+	keyCondition, filters := getFiltersFromStruct(query, aws.String("AccountId"))
+	bldr := expression.NewBuilder().WithKeyCondition(*keyCondition)
+	if filters != nil {
+		bldr = bldr.WithFilter(*filters)
+	}
+
+	expr, err := bldr.Build()
+	if err != nil {
+		return nil, errors.NewInternalServer("unable to build query", err)
+	}
+
+	queryInput := &dynamodb.QueryInput{
+		TableName:                 aws.String(a.TableName),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ConsistentRead:            aws.Bool(a.ConsistentRead),
+		FilterExpression:          expr.Filter(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		ScanIndexForward:          aws.Bool(false),
+	}
+
+	queryInput.SetLimit(*query.Limit)
+	if query.NextStartedOn != nil {
+		queryInput.SetExclusiveStartKey(map[string]*dynamodb.AttributeValue{
+			"AccountId": {
+				S: query.AccountID,
+			},
+			"StartedOn": {
+				N: aws.String(strconv.FormatInt(*query.NextStartedOn, 10)),
+			},
+		})
+	}
+
+	res, err := a.DynamoDB.Query(queryInput)
+	if err != nil {
+		return nil, errors.NewInternalServer(
+			fmt.Sprintf("failed to query reset runs of account %q", *query.AccountID),
+			err,
+		)
+	}
+
+	query.NextStartedOn = nil
+	if v, ok := res.LastEvaluatedKey["StartedOn"]; ok {
+		n, err := strconv.ParseInt(*v.N, 10, 64)
+		if err != nil {
+			return nil, errors.NewInternalServer("unexpected error translating started on to int64", err)
+		}
+		query.NextStartedOn = &n
+	}
+
+	runs := &resetrun.ResetRuns{}
+	err = dynamodbattribute.UnmarshalListOfMaps(res.Items, runs)
+	if err != nil {
+		return nil, errors.NewInternalServer("failed unmarshaling of reset runs", err)
+	}
+
+	return runs, nil
+}
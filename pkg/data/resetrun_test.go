@@ -0,0 +1,177 @@
+package data
+
+import (
+	gErrors "errors"
+	"fmt"
+	"strconv"
+	"testing"
+
+	awsmocks "github.com/Optum/dce/pkg/awsiface/mocks"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/resetrun"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestListResetRuns(t *testing.T) {
+	tests := []struct {
+		name             string
+		query            *resetrun.ResetRun
+		dynamoErr        error
+		dynamoOutput     *dynamodb.QueryOutput
+		expErr           error
+		expRuns          *resetrun.ResetRuns
+		expNextStartedOn *int64
+	}{
+		{
+			name: "should return the reset runs of the account",
+			query: &resetrun.ResetRun{
+				AccountID: ptrString("123456789012"),
+			},
+			dynamoOutput: &dynamodb.QueryOutput{
+				Items: []map[string]*dynamodb.AttributeValue{
+					{
+						"AccountId": {
+							S: aws.String("123456789012"),
+						},
+						"StartedOn": {
+							N: aws.String("1573592058"),
+						},
+						"RunStatus": {
+							S: aws.String("Failed"),
+						},
+						"Error": {
+							S: aws.String("Failed to execute aws-nuke"),
+						},
+					},
+				},
+			},
+			expRuns: &resetrun.ResetRuns{
+				{
+					AccountID: ptrString("123456789012"),
+					StartedOn: ptrInt64(1573592058),
+					Status:    resetrun.StatusFailed.StatusPtr(),
+					Error:     ptrString("Failed to execute aws-nuke"),
+				},
+			},
+		},
+		{
+			name: "should return the next page",
+			query: &resetrun.ResetRun{
+				AccountID: ptrString("123456789012"),
+			},
+			dynamoOutput: &dynamodb.QueryOutput{
+				Items: []map[string]*dynamodb.AttributeValue{},
+				LastEvaluatedKey: map[string]*dynamodb.AttributeValue{
+					"AccountId": {
+						S: aws.String("123456789012"),
+					},
+					"StartedOn": {
+						N: aws.String("1573592058"),
+					},
+				},
+			},
+			expRuns:          &resetrun.ResetRuns{},
+			expNextStartedOn: ptrInt64(1573592058),
+		},
+		{
+			name:   "should require an account ID",
+			query:  &resetrun.ResetRun{},
+			expErr: errors.NewValidation("reset run", fmt.Errorf("accountId: must be set")),
+		},
+		{
+			name: "should return internal server error when dynamodb fails",
+			query: &resetrun.ResetRun{
+				AccountID: ptrString("123456789012"),
+			},
+			dynamoErr:    gErrors.New("failure"),
+			dynamoOutput: &dynamodb.QueryOutput{},
+			expErr:       errors.NewInternalServer("failed to query reset runs of account \"123456789012\"", gErrors.New("failure")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamo := awsmocks.DynamoDBAPI{}
+			mockDynamo.On("Query", mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+				return *input.TableName == "ResetRuns" &&
+					*input.ExpressionAttributeValues[":0"].S == *tt.query.AccountID &&
+					!*input.ScanIndexForward
+			})).Return(tt.dynamoOutput, tt.dynamoErr)
+
+			runData := &ResetRun{
+				DynamoDB:  &mockDynamo,
+				TableName: "ResetRuns",
+				Limit:     25,
+			}
+
+			runs, err := runData.List(tt.query)
+			assert.Truef(t, errors.Is(err, tt.expErr), "actual error %q doesn't match expected error %q", err, tt.expErr)
+			assert.Equal(t, tt.expRuns, runs)
+			assert.Equal(t, tt.expNextStartedOn, tt.query.NextStartedOn)
+		})
+	}
+}
+
+func TestWriteResetRun(t *testing.T) {
+	tests := []struct {
+		name              string
+		run               resetrun.ResetRun
+		oldLastModifiedOn *int64
+		dynamoErr         error
+		expErr            error
+	}{
+		{
+			name: "start",
+			run: resetrun.ResetRun{
+				AccountID:      ptrString("123456789012"),
+				StartedOn:      ptrInt64(1573592058),
+				Status:         resetrun.StatusRunning.StatusPtr(),
+				LastModifiedOn: ptrInt64(1573592058),
+			},
+		},
+		{
+			name: "conditional failure",
+			run: resetrun.ResetRun{
+				AccountID:      ptrString("123456789012"),
+				StartedOn:      ptrInt64(1573592058),
+				Status:         resetrun.StatusSucceeded.StatusPtr(),
+				LastModifiedOn: ptrInt64(1573592158),
+			},
+			oldLastModifiedOn: ptrInt64(1573592058),
+			dynamoErr:         awserr.New("ConditionalCheckFailedException", "Message", fmt.Errorf("Bad")),
+			expErr: errors.NewConflict(
+				"reset run",
+				"123456789012",
+				fmt.Errorf("unable to update reset run: reset run has been modified since request was made")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamo := awsmocks.DynamoDBAPI{}
+			mockDynamo.On("PutItem", mock.MatchedBy(func(input *dynamodb.PutItemInput) bool {
+				if tt.oldLastModifiedOn == nil {
+					return *input.TableName == "ResetRuns" &&
+						*input.Item["AccountId"].S == *tt.run.AccountID &&
+						*input.Item["RunStatus"].S == string(*tt.run.Status) &&
+						*input.ConditionExpression == "attribute_not_exists (#0)"
+				}
+				return *input.TableName == "ResetRuns" &&
+					*input.Item["AccountId"].S == *tt.run.AccountID &&
+					*input.ExpressionAttributeValues[":0"].N == strconv.FormatInt(*tt.oldLastModifiedOn, 10)
+			})).Return(&dynamodb.PutItemOutput{}, tt.dynamoErr)
+
+			runData := &ResetRun{
+				DynamoDB:  &mockDynamo,
+				TableName: "ResetRuns",
+			}
+
+			err := runData.Write(&tt.run, tt.oldLastModifiedOn)
+			assert.Truef(t, errors.Is(err, tt.expErr), "actual error %q doesn't match expected error %q", err, tt.expErr)
+		})
+	}
+}
@@ -17,6 +17,7 @@ import (
 type Usage struct {
 	DynamoDB       dynamodbiface.DynamoDBAPI
 	TableName      string `env:"USAGE_DB"`
+	LeaseTableName string `env:"LEASE_USAGE_DB"`
 	ConsistentRead bool   `env:"USE_CONSISTENT_READS" envDefault:"false"`
 	Limit          int64  `env:"LIMIT" envDefault:"25"`
 }
@@ -93,3 +94,8 @@ func (a *Usage) GetByStartDateAndPrincipalID(startDate int64, principalID string
 	}
 	return usg, nil
 }
+
+// Get gets the Usage record by StartDate and PrincipalID
+func (a *Usage) Get(startDate int64, principalID string) (*usage.Usage, error) {
+	return a.GetByStartDateAndPrincipalID(startDate, principalID)
+}
@@ -1,6 +1,7 @@
 package data
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 
@@ -14,6 +15,15 @@ import (
 
 // query for doing a query against dynamodb
 func (a *Usage) query(query *usage.Usage, keyName string, index *string) (*queryScanOutput, error) {
+	return a.queryTable(query, a.TableName, keyName, index)
+}
+
+// queryLeases for doing a query of the usage of a lease against dynamodb
+func (a *Usage) queryLeases(query *usage.Usage) (*queryScanOutput, error) {
+	return a.queryTable(query, a.LeaseTableName, "LeaseId", nil)
+}
+
+func (a *Usage) queryTable(query *usage.Usage, tableName string, keyName string, index *string) (*queryScanOutput, error) {
 	var expr expression.Expression
 	var bldr expression.Builder
 	var err error
@@ -31,7 +41,7 @@ func (a *Usage) query(query *usage.Usage, keyName string, index *string) (*query
 	}
 
 	queryInput := &dynamodb.QueryInput{
-		TableName:                 aws.String(a.TableName),
+		TableName:                 aws.String(tableName),
 		IndexName:                 index,
 		KeyConditionExpression:    expr.KeyCondition(),
 		ConsistentRead:            aws.Bool(a.ConsistentRead),
@@ -41,11 +51,21 @@ func (a *Usage) query(query *usage.Usage, keyName string, index *string) (*query
 	}
 
 	queryInput.SetLimit(*query.Limit)
-	if query.NextStartDate != nil && query.NextPrincipalID != nil {
+	if keyName == "LeaseId" && query.NextStartDate != nil {
+		// Lease usage is keyed by lease ID and start date
+		queryInput.SetExclusiveStartKey(map[string]*dynamodb.AttributeValue{
+			"LeaseId": &dynamodb.AttributeValue{
+				S: query.LeaseID,
+			},
+			"StartDate": &dynamodb.AttributeValue{
+				N: aws.String(strconv.FormatInt(*query.NextStartDate, 10)),
+			},
+		})
+	} else if query.NextStartDate != nil && query.NextPrincipalID != nil {
 		// Should be more dynamic
 		queryInput.SetExclusiveStartKey(map[string]*dynamodb.AttributeValue{
 			"StartDate": &dynamodb.AttributeValue{
-				N: aws.String(strconv.FormatInt(*query.StartDate, 10)),
+				N: aws.String(strconv.FormatInt(*query.NextStartDate, 10)),
 			},
 			"PrincipalId": &dynamodb.AttributeValue{
 				S: query.NextPrincipalID,
@@ -94,7 +114,7 @@ func (a *Usage) scan(query *usage.Usage) (*queryScanOutput, error) {
 		// Should be more dynamic
 		scanInput.SetExclusiveStartKey(map[string]*dynamodb.AttributeValue{
 			"StartDate": &dynamodb.AttributeValue{
-				N: aws.String(strconv.FormatInt(*query.StartDate, 10)),
+				N: aws.String(strconv.FormatInt(*query.NextStartDate, 10)),
 			},
 			"PrincipalId": &dynamodb.AttributeValue{
 				S: query.NextPrincipalID,
@@ -126,6 +146,11 @@ func (a *Usage) List(query *usage.Usage) (*usage.Usages, error) {
 
 	if query.StartDate != nil {
 		outputs, err = a.query(query, "StartDate", nil)
+	} else if query.LeaseID != nil {
+		// Usage records are shared by all the leases of a principal,
+		// so the usage of a lease is listed from its own records.
+		// Pages of lease usage have a NextStartDate, without a NextPrincipalID.
+		outputs, err = a.queryLeases(query)
 	} else {
 		outputs, err = a.scan(query)
 	}
@@ -133,6 +158,66 @@ func (a *Usage) List(query *usage.Usage) (*usage.Usages, error) {
 		return nil, err
 	}
 
+	return unmarshalUsages(query, outputs)
+}
+
+// ListByMonth Get a page of the usage records of a month, from the StartMonth index.
+// Only records with a start date between the StartDate (inclusive)
+// and EndDate (exclusive) of the query are listed.
+func (a *Usage) ListByMonth(month string, query *usage.Usage) (*usage.Usages, error) {
+	if query.Limit == nil {
+		query.Limit = &a.Limit
+	}
+
+	keyCondition := expression.Key("StartMonth").Equal(expression.Value(month)).
+		And(expression.Key("StartDate").Between(
+			expression.Value(*query.StartDate),
+			expression.Value(*query.EndDate-1),
+		))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCondition).Build()
+	if err != nil {
+		return nil, errors.NewInternalServer("unable to build query", err)
+	}
+
+	queryInput := &dynamodb.QueryInput{
+		TableName:                 aws.String(a.TableName),
+		IndexName:                 aws.String("StartMonth"),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	}
+
+	queryInput.SetLimit(*query.Limit)
+	if query.NextStartDate != nil && query.NextPrincipalID != nil {
+		queryInput.SetExclusiveStartKey(map[string]*dynamodb.AttributeValue{
+			"StartMonth": {
+				S: aws.String(month),
+			},
+			"StartDate": {
+				N: aws.String(strconv.FormatInt(*query.NextStartDate, 10)),
+			},
+			"PrincipalId": {
+				S: query.NextPrincipalID,
+			},
+		})
+	}
+
+	res, err := a.DynamoDB.Query(queryInput)
+	if err != nil {
+		return nil, errors.NewInternalServer(
+			fmt.Sprintf("failed to query usages of month %q", month),
+			err,
+		)
+	}
+
+	return unmarshalUsages(query, &queryScanOutput{
+		items:            res.Items,
+		lastEvaluatedKey: res.LastEvaluatedKey,
+	})
+}
+
+// unmarshalUsages returns the usages of a page, and sets the start keys of the next page on the query
+func unmarshalUsages(query *usage.Usage, outputs *queryScanOutput) (*usage.Usages, error) {
 	query.NextStartDate = nil
 	query.NextPrincipalID = nil
 	for k, v := range outputs.lastEvaluatedKey {
@@ -150,7 +235,7 @@ func (a *Usage) List(query *usage.Usage) (*usage.Usages, error) {
 	}
 
 	usgs := &usage.Usages{}
-	err = dynamodbattribute.UnmarshalListOfMaps(outputs.items, usgs)
+	err := dynamodbattribute.UnmarshalListOfMaps(outputs.items, usgs)
 	if err != nil {
 		return nil, errors.NewInternalServer("failed unmarshal of usages", err)
 	}
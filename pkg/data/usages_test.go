@@ -10,6 +10,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestGetUsageScan(t *testing.T) {
@@ -100,6 +101,44 @@ func TestGetUsageScan(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "scan get all Usages from the next page",
+			query: &usage.Usage{
+				NextStartDate:   ptrInt64(1580924093),
+				NextPrincipalID: ptrString("User1"),
+			},
+			sInput: &dynamodb.ScanInput{
+				ConsistentRead: aws.Bool(false),
+				TableName:      aws.String("Usages"),
+				Limit:          ptrInt64(25),
+				ExclusiveStartKey: map[string]*dynamodb.AttributeValue{
+					"StartDate": {
+						N: aws.String("1580924093"),
+					},
+					"PrincipalId": {
+						S: aws.String("User1"),
+					},
+				},
+			},
+			sOutputRec: &dynamodb.ScanOutput{
+				Items: []map[string]*dynamodb.AttributeValue{
+					map[string]*dynamodb.AttributeValue{
+						"AccountId": {
+							S: aws.String("1"),
+						},
+						"PrincipalId": {
+							S: aws.String("User2"),
+						},
+					},
+				},
+			},
+			expUsages: &usage.Usages{
+				{
+					AccountID:   ptrString("1"),
+					PrincipalID: ptrString("User2"),
+				},
+			},
+		},
 		{
 			name:  "scan failure with internal server error",
 			query: &usage.Usage{},
@@ -230,6 +269,105 @@ func TestGetUsagesQuery(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "query all Usages by StartDate from the next page",
+			query: &usage.Usage{
+				StartDate:       ptrInt64(1580924093),
+				NextStartDate:   ptrInt64(1580924093),
+				NextPrincipalID: ptrString("User1"),
+			},
+			qInput: &dynamodb.QueryInput{
+				ConsistentRead: aws.Bool(false),
+				TableName:      aws.String("Usages"),
+				ExpressionAttributeNames: map[string]*string{
+					"#0": aws.String("StartDate"),
+				},
+				ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+					":0": {
+						N: aws.String("1580924093"),
+					},
+				},
+				KeyConditionExpression: aws.String("#0 = :0"),
+				Limit:                  ptrInt64(25),
+				ExclusiveStartKey: map[string]*dynamodb.AttributeValue{
+					"StartDate": {
+						N: aws.String("1580924093"),
+					},
+					"PrincipalId": {
+						S: aws.String("User1"),
+					},
+				},
+			},
+			qOutputRec: &dynamodb.QueryOutput{
+				Items: []map[string]*dynamodb.AttributeValue{
+					map[string]*dynamodb.AttributeValue{
+						"StartDate": {
+							N: aws.String("1580924093"),
+						},
+						"PrincipalId": {
+							S: aws.String("User2"),
+						},
+					},
+				},
+			},
+			expUsages: &usage.Usages{
+				{
+					StartDate:   ptrInt64(1580924093),
+					PrincipalID: ptrString("User2"),
+				},
+			},
+		},
+		{
+			name: "query all Usages by LeaseId",
+			query: &usage.Usage{
+				LeaseID:       ptrString("lease1"),
+				NextStartDate: ptrInt64(1580924093),
+			},
+			qInput: &dynamodb.QueryInput{
+				ConsistentRead: aws.Bool(false),
+				TableName:      aws.String("LeaseUsages"),
+				ExpressionAttributeNames: map[string]*string{
+					"#0": aws.String("LeaseId"),
+				},
+				ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+					":0": {
+						S: aws.String("lease1"),
+					},
+				},
+				KeyConditionExpression: aws.String("#0 = :0"),
+				Limit:                  ptrInt64(25),
+				ExclusiveStartKey: map[string]*dynamodb.AttributeValue{
+					"LeaseId": {
+						S: aws.String("lease1"),
+					},
+					"StartDate": {
+						N: aws.String("1580924093"),
+					},
+				},
+			},
+			qOutputRec: &dynamodb.QueryOutput{
+				Items: []map[string]*dynamodb.AttributeValue{
+					map[string]*dynamodb.AttributeValue{
+						"StartDate": {
+							N: aws.String("1580924094"),
+						},
+						"PrincipalId": {
+							S: aws.String("User1"),
+						},
+						"LeaseId": {
+							S: aws.String("lease1"),
+						},
+					},
+				},
+			},
+			expUsages: &usage.Usages{
+				{
+					StartDate:   ptrInt64(1580924094),
+					PrincipalID: ptrString("User1"),
+					LeaseID:     ptrString("lease1"),
+				},
+			},
+		},
 		{
 			name: "query internal error",
 			query: &usage.Usage{
@@ -273,9 +411,10 @@ func TestGetUsagesQuery(t *testing.T) {
 			}
 
 			leaseData := &Usage{
-				DynamoDB:  &mockDynamo,
-				TableName: "Usages",
-				Limit:     25,
+				DynamoDB:       &mockDynamo,
+				TableName:      "Usages",
+				LeaseTableName: "LeaseUsages",
+				Limit:          25,
 			}
 			Usages, err := leaseData.List(tt.query)
 			assert.True(t, errors.Is(err, tt.expErr))
@@ -284,3 +423,74 @@ func TestGetUsagesQuery(t *testing.T) {
 	}
 
 }
+
+func TestListUsagesByMonth(t *testing.T) {
+	t.Run("should query the month index by date range", func(t *testing.T) {
+		mockDynamo := awsmocks.DynamoDBAPI{}
+		mockDynamo.On("Query", mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+			return *input.TableName == "Usage" &&
+				*input.IndexName == "StartMonth" &&
+				*input.ExclusiveStartKey["StartMonth"].S == "2019-10" &&
+				*input.ExclusiveStartKey["StartDate"].N == "1570924800" &&
+				*input.ExclusiveStartKey["PrincipalId"].S == "user1" &&
+				*input.ExpressionAttributeValues[":0"].S == "2019-10" &&
+				*input.ExpressionAttributeValues[":1"].N == "1570924800" &&
+				*input.ExpressionAttributeValues[":2"].N == "1571529599"
+		})).Return(&dynamodb.QueryOutput{
+			Items: []map[string]*dynamodb.AttributeValue{
+				{
+					"PrincipalId": {S: aws.String("user2")},
+					"StartDate":   {N: aws.String("1570924800")},
+					"StartMonth":  {S: aws.String("2019-10")},
+				},
+			},
+			LastEvaluatedKey: map[string]*dynamodb.AttributeValue{
+				"PrincipalId": {S: aws.String("user2")},
+				"StartDate":   {N: aws.String("1570924800")},
+				"StartMonth":  {S: aws.String("2019-10")},
+			},
+		}, nil)
+
+		usageData := &Usage{
+			DynamoDB:  &mockDynamo,
+			TableName: "Usage",
+			Limit:     25,
+		}
+
+		query := &usage.Usage{
+			StartDate:       aws.Int64(1570924800),
+			EndDate:         aws.Int64(1571529600),
+			NextStartDate:   aws.Int64(1570924800),
+			NextPrincipalID: aws.String("user1"),
+		}
+		usages, err := usageData.ListByMonth("2019-10", query)
+		assert.Nil(t, err)
+		assert.Equal(t, &usage.Usages{
+			{
+				PrincipalID: aws.String("user2"),
+				StartDate:   aws.Int64(1570924800),
+				StartMonth:  aws.String("2019-10"),
+			},
+		}, usages)
+		assert.Equal(t, aws.Int64(1570924800), query.NextStartDate)
+		assert.Equal(t, aws.String("user2"), query.NextPrincipalID)
+	})
+
+	t.Run("should return internal server error when dynamodb fails", func(t *testing.T) {
+		mockDynamo := awsmocks.DynamoDBAPI{}
+		mockDynamo.On("Query", mock.Anything).Return(nil, fmt.Errorf("failure"))
+
+		usageData := &Usage{
+			DynamoDB:  &mockDynamo,
+			TableName: "Usage",
+			Limit:     25,
+		}
+
+		_, err := usageData.ListByMonth("2019-10", &usage.Usage{
+			StartDate: aws.Int64(1570924800),
+			EndDate:   aws.Int64(1571529600),
+		})
+		expErr := errors.NewInternalServer("failed to query usages of month \"2019-10\"", fmt.Errorf("failure"))
+		assert.Truef(t, errors.Is(err, expErr), "actual error %q doesn't match expected error %q", err, expErr)
+	})
+}
@@ -61,6 +61,7 @@ type DBer interface {
 	FindLeasesByStatus(status LeaseStatus) ([]*Lease, error)
 	UpdateAccountPrincipalPolicyHash(accountID string, prevHash string, nextHash string) (*Account, error)
 	OrphanAccount(accountID string) (*Account, error)
+	AddLeaseBudgetNotification(accountID string, principalID string, notification LeaseBudgetNotification) (*Lease, error)
 }
 
 // GetAccount returns an account record corresponding to an accountID
@@ -726,6 +727,53 @@ func (db *DB) OrphanAccount(accountID string) (*Account, error) {
 	return resAccount, nil
 }
 
+// AddLeaseBudgetNotification appends a sent budget notification to the lease record,
+// and returns the updated lease
+func (db *DB) AddLeaseBudgetNotification(accountID string, principalID string, notification LeaseBudgetNotification) (*Lease, error) {
+	notificationValue, err := dynamodbattribute.MarshalMap(notification)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := db.Client.UpdateItem(
+		&dynamodb.UpdateItemInput{
+			TableName: aws.String(db.LeaseTableName),
+			Key: map[string]*dynamodb.AttributeValue{
+				"AccountId": {
+					S: aws.String(accountID),
+				},
+				"PrincipalId": {
+					S: aws.String(principalID),
+				},
+			},
+			UpdateExpression: aws.String("set BudgetNotifications=list_append(if_not_exists(BudgetNotifications, :empty), :notifications), " +
+				"LastModifiedOn=:lastModifiedOn"),
+			ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+				":empty": {
+					L: []*dynamodb.AttributeValue{},
+				},
+				":notifications": {
+					L: []*dynamodb.AttributeValue{
+						{M: notificationValue},
+					},
+				},
+				":lastModifiedOn": {
+					N: aws.String(strconv.FormatInt(time.Now().Unix(), 10)),
+				},
+			},
+			// Don't create a lease record, if the lease doesn't exist
+			ConditionExpression: aws.String("attribute_exists(AccountId)"),
+			// Return the updated record
+			ReturnValues: aws.String("ALL_NEW"),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return unmarshalLease(result.Attributes)
+}
+
 func unmarshalAccount(dbResult map[string]*dynamodb.AttributeValue) (*Account, error) {
 	account := Account{}
 	err := dynamodbattribute.UnmarshalMap(dbResult, &account)
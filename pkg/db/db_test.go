@@ -344,3 +344,52 @@ func TestGetLeaseByID(t *testing.T) {
 		})
 	}
 }
+
+func TestAddLeaseBudgetNotification(t *testing.T) {
+	mockDynamo := awsmocks.DynamoDBAPI{}
+	mockDynamo.On("UpdateItem", mock.MatchedBy(func(input *dynamodb.UpdateItemInput) bool {
+		notification := input.ExpressionAttributeValues[":notifications"].L[0].M
+		return *input.TableName == "lease" &&
+			*input.Key["AccountId"].S == "123456789012" &&
+			*input.Key["PrincipalId"].S == "user1" &&
+			*notification["Type"].S == "LeaseBudgetThreshold" &&
+			*notification["ThresholdPercentile"].N == "75"
+	})).Return(&dynamodb.UpdateItemOutput{
+		Attributes: map[string]*dynamodb.AttributeValue{
+			"AccountId":   {S: aws.String("123456789012")},
+			"PrincipalId": {S: aws.String("user1")},
+			"BudgetNotifications": {L: []*dynamodb.AttributeValue{
+				{M: map[string]*dynamodb.AttributeValue{
+					"Type":                {S: aws.String("LeaseBudgetThreshold")},
+					"ThresholdPercentile": {N: aws.String("75")},
+					"BudgetAmount":        {N: aws.String("100")},
+					"ActualSpend":         {N: aws.String("80")},
+					"SentOn":              {N: aws.String("1570000000")},
+				}},
+			}},
+		},
+	}, nil)
+
+	db := DB{
+		Client:         &mockDynamo,
+		LeaseTableName: "lease",
+	}
+
+	lease, err := db.AddLeaseBudgetNotification("123456789012", "user1", LeaseBudgetNotification{
+		Type:                LeaseBudgetThresholdNotification,
+		ThresholdPercentile: 75,
+		BudgetAmount:        100,
+		ActualSpend:         80,
+		SentOn:              1570000000,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []LeaseBudgetNotification{
+		{
+			Type:                LeaseBudgetThresholdNotification,
+			ThresholdPercentile: 75,
+			BudgetAmount:        100,
+			ActualSpend:         80,
+			SentOn:              1570000000,
+		},
+	}, lease.BudgetNotifications)
+}
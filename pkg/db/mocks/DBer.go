@@ -10,6 +10,29 @@ type DBer struct {
 	mock.Mock
 }
 
+// AddLeaseBudgetNotification provides a mock function with given fields: accountID, principalID, notification
+func (_m *DBer) AddLeaseBudgetNotification(accountID string, principalID string, notification db.LeaseBudgetNotification) (*db.Lease, error) {
+	ret := _m.Called(accountID, principalID, notification)
+
+	var r0 *db.Lease
+	if rf, ok := ret.Get(0).(func(string, string, db.LeaseBudgetNotification) *db.Lease); ok {
+		r0 = rf(accountID, principalID, notification)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*db.Lease)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, db.LeaseBudgetNotification) error); ok {
+		r1 = rf(accountID, principalID, notification)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindAccountsByStatus provides a mock function with given fields: status
 func (_m *DBer) FindAccountsByStatus(status db.AccountStatus) ([]*db.Account, error) {
 	ret := _m.Called(status)
@@ -20,21 +20,45 @@ type Account struct {
 // Lease is a type corresponding to a Lease
 // table record
 type Lease struct {
-	AccountID                string                 `json:"AccountId"`                // AWS Account ID
-	PrincipalID              string                 `json:"PrincipalId"`              // Azure User Principal ID
-	ID                       string                 `json:"Id"`                       // Lease ID
-	LeaseStatus              LeaseStatus            `json:"LeaseStatus"`              // Status of the Lease
-	LeaseStatusReason        LeaseStatusReason      `json:"LeaseStatusReason"`        // Reason for the status of the lease
-	CreatedOn                int64                  `json:"CreatedOn"`                // Created Epoch Timestamp
-	LastModifiedOn           int64                  `json:"LastModifiedOn"`           // Last Modified Epoch Timestamp
-	BudgetAmount             float64                `json:"BudgetAmount"`             // Budget Amount allocated for this lease
-	BudgetCurrency           string                 `json:"BudgetCurrency"`           // Budget currency
-	BudgetNotificationEmails []string               `json:"BudgetNotificationEmails"` // Budget notification emails
-	LeaseStatusModifiedOn    int64                  `json:"LeaseStatusModifiedOn"`    // Last Modified Epoch Timestamp
-	ExpiresOn                int64                  `json:"ExpiresOn"`                // Lease expiration time as Epoch
-	Metadata                 map[string]interface{} `json:"Metadata"`                 // Arbitrary key-value metadata to store with lease object
+	AccountID                string                    `json:"AccountId"`                // AWS Account ID
+	PrincipalID              string                    `json:"PrincipalId"`              // Azure User Principal ID
+	ID                       string                    `json:"Id"`                       // Lease ID
+	LeaseStatus              LeaseStatus               `json:"LeaseStatus"`              // Status of the Lease
+	LeaseStatusReason        LeaseStatusReason         `json:"LeaseStatusReason"`        // Reason for the status of the lease
+	CreatedOn                int64                     `json:"CreatedOn"`                // Created Epoch Timestamp
+	LastModifiedOn           int64                     `json:"LastModifiedOn"`           // Last Modified Epoch Timestamp
+	BudgetAmount             float64                   `json:"BudgetAmount"`             // Budget Amount allocated for this lease
+	BudgetCurrency           string                    `json:"BudgetCurrency"`           // Budget currency
+	BudgetNotificationEmails []string                  `json:"BudgetNotificationEmails"` // Budget notification emails
+	NotificationTargets      []string                  `json:"NotificationTargets"`      // Budget notification webhooks, eg. "slack:https://hooks.slack.com/..."
+	BudgetNotifications      []LeaseBudgetNotification `json:"BudgetNotifications"`      // Budget notifications already sent for this lease
+	LeaseStatusModifiedOn    int64                     `json:"LeaseStatusModifiedOn"`    // Last Modified Epoch Timestamp
+	ExpiresOn                int64                     `json:"ExpiresOn"`                // Lease expiration time as Epoch
+	Metadata                 map[string]interface{}    `json:"Metadata"`                 // Arbitrary key-value metadata to store with lease object
 }
 
+// LeaseBudgetNotification records a budget notification sent for a lease,
+// so the same notification isn't sent again
+type LeaseBudgetNotification struct {
+	Type                LeaseBudgetNotificationType `json:"Type"`
+	ThresholdPercentile float64                     `json:"ThresholdPercentile"` // Budget threshold reached. 0 for forecast notifications.
+	BudgetAmount        float64                     `json:"BudgetAmount"`        // Budget amount the threshold applies to
+	ActualSpend         float64                     `json:"ActualSpend"`         // Spend when the notification was sent
+	SentOn              int64                       `json:"SentOn"`              // Sent Epoch Timestamp
+}
+
+// LeaseBudgetNotificationType is the reason a budget notification was sent
+type LeaseBudgetNotificationType string
+
+const (
+	// LeaseBudgetThresholdNotification is sent when lease spend reaches a threshold of the lease budget
+	LeaseBudgetThresholdNotification LeaseBudgetNotificationType = "LeaseBudgetThreshold"
+	// PrincipalBudgetThresholdNotification is sent when principal spend reaches a threshold of the lease budget
+	PrincipalBudgetThresholdNotification LeaseBudgetNotificationType = "PrincipalBudgetThreshold"
+	// ForecastOverBudgetNotification is sent when the lease is forecasted to exceed its budget
+	ForecastOverBudgetNotification LeaseBudgetNotificationType = "ForecastOverBudget"
+)
+
 // Timestamp is a timestamp type for epoch format
 type Timestamp int64
 
@@ -58,6 +82,10 @@ const (
 	Leased AccountStatus = "Leased"
 	// Orphaned status
 	Orphaned AccountStatus = "Orphaned"
+	// Retiring status
+	Retiring AccountStatus = "Retiring"
+	// Retired status
+	Retired AccountStatus = "Retired"
 )
 
 // ParseAccountStatus - parses the string into an account status.
@@ -106,6 +134,9 @@ const (
 	LeaseOverBudget LeaseStatusReason = "OverBudget"
 	// LeaseOverPrincipalBudget means the lease is over its principal budgeted amount and is therefore reset/reclaimed.
 	LeaseOverPrincipalBudget LeaseStatusReason = "OverPrincipalBudget"
+	// LeaseForecastOverBudget means the lease is forecasted to exceed its budget before it expires
+	// and is therefore reset/reclaimed.
+	LeaseForecastOverBudget LeaseStatusReason = "ForecastOverBudget"
 	// LeaseDestroyed means the lease has been deleted via an API call or other user action.
 	LeaseDestroyed LeaseStatusReason = "Destroyed"
 	// LeaseActive means the lease is still active.
@@ -75,7 +75,10 @@ func (svc *SESEmailService) SendEmail(input *SendEmailInput) error {
 func (svc *SESEmailService) SendRawEmailWithAttachment(input *SendEmailWithAttachmentInput) error {
 	msg := gomail.NewMessage()
 	msg.SetHeader("From", input.FromAddress)
-	msg.SetHeader("To", input.ToAddresses[0])
+	msg.SetHeader("To", input.ToAddresses...)
+	if len(input.CCAddresses) > 0 {
+		msg.SetHeader("Cc", input.CCAddresses...)
+	}
 	msg.SetHeader("Subject", input.Subject)
 	msg.SetBody("text/html", input.BodyHTML)
 	msg.Attach(input.AttachmentFileName)
@@ -86,10 +89,18 @@ func (svc *SESEmailService) SendRawEmailWithAttachment(input *SendEmailWithAttac
 		return err
 	}
 
+	// BCC addresses are only set as destinations, so they aren't visible to other recipients
+	destinations := append([]string{}, input.ToAddresses...)
+	destinations = append(destinations, input.CCAddresses...)
+	destinations = append(destinations, input.BCCAddresses...)
+
 	message := ses.RawMessage{Data: emailRaw.Bytes()}
 	emailInput := &ses.SendRawEmailInput{
-		FromArn:    aws.String(input.FromArn),
-		RawMessage: &message,
+		Destinations: aws.StringSlice(destinations),
+		RawMessage:   &message,
+	}
+	if input.FromArn != "" {
+		emailInput.FromArn = aws.String(input.FromArn)
 	}
 
 	_, err = svc.SES.SendRawEmail(emailInput)
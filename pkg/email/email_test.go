@@ -0,0 +1,114 @@
+package email
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	awsMocks "github.com/Optum/dce/pkg/awsiface/mocks"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ses"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSendRawEmailWithAttachment(t *testing.T) {
+
+	tests := []struct {
+		name            string
+		input           *SendEmailWithAttachmentInput
+		sendErr         error
+		expDestinations []string
+		expHeaders      []string
+		expFromArn      *string
+		expErr          error
+	}{
+		{
+			name: "when there are multiple recipients. Each is a destination",
+			input: &SendEmailWithAttachmentInput{
+				FromAddress: "dce@example.com",
+				ToAddresses: []string{"to1@example.com", "to2@example.com"},
+				Subject:     "Report",
+				BodyHTML:    "<p>Report</p>",
+			},
+			expDestinations: []string{"to1@example.com", "to2@example.com"},
+			expHeaders: []string{
+				"From: dce@example.com",
+				"To: to1@example.com, to2@example.com",
+				"Subject: Report",
+			},
+		},
+		{
+			name: "when there are CC and BCC recipients. Only CC recipients are in the headers",
+			input: &SendEmailWithAttachmentInput{
+				FromAddress:  "dce@example.com",
+				ToAddresses:  []string{"to@example.com"},
+				CCAddresses:  []string{"cc@example.com"},
+				BCCAddresses: []string{"bcc@example.com"},
+				Subject:      "Report",
+			},
+			expDestinations: []string{"to@example.com", "cc@example.com", "bcc@example.com"},
+			expHeaders: []string{
+				"To: to@example.com",
+				"Cc: cc@example.com",
+			},
+		},
+		{
+			name: "when a from ARN is set. It is sent to SES",
+			input: &SendEmailWithAttachmentInput{
+				FromArn:     "arn:aws:ses:us-east-1:123456789012:identity/example.com",
+				FromAddress: "dce@example.com",
+				ToAddresses: []string{"to@example.com"},
+				Subject:     "Report",
+			},
+			expDestinations: []string{"to@example.com"},
+			expFromArn:      aws.String("arn:aws:ses:us-east-1:123456789012:identity/example.com"),
+		},
+		{
+			name: "when SES fails. The error is returned",
+			input: &SendEmailWithAttachmentInput{
+				FromAddress: "dce@example.com",
+				ToAddresses: []string{"to@example.com"},
+				Subject:     "Report",
+			},
+			sendErr:         fmt.Errorf("failure"),
+			expDestinations: []string{"to@example.com"},
+			expErr:          fmt.Errorf("failure"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "email")
+			assert.Nil(t, err)
+			defer os.RemoveAll(dir)
+
+			attachment := filepath.Join(dir, "report.csv")
+			err = ioutil.WriteFile(attachment, []byte("a,b\n1,2\n"), 0600)
+			assert.Nil(t, err)
+			tt.input.AttachmentFileName = attachment
+
+			sesSvc := &awsMocks.SESAPI{}
+			sesSvc.On("SendRawEmail", mock.AnythingOfType("*ses.SendRawEmailInput")).
+				Run(func(args mock.Arguments) {
+					input := args.Get(0).(*ses.SendRawEmailInput)
+					assert.Equal(t, tt.expDestinations, aws.StringValueSlice(input.Destinations))
+					assert.Equal(t, tt.expFromArn, input.FromArn)
+
+					raw := string(input.RawMessage.Data)
+					for _, header := range tt.expHeaders {
+						assert.Contains(t, raw, header)
+					}
+					assert.NotContains(t, raw, "bcc@example.com")
+					assert.Contains(t, raw, `filename="report.csv"`)
+				}).Return(&ses.SendRawEmailOutput{}, tt.sendErr)
+
+			svc := &SESEmailService{SES: sesSvc}
+			err = svc.SendRawEmailWithAttachment(tt.input)
+			assert.Equal(t, tt.expErr, err)
+			sesSvc.AssertExpectations(t)
+		})
+	}
+}
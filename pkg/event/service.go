@@ -19,6 +19,7 @@ type NewServiceInput struct {
 	AccountDeletedTopicArn string `env:"ACCOUNT_DELETED_TOPIC_ARN" envDefault:"arn:aws:sns:us-east-1:123456789012:account-delete"`
 	AccountResetQueueURL   string `env:"RESET_SQS_URL" envDefault:"DefaultResetSQSUrl"`
 	LeaseAddedTopicArn     string `env:"LEASE_ADDED_TOPIC" envDefault:"arn:aws:sns:us-east-1:123456789012:lease-added"`
+	LeaseUpdatedTopicArn   string `env:"LEASE_UPDATED_TOPIC" envDefault:"arn:aws:sns:us-east-1:123456789012:lease-updated"`
 }
 
 // Service is the public interface for publishing events
@@ -117,11 +118,18 @@ func NewService(input NewServiceInput) (*Service, error) {
 		return nil, err
 	}
 
+	updateLease, err := NewSnsEvent(input.SnsClient, input.LeaseUpdatedTopicArn)
+	if err != nil {
+		return nil, err
+	}
+
 	newEventer.leaseCreate = []Publisher{
 		createLease,
 	}
 	newEventer.leaseEnd = []Publisher{}
-	newEventer.leaseUpdate = []Publisher{}
+	newEventer.leaseUpdate = []Publisher{
+		updateLease,
+	}
 
 	return newEventer, nil
 }
@@ -20,6 +20,7 @@ func TestNewEvent(t *testing.T) {
 		accountCreatedTopicArn, _ := arn.Parse("arn:aws:sns:us-east-1:123456789012:createAccount")
 		accountDeletedTopicArn, _ := arn.Parse("arn:aws:sns:us-east-1:123456789012:deleteAccount")
 		leaseAddedTopicArn, _ := arn.Parse("arn:aws:sns:us-east-1:123456789012:createLease")
+		leaseUpdatedTopicArn, _ := arn.Parse("arn:aws:sns:us-east-1:123456789012:updateLease")
 		accountResetQueueURL := "http://sqs.com/queue"
 
 		eventer, err := NewService(NewServiceInput{
@@ -28,6 +29,7 @@ func TestNewEvent(t *testing.T) {
 			AccountCreatedTopicArn: accountCreatedTopicArn.String(),
 			AccountDeletedTopicArn: accountDeletedTopicArn.String(),
 			LeaseAddedTopicArn:     leaseAddedTopicArn.String(),
+			LeaseUpdatedTopicArn:   leaseUpdatedTopicArn.String(),
 			AccountResetQueueURL:   accountResetQueueURL,
 		})
 
@@ -52,7 +54,12 @@ func TestNewEvent(t *testing.T) {
 				topicArn: leaseAddedTopicArn,
 			},
 		}, eventer.leaseCreate)
-		assert.Equal(t, []Publisher{}, eventer.leaseUpdate)
+		assert.Equal(t, []Publisher{
+			&SnsEvent{
+				sns:      mockSns,
+				topicArn: leaseUpdatedTopicArn,
+			},
+		}, eventer.leaseUpdate)
 		assert.Equal(t, []Publisher{}, eventer.leaseEnd)
 	})
 
@@ -0,0 +1,52 @@
+package lease
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExtensionToken returns a token which authorizes a single extension of the lease,
+// so it may be extended from a link, without signing the request.
+// The token is an HMAC of the lease ID, its expiresOn date and the token's own
+// expiry, so it can't be used again once the lease has been extended,
+// or once the token has expired.
+func ExtensionToken(secret string, data *Lease, expiresOn time.Time) (string, error) {
+	if secret == "" {
+		return "", fmt.Errorf("no lease extension secret is configured")
+	}
+	if data.ID == nil || data.ExpiresOn == nil {
+		return "", fmt.Errorf("lease id and expiresOn are required for an extension token")
+	}
+
+	return fmt.Sprintf("%d.%s", expiresOn.Unix(), extensionMAC(secret, data, expiresOn.Unix())), nil
+}
+
+// IsValidExtensionToken returns true if the token authorizes extending
+// the lease from its current expiresOn date, and the token hasn't expired
+func IsValidExtensionToken(secret string, data *Lease, token string, now time.Time) bool {
+	if secret == "" || data.ID == nil || data.ExpiresOn == nil {
+		return false
+	}
+
+	parts := strings.SplitN(token, ".", 2)
+	if len(parts) != 2 {
+		return false
+	}
+	expiresOn, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || now.Unix() > expiresOn {
+		return false
+	}
+
+	return hmac.Equal([]byte(extensionMAC(secret, data, expiresOn)), []byte(parts[1]))
+}
+
+func extensionMAC(secret string, data *Lease, expiresOn int64) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = fmt.Fprintf(mac, "%s:%d:%d", *data.ID, *data.ExpiresOn, expiresOn)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -11,6 +11,75 @@ type Servicer struct {
 	mock.Mock
 }
 
+// ActivateScheduled provides a mock function with given fields:
+func (_m *Servicer) ActivateScheduled() (*lease.Leases, error) {
+	ret := _m.Called()
+
+	var r0 *lease.Leases
+	if rf, ok := ret.Get(0).(func() *lease.Leases); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*lease.Leases)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AssignPending provides a mock function with given fields:
+func (_m *Servicer) AssignPending() (*lease.Lease, error) {
+	ret := _m.Called()
+
+	var r0 *lease.Lease
+	if rf, ok := ret.Get(0).(func() *lease.Lease); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*lease.Lease)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: data
+func (_m *Servicer) Create(data *lease.Lease) (*lease.Lease, error) {
+	ret := _m.Called(data)
+
+	var r0 *lease.Lease
+	if rf, ok := ret.Get(0).(func(*lease.Lease) *lease.Lease); ok {
+		r0 = rf(data)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*lease.Lease)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*lease.Lease) error); ok {
+		r1 = rf(data)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Delete provides a mock function with given fields: ID
 func (_m *Servicer) Delete(ID string) (*lease.Lease, error) {
 	ret := _m.Called(ID)
@@ -34,6 +103,29 @@ func (_m *Servicer) Delete(ID string) (*lease.Lease, error) {
 	return r0, r1
 }
 
+// ExpiringSoon provides a mock function with given fields: ID, reminderOn
+func (_m *Servicer) ExpiringSoon(ID string, reminderOn int64) (*lease.Lease, error) {
+	ret := _m.Called(ID, reminderOn)
+
+	var r0 *lease.Lease
+	if rf, ok := ret.Get(0).(func(string, int64) *lease.Lease); ok {
+		r0 = rf(ID, reminderOn)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*lease.Lease)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, int64) error); ok {
+		r1 = rf(ID, reminderOn)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Get provides a mock function with given fields: ID
 func (_m *Servicer) Get(ID string) (*lease.Lease, error) {
 	ret := _m.Called(ID)
@@ -93,3 +185,49 @@ func (_m *Servicer) ListPages(query *lease.Lease, fn func(*lease.Leases) bool) e
 
 	return r0
 }
+
+// Orphan provides a mock function with given fields: accountID
+func (_m *Servicer) Orphan(accountID string) (*lease.Leases, error) {
+	ret := _m.Called(accountID)
+
+	var r0 *lease.Leases
+	if rf, ok := ret.Get(0).(func(string) *lease.Leases); ok {
+		r0 = rf(accountID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*lease.Leases)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(accountID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ID, data
+func (_m *Servicer) Update(ID string, data *lease.Lease) (*lease.Lease, error) {
+	ret := _m.Called(ID, data)
+
+	var r0 *lease.Lease
+	if rf, ok := ret.Get(0).(func(string, *lease.Lease) *lease.Lease); ok {
+		r0 = rf(ID, data)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*lease.Lease)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, *lease.Lease) error); ok {
+		r1 = rf(ID, data)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
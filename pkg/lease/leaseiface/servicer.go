@@ -14,9 +14,27 @@ type Servicer interface {
 	//// Save writes the record to the dataSvc
 	//Save(data *lease.Lease) error
 
+	// Create claims a Ready account and creates an Active lease on it
+	Create(data *lease.Lease) (*lease.Lease, error)
+
+	// AssignPending assigns a Ready account to the oldest Pending lease
+	AssignPending() (*lease.Lease, error)
+
+	// ActivateScheduled assigns Ready accounts to Scheduled leases which have started
+	ActivateScheduled() (*lease.Leases, error)
+
+	// Update extends an Active lease's expiration date and/or budget amount
+	Update(ID string, data *lease.Lease) (*lease.Lease, error)
+
+	// ExpiringSoon records an expiry reminder for an Active lease, and publishes an ExpiringSoon event
+	ExpiringSoon(ID string, reminderOn int64) (*lease.Lease, error)
+
 	// Update the Lease record to status Inactive in DynamoDB
 	Delete(ID string) (*lease.Lease, error)
 
+	// Orphan inactivates the Active leases on an orphaned account
+	Orphan(accountID string) (*lease.Leases, error)
+
 	// List Get a list of lease based on Lease ID
 	List(query *lease.Lease) (*lease.Leases, error)
 
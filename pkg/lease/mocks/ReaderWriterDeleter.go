@@ -10,6 +10,20 @@ type ReaderWriterDeleter struct {
 	mock.Mock
 }
 
+// Create provides a mock function with given fields: input
+func (_m *ReaderWriterDeleter) Create(input *lease.Lease) error {
+	ret := _m.Called(input)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*lease.Lease) error); ok {
+		r0 = rf(input)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Delete provides a mock function with given fields: input
 func (_m *ReaderWriterDeleter) Delete(input *lease.Lease) error {
 	ret := _m.Called(input)
@@ -70,6 +84,34 @@ func (_m *ReaderWriterDeleter) List(_a0 *lease.Lease) (*lease.Leases, error) {
 	return r0, r1
 }
 
+// Promote provides a mock function with given fields: pending, input
+func (_m *ReaderWriterDeleter) Promote(pending *lease.Lease, input *lease.Lease) error {
+	ret := _m.Called(pending, input)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*lease.Lease, *lease.Lease) error); ok {
+		r0 = rf(pending, input)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Rollback provides a mock function with given fields: input
+func (_m *ReaderWriterDeleter) Rollback(input *lease.Lease) error {
+	ret := _m.Called(input)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*lease.Lease) error); ok {
+		r0 = rf(input)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Write provides a mock function with given fields: input, lastModifiedOn
 func (_m *ReaderWriterDeleter) Write(input *lease.Lease, lastModifiedOn *int64) error {
 	ret := _m.Called(input, lastModifiedOn)
@@ -5,38 +5,59 @@ import (
 	"strings"
 
 	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/notification"
 	validation "github.com/go-ozzo/ozzo-validation"
 )
 
 // Lease is a type corresponding to a Lease
 // table record
 type Lease struct {
-	AccountID                *string       `json:"accountId,omitempty" dynamodbav:"AccountId" schema:"accountId,omitempty"`                                                        // AWS Account ID
-	PrincipalID              *string       `json:"principalId,omitempty" dynamodbav:"PrincipalId" schema:"principalId,omitempty"`                                                  // Azure User Principal ID
-	ID                       *string       `json:"id,omitempty" dynamodbav:"Id,omitempty" schema:"id,omitempty"`                                                                   // Lease ID
-	Status                   *Status       `json:"leaseStatus,omitempty" dynamodbav:"LeaseStatus,omitempty" schema:"status,omitempty"`                                             // Status of the Lease
-	StatusReason             *StatusReason `json:"leaseStatusReason,omitempty" dynamodbav:"LeaseStatusReason,omitempty" schema:"-"`                                                // Reason for the status of the lease
-	CreatedOn                *int64        `json:"createdOn,omitempty" dynamodbav:"CreatedOn,omitempty" schema:"createdOn,omitempty"`                                              // Created Epoch Timestamp
-	LastModifiedOn           *int64        `json:"lastModifiedOn,omitempty" dynamodbav:"LastModifiedOn,omitempty" schema:"lastModifiedOn,omitempty"`                               // Last Modified Epoch Timestamp
-	BudgetAmount             *float64      `json:"budgetAmount,omitempty" dynamodbav:"BudgetAmount,omitempty" schema:"budgetAmount,omitempty"`                                     // Budget Amount allocated for this lease
-	BudgetCurrency           *string       `json:"budgetCurrency,omitempty" dynamodbav:"BudgetCurrency,omitempty" schema:"budgetCurrency,omitempty"`                               // Budget currency
-	BudgetNotificationEmails *[]string     `json:"budgetNotificationEmails,omitempty" dynamodbav:"BudgetNotificationEmails,omitempty" schema:"budgetNotificationEmails,omitempty"` // Budget notification emails
-	StatusModifiedOn         *int64        `json:"leaseStatusModifiedOn,omitempty" dynamodbav:"LeaseStatusModifiedOn,omitempty" schema:"leaseStatusModifiedOn,omitempty"`          // Last Modified Epoch Timestamp
-	ExpiresOn                *int64        `json:"expiresOn,omitempty" dynamodbav:"ExpiresOn,omitempty" schema:"expiresOn,omitempty"`                                              // Lease expiration time as Epoch
-	Limit                    *int64        `json:"-" dynamodbav:"-" schema:"limit,omitempty"`
-	NextAccountID            *string       `json:"-" dynamodbav:"-" schema:"nextAccountId,omitempty"`
-	NextPrincipalID          *string       `json:"-" dynamodbav:"-" schema:"nextPrincipalId,omitempty"`
+	AccountID                *string                `json:"accountId,omitempty" dynamodbav:"AccountId" schema:"accountId,omitempty"`                                                        // AWS Account ID
+	PrincipalID              *string                `json:"principalId,omitempty" dynamodbav:"PrincipalId" schema:"principalId,omitempty"`                                                  // Azure User Principal ID
+	ID                       *string                `json:"id,omitempty" dynamodbav:"Id,omitempty" schema:"id,omitempty"`                                                                   // Lease ID
+	Status                   *Status                `json:"leaseStatus,omitempty" dynamodbav:"LeaseStatus,omitempty" schema:"status,omitempty"`                                             // Status of the Lease
+	StatusReason             *StatusReason          `json:"leaseStatusReason,omitempty" dynamodbav:"LeaseStatusReason,omitempty" schema:"-"`                                                // Reason for the status of the lease
+	CreatedOn                *int64                 `json:"createdOn,omitempty" dynamodbav:"CreatedOn,omitempty" schema:"createdOn,omitempty"`                                              // Created Epoch Timestamp
+	LastModifiedOn           *int64                 `json:"lastModifiedOn,omitempty" dynamodbav:"LastModifiedOn,omitempty" schema:"lastModifiedOn,omitempty"`                               // Last Modified Epoch Timestamp
+	BudgetAmount             *float64               `json:"budgetAmount,omitempty" dynamodbav:"BudgetAmount,omitempty" schema:"budgetAmount,omitempty"`                                     // Budget Amount allocated for this lease
+	BudgetCurrency           *string                `json:"budgetCurrency,omitempty" dynamodbav:"BudgetCurrency,omitempty" schema:"budgetCurrency,omitempty"`                               // Budget currency
+	BudgetNotificationEmails *[]string              `json:"budgetNotificationEmails,omitempty" dynamodbav:"BudgetNotificationEmails,omitempty" schema:"budgetNotificationEmails,omitempty"` // Budget notification emails
+	NotificationTargets      *[]string              `json:"notificationTargets,omitempty" dynamodbav:"NotificationTargets,omitempty" schema:"-"`                                            // Budget notification webhooks, eg. "slack:https://hooks.slack.com/..."
+	BudgetNotifications      *[]BudgetNotification  `json:"budgetNotifications,omitempty" dynamodbav:"BudgetNotifications,omitempty" schema:"-"`                                            // Budget threshold notifications which have been sent
+	StatusModifiedOn         *int64                 `json:"leaseStatusModifiedOn,omitempty" dynamodbav:"LeaseStatusModifiedOn,omitempty" schema:"leaseStatusModifiedOn,omitempty"`          // Last Modified Epoch Timestamp
+	ExpiresOn                *int64                 `json:"expiresOn,omitempty" dynamodbav:"ExpiresOn,omitempty" schema:"expiresOn,omitempty"`                                              // Lease expiration time as Epoch
+	Metadata                 map[string]interface{} `json:"metadata,omitempty" dynamodbav:"Metadata,omitempty" schema:"-"`                                                                  // Arbitrary key-value metadata to store with lease object
+	StartsOn                 *int64                 `json:"startsOn,omitempty" dynamodbav:"StartsOn,omitempty" schema:"startsOn,omitempty"`                                                 // Lease start time as Epoch, for Scheduled leases
+	Pool                     *string                `json:"pool,omitempty" dynamodbav:"Pool,omitempty" schema:"pool,omitempty"`                                                             // Pool of accounts the lease must be assigned from
+	ExpiryReminderOn         *int64                 `json:"expiryReminderOn,omitempty" dynamodbav:"ExpiryReminderOn,omitempty" schema:"-"`                                                  // Time the last expiry reminder was due, as Epoch
+	QueuePosition            *int64                 `json:"queuePosition,omitempty" dynamodbav:"-" schema:"-"`                                                                              // Position in the waitlist of a Pending lease, starting at 1
+	Limit                    *int64                 `json:"-" dynamodbav:"-" schema:"limit,omitempty"`
+	NextAccountID            *string                `json:"-" dynamodbav:"-" schema:"nextAccountId,omitempty"`
+	NextPrincipalID          *string                `json:"-" dynamodbav:"-" schema:"nextPrincipalId,omitempty"`
 }
 
 // Validate the lease data
 func (l *Lease) Validate() error {
+	leaseID := ""
+	if l.ID != nil {
+		leaseID = *l.ID
+	}
+	accountIDRules := validateAccountID
+	if l.Status != nil && *l.Status == StatusPending {
+		accountIDRules = validatePlaceholderAccountID(PendingAccountID(leaseID))
+	}
+	if l.Status != nil && *l.Status == StatusScheduled {
+		accountIDRules = validatePlaceholderAccountID(ScheduledAccountID(leaseID))
+	}
+
 	err := validation.ValidateStruct(l,
 		validation.Field(&l.ID, validateID...),
-		validation.Field(&l.AccountID, validateAccountID...),
+		validation.Field(&l.AccountID, accountIDRules...),
 		validation.Field(&l.PrincipalID, validatePrincipalID...),
 		validation.Field(&l.LastModifiedOn, validateInt64...),
 		validation.Field(&l.Status, validateStatus...),
 		validation.Field(&l.CreatedOn, validateInt64...),
+		validation.Field(&l.Pool, validatePool...),
 	)
 	if err != nil {
 		return errors.NewValidation("lease", err)
@@ -44,9 +65,58 @@ func (l *Lease) Validate() error {
 	return nil
 }
 
+// overlaps returns true if the lease period overlaps with the other lease's period.
+// Leases without a startsOn date have already started, and leases without an
+// expiresOn date never end.
+func (l *Lease) overlaps(other *Lease) bool {
+	start, otherStart := int64(0), int64(0)
+	if l.StartsOn != nil {
+		start = *l.StartsOn
+	}
+	if other.StartsOn != nil {
+		otherStart = *other.StartsOn
+	}
+	if l.ExpiresOn != nil && *l.ExpiresOn <= otherStart {
+		return false
+	}
+	if other.ExpiresOn != nil && *other.ExpiresOn <= start {
+		return false
+	}
+	return true
+}
+
+// Redacted returns a copy of the lease for API responses, with the
+// webhook URLs of its notification targets hidden, as they are secrets
+func (l Lease) Redacted() *Lease {
+	if l.NotificationTargets != nil {
+		targets := notification.RedactTargets(*l.NotificationTargets)
+		l.NotificationTargets = &targets
+	}
+	return &l
+}
+
 // Leases is a list of type Lease
 type Leases []Lease
 
+// Redacted returns a copy of the leases for API responses
+func (l Leases) Redacted() *Leases {
+	redacted := make(Leases, len(l))
+	for i, ls := range l {
+		redacted[i] = *ls.Redacted()
+	}
+	return &redacted
+}
+
+// BudgetNotification records a budget threshold notification sent for a lease,
+// so the same threshold isn't notified twice
+type BudgetNotification struct {
+	Type                *string  `json:"type,omitempty" dynamodbav:"Type,omitempty"`
+	ThresholdPercentile *float64 `json:"thresholdPercentile,omitempty" dynamodbav:"ThresholdPercentile,omitempty"`
+	BudgetAmount        *float64 `json:"budgetAmount,omitempty" dynamodbav:"BudgetAmount,omitempty"`
+	ActualSpend         *float64 `json:"actualSpend,omitempty" dynamodbav:"ActualSpend,omitempty"`
+	SentOn              *int64   `json:"sentOn,omitempty" dynamodbav:"SentOn,omitempty"`
+}
+
 // Status is a lease status type
 type Status string
 
@@ -57,8 +127,26 @@ const (
 	StatusActive Status = "Active"
 	// StatusInactive status
 	StatusInactive Status = "Inactive"
+	// StatusPending status
+	StatusPending Status = "Pending"
+	// StatusScheduled status
+	StatusScheduled Status = "Scheduled"
 )
 
+// PendingAccountID returns the AccountID stored for a Pending lease, until
+// an account is assigned. AccountId is the hash key of the Leases table,
+// so it can't be left empty. It includes the lease ID, so a principal
+// may have more than one Pending lease.
+func PendingAccountID(leaseID string) string {
+	return "pending#" + leaseID
+}
+
+// ScheduledAccountID returns the AccountID stored for a Scheduled lease,
+// until the lease starts and an account is assigned.
+func ScheduledAccountID(leaseID string) string {
+	return "scheduled#" + leaseID
+}
+
 // String returns the string value of Status
 func (c Status) String() string {
 	return string(c)
@@ -83,6 +171,10 @@ func ParseStatus(status string) (Status, error) {
 		return StatusActive, nil
 	case "inactive":
 		return StatusInactive, nil
+	case "pending":
+		return StatusPending, nil
+	case "scheduled":
+		return StatusScheduled, nil
 	}
 	return StatusEmpty, fmt.Errorf("Cannot parse value %s", status)
 }
@@ -97,10 +189,17 @@ const (
 	StatusReasonOverBudget StatusReason = "OverBudget"
 	// StatusReasonOverPrincipalBudget means the lease is over its principal budgeted amount and is therefore reset/reclaimed.
 	StatusReasonOverPrincipalBudget StatusReason = "OverPrincipalBudget"
+	// StatusReasonForecastOverBudget means the lease is forecasted to exceed its budget before it expires
+	// and is therefore reset/reclaimed.
+	StatusReasonForecastOverBudget StatusReason = "ForecastOverBudget"
 	// StatusReasonDestroyed means the lease has been deleted via an API call or other user action.
 	StatusReasonDestroyed StatusReason = "Destroyed"
 	// StatusReasonActive means the lease is still active.
 	StatusReasonActive StatusReason = "Active"
+	// StatusReasonPending means the lease is waiting in line for an account to become Ready.
+	StatusReasonPending StatusReason = "Pending"
+	// StatusReasonScheduled means the lease is waiting for its startsOn date.
+	StatusReasonScheduled StatusReason = "Scheduled"
 	// StatusReasonRolledBack means something happened in the system that caused the lease to be inactive
 	// based on an error happening and rollback occuring
 	StatusReasonRolledBack StatusReason = "Rollback"
@@ -114,3 +213,19 @@ func (c StatusReason) StatusReasonPtr() *StatusReason {
 	v := c
 	return &v
 }
+
+// Event is the type of a lease event, for events which are not
+// a change to the lease record
+type Event string
+
+const (
+	// EventExpiringSoon is published when an expiry reminder is sent for an Active lease
+	EventExpiringSoon Event = "ExpiringSoon"
+)
+
+// EventMessage is a lease event. The lease fields are included at the top
+// level, so subscribers to lease updates may read it as a Lease.
+type EventMessage struct {
+	Lease
+	Event Event `json:"event"`
+}
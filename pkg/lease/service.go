@@ -1,10 +1,17 @@
 package lease
 
 import (
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
 	"time"
 
+	"github.com/Optum/dce/pkg/account"
 	"github.com/Optum/dce/pkg/errors"
 	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/google/uuid"
+	"github.com/imdario/mergo"
 )
 
 // Writer put an item into the data store
@@ -28,21 +35,37 @@ type Reader interface {
 	MultipleReader
 }
 
-// ReaderWriterDeleter includes Reader and Writer interfaces
+// Creator writes a new lease and claims its account in a single transaction
+type Creator interface {
+	Create(input *Lease) error
+	Promote(pending *Lease, input *Lease) error
+	Rollback(input *Lease) error
+}
+
+// ReaderWriter includes Reader, Writer and Creator interfaces
 type ReaderWriter interface {
 	Reader
 	Writer
+	Creator
+}
+
+// AccountLister lists accounts, used to find accounts which are Ready to be leased
+type AccountLister interface {
+	List(query *account.Account) (*account.Accounts, error)
 }
 
 // Eventer for publishing events
 type Eventer interface {
-	Publish() error
+	LeaseCreate(i interface{}) error
+	LeaseEnd(i interface{}) error
+	LeaseUpdate(i interface{}) error
 }
 
 // Service is a type corresponding to a Lease table record
 type Service struct {
-	dataSvc  ReaderWriter
-	eventSvc Eventer
+	dataSvc    ReaderWriter
+	accountSvc AccountLister
+	eventSvc   Eventer
 }
 
 // Get returns a lease from ID
@@ -53,6 +76,20 @@ func (a *Service) Get(ID string) (*Lease, error) {
 		return nil, err
 	}
 
+	if new.Status != nil && *new.Status == StatusPending {
+		pending, err := a.listByStatus(StatusPending)
+		if err != nil {
+			return nil, err
+		}
+		for i, l := range inPool(pending, new.Pool) {
+			if *l.ID == ID {
+				position := int64(i + 1)
+				new.QueuePosition = &position
+				break
+			}
+		}
+	}
+
 	return new, err
 }
 
@@ -80,9 +117,507 @@ func (a *Service) Save(data *Lease) error {
 	return nil
 }
 
-// Delete finds a given lease and checks if it's active and then updates it to status `Inactive`. Returns the lease.
-func (a *Service) Delete(ID string) (*Lease, error) {
+// Create claims a Ready account and creates an Active lease on it for the principal.
+// The account claim and the lease record are written in a single transaction,
+// so an account can never be claimed by two leases at once.
+// If no account is Ready, or other requests are already waiting, the lease is
+// created as Pending, and is activated later by AssignPending.
+// Accounts reserved for Scheduled leases which start before the lease expires
+// aren't assigned.
+// If startsOn is set, the lease is created as Scheduled instead, and is
+// activated later by ActivateScheduled.
+// If a pool is set, only accounts in that pool are assigned to the lease.
+// Otherwise, only accounts which are not in a pool are assigned.
+func (a *Service) Create(data *Lease) (*Lease, error) {
+	now := time.Now().Unix()
+	err := validation.ValidateStruct(data,
+		// The account and lease status are assigned here
+		validation.Field(&data.ID, validation.By(isNil)),
+		validation.Field(&data.AccountID, validation.By(isNil)),
+		validation.Field(&data.PrincipalID, validatePrincipalID...),
+		validation.Field(&data.Status, validation.By(isNil)),
+		validation.Field(&data.StatusReason, validation.By(isNil)),
+		validation.Field(&data.CreatedOn, validation.By(isNil)),
+		validation.Field(&data.LastModifiedOn, validation.By(isNil)),
+		validation.Field(&data.StatusModifiedOn, validation.By(isNil)),
+		validation.Field(&data.StartsOn, validation.By(isNilOrInFuture(now))),
+		validation.Field(&data.ExpiresOn, validation.By(isNilOrAfter(data.StartsOn))),
+		validation.Field(&data.Pool, validatePool...),
+	)
+	if err != nil {
+		return nil, errors.NewValidation("lease", err)
+	}
+
+	if data.Pool != nil {
+		capacity, err := a.countAccounts(data.Pool)
+		if err != nil {
+			return nil, err
+		}
+		if capacity == 0 {
+			return nil, errors.NewValidation("lease", fmt.Errorf("pool: there are no accounts in pool %q", *data.Pool))
+		}
+	}
+
+	if data.StartsOn != nil {
+		return a.schedule(data, now)
+	}
+
+	// Wait in line behind any earlier requests for the same pool
+	pending, err := a.listByStatus(StatusPending)
+	if err != nil {
+		return nil, err
+	}
+	pending = inPool(pending, data.Pool)
+
+	accounts := account.Accounts{}
+	if len(pending) == 0 {
+		// Don't take an account which is reserved for a Scheduled lease
+		capacity, reserved, err := a.reservations(data, false)
+		if err != nil {
+			return nil, err
+		}
+		if reserved == 0 || reserved < capacity {
+			accounts, err = a.listReadyAccounts(data.Pool)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	id := uuid.New().String()
+	data.ID = &id
+	data.Status = StatusActive.StatusPtr()
+	data.StatusReason = StatusReasonActive.StatusReasonPtr()
+	data.CreatedOn = &now
+	data.LastModifiedOn = &now
+	data.StatusModifiedOn = &now
+
+	// Try each Ready account in turn, in case another request claims it first
+	for _, acct := range accounts {
+		data.AccountID = acct.ID
+		err = data.Validate()
+		if err != nil {
+			return nil, err
+		}
+
+		err = a.dataSvc.Create(data)
+		if errors.HTTPCodeForError(err) == http.StatusConflict {
+			log.Printf("Account %q was claimed by another request: %s", *acct.ID, err)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		err = a.eventSvc.LeaseCreate(data)
+		if err != nil {
+			// Release the account, so we don't leave an Active lease
+			// nobody was told about
+			rollbackErr := a.dataSvc.Rollback(data)
+			if rollbackErr != nil {
+				log.Printf("Failed to rollback lease %q for account %q: %s", *data.ID, *data.AccountID, rollbackErr)
+			}
+			return nil, err
+		}
+
+		return data, nil
+	}
+
+	// No account is available, so add the lease to the waitlist
+	accountID := PendingAccountID(id)
+	data.AccountID = &accountID
+	data.Status = StatusPending.StatusPtr()
+	data.StatusReason = StatusReasonPending.StatusReasonPtr()
+	err = data.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.dataSvc.Create(data)
+	if err != nil {
+		return nil, err
+	}
+	position := int64(len(pending) + 1)
+	data.QueuePosition = &position
+
+	return data, nil
+}
+
+// schedule reserves an account for a lease which starts in the future.
+// Returns a conflict error if every account in the lease's pool is already
+// reserved by Active or Scheduled leases which overlap the requested period,
+// or by Pending leases.
+func (a *Service) schedule(data *Lease, now int64) (*Lease, error) {
+	capacity, reserved, err := a.reservations(data, true)
+	if err != nil {
+		return nil, err
+	}
+	if reserved >= capacity {
+		return nil, errors.NewConflict("lease", *data.PrincipalID,
+			fmt.Errorf("all %d accounts are reserved for the requested lease period", capacity))
+	}
+
+	id := uuid.New().String()
+	accountID := ScheduledAccountID(id)
+	data.ID = &id
+	data.AccountID = &accountID
+	data.Status = StatusScheduled.StatusPtr()
+	data.StatusReason = StatusReasonScheduled.StatusReasonPtr()
+	data.CreatedOn = &now
+	data.LastModifiedOn = &now
+	data.StatusModifiedOn = &now
+	err = data.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.dataSvc.Create(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// AssignPending assigns a Ready account to the oldest Pending lease which
+// can be served, and activates it. Leases waiting on an empty pool don't hold
+// up leases for other pools. Accounts reserved for Scheduled leases aren't assigned.
+// Returns nil when there are no Pending leases, or no accounts are Ready.
+func (a *Service) AssignPending() (*Lease, error) {
+	pending, err := a.listByStatus(StatusPending)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the oldest lease for each pool may be assigned
+	tried := map[string]bool{}
+	for _, next := range pending {
+		pool := ""
+		if next.Pool != nil {
+			pool = *next.Pool
+		}
+		if tried[pool] {
+			continue
+		}
+		tried[pool] = true
+
+		// Don't count time spent waiting against the lease period
+		expiresOn := next.ExpiresOn
+		if next.ExpiresOn != nil && next.CreatedOn != nil {
+			shifted := *next.ExpiresOn + (time.Now().Unix() - *next.CreatedOn)
+			expiresOn = &shifted
+		}
+
+		// Don't take an account which is reserved for a Scheduled lease
+		period := next
+		period.ExpiresOn = expiresOn
+		capacity, reserved, err := a.reservations(&period, false)
+		if err != nil {
+			return nil, err
+		}
+		if reserved > 0 && reserved >= capacity {
+			log.Printf("All %d accounts are reserved during pending lease %q", capacity, *next.ID)
+			continue
+		}
+
+		assigned, err := a.activate(next, expiresOn)
+		if err != nil {
+			return nil, err
+		}
+		if assigned != nil {
+			return assigned, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// ActivateScheduled assigns a Ready account to each Scheduled lease whose
+// startsOn date has passed, and activates it. Returns the activated leases.
+// Leases are left Scheduled if no account is Ready, to be retried later.
+func (a *Service) ActivateScheduled() (*Leases, error) {
+	scheduled, err := a.listByStatus(StatusScheduled)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	activated := Leases{}
+	for _, l := range scheduled {
+		if l.StartsOn == nil || *l.StartsOn > now {
+			continue
+		}
+
+		data, err := a.activate(l, l.ExpiresOn)
+		if err != nil {
+			return &activated, err
+		}
+		if data == nil {
+			log.Printf("No accounts are ready for scheduled lease %q", *l.ID)
+			continue
+		}
+		activated = append(activated, *data)
+	}
+
+	return &activated, nil
+}
+
+// activate replaces a waiting lease with an Active lease on a Ready account.
+// Returns nil if no account could be claimed.
+func (a *Service) activate(waiting Lease, expiresOn *int64) (*Lease, error) {
+	accounts, err := a.listReadyAccounts(waiting.Pool)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	for _, acct := range accounts {
+		data := waiting
+		data.AccountID = acct.ID
+		data.Status = StatusActive.StatusPtr()
+		data.StatusReason = StatusReasonActive.StatusReasonPtr()
+		data.LastModifiedOn = &now
+		data.StatusModifiedOn = &now
+		data.ExpiresOn = expiresOn
+		err = data.Validate()
+		if err != nil {
+			return nil, err
+		}
+
+		err = a.dataSvc.Promote(&waiting, &data)
+		if errors.HTTPCodeForError(err) == http.StatusConflict {
+			log.Printf("Unable to assign account %q to lease %q: %s", *acct.ID, *waiting.ID, err)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		err = a.eventSvc.LeaseCreate(&data)
+		if err != nil {
+			rollbackErr := a.dataSvc.Rollback(&data)
+			if rollbackErr != nil {
+				log.Printf("Failed to rollback lease %q for account %q: %s", *data.ID, *data.AccountID, rollbackErr)
+			}
+			return nil, err
+		}
+
+		return &data, nil
+	}
+
+	return nil, nil
+}
+
+// listByStatus returns all leases with the given status, oldest first
+func (a *Service) listByStatus(status Status) (Leases, error) {
+	all := Leases{}
+	query := &Lease{
+		Status: status.StatusPtr(),
+	}
+	for {
+		leases, err := a.dataSvc.List(query)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, *leases...)
+		if query.NextAccountID == nil || query.NextPrincipalID == nil {
+			break
+		}
+	}
+
+	sort.SliceStable(all, func(i, j int) bool {
+		if all[i].CreatedOn == nil || all[j].CreatedOn == nil {
+			return all[j].CreatedOn != nil
+		}
+		return *all[i].CreatedOn < *all[j].CreatedOn
+	})
+	return all, nil
+}
 
+// listReadyAccounts returns the Ready accounts in the pool.
+// Accounts which are in a pool are only returned for that pool.
+// Every page is read, as page limits apply before the Status filter,
+// so a page may have no Ready accounts, even though later pages do.
+func (a *Service) listReadyAccounts(pool *string) (account.Accounts, error) {
+	ready := account.Accounts{}
+	query := &account.Account{
+		Status: account.StatusReady.StatusPtr(),
+		Pool:   pool,
+	}
+	for {
+		accounts, err := a.accountSvc.List(query)
+		if err != nil {
+			return nil, err
+		}
+		for _, acct := range *accounts {
+			if pool == nil && acct.Pool != nil {
+				continue
+			}
+			ready = append(ready, acct)
+		}
+		if query.NextID == nil {
+			break
+		}
+	}
+	return ready, nil
+}
+
+// inPool returns the leases for the given pool
+func inPool(leases Leases, pool *string) Leases {
+	matched := Leases{}
+	for _, l := range leases {
+		if (l.Pool == nil && pool == nil) || (l.Pool != nil && pool != nil && *l.Pool == *pool) {
+			matched = append(matched, l)
+		}
+	}
+	return matched
+}
+
+// reservations returns the number of accounts in the pool of the lease which
+// may be leased, and how many of them are reserved during the lease period,
+// by Active leases and by other Scheduled leases.
+// Pending leases take the next account which is Ready, so if countPending is set,
+// each Pending lease in the pool reserves an account for the whole period.
+func (a *Service) reservations(data *Lease, countPending bool) (int, int, error) {
+	capacity, err := a.countAccounts(data.Pool)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	statuses := []Status{StatusActive, StatusScheduled}
+	if countPending {
+		statuses = append(statuses, StatusPending)
+	}
+
+	reserved := 0
+	for _, status := range statuses {
+		leases, err := a.listByStatus(status)
+		if err != nil {
+			return 0, 0, err
+		}
+		for _, l := range inPool(leases, data.Pool) {
+			if data.ID != nil && l.ID != nil && *l.ID == *data.ID {
+				continue
+			}
+			if status == StatusPending || l.overlaps(data) {
+				reserved++
+			}
+		}
+	}
+	return capacity, reserved, nil
+}
+
+// countAccounts returns the number of accounts in the pool which may be leased
+func (a *Service) countAccounts(pool *string) (int, error) {
+	count := 0
+	query := &account.Account{
+		Pool: pool,
+	}
+	for {
+		accounts, err := a.accountSvc.List(query)
+		if err != nil {
+			return 0, err
+		}
+		for _, acct := range *accounts {
+			if pool == nil && acct.Pool != nil {
+				continue
+			}
+			if acct.Status == nil {
+				count++
+				continue
+			}
+			switch *acct.Status {
+			case account.StatusOrphaned, account.StatusRetiring, account.StatusRetired:
+			default:
+				count++
+			}
+		}
+		if query.NextID == nil {
+			break
+		}
+	}
+	return count, nil
+}
+
+// Update extends an Active lease, by moving out its expiration date
+// and/or raising its budget amount, and/or replaces its notification targets.
+// Returns the updated lease.
+func (a *Service) Update(ID string, data *Lease) (*Lease, error) {
+	err := validation.ValidateStruct(data,
+		// Only the expiration and budget may be changed
+		validation.Field(&data.ID, validation.NilOrNotEmpty, validation.In(ID)),
+		validation.Field(&data.AccountID, validation.By(isNil)),
+		validation.Field(&data.PrincipalID, validation.By(isNil)),
+		validation.Field(&data.Status, validation.By(isNil)),
+		validation.Field(&data.StatusReason, validation.By(isNil)),
+		validation.Field(&data.CreatedOn, validation.By(isNil)),
+		validation.Field(&data.LastModifiedOn, validation.By(isNil)),
+		validation.Field(&data.StatusModifiedOn, validation.By(isNil)),
+		validation.Field(&data.BudgetCurrency, validation.By(isNil)),
+		validation.Field(&data.BudgetNotificationEmails, validation.By(isNil)),
+		validation.Field(&data.BudgetNotifications, validation.By(isNil)),
+		validation.Field(&data.ExpiryReminderOn, validation.By(isNil)),
+		validation.Field(&data.Metadata, validation.By(isNil)),
+		validation.Field(&data.StartsOn, validation.By(isNil)),
+		validation.Field(&data.Pool, validation.By(isNil)),
+		validation.Field(&data.QueuePosition, validation.By(isNil)),
+	)
+	if err != nil {
+		return nil, errors.NewValidation("lease", err)
+	}
+
+	if data.ExpiresOn == nil && data.BudgetAmount == nil && data.NotificationTargets == nil {
+		return nil, errors.NewValidation("lease",
+			fmt.Errorf("expiresOn, budgetAmount or notificationTargets must be provided"))
+	}
+
+	lease, err := a.dataSvc.Get(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validation.ValidateStruct(lease,
+		validation.Field(&lease.Status, validation.NotNil, validation.By(isLeaseActive)),
+	)
+	if err != nil {
+		return nil, errors.NewConflict("lease", ID, err)
+	}
+
+	err = validation.ValidateStruct(data,
+		validation.Field(&data.ExpiresOn, validation.By(isNilOrNotBefore(lease.ExpiresOn))),
+		validation.Field(&data.BudgetAmount, validation.By(isNilOrNotLessThan(lease.BudgetAmount))),
+	)
+	if err != nil {
+		return nil, errors.NewValidation("lease", err)
+	}
+
+	err = mergo.Merge(lease, *data, mergo.WithOverride)
+	if err != nil {
+		return nil, errors.NewInternalServer("unexpected error updating lease", err)
+	}
+	// Set explicitly, so an empty list removes the targets
+	if data.NotificationTargets != nil {
+		lease.NotificationTargets = data.NotificationTargets
+	}
+
+	err = a.Save(lease)
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.eventSvc.LeaseUpdate(lease)
+	if err != nil {
+		return nil, err
+	}
+
+	return lease, nil
+}
+
+// ExpiringSoon records that an expiry reminder was sent for an Active lease,
+// and publishes an ExpiringSoon event. reminderOn is the time the reminder was due,
+// so each reminder is only sent once for the lease's current expiresOn date.
+// Returns the updated lease.
+func (a *Service) ExpiringSoon(ID string, reminderOn int64) (*Lease, error) {
 	data, err := a.dataSvc.Get(ID)
 	if err != nil {
 		return nil, err
@@ -92,7 +627,43 @@ func (a *Service) Delete(ID string) (*Lease, error) {
 		validation.Field(&data.Status, validation.NotNil, validation.By(isLeaseActive)),
 	)
 	if err != nil {
-		return nil, errors.NewConflict("lease", *data.ID, err)
+		return nil, errors.NewConflict("lease", ID, err)
+	}
+
+	data.ExpiryReminderOn = &reminderOn
+	err = a.Save(data)
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.eventSvc.LeaseUpdate(&EventMessage{
+		Lease: *data,
+		Event: EventExpiringSoon,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Delete finds a given lease and checks if it's active and then updates it to status `Inactive`. Returns the lease.
+func (a *Service) Delete(ID string) (*Lease, error) {
+
+	data, err := a.dataSvc.Get(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Pending and Scheduled leases may also be deleted, to leave
+	// the waitlist or cancel the reservation
+	if data.Status == nil || (*data.Status != StatusPending && *data.Status != StatusScheduled) {
+		err = validation.ValidateStruct(data,
+			validation.Field(&data.Status, validation.NotNil, validation.By(isLeaseActive)),
+		)
+		if err != nil {
+			return nil, errors.NewConflict("lease", *data.ID, err)
+		}
 	}
 
 	data.Status = StatusInactive.StatusPtr()
@@ -104,6 +675,39 @@ func (a *Service) Delete(ID string) (*Lease, error) {
 	return data, nil
 }
 
+// Orphan inactivates the Active leases on an account which has been orphaned.
+// Returns the leases which were inactivated.
+// Leases are queried by account, the table key, so only the account's leases are read.
+func (a *Service) Orphan(accountID string) (*Leases, error) {
+	query := &Lease{
+		AccountID: &accountID,
+		Status:    StatusActive.StatusPtr(),
+	}
+
+	orphaned := Leases{}
+	for {
+		leases, err := a.dataSvc.List(query)
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range *leases {
+			l := l
+			l.Status = StatusInactive.StatusPtr()
+			l.StatusReason = StatusReasonAccountOrphaned.StatusReasonPtr()
+			err = a.Save(&l)
+			if err != nil {
+				return nil, err
+			}
+			orphaned = append(orphaned, l)
+		}
+		if query.NextAccountID == nil || query.NextPrincipalID == nil {
+			break
+		}
+	}
+
+	return &orphaned, nil
+}
+
 // List Get a list of leases based on Principal ID
 func (a *Service) List(query *Lease) (*Leases, error) {
 	err := validation.ValidateStruct(query,
@@ -133,7 +737,7 @@ func (a *Service) ListPages(query *Lease, fn func(*Leases) bool) error {
 		if !fn(records) {
 			break
 		}
-		if query.PrincipalID == nil {
+		if query.NextAccountID == nil || query.NextPrincipalID == nil {
 			break
 		}
 	}
@@ -143,14 +747,16 @@ func (a *Service) ListPages(query *Lease, fn func(*Leases) bool) error {
 
 // NewServiceInput Input for creating a new Service
 type NewServiceInput struct {
-	DataSvc  ReaderWriter
-	EventSvc Eventer
+	DataSvc    ReaderWriter
+	AccountSvc AccountLister
+	EventSvc   Eventer
 }
 
 // NewService creates a new instance of the Service
 func NewService(input NewServiceInput) *Service {
 	return &Service{
-		dataSvc:  input.DataSvc,
-		eventSvc: input.EventSvc,
+		dataSvc:    input.DataSvc,
+		accountSvc: input.AccountSvc,
+		eventSvc:   input.EventSvc,
 	}
 }
@@ -2,9 +2,11 @@ package lease_test
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/Optum/dce/pkg/account"
 	"github.com/Optum/dce/pkg/errors"
 	"github.com/Optum/dce/pkg/lease"
 	"github.com/Optum/dce/pkg/lease/mocks"
@@ -18,6 +20,24 @@ func ptrString(s string) *string {
 	return &ptrS
 }
 
+// mockListByStatus mocks listing the leases with each status.
+// A nil list is returned as empty.
+func mockListByStatus(mocksRwd *mocks.ReaderWriterDeleter, pending, active, scheduled *lease.Leases) {
+	for status, leases := range map[lease.Status]*lease.Leases{
+		lease.StatusPending:   pending,
+		lease.StatusActive:    active,
+		lease.StatusScheduled: scheduled,
+	} {
+		if leases == nil {
+			leases = &lease.Leases{}
+		}
+		status := status
+		mocksRwd.On("List", mock.MatchedBy(func(query *lease.Lease) bool {
+			return query.Status != nil && *query.Status == status
+		})).Return(leases, nil)
+	}
+}
+
 func TestGetLeaseByID(t *testing.T) {
 
 	type response struct {
@@ -98,6 +118,17 @@ func TestDelete(t *testing.T) {
 			},
 			returnErr: nil,
 		},
+		{
+			name: "should delete a pending lease",
+			ID:   "70c2d96d-7938-4ec9-917d-476f2b09cc04",
+			expLease: &lease.Lease{
+				ID:           ptrString("70c2d96d-7938-4ec9-917d-476f2b09cc04"),
+				AccountID:    ptrString(lease.PendingAccountID("70c2d96d-7938-4ec9-917d-476f2b09cc04")),
+				Status:       lease.StatusPending.StatusPtr(),
+				StatusReason: lease.StatusReasonPending.StatusReasonPtr(),
+			},
+			returnErr: nil,
+		},
 		{
 			name:      "should error when delete fails",
 			ID:        "70c2d96d-7938-4ec9-917d-476f2b09cc04",
@@ -129,6 +160,1270 @@ func TestDelete(t *testing.T) {
 	}
 }
 
+func TestOrphan(t *testing.T) {
+	now := time.Now().Unix()
+
+	tests := []struct {
+		name     string
+		leases   *lease.Leases
+		listErr  error
+		writeErr error
+		expCount int
+		expErr   error
+	}{
+		{
+			name: "should inactivate active leases on the account",
+			leases: &lease.Leases{
+				{
+					ID:             ptrString("70c2d96d-7938-4ec9-917d-476f2b09cc04"),
+					Status:         lease.StatusActive.StatusPtr(),
+					AccountID:      ptrString("123456789012"),
+					PrincipalID:    ptrString("User1"),
+					CreatedOn:      &now,
+					LastModifiedOn: &now,
+				},
+			},
+			expCount: 1,
+		},
+		{
+			name:     "should succeed when account has no active leases",
+			leases:   &lease.Leases{},
+			expCount: 0,
+		},
+		{
+			name:    "should error when list fails",
+			listErr: errors.NewInternalServer("failure", fmt.Errorf("original failure")),
+			expErr:  errors.NewInternalServer("failure", nil),
+		},
+		{
+			name: "should error when write fails",
+			leases: &lease.Leases{
+				{
+					ID:             ptrString("70c2d96d-7938-4ec9-917d-476f2b09cc04"),
+					Status:         lease.StatusActive.StatusPtr(),
+					AccountID:      ptrString("123456789012"),
+					PrincipalID:    ptrString("User1"),
+					CreatedOn:      &now,
+					LastModifiedOn: &now,
+				},
+			},
+			writeErr: errors.NewInternalServer("failure", fmt.Errorf("original failure")),
+			expErr:   errors.NewInternalServer("failure", nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocksRwd := &mocks.ReaderWriterDeleter{}
+			mocksRwd.On("List", mock.MatchedBy(func(q *lease.Lease) bool {
+				return *q.AccountID == "123456789012" && *q.Status == lease.StatusActive
+			})).Return(tt.leases, tt.listErr)
+			mocksRwd.On("Write", mock.AnythingOfType("*lease.Lease"), mock.AnythingOfType("*int64")).
+				Return(tt.writeErr)
+
+			leaseSvc := lease.NewService(
+				lease.NewServiceInput{
+					DataSvc: mocksRwd,
+				},
+			)
+			orphaned, err := leaseSvc.Orphan("123456789012")
+			assert.True(t, errors.Is(err, tt.expErr), "actual error %q doesn't match expected error %q", err, tt.expErr)
+			if tt.expErr == nil {
+				assert.Len(t, *orphaned, tt.expCount)
+				for _, l := range *orphaned {
+					assert.Equal(t, lease.StatusInactive, *l.Status)
+					assert.Equal(t, lease.StatusReasonAccountOrphaned, *l.StatusReason)
+				}
+			}
+		})
+	}
+}
+
+func TestGetPendingLeaseQueuePosition(t *testing.T) {
+	mocksRwd := &mocks.ReaderWriterDeleter{}
+
+	mocksRwd.On("Get", "70c2d96d-7938-4ec9-917d-476f2b09cc04").Return(&lease.Lease{
+		ID:        ptrString("70c2d96d-7938-4ec9-917d-476f2b09cc04"),
+		Status:    lease.StatusPending.StatusPtr(),
+		CreatedOn: aws.Int64(1573592060),
+	}, nil)
+	mocksRwd.On("List", mock.AnythingOfType("*lease.Lease")).Return(&lease.Leases{
+		{ID: ptrString("70c2d96d-7938-4ec9-917d-476f2b09cc04"), CreatedOn: aws.Int64(1573592060)},
+		{ID: ptrString("a8f6bde2-5c4a-4d2f-9a57-0f07a2b1f0c1"), CreatedOn: aws.Int64(1573592070)},
+		{ID: ptrString("1f2d7a0c-9e0b-4c55-8a52-6a3c5e7a2f10"), CreatedOn: aws.Int64(1573592050)},
+	}, nil)
+
+	leaseSvc := lease.NewService(lease.NewServiceInput{
+		DataSvc: mocksRwd,
+	})
+
+	getLease, err := leaseSvc.Get("70c2d96d-7938-4ec9-917d-476f2b09cc04")
+	assert.Nil(t, err)
+	assert.Equal(t, aws.Int64(2), getLease.QueuePosition)
+}
+
+func TestCreate(t *testing.T) {
+
+	type response struct {
+		data *lease.Lease
+		err  error
+	}
+
+	readyAccounts := &account.Accounts{
+		{ID: ptrString("123456789012")},
+		{ID: ptrString("210987654321")},
+	}
+
+	tests := []struct {
+		name         string
+		input        *lease.Lease
+		pending      *lease.Leases
+		active       *lease.Leases
+		scheduled    *lease.Leases
+		accounts     *account.Accounts
+		accountsErr  error
+		createErrs   []error
+		eventErr     error
+		expAccountID string
+		expRollback  bool
+		exp          response
+	}{
+		{
+			name: "should create a lease on the first ready account",
+			input: &lease.Lease{
+				PrincipalID:  ptrString("User1"),
+				BudgetAmount: aws.Float64(100),
+			},
+			accounts:     readyAccounts,
+			createErrs:   []error{nil},
+			expAccountID: "123456789012",
+			exp: response{
+				data: &lease.Lease{
+					AccountID:    ptrString("123456789012"),
+					PrincipalID:  ptrString("User1"),
+					Status:       lease.StatusActive.StatusPtr(),
+					BudgetAmount: aws.Float64(100),
+				},
+			},
+		},
+		{
+			name: "should move on to the next account when one is claimed by another request",
+			input: &lease.Lease{
+				PrincipalID: ptrString("User1"),
+			},
+			accounts: readyAccounts,
+			createErrs: []error{
+				errors.NewConflict("lease", "123456789012", fmt.Errorf("unable to create lease: account is no longer available")),
+				nil,
+			},
+			expAccountID: "210987654321",
+			exp: response{
+				data: &lease.Lease{
+					AccountID:   ptrString("210987654321"),
+					PrincipalID: ptrString("User1"),
+					Status:      lease.StatusActive.StatusPtr(),
+				},
+			},
+		},
+		{
+			name: "should queue the lease when there are no ready accounts",
+			input: &lease.Lease{
+				PrincipalID: ptrString("User1"),
+			},
+			accounts:   &account.Accounts{},
+			createErrs: []error{nil},
+			exp: response{
+				data: &lease.Lease{
+					PrincipalID:   ptrString("User1"),
+					Status:        lease.StatusPending.StatusPtr(),
+					QueuePosition: aws.Int64(1),
+				},
+			},
+		},
+		{
+			name: "should queue the lease when every ready account is claimed",
+			input: &lease.Lease{
+				PrincipalID: ptrString("User1"),
+			},
+			accounts: readyAccounts,
+			createErrs: []error{
+				errors.NewConflict("lease", "123456789012", fmt.Errorf("unable to create lease: account is no longer available")),
+				errors.NewConflict("lease", "210987654321", fmt.Errorf("unable to create lease: account is no longer available")),
+				nil,
+			},
+			exp: response{
+				data: &lease.Lease{
+					PrincipalID:   ptrString("User1"),
+					Status:        lease.StatusPending.StatusPtr(),
+					QueuePosition: aws.Int64(1),
+				},
+			},
+		},
+		{
+			name: "should queue the lease behind earlier pending leases",
+			input: &lease.Lease{
+				PrincipalID: ptrString("User1"),
+			},
+			pending: &lease.Leases{
+				{ID: ptrString("1f2d7a0c-9e0b-4c55-8a52-6a3c5e7a2f10"), CreatedOn: aws.Int64(1573592058)},
+			},
+			accounts:   readyAccounts,
+			createErrs: []error{nil},
+			exp: response{
+				data: &lease.Lease{
+					PrincipalID:   ptrString("User1"),
+					Status:        lease.StatusPending.StatusPtr(),
+					QueuePosition: aws.Int64(2),
+				},
+			},
+		},
+		{
+			name: "should create a lease when some ready accounts are reserved by scheduled leases",
+			input: &lease.Lease{
+				PrincipalID: ptrString("User1"),
+			},
+			scheduled: &lease.Leases{
+				{ID: ptrString("1f2d7a0c-9e0b-4c55-8a52-6a3c5e7a2f10"), StartsOn: aws.Int64(time.Now().Unix() + 3600)},
+			},
+			accounts:     readyAccounts,
+			createErrs:   []error{nil},
+			expAccountID: "123456789012",
+			exp: response{
+				data: &lease.Lease{
+					AccountID:   ptrString("123456789012"),
+					PrincipalID: ptrString("User1"),
+					Status:      lease.StatusActive.StatusPtr(),
+				},
+			},
+		},
+		{
+			name: "should queue the lease when the ready accounts are reserved by scheduled leases",
+			input: &lease.Lease{
+				PrincipalID: ptrString("User1"),
+			},
+			active: &lease.Leases{
+				{ID: ptrString("a8f6bde2-5c4a-4d2f-9a57-0f07a2b1f0c1")},
+			},
+			scheduled: &lease.Leases{
+				{ID: ptrString("1f2d7a0c-9e0b-4c55-8a52-6a3c5e7a2f10"), StartsOn: aws.Int64(time.Now().Unix() + 3600)},
+			},
+			accounts:   readyAccounts,
+			createErrs: []error{nil},
+			exp: response{
+				data: &lease.Lease{
+					PrincipalID:   ptrString("User1"),
+					Status:        lease.StatusPending.StatusPtr(),
+					QueuePosition: aws.Int64(1),
+				},
+			},
+		},
+		{
+			name: "should rollback when the event fails to publish",
+			input: &lease.Lease{
+				PrincipalID: ptrString("User1"),
+			},
+			accounts:     readyAccounts,
+			createErrs:   []error{nil},
+			eventErr:     errors.NewInternalServer("failure", nil),
+			expAccountID: "123456789012",
+			expRollback:  true,
+			exp: response{
+				err: errors.NewInternalServer("failure", nil),
+			},
+		},
+		{
+			name: "should fail when listing accounts fails",
+			input: &lease.Lease{
+				PrincipalID: ptrString("User1"),
+			},
+			accountsErr: errors.NewInternalServer("failure", nil),
+			exp: response{
+				err: errors.NewInternalServer("failure", nil),
+			},
+		},
+		{
+			name: "should not allow an account ID",
+			input: &lease.Lease{
+				AccountID:   ptrString("123456789012"),
+				PrincipalID: ptrString("User1"),
+			},
+			exp: response{
+				err: errors.NewValidation("lease", fmt.Errorf("accountId: must be empty.")), //nolint golint
+			},
+		},
+		{
+			name: "should not allow a start date in the past",
+			input: &lease.Lease{
+				PrincipalID: ptrString("User1"),
+				StartsOn:    aws.Int64(1573592058),
+			},
+			exp: response{
+				err: errors.NewValidation("lease", fmt.Errorf("startsOn: must be in the future.")), //nolint golint
+			},
+		},
+		{
+			name:  "should require a principal ID",
+			input: &lease.Lease{},
+			exp: response{
+				err: errors.NewValidation("lease", fmt.Errorf("principalId: must be a string.")), //nolint golint
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocksRwd := &mocks.ReaderWriterDeleter{}
+			mocksAccounts := &mocks.AccountLister{}
+			mocksEventer := &mocks.Eventer{}
+
+			mockListByStatus(mocksRwd, tt.pending, tt.active, tt.scheduled)
+			mocksAccounts.On("List", mock.MatchedBy(func(query *account.Account) bool {
+				return query.Status == nil
+			})).Return(tt.accounts, tt.accountsErr)
+			mocksAccounts.On("List", mock.MatchedBy(func(query *account.Account) bool {
+				return query.Status != nil && *query.Status == account.StatusReady
+			})).Return(tt.accounts, tt.accountsErr)
+			for _, createErr := range tt.createErrs {
+				mocksRwd.On("Create", mock.AnythingOfType("*lease.Lease")).Return(createErr).Once()
+			}
+			mocksRwd.On("Rollback", mock.AnythingOfType("*lease.Lease")).Return(nil)
+			mocksEventer.On("LeaseCreate", mock.AnythingOfType("*lease.Lease")).Return(tt.eventErr)
+
+			leaseSvc := lease.NewService(
+				lease.NewServiceInput{
+					DataSvc:    mocksRwd,
+					AccountSvc: mocksAccounts,
+					EventSvc:   mocksEventer,
+				},
+			)
+
+			newLease, err := leaseSvc.Create(tt.input)
+			assert.Truef(t, errors.Is(err, tt.exp.err), "actual error %q doesn't match expected error %q", err, tt.exp.err)
+			if tt.exp.data != nil {
+				if *tt.exp.data.Status == lease.StatusPending {
+					assert.Equal(t, lease.PendingAccountID(*newLease.ID), *newLease.AccountID)
+				} else {
+					assert.Equal(t, tt.exp.data.AccountID, newLease.AccountID)
+				}
+				assert.Equal(t, tt.exp.data.PrincipalID, newLease.PrincipalID)
+				assert.Equal(t, tt.exp.data.Status, newLease.Status)
+				assert.Equal(t, tt.exp.data.BudgetAmount, newLease.BudgetAmount)
+				assert.Equal(t, tt.exp.data.QueuePosition, newLease.QueuePosition)
+				assert.NotNil(t, newLease.ID)
+				assert.NotNil(t, newLease.CreatedOn)
+				if *newLease.Status == lease.StatusActive {
+					mocksEventer.AssertCalled(t, "LeaseCreate", newLease)
+				} else {
+					mocksEventer.AssertNotCalled(t, "LeaseCreate", mock.Anything)
+				}
+			} else {
+				assert.Nil(t, newLease)
+			}
+			if tt.expRollback {
+				mocksRwd.AssertCalled(t, "Rollback", mock.MatchedBy(func(l *lease.Lease) bool {
+					return *l.AccountID == tt.expAccountID
+				}))
+			} else {
+				mocksRwd.AssertNotCalled(t, "Rollback", mock.Anything)
+			}
+		})
+	}
+}
+
+func TestCreateReadsEveryPageOfReadyAccounts(t *testing.T) {
+	mocksRwd := &mocks.ReaderWriterDeleter{}
+	mocksAccounts := &mocks.AccountLister{}
+	mocksEventer := &mocks.Eventer{}
+
+	mockListByStatus(mocksRwd, nil, nil, nil)
+	mocksAccounts.On("List", mock.MatchedBy(func(query *account.Account) bool {
+		return query.Status == nil
+	})).Return(&account.Accounts{
+		{ID: ptrString("123456789012"), Status: account.StatusLeased.StatusPtr()},
+		{ID: ptrString("210987654321"), Status: account.StatusReady.StatusPtr()},
+	}, nil)
+	// The first page has no Ready accounts, as the page limit
+	// is applied before filtering by status
+	mocksAccounts.On("List", mock.MatchedBy(func(query *account.Account) bool {
+		return query.Status != nil && query.NextID == nil
+	})).Run(func(args mock.Arguments) {
+		args.Get(0).(*account.Account).NextID = ptrString("123456789012")
+	}).Return(&account.Accounts{}, nil)
+	mocksAccounts.On("List", mock.MatchedBy(func(query *account.Account) bool {
+		return query.Status != nil && query.NextID != nil
+	})).Run(func(args mock.Arguments) {
+		args.Get(0).(*account.Account).NextID = nil
+	}).Return(&account.Accounts{
+		{ID: ptrString("210987654321"), Status: account.StatusReady.StatusPtr()},
+	}, nil)
+	mocksRwd.On("Create", mock.AnythingOfType("*lease.Lease")).Return(nil)
+	mocksEventer.On("LeaseCreate", mock.AnythingOfType("*lease.Lease")).Return(nil)
+
+	leaseSvc := lease.NewService(
+		lease.NewServiceInput{
+			DataSvc:    mocksRwd,
+			AccountSvc: mocksAccounts,
+			EventSvc:   mocksEventer,
+		},
+	)
+
+	newLease, err := leaseSvc.Create(&lease.Lease{
+		PrincipalID: ptrString("User1"),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, lease.StatusActive, *newLease.Status)
+	assert.Equal(t, "210987654321", *newLease.AccountID)
+}
+
+func TestCreatePool(t *testing.T) {
+	tests := []struct {
+		name         string
+		pool         *string
+		poolAccounts *account.Accounts
+		readyAccount *account.Accounts
+		expAccountID *string
+		expStatus    lease.Status
+		expErr       error
+	}{
+		{
+			name: "should create a lease on a ready account in the pool",
+			pool: ptrString("gov-region"),
+			poolAccounts: &account.Accounts{
+				{ID: ptrString("123456789012"), Pool: ptrString("gov-region"), Status: account.StatusLeased.StatusPtr()},
+				{ID: ptrString("210987654321"), Pool: ptrString("gov-region"), Status: account.StatusReady.StatusPtr()},
+			},
+			readyAccount: &account.Accounts{
+				{ID: ptrString("210987654321"), Pool: ptrString("gov-region"), Status: account.StatusReady.StatusPtr()},
+			},
+			expAccountID: ptrString("210987654321"),
+			expStatus:    lease.StatusActive,
+		},
+		{
+			name: "should not assign pooled accounts to leases without a pool",
+			readyAccount: &account.Accounts{
+				{ID: ptrString("210987654321"), Pool: ptrString("gov-region"), Status: account.StatusReady.StatusPtr()},
+			},
+			poolAccounts: &account.Accounts{},
+			expStatus:    lease.StatusPending,
+		},
+		{
+			name:         "should fail when the pool has no accounts",
+			pool:         ptrString("network-enabled"),
+			poolAccounts: &account.Accounts{},
+			expErr:       errors.NewValidation("lease", fmt.Errorf("pool: there are no accounts in pool \"network-enabled\"")),
+		},
+		{
+			name:   "should fail when the pool name is invalid",
+			pool:   ptrString("gov region"),
+			expErr: errors.NewValidation("lease", fmt.Errorf("pool: must only contain letters, numbers, hyphens and underscores.")), //nolint golint
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocksRwd := &mocks.ReaderWriterDeleter{}
+			mocksAccounts := &mocks.AccountLister{}
+			mocksEventer := &mocks.Eventer{}
+
+			mockListByStatus(mocksRwd, nil, nil, nil)
+			mocksAccounts.On("List", mock.MatchedBy(func(query *account.Account) bool {
+				return query.Status == nil
+			})).Return(tt.poolAccounts, nil)
+			mocksAccounts.On("List", mock.MatchedBy(func(query *account.Account) bool {
+				return query.Status != nil && *query.Status == account.StatusReady
+			})).Return(tt.readyAccount, nil)
+			mocksRwd.On("Create", mock.AnythingOfType("*lease.Lease")).Return(nil)
+			mocksEventer.On("LeaseCreate", mock.AnythingOfType("*lease.Lease")).Return(nil)
+
+			leaseSvc := lease.NewService(
+				lease.NewServiceInput{
+					DataSvc:    mocksRwd,
+					AccountSvc: mocksAccounts,
+					EventSvc:   mocksEventer,
+				},
+			)
+
+			newLease, err := leaseSvc.Create(&lease.Lease{
+				PrincipalID: ptrString("User1"),
+				Pool:        tt.pool,
+			})
+			assert.Truef(t, errors.Is(err, tt.expErr), "actual error %q doesn't match expected error %q", err, tt.expErr)
+			if tt.expErr == nil {
+				if tt.expStatus == lease.StatusPending {
+					assert.Equal(t, lease.PendingAccountID(*newLease.ID), *newLease.AccountID)
+				} else {
+					assert.Equal(t, *tt.expAccountID, *newLease.AccountID)
+				}
+				assert.Equal(t, tt.expStatus, *newLease.Status)
+				assert.Equal(t, tt.pool, newLease.Pool)
+				mocksAccounts.AssertCalled(t, "List", mock.MatchedBy(func(query *account.Account) bool {
+					return query.Status != nil && query.Pool == tt.pool
+				}))
+			}
+		})
+	}
+}
+
+func TestCreateScheduled(t *testing.T) {
+	now := time.Now().Unix()
+	day := int64(86400)
+	startsOn := now + 7*day
+	expiresOn := startsOn + 2*day
+
+	allAccounts := &account.Accounts{
+		{ID: ptrString("123456789012"), Status: account.StatusLeased.StatusPtr()},
+		{ID: ptrString("210987654321"), Status: account.StatusReady.StatusPtr()},
+		{ID: ptrString("111111111111"), Status: account.StatusOrphaned.StatusPtr()},
+	}
+
+	tests := []struct {
+		name      string
+		input     *lease.Lease
+		pending   *lease.Leases
+		active    *lease.Leases
+		scheduled *lease.Leases
+		expCreate bool
+		expErr    error
+	}{
+		{
+			name: "should schedule a lease when an account is free for the period",
+			input: &lease.Lease{
+				PrincipalID: ptrString("User1"),
+				StartsOn:    aws.Int64(startsOn),
+				ExpiresOn:   aws.Int64(expiresOn),
+			},
+			active: &lease.Leases{
+				{ID: ptrString("a1"), PrincipalID: ptrString("User2"), ExpiresOn: aws.Int64(startsOn + day)},
+			},
+			scheduled: &lease.Leases{},
+			expCreate: true,
+		},
+		{
+			name: "should not count leases which end before the period starts",
+			input: &lease.Lease{
+				PrincipalID: ptrString("User1"),
+				StartsOn:    aws.Int64(startsOn),
+				ExpiresOn:   aws.Int64(expiresOn),
+			},
+			active: &lease.Leases{
+				{ID: ptrString("a1"), PrincipalID: ptrString("User2"), ExpiresOn: aws.Int64(startsOn + day)},
+				{ID: ptrString("a2"), PrincipalID: ptrString("User3"), ExpiresOn: aws.Int64(startsOn - day)},
+			},
+			scheduled: &lease.Leases{
+				{ID: ptrString("s1"), PrincipalID: ptrString("User4"), StartsOn: aws.Int64(expiresOn), ExpiresOn: aws.Int64(expiresOn + day)},
+			},
+			expCreate: true,
+		},
+		{
+			name: "should fail when every account is reserved for the period",
+			input: &lease.Lease{
+				PrincipalID: ptrString("User1"),
+				StartsOn:    aws.Int64(startsOn),
+				ExpiresOn:   aws.Int64(expiresOn),
+			},
+			active: &lease.Leases{
+				{ID: ptrString("a1"), PrincipalID: ptrString("User2"), ExpiresOn: aws.Int64(startsOn + day)},
+			},
+			scheduled: &lease.Leases{
+				{ID: ptrString("s1"), PrincipalID: ptrString("User3"), StartsOn: aws.Int64(startsOn - day), ExpiresOn: aws.Int64(startsOn + day)},
+			},
+			expErr: errors.NewConflict("lease", "User1", fmt.Errorf("all 2 accounts are reserved for the requested lease period")),
+		},
+		{
+			name: "should count pending leases as reserving an account",
+			input: &lease.Lease{
+				PrincipalID: ptrString("User1"),
+				StartsOn:    aws.Int64(startsOn),
+				ExpiresOn:   aws.Int64(expiresOn),
+			},
+			pending: &lease.Leases{
+				{ID: ptrString("p1"), PrincipalID: ptrString("User3")},
+			},
+			active: &lease.Leases{
+				{ID: ptrString("a1"), PrincipalID: ptrString("User2"), ExpiresOn: aws.Int64(startsOn + day)},
+			},
+			expErr: errors.NewConflict("lease", "User1", fmt.Errorf("all 2 accounts are reserved for the requested lease period")),
+		},
+		{
+			name: "should allow a principal to schedule more than one lease",
+			input: &lease.Lease{
+				PrincipalID: ptrString("User1"),
+				StartsOn:    aws.Int64(startsOn),
+				ExpiresOn:   aws.Int64(expiresOn),
+			},
+			scheduled: &lease.Leases{
+				{ID: ptrString("s1"), PrincipalID: ptrString("User1"), StartsOn: aws.Int64(expiresOn), ExpiresOn: aws.Int64(expiresOn + day)},
+			},
+			expCreate: true,
+		},
+		{
+			name: "should fail when the lease expires before it starts",
+			input: &lease.Lease{
+				PrincipalID: ptrString("User1"),
+				StartsOn:    aws.Int64(startsOn),
+				ExpiresOn:   aws.Int64(startsOn - day),
+			},
+			expErr: errors.NewValidation("lease", fmt.Errorf("expiresOn: must be after startsOn.")), //nolint golint
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocksRwd := &mocks.ReaderWriterDeleter{}
+			mocksAccounts := &mocks.AccountLister{}
+			mocksEventer := &mocks.Eventer{}
+
+			mockListByStatus(mocksRwd, tt.pending, tt.active, tt.scheduled)
+			mocksAccounts.On("List", mock.AnythingOfType("*account.Account")).Return(allAccounts, nil)
+			mocksRwd.On("Create", mock.AnythingOfType("*lease.Lease")).Return(nil)
+
+			leaseSvc := lease.NewService(
+				lease.NewServiceInput{
+					DataSvc:    mocksRwd,
+					AccountSvc: mocksAccounts,
+					EventSvc:   mocksEventer,
+				},
+			)
+
+			newLease, err := leaseSvc.Create(tt.input)
+			assert.Truef(t, errors.Is(err, tt.expErr), "actual error %q doesn't match expected error %q", err, tt.expErr)
+			if tt.expCreate {
+				assert.Equal(t, lease.ScheduledAccountID(*newLease.ID), *newLease.AccountID)
+				assert.Equal(t, lease.StatusScheduled, *newLease.Status)
+				assert.Equal(t, startsOn, *newLease.StartsOn)
+				assert.NotNil(t, newLease.ID)
+				mocksRwd.AssertCalled(t, "Create", newLease)
+			} else {
+				assert.Nil(t, newLease)
+				mocksRwd.AssertNotCalled(t, "Create", mock.Anything)
+			}
+			// Events are published when the lease is activated
+			mocksEventer.AssertNotCalled(t, "LeaseCreate", mock.Anything)
+		})
+	}
+}
+
+func TestActivateScheduled(t *testing.T) {
+	now := time.Now().Unix()
+	readyAccounts := &account.Accounts{
+		{ID: ptrString("123456789012")},
+		{ID: ptrString("210987654321")},
+	}
+
+	scheduledLease := func(id string, principalID string, startsOn int64) lease.Lease {
+		return lease.Lease{
+			ID:          ptrString(id),
+			AccountID:   ptrString(lease.ScheduledAccountID(id)),
+			PrincipalID: ptrString(principalID),
+			Status:      lease.StatusScheduled.StatusPtr(),
+			CreatedOn:   aws.Int64(now - 604800),
+			StartsOn:    aws.Int64(startsOn),
+			ExpiresOn:   aws.Int64(startsOn + 86400),
+		}
+	}
+
+	tests := []struct {
+		name         string
+		scheduled    *lease.Leases
+		accounts     *account.Accounts
+		promoteErrs  []error
+		expActivated []string
+		expErr       error
+	}{
+		{
+			name: "should activate leases which have started",
+			scheduled: &lease.Leases{
+				scheduledLease("70c2d96d-7938-4ec9-917d-476f2b09cc04", "User1", now-60),
+				scheduledLease("a8f6bde2-5c4a-4d2f-9a57-0f07a2b1f0c1", "User2", now+3600),
+			},
+			accounts:     readyAccounts,
+			promoteErrs:  []error{nil},
+			expActivated: []string{"123456789012"},
+		},
+		{
+			name: "should leave leases scheduled when no accounts are ready",
+			scheduled: &lease.Leases{
+				scheduledLease("70c2d96d-7938-4ec9-917d-476f2b09cc04", "User1", now-60),
+			},
+			accounts:     &account.Accounts{},
+			expActivated: []string{},
+		},
+		{
+			name: "should fail when promoting the lease fails",
+			scheduled: &lease.Leases{
+				scheduledLease("70c2d96d-7938-4ec9-917d-476f2b09cc04", "User1", now-60),
+			},
+			accounts:     readyAccounts,
+			promoteErrs:  []error{errors.NewInternalServer("failure", nil)},
+			expActivated: []string{},
+			expErr:       errors.NewInternalServer("failure", nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocksRwd := &mocks.ReaderWriterDeleter{}
+			mocksAccounts := &mocks.AccountLister{}
+			mocksEventer := &mocks.Eventer{}
+
+			mockListByStatus(mocksRwd, nil, nil, tt.scheduled)
+			mocksAccounts.On("List", mock.Anything).Return(tt.accounts, nil)
+			for _, promoteErr := range tt.promoteErrs {
+				mocksRwd.On("Promote", mock.AnythingOfType("*lease.Lease"), mock.AnythingOfType("*lease.Lease")).Return(promoteErr).Once()
+			}
+			mocksEventer.On("LeaseCreate", mock.AnythingOfType("*lease.Lease")).Return(nil)
+
+			leaseSvc := lease.NewService(
+				lease.NewServiceInput{
+					DataSvc:    mocksRwd,
+					AccountSvc: mocksAccounts,
+					EventSvc:   mocksEventer,
+				},
+			)
+
+			activated, err := leaseSvc.ActivateScheduled()
+			assert.Truef(t, errors.Is(err, tt.expErr), "actual error %q doesn't match expected error %q", err, tt.expErr)
+			accountIDs := []string{}
+			for _, l := range *activated {
+				assert.Equal(t, lease.StatusActive, *l.Status)
+				// Scheduled leases keep their requested expiration
+				assert.Equal(t, *l.StartsOn+86400, *l.ExpiresOn)
+				accountIDs = append(accountIDs, *l.AccountID)
+			}
+			assert.Equal(t, tt.expActivated, accountIDs)
+		})
+	}
+}
+
+func TestActivateScheduledReadsEveryPage(t *testing.T) {
+	now := time.Now().Unix()
+	scheduledLease := func(id string, principalID string) lease.Lease {
+		return lease.Lease{
+			ID:          ptrString(id),
+			AccountID:   ptrString(lease.ScheduledAccountID(id)),
+			PrincipalID: ptrString(principalID),
+			Status:      lease.StatusScheduled.StatusPtr(),
+			CreatedOn:   aws.Int64(now - 604800),
+			StartsOn:    aws.Int64(now - 60),
+			ExpiresOn:   aws.Int64(now + 86400),
+		}
+	}
+
+	mocksRwd := &mocks.ReaderWriterDeleter{}
+	mocksAccounts := &mocks.AccountLister{}
+	mocksEventer := &mocks.Eventer{}
+
+	isScheduled := mock.MatchedBy(func(query *lease.Lease) bool {
+		return query.Status != nil && *query.Status == lease.StatusScheduled
+	})
+	// The first page has more leases to read
+	mocksRwd.On("List", isScheduled).Run(func(args mock.Arguments) {
+		query := args.Get(0).(*lease.Lease)
+		query.NextAccountID = ptrString(lease.ScheduledAccountID("70c2d96d-7938-4ec9-917d-476f2b09cc04"))
+		query.NextPrincipalID = ptrString("User1")
+	}).Return(&lease.Leases{
+		scheduledLease("70c2d96d-7938-4ec9-917d-476f2b09cc04", "User1"),
+	}, nil).Once()
+	mocksRwd.On("List", isScheduled).Run(func(args mock.Arguments) {
+		query := args.Get(0).(*lease.Lease)
+		query.NextAccountID = nil
+		query.NextPrincipalID = nil
+	}).Return(&lease.Leases{
+		scheduledLease("a8f6bde2-5c4a-4d2f-9a57-0f07a2b1f0c1", "User2"),
+	}, nil).Once()
+	mocksAccounts.On("List", mock.Anything).Return(&account.Accounts{
+		{ID: ptrString("123456789012")},
+		{ID: ptrString("210987654321")},
+	}, nil)
+	mocksRwd.On("Promote", mock.AnythingOfType("*lease.Lease"), mock.AnythingOfType("*lease.Lease")).Return(nil)
+	mocksEventer.On("LeaseCreate", mock.AnythingOfType("*lease.Lease")).Return(nil)
+
+	leaseSvc := lease.NewService(
+		lease.NewServiceInput{
+			DataSvc:    mocksRwd,
+			AccountSvc: mocksAccounts,
+			EventSvc:   mocksEventer,
+		},
+	)
+
+	activated, err := leaseSvc.ActivateScheduled()
+	assert.Nil(t, err)
+	assert.Len(t, *activated, 2)
+	mocksRwd.AssertNumberOfCalls(t, "List", 2)
+}
+
+func TestAssignPending(t *testing.T) {
+	readyAccounts := &account.Accounts{
+		{ID: ptrString("123456789012")},
+		{ID: ptrString("210987654321")},
+	}
+
+	pendingLease := func(id string, principalID string, createdOn int64) lease.Lease {
+		return lease.Lease{
+			ID:          ptrString(id),
+			AccountID:   ptrString(lease.PendingAccountID(id)),
+			PrincipalID: ptrString(principalID),
+			Status:      lease.StatusPending.StatusPtr(),
+			CreatedOn:   aws.Int64(createdOn),
+			ExpiresOn:   aws.Int64(createdOn + 604800),
+		}
+	}
+
+	tests := []struct {
+		name         string
+		pending      *lease.Leases
+		scheduled    *lease.Leases
+		accounts     *account.Accounts
+		promoteErrs  []error
+		eventErr     error
+		expAccountID *string
+		expRollback  bool
+		expErr       error
+	}{
+		{
+			name: "should assign the first ready account to the oldest pending lease",
+			pending: &lease.Leases{
+				pendingLease("a8f6bde2-5c4a-4d2f-9a57-0f07a2b1f0c1", "User2", 1573592060),
+				pendingLease("70c2d96d-7938-4ec9-917d-476f2b09cc04", "User1", 1573592058),
+			},
+			accounts:     readyAccounts,
+			promoteErrs:  []error{nil},
+			expAccountID: ptrString("123456789012"),
+		},
+		{
+			name: "should try the next account when one is claimed by another request",
+			pending: &lease.Leases{
+				pendingLease("70c2d96d-7938-4ec9-917d-476f2b09cc04", "User1", 1573592058),
+			},
+			accounts: readyAccounts,
+			promoteErrs: []error{
+				errors.NewConflict("lease", "70c2d96d-7938-4ec9-917d-476f2b09cc04", fmt.Errorf("unable to promote lease")),
+				nil,
+			},
+			expAccountID: ptrString("210987654321"),
+		},
+		{
+			name:     "should do nothing when there are no pending leases",
+			pending:  &lease.Leases{},
+			accounts: readyAccounts,
+		},
+		{
+			name: "should do nothing when there are no ready accounts",
+			pending: &lease.Leases{
+				pendingLease("70c2d96d-7938-4ec9-917d-476f2b09cc04", "User1", 1573592058),
+			},
+			accounts: &account.Accounts{},
+		},
+		{
+			name: "should leave the lease pending when the ready accounts are reserved by scheduled leases",
+			pending: &lease.Leases{
+				pendingLease("70c2d96d-7938-4ec9-917d-476f2b09cc04", "User1", 1573592058),
+			},
+			scheduled: &lease.Leases{
+				{ID: ptrString("s1"), StartsOn: aws.Int64(time.Now().Unix() + 3600)},
+				{ID: ptrString("s2"), StartsOn: aws.Int64(time.Now().Unix() + 7200)},
+			},
+			accounts: readyAccounts,
+		},
+		{
+			name: "should rollback when the event fails to publish",
+			pending: &lease.Leases{
+				pendingLease("70c2d96d-7938-4ec9-917d-476f2b09cc04", "User1", 1573592058),
+			},
+			accounts:    readyAccounts,
+			promoteErrs: []error{nil},
+			eventErr:    errors.NewInternalServer("failure", nil),
+			expRollback: true,
+			expErr:      errors.NewInternalServer("failure", nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocksRwd := &mocks.ReaderWriterDeleter{}
+			mocksAccounts := &mocks.AccountLister{}
+			mocksEventer := &mocks.Eventer{}
+
+			mockListByStatus(mocksRwd, tt.pending, nil, tt.scheduled)
+			mocksAccounts.On("List", mock.Anything).Return(tt.accounts, nil)
+			for _, promoteErr := range tt.promoteErrs {
+				mocksRwd.On("Promote", mock.AnythingOfType("*lease.Lease"), mock.AnythingOfType("*lease.Lease")).Return(promoteErr).Once()
+			}
+			mocksRwd.On("Rollback", mock.AnythingOfType("*lease.Lease")).Return(nil)
+			mocksEventer.On("LeaseCreate", mock.AnythingOfType("*lease.Lease")).Return(tt.eventErr)
+
+			leaseSvc := lease.NewService(
+				lease.NewServiceInput{
+					DataSvc:    mocksRwd,
+					AccountSvc: mocksAccounts,
+					EventSvc:   mocksEventer,
+				},
+			)
+
+			assigned, err := leaseSvc.AssignPending()
+			assert.Truef(t, errors.Is(err, tt.expErr), "actual error %q doesn't match expected error %q", err, tt.expErr)
+			if tt.expAccountID != nil {
+				// The oldest lease is assigned first
+				assert.Equal(t, "70c2d96d-7938-4ec9-917d-476f2b09cc04", *assigned.ID)
+				assert.Equal(t, *tt.expAccountID, *assigned.AccountID)
+				assert.Equal(t, lease.StatusActive, *assigned.Status)
+				// Time spent waiting isn't counted against the lease period
+				assert.True(t, *assigned.ExpiresOn-*assigned.StatusModifiedOn >= 604800)
+				mocksEventer.AssertCalled(t, "LeaseCreate", assigned)
+			} else {
+				assert.Nil(t, assigned)
+			}
+			if tt.expRollback {
+				mocksRwd.AssertCalled(t, "Rollback", mock.AnythingOfType("*lease.Lease"))
+			} else {
+				mocksRwd.AssertNotCalled(t, "Rollback", mock.Anything)
+			}
+		})
+	}
+}
+
+func TestAssignPendingPools(t *testing.T) {
+	mocksRwd := &mocks.ReaderWriterDeleter{}
+	mocksAccounts := &mocks.AccountLister{}
+	mocksEventer := &mocks.Eventer{}
+
+	// The oldest lease is waiting on a pool with no Ready accounts
+	mockListByStatus(mocksRwd, &lease.Leases{
+		{
+			ID:          ptrString("a8f6bde2-5c4a-4d2f-9a57-0f07a2b1f0c1"),
+			AccountID:   ptrString(lease.PendingAccountID("a8f6bde2-5c4a-4d2f-9a57-0f07a2b1f0c1")),
+			PrincipalID: ptrString("User1"),
+			Status:      lease.StatusPending.StatusPtr(),
+			Pool:        ptrString("gov-region"),
+			CreatedOn:   aws.Int64(1573592058),
+		},
+		{
+			ID:          ptrString("70c2d96d-7938-4ec9-917d-476f2b09cc04"),
+			AccountID:   ptrString(lease.PendingAccountID("70c2d96d-7938-4ec9-917d-476f2b09cc04")),
+			PrincipalID: ptrString("User2"),
+			Status:      lease.StatusPending.StatusPtr(),
+			CreatedOn:   aws.Int64(1573592060),
+		},
+	}, nil, nil)
+	mocksAccounts.On("List", mock.MatchedBy(func(query *account.Account) bool {
+		return query.Pool != nil
+	})).Return(&account.Accounts{}, nil)
+	mocksAccounts.On("List", mock.MatchedBy(func(query *account.Account) bool {
+		return query.Pool == nil
+	})).Return(&account.Accounts{
+		{ID: ptrString("123456789012"), Pool: ptrString("network-enabled")},
+		{ID: ptrString("210987654321")},
+	}, nil)
+	mocksRwd.On("Promote", mock.AnythingOfType("*lease.Lease"), mock.AnythingOfType("*lease.Lease")).Return(nil)
+	mocksEventer.On("LeaseCreate", mock.AnythingOfType("*lease.Lease")).Return(nil)
+
+	leaseSvc := lease.NewService(
+		lease.NewServiceInput{
+			DataSvc:    mocksRwd,
+			AccountSvc: mocksAccounts,
+			EventSvc:   mocksEventer,
+		},
+	)
+
+	assigned, err := leaseSvc.AssignPending()
+	assert.Nil(t, err)
+	assert.Equal(t, "70c2d96d-7938-4ec9-917d-476f2b09cc04", *assigned.ID)
+	// Accounts in a pool are only assigned to leases for that pool
+	assert.Equal(t, "210987654321", *assigned.AccountID)
+}
+
+func TestUpdate(t *testing.T) {
+	now := time.Now().Unix()
+	later := time.Now().AddDate(0, 0, 7).Unix()
+	earlier := time.Now().AddDate(0, 0, -7).Unix()
+
+	type response struct {
+		data *lease.Lease
+		err  error
+	}
+
+	tests := []struct {
+		name     string
+		ID       string
+		input    *lease.Lease
+		getLease *lease.Lease
+		getErr   error
+		exp      response
+		expEvent bool
+	}{
+		{
+			name: "should extend an active lease",
+			ID:   "70c2d96d-7938-4ec9-917d-476f2b09cc04",
+			input: &lease.Lease{
+				ExpiresOn:    &later,
+				BudgetAmount: aws.Float64(200),
+			},
+			getLease: &lease.Lease{
+				ID:             ptrString("70c2d96d-7938-4ec9-917d-476f2b09cc04"),
+				Status:         lease.StatusActive.StatusPtr(),
+				AccountID:      ptrString("123456789012"),
+				PrincipalID:    ptrString("test:arn"),
+				CreatedOn:      &now,
+				LastModifiedOn: &now,
+				ExpiresOn:      &now,
+				BudgetAmount:   aws.Float64(100),
+			},
+			exp: response{
+				data: &lease.Lease{
+					ID:             ptrString("70c2d96d-7938-4ec9-917d-476f2b09cc04"),
+					Status:         lease.StatusActive.StatusPtr(),
+					AccountID:      ptrString("123456789012"),
+					PrincipalID:    ptrString("test:arn"),
+					CreatedOn:      &now,
+					LastModifiedOn: &now,
+					ExpiresOn:      &later,
+					BudgetAmount:   aws.Float64(200),
+				},
+			},
+			expEvent: true,
+		},
+		{
+			name: "should replace the notification targets",
+			ID:   "70c2d96d-7938-4ec9-917d-476f2b09cc04",
+			input: &lease.Lease{
+				NotificationTargets: &[]string{},
+			},
+			getLease: &lease.Lease{
+				ID:                  ptrString("70c2d96d-7938-4ec9-917d-476f2b09cc04"),
+				Status:              lease.StatusActive.StatusPtr(),
+				AccountID:           ptrString("123456789012"),
+				PrincipalID:         ptrString("test:arn"),
+				CreatedOn:           &now,
+				LastModifiedOn:      &now,
+				ExpiresOn:           &now,
+				NotificationTargets: &[]string{"slack:https://hooks.slack.com/services/T000/B000/XXXX"},
+			},
+			exp: response{
+				data: &lease.Lease{
+					PrincipalID:         ptrString("test:arn"),
+					ExpiresOn:           &now,
+					NotificationTargets: &[]string{},
+				},
+			},
+			expEvent: true,
+		},
+		{
+			name:  "should fail when no changes are provided",
+			ID:    "70c2d96d-7938-4ec9-917d-476f2b09cc04",
+			input: &lease.Lease{},
+			exp: response{
+				err: errors.NewValidation("lease", fmt.Errorf("expiresOn, budgetAmount or notificationTargets must be provided")),
+			},
+		},
+		{
+			name: "should fail when changing the principal",
+			ID:   "70c2d96d-7938-4ec9-917d-476f2b09cc04",
+			input: &lease.Lease{
+				PrincipalID: ptrString("other:arn"),
+				ExpiresOn:   &later,
+			},
+			exp: response{
+				err: errors.NewValidation("lease", fmt.Errorf("principalId: must be empty.")), //nolint golint
+			},
+		},
+		{
+			name: "should fail when changing the metadata, pool or start date",
+			ID:   "70c2d96d-7938-4ec9-917d-476f2b09cc04",
+			input: &lease.Lease{
+				Metadata:  map[string]interface{}{"costCenter": "cc-1"},
+				StartsOn:  &later,
+				Pool:      ptrString("gov-region"),
+				ExpiresOn: &later,
+			},
+			exp: response{
+				err: errors.NewValidation("lease", fmt.Errorf("metadata: must be empty; pool: must be empty; startsOn: must be empty.")), //nolint golint
+			},
+		},
+		{
+			name: "should fail when the lease is inactive",
+			ID:   "70c2d96d-7938-4ec9-917d-476f2b09cc04",
+			input: &lease.Lease{
+				ExpiresOn: &later,
+			},
+			getLease: &lease.Lease{
+				ID:        ptrString("70c2d96d-7938-4ec9-917d-476f2b09cc04"),
+				Status:    lease.StatusInactive.StatusPtr(),
+				ExpiresOn: &now,
+			},
+			exp: response{
+				err: errors.NewConflict("lease", "70c2d96d-7938-4ec9-917d-476f2b09cc04", fmt.Errorf("leaseStatus: must be active lease.")), //nolint golint
+			},
+		},
+		{
+			name: "should fail when shortening the lease",
+			ID:   "70c2d96d-7938-4ec9-917d-476f2b09cc04",
+			input: &lease.Lease{
+				ExpiresOn: &earlier,
+			},
+			getLease: &lease.Lease{
+				ID:        ptrString("70c2d96d-7938-4ec9-917d-476f2b09cc04"),
+				Status:    lease.StatusActive.StatusPtr(),
+				ExpiresOn: &now,
+			},
+			exp: response{
+				err: errors.NewValidation("lease", fmt.Errorf("expiresOn: must not be before the current value.")), //nolint golint
+			},
+		},
+		{
+			name: "should fail when get fails",
+			ID:   "70c2d96d-7938-4ec9-917d-476f2b09cc04",
+			input: &lease.Lease{
+				ExpiresOn: &later,
+			},
+			getErr: errors.NewInternalServer("failure", nil),
+			exp: response{
+				err: errors.NewInternalServer("failure", nil),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocksRwd := &mocks.ReaderWriterDeleter{}
+			mocksEventer := &mocks.Eventer{}
+
+			mocksRwd.On("Get", tt.ID).Return(tt.getLease, tt.getErr)
+			mocksRwd.On("Write", mock.AnythingOfType("*lease.Lease"), mock.AnythingOfType("*int64")).Return(nil)
+			mocksEventer.On("LeaseUpdate", mock.AnythingOfType("*lease.Lease")).Return(nil)
+
+			leaseSvc := lease.NewService(
+				lease.NewServiceInput{
+					DataSvc:  mocksRwd,
+					EventSvc: mocksEventer,
+				},
+			)
+
+			updatedLease, err := leaseSvc.Update(tt.ID, tt.input)
+			assert.Truef(t, errors.Is(err, tt.exp.err), "actual error %q doesn't match expected error %q", err, tt.exp.err)
+			if tt.exp.data != nil {
+				assert.Equal(t, tt.exp.data.ExpiresOn, updatedLease.ExpiresOn)
+				assert.Equal(t, tt.exp.data.BudgetAmount, updatedLease.BudgetAmount)
+				assert.Equal(t, tt.exp.data.PrincipalID, updatedLease.PrincipalID)
+				if tt.exp.data.NotificationTargets != nil {
+					assert.Equal(t, tt.exp.data.NotificationTargets, updatedLease.NotificationTargets)
+				}
+			} else {
+				assert.Nil(t, updatedLease)
+			}
+			if tt.expEvent {
+				mocksEventer.AssertCalled(t, "LeaseUpdate", updatedLease)
+			} else {
+				mocksEventer.AssertNotCalled(t, "LeaseUpdate", mock.Anything)
+			}
+		})
+	}
+}
+
+func TestExpiringSoon(t *testing.T) {
+	now := time.Now().Unix()
+	reminderOn := time.Now().Add(-time.Minute).Unix()
+
+	tests := []struct {
+		name     string
+		getLease *lease.Lease
+		getErr   error
+		expErr   error
+		expEvent bool
+	}{
+		{
+			name: "should record the reminder and publish an event",
+			getLease: &lease.Lease{
+				ID:             ptrString("70c2d96d-7938-4ec9-917d-476f2b09cc04"),
+				Status:         lease.StatusActive.StatusPtr(),
+				AccountID:      ptrString("123456789012"),
+				PrincipalID:    ptrString("test:arn"),
+				CreatedOn:      &now,
+				LastModifiedOn: &now,
+				ExpiresOn:      &now,
+			},
+			expEvent: true,
+		},
+		{
+			name: "should fail when the lease is inactive",
+			getLease: &lease.Lease{
+				ID:     ptrString("70c2d96d-7938-4ec9-917d-476f2b09cc04"),
+				Status: lease.StatusInactive.StatusPtr(),
+			},
+			expErr: errors.NewConflict("lease", "70c2d96d-7938-4ec9-917d-476f2b09cc04", fmt.Errorf("leaseStatus: must be active lease.")), //nolint golint
+		},
+		{
+			name:   "should fail when get fails",
+			getErr: errors.NewInternalServer("failure", nil),
+			expErr: errors.NewInternalServer("failure", nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocksRwd := &mocks.ReaderWriterDeleter{}
+			mocksEventer := &mocks.Eventer{}
+
+			mocksRwd.On("Get", "70c2d96d-7938-4ec9-917d-476f2b09cc04").Return(tt.getLease, tt.getErr)
+			mocksRwd.On("Write", mock.AnythingOfType("*lease.Lease"), mock.AnythingOfType("*int64")).Return(nil)
+			mocksEventer.On("LeaseUpdate", mock.AnythingOfType("*lease.EventMessage")).Return(nil)
+
+			leaseSvc := lease.NewService(
+				lease.NewServiceInput{
+					DataSvc:  mocksRwd,
+					EventSvc: mocksEventer,
+				},
+			)
+
+			updatedLease, err := leaseSvc.ExpiringSoon("70c2d96d-7938-4ec9-917d-476f2b09cc04", reminderOn)
+			assert.Truef(t, errors.Is(err, tt.expErr), "actual error %q doesn't match expected error %q", err, tt.expErr)
+			if tt.expEvent {
+				assert.Equal(t, &reminderOn, updatedLease.ExpiryReminderOn)
+				mocksEventer.AssertCalled(t, "LeaseUpdate", &lease.EventMessage{
+					Lease: *updatedLease,
+					Event: lease.EventExpiringSoon,
+				})
+			} else {
+				assert.Nil(t, updatedLease)
+				mocksRwd.AssertNotCalled(t, "Write", mock.Anything, mock.Anything)
+				mocksEventer.AssertNotCalled(t, "LeaseUpdate", mock.Anything)
+			}
+		})
+	}
+}
+
+func TestExtensionToken(t *testing.T) {
+	expiresOn := int64(1570000000)
+	data := &lease.Lease{
+		ID:        ptrString("70c2d96d-7938-4ec9-917d-476f2b09cc04"),
+		ExpiresOn: &expiresOn,
+	}
+
+	now := time.Unix(expiresOn, 0).Add(-time.Hour)
+
+	token, err := lease.ExtensionToken("secret", data, time.Unix(expiresOn, 0))
+	assert.Nil(t, err)
+	assert.NotEmpty(t, token)
+	assert.True(t, lease.IsValidExtensionToken("secret", data, token, now))
+	assert.False(t, lease.IsValidExtensionToken("other-secret", data, token, now))
+
+	// The token can't be reused once the lease has been extended
+	extended := *data
+	extendedOn := expiresOn + 3600
+	extended.ExpiresOn = &extendedOn
+	assert.False(t, lease.IsValidExtensionToken("secret", &extended, token, now))
+
+	// The token can't be used once it has expired
+	assert.False(t, lease.IsValidExtensionToken("secret", data, token, now.Add(2*time.Hour)))
+
+	// The token's expiry can't be changed
+	forged := "1670000000" + token[strings.Index(token, "."):]
+	assert.False(t, lease.IsValidExtensionToken("secret", data, forged, now))
+
+	_, err = lease.ExtensionToken("", data, time.Unix(expiresOn, 0))
+	assert.EqualError(t, err, "no lease extension secret is configured")
+	assert.False(t, lease.IsValidExtensionToken("", data, "", now))
+}
+
 func TestSave(t *testing.T) {
 	now := time.Now().Unix()
 
@@ -301,7 +1596,7 @@ func TestGetLeases(t *testing.T) {
 			},
 			exp: response{
 				data: nil,
-				err:  errors.NewValidation("lease", fmt.Errorf("id: must be empty.")),
+				err:  errors.NewValidation("lease", fmt.Errorf("id: must be empty.")), //nolint golint
 			},
 		},
 	}
@@ -324,3 +1619,63 @@ func TestGetLeases(t *testing.T) {
 	}
 
 }
+
+func TestListPages(t *testing.T) {
+	tests := []struct {
+		name  string
+		query *lease.Lease
+	}{
+		{
+			name:  "should read every page of a scan",
+			query: &lease.Lease{},
+		},
+		{
+			name: "should read every page of a status query",
+			query: &lease.Lease{
+				Status: lease.StatusActive.StatusPtr(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocksRWD := &mocks.ReaderWriterDeleter{}
+			mocksRWD.On("List", mock.MatchedBy(func(query *lease.Lease) bool {
+				return query.NextAccountID == nil
+			})).Run(func(args mock.Arguments) {
+				query := args.Get(0).(*lease.Lease)
+				query.NextAccountID = ptrString("123456789012")
+				query.NextPrincipalID = ptrString("user1")
+			}).Return(&lease.Leases{
+				lease.Lease{ID: ptrString("1")},
+			}, nil).Once()
+			mocksRWD.On("List", mock.MatchedBy(func(query *lease.Lease) bool {
+				return query.NextAccountID != nil
+			})).Run(func(args mock.Arguments) {
+				query := args.Get(0).(*lease.Lease)
+				assert.Equal(t, tt.query.Status, query.Status)
+				query.NextAccountID = nil
+				query.NextPrincipalID = nil
+			}).Return(&lease.Leases{
+				lease.Lease{ID: ptrString("2")},
+			}, nil).Once()
+
+			leasesSvc := lease.NewService(
+				lease.NewServiceInput{
+					DataSvc: mocksRWD,
+				},
+			)
+
+			ids := []string{}
+			err := leasesSvc.ListPages(tt.query, func(leases *lease.Leases) bool {
+				for _, l := range *leases {
+					ids = append(ids, *l.ID)
+				}
+				return true
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"1", "2"}, ids)
+			mocksRWD.AssertNumberOfCalls(t, "List", 2)
+		})
+	}
+}
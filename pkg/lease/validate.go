@@ -2,6 +2,7 @@ package lease
 
 import (
 	"errors"
+	"fmt"
 	"reflect"
 	"regexp"
 
@@ -22,6 +23,15 @@ var validateAccountID = []validation.Rule{
 	validation.Match(regexp.MustCompile("^[0-9]{12}$")).Error("must be a string with 12 digits"),
 }
 
+// validatePlaceholderAccountID validates the AccountID of a Pending or Scheduled lease,
+// which doesn't have an account yet
+func validatePlaceholderAccountID(placeholder string) []validation.Rule {
+	return []validation.Rule{
+		validation.NotNil.Error("must be a string"),
+		validation.In(placeholder).Error(fmt.Sprintf("must be %q", placeholder)),
+	}
+}
+
 var validatePrincipalID = []validation.Rule{
 	validation.NotNil.Error("must be a string"),
 }
@@ -30,6 +40,11 @@ var validateInt64 = []validation.Rule{
 	validation.NotNil.Error("must be an epoch timestamp"),
 }
 
+var validatePool = []validation.Rule{
+	validation.NilOrNotEmpty.Error("must be a pool name or empty"),
+	validation.Match(regexp.MustCompile("^[a-zA-Z0-9_-]{1,64}$")).Error("must only contain letters, numbers, hyphens and underscores"),
+}
+
 var validateStatus = []validation.Rule{
 	validation.NotNil.Error("must be a valid lease status"),
 }
@@ -48,3 +63,43 @@ func isLeaseActive(value interface{}) error {
 	}
 	return nil
 }
+
+func isNilOrNotBefore(current *int64) validation.RuleFunc {
+	return func(value interface{}) error {
+		v, _ := value.(*int64)
+		if v != nil && current != nil && *v < *current {
+			return errors.New("must not be before the current value")
+		}
+		return nil
+	}
+}
+
+func isNilOrNotLessThan(current *float64) validation.RuleFunc {
+	return func(value interface{}) error {
+		v, _ := value.(*float64)
+		if v != nil && current != nil && *v < *current {
+			return errors.New("must not be less than the current value")
+		}
+		return nil
+	}
+}
+
+func isNilOrInFuture(now int64) validation.RuleFunc {
+	return func(value interface{}) error {
+		v, _ := value.(*int64)
+		if v != nil && *v <= now {
+			return errors.New("must be in the future")
+		}
+		return nil
+	}
+}
+
+func isNilOrAfter(start *int64) validation.RuleFunc {
+	return func(value interface{}) error {
+		v, _ := value.(*int64)
+		if v != nil && start != nil && *v <= *start {
+			return errors.New("must be after startsOn")
+		}
+		return nil
+	}
+}
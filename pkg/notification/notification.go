@@ -0,0 +1,177 @@
+// Package notification sends messages to chat channels and webhooks,
+// as an alternative to email (see the email package).
+package notification
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Optum/dce/pkg/errors"
+)
+
+// Message is a notification to be sent to one or more targets
+type Message struct {
+	Subject string
+	Text    string
+}
+
+// TargetType is the type of channel a target is notified through
+type TargetType string
+
+const (
+	// TargetTypeWebhook posts messages as JSON to any URL
+	TargetTypeWebhook TargetType = "webhook"
+	// TargetTypeSlack posts messages to a Slack incoming webhook
+	TargetTypeSlack TargetType = "slack"
+	// TargetTypeTeams posts messages to a Microsoft Teams incoming webhook
+	TargetTypeTeams TargetType = "teams"
+)
+
+// Target is where a notification is sent to. Targets are written as
+// "<type>:<url>", eg. "slack:https://hooks.slack.com/services/T000/B000/XXXX"
+type Target struct {
+	Type TargetType
+	URL  string
+}
+
+// ParseTarget parses a notification target. Only HTTPS URLs to one of the
+// allowedHosts are supported, so targets can't be used to make requests to
+// internal services. Allowed hosts starting with "*." match any subdomain.
+func ParseTarget(target string, allowedHosts []string) (*Target, error) {
+	parts := strings.SplitN(target, ":", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("notification target must be formatted as \"<type>:<url>\"")
+	}
+
+	t := &Target{
+		Type: TargetType(parts[0]),
+		URL:  parts[1],
+	}
+	switch t.Type {
+	case TargetTypeWebhook, TargetTypeSlack, TargetTypeTeams:
+	default:
+		return nil, fmt.Errorf("notification target type must be one of %q, %q or %q",
+			TargetTypeWebhook, TargetTypeSlack, TargetTypeTeams)
+	}
+
+	u, err := url.Parse(t.URL)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("notification target URL must be an HTTPS URL")
+	}
+	if !isAllowedHost(u.Hostname(), allowedHosts) {
+		return nil, fmt.Errorf("notification target host %q is not allowed", u.Hostname())
+	}
+	return t, nil
+}
+
+// isAllowedHost checks the host against the allowed hosts,
+// where "*.example.com" matches any subdomain of example.com
+func isAllowedHost(host string, allowedHosts []string) bool {
+	host = strings.ToLower(host)
+	for _, allowed := range allowedHosts {
+		allowed = strings.ToLower(allowed)
+		if strings.HasPrefix(allowed, "*.") {
+			if strings.HasSuffix(host, allowed[1:]) {
+				return true
+			}
+			continue
+		}
+		if host == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactTarget hides the URL path of a notification target, which is
+// usually the webhook's secret, eg. "slack:https://hooks.slack.com/***"
+func RedactTarget(target string) string {
+	parts := strings.SplitN(target, ":", 2)
+	if len(parts) != 2 {
+		return "***"
+	}
+	u, err := url.Parse(parts[1])
+	if err != nil || u.Host == "" {
+		return parts[0] + ":***"
+	}
+	return fmt.Sprintf("%s:%s://%s/***", parts[0], u.Scheme, u.Host)
+}
+
+// RedactTargets redacts each of the notification targets
+func RedactTargets(targets []string) []string {
+	redacted := make([]string, len(targets))
+	for i, target := range targets {
+		redacted[i] = RedactTarget(target)
+	}
+	return redacted
+}
+
+// Channel sends messages to targets of a single type
+type Channel interface {
+	Send(targetURL string, message *Message) error
+}
+
+//go:generate mockery -name Service
+
+// Service sends messages to notification targets
+type Service interface {
+	Notify(targets []string, message *Message) error
+}
+
+// ChannelService sends messages through the channel for each target's type
+type ChannelService struct {
+	Channels     map[TargetType]Channel
+	AllowedHosts []string
+}
+
+// Notify sends the message to each target.
+// Every target is attempted, even when sending to another target fails.
+func (svc *ChannelService) Notify(targets []string, message *Message) error {
+	errs := []error{}
+	for _, target := range targets {
+		t, err := ParseTarget(target, svc.AllowedHosts)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		channel, ok := svc.Channels[t.Type]
+		if !ok {
+			errs = append(errs, fmt.Errorf("no channel is configured for %q notification targets", t.Type))
+			continue
+		}
+
+		err = channel.Send(t.URL, message)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.NewMultiError("failed to send notifications", errs)
+	}
+	return nil
+}
+
+// NewService creates a Service with the webhook, Slack and Teams channels,
+// which only sends to targets on the allowedHosts
+func NewService(allowedHosts []string) *ChannelService {
+	httpClient := &http.Client{
+		Timeout: 10 * time.Second,
+		// Redirects aren't followed, as they could lead to hosts which aren't allowed
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	return &ChannelService{
+		AllowedHosts: allowedHosts,
+		Channels: map[TargetType]Channel{
+			TargetTypeWebhook: &WebhookChannel{HTTPClient: httpClient},
+			TargetTypeSlack:   &SlackChannel{HTTPClient: httpClient},
+			TargetTypeTeams:   &TeamsChannel{HTTPClient: httpClient},
+		},
+	}
+}
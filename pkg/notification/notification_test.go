@@ -0,0 +1,189 @@
+package notification_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Optum/dce/pkg/notification"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		exp    *notification.Target
+		expErr string
+	}{
+		{
+			name:   "should parse a slack target",
+			target: "slack:https://hooks.slack.com/services/T000/B000/XXXX",
+			exp: &notification.Target{
+				Type: notification.TargetTypeSlack,
+				URL:  "https://hooks.slack.com/services/T000/B000/XXXX",
+			},
+		},
+		{
+			name:   "should parse a teams target",
+			target: "teams:https://example.webhook.office.com/webhookb2/abc",
+			exp: &notification.Target{
+				Type: notification.TargetTypeTeams,
+				URL:  "https://example.webhook.office.com/webhookb2/abc",
+			},
+		},
+		{
+			name:   "should fail without a type",
+			target: "hooks.slack.com",
+			expErr: "notification target must be formatted as \"<type>:<url>\"",
+		},
+		{
+			name:   "should fail on an unsupported type",
+			target: "sms:https://example.com",
+			expErr: "notification target type must be one of \"webhook\", \"slack\" or \"teams\"",
+		},
+		{
+			name:   "should fail on a non-HTTPS URL",
+			target: "webhook:http://example.com/hook",
+			expErr: "notification target URL must be an HTTPS URL",
+		},
+		{
+			name:   "should fail on a host which isn't allowed",
+			target: "webhook:https://169.254.169.254/latest/meta-data",
+			expErr: "notification target host \"169.254.169.254\" is not allowed",
+		},
+		{
+			name:   "should fail on a host which only ends with an allowed domain",
+			target: "teams:https://evilwebhook.office.com/webhookb2/abc",
+			expErr: "notification target host \"evilwebhook.office.com\" is not allowed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, err := notification.ParseTarget(tt.target, []string{"hooks.slack.com", "*.webhook.office.com"})
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.exp, target)
+		})
+	}
+}
+
+func TestNotify(t *testing.T) {
+	message := &notification.Message{
+		Subject: "Lease over budget",
+		Text:    "Your lease has exceeded its budget",
+	}
+
+	tests := []struct {
+		name       string
+		targetType notification.TargetType
+		status     int
+		expBody    map[string]string
+		expErr     string
+	}{
+		{
+			name:       "should post to a webhook",
+			targetType: notification.TargetTypeWebhook,
+			status:     http.StatusOK,
+			expBody: map[string]string{
+				"subject": "Lease over budget",
+				"text":    "Your lease has exceeded its budget",
+			},
+		},
+		{
+			name:       "should post to slack",
+			targetType: notification.TargetTypeSlack,
+			status:     http.StatusOK,
+			expBody: map[string]string{
+				"text": "*Lease over budget*\nYour lease has exceeded its budget",
+			},
+		},
+		{
+			name:       "should post a message card to teams",
+			targetType: notification.TargetTypeTeams,
+			status:     http.StatusOK,
+			expBody: map[string]string{
+				"@type":    "MessageCard",
+				"@context": "https://schema.org/extensions",
+				"summary":  "Lease over budget",
+				"title":    "Lease over budget",
+				"text":     "Your lease has exceeded its budget",
+			},
+		},
+		{
+			name:       "should fail when the webhook fails",
+			targetType: notification.TargetTypeSlack,
+			status:     http.StatusNotFound,
+			expBody: map[string]string{
+				"text": "*Lease over budget*\nYour lease has exceeded its budget",
+			},
+			expErr: "failed to send notifications: notification webhook responded with status 404",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body map[string]string
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "POST", r.Method)
+				assert.Equal(t, "/hook", r.URL.Path)
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				err := json.NewDecoder(r.Body).Decode(&body)
+				assert.Nil(t, err)
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			svc := notification.NewService([]string{"127.0.0.1"})
+			svc.Channels = map[notification.TargetType]notification.Channel{
+				notification.TargetTypeWebhook: &notification.WebhookChannel{HTTPClient: server.Client()},
+				notification.TargetTypeSlack:   &notification.SlackChannel{HTTPClient: server.Client()},
+				notification.TargetTypeTeams:   &notification.TeamsChannel{HTTPClient: server.Client()},
+			}
+
+			err := svc.Notify([]string{string(tt.targetType) + ":" + server.URL + "/hook"}, message)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+			} else {
+				assert.Nil(t, err)
+			}
+			assert.Equal(t, tt.expBody, body)
+		})
+	}
+
+	t.Run("should attempt every target", func(t *testing.T) {
+		calls := 0
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+		}))
+		defer server.Close()
+
+		svc := &notification.ChannelService{
+			Channels: map[notification.TargetType]notification.Channel{
+				notification.TargetTypeWebhook: &notification.WebhookChannel{HTTPClient: server.Client()},
+			},
+			AllowedHosts: []string{"127.0.0.1"},
+		}
+
+		err := svc.Notify([]string{
+			"slack:" + server.URL,
+			"webhook:" + server.URL,
+		}, message)
+		assert.EqualError(t, err, "failed to send notifications: no channel is configured for \"slack\" notification targets")
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func TestRedactTarget(t *testing.T) {
+	assert.Equal(t, "slack:https://hooks.slack.com/***",
+		notification.RedactTarget("slack:https://hooks.slack.com/services/T000/B000/XXXX"))
+	assert.Equal(t, "webhook:***", notification.RedactTarget("webhook:not a url"))
+	assert.Equal(t, "***", notification.RedactTarget("hooks.slack.com"))
+	assert.Equal(t, []string{"teams:https://example.webhook.office.com/***"},
+		notification.RedactTargets([]string{"teams:https://example.webhook.office.com/webhookb2/abc"}))
+}
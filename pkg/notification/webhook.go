@@ -0,0 +1,80 @@
+package notification
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+// WebhookChannel posts messages as JSON to a URL:
+// {"subject": "...", "text": "..."}
+type WebhookChannel struct {
+	HTTPClient *http.Client
+}
+
+// Send posts the message to the webhook URL
+func (c *WebhookChannel) Send(targetURL string, message *Message) error {
+	return postJSON(c.HTTPClient, targetURL, map[string]string{
+		"subject": message.Subject,
+		"text":    message.Text,
+	})
+}
+
+// SlackChannel posts messages to Slack incoming webhooks.
+// See https://api.slack.com/messaging/webhooks
+type SlackChannel struct {
+	HTTPClient *http.Client
+}
+
+// Send posts the message to the Slack webhook URL
+func (c *SlackChannel) Send(targetURL string, message *Message) error {
+	return postJSON(c.HTTPClient, targetURL, map[string]string{
+		"text": fmt.Sprintf("*%s*\n%s", message.Subject, message.Text),
+	})
+}
+
+// TeamsChannel posts messages to Microsoft Teams incoming webhooks, as message cards.
+// See https://docs.microsoft.com/en-us/microsoftteams/platform/webhooks-and-connectors/how-to/connectors-using
+type TeamsChannel struct {
+	HTTPClient *http.Client
+}
+
+// Send posts the message to the Teams webhook URL
+func (c *TeamsChannel) Send(targetURL string, message *Message) error {
+	return postJSON(c.HTTPClient, targetURL, map[string]string{
+		"@type":    "MessageCard",
+		"@context": "https://schema.org/extensions",
+		"summary":  message.Subject,
+		"title":    message.Subject,
+		"text":     message.Text,
+	})
+}
+
+// postJSON posts the body as JSON, and fails on any non-2xx response
+func postJSON(httpClient *http.Client, targetURL string, body interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	res, err := httpClient.Post(targetURL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		// Webhook URLs are secrets, so they're left out of the error
+		if urlErr, ok := err.(*url.Error); ok {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("failed to post to notification webhook: %s", err)
+	}
+	defer res.Body.Close()
+	// Drain the body, so the connection may be reused
+	_, _ = io.Copy(ioutil.Discard, res.Body)
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("notification webhook responded with status %d", res.StatusCode)
+	}
+	return nil
+}
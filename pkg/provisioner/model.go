@@ -0,0 +1,16 @@
+package provisioner
+
+// Request is a request to AWS Organizations to create an account for the pool.
+// Requests are recorded, so the provisioner only registers accounts it created,
+// and only registers each of them once.
+type Request struct {
+	ID           *string `json:"id,omitempty" dynamodbav:"Id"`                               // Organizations CreateAccountStatus ID
+	AccountName  *string `json:"accountName,omitempty" dynamodbav:"AccountName,omitempty"`   // Name of the requested account
+	AccountID    *string `json:"accountId,omitempty" dynamodbav:"AccountId,omitempty"`       // AWS Account ID, once created
+	CreatedOn    *int64  `json:"createdOn,omitempty" dynamodbav:"CreatedOn,omitempty"`       // Request Epoch Timestamp
+	RegisteredOn *int64  `json:"registeredOn,omitempty" dynamodbav:"RegisteredOn,omitempty"` // Epoch Timestamp the account was added to the pool
+	TimeToLive   *int64  `json:"timeToLive,omitempty" dynamodbav:"TimeToLive,omitempty"`     // Epoch Timestamp the record expires, after Organizations stops listing the request
+}
+
+// Requests is a list of type Request
+type Requests []Request
@@ -0,0 +1,9 @@
+//
+
+package provisioneriface
+
+// Servicer makes working with the Provisioner easier
+type Servicer interface {
+	// Replenish creates new accounts when the number of Ready accounts is too low
+	Replenish(readyCount int) error
+}
@@ -0,0 +1,242 @@
+package provisioner
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Optum/dce/pkg/account"
+	"github.com/Optum/dce/pkg/arn"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/google/uuid"
+)
+
+// OrganizationsAPI is the part of the AWS Organizations API used to create accounts
+type OrganizationsAPI interface {
+	CreateAccount(input *organizations.CreateAccountInput) (*organizations.CreateAccountOutput, error)
+	ListCreateAccountStatusPages(input *organizations.ListCreateAccountStatusInput, fn func(*organizations.ListCreateAccountStatusOutput, bool) bool) error
+}
+
+// AccountCreator looks up and registers accounts in the account pool
+type AccountCreator interface {
+	Get(ID string) (*account.Account, error)
+	Create(data *account.Account) (*account.Account, error)
+}
+
+//go:generate mockery -name RequestReaderWriter
+
+// RequestReaderWriter records the requests to create accounts made by the provisioner
+type RequestReaderWriter interface {
+	Write(data *Request) error
+	List() (*Requests, error)
+}
+
+// requestTTL is how long requests are recorded for.
+// Organizations lists requests to create accounts for 90 days.
+const requestTTL = 90 * 24 * time.Hour
+
+// ServiceConfig has specific static values for the service configuration
+type ServiceConfig struct {
+	MinReadyAccounts   int    `env:"PROVISIONER_MIN_READY_ACCOUNTS" envDefault:"0"`
+	MaxAccountsPerRun  int    `env:"PROVISIONER_MAX_ACCOUNTS_PER_RUN" envDefault:"5"`
+	AccountNamePrefix  string `env:"PROVISIONER_ACCOUNT_NAME_PREFIX" envDefault:"dce-"`
+	AccountEmailFormat string `env:"PROVISIONER_ACCOUNT_EMAIL_FORMAT" envDefault:""`
+	AdminRoleName      string `env:"PROVISIONER_ADMIN_ROLE_NAME" envDefault:"OrganizationAccountAccessRole"`
+}
+
+// Service creates new accounts in AWS Organizations, to keep the account pool topped up
+type Service struct {
+	orgSvc     OrganizationsAPI
+	accountSvc AccountCreator
+	requestSvc RequestReaderWriter
+	config     ServiceConfig
+}
+
+// Replenish registers accounts which have finished being created, and creates
+// new accounts when the number of Ready accounts drops below the configured minimum.
+// Only accounts requested by the provisioner are registered, and only once,
+// so accounts which an admin has since removed from the pool aren't registered again.
+// Accounts which are still being created, or are being reset after registration,
+// count towards the minimum, so they aren't created twice.
+func (s *Service) Replenish(readyCount int) error {
+	if s.config.MinReadyAccounts <= 0 {
+		return nil
+	}
+
+	requests, err := s.requestSvc.List()
+	if err != nil {
+		return err
+	}
+	requestsByID := map[string]*Request{}
+	for i, request := range *requests {
+		requestsByID[*request.ID] = &(*requests)[i]
+	}
+
+	statuses, err := s.listCreateAccountStatuses()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	inProgress := 0
+	for _, status := range statuses {
+		request, ok := requestsByID[*status.Id]
+		// Ignore accounts which weren't requested by the provisioner
+		if !ok {
+			continue
+		}
+
+		switch *status.State {
+		case organizations.CreateAccountStateInProgress:
+			inProgress++
+		case organizations.CreateAccountStateSucceeded:
+			acct, err := s.register(request, *status.AccountId)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if acct != nil && acct.Status != nil && *acct.Status == account.StatusNotReady {
+				inProgress++
+			}
+		}
+	}
+
+	needed := s.config.MinReadyAccounts - readyCount - inProgress
+	if needed > s.config.MaxAccountsPerRun {
+		needed = s.config.MaxAccountsPerRun
+	}
+	for i := 0; i < needed; i++ {
+		err = s.createAccount()
+		if err != nil {
+			// Further requests are likely to fail the same way
+			errs = append(errs, err)
+			break
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.NewMultiError("error when replenishing account pool", errs)
+	}
+	return nil
+}
+
+// listCreateAccountStatuses returns the Organizations requests to create accounts,
+// which are in progress or have succeeded
+func (s *Service) listCreateAccountStatuses() ([]*organizations.CreateAccountStatus, error) {
+	statuses := []*organizations.CreateAccountStatus{}
+	err := s.orgSvc.ListCreateAccountStatusPages(&organizations.ListCreateAccountStatusInput{
+		States: aws.StringSlice([]string{
+			organizations.CreateAccountStateInProgress,
+			organizations.CreateAccountStateSucceeded,
+		}),
+	}, func(out *organizations.ListCreateAccountStatusOutput, lastPage bool) bool {
+		statuses = append(statuses, out.CreateAccountStatuses...)
+		return true
+	})
+	if err != nil {
+		return nil, errors.NewInternalServer("failed to list create account requests", err)
+	}
+	return statuses, nil
+}
+
+// register adds a newly created account to the account pool, if its request
+// hasn't been registered already. Returns the account, or nil if it was
+// registered, and has since been removed from the pool.
+func (s *Service) register(request *Request, accountID string) (*account.Account, error) {
+	acct, err := s.accountSvc.Get(accountID)
+	isNotFound := errors.Is(err, errors.NewNotFound("account", accountID))
+	if err != nil && !isNotFound {
+		return nil, err
+	}
+	if request.RegisteredOn != nil {
+		if isNotFound {
+			return nil, nil
+		}
+		return acct, nil
+	}
+
+	if isNotFound {
+		// Organizations creates the admin role in new accounts,
+		// and allows the master account to assume it
+		adminRoleArn := arn.New("aws", "iam", "", accountID, "role/"+s.config.AdminRoleName)
+		acct, err = s.accountSvc.Create(&account.Account{
+			ID:           &accountID,
+			AdminRoleArn: adminRoleArn,
+			Metadata: map[string]interface{}{
+				"ProvisionedBy": "dce-provisioner",
+			},
+		})
+		if err != nil {
+			log.Printf("Failed to register new account %s: %s", accountID, err)
+			return nil, err
+		}
+		log.Printf("Registered new account %s", accountID)
+	}
+
+	registeredOn := time.Now().Unix()
+	request.AccountID = &accountID
+	request.RegisteredOn = &registeredOn
+	err = s.requestSvc.Write(request)
+	if err != nil {
+		log.Printf("Failed to record registration of account %s: %s", accountID, err)
+		return nil, err
+	}
+	return acct, nil
+}
+
+// createAccount requests a new account from Organizations
+func (s *Service) createAccount() error {
+	name := s.config.AccountNamePrefix + strings.Split(uuid.New().String(), "-")[0]
+	email := fmt.Sprintf(s.config.AccountEmailFormat, name)
+
+	out, err := s.orgSvc.CreateAccount(&organizations.CreateAccountInput{
+		AccountName: aws.String(name),
+		Email:       aws.String(email),
+		RoleName:    aws.String(s.config.AdminRoleName),
+	})
+	if err != nil {
+		log.Printf("Failed to create account %s: %s", name, err)
+		return errors.NewInternalServer("failed to create account", err)
+	}
+	log.Printf("Requested new account %s (%s), request %s", name, email, *out.CreateAccountStatus.Id)
+
+	now := time.Now()
+	err = s.requestSvc.Write(&Request{
+		ID:          out.CreateAccountStatus.Id,
+		AccountName: &name,
+		CreatedOn:   aws.Int64(now.Unix()),
+		TimeToLive:  aws.Int64(now.Add(requestTTL).Unix()),
+	})
+	if err != nil {
+		log.Printf("Failed to record request %s for account %s. The account must be added to the pool manually: %s",
+			*out.CreateAccountStatus.Id, name, err)
+		return err
+	}
+	return nil
+}
+
+// NewServiceInput are the items needed to create a new service
+type NewServiceInput struct {
+	OrgSvc     OrganizationsAPI
+	AccountSvc AccountCreator
+	RequestSvc RequestReaderWriter
+	Config     ServiceConfig
+}
+
+// NewService creates a new provisioner service
+func NewService(input NewServiceInput) (*Service, error) {
+	if input.Config.MinReadyAccounts > 0 && !strings.Contains(input.Config.AccountEmailFormat, "%s") {
+		return nil, errors.NewValidation("provisioner",
+			fmt.Errorf("account email format %q must contain %%s, to make each account email unique", input.Config.AccountEmailFormat))
+	}
+
+	return &Service{
+		orgSvc:     input.OrgSvc,
+		accountSvc: input.AccountSvc,
+		requestSvc: input.RequestSvc,
+		config:     input.Config,
+	}, nil
+}
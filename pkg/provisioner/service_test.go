@@ -0,0 +1,335 @@
+package provisioner_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Optum/dce/pkg/account"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/provisioner"
+	"github.com/Optum/dce/pkg/provisioner/mocks"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func createAccountStatus(name string, state string, accountID string) *organizations.CreateAccountStatus {
+	status := &organizations.CreateAccountStatus{
+		Id:          aws.String("car-" + name),
+		AccountName: aws.String(name),
+		State:       aws.String(state),
+	}
+	if accountID != "" {
+		status.AccountId = aws.String(accountID)
+	}
+	return status
+}
+
+// requestsFor returns the provisioner's records of the given requests,
+// none of which have been registered yet
+func requestsFor(statuses []*organizations.CreateAccountStatus) *provisioner.Requests {
+	requests := provisioner.Requests{}
+	for _, status := range statuses {
+		requests = append(requests, provisioner.Request{
+			ID:          status.Id,
+			AccountName: status.AccountName,
+		})
+	}
+	return &requests
+}
+
+func TestReplenish(t *testing.T) {
+	config := provisioner.ServiceConfig{
+		MinReadyAccounts:   3,
+		MaxAccountsPerRun:  5,
+		AccountNamePrefix:  "dce-test-",
+		AccountEmailFormat: "aws+%s@example.com",
+		AdminRoleName:      "OrganizationAccountAccessRole",
+	}
+
+	type registered struct {
+		accountID string
+		getErr    error
+		getAcct   *account.Account
+		createErr error
+	}
+
+	tests := []struct {
+		name        string
+		config      *provisioner.ServiceConfig
+		readyCount  int
+		statuses    []*organizations.CreateAccountStatus
+		requests    *provisioner.Requests
+		requestErr  error
+		listErr     error
+		registered  []registered
+		createErr   error
+		expCreates  int
+		expRegister []string
+		expRecorded []string
+		expErr      error
+	}{
+		{
+			name:       "should create accounts to reach the minimum",
+			readyCount: 1,
+			expCreates: 2,
+		},
+		{
+			name:       "should do nothing when there are enough ready accounts",
+			readyCount: 3,
+			expCreates: 0,
+		},
+		{
+			name: "should do nothing when disabled",
+			config: &provisioner.ServiceConfig{
+				MinReadyAccounts: 0,
+			},
+			readyCount: 0,
+			expCreates: 0,
+		},
+		{
+			name: "should create no more than the maximum per run",
+			config: &provisioner.ServiceConfig{
+				MinReadyAccounts:   10,
+				MaxAccountsPerRun:  2,
+				AccountNamePrefix:  "dce-test-",
+				AccountEmailFormat: "aws+%s@example.com",
+				AdminRoleName:      "OrganizationAccountAccessRole",
+			},
+			readyCount: 0,
+			expCreates: 2,
+		},
+		{
+			name:       "should count accounts which are still being created",
+			readyCount: 1,
+			statuses: []*organizations.CreateAccountStatus{
+				createAccountStatus("dce-test-aaaa", organizations.CreateAccountStateInProgress, ""),
+			},
+			expCreates: 1,
+		},
+		{
+			name:       "should ignore requests not made by the provisioner",
+			readyCount: 1,
+			statuses: []*organizations.CreateAccountStatus{
+				createAccountStatus("some-other-account", organizations.CreateAccountStateInProgress, ""),
+				createAccountStatus("dce-test-aaaa", organizations.CreateAccountStateSucceeded, "123456789012"),
+			},
+			requests:   &provisioner.Requests{},
+			expCreates: 2,
+		},
+		{
+			name:       "should register accounts which have been created",
+			readyCount: 1,
+			statuses: []*organizations.CreateAccountStatus{
+				createAccountStatus("dce-test-aaaa", organizations.CreateAccountStateSucceeded, "123456789012"),
+				createAccountStatus("dce-test-bbbb", organizations.CreateAccountStateSucceeded, "123456789013"),
+			},
+			registered: []registered{
+				{
+					accountID: "123456789012",
+					getErr:    errors.NewNotFound("account", "123456789012"),
+				},
+				{
+					// Already registered, and now Ready
+					accountID: "123456789013",
+					getAcct: &account.Account{
+						ID:     aws.String("123456789013"),
+						Status: account.StatusReady.StatusPtr(),
+					},
+				},
+			},
+			expRegister: []string{"123456789012"},
+			expRecorded: []string{"123456789012", "123456789013"},
+			// The newly registered account is resetting, so only one more is needed
+			expCreates: 1,
+		},
+		{
+			name:       "should not register accounts removed from the pool after registration",
+			readyCount: 1,
+			statuses: []*organizations.CreateAccountStatus{
+				createAccountStatus("dce-test-aaaa", organizations.CreateAccountStateSucceeded, "123456789012"),
+			},
+			requests: &provisioner.Requests{
+				{
+					ID:           aws.String("car-dce-test-aaaa"),
+					AccountName:  aws.String("dce-test-aaaa"),
+					AccountID:    aws.String("123456789012"),
+					RegisteredOn: aws.Int64(1573592058),
+				},
+			},
+			registered: []registered{
+				{
+					// Deleted by an admin
+					accountID: "123456789012",
+					getErr:    errors.NewNotFound("account", "123456789012"),
+				},
+			},
+			expCreates: 2,
+		},
+		{
+			name:       "should continue when registering an account fails",
+			readyCount: 2,
+			statuses: []*organizations.CreateAccountStatus{
+				createAccountStatus("dce-test-aaaa", organizations.CreateAccountStateSucceeded, "123456789012"),
+			},
+			registered: []registered{
+				{
+					accountID: "123456789012",
+					getErr:    errors.NewNotFound("account", "123456789012"),
+					createErr: errors.NewInternalServer("failure", fmt.Errorf("error")),
+				},
+			},
+			expRegister: []string{"123456789012"},
+			expCreates:  1,
+			expErr: errors.NewMultiError("error when replenishing account pool", []error{
+				errors.NewInternalServer("failure", fmt.Errorf("error")),
+			}),
+		},
+		{
+			name:       "should stop creating accounts after a failure",
+			readyCount: 0,
+			createErr:  fmt.Errorf("error"),
+			expCreates: 1,
+			expErr: errors.NewMultiError("error when replenishing account pool", []error{
+				errors.NewInternalServer("failed to create account", fmt.Errorf("error")),
+			}),
+		},
+		{
+			name:       "should fail when listing create account requests fails",
+			readyCount: 0,
+			listErr:    fmt.Errorf("error"),
+			expCreates: 0,
+			expErr:     errors.NewInternalServer("failed to list create account requests", fmt.Errorf("error")),
+		},
+		{
+			name:       "should fail when listing the provisioner's requests fails",
+			readyCount: 0,
+			requestErr: errors.NewInternalServer("error getting provisioner requests", fmt.Errorf("error")),
+			expCreates: 0,
+			expErr:     errors.NewInternalServer("error getting provisioner requests", fmt.Errorf("error")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orgSvc := &mocks.OrganizationsAPI{}
+			accountSvc := &mocks.AccountCreator{}
+			requestSvc := &mocks.RequestReaderWriter{}
+
+			requests := tt.requests
+			if requests == nil {
+				requests = requestsFor(tt.statuses)
+			}
+			requestSvc.On("List").Return(requests, tt.requestErr)
+			requestSvc.On("Write", mock.AnythingOfType("*provisioner.Request")).Return(nil)
+
+			statuses := tt.statuses
+			orgSvc.On("ListCreateAccountStatusPages", mock.MatchedBy(func(input *organizations.ListCreateAccountStatusInput) bool {
+				return assert.ObjectsAreEqual(aws.StringSlice([]string{
+					organizations.CreateAccountStateInProgress,
+					organizations.CreateAccountStateSucceeded,
+				}), input.States)
+			}), mock.Anything).
+				Run(func(args mock.Arguments) {
+					fn := args.Get(1).(func(*organizations.ListCreateAccountStatusOutput, bool) bool)
+					fn(&organizations.ListCreateAccountStatusOutput{
+						CreateAccountStatuses: statuses,
+					}, true)
+				}).
+				Return(tt.listErr)
+			orgSvc.On("CreateAccount", mock.AnythingOfType("*organizations.CreateAccountInput")).
+				Return(&organizations.CreateAccountOutput{
+					CreateAccountStatus: &organizations.CreateAccountStatus{
+						Id: aws.String("car-new"),
+					},
+				}, tt.createErr)
+
+			for _, r := range tt.registered {
+				r := r
+				accountSvc.On("Get", r.accountID).Return(r.getAcct, r.getErr)
+				accountSvc.On("Create", mock.MatchedBy(func(input *account.Account) bool {
+					return *input.ID == r.accountID
+				})).Return(&account.Account{
+					ID:     aws.String(r.accountID),
+					Status: account.StatusNotReady.StatusPtr(),
+				}, r.createErr)
+			}
+
+			cfg := config
+			if tt.config != nil {
+				cfg = *tt.config
+			}
+			svc, err := provisioner.NewService(provisioner.NewServiceInput{
+				OrgSvc:     orgSvc,
+				AccountSvc: accountSvc,
+				RequestSvc: requestSvc,
+				Config:     cfg,
+			})
+			assert.Nil(t, err)
+
+			err = svc.Replenish(tt.readyCount)
+			assert.Truef(t, errors.Is(err, tt.expErr), "actual error %q doesn't match expected error %q", err, tt.expErr)
+
+			orgSvc.AssertNumberOfCalls(t, "CreateAccount", tt.expCreates)
+			if tt.expCreates > 0 {
+				orgSvc.AssertCalled(t, "CreateAccount", mock.MatchedBy(func(input *organizations.CreateAccountInput) bool {
+					return strings.HasPrefix(*input.AccountName, cfg.AccountNamePrefix) &&
+						*input.Email == "aws+"+*input.AccountName+"@example.com" &&
+						*input.RoleName == "OrganizationAccountAccessRole"
+				}))
+			}
+
+			for _, accountID := range tt.expRegister {
+				accountSvc.AssertCalled(t, "Create", mock.MatchedBy(func(input *account.Account) bool {
+					return *input.ID == accountID &&
+						input.AdminRoleArn.String() == "arn:aws:iam::"+accountID+":role/OrganizationAccountAccessRole"
+				}))
+			}
+			if len(tt.expRegister) == 0 {
+				accountSvc.AssertNotCalled(t, "Create", mock.Anything)
+			}
+
+			// Each new request, and each registration, is recorded
+			expRequests := tt.expCreates
+			if tt.createErr != nil {
+				expRequests = 0
+			}
+			requestSvc.AssertNumberOfCalls(t, "Write", expRequests+len(tt.expRecorded))
+			if expRequests > 0 {
+				requestSvc.AssertCalled(t, "Write", mock.MatchedBy(func(input *provisioner.Request) bool {
+					return *input.ID == "car-new" &&
+						strings.HasPrefix(*input.AccountName, cfg.AccountNamePrefix) &&
+						*input.TimeToLive > *input.CreatedOn
+				}))
+			}
+			for _, accountID := range tt.expRecorded {
+				requestSvc.AssertCalled(t, "Write", mock.MatchedBy(func(input *provisioner.Request) bool {
+					return input.AccountID != nil && *input.AccountID == accountID && input.RegisteredOn != nil
+				}))
+			}
+		})
+	}
+}
+
+func TestNewService(t *testing.T) {
+	t.Run("should require a unique email for each account", func(t *testing.T) {
+		_, err := provisioner.NewService(provisioner.NewServiceInput{
+			Config: provisioner.ServiceConfig{
+				MinReadyAccounts:   1,
+				AccountEmailFormat: "aws@example.com",
+			},
+		})
+		assert.NotNil(t, err)
+	})
+
+	t.Run("should not require an email when disabled", func(t *testing.T) {
+		_, err := provisioner.NewService(provisioner.NewServiceInput{
+			Config: provisioner.ServiceConfig{
+				MinReadyAccounts: 0,
+			},
+		})
+		assert.Nil(t, err)
+	})
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/service/athena"
 	"github.com/aws/aws-sdk-go/service/athena/athenaiface"
+	"github.com/pkg/errors"
 )
 
 // AthenaService interface
@@ -42,23 +43,47 @@ func (athenaReset AthenaReset) DeleteNamedQuery(input *athena.DeleteNamedQueryIn
 
 // DeleteAthenaResources deletes all aethna resources in the current aws session
 func DeleteAthenaResources(athenaSvc AthenaService) error {
+	return deleteAthenaResources(athenaSvc, &ResetterInput{})
+}
+
+// deleteAthenaResources deletes all athena workgroups, except the primary
+// workgroup which can't be deleted, and all named queries.
+// Every page of results is listed before anything is deleted,
+// so deletes don't invalidate the next page token.
+// Resources matching the nuke filters of the input are kept.
+func deleteAthenaResources(athenaSvc AthenaService, input *ResetterInput) error {
 
 	var maxResult int64 = 50
 	// Delete all workgroups
+	workGroups := []*athena.WorkGroupSummary{}
 	listWorkGroupsInput := &athena.ListWorkGroupsInput{
 		MaxResults: &maxResult,
 	}
-	listWorkGroupsOutput, err := athenaSvc.ListWorkGroups(listWorkGroupsInput)
-	if err != nil {
-		return err
+	for {
+		listWorkGroupsOutput, err := athenaSvc.ListWorkGroups(listWorkGroupsInput)
+		if err != nil {
+			return err
+		}
+		workGroups = append(workGroups, listWorkGroupsOutput.WorkGroups...)
+		if listWorkGroupsOutput.NextToken == nil {
+			break
+		}
+		listWorkGroupsInput.NextToken = listWorkGroupsOutput.NextToken
 	}
 
-	for _, workGroup := range listWorkGroupsOutput.WorkGroups {
+	for _, workGroup := range workGroups {
 		isDelete := true
 		log.Printf("Starting Athena workgroup list %v", workGroup)
 		if *workGroup.Name == "primary" {
 			continue
 		}
+		if input.Keep("AthenaWorkGroup", *workGroup.Name, map[string]string{"Name": *workGroup.Name}) {
+			continue
+		}
+		if input.DryRun {
+			log.Printf("Would delete Athena workgroup %s", *workGroup.Name)
+			continue
+		}
 		deleteWorkGroupInput := &athena.DeleteWorkGroupInput{
 			RecursiveDeleteOption: &isDelete,
 			WorkGroup:             workGroup.Name,
@@ -72,13 +97,30 @@ func DeleteAthenaResources(athenaSvc AthenaService) error {
 	}
 
 	// Delete all namedqueries
-	listNamedQueriesInput := &athena.ListNamedQueriesInput{}
-	listNamedQueriesOutput, err := athenaSvc.ListNamedQueries(listNamedQueriesInput)
-	if err != nil {
-		return err
+	namedQueries := []*string{}
+	listNamedQueriesInput := &athena.ListNamedQueriesInput{
+		MaxResults: &maxResult,
+	}
+	for {
+		listNamedQueriesOutput, err := athenaSvc.ListNamedQueries(listNamedQueriesInput)
+		if err != nil {
+			return err
+		}
+		namedQueries = append(namedQueries, listNamedQueriesOutput.NamedQueryIds...)
+		if listNamedQueriesOutput.NextToken == nil {
+			break
+		}
+		listNamedQueriesInput.NextToken = listNamedQueriesOutput.NextToken
 	}
 
-	for _, namedQuery := range listNamedQueriesOutput.NamedQueryIds {
+	for _, namedQuery := range namedQueries {
+		if input.Keep("AthenaNamedQuery", *namedQuery, map[string]string{"ID": *namedQuery}) {
+			continue
+		}
+		if input.DryRun {
+			log.Printf("Would delete Athena namedquery %s", *namedQuery)
+			continue
+		}
 		log.Printf("Starting Athena namedquery delete %v", *namedQuery)
 		deleteNamedQueryInput := &athena.DeleteNamedQueryInput{
 			NamedQueryId: namedQuery,
@@ -91,3 +133,31 @@ func DeleteAthenaResources(athenaSvc AthenaService) error {
 
 	return nil
 }
+
+// AthenaResetter deletes Athena workgroups and named queries in each region
+type AthenaResetter struct {
+	// NewClient returns an Athena client for the region
+	NewClient func(region string) athenaiface.AthenaAPI
+}
+
+// Name of the resetter
+func (r *AthenaResetter) Name() string {
+	return "Athena"
+}
+
+// Phase the resetter runs in
+func (r *AthenaResetter) Phase() Phase {
+	return PhasePreNuke
+}
+
+// Reset deletes the Athena resources in each region
+func (r *AthenaResetter) Reset(input *ResetterInput) error {
+	for _, region := range input.Regions {
+		log.Printf("Starting Athena reset in %s", region)
+		err := deleteAthenaResources(AthenaReset{Client: r.NewClient(region)}, input)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to delete Athena resources in %s", region)
+		}
+	}
+	return nil
+}
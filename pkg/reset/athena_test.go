@@ -49,3 +49,84 @@ func TestDeleteAthenaResources(t *testing.T) {
 	err := DeleteAthenaResources(mockAthena)
 	assert.Nil(t, err, "There should be no errors")
 }
+
+// mockPagedAthenaReset returns workgroups and named queries over two pages,
+// and records what was deleted
+type mockPagedAthenaReset struct {
+	athenaiface.AthenaAPI
+	deletedWorkGroups   []string
+	deletedNamedQueries []string
+}
+
+func (athenaReset *mockPagedAthenaReset) ListWorkGroups(input *athena.ListWorkGroupsInput) (*athena.ListWorkGroupsOutput, error) {
+	if input.NextToken == nil {
+		return &athena.ListWorkGroupsOutput{
+			WorkGroups: []*athena.WorkGroupSummary{{Name: aws.String("primary")}, {Name: aws.String("wg1")}},
+			NextToken:  aws.String("page2"),
+		}, nil
+	}
+	return &athena.ListWorkGroupsOutput{
+		WorkGroups: []*athena.WorkGroupSummary{{Name: aws.String("wg2")}},
+	}, nil
+}
+
+func (athenaReset *mockPagedAthenaReset) ListNamedQueries(input *athena.ListNamedQueriesInput) (*athena.ListNamedQueriesOutput, error) {
+	if input.NextToken == nil {
+		return &athena.ListNamedQueriesOutput{
+			NamedQueryIds: []*string{aws.String("test-query-1")},
+			NextToken:     aws.String("page2"),
+		}, nil
+	}
+	return &athena.ListNamedQueriesOutput{
+		NamedQueryIds: []*string{aws.String("test-query-2")},
+	}, nil
+}
+
+func (athenaReset *mockPagedAthenaReset) DeleteWorkGroup(input *athena.DeleteWorkGroupInput) (*athena.DeleteWorkGroupOutput, error) {
+	athenaReset.deletedWorkGroups = append(athenaReset.deletedWorkGroups, *input.WorkGroup)
+	return &athena.DeleteWorkGroupOutput{}, nil
+}
+
+func (athenaReset *mockPagedAthenaReset) DeleteNamedQuery(input *athena.DeleteNamedQueryInput) (*athena.DeleteNamedQueryOutput, error) {
+	athenaReset.deletedNamedQueries = append(athenaReset.deletedNamedQueries, *input.NamedQueryId)
+	return &athena.DeleteNamedQueryOutput{}, nil
+}
+
+func TestDeleteAthenaResourcesPaged(t *testing.T) {
+	mockAthena := &mockPagedAthenaReset{}
+	err := DeleteAthenaResources(mockAthena)
+	assert.Nil(t, err, "There should be no errors")
+	assert.Equal(t, []string{"wg1", "wg2"}, mockAthena.deletedWorkGroups)
+	assert.Equal(t, []string{"test-query-1", "test-query-2"}, mockAthena.deletedNamedQueries)
+}
+
+func TestAthenaResetter(t *testing.T) {
+	t.Run("should delete in each region", func(t *testing.T) {
+		clients := map[string]*mockPagedAthenaReset{}
+		resetter := &AthenaResetter{
+			NewClient: func(region string) athenaiface.AthenaAPI {
+				clients[region] = &mockPagedAthenaReset{}
+				return clients[region]
+			},
+		}
+
+		err := resetter.Reset(&ResetterInput{Regions: []string{"us-east-1", "us-west-2"}})
+		assert.Nil(t, err)
+		assert.Len(t, clients, 2)
+		assert.Equal(t, []string{"wg1", "wg2"}, clients["us-west-2"].deletedWorkGroups)
+	})
+
+	t.Run("should not delete in dry run mode", func(t *testing.T) {
+		client := &mockPagedAthenaReset{}
+		resetter := &AthenaResetter{
+			NewClient: func(region string) athenaiface.AthenaAPI {
+				return client
+			},
+		}
+
+		err := resetter.Reset(&ResetterInput{Regions: []string{"us-east-1"}, DryRun: true})
+		assert.Nil(t, err)
+		assert.Empty(t, client.deletedWorkGroups)
+		assert.Empty(t, client.deletedNamedQueries)
+	})
+}
@@ -0,0 +1,42 @@
+package reset
+
+import (
+	"github.com/Optum/dce/pkg/account"
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// EnvironmentVariable configures the reset of a single account
+type EnvironmentVariable struct {
+	Name  string
+	Value string
+}
+
+// AccountEnvironment returns the environment variables which configure
+// cmd/codebuild/reset to reset the account from a reset queue message.
+// These are set on the CodeBuild build, or on the reset process of the reset worker.
+func AccountEnvironment(acct *account.Account) []EnvironmentVariable {
+	// The reset reason is recorded with the reset run, by the reset build
+	resetReason := "Unknown"
+	if acct.ResetReason != nil {
+		resetReason = acct.ResetReason.String()
+	}
+
+	return []EnvironmentVariable{
+		{
+			Name:  "RESET_ACCOUNT",
+			Value: aws.StringValue(acct.ID),
+		},
+		{
+			Name:  "RESET_ACCOUNT_ADMIN_ROLE_NAME",
+			Value: aws.StringValue(acct.AdminRoleArn.IAMResourceName()),
+		},
+		{
+			Name:  "RESET_ACCOUNT_PRINCIPAL_ROLE_NAME",
+			Value: aws.StringValue(acct.PrincipalRoleArn.IAMResourceName()),
+		},
+		{
+			Name:  "RESET_REASON",
+			Value: resetReason,
+		},
+	}
+}
@@ -0,0 +1,93 @@
+package reset
+
+import (
+	"log"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Optum/dce/pkg/account"
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// Keep returns true if the nuke filters of the account match the resource,
+// so resetters leave it in place, like aws-nuke does.
+// Resources are matched by their aws-nuke resource type, their name,
+// and their aws-nuke properties.
+func (input *ResetterInput) Keep(resourceType string, name string, properties map[string]string) bool {
+	for _, filter := range input.Filters[resourceType] {
+		value := name
+		if filter.Property != nil {
+			value = properties[*filter.Property]
+		}
+
+		matched, err := matchNukeFilter(filter, value)
+		if err != nil {
+			// Keep the resource, rather than delete what the filter was meant to keep
+			log.Printf("Keeping %s %s, failed to match nuke filter: %s", resourceType, name, err)
+			return true
+		}
+		if aws.BoolValue(filter.Invert) {
+			matched = !matched
+		}
+		if matched {
+			log.Printf("Keeping %s %s, it matches the nuke filters of account %s", resourceType, name, input.AccountID)
+			return true
+		}
+	}
+	return false
+}
+
+// matchNukeFilter returns true if the value matches the filter,
+// following the aws-nuke filter types
+func matchNukeFilter(filter account.NukeFilter, value string) (bool, error) {
+	filterType := account.NukeFilterTypeExact
+	if filter.Type != nil {
+		filterType = *filter.Type
+	}
+	filterValue := aws.StringValue(filter.Value)
+
+	switch filterType {
+	case account.NukeFilterTypeContains:
+		return strings.Contains(value, filterValue), nil
+	case account.NukeFilterTypeGlob:
+		return path.Match(filterValue, value)
+	case account.NukeFilterTypeRegex:
+		return regexp.MatchString(filterValue, value)
+	case account.NukeFilterTypeDateOlderThan:
+		if value == "" {
+			return false, nil
+		}
+		duration, err := time.ParseDuration(filterValue)
+		if err != nil {
+			return false, err
+		}
+		date, err := parseNukeDate(value)
+		if err != nil {
+			return false, err
+		}
+		return date.Add(duration).After(time.Now()), nil
+	default:
+		return value == filterValue, nil
+	}
+}
+
+// parseNukeDate parses a resource property date,
+// as a unix timestamp or in one of the layouts aws-nuke supports
+func parseNukeDate(value string) (time.Time, error) {
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0), nil
+	}
+
+	var err error
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05 -0700 MST", "2006-01-02"} {
+		var date time.Time
+		date, err = time.Parse(layout, value)
+		if err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, err
+}
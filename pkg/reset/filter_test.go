@@ -0,0 +1,96 @@
+package reset
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Optum/dce/pkg/account"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResetterInputKeep(t *testing.T) {
+	recent := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+	old := time.Now().AddDate(0, 0, -30).Format(time.RFC3339)
+
+	tests := []struct {
+		name       string
+		filter     account.NukeFilter
+		properties map[string]string
+		exp        bool
+	}{
+		{
+			name:   "should match the name exactly",
+			filter: account.NukeFilter{Value: aws.String("keep-me")},
+			exp:    true,
+		},
+		{
+			name:   "should not match another name",
+			filter: account.NukeFilter{Value: aws.String("keep")},
+			exp:    false,
+		},
+		{
+			name:   "should match a name containing the value",
+			filter: account.NukeFilter{Type: account.NukeFilterTypeContains.NukeFilterTypePtr(), Value: aws.String("ep-")},
+			exp:    true,
+		},
+		{
+			name:   "should match a glob",
+			filter: account.NukeFilter{Type: account.NukeFilterTypeGlob.NukeFilterTypePtr(), Value: aws.String("keep-*")},
+			exp:    true,
+		},
+		{
+			name:   "should match a regex",
+			filter: account.NukeFilter{Type: account.NukeFilterTypeRegex.NukeFilterTypePtr(), Value: aws.String("^k.*e$")},
+			exp:    true,
+		},
+		{
+			name:       "should match a property",
+			filter:     account.NukeFilter{Property: aws.String("tag:team"), Value: aws.String("data")},
+			properties: map[string]string{"tag:team": "data"},
+			exp:        true,
+		},
+		{
+			name:   "should not match a missing property",
+			filter: account.NukeFilter{Property: aws.String("tag:team"), Value: aws.String("data")},
+			exp:    false,
+		},
+		{
+			name:   "should invert the match",
+			filter: account.NukeFilter{Value: aws.String("other"), Invert: aws.Bool(true)},
+			exp:    true,
+		},
+		{
+			name:       "should match dates newer than the duration",
+			filter:     account.NukeFilter{Type: account.NukeFilterTypeDateOlderThan.NukeFilterTypePtr(), Property: aws.String("CreationDate"), Value: aws.String("24h")},
+			properties: map[string]string{"CreationDate": recent},
+			exp:        true,
+		},
+		{
+			name:       "should not match dates older than the duration",
+			filter:     account.NukeFilter{Type: account.NukeFilterTypeDateOlderThan.NukeFilterTypePtr(), Property: aws.String("CreationDate"), Value: aws.String("24h")},
+			properties: map[string]string{"CreationDate": old},
+			exp:        false,
+		},
+		{
+			name:   "should keep the resource when the filter is invalid",
+			filter: account.NukeFilter{Type: account.NukeFilterTypeRegex.NukeFilterTypePtr(), Value: aws.String("(")},
+			exp:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := &ResetterInput{
+				AccountID: "123456789012",
+				Filters: account.NukeFilters{
+					"S3Bucket": {tt.filter},
+				},
+			}
+			assert.Equal(t, tt.exp, input.Keep("S3Bucket", "keep-me", tt.properties))
+			// Filters only apply to their own resource type
+			assert.False(t, input.Keep("GlueDatabase", "keep-me", tt.properties))
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package reset
+
+import (
+	"log"
+
+	"github.com/aws/aws-sdk-go/service/glue"
+	"github.com/aws/aws-sdk-go/service/glue/glueiface"
+	"github.com/pkg/errors"
+)
+
+// GlueResetter deletes the databases of the Glue data catalog in each region.
+// Deleting a database also deletes its tables and partitions.
+type GlueResetter struct {
+	// NewClient returns a Glue client for the region
+	NewClient func(region string) glueiface.GlueAPI
+}
+
+// Name of the resetter
+func (r *GlueResetter) Name() string {
+	return "Glue"
+}
+
+// Phase the resetter runs in
+func (r *GlueResetter) Phase() Phase {
+	return PhasePreNuke
+}
+
+// Reset deletes the Glue catalog databases in each region
+func (r *GlueResetter) Reset(input *ResetterInput) error {
+	for _, region := range input.Regions {
+		client := r.NewClient(region)
+
+		databases := []*glue.Database{}
+		getDatabasesInput := &glue.GetDatabasesInput{}
+		for {
+			getDatabasesOutput, err := client.GetDatabases(getDatabasesInput)
+			if err != nil {
+				return errors.Wrapf(err, "Failed to list Glue databases in %s", region)
+			}
+			databases = append(databases, getDatabasesOutput.DatabaseList...)
+			if getDatabasesOutput.NextToken == nil {
+				break
+			}
+			getDatabasesInput.NextToken = getDatabasesOutput.NextToken
+		}
+
+		for _, database := range databases {
+			if input.Keep("GlueDatabase", *database.Name, map[string]string{"Name": *database.Name}) {
+				continue
+			}
+			if input.DryRun {
+				log.Printf("Would delete Glue database %s in %s", *database.Name, region)
+				continue
+			}
+			log.Printf("Deleting Glue database %s in %s", *database.Name, region)
+			_, err := client.DeleteDatabase(&glue.DeleteDatabaseInput{
+				Name: database.Name,
+			})
+			if err != nil {
+				return errors.Wrapf(err, "Failed to delete Glue database %s in %s", *database.Name, region)
+			}
+		}
+	}
+	return nil
+}
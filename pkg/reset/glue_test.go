@@ -0,0 +1,88 @@
+package reset
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Optum/dce/pkg/account"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/glue"
+	"github.com/aws/aws-sdk-go/service/glue/glueiface"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockGlue returns databases over two pages, and records what was deleted
+type mockGlue struct {
+	glueiface.GlueAPI
+	deleteErr error
+	deleted   []string
+}
+
+func (m *mockGlue) GetDatabases(input *glue.GetDatabasesInput) (*glue.GetDatabasesOutput, error) {
+	if input.NextToken == nil {
+		return &glue.GetDatabasesOutput{
+			DatabaseList: []*glue.Database{{Name: aws.String("default")}},
+			NextToken:    aws.String("page2"),
+		}, nil
+	}
+	return &glue.GetDatabasesOutput{
+		DatabaseList: []*glue.Database{{Name: aws.String("logs")}},
+	}, nil
+}
+
+func (m *mockGlue) DeleteDatabase(input *glue.DeleteDatabaseInput) (*glue.DeleteDatabaseOutput, error) {
+	m.deleted = append(m.deleted, *input.Name)
+	return &glue.DeleteDatabaseOutput{}, m.deleteErr
+}
+
+func TestGlueResetter(t *testing.T) {
+	tests := []struct {
+		name       string
+		dryRun     bool
+		filters    account.NukeFilters
+		deleteErr  error
+		expDeleted []string
+		expErr     string
+	}{
+		{
+			name:       "should delete every database",
+			expDeleted: []string{"default", "logs"},
+		},
+		{
+			name: "should keep databases matching the nuke filters",
+			filters: account.NukeFilters{
+				"GlueDatabase": {{Value: aws.String("logs")}},
+			},
+			expDeleted: []string{"default"},
+		},
+		{
+			name:   "should not delete in dry run mode",
+			dryRun: true,
+		},
+		{
+			name:       "should fail when a database can't be deleted",
+			deleteErr:  errors.New("access denied"),
+			expDeleted: []string{"default"},
+			expErr:     "Failed to delete Glue database default in us-east-1: access denied",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &mockGlue{deleteErr: tt.deleteErr}
+			resetter := &GlueResetter{
+				NewClient: func(region string) glueiface.GlueAPI {
+					return client
+				},
+			}
+
+			err := resetter.Reset(&ResetterInput{Regions: []string{"us-east-1"}, DryRun: tt.dryRun, Filters: tt.filters})
+			if tt.expErr == "" {
+				assert.Nil(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expErr)
+			}
+			assert.Equal(t, tt.expDeleted, client.deleted)
+		})
+	}
+}
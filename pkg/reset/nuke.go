@@ -17,14 +17,17 @@ type NukeAccountInput struct {
 	RoleName       string
 	ConfigPath     string
 	NoDryRun       bool
+	Targets        []string // Limit the nuke to these resource types, if set
+	Regions        []string // Limit the nuke to these regions, if set
 	Token          common.TokenService
 	Nuke           Nuker
 }
 
 // NukeAccount directly triggers aws-nuke to be called on the
 // configuration file provided, bypassing any manual prompts.
-// Returns an error if there's any, else nil.
-func NukeAccount(input *NukeAccountInput) error {
+// Returns the outcome of each resource found by aws-nuke,
+// and an error if there's any, else nil.
+func NukeAccount(input *NukeAccountInput) (NukeResources, error) {
 
 	// Create a NukeParameter based on the configuration file
 	// path and force to bypass prompts.
@@ -34,6 +37,7 @@ func NukeAccount(input *NukeAccountInput) error {
 		ForceSleep:     5,
 		MaxWaitRetries: 200,
 		Force:          true,
+		Targets:        input.Targets,
 	}
 
 	// Get the Credentials of the Role to be assumed into for the Nuke
@@ -47,7 +51,7 @@ func NukeAccount(input *NukeAccountInput) error {
 		&assumeRoleInputs,
 	)
 	if err != nil {
-		return errors.Wrapf(err, "Failed to assume role for nuking account %s as %s",
+		return nil, errors.Wrapf(err, "Failed to assume role for nuking account %s as %s",
 			input.ChildAccountID, roleArn)
 	}
 
@@ -63,7 +67,7 @@ func NukeAccount(input *NukeAccountInput) error {
 	// on the new Account and NukeParameter
 	account, err := input.Nuke.NewAccount(creds)
 	if err != nil {
-		return errors.Wrapf(err, "Failed to configure account %s for aws-nuke as %s",
+		return nil, errors.Wrapf(err, "Failed to configure account %s for aws-nuke as %s",
 			input.ChildAccountID, roleArn)
 	}
 	nuke := cmd.NewNuke(params, *account)
@@ -74,18 +78,79 @@ func NukeAccount(input *NukeAccountInput) error {
 	// https://github.com/golang/go/wiki/Timeouts
 	nuke.Config, err = input.Nuke.Load(nuke.Parameters.ConfigPath)
 	if err != nil {
-		return errors.Wrapf(err, "Failed to load nuke config at %s", nuke.Parameters.ConfigPath)
+		return nil, errors.Wrapf(err, "Failed to load nuke config at %s", nuke.Parameters.ConfigPath)
 	}
-	c := make(chan error, 1)
-	go func() { c <- input.Nuke.Run(nuke) }()
+	if len(input.Regions) > 0 {
+		nuke.Config.Regions = input.Regions
+	}
+
+	type result struct {
+		resources NukeResources
+		err       error
+	}
+	c := make(chan result, 1)
+	go func() {
+		resources, err := input.Nuke.Run(nuke)
+		c <- result{resources, err}
+	}()
 	select {
-	case err := <-c:
-		if err != nil {
-			return errors.Wrapf(err, "Failed to run nuke for account %s as %s",
+	case res := <-c:
+		if res.err != nil {
+			return res.resources, errors.Wrapf(res.err, "Failed to run nuke for account %s as %s",
 				input.ChildAccountID, roleArn)
 		}
-		return nil
+		return res.resources, nil
 	case <-time.After(time.Minute * 60):
-		return errors.New("Nuke Timed Out after 60 minutes")
+		return nil, errors.New("Nuke Timed Out after 60 minutes")
+	}
+}
+
+// NukeAccountWithRetries nukes the account, then retries up to attempts-1 times
+// if any resources fail to be removed. Each retry is limited to the resource types
+// and regions of the resources which failed in the previous attempt.
+// Returns a report of every attempt, and an error listing the resources
+// which could not be removed, if there are any.
+func NukeAccountWithRetries(input *NukeAccountInput, attempts int) (*NukeReport, error) {
+	report := &NukeReport{
+		AccountID: input.ChildAccountID,
+		DryRun:    !input.NoDryRun,
+		Attempts:  []NukeAttempt{},
+	}
+
+	attemptInput := *input
+	var err error
+	for i := 0; i < attempts; i++ {
+		var resources NukeResources
+		resources, err = NukeAccount(&attemptInput)
+
+		attempt := NukeAttempt{
+			Targets:   attemptInput.Targets,
+			Regions:   attemptInput.Regions,
+			Resources: resources,
+		}
+		if attempt.Resources == nil {
+			attempt.Resources = NukeResources{}
+		}
+		if err != nil {
+			attempt.Error = err.Error()
+		}
+		report.Attempts = append(report.Attempts, attempt)
+
+		failed := attempt.Resources.Failed()
+		if err == nil && len(failed) == 0 {
+			return report, nil
+		}
+		// Without any failed resources (eg. the role couldn't be assumed),
+		// the next attempt has the same targets as this one
+		if len(failed) > 0 {
+			attemptInput.Targets, attemptInput.Regions = failed.TypesAndRegions()
+		}
+	}
+
+	failed := report.Failed()
+	if len(failed) == 0 {
+		return report, err
 	}
+	return report, errors.Errorf("Failed to remove %d resources after %d attempts: %s",
+		len(failed), attempts, failed)
 }
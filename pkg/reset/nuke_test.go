@@ -76,13 +76,31 @@ func (nuke mockNukeService) Load(configPath string) (*config.Nuke, error) {
 }
 
 // Run mocks an execution of a Nuke process
-func (nuke mockNukeService) Run(cmd *cmd.Nuke) error {
+func (nuke mockNukeService) Run(cmd *cmd.Nuke) (NukeResources, error) {
+	removed := NukeResources{
+		{Region: "us-east-1", Type: "S3Bucket", ID: "s3://bucket", State: NukeResourceRemoved},
+	}
+	failed := NukeResources{
+		{Region: "us-west-2", Type: "EC2Instance", ID: "i-01b489457a60298dd", State: NukeResourceFailed},
+	}
+
+	switch cmd.Account.Credentials.SessionToken {
 	// Failure case
-	if cmd.Account.Credentials.SessionToken == "DCENukeTestRunError" {
-		return errors.New("Error: Failed to Run")
+	case "DCENukeTestRunError":
+		return nil, errors.New("Error: Failed to Run")
+	// Fails to remove a resource on the first attempt only
+	case "DCENukeTestRetry":
+		if len(cmd.Parameters.Targets) == 0 {
+			return append(removed, failed...), errors.New("failed")
+		}
+		failed[0].State = NukeResourceRemoved
+		return failed, nil
+	// Always fails to remove a resource
+	case "DCENukeTestRetryError":
+		return failed, errors.New("failed")
 	}
 
-	return nil
+	return removed, nil
 }
 
 // testNukeAccountInput is the testing infrastructure used to test NukeAccount
@@ -160,7 +178,7 @@ func TestNukeAccount(t *testing.T) {
 	// Iterate through each test in the list
 	for _, test := range tests {
 		// Call the NukeAccount function and get the respective error
-		err := NukeAccount(test.Input)
+		_, err := NukeAccount(test.Input)
 
 		// Assert that error is expected correctly
 		if test.ExpectedError == "" {
@@ -171,3 +189,50 @@ func TestNukeAccount(t *testing.T) {
 		}
 	}
 }
+
+// TestNukeAccountWithRetries verifies that only the failed resource types
+// and regions are retried, and that every attempt is reported
+func TestNukeAccountWithRetries(t *testing.T) {
+	newInput := func(id string) *NukeAccountInput {
+		return &NukeAccountInput{
+			ChildAccountID: id,
+			RoleName:       id,
+			ConfigPath:     id,
+			NoDryRun:       true,
+			Token:          mockTokenService{},
+			Nuke:           mockNukeService{},
+		}
+	}
+
+	t.Run("succeeds on the first attempt", func(t *testing.T) {
+		report, err := NukeAccountWithRetries(newInput("TestSuccess"), 3)
+		require.Nil(t, err)
+		require.Len(t, report.Attempts, 1)
+		require.Equal(t, 1, report.Attempts[0].Resources.Count(NukeResourceRemoved))
+		require.False(t, report.DryRun)
+	})
+
+	t.Run("retries only what failed", func(t *testing.T) {
+		report, err := NukeAccountWithRetries(newInput("TestRetry"), 3)
+		require.Nil(t, err)
+		require.Len(t, report.Attempts, 2)
+		require.NotEmpty(t, report.Attempts[0].Error)
+		require.Equal(t, []string{"EC2Instance"}, report.Attempts[1].Targets)
+		require.Equal(t, []string{"us-west-2"}, report.Attempts[1].Regions)
+		require.Empty(t, report.Failed())
+	})
+
+	t.Run("reports the resources which could not be removed", func(t *testing.T) {
+		report, err := NukeAccountWithRetries(newInput("TestRetryError"), 3)
+		require.NotNil(t, err)
+		require.Len(t, report.Attempts, 3)
+		require.Regexp(t, "Failed to remove 1 resources after 3 attempts: EC2Instance i-01b489457a60298dd \\(us-west-2\\)", err.Error())
+	})
+
+	t.Run("retries errors without failed resources", func(t *testing.T) {
+		report, err := NukeAccountWithRetries(newInput("TestRunError"), 3)
+		require.NotNil(t, err)
+		require.Len(t, report.Attempts, 3)
+		require.Regexp(t, "Error: Failed to Run", err.Error())
+	})
+}
@@ -1,17 +1,25 @@
 package reset
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/rebuy-de/aws-nuke/cmd"
 	"github.com/rebuy-de/aws-nuke/pkg/awsutil"
 	"github.com/rebuy-de/aws-nuke/pkg/config"
+	"github.com/rebuy-de/aws-nuke/pkg/types"
+	"github.com/rebuy-de/aws-nuke/resources"
 )
 
+// nukeRetryInterval is how long to wait between passes over the aws-nuke queue
+const nukeRetryInterval = 5 * time.Second
+
 // Nuker interface requires methods that are necessary to set up and
 // execute a Nuke in an AWS Account.
 type Nuker interface {
 	NewAccount(awsutil.Credentials) (*awsutil.Account, error)
 	Load(string) (*config.Nuke, error)
-	Run(*cmd.Nuke) error
+	Run(*cmd.Nuke) (NukeResources, error)
 }
 
 // Nuke implements the NukeService interface using rebuy-de/aws-nuke
@@ -34,7 +42,119 @@ func (nuke Nuke) Load(configPath string) (*config.Nuke, error) {
 	return config.Load(configPath)
 }
 
-// Run executes and returns the result of the aws-nuke nuke.
-func (nuke Nuke) Run(cmd *cmd.Nuke) error {
-	return cmd.Run()
+// Run executes the aws-nuke nuke, and returns the outcome of each resource.
+// cmd.Nuke.Run keeps its item queue to itself, so the nuke is run here with
+// the same steps, and the outcome of each resource is read from the queue.
+func (nuke Nuke) Run(n *cmd.Nuke) (NukeResources, error) {
+	err := n.Config.ValidateAccount(n.Account.ID(), n.Account.Aliases())
+	if err != nil {
+		return nil, err
+	}
+
+	queue, err := scanNukeQueue(n)
+	if err != nil {
+		return newNukeResources(queue, !n.Parameters.NoDryRun), err
+	}
+	if !n.Parameters.NoDryRun {
+		return newNukeResources(queue, true), nil
+	}
+
+	err = removeNukeQueue(n, queue)
+	return newNukeResources(queue, false), err
+}
+
+// scanNukeQueue lists the resources of each region in the nuke config,
+// and filters them, as cmd.Nuke.Scan does
+func scanNukeQueue(n *cmd.Nuke) (cmd.Queue, error) {
+	accountConfig := n.Config.Accounts[n.Account.ID()]
+
+	resourceTypes := cmd.ResolveResourceTypes(
+		resources.GetListerNames(),
+		[]types.Collection{
+			n.Parameters.Targets,
+			n.Config.ResourceTypes.Targets,
+			accountConfig.ResourceTypes.Targets,
+		},
+		[]types.Collection{
+			n.Parameters.Excludes,
+			n.Config.ResourceTypes.Excludes,
+			accountConfig.ResourceTypes.Excludes,
+		},
+	)
+
+	queue := cmd.Queue{}
+	for _, regionName := range n.Config.Regions {
+		region := cmd.NewRegion(regionName, n.Account.ResourceTypeToServiceType, n.Account.NewSession)
+		for item := range cmd.Scan(region, resourceTypes) {
+			queue = append(queue, item)
+			err := n.Filter(item)
+			if err != nil {
+				return queue, err
+			}
+			item.Print()
+		}
+	}
+
+	fmt.Printf("Scan complete: %d total, %d nukeable, %d filtered.\n\n",
+		queue.CountTotal(), queue.Count(cmd.ItemStateNew), queue.Count(cmd.ItemStateFiltered))
+	return queue, nil
+}
+
+// removeNukeQueue removes each resource in the queue, and waits for the
+// removals to finish, as cmd.Nuke.Run does.
+// Fails if resources are still failing after they're all done,
+// or after MaxWaitRetries passes waiting on resources to be removed.
+func removeNukeQueue(n *cmd.Nuke, queue cmd.Queue) error {
+	failCount := 0
+	waitingCount := 0
+	for {
+		listCache := map[string]map[string][]resources.Resource{}
+		for _, item := range queue {
+			switch item.State {
+			case cmd.ItemStateNew:
+				n.HandleRemove(item)
+			case cmd.ItemStateFailed:
+				n.HandleRemove(item)
+				n.HandleWait(item, listCache)
+			case cmd.ItemStatePending:
+				n.HandleWait(item, listCache)
+				item.State = cmd.ItemStateWaiting
+			case cmd.ItemStateWaiting:
+				n.HandleWait(item, listCache)
+			default:
+				continue
+			}
+			item.Print()
+		}
+
+		if queue.Count(cmd.ItemStatePending, cmd.ItemStateWaiting, cmd.ItemStateNew) == 0 &&
+			queue.Count(cmd.ItemStateFailed) > 0 {
+			if failCount >= 2 {
+				return fmt.Errorf("%d resources are in failed state, but none are ready for deletion, anymore",
+					queue.Count(cmd.ItemStateFailed))
+			}
+			failCount++
+		} else {
+			failCount = 0
+		}
+
+		if n.Parameters.MaxWaitRetries != 0 &&
+			queue.Count(cmd.ItemStateWaiting, cmd.ItemStatePending) > 0 &&
+			queue.Count(cmd.ItemStateNew) == 0 {
+			if waitingCount >= n.Parameters.MaxWaitRetries {
+				return fmt.Errorf("max wait retries of %d exceeded", n.Parameters.MaxWaitRetries)
+			}
+			waitingCount++
+		} else {
+			waitingCount = 0
+		}
+
+		if queue.Count(cmd.ItemStateNew, cmd.ItemStatePending, cmd.ItemStateFailed, cmd.ItemStateWaiting) == 0 {
+			fmt.Printf("Nuke complete: %d failed, %d skipped, %d finished.\n\n",
+				queue.Count(cmd.ItemStateFailed), queue.Count(cmd.ItemStateFiltered), queue.Count(cmd.ItemStateFinished))
+			return nil
+		}
+
+		time.Sleep(nukeRetryInterval)
+	}
 }
@@ -0,0 +1,165 @@
+package reset
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rebuy-de/aws-nuke/cmd"
+	"github.com/rebuy-de/aws-nuke/resources"
+)
+
+// NukeResourceState is the outcome of aws-nuke for a single resource
+type NukeResourceState string
+
+const (
+	// NukeResourceRemoved means aws-nuke deleted the resource
+	NukeResourceRemoved NukeResourceState = "Removed"
+	// NukeResourceFiltered means the resource was kept, see the resource's reason
+	NukeResourceFiltered NukeResourceState = "Filtered"
+	// NukeResourceFailed means aws-nuke failed to delete the resource
+	NukeResourceFailed NukeResourceState = "Failed"
+	// NukeResourceWouldRemove means the resource would be deleted, if aws-nuke was not a dry run
+	NukeResourceWouldRemove NukeResourceState = "WouldRemove"
+	// NukeResourcePending means aws-nuke didn't see the removal of the resource complete
+	NukeResourcePending NukeResourceState = "Pending"
+)
+
+// NukeResource is the outcome of aws-nuke for a single resource
+type NukeResource struct {
+	Region     string            `json:"region"`
+	Type       string            `json:"type"`
+	ID         string            `json:"id,omitempty"`
+	Properties string            `json:"properties,omitempty"`
+	State      NukeResourceState `json:"state"`
+	Reason     string            `json:"reason,omitempty"`
+}
+
+// String returns the type, ID and region of the resource
+func (r NukeResource) String() string {
+	if r.ID == "" {
+		return fmt.Sprintf("%s %s (%s)", r.Type, r.Properties, r.Region)
+	}
+	return fmt.Sprintf("%s %s (%s)", r.Type, r.ID, r.Region)
+}
+
+// NukeResources is a list of type NukeResource
+type NukeResources []NukeResource
+
+// String returns the resources as a comma separated list
+func (r NukeResources) String() string {
+	names := make([]string, len(r))
+	for i, res := range r {
+		names[i] = res.String()
+	}
+	return strings.Join(names, ", ")
+}
+
+// Failed returns the resources aws-nuke failed to delete
+func (r NukeResources) Failed() NukeResources {
+	failed := NukeResources{}
+	for _, res := range r {
+		if res.State == NukeResourceFailed || res.State == NukeResourcePending {
+			failed = append(failed, res)
+		}
+	}
+	return failed
+}
+
+// Count returns the number of resources in the state
+func (r NukeResources) Count(state NukeResourceState) int {
+	count := 0
+	for _, res := range r {
+		if res.State == state {
+			count++
+		}
+	}
+	return count
+}
+
+// TypesAndRegions returns the distinct resource types and regions of the resources, sorted
+func (r NukeResources) TypesAndRegions() ([]string, []string) {
+	types := map[string]bool{}
+	regions := map[string]bool{}
+	for _, res := range r {
+		types[res.Type] = true
+		regions[res.Region] = true
+	}
+	return sortedKeys(types), sortedKeys(regions)
+}
+
+// NukeAttempt is a single run of aws-nuke against an account
+type NukeAttempt struct {
+	// Resource types and regions the attempt was limited to.
+	// Empty for the first attempt, which nukes everything
+	Targets   []string      `json:"targets,omitempty"`
+	Regions   []string      `json:"regions,omitempty"`
+	Resources NukeResources `json:"resources"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// NukeReport lists the outcome of every resource aws-nuke found in an account,
+// for each attempt to nuke the account
+type NukeReport struct {
+	AccountID string        `json:"accountId"`
+	DryRun    bool          `json:"dryRun"`
+	Attempts  []NukeAttempt `json:"attempts"`
+}
+
+// Failed returns the resources which could not be deleted by the last attempt
+func (r *NukeReport) Failed() NukeResources {
+	if len(r.Attempts) == 0 {
+		return NukeResources{}
+	}
+	return r.Attempts[len(r.Attempts)-1].Resources.Failed()
+}
+
+// newNukeResources returns the outcome of each resource in the aws-nuke queue.
+// Resources still New were never removed, which is expected of a dry run.
+func newNukeResources(queue cmd.Queue, dryRun bool) NukeResources {
+	nukeResources := make(NukeResources, 0, len(queue))
+	for _, item := range queue {
+		res := NukeResource{
+			Type:   item.Type,
+			Reason: item.Reason,
+		}
+		if item.Region != nil {
+			res.Region = item.Region.Name
+		}
+		if r, ok := item.Resource.(resources.LegacyStringer); ok {
+			res.ID = r.String()
+		}
+		if r, ok := item.Resource.(resources.ResourcePropertyGetter); ok {
+			res.Properties = r.Properties().String()
+		}
+
+		switch item.State {
+		case cmd.ItemStateFinished:
+			res.State = NukeResourceRemoved
+			res.Reason = ""
+		case cmd.ItemStateFiltered:
+			res.State = NukeResourceFiltered
+		case cmd.ItemStateFailed:
+			res.State = NukeResourceFailed
+		case cmd.ItemStateNew:
+			res.State = NukeResourcePending
+			if dryRun {
+				res.State = NukeResourceWouldRemove
+			}
+		default:
+			res.State = NukeResourcePending
+		}
+
+		nukeResources = append(nukeResources, res)
+	}
+	return nukeResources
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
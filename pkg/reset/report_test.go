@@ -0,0 +1,100 @@
+package reset
+
+import (
+	"testing"
+
+	"github.com/rebuy-de/aws-nuke/cmd"
+	"github.com/rebuy-de/aws-nuke/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+// testNukeResource is an aws-nuke resource, with an ID and properties
+type testNukeResource struct {
+	id         string
+	properties types.Properties
+}
+
+func (r testNukeResource) Remove() error {
+	return nil
+}
+
+func (r testNukeResource) String() string {
+	return r.id
+}
+
+func (r testNukeResource) Properties() types.Properties {
+	return r.properties
+}
+
+// testNukeIDResource is an aws-nuke resource, with only an ID
+type testNukeIDResource struct {
+	id string
+}
+
+func (r testNukeIDResource) Remove() error {
+	return nil
+}
+
+func (r testNukeIDResource) String() string {
+	return r.id
+}
+
+func TestNewNukeResources(t *testing.T) {
+	usEast1 := &cmd.Region{Name: "us-east-1"}
+	queue := cmd.Queue{
+		{
+			Region:   usEast1,
+			Type:     "EC2Instance",
+			Resource: testNukeResource{id: "i-01b489457a60298dd", properties: types.NewProperties().Set("Name", "web")},
+			State:    cmd.ItemStateFinished,
+		},
+		{
+			Region:   &cmd.Region{Name: "global"},
+			Type:     "IAMRole",
+			Resource: testNukeIDResource{id: "OrganizationAccountAccessRole"},
+			State:    cmd.ItemStateFiltered,
+			Reason:   "filtered by config",
+		},
+		{
+			Region:   usEast1,
+			Type:     "CloudTrailTrail",
+			Resource: testNukeIDResource{id: "trail"},
+			State:    cmd.ItemStateFailed,
+			Reason:   "AccessDenied",
+		},
+		{
+			Region:   usEast1,
+			Type:     "S3Bucket",
+			Resource: testNukeIDResource{id: "s3://my-bucket"},
+			State:    cmd.ItemStateWaiting,
+		},
+		{
+			Region:   usEast1,
+			Type:     "SNSTopic",
+			Resource: testNukeIDResource{id: "topic"},
+			State:    cmd.ItemStateNew,
+		},
+	}
+
+	resources := newNukeResources(queue, false)
+
+	require.Equal(t, NukeResources{
+		{Region: "us-east-1", Type: "EC2Instance", ID: "i-01b489457a60298dd", Properties: `[Name: "web"]`, State: NukeResourceRemoved},
+		{Region: "global", Type: "IAMRole", ID: "OrganizationAccountAccessRole", State: NukeResourceFiltered, Reason: "filtered by config"},
+		{Region: "us-east-1", Type: "CloudTrailTrail", ID: "trail", State: NukeResourceFailed, Reason: "AccessDenied"},
+		{Region: "us-east-1", Type: "S3Bucket", ID: "s3://my-bucket", State: NukeResourcePending},
+		{Region: "us-east-1", Type: "SNSTopic", ID: "topic", State: NukeResourcePending},
+	}, resources)
+
+	failed := resources.Failed()
+	require.Len(t, failed, 3)
+	resourceTypes, regions := failed.TypesAndRegions()
+	require.Equal(t, []string{"CloudTrailTrail", "S3Bucket", "SNSTopic"}, resourceTypes)
+	require.Equal(t, []string{"us-east-1"}, regions)
+
+	// Resources aren't removed by a dry run
+	dryRun := newNukeResources(cmd.Queue{queue[4]}, true)
+	require.Equal(t, NukeResources{
+		{Region: "us-east-1", Type: "SNSTopic", ID: "topic", State: NukeResourceWouldRemove},
+	}, dryRun)
+}
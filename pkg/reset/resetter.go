@@ -0,0 +1,192 @@
+package reset
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/Optum/dce/pkg/account"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/service/athena"
+	"github.com/aws/aws-sdk-go/service/athena/athenaiface"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/aws/aws-sdk-go/service/glue"
+	"github.com/aws/aws-sdk-go/service/glue/glueiface"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/servicecatalog"
+	"github.com/aws/aws-sdk-go/service/servicecatalog/servicecatalogiface"
+	"github.com/pkg/errors"
+)
+
+// Phase is when a Resetter runs, relative to aws-nuke
+type Phase string
+
+const (
+	// PhasePreNuke resetters remove resources which aws-nuke doesn't support,
+	// or which would stop aws-nuke from removing other resources
+	PhasePreNuke Phase = "PreNuke"
+	// PhasePostNuke resetters restore resources which aws-nuke removed,
+	// but which accounts are expected to have
+	PhasePostNuke Phase = "PostNuke"
+)
+
+// Resetter cleans up a single service in an account,
+// where aws-nuke alone doesn't leave the account in a clean state
+type Resetter interface {
+	Name() string
+	Phase() Phase
+	Reset(input *ResetterInput) error
+}
+
+// ResetterInput is the account a Resetter cleans up
+type ResetterInput struct {
+	AccountID string
+	Regions   []string
+	// DryRun logs what would be changed, without changing anything
+	DryRun bool
+	// Filters match the resources of the account to keep, as for aws-nuke
+	Filters account.NukeFilters
+}
+
+// Registry holds the Resetters to run against an account, in the order they were registered
+type Registry struct {
+	resetters []Resetter
+}
+
+// NewRegistry returns a Registry with the resetters registered
+func NewRegistry(resetters ...Resetter) *Registry {
+	registry := &Registry{}
+	registry.Register(resetters...)
+	return registry
+}
+
+// Register adds resetters to the registry
+func (r *Registry) Register(resetters ...Resetter) {
+	r.resetters = append(r.resetters, resetters...)
+}
+
+// Resetters returns the registered resetters of the phase
+func (r *Registry) Resetters(phase Phase) []Resetter {
+	resetters := []Resetter{}
+	for _, resetter := range r.resetters {
+		if resetter.Phase() == phase {
+			resetters = append(resetters, resetter)
+		}
+	}
+	return resetters
+}
+
+// Run runs every resetter of the phase against the account.
+// A failing resetter doesn't stop the others from running,
+// the errors of all failed resetters are returned together as a *RunError.
+func (r *Registry) Run(phase Phase, input *ResetterInput) error {
+	runErr := &RunError{
+		Phase:     phase,
+		AccountID: input.AccountID,
+	}
+	for _, resetter := range r.Resetters(phase) {
+		log.Printf("Starting %s reset of account %s", resetter.Name(), input.AccountID)
+		err := resetter.Reset(input)
+		if err != nil {
+			log.Printf("Failed %s reset of account %s: %s", resetter.Name(), input.AccountID, err)
+			runErr.Failures = append(runErr.Failures, ResetterFailure{
+				Resetter: resetter.Name(),
+				Err:      err,
+			})
+		}
+	}
+	if len(runErr.Failures) > 0 {
+		return runErr
+	}
+	return nil
+}
+
+// ResetterFailure is the error of a single resetter
+type ResetterFailure struct {
+	Resetter string
+	Err      error
+}
+
+// RunError is returned when resetters of a phase fail
+type RunError struct {
+	Phase     Phase
+	AccountID string
+	Failures  []ResetterFailure
+}
+
+func (e *RunError) Error() string {
+	failures := []string{}
+	for _, failure := range e.Failures {
+		failures = append(failures, failure.Resetter+": "+failure.Err.Error())
+	}
+	return fmt.Sprintf("Failed %s resets of account %s: %s",
+		e.Phase, e.AccountID, strings.Join(failures, "; "))
+}
+
+// Lockouts returns the resources the failed resetters were locked out of
+func (e *RunError) Lockouts() []*LockoutError {
+	lockouts := []*LockoutError{}
+	for _, failure := range e.Failures {
+		if lockout, ok := errors.Cause(failure.Err).(*LockoutError); ok {
+			lockouts = append(lockouts, lockout)
+		}
+	}
+	return lockouts
+}
+
+// LockoutError is returned by a resetter when resources of the account deny
+// the admin role access, so neither the resetter nor aws-nuke can remove them.
+// They need to be removed by someone who still has access, such as the root
+// user of the account.
+type LockoutError struct {
+	ResourceType string
+	Names        []string
+}
+
+func (e *LockoutError) Error() string {
+	return fmt.Sprintf("%s resources deny the admin role access: %s",
+		e.ResourceType, strings.Join(e.Names, ", "))
+}
+
+// NewDefaultRegistry returns a Registry of all the resetters DCE ships with,
+// using clients created with the credentials of the account being reset
+func NewDefaultRegistry(p client.ConfigProvider, creds *credentials.Credentials) *Registry {
+	config := func(region string) *aws.Config {
+		return &aws.Config{
+			Credentials: creds,
+			Region:      aws.String(region),
+		}
+	}
+
+	return NewRegistry(
+		&AthenaResetter{
+			NewClient: func(region string) athenaiface.AthenaAPI {
+				return athena.New(p, config(region))
+			},
+		},
+		&GlueResetter{
+			NewClient: func(region string) glueiface.GlueAPI {
+				return glue.New(p, config(region))
+			},
+		},
+		&S3BucketPolicyResetter{
+			NewClient: func(region string) s3iface.S3API {
+				return s3.New(p, config(region))
+			},
+		},
+		&ServiceCatalogResetter{
+			NewClient: func(region string) servicecatalogiface.ServiceCatalogAPI {
+				return servicecatalog.New(p, config(region))
+			},
+		},
+		&DefaultVPCResetter{
+			NewClient: func(region string) ec2iface.EC2API {
+				return ec2.New(p, config(region))
+			},
+		},
+	)
+}
@@ -0,0 +1,84 @@
+package reset
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// mockResetter records the accounts it was run against
+type mockResetter struct {
+	name  string
+	phase Phase
+	err   error
+	runs  []ResetterInput
+}
+
+func (r *mockResetter) Name() string {
+	return r.name
+}
+
+func (r *mockResetter) Phase() Phase {
+	return r.phase
+}
+
+func (r *mockResetter) Reset(input *ResetterInput) error {
+	r.runs = append(r.runs, *input)
+	return r.err
+}
+
+func TestRegistry(t *testing.T) {
+	input := &ResetterInput{
+		AccountID: "123456789012",
+		Regions:   []string{"us-east-1"},
+		DryRun:    true,
+	}
+
+	t.Run("should run the resetters of the phase", func(t *testing.T) {
+		pre := &mockResetter{name: "Pre", phase: PhasePreNuke}
+		post := &mockResetter{name: "Post", phase: PhasePostNuke}
+		registry := NewRegistry(pre, post)
+
+		err := registry.Run(PhasePreNuke, input)
+		assert.Nil(t, err)
+		assert.Equal(t, []ResetterInput{*input}, pre.runs)
+		assert.Empty(t, post.runs)
+	})
+
+	t.Run("should run every resetter, and return all errors", func(t *testing.T) {
+		first := &mockResetter{name: "First", phase: PhasePreNuke, err: errors.New("first failure")}
+		second := &mockResetter{name: "Second", phase: PhasePreNuke}
+		third := &mockResetter{name: "Third", phase: PhasePreNuke, err: errors.New("third failure")}
+		registry := NewRegistry(first)
+		registry.Register(second, third)
+
+		err := registry.Run(PhasePreNuke, input)
+		assert.EqualError(t, err, "Failed PreNuke resets of account 123456789012: First: first failure; Third: third failure")
+		assert.Len(t, second.runs, 1)
+		assert.Len(t, third.runs, 1)
+		assert.Empty(t, err.(*RunError).Lockouts())
+	})
+
+	t.Run("should return the resources resetters were locked out of", func(t *testing.T) {
+		lockout := &LockoutError{ResourceType: "S3Bucket", Names: []string{"denied"}}
+		locked := &mockResetter{name: "Locked", phase: PhasePreNuke, err: lockout}
+		failed := &mockResetter{name: "Failed", phase: PhasePreNuke, err: errors.New("failure")}
+		registry := NewRegistry(locked, failed)
+
+		err := registry.Run(PhasePreNuke, input)
+		assert.EqualError(t, err, "Failed PreNuke resets of account 123456789012: Locked: S3Bucket resources deny the admin role access: denied; Failed: failure")
+		assert.Equal(t, []*LockoutError{lockout}, err.(*RunError).Lockouts())
+	})
+
+	t.Run("should register the default resetters", func(t *testing.T) {
+		registry := NewDefaultRegistry(nil, nil)
+
+		names := []string{}
+		for _, resetter := range registry.Resetters(PhasePreNuke) {
+			names = append(names, resetter.Name())
+		}
+		assert.Equal(t, []string{"Athena", "Glue", "S3BucketPolicy", "ServiceCatalog"}, names)
+		assert.Len(t, registry.Resetters(PhasePostNuke), 1)
+	})
+}
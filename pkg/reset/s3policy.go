@@ -0,0 +1,118 @@
+package reset
+
+import (
+	"log"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/pkg/errors"
+)
+
+// S3BucketPolicyResetter deletes the bucket policies of the buckets in each region.
+// A bucket policy may deny the admin role access to the bucket,
+// which stops aws-nuke from emptying and deleting it.
+// A policy which also denies the admin role access to the policy itself
+// can only be removed by the root user of the account. These buckets are
+// skipped, and returned as a *LockoutError once the other buckets are reset.
+type S3BucketPolicyResetter struct {
+	// NewClient returns an S3 client for the region
+	NewClient func(region string) s3iface.S3API
+}
+
+// Name of the resetter
+func (r *S3BucketPolicyResetter) Name() string {
+	return "S3BucketPolicy"
+}
+
+// Phase the resetter runs in
+func (r *S3BucketPolicyResetter) Phase() Phase {
+	return PhasePreNuke
+}
+
+// Reset deletes the policy of each bucket in the regions
+func (r *S3BucketPolicyResetter) Reset(input *ResetterInput) error {
+	locked := []string{}
+	// Buckets are listed globally, then handled by a client in their own region
+	globalClient := r.NewClient("us-east-1")
+	listBucketsOutput, err := globalClient.ListBuckets(&s3.ListBucketsInput{})
+	if err != nil {
+		return errors.Wrap(err, "Failed to list S3 buckets")
+	}
+
+	clients := map[string]s3iface.S3API{}
+	for _, region := range input.Regions {
+		clients[region] = nil
+	}
+
+	for _, bucket := range listBucketsOutput.Buckets {
+		locationOutput, err := globalClient.GetBucketLocation(&s3.GetBucketLocationInput{
+			Bucket: bucket.Name,
+		})
+		if isAccessDenied(err) {
+			locked = append(locked, *bucket.Name)
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, "Failed to get the region of S3 bucket %s", *bucket.Name)
+		}
+		region := s3.NormalizeBucketLocation(aws.StringValue(locationOutput.LocationConstraint))
+
+		client, ok := clients[region]
+		if !ok {
+			continue
+		}
+		if input.Keep("S3Bucket", *bucket.Name, map[string]string{"Name": *bucket.Name}) {
+			continue
+		}
+		if client == nil {
+			client = r.NewClient(region)
+			clients[region] = client
+		}
+
+		_, err = client.GetBucketPolicy(&s3.GetBucketPolicyInput{
+			Bucket: bucket.Name,
+		})
+		if err != nil {
+			if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "NoSuchBucketPolicy" {
+				continue
+			}
+			if isAccessDenied(err) {
+				locked = append(locked, *bucket.Name)
+				continue
+			}
+			return errors.Wrapf(err, "Failed to get the policy of S3 bucket %s", *bucket.Name)
+		}
+
+		if input.DryRun {
+			log.Printf("Would delete the policy of S3 bucket %s in %s", *bucket.Name, region)
+			continue
+		}
+		log.Printf("Deleting the policy of S3 bucket %s in %s", *bucket.Name, region)
+		_, err = client.DeleteBucketPolicy(&s3.DeleteBucketPolicyInput{
+			Bucket: bucket.Name,
+		})
+		if isAccessDenied(err) {
+			locked = append(locked, *bucket.Name)
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, "Failed to delete the policy of S3 bucket %s", *bucket.Name)
+		}
+	}
+
+	if len(locked) > 0 {
+		return &LockoutError{
+			ResourceType: "S3Bucket",
+			Names:        locked,
+		}
+	}
+	return nil
+}
+
+// isAccessDenied returns true if the error is S3 denying access
+func isAccessDenied(err error) bool {
+	aerr, ok := err.(awserr.Error)
+	return ok && aerr.Code() == "AccessDenied"
+}
@@ -0,0 +1,117 @@
+package reset
+
+import (
+	"testing"
+
+	"github.com/Optum/dce/pkg/account"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockS3 has a bucket with a policy in each of us-east-1 and eu-west-1,
+// and a bucket without a policy in us-east-1.
+// With lockout set, it also has a bucket whose policy denies all access.
+type mockS3 struct {
+	s3iface.S3API
+	lockout bool
+	deleted []string
+}
+
+func (m *mockS3) ListBuckets(input *s3.ListBucketsInput) (*s3.ListBucketsOutput, error) {
+	buckets := []*s3.Bucket{
+		{Name: aws.String("locked")},
+		{Name: aws.String("open")},
+		{Name: aws.String("locked-eu")},
+	}
+	if m.lockout {
+		buckets = append(buckets, &s3.Bucket{Name: aws.String("denied")})
+	}
+	return &s3.ListBucketsOutput{Buckets: buckets}, nil
+}
+
+func (m *mockS3) GetBucketLocation(input *s3.GetBucketLocationInput) (*s3.GetBucketLocationOutput, error) {
+	if *input.Bucket == "locked-eu" {
+		return &s3.GetBucketLocationOutput{LocationConstraint: aws.String("EU")}, nil
+	}
+	return &s3.GetBucketLocationOutput{}, nil
+}
+
+func (m *mockS3) GetBucketPolicy(input *s3.GetBucketPolicyInput) (*s3.GetBucketPolicyOutput, error) {
+	if *input.Bucket == "open" {
+		return nil, awserr.New("NoSuchBucketPolicy", "The bucket policy does not exist", nil)
+	}
+	if *input.Bucket == "denied" {
+		return nil, awserr.New("AccessDenied", "Access Denied", nil)
+	}
+	return &s3.GetBucketPolicyOutput{Policy: aws.String("{}")}, nil
+}
+
+func (m *mockS3) DeleteBucketPolicy(input *s3.DeleteBucketPolicyInput) (*s3.DeleteBucketPolicyOutput, error) {
+	m.deleted = append(m.deleted, *input.Bucket)
+	return &s3.DeleteBucketPolicyOutput{}, nil
+}
+
+func TestS3BucketPolicyResetter(t *testing.T) {
+	tests := []struct {
+		name       string
+		regions    []string
+		dryRun     bool
+		filters    account.NukeFilters
+		lockout    bool
+		expDeleted []string
+		expErr     error
+	}{
+		{
+			name:       "should delete the bucket policies in the regions",
+			regions:    []string{"us-east-1"},
+			expDeleted: []string{"locked"},
+		},
+		{
+			name:       "should delete the bucket policies in every region",
+			regions:    []string{"us-east-1", "eu-west-1"},
+			expDeleted: []string{"locked", "locked-eu"},
+		},
+		{
+			name:    "should keep the policies of buckets matching the nuke filters",
+			regions: []string{"us-east-1", "eu-west-1"},
+			filters: account.NukeFilters{
+				"S3Bucket": {{Type: account.NukeFilterTypeGlob.NukeFilterTypePtr(), Value: aws.String("*-eu")}},
+			},
+			expDeleted: []string{"locked"},
+		},
+		{
+			name:       "should reset the other buckets when a bucket policy denies access",
+			regions:    []string{"us-east-1"},
+			lockout:    true,
+			expDeleted: []string{"locked"},
+			expErr:     &LockoutError{ResourceType: "S3Bucket", Names: []string{"denied"}},
+		},
+		{
+			name:    "should not delete in dry run mode",
+			regions: []string{"us-east-1", "eu-west-1"},
+			dryRun:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &mockS3{lockout: tt.lockout}
+			resetter := &S3BucketPolicyResetter{
+				NewClient: func(region string) s3iface.S3API {
+					return client
+				},
+			}
+
+			err := resetter.Reset(&ResetterInput{Regions: tt.regions, DryRun: tt.dryRun, Filters: tt.filters})
+			if tt.expErr == nil {
+				assert.Nil(t, err)
+			} else {
+				assert.Equal(t, tt.expErr, err)
+			}
+			assert.Equal(t, tt.expDeleted, client.deleted)
+		})
+	}
+}
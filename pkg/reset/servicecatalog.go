@@ -0,0 +1,81 @@
+package reset
+
+import (
+	"log"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/servicecatalog"
+	"github.com/aws/aws-sdk-go/service/servicecatalog/servicecatalogiface"
+	"github.com/pkg/errors"
+)
+
+// ServiceCatalogResetter terminates the Service Catalog provisioned products in each region.
+// aws-nuke can't delete the products and portfolios of an account
+// while they are still provisioned.
+type ServiceCatalogResetter struct {
+	// NewClient returns a Service Catalog client for the region
+	NewClient func(region string) servicecatalogiface.ServiceCatalogAPI
+}
+
+// Name of the resetter
+func (r *ServiceCatalogResetter) Name() string {
+	return "ServiceCatalog"
+}
+
+// Phase the resetter runs in
+func (r *ServiceCatalogResetter) Phase() Phase {
+	return PhasePreNuke
+}
+
+// Reset terminates the provisioned products of the account in each region
+func (r *ServiceCatalogResetter) Reset(input *ResetterInput) error {
+	for _, region := range input.Regions {
+		client := r.NewClient(region)
+
+		products := []*servicecatalog.ProvisionedProductDetail{}
+		scanInput := &servicecatalog.ScanProvisionedProductsInput{
+			AccessLevelFilter: &servicecatalog.AccessLevelFilter{
+				Key:   aws.String(servicecatalog.AccessLevelFilterKeyAccount),
+				Value: aws.String("self"),
+			},
+		}
+		for {
+			scanOutput, err := client.ScanProvisionedProducts(scanInput)
+			if err != nil {
+				return errors.Wrapf(err, "Failed to list Service Catalog provisioned products in %s", region)
+			}
+			products = append(products, scanOutput.ProvisionedProducts...)
+			if scanOutput.NextPageToken == nil {
+				break
+			}
+			scanInput.PageToken = scanOutput.NextPageToken
+		}
+
+		for _, product := range products {
+			properties := map[string]string{
+				"ID":   *product.Id,
+				"Name": aws.StringValue(product.Name),
+			}
+			if input.Keep("ServiceCatalogProvisionedProduct", *product.Id, properties) {
+				continue
+			}
+			if input.DryRun {
+				log.Printf("Would terminate Service Catalog provisioned product %s in %s", *product.Id, region)
+				continue
+			}
+			log.Printf("Terminating Service Catalog provisioned product %s in %s", *product.Id, region)
+			_, err := client.TerminateProvisionedProduct(&servicecatalog.TerminateProvisionedProductInput{
+				ProvisionedProductId: product.Id,
+				// Retries of the reset shouldn't terminate the product twice
+				TerminateToken: product.Id,
+				// Remove the provisioned product, even if its resources can't be deleted.
+				// aws-nuke deletes any remaining resources.
+				IgnoreErrors: aws.Bool(true),
+			})
+			if err != nil {
+				return errors.Wrapf(err, "Failed to terminate Service Catalog provisioned product %s in %s", *product.Id, region)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package reset
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/servicecatalog"
+	"github.com/aws/aws-sdk-go/service/servicecatalog/servicecatalogiface"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockServiceCatalog returns provisioned products over two pages,
+// and records what was terminated
+type mockServiceCatalog struct {
+	servicecatalogiface.ServiceCatalogAPI
+	terminated []string
+}
+
+func (m *mockServiceCatalog) ScanProvisionedProducts(input *servicecatalog.ScanProvisionedProductsInput) (*servicecatalog.ScanProvisionedProductsOutput, error) {
+	if input.PageToken == nil {
+		return &servicecatalog.ScanProvisionedProductsOutput{
+			ProvisionedProducts: []*servicecatalog.ProvisionedProductDetail{{Id: aws.String("pp-1")}},
+			NextPageToken:       aws.String("page2"),
+		}, nil
+	}
+	return &servicecatalog.ScanProvisionedProductsOutput{
+		ProvisionedProducts: []*servicecatalog.ProvisionedProductDetail{{Id: aws.String("pp-2")}},
+	}, nil
+}
+
+func (m *mockServiceCatalog) TerminateProvisionedProduct(input *servicecatalog.TerminateProvisionedProductInput) (*servicecatalog.TerminateProvisionedProductOutput, error) {
+	m.terminated = append(m.terminated, *input.ProvisionedProductId)
+	return &servicecatalog.TerminateProvisionedProductOutput{}, nil
+}
+
+func TestServiceCatalogResetter(t *testing.T) {
+	t.Run("should terminate every provisioned product", func(t *testing.T) {
+		client := &mockServiceCatalog{}
+		resetter := &ServiceCatalogResetter{
+			NewClient: func(region string) servicecatalogiface.ServiceCatalogAPI {
+				return client
+			},
+		}
+
+		err := resetter.Reset(&ResetterInput{Regions: []string{"us-east-1"}})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"pp-1", "pp-2"}, client.terminated)
+	})
+
+	t.Run("should not terminate in dry run mode", func(t *testing.T) {
+		client := &mockServiceCatalog{}
+		resetter := &ServiceCatalogResetter{
+			NewClient: func(region string) servicecatalogiface.ServiceCatalogAPI {
+				return client
+			},
+		}
+
+		err := resetter.Reset(&ResetterInput{Regions: []string{"us-east-1"}, DryRun: true})
+		assert.Nil(t, err)
+		assert.Empty(t, client.terminated)
+	})
+}
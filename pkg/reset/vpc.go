@@ -0,0 +1,61 @@
+package reset
+
+import (
+	"log"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/pkg/errors"
+)
+
+// DefaultVPCResetter recreates the default VPC in each region, after aws-nuke
+// has deleted it. Many tools expect accounts to have a default VPC.
+type DefaultVPCResetter struct {
+	// NewClient returns an EC2 client for the region
+	NewClient func(region string) ec2iface.EC2API
+}
+
+// Name of the resetter
+func (r *DefaultVPCResetter) Name() string {
+	return "DefaultVPC"
+}
+
+// Phase the resetter runs in
+func (r *DefaultVPCResetter) Phase() Phase {
+	return PhasePostNuke
+}
+
+// Reset creates a default VPC in each region which doesn't have one
+func (r *DefaultVPCResetter) Reset(input *ResetterInput) error {
+	for _, region := range input.Regions {
+		client := r.NewClient(region)
+
+		describeVpcsOutput, err := client.DescribeVpcs(&ec2.DescribeVpcsInput{
+			Filters: []*ec2.Filter{
+				{
+					Name:   aws.String("isDefault"),
+					Values: []*string{aws.String("true")},
+				},
+			},
+		})
+		if err != nil {
+			return errors.Wrapf(err, "Failed to find the default VPC in %s", region)
+		}
+		if len(describeVpcsOutput.Vpcs) > 0 {
+			continue
+		}
+
+		if input.DryRun {
+			log.Printf("Would create the default VPC in %s", region)
+			continue
+		}
+		log.Printf("Creating the default VPC in %s", region)
+		createDefaultVpcOutput, err := client.CreateDefaultVpc(&ec2.CreateDefaultVpcInput{})
+		if err != nil {
+			return errors.Wrapf(err, "Failed to create the default VPC in %s", region)
+		}
+		log.Printf("Created the default VPC %s in %s", *createDefaultVpcOutput.Vpc.VpcId, region)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package reset
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockEC2 records whether a default VPC was created
+type mockEC2 struct {
+	ec2iface.EC2API
+	defaultVpcs []*ec2.Vpc
+	created     bool
+}
+
+func (m *mockEC2) DescribeVpcs(input *ec2.DescribeVpcsInput) (*ec2.DescribeVpcsOutput, error) {
+	return &ec2.DescribeVpcsOutput{Vpcs: m.defaultVpcs}, nil
+}
+
+func (m *mockEC2) CreateDefaultVpc(input *ec2.CreateDefaultVpcInput) (*ec2.CreateDefaultVpcOutput, error) {
+	m.created = true
+	return &ec2.CreateDefaultVpcOutput{Vpc: &ec2.Vpc{VpcId: aws.String("vpc-123")}}, nil
+}
+
+func TestDefaultVPCResetter(t *testing.T) {
+	tests := []struct {
+		name        string
+		dryRun      bool
+		defaultVpcs []*ec2.Vpc
+		expCreated  bool
+	}{
+		{
+			name:       "should create a missing default VPC",
+			expCreated: true,
+		},
+		{
+			name:        "should keep an existing default VPC",
+			defaultVpcs: []*ec2.Vpc{{VpcId: aws.String("vpc-456")}},
+		},
+		{
+			name:   "should not create in dry run mode",
+			dryRun: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &mockEC2{defaultVpcs: tt.defaultVpcs}
+			resetter := &DefaultVPCResetter{
+				NewClient: func(region string) ec2iface.EC2API {
+					return client
+				},
+			}
+
+			err := resetter.Reset(&ResetterInput{Regions: []string{"us-east-1"}, DryRun: tt.dryRun})
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expCreated, client.created)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package resetrun
+
+import (
+	"github.com/Optum/dce/pkg/errors"
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+// ResetRun is the record of a single reset of an account.
+// It is written when the reset starts, and again when it completes,
+// so the history of resets shows why an account was reset, and whether it worked.
+type ResetRun struct {
+	AccountID      *string `json:"accountId,omitempty" dynamodbav:"AccountId" schema:"-"`                       // AWS Account ID
+	StartedOn      *int64  `json:"startedOn,omitempty" dynamodbav:"StartedOn" schema:"-"`                       // Reset start Epoch Timestamp
+	EndedOn        *int64  `json:"endedOn,omitempty" dynamodbav:"EndedOn,omitempty" schema:"-"`                 // Reset end Epoch Timestamp
+	Reason         *string `json:"reason,omitempty" dynamodbav:"Reason,omitempty" schema:"-"`                   // What triggered the reset
+	BuildID        *string `json:"buildId,omitempty" dynamodbav:"BuildId,omitempty" schema:"-"`                 // CodeBuild build ID
+	Status         *Status `json:"status,omitempty" dynamodbav:"RunStatus,omitempty" schema:"status,omitempty"` // Result of the reset
+	Error          *string `json:"error,omitempty" dynamodbav:"Error,omitempty" schema:"-"`                     // Why the reset failed
+	Report         *string `json:"report,omitempty" dynamodbav:"Report,omitempty" schema:"-"`                   // S3 location of the aws-nuke report
+	LastModifiedOn *int64  `json:"lastModifiedOn,omitempty" dynamodbav:"LastModifiedOn" schema:"-"`             // Last Modified Epoch Timestamp
+	Limit          *int64  `json:"-" dynamodbav:"-" schema:"limit,omitempty"`
+	NextStartedOn  *int64  `json:"-" dynamodbav:"-" schema:"nextStartedOn,omitempty"`
+}
+
+// Validate the reset run data
+func (r *ResetRun) Validate() error {
+	err := validation.ValidateStruct(r,
+		validation.Field(&r.AccountID, validateAccountID...),
+		validation.Field(&r.StartedOn, validateInt64...),
+		validation.Field(&r.Status, validateStatus...),
+		validation.Field(&r.LastModifiedOn, validateInt64...),
+	)
+	if err != nil {
+		return errors.NewValidation("reset run", err)
+	}
+	return nil
+}
+
+// ResetRuns is a list of type ResetRun
+type ResetRuns []ResetRun
+
+// Status is a reset run status type
+type Status string
+
+const (
+	// StatusRunning means the reset has started, and not completed
+	StatusRunning Status = "Running"
+	// StatusSucceeded means the reset completed
+	StatusSucceeded Status = "Succeeded"
+	// StatusFailed means the reset failed, see the run's error
+	StatusFailed Status = "Failed"
+)
+
+// String returns the string value of Status
+func (c Status) String() string {
+	return string(c)
+}
+
+// StatusPtr returns a pointer to the string value of Status
+func (c Status) StatusPtr() *Status {
+	v := c
+	return &v
+}
@@ -0,0 +1,17 @@
+//
+
+package resetruniface
+
+import (
+	"github.com/Optum/dce/pkg/resetrun"
+)
+
+// Servicer makes working with the Reset Run Service struct easier
+type Servicer interface {
+	// Start records the start of a reset of the account
+	Start(accountID string, reason string, buildID string) (*resetrun.ResetRun, error)
+	// End records the completion of a reset, failed if resetErr is not nil
+	End(run *resetrun.ResetRun, resetErr error) (*resetrun.ResetRun, error)
+	// List Get a list of reset runs of an account, most recent first
+	List(query *resetrun.ResetRun) (*resetrun.ResetRuns, error)
+}
@@ -0,0 +1,106 @@
+package resetrun
+
+import (
+	"time"
+)
+
+// Writer put an item into the data store
+type Writer interface {
+	Write(i *ResetRun, lastModifiedOn *int64) error
+}
+
+// MultipleReader reads multiple reset runs from the data store
+type MultipleReader interface {
+	List(query *ResetRun) (*ResetRuns, error)
+}
+
+// ReaderWriter includes Reader and Writer interfaces
+type ReaderWriter interface {
+	MultipleReader
+	Writer
+}
+
+// Service is a type corresponding to a Reset Run table record
+type Service struct {
+	dataSvc ReaderWriter
+}
+
+// Start records the start of a reset of the account.
+// reason is what triggered the reset, and buildID the CodeBuild build running it, if any.
+func (a *Service) Start(accountID string, reason string, buildID string) (*ResetRun, error) {
+	now := time.Now().Unix()
+	run := &ResetRun{
+		AccountID:      &accountID,
+		StartedOn:      &now,
+		Status:         StatusRunning.StatusPtr(),
+		LastModifiedOn: &now,
+	}
+	if reason != "" {
+		run.Reason = &reason
+	}
+	if buildID != "" {
+		run.BuildID = &buildID
+	}
+
+	err := run.Validate()
+	if err != nil {
+		return nil, err
+	}
+	err = a.dataSvc.Write(run, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return run, nil
+}
+
+// End records the completion of a reset.
+// The run failed if resetErr is not nil, and the error is kept with the run.
+func (a *Service) End(run *ResetRun, resetErr error) (*ResetRun, error) {
+	lastModifiedOn := run.LastModifiedOn
+	now := time.Now().Unix()
+	run.EndedOn = &now
+	run.LastModifiedOn = &now
+	if resetErr != nil {
+		errMsg := resetErr.Error()
+		run.Status = StatusFailed.StatusPtr()
+		run.Error = &errMsg
+	} else {
+		run.Status = StatusSucceeded.StatusPtr()
+		run.Error = nil
+	}
+
+	err := run.Validate()
+	if err != nil {
+		return nil, err
+	}
+	err = a.dataSvc.Write(run, lastModifiedOn)
+	if err != nil {
+		return nil, err
+	}
+
+	return run, nil
+}
+
+// List Get a list of reset runs of an account, most recent first
+func (a *Service) List(query *ResetRun) (*ResetRuns, error) {
+
+	runs, err := a.dataSvc.List(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return runs, nil
+}
+
+// NewServiceInput Input for creating a new Service
+type NewServiceInput struct {
+	DataSvc ReaderWriter
+}
+
+// NewService creates a new instance of the Service
+func NewService(input NewServiceInput) *Service {
+	return &Service{
+		dataSvc: input.DataSvc,
+	}
+}
@@ -0,0 +1,120 @@
+package resetrun_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/resetrun"
+	"github.com/Optum/dce/pkg/resetrun/mocks"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestStart(t *testing.T) {
+	tests := []struct {
+		name      string
+		accountID string
+		reason    string
+		buildID   string
+		writeErr  error
+		expErr    error
+	}{
+		{
+			name:      "should record a running reset",
+			accountID: "123456789012",
+			reason:    "LeaseEnded",
+			buildID:   "account-reset-prod:7f5d6c3e",
+		},
+		{
+			name:      "should record a reset without a build",
+			accountID: "123456789012",
+		},
+		{
+			name:      "should fail with an invalid account ID",
+			accountID: "abc",
+			expErr:    errors.NewValidation("reset run", fmt.Errorf("accountId: must be a string with all digits.")), //nolint golint
+		},
+		{
+			name:      "should fail when the write fails",
+			accountID: "123456789012",
+			writeErr:  errors.NewInternalServer("failure", nil),
+			expErr:    errors.NewInternalServer("failure", nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocksRw := &mocks.ReaderWriter{}
+			mocksRw.On("Write", mock.AnythingOfType("*resetrun.ResetRun"), (*int64)(nil)).Return(tt.writeErr)
+
+			runSvc := resetrun.NewService(resetrun.NewServiceInput{
+				DataSvc: mocksRw,
+			})
+
+			run, err := runSvc.Start(tt.accountID, tt.reason, tt.buildID)
+			assert.Truef(t, errors.Is(err, tt.expErr), "actual error %q doesn't match expected error %q", err, tt.expErr)
+			if tt.expErr == nil {
+				assert.Equal(t, resetrun.StatusRunning, *run.Status)
+				assert.NotNil(t, run.StartedOn)
+				assert.Nil(t, run.EndedOn)
+				if tt.reason != "" {
+					assert.Equal(t, tt.reason, *run.Reason)
+				} else {
+					assert.Nil(t, run.Reason)
+				}
+				if tt.buildID != "" {
+					assert.Equal(t, tt.buildID, *run.BuildID)
+				} else {
+					assert.Nil(t, run.BuildID)
+				}
+			}
+		})
+	}
+}
+
+func TestEnd(t *testing.T) {
+	tests := []struct {
+		name      string
+		resetErr  error
+		expStatus resetrun.Status
+		expError  *string
+	}{
+		{
+			name:      "should record a successful reset",
+			expStatus: resetrun.StatusSucceeded,
+		},
+		{
+			name:      "should record a failed reset with its error",
+			resetErr:  fmt.Errorf("Failed to execute aws-nuke on account 123456789012"),
+			expStatus: resetrun.StatusFailed,
+			expError:  aws.String("Failed to execute aws-nuke on account 123456789012"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			run := &resetrun.ResetRun{
+				AccountID:      aws.String("123456789012"),
+				StartedOn:      aws.Int64(1573592058),
+				Status:         resetrun.StatusRunning.StatusPtr(),
+				LastModifiedOn: aws.Int64(1573592058),
+			}
+
+			mocksRw := &mocks.ReaderWriter{}
+			mocksRw.On("Write", mock.AnythingOfType("*resetrun.ResetRun"), aws.Int64(1573592058)).Return(nil)
+
+			runSvc := resetrun.NewService(resetrun.NewServiceInput{
+				DataSvc: mocksRw,
+			})
+
+			ended, err := runSvc.End(run, tt.resetErr)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expStatus, *ended.Status)
+			assert.Equal(t, tt.expError, ended.Error)
+			assert.NotNil(t, ended.EndedOn)
+			mocksRw.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package resetrun
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+)
+
+// We don't use the internal errors package here because validation will rewrite it anyways
+// Just spit out errors and turn them into validation errors inside the appropriate functions
+
+var validateAccountID = []validation.Rule{
+	validation.NotNil.Error("must be a string"),
+	is.Digit.Error("must be a string with all digits"),
+}
+
+var validateInt64 = []validation.Rule{
+	validation.NotNil.Error("must be an epoch timestamp"),
+}
+
+var validateStatus = []validation.Rule{
+	validation.NotNil.Error("must be a valid reset run status"),
+	validation.In(StatusRunning, StatusSucceeded, StatusFailed).Error("must be a valid reset run status"),
+}
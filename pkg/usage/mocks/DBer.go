@@ -34,6 +34,29 @@ func (_m *DBer) GetUsageByDateRange(startDate time.Time, endDate time.Time) ([]*
 	return r0, r1
 }
 
+// GetUsageByLease provides a mock function with given fields: leaseID, startDate, endDate
+func (_m *DBer) GetUsageByLease(leaseID string, startDate time.Time, endDate time.Time) ([]*usage.Usage, error) {
+	ret := _m.Called(leaseID, startDate, endDate)
+
+	var r0 []*usage.Usage
+	if rf, ok := ret.Get(0).(func(string, time.Time, time.Time) []*usage.Usage); ok {
+		r0 = rf(leaseID, startDate, endDate)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*usage.Usage)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, time.Time, time.Time) error); ok {
+		r1 = rf(leaseID, startDate, endDate)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetUsageByPrincipal provides a mock function with given fields: startDate, principalID
 func (_m *DBer) GetUsageByPrincipal(startDate time.Time, principalID string) ([]*usage.Usage, error) {
 	ret := _m.Called(startDate, principalID)
@@ -70,3 +93,17 @@ func (_m *DBer) PutUsage(input usage.Usage) error {
 
 	return r0
 }
+
+// UpdateLeaseUsage provides a mock function with given fields: input
+func (_m *DBer) UpdateLeaseUsage(input usage.Usage) error {
+	ret := _m.Called(input)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(usage.Usage) error); ok {
+		r0 = rf(input)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -56,6 +56,29 @@ func (_m *ReaderWriter) List(query *usage.Usage) (*usage.Usages, error) {
 	return r0, r1
 }
 
+// ListByMonth provides a mock function with given fields: month, query
+func (_m *ReaderWriter) ListByMonth(month string, query *usage.Usage) (*usage.Usages, error) {
+	ret := _m.Called(month, query)
+
+	var r0 *usage.Usages
+	if rf, ok := ret.Get(0).(func(string, *usage.Usage) *usage.Usages); ok {
+		r0 = rf(month, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*usage.Usages)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, *usage.Usage) error); ok {
+		r1 = rf(month, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Write provides a mock function with given fields: i
 func (_m *ReaderWriter) Write(i *usage.Usage) error {
 	ret := _m.Called(i)
@@ -1,6 +1,8 @@
 package usage
 
 import (
+	"time"
+
 	"github.com/Optum/dce/pkg/errors"
 	validation "github.com/go-ozzo/ozzo-validation"
 )
@@ -9,6 +11,7 @@ import (
 type Usage struct {
 	PrincipalID     *string  `json:"principalId,omitempty" dynamodbav:"PrincipalId" schema:"principalId,omitempty"`              // User Principal ID
 	AccountID       *string  `json:"accountId,omitempty" dynamodbav:"AccountId,omitempty" schema:"accountId,omitempty"`          // AWS Account ID
+	LeaseID         *string  `json:"leaseId,omitempty" dynamodbav:"LeaseId,omitempty" schema:"leaseId,omitempty"`                // Lease ID of the AWS Account
 	StartDate       *int64   `json:"startDate,omitempty" dynamodbav:"StartDate" schema:"startDate,omitempty"`                    // Usage start date Epoch Timestamp
 	EndDate         *int64   `json:"endDate,omitempty" dynamodbav:"EndDate,omitempty" schema:"endDate,omitempty"`                // Usage ends date Epoch Timestamp
 	CostAmount      *float64 `json:"costAmount,omitempty" dynamodbav:"CostAmount,omitempty" schema:"costAmount,omitempty"`       // Cost Amount for given period
@@ -17,6 +20,30 @@ type Usage struct {
 	Limit           *int64   `json:"-" dynamodbav:"-" schema:"limit,omitempty"`
 	NextStartDate   *int64   `json:"-" dynamodbav:"-" schema:"nextStartDate,omitempty"`
 	NextPrincipalID *string  `json:"-" dynamodbav:"-" schema:"nextPrincipalId,omitempty"`
+	// Cost Amount for given period, by AWS Account ID.
+	// A principal with several active leases has a single usage record per day,
+	// so the spend of each leased account is tracked here.
+	AccountCosts map[string]float64 `json:"accountCosts,omitempty" dynamodbav:"AccountCosts,omitempty" schema:"-"`
+	// Cost Amount for given period, by Lease ID.
+	// Repeat leases of the same account by a principal have their own spend.
+	LeaseCosts map[string]float64 `json:"leaseCosts,omitempty" dynamodbav:"LeaseCosts,omitempty" schema:"-"`
+	// Cost Amount for given period, by AWS service name.
+	// Summed across every account leased by the principal.
+	ServiceCosts map[string]float64 `json:"serviceCosts,omitempty" dynamodbav:"ServiceCosts,omitempty" schema:"-"`
+	// Cost Amount for given period, by AWS Account ID and AWS service name,
+	// so ServiceCosts can be recalculated when the spend of one account changes.
+	AccountServiceCosts map[string]map[string]float64 `json:"-" dynamodbav:"AccountServiceCosts,omitempty" schema:"-"`
+	// Version is incremented on each update by a lease,
+	// so concurrent updates by the principal's other leases aren't lost
+	Version *int64 `json:"-" dynamodbav:"Version,omitempty" schema:"-"`
+	// StartMonth is the month of the start date, e.g. "2019-10".
+	// It is the key of a secondary index, so usage can be listed by date range.
+	StartMonth *string `json:"-" dynamodbav:"StartMonth,omitempty" schema:"-"`
+}
+
+// StartMonthOf returns the month usage starting on the given date is indexed by
+func StartMonthOf(startDate int64) string {
+	return time.Unix(startDate, 0).UTC().Format("2006-01")
 }
 
 // Validate the account data
@@ -24,6 +51,7 @@ func (u *Usage) Validate() error {
 	err := validation.ValidateStruct(u,
 		validation.Field(&u.PrincipalID, validatePrincipalID...),
 		validation.Field(&u.AccountID, validateAccountID...),
+		validation.Field(&u.LeaseID, validateLeaseID...),
 		validation.Field(&u.StartDate, validateInt64...),
 		validation.Field(&u.EndDate, validateInt64...),
 		validation.Field(&u.CostAmount, validateFloat64...),
@@ -36,10 +64,98 @@ func (u *Usage) Validate() error {
 	return nil
 }
 
+// AccountCostAmount returns the cost amount spent in the given account
+func (u *Usage) AccountCostAmount(accountID string) float64 {
+	if u.AccountCosts != nil {
+		return u.AccountCosts[accountID]
+	}
+	if u.AccountID != nil && *u.AccountID == accountID && u.CostAmount != nil {
+		return *u.CostAmount
+	}
+	return 0
+}
+
+// LeaseCostAmount returns the cost amount spent by the given lease.
+// Usage recorded before spend was tracked by lease is attributed
+// to the lease by its AWS Account ID.
+func (u *Usage) LeaseCostAmount(leaseID string, accountID string) float64 {
+	if u.LeaseCosts != nil {
+		return u.LeaseCosts[leaseID]
+	}
+	return u.AccountCostAmount(accountID)
+}
+
+// SetAccountServiceCosts records the cost amount spent in the given account
+// by AWS service, and recalculates ServiceCosts across all accounts
+func (u *Usage) SetAccountServiceCosts(accountID string, serviceCosts map[string]float64) {
+	if u.AccountServiceCosts == nil {
+		u.AccountServiceCosts = map[string]map[string]float64{}
+	}
+	u.AccountServiceCosts[accountID] = serviceCosts
+
+	u.ServiceCosts = map[string]float64{}
+	for _, accountServiceCosts := range u.AccountServiceCosts {
+		for service, costAmount := range accountServiceCosts {
+			u.ServiceCosts[service] = u.ServiceCosts[service] + costAmount
+		}
+	}
+}
+
+// MergeLeaseUsage adds the spend of a lease to the usage record of its
+// principal, keeping the spend of the principal's other leases,
+// and increments the version of the record.
+// ServiceCosts of the lease usage are the costs of the lease by AWS service.
+func (u *Usage) MergeLeaseUsage(lease Usage) {
+	accountID := *lease.AccountID
+	costAmount := *lease.CostAmount
+
+	// Records written before spend was tracked by account have the spend of a single account
+	if u.AccountCosts == nil {
+		u.AccountCosts = map[string]float64{}
+		if u.AccountID != nil && u.CostAmount != nil {
+			u.AccountCosts[*u.AccountID] = *u.CostAmount
+		}
+	}
+	if u.LeaseCosts == nil {
+		u.LeaseCosts = map[string]float64{}
+	}
+
+	u.PrincipalID = lease.PrincipalID
+	u.AccountID = lease.AccountID
+	u.LeaseID = lease.LeaseID
+	u.StartDate = lease.StartDate
+	if lease.StartDate != nil {
+		startMonth := StartMonthOf(*lease.StartDate)
+		u.StartMonth = &startMonth
+	}
+	u.EndDate = lease.EndDate
+	u.CostCurrency = lease.CostCurrency
+	u.TimeToLive = lease.TimeToLive
+
+	u.AccountCosts[accountID] = costAmount
+	if lease.LeaseID != nil {
+		u.LeaseCosts[*lease.LeaseID] = costAmount
+	}
+	u.SetAccountServiceCosts(accountID, lease.ServiceCosts)
+
+	principalCostAmount := 0.0
+	for _, accountCostAmount := range u.AccountCosts {
+		principalCostAmount = principalCostAmount + accountCostAmount
+	}
+	u.CostAmount = &principalCostAmount
+
+	version := int64(1)
+	if u.Version != nil {
+		version = *u.Version + 1
+	}
+	u.Version = &version
+}
+
 // NewUsageInput has the input for create a new usage record
 type NewUsageInput struct {
 	PrincipalID  string
 	AccountID    string
+	LeaseID      string
 	StartDate    int64
 	EndDate      int64
 	CostAmount   float64
@@ -50,6 +166,7 @@ type NewUsageInput struct {
 // NewUsage creates a new instance of usage
 func NewUsage(input NewUsageInput) (*Usage, error) {
 
+	startMonth := StartMonthOf(input.StartDate)
 	new := &Usage{
 		PrincipalID:  &input.PrincipalID,
 		AccountID:    &input.AccountID,
@@ -58,6 +175,12 @@ func NewUsage(input NewUsageInput) (*Usage, error) {
 		CostAmount:   &input.CostAmount,
 		CostCurrency: &input.CostCurrency,
 		TimeToLive:   &input.TimeToLive,
+		StartMonth:   &startMonth,
+	}
+
+	// Lease ID is the key of a secondary index, so it can't be empty
+	if input.LeaseID != "" {
+		new.LeaseID = &input.LeaseID
 	}
 
 	err := new.Validate()
@@ -0,0 +1,223 @@
+package usage_test
+
+import (
+	"testing"
+
+	"github.com/Optum/dce/pkg/usage"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountCostAmount(t *testing.T) {
+	tests := []struct {
+		name      string
+		usage     usage.Usage
+		accountID string
+		exp       float64
+	}{
+		{
+			name: "should use the account cost breakdown",
+			usage: usage.Usage{
+				AccountID:  aws.String("123456789012"),
+				CostAmount: aws.Float64(30),
+				AccountCosts: map[string]float64{
+					"123456789012": 10,
+					"210987654321": 20,
+				},
+			},
+			accountID: "210987654321",
+			exp:       20,
+		},
+		{
+			name: "should be zero for an account missing from the breakdown",
+			usage: usage.Usage{
+				AccountID:  aws.String("123456789012"),
+				CostAmount: aws.Float64(10),
+				AccountCosts: map[string]float64{
+					"123456789012": 10,
+				},
+			},
+			accountID: "210987654321",
+			exp:       0,
+		},
+		{
+			name: "should fall back to the cost amount for the same account",
+			usage: usage.Usage{
+				AccountID:  aws.String("123456789012"),
+				CostAmount: aws.Float64(10),
+			},
+			accountID: "123456789012",
+			exp:       10,
+		},
+		{
+			name: "should be zero for a different account",
+			usage: usage.Usage{
+				AccountID:  aws.String("123456789012"),
+				CostAmount: aws.Float64(10),
+			},
+			accountID: "210987654321",
+			exp:       0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.exp, tt.usage.AccountCostAmount(tt.accountID))
+		})
+	}
+}
+
+func TestLeaseCostAmount(t *testing.T) {
+	tests := []struct {
+		name      string
+		usage     usage.Usage
+		leaseID   string
+		accountID string
+		exp       float64
+	}{
+		{
+			name: "should use the lease cost breakdown",
+			usage: usage.Usage{
+				AccountID:  aws.String("123456789012"),
+				LeaseID:    aws.String("lease2"),
+				CostAmount: aws.Float64(30),
+				AccountCosts: map[string]float64{
+					"123456789012": 30,
+				},
+				LeaseCosts: map[string]float64{
+					"lease1": 10,
+					"lease2": 20,
+				},
+			},
+			leaseID:   "lease1",
+			accountID: "123456789012",
+			exp:       10,
+		},
+		{
+			name: "should be zero for a lease missing from the breakdown",
+			usage: usage.Usage{
+				AccountID:  aws.String("123456789012"),
+				CostAmount: aws.Float64(10),
+				LeaseCosts: map[string]float64{
+					"lease2": 10,
+				},
+			},
+			leaseID:   "lease1",
+			accountID: "123456789012",
+			exp:       0,
+		},
+		{
+			name: "should fall back to the account for usage without a lease breakdown",
+			usage: usage.Usage{
+				AccountID:  aws.String("123456789012"),
+				CostAmount: aws.Float64(10),
+			},
+			leaseID:   "lease1",
+			accountID: "123456789012",
+			exp:       10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.exp, tt.usage.LeaseCostAmount(tt.leaseID, tt.accountID))
+		})
+	}
+}
+
+func TestSetAccountServiceCosts(t *testing.T) {
+	u := usage.Usage{
+		AccountServiceCosts: map[string]map[string]float64{
+			"123456789012": {
+				"Amazon Elastic Compute Cloud - Compute": 10,
+				"EC2 - Other":                            5,
+			},
+			"210987654321": {
+				"Amazon Elastic Compute Cloud - Compute": 20,
+			},
+		},
+	}
+
+	u.SetAccountServiceCosts("210987654321", map[string]float64{
+		"Amazon Elastic Compute Cloud - Compute": 30,
+		"Amazon Simple Storage Service":          1,
+	})
+
+	assert.Equal(t, map[string]float64{
+		"Amazon Elastic Compute Cloud - Compute": 40,
+		"EC2 - Other":                            5,
+		"Amazon Simple Storage Service":          1,
+	}, u.ServiceCosts)
+}
+
+func TestMergeLeaseUsage(t *testing.T) {
+	// The principal's other lease has already recorded its spend for today
+	u := usage.Usage{
+		PrincipalID: aws.String("test-user"),
+		AccountID:   aws.String("210987654321"),
+		LeaseID:     aws.String("other-lease"),
+		CostAmount:  aws.Float64(20),
+		AccountCosts: map[string]float64{
+			"210987654321": 20,
+		},
+		LeaseCosts: map[string]float64{
+			"other-lease": 20,
+		},
+		AccountServiceCosts: map[string]map[string]float64{
+			"210987654321": {
+				"Amazon Elastic Compute Cloud - Compute": 20,
+			},
+		},
+		Version: aws.Int64(3),
+	}
+
+	u.MergeLeaseUsage(usage.Usage{
+		PrincipalID: aws.String("test-user"),
+		AccountID:   aws.String("123456789012"),
+		LeaseID:     aws.String("test-lease"),
+		CostAmount:  aws.Float64(10),
+		ServiceCosts: map[string]float64{
+			"Amazon Elastic Compute Cloud - Compute": 8,
+			"EC2 - Other":                            2,
+		},
+	})
+
+	assert.Equal(t, map[string]float64{
+		"123456789012": 10,
+		"210987654321": 20,
+	}, u.AccountCosts)
+	assert.Equal(t, map[string]float64{
+		"test-lease":  10,
+		"other-lease": 20,
+	}, u.LeaseCosts)
+	assert.Equal(t, map[string]float64{
+		"Amazon Elastic Compute Cloud - Compute": 28,
+		"EC2 - Other":                            2,
+	}, u.ServiceCosts)
+	assert.Equal(t, "test-lease", *u.LeaseID)
+	assert.Equal(t, 30.0, *u.CostAmount)
+	assert.Equal(t, int64(4), *u.Version)
+}
+
+func TestMergeLeaseUsageWithoutAccountCosts(t *testing.T) {
+	// Records written before spend was tracked by account
+	u := usage.Usage{
+		PrincipalID: aws.String("test-user"),
+		AccountID:   aws.String("210987654321"),
+		CostAmount:  aws.Float64(20),
+	}
+
+	u.MergeLeaseUsage(usage.Usage{
+		PrincipalID: aws.String("test-user"),
+		AccountID:   aws.String("123456789012"),
+		LeaseID:     aws.String("test-lease"),
+		CostAmount:  aws.Float64(10),
+	})
+
+	assert.Equal(t, map[string]float64{
+		"123456789012": 10,
+		"210987654321": 20,
+	}, u.AccountCosts)
+	assert.Equal(t, 30.0, *u.CostAmount)
+	assert.Equal(t, int64(1), *u.Version)
+}
@@ -3,8 +3,10 @@ package usage
 import (
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/Optum/dce/pkg/errors"
+	"github.com/aws/aws-sdk-go/aws"
 )
 
 // Writer put an item into the data store
@@ -20,6 +22,7 @@ type SingleReader interface {
 // MultipleReader reads multiple usages from the data store
 type MultipleReader interface {
 	List(query *Usage) (*Usages, error)
+	ListByMonth(month string, query *Usage) (*Usages, error)
 }
 
 // Reader data Layer
@@ -115,6 +118,51 @@ func (a *Service) List(query *Usage) (*Usages, error) {
 	return usages, nil
 }
 
+// Summarize rolls up the usage within a period by principal, account or lease
+func (a *Service) Summarize(input *SummaryInput) (*Summary, error) {
+	err := input.Validate()
+	if err != nil {
+		return nil, errors.NewValidation("usage summary", err)
+	}
+
+	start, end := input.Period.Range(input.Date)
+	usages, err := a.ListByDateRange(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSummary(input, *usages)
+}
+
+// ListByDateRange returns every usage record with a start date
+// between the start (inclusive) and end (exclusive) dates
+func (a *Service) ListByDateRange(start time.Time, end time.Time) (*Usages, error) {
+	// Usage is indexed by the month of its start date, so query each month in the range
+	usages := Usages{}
+	start = start.UTC()
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+	month := time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for ; month.Before(end); month = month.AddDate(0, 1, 0) {
+		query := &Usage{
+			StartDate: aws.Int64(day.Unix()),
+			EndDate:   aws.Int64(end.Unix()),
+		}
+		for {
+			page, err := a.dataSvc.ListByMonth(StartMonthOf(month.Unix()), query)
+			if err != nil {
+				return nil, err
+			}
+			usages = append(usages, *page...)
+
+			if query.NextStartDate == nil || query.NextPrincipalID == nil {
+				break
+			}
+		}
+	}
+
+	return &usages, nil
+}
+
 // NewServiceInput Input for creating a new Service
 type NewServiceInput struct {
 	DataSvc ReaderWriter
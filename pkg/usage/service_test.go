@@ -176,6 +176,7 @@ func TestCreate(t *testing.T) {
 	var costAmount float64 = 100.00
 	var costCurrency string = "USD"
 	var timeToLive int64 = time.Now().AddDate(0, 0, 30).Unix()
+	startMonth := usage.StartMonthOf(startDate)
 	type response struct {
 		data *usage.Usage
 		err  error
@@ -208,6 +209,7 @@ func TestCreate(t *testing.T) {
 					CostCurrency: &costCurrency,
 					CostAmount:   &costAmount,
 					TimeToLive:   &timeToLive,
+					StartMonth:   &startMonth,
 				},
 				err: nil,
 			},
@@ -304,3 +306,127 @@ func TestCreate(t *testing.T) {
 		})
 	}
 }
+
+func TestSummarize(t *testing.T) {
+	date := time.Date(2019, 10, 16, 0, 0, 0, 0, time.UTC)
+	day1 := time.Date(2019, 10, 14, 0, 0, 0, 0, time.UTC).Unix()
+	user1 := "user1"
+	user2 := "user2"
+	costAmount1 := float64(7)
+	costAmount2 := float64(14)
+
+	t.Run("should read every page of the period", func(t *testing.T) {
+		weekStart := time.Date(2019, 10, 13, 0, 0, 0, 0, time.UTC).Unix()
+		weekEnd := time.Date(2019, 10, 20, 0, 0, 0, 0, time.UTC).Unix()
+		isWeek := func(query *usage.Usage) bool {
+			return *query.StartDate == weekStart && *query.EndDate == weekEnd
+		}
+
+		mocksRwd := &mocks.ReaderWriter{}
+		mocksRwd.On("ListByMonth", "2019-10", mock.MatchedBy(func(query *usage.Usage) bool {
+			return isWeek(query) && query.NextPrincipalID == nil
+		})).Run(func(args mock.Arguments) {
+			query := args.Get(1).(*usage.Usage)
+			query.NextStartDate = &day1
+			query.NextPrincipalID = &user1
+		}).Return(&usage.Usages{
+			{StartDate: &day1, PrincipalID: &user1, CostAmount: &costAmount1},
+		}, nil).Once()
+		mocksRwd.On("ListByMonth", "2019-10", mock.MatchedBy(func(query *usage.Usage) bool {
+			return isWeek(query) && query.NextPrincipalID != nil
+		})).Run(func(args mock.Arguments) {
+			query := args.Get(1).(*usage.Usage)
+			query.NextStartDate = nil
+			query.NextPrincipalID = nil
+		}).Return(&usage.Usages{
+			{StartDate: &day1, PrincipalID: &user2, CostAmount: &costAmount2},
+		}, nil).Once()
+
+		usageSvc := usage.NewService(
+			usage.NewServiceInput{
+				DataSvc: mocksRwd,
+			},
+		)
+
+		summary, err := usageSvc.Summarize(&usage.SummaryInput{
+			GroupBy: usage.SummaryGroupByPrincipal,
+			Period:  usage.SummaryPeriodWeekly,
+			Date:    date,
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, float64(21), summary.TotalCostAmount)
+		assert.Equal(t, 2, summary.Count)
+		mocksRwd.AssertNumberOfCalls(t, "ListByMonth", 2)
+		mocksRwd.AssertNotCalled(t, "List", mock.Anything)
+	})
+
+	t.Run("should fail on an invalid input", func(t *testing.T) {
+		usageSvc := usage.NewService(
+			usage.NewServiceInput{
+				DataSvc: &mocks.ReaderWriter{},
+			},
+		)
+
+		_, err := usageSvc.Summarize(&usage.SummaryInput{
+			GroupBy: "service",
+			Period:  usage.SummaryPeriodWeekly,
+		})
+		assert.True(t, errors.Is(err, errors.NewValidation("usage summary", fmt.Errorf("groupBy must be one of \"principal\", \"account\" or \"lease\""))))
+	})
+
+	t.Run("should fail when usage can't be listed", func(t *testing.T) {
+		mocksRwd := &mocks.ReaderWriter{}
+		mocksRwd.On("ListByMonth", "2019-10", mock.AnythingOfType("*usage.Usage")).Return(nil, errors.NewInternalServer("failure", nil))
+
+		usageSvc := usage.NewService(
+			usage.NewServiceInput{
+				DataSvc: mocksRwd,
+			},
+		)
+
+		_, err := usageSvc.Summarize(&usage.SummaryInput{
+			GroupBy: usage.SummaryGroupByAccount,
+			Period:  usage.SummaryPeriodMonthly,
+			Date:    date,
+		})
+		assert.True(t, errors.Is(err, errors.NewInternalServer("failure", nil)))
+	})
+}
+
+func TestListByDateRange(t *testing.T) {
+	day1 := time.Date(2019, 10, 31, 0, 0, 0, 0, time.UTC).Unix()
+	day2 := time.Date(2019, 11, 1, 0, 0, 0, 0, time.UTC).Unix()
+	end := time.Date(2019, 11, 2, 0, 0, 0, 0, time.UTC)
+	user1 := "user1"
+
+	t.Run("should query each month in the range", func(t *testing.T) {
+		isRange := func(query *usage.Usage) bool {
+			return *query.StartDate == day1 && *query.EndDate == end.Unix()
+		}
+
+		mocksRwd := &mocks.ReaderWriter{}
+		mocksRwd.On("ListByMonth", "2019-10", mock.MatchedBy(isRange)).Return(&usage.Usages{
+			{StartDate: &day1, PrincipalID: &user1},
+		}, nil).Once()
+		mocksRwd.On("ListByMonth", "2019-11", mock.MatchedBy(isRange)).Return(&usage.Usages{
+			{StartDate: &day2, PrincipalID: &user1},
+		}, nil).Once()
+
+		usageSvc := usage.NewService(
+			usage.NewServiceInput{
+				DataSvc: mocksRwd,
+			},
+		)
+
+		usages, err := usageSvc.ListByDateRange(
+			time.Date(2019, 10, 31, 12, 0, 0, 0, time.UTC),
+			end,
+		)
+		assert.Nil(t, err)
+		assert.Equal(t, &usage.Usages{
+			{StartDate: &day1, PrincipalID: &user1},
+			{StartDate: &day2, PrincipalID: &user1},
+		}, usages)
+		mocksRwd.AssertNumberOfCalls(t, "ListByMonth", 2)
+	})
+}
@@ -0,0 +1,220 @@
+package usage
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// SummaryGroupBy is what usage is rolled up by in a Summary
+type SummaryGroupBy string
+
+const (
+	// SummaryGroupByPrincipal rolls up usage by principal ID
+	SummaryGroupByPrincipal SummaryGroupBy = "principal"
+	// SummaryGroupByAccount rolls up usage by AWS Account ID
+	SummaryGroupByAccount SummaryGroupBy = "account"
+	// SummaryGroupByLease rolls up usage by lease ID
+	SummaryGroupByLease SummaryGroupBy = "lease"
+)
+
+// SummaryPeriod is the length of time covered by a Summary
+type SummaryPeriod string
+
+const (
+	// SummaryPeriodWeekly covers a week, starting on Sunday
+	SummaryPeriodWeekly SummaryPeriod = "weekly"
+	// SummaryPeriodMonthly covers a calendar month
+	SummaryPeriodMonthly SummaryPeriod = "monthly"
+)
+
+// DefaultSummaryLimit is the number of top spenders returned in a Summary
+const DefaultSummaryLimit = 10
+
+// UnknownSummaryID is used for usage which can't be attributed to a group
+// (eg. usage recorded after its lease was deleted)
+const UnknownSummaryID = "Unknown"
+
+// Range returns the start and end of the period containing the given date.
+// The end date is exclusive.
+func (p SummaryPeriod) Range(date time.Time) (time.Time, time.Time) {
+	date = date.UTC()
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	switch p {
+	case SummaryPeriodWeekly:
+		start := day.AddDate(0, 0, -int(day.Weekday()))
+		return start, start.AddDate(0, 0, 7)
+	default:
+		start := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 1, 0)
+	}
+}
+
+// SummaryInput is the input for summarizing usage
+type SummaryInput struct {
+	GroupBy SummaryGroupBy
+	Period  SummaryPeriod
+	// Any date within the period to summarize
+	Date time.Time
+	// Number of top spenders to return
+	Limit int
+	// LeaseID returns the ID of the lease of the principal on the account
+	// at the given usage start date, or an empty string.
+	// Used to group usage recorded before spend was tracked by lease.
+	LeaseID func(principalID string, accountID string, startDate int64) (string, error)
+}
+
+// Validate the summary input
+func (input *SummaryInput) Validate() error {
+	switch input.GroupBy {
+	case SummaryGroupByPrincipal, SummaryGroupByAccount, SummaryGroupByLease:
+	default:
+		return fmt.Errorf("groupBy must be one of %q, %q or %q", SummaryGroupByPrincipal, SummaryGroupByAccount, SummaryGroupByLease)
+	}
+	switch input.Period {
+	case SummaryPeriodWeekly, SummaryPeriodMonthly:
+	default:
+		return fmt.Errorf("period must be one of %q or %q", SummaryPeriodWeekly, SummaryPeriodMonthly)
+	}
+	if input.Limit < 0 {
+		return fmt.Errorf("limit must not be negative")
+	}
+	return nil
+}
+
+// Summary rolls up the usage within a period
+type Summary struct {
+	GroupBy      SummaryGroupBy `json:"groupBy"`
+	Period       SummaryPeriod  `json:"period"`
+	StartDate    int64          `json:"startDate"`    // Period start date Epoch Timestamp
+	EndDate      int64          `json:"endDate"`      // Period end date Epoch Timestamp (exclusive)
+	CostCurrency string         `json:"costCurrency"` // Cost currency
+	// Cost Amount across every group
+	TotalCostAmount float64 `json:"totalCostAmount"`
+	// Average Cost Amount of each group
+	AverageCostAmount float64 `json:"averageCostAmount"`
+	// Number of groups with usage in the period
+	Count int `json:"count"`
+	// Groups with the highest Cost Amount, most expensive first
+	TopSpenders []*SummaryItem `json:"topSpenders"`
+}
+
+// SummaryItem is the usage of a single principal, account or lease within a period
+type SummaryItem struct {
+	ID                     string  `json:"id"`
+	CostAmount             float64 `json:"costAmount"`
+	AverageDailyCostAmount float64 `json:"averageDailyCostAmount"`
+}
+
+// NewSummary rolls up usage records within the period containing the input date
+func NewSummary(input *SummaryInput, usages Usages) (*Summary, error) {
+	start, end := input.Period.Range(input.Date)
+	limit := input.Limit
+	if limit == 0 {
+		limit = DefaultSummaryLimit
+	}
+
+	summary := &Summary{
+		GroupBy:      input.GroupBy,
+		Period:       input.Period,
+		StartDate:    start.Unix(),
+		EndDate:      end.Unix(),
+		CostCurrency: "USD",
+		TopSpenders:  []*SummaryItem{},
+	}
+
+	costs := map[string]float64{}
+	for _, usg := range usages {
+		if usg.StartDate == nil || *usg.StartDate < start.Unix() || *usg.StartDate >= end.Unix() {
+			continue
+		}
+		if usg.CostCurrency != nil && *usg.CostCurrency != "" {
+			summary.CostCurrency = *usg.CostCurrency
+		}
+		usgCosts, err := summaryCosts(input, &usg)
+		if err != nil {
+			return nil, err
+		}
+		for id, costAmount := range usgCosts {
+			costs[id] = costs[id] + costAmount
+		}
+	}
+
+	days := end.Sub(start).Hours() / 24
+	items := make([]*SummaryItem, 0, len(costs))
+	for id, costAmount := range costs {
+		summary.TotalCostAmount = summary.TotalCostAmount + costAmount
+		items = append(items, &SummaryItem{
+			ID:                     id,
+			CostAmount:             costAmount,
+			AverageDailyCostAmount: costAmount / days,
+		})
+	}
+	summary.Count = len(items)
+	if summary.Count > 0 {
+		summary.AverageCostAmount = summary.TotalCostAmount / float64(summary.Count)
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].CostAmount != items[j].CostAmount {
+			return items[i].CostAmount > items[j].CostAmount
+		}
+		return items[i].ID < items[j].ID
+	})
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	summary.TopSpenders = items
+
+	return summary, nil
+}
+
+// summaryCosts returns the cost amount of a usage record, by group ID
+func summaryCosts(input *SummaryInput, usg *Usage) (map[string]float64, error) {
+	if input.GroupBy == SummaryGroupByPrincipal {
+		costAmount := float64(0)
+		if usg.CostAmount != nil {
+			costAmount = *usg.CostAmount
+		}
+		return map[string]float64{stringOrUnknown(usg.PrincipalID): costAmount}, nil
+	}
+
+	// Usage recorded before the spend of each account was tracked
+	// is attributed to a single account
+	accountCosts := usg.AccountCosts
+	if accountCosts == nil {
+		accountCosts = map[string]float64{}
+		if usg.CostAmount != nil {
+			accountCosts[stringOrUnknown(usg.AccountID)] = *usg.CostAmount
+		}
+	}
+	if input.GroupBy == SummaryGroupByAccount {
+		return accountCosts, nil
+	}
+
+	if usg.LeaseCosts != nil {
+		return usg.LeaseCosts, nil
+	}
+	costs := map[string]float64{}
+	for accountID, costAmount := range accountCosts {
+		id := UnknownSummaryID
+		if input.LeaseID != nil && usg.PrincipalID != nil && usg.StartDate != nil {
+			leaseID, err := input.LeaseID(*usg.PrincipalID, accountID, *usg.StartDate)
+			if err != nil {
+				return nil, err
+			}
+			if leaseID != "" {
+				id = leaseID
+			}
+		}
+		costs[id] = costs[id] + costAmount
+	}
+	return costs, nil
+}
+
+func stringOrUnknown(s *string) string {
+	if s == nil || *s == "" {
+		return UnknownSummaryID
+	}
+	return *s
+}
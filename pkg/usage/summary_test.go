@@ -0,0 +1,231 @@
+package usage_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Optum/dce/pkg/usage"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummaryPeriodRange(t *testing.T) {
+	// Wednesday, October 16th 2019
+	date := time.Date(2019, 10, 16, 13, 30, 0, 0, time.UTC)
+
+	start, end := usage.SummaryPeriodWeekly.Range(date)
+	assert.Equal(t, time.Date(2019, 10, 13, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2019, 10, 20, 0, 0, 0, 0, time.UTC), end)
+
+	start, end = usage.SummaryPeriodMonthly.Range(date)
+	assert.Equal(t, time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2019, 11, 1, 0, 0, 0, 0, time.UTC), end)
+}
+
+func TestNewSummary(t *testing.T) {
+	date := time.Date(2019, 10, 16, 0, 0, 0, 0, time.UTC)
+	day1 := time.Date(2019, 10, 14, 0, 0, 0, 0, time.UTC).Unix()
+	day2 := time.Date(2019, 10, 15, 0, 0, 0, 0, time.UTC).Unix()
+	lastWeek := time.Date(2019, 10, 7, 0, 0, 0, 0, time.UTC).Unix()
+
+	usages := usage.Usages{
+		{
+			PrincipalID:  aws.String("user1"),
+			AccountID:    aws.String("111111111111"),
+			StartDate:    aws.Int64(day1),
+			CostAmount:   aws.Float64(7),
+			CostCurrency: aws.String("USD"),
+		},
+		{
+			PrincipalID:  aws.String("user1"),
+			AccountID:    aws.String("111111111111"),
+			StartDate:    aws.Int64(day2),
+			CostAmount:   aws.Float64(21),
+			CostCurrency: aws.String("USD"),
+			AccountCosts: map[string]float64{
+				"111111111111": 14,
+				"222222222222": 7,
+			},
+		},
+		{
+			PrincipalID:  aws.String("user2"),
+			AccountID:    aws.String("333333333333"),
+			StartDate:    aws.Int64(day2),
+			CostAmount:   aws.Float64(14),
+			CostCurrency: aws.String("USD"),
+		},
+		{
+			PrincipalID:  aws.String("user4"),
+			AccountID:    aws.String("555555555555"),
+			LeaseID:      aws.String("lease4"),
+			StartDate:    aws.Int64(day2),
+			CostAmount:   aws.Float64(21),
+			CostCurrency: aws.String("USD"),
+			AccountCosts: map[string]float64{
+				"555555555555": 21,
+			},
+			LeaseCosts: map[string]float64{
+				"lease4": 21,
+			},
+		},
+		{
+			PrincipalID:  aws.String("user3"),
+			AccountID:    aws.String("444444444444"),
+			StartDate:    aws.Int64(lastWeek),
+			CostAmount:   aws.Float64(100),
+			CostCurrency: aws.String("USD"),
+		},
+	}
+
+	leaseID := func(principalID string, accountID string, startDate int64) (string, error) {
+		if principalID == "user1" && accountID == "111111111111" {
+			return "lease1", nil
+		}
+		if principalID == "user2" {
+			return "lease2", nil
+		}
+		return "", nil
+	}
+
+	tests := []struct {
+		name  string
+		input usage.SummaryInput
+		exp   *usage.Summary
+	}{
+		{
+			name: "should summarize weekly usage by principal",
+			input: usage.SummaryInput{
+				GroupBy: usage.SummaryGroupByPrincipal,
+				Period:  usage.SummaryPeriodWeekly,
+				Date:    date,
+			},
+			exp: &usage.Summary{
+				GroupBy:           usage.SummaryGroupByPrincipal,
+				Period:            usage.SummaryPeriodWeekly,
+				StartDate:         time.Date(2019, 10, 13, 0, 0, 0, 0, time.UTC).Unix(),
+				EndDate:           time.Date(2019, 10, 20, 0, 0, 0, 0, time.UTC).Unix(),
+				CostCurrency:      "USD",
+				TotalCostAmount:   63,
+				AverageCostAmount: 21,
+				Count:             3,
+				TopSpenders: []*usage.SummaryItem{
+					{ID: "user1", CostAmount: 28, AverageDailyCostAmount: 4},
+					{ID: "user4", CostAmount: 21, AverageDailyCostAmount: 3},
+					{ID: "user2", CostAmount: 14, AverageDailyCostAmount: 2},
+				},
+			},
+		},
+		{
+			name: "should summarize weekly usage by account",
+			input: usage.SummaryInput{
+				GroupBy: usage.SummaryGroupByAccount,
+				Period:  usage.SummaryPeriodWeekly,
+				Date:    date,
+				Limit:   2,
+			},
+			exp: &usage.Summary{
+				GroupBy:           usage.SummaryGroupByAccount,
+				Period:            usage.SummaryPeriodWeekly,
+				StartDate:         time.Date(2019, 10, 13, 0, 0, 0, 0, time.UTC).Unix(),
+				EndDate:           time.Date(2019, 10, 20, 0, 0, 0, 0, time.UTC).Unix(),
+				CostCurrency:      "USD",
+				TotalCostAmount:   63,
+				AverageCostAmount: 15.75,
+				Count:             4,
+				TopSpenders: []*usage.SummaryItem{
+					{ID: "111111111111", CostAmount: 21, AverageDailyCostAmount: 3},
+					{ID: "555555555555", CostAmount: 21, AverageDailyCostAmount: 3},
+				},
+			},
+		},
+		{
+			name: "should summarize weekly usage by lease",
+			input: usage.SummaryInput{
+				GroupBy: usage.SummaryGroupByLease,
+				Period:  usage.SummaryPeriodWeekly,
+				Date:    date,
+				LeaseID: leaseID,
+			},
+			exp: &usage.Summary{
+				GroupBy:           usage.SummaryGroupByLease,
+				Period:            usage.SummaryPeriodWeekly,
+				StartDate:         time.Date(2019, 10, 13, 0, 0, 0, 0, time.UTC).Unix(),
+				EndDate:           time.Date(2019, 10, 20, 0, 0, 0, 0, time.UTC).Unix(),
+				CostCurrency:      "USD",
+				TotalCostAmount:   63,
+				AverageCostAmount: 15.75,
+				Count:             4,
+				TopSpenders: []*usage.SummaryItem{
+					{ID: "lease1", CostAmount: 21, AverageDailyCostAmount: 3},
+					{ID: "lease4", CostAmount: 21, AverageDailyCostAmount: 3},
+					{ID: "lease2", CostAmount: 14, AverageDailyCostAmount: 2},
+					{ID: usage.UnknownSummaryID, CostAmount: 7, AverageDailyCostAmount: 1},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary, err := usage.NewSummary(&tt.input, usages)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.exp, summary)
+		})
+	}
+
+	t.Run("should fail when the lease of usage can't be found", func(t *testing.T) {
+		_, err := usage.NewSummary(&usage.SummaryInput{
+			GroupBy: usage.SummaryGroupByLease,
+			Period:  usage.SummaryPeriodWeekly,
+			Date:    date,
+			LeaseID: func(principalID string, accountID string, startDate int64) (string, error) {
+				return "", fmt.Errorf("failure")
+			},
+		}, usages)
+		assert.Equal(t, fmt.Errorf("failure"), err)
+	})
+}
+
+func TestSummaryInputValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  usage.SummaryInput
+		expErr string
+	}{
+		{
+			name: "should be valid",
+			input: usage.SummaryInput{
+				GroupBy: usage.SummaryGroupByAccount,
+				Period:  usage.SummaryPeriodMonthly,
+			},
+		},
+		{
+			name: "should fail on an unsupported groupBy",
+			input: usage.SummaryInput{
+				GroupBy: "service",
+				Period:  usage.SummaryPeriodMonthly,
+			},
+			expErr: "groupBy must be one of \"principal\", \"account\" or \"lease\"",
+		},
+		{
+			name: "should fail on an unsupported period",
+			input: usage.SummaryInput{
+				GroupBy: usage.SummaryGroupByPrincipal,
+				Period:  "daily",
+			},
+			expErr: "period must be one of \"weekly\" or \"monthly\"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.input.Validate()
+			if tt.expErr == "" {
+				assert.Nil(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.expErr)
+		})
+	}
+}
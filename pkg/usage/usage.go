@@ -9,11 +9,17 @@ import (
 
 	"github.com/Optum/dce/pkg/common"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
 )
 
+// maxLeaseUsageRetries is the number of times UpdateLeaseUsage retries,
+// when the usage record is updated concurrently
+const maxLeaseUsageRetries = 5
+
 /*
 The `UsageDB` service abstracts all interactions
 with the DynamoDB usage table
@@ -22,11 +28,13 @@ with the DynamoDB usage table
 // DB contains DynamoDB client and table names
 type DB struct {
 	// DynamoDB Client
-	Client *dynamodb.DynamoDB
+	Client dynamodbiface.DynamoDBAPI
 	// Name of the Usage table
-	UsageTableName   string
-	PartitionKeyName string
-	SortKeyName      string
+	UsageTableName string
+	// Name of the Lease Usage table, with the usage of each lease
+	LeaseUsageTableName string
+	PartitionKeyName    string
+	SortKeyName         string
 	// Use Consistent Reads when scanning or querying.  When possbile.
 	ConsistentRead bool
 }
@@ -35,12 +43,15 @@ type DB struct {
 // Usage DynamoDB. This is useful if we want to mock the DB service.
 type DBer interface {
 	PutUsage(input Usage) error
+	UpdateLeaseUsage(input Usage) error
 	GetUsageByDateRange(startDate time.Time, endDate time.Time) ([]*Usage, error)
 	GetUsageByPrincipal(startDate time.Time, principalID string) ([]*Usage, error)
+	GetUsageByLease(leaseID string, startDate time.Time, endDate time.Time) ([]*Usage, error)
 }
 
 // PutUsage adds an item to Usage DB
 func (db *DB) PutUsage(input Usage) error {
+	input.StartMonth = aws.String(StartMonthOf(*input.StartDate))
 	item, err := dynamodbattribute.MarshalMap(input)
 	if err != nil {
 		errorMessage := fmt.Sprintf("Failed to add usage record for start date \"%d\" and PrincipalID \"%s\": %s.", *input.StartDate, *input.PrincipalID, err)
@@ -57,6 +68,73 @@ func (db *DB) PutUsage(input Usage) error {
 	return err
 }
 
+// UpdateLeaseUsage records the spend of a lease for a day, on the usage record
+// of its principal for the day, and on the lease usage record of the lease. The record is shared by all the leases of the
+// principal, so it is updated with a conditional write on its version,
+// and retried if another lease updated it concurrently.
+// ServiceCosts of the input are the costs of the lease by AWS service.
+func (db *DB) UpdateLeaseUsage(input Usage) error {
+	for attempt := 1; ; attempt++ {
+		resp, err := db.Client.GetItem(getInputForGetUsageByPrincipalID(db, time.Unix(*input.StartDate, 0), *input.PrincipalID, true))
+		if err != nil {
+			return err
+		}
+
+		record := Usage{}
+		condition := "attribute_not_exists(PrincipalId)"
+		values := map[string]*dynamodb.AttributeValue{}
+		if len(resp.Item) > 0 {
+			err = dynamodbattribute.UnmarshalMap(resp.Item, &record)
+			if err != nil {
+				return err
+			}
+			if record.Version == nil {
+				condition = "attribute_not_exists(Version)"
+			} else {
+				condition = "Version = :version"
+				values[":version"] = &dynamodb.AttributeValue{N: aws.String(strconv.FormatInt(*record.Version, 10))}
+			}
+		}
+		record.MergeLeaseUsage(input)
+
+		item, err := dynamodbattribute.MarshalMap(record)
+		if err != nil {
+			return err
+		}
+		putInput := &dynamodb.PutItemInput{
+			TableName:           aws.String(db.UsageTableName),
+			Item:                item,
+			ConditionExpression: aws.String(condition),
+		}
+		if len(values) > 0 {
+			putInput.ExpressionAttributeValues = values
+		}
+		_, err = db.Client.PutItem(putInput)
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException && attempt < maxLeaseUsageRetries {
+			log.Printf("Usage record for start date \"%d\" and PrincipalID \"%s\" was updated concurrently, retrying", *input.StartDate, *input.PrincipalID)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		return db.putLeaseUsage(input)
+	}
+}
+
+// putLeaseUsage records the usage of a lease for a day, on its own record
+// in the Lease Usage table. Only the lease writes its own records.
+func (db *DB) putLeaseUsage(input Usage) error {
+	item, err := dynamodbattribute.MarshalMap(input)
+	if err != nil {
+		return err
+	}
+	_, err = db.Client.PutItem(&dynamodb.PutItemInput{
+		TableName: aws.String(db.LeaseUsageTableName),
+		Item:      item,
+	})
+	return err
+}
+
 // GetUsageByDateRange returns usage amount for all leases for input date range
 // startDate and endDate are epoch Unix dates
 func (db *DB) GetUsageByDateRange(startDate time.Time, endDate time.Time) ([]*Usage, error) {
@@ -170,6 +248,52 @@ func (db *DB) GetUsageByPrincipal(startDate time.Time, principalID string) ([]*U
 	return output, nil
 }
 
+// GetUsageByLease returns the daily usage of a lease for input date range,
+// from the Lease Usage table.
+// The Lease Usage table is the record of a lease's spend. The LeaseCosts
+// of the principal's usage records are a copy, for usage reports by lease.
+func (db *DB) GetUsageByLease(leaseID string, startDate time.Time, endDate time.Time) ([]*Usage, error) {
+	usageStartDate := time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, time.UTC)
+	usageEndDate := time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 23, 59, 59, 0, time.UTC)
+
+	output := make([]*Usage, 0)
+	if usageEndDate.Before(usageStartDate) {
+		return output, nil
+	}
+
+	queryInput := &dynamodb.QueryInput{
+		TableName:              aws.String(db.LeaseUsageTableName),
+		KeyConditionExpression: aws.String("LeaseId = :leaseId and StartDate between :startDate and :endDate"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":leaseId":   {S: aws.String(leaseID)},
+			":startDate": {N: aws.String(strconv.FormatInt(usageStartDate.Unix(), 10))},
+			":endDate":   {N: aws.String(strconv.FormatInt(usageEndDate.Unix(), 10))},
+		},
+		ConsistentRead: aws.Bool(db.ConsistentRead),
+	}
+
+	for {
+		resp, err := db.Client.Query(queryInput)
+		if err != nil {
+			log.Printf("Failed to query usage of lease \"%s\": %s.", leaseID, err)
+			return nil, err
+		}
+
+		for _, r := range resp.Items {
+			usageRecord, err := unmarshalUsageRecord(r)
+			if err != nil {
+				return nil, err
+			}
+			output = append(output, usageRecord)
+		}
+
+		if len(resp.LastEvaluatedKey) == 0 {
+			return output, nil
+		}
+		queryInput.ExclusiveStartKey = resp.LastEvaluatedKey
+	}
+}
+
 // GetUsageInput contains the filtering criteria for the GetUsage scan.
 type GetUsageInput struct {
 	StartKeys   map[string]string
@@ -286,13 +410,14 @@ Requires env vars for:
 
 - AWS_CURRENT_REGION
 - USAGE_CACHE_DB
+- LEASE_USAGE_DB
 */
 func NewFromEnv() (*DB, error) {
 	awsSession, err := session.NewSession()
 	if err != nil {
 		return nil, err
 	}
-	return New(
+	db := New(
 		dynamodb.New(
 			awsSession,
 			aws.NewConfig().WithRegion(common.RequireEnv("AWS_CURRENT_REGION")),
@@ -300,7 +425,9 @@ func NewFromEnv() (*DB, error) {
 		common.RequireEnv("USAGE_CACHE_DB"),
 		"StartDate",
 		"PrincipalId",
-	), nil
+	)
+	db.LeaseUsageTableName = common.RequireEnv("LEASE_USAGE_DB")
+	return db, nil
 }
 
 func unmarshalUsageRecord(dbResult map[string]*dynamodb.AttributeValue) (*Usage, error) {
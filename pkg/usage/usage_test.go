@@ -0,0 +1,143 @@
+package usage_test
+
+import (
+	"testing"
+	"time"
+
+	awsMocks "github.com/Optum/dce/pkg/awsiface/mocks"
+	"github.com/Optum/dce/pkg/usage"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUpdateLeaseUsage(t *testing.T) {
+	leaseUsage := usage.Usage{
+		PrincipalID: aws.String("test-user"),
+		AccountID:   aws.String("123456789012"),
+		LeaseID:     aws.String("test-lease"),
+		StartDate:   aws.Int64(1580515200),
+		CostAmount:  aws.Float64(10),
+	}
+	newDB := func(dynamoSvc *awsMocks.DynamoDBAPI) *usage.DB {
+		return &usage.DB{
+			Client:              dynamoSvc,
+			UsageTableName:      "Usage",
+			LeaseUsageTableName: "LeaseUsage",
+			PartitionKeyName:    "StartDate",
+			SortKeyName:         "PrincipalId",
+		}
+	}
+
+	isLeaseUsage := func(input *dynamodb.PutItemInput) bool {
+		return *input.TableName == "LeaseUsage"
+	}
+
+	t.Run("should create the usage records of the principal and the lease", func(t *testing.T) {
+		dynamoSvc := &awsMocks.DynamoDBAPI{}
+		dynamoSvc.On("GetItem", mock.MatchedBy(func(input *dynamodb.GetItemInput) bool {
+			return *input.ConsistentRead
+		})).Return(&dynamodb.GetItemOutput{}, nil)
+		dynamoSvc.On("PutItem", mock.MatchedBy(func(input *dynamodb.PutItemInput) bool {
+			return *input.TableName == "Usage" &&
+				*input.ConditionExpression == "attribute_not_exists(PrincipalId)" &&
+				*input.Item["Version"].N == "1" &&
+				*input.Item["StartMonth"].S == "2020-02" &&
+				*input.Item["LeaseCosts"].M["test-lease"].N == "10"
+		})).Return(&dynamodb.PutItemOutput{}, nil)
+		dynamoSvc.On("PutItem", mock.MatchedBy(func(input *dynamodb.PutItemInput) bool {
+			return isLeaseUsage(input) &&
+				*input.Item["LeaseId"].S == "test-lease" &&
+				*input.Item["StartDate"].N == "1580515200" &&
+				*input.Item["CostAmount"].N == "10"
+		})).Return(&dynamodb.PutItemOutput{}, nil)
+
+		assert.Nil(t, newDB(dynamoSvc).UpdateLeaseUsage(leaseUsage))
+		dynamoSvc.AssertExpectations(t)
+	})
+
+	t.Run("should retry when the record is updated concurrently", func(t *testing.T) {
+		dynamoSvc := &awsMocks.DynamoDBAPI{}
+		dynamoSvc.On("GetItem", mock.Anything).Return(&dynamodb.GetItemOutput{
+			Item: map[string]*dynamodb.AttributeValue{
+				"StartDate":   {N: aws.String("1580515200")},
+				"PrincipalId": {S: aws.String("test-user")},
+				"CostAmount":  {N: aws.String("20")},
+				"AccountCosts": {M: map[string]*dynamodb.AttributeValue{
+					"210987654321": {N: aws.String("20")},
+				}},
+				"Version": {N: aws.String("2")},
+			},
+		}, nil).Twice()
+		dynamoSvc.On("PutItem", mock.MatchedBy(isLeaseUsage)).Return(&dynamodb.PutItemOutput{}, nil).Once()
+		dynamoSvc.On("PutItem", mock.Anything).Return(nil,
+			awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "The conditional request failed", nil),
+		).Once()
+		dynamoSvc.On("PutItem", mock.MatchedBy(func(input *dynamodb.PutItemInput) bool {
+			return *input.TableName == "Usage" &&
+				*input.ConditionExpression == "Version = :version" &&
+				*input.ExpressionAttributeValues[":version"].N == "2" &&
+				*input.Item["Version"].N == "3" &&
+				*input.Item["CostAmount"].N == "30"
+		})).Return(&dynamodb.PutItemOutput{}, nil).Once()
+
+		assert.Nil(t, newDB(dynamoSvc).UpdateLeaseUsage(leaseUsage))
+		dynamoSvc.AssertExpectations(t)
+	})
+
+	t.Run("should give up after retrying", func(t *testing.T) {
+		dynamoSvc := &awsMocks.DynamoDBAPI{}
+		dynamoSvc.On("GetItem", mock.Anything).Return(&dynamodb.GetItemOutput{}, nil)
+		dynamoSvc.On("PutItem", mock.Anything).Return(nil,
+			awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "The conditional request failed", nil),
+		)
+
+		err := newDB(dynamoSvc).UpdateLeaseUsage(leaseUsage)
+		assert.NotNil(t, err)
+		dynamoSvc.AssertNumberOfCalls(t, "PutItem", 5)
+	})
+}
+
+func TestGetUsageByLease(t *testing.T) {
+	dynamoSvc := &awsMocks.DynamoDBAPI{}
+	db := &usage.DB{
+		Client:              dynamoSvc,
+		UsageTableName:      "Usage",
+		LeaseUsageTableName: "LeaseUsage",
+	}
+	lastKey := map[string]*dynamodb.AttributeValue{
+		"LeaseId":   {S: aws.String("test-lease")},
+		"StartDate": {N: aws.String("1580515200")},
+	}
+
+	isLeaseQuery := func(input *dynamodb.QueryInput) bool {
+		return *input.TableName == "LeaseUsage" &&
+			*input.ExpressionAttributeValues[":leaseId"].S == "test-lease" &&
+			*input.ExpressionAttributeValues[":startDate"].N == "1580515200" &&
+			*input.ExpressionAttributeValues[":endDate"].N == "1580687999"
+	}
+	dynamoSvc.On("Query", mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+		return isLeaseQuery(input) && input.ExclusiveStartKey == nil
+	})).Return(&dynamodb.QueryOutput{
+		Items: []map[string]*dynamodb.AttributeValue{
+			{"LeaseId": {S: aws.String("test-lease")}, "StartDate": {N: aws.String("1580515200")}, "CostAmount": {N: aws.String("10")}},
+		},
+		LastEvaluatedKey: lastKey,
+	}, nil).Once()
+	dynamoSvc.On("Query", mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+		return isLeaseQuery(input) && input.ExclusiveStartKey != nil
+	})).Return(&dynamodb.QueryOutput{
+		Items: []map[string]*dynamodb.AttributeValue{
+			{"LeaseId": {S: aws.String("test-lease")}, "StartDate": {N: aws.String("1580601600")}, "CostAmount": {N: aws.String("5")}},
+		},
+	}, nil).Once()
+
+	usageRecords, err := db.GetUsageByLease("test-lease", time.Unix(1580515200, 0), time.Unix(1580601600, 0))
+	assert.Nil(t, err)
+	assert.Len(t, usageRecords, 2)
+	assert.Equal(t, 10.0, *usageRecords[0].CostAmount)
+	assert.Equal(t, 5.0, *usageRecords[1].CostAmount)
+	dynamoSvc.AssertExpectations(t)
+}
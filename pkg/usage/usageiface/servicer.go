@@ -0,0 +1,24 @@
+//
+
+package usageiface
+
+import (
+	"time"
+
+	"github.com/Optum/dce/pkg/usage"
+)
+
+// Servicer makes working with the Usage Service struct easier
+type Servicer interface {
+	// Get returns an usage from startDate and principalID
+	Get(startDate int64, principalID string) (*usage.Usage, error)
+	// Create creates a new usage record
+	Create(data *usage.Usage) (*usage.Usage, error)
+	// List Get a list of usages based on a query
+	List(query *usage.Usage) (*usage.Usages, error)
+	// Summarize rolls up the usage within a period by principal, account or lease
+	Summarize(input *usage.SummaryInput) (*usage.Summary, error)
+	// ListByDateRange returns every usage record with a start date
+	// between the start (inclusive) and end (exclusive) dates
+	ListByDateRange(start time.Time, end time.Time) (*usage.Usages, error)
+}
@@ -1,8 +1,10 @@
 package usage
 
 import (
+	"errors"
 	"regexp"
 
+	"github.com/Optum/dce/pkg/budget"
 	validation "github.com/go-ozzo/ozzo-validation"
 )
 
@@ -18,6 +20,10 @@ var validateAccountID = []validation.Rule{
 	validation.Match(regexp.MustCompile("^[0-9]{12}$")).Error("must be a string with 12 digits"),
 }
 
+var validateLeaseID = []validation.Rule{
+	validation.NilOrNotEmpty.Error("must be a valid lease ID"),
+}
+
 var validateInt64 = []validation.Rule{
 	validation.NotNil.Error("must be an epoch timestamp"),
 }
@@ -28,8 +34,17 @@ var validateFloat64 = []validation.Rule{
 
 var validateCostCurrency = []validation.Rule{
 	validation.NotNil.Error("must be a valid cost concurrency"),
+	validation.By(isCurrency),
 }
 
 var validateTimeToLive = []validation.Rule{
 	validation.NotNil.Error("must be a valid time to live"),
 }
+
+func isCurrency(value interface{}) error {
+	s, _ := value.(*string)
+	if s != nil && *s != "" && !budget.IsValidCurrency(*s) {
+		return errors.New("must be an ISO 4217 currency code")
+	}
+	return nil
+}
@@ -32,6 +32,25 @@ func TestValidate(t *testing.T) {
 			},
 			expErr: errors.NewValidation("usage", fmt.Errorf("accountId: must be a string with 12 digits.")), //nolint golint
 		},
+		{
+			name: "should validate cost currency",
+			usage: usage.NewUsageInput{
+				StartDate:    1580924093,
+				AccountID:    "123456789012",
+				PrincipalID:  "user1",
+				CostCurrency: "EUR",
+			},
+		},
+		{
+			name: "should not validate invalid cost currency",
+			usage: usage.NewUsageInput{
+				StartDate:    1580924093,
+				AccountID:    "123456789012",
+				PrincipalID:  "user1",
+				CostCurrency: "Dollars",
+			},
+			expErr: errors.NewValidation("usage", fmt.Errorf("costCurrency: must be an ISO 4217 currency code.")), //nolint golint
+		},
 	}
 
 	for _, tt := range tests {
@@ -58,12 +58,25 @@ zip -j --must-match \
     cmd/codebuild/reset/buildspec.yml \
     cmd/codebuild/reset/default-nuke-config-template.yml
 
+# Build Account Reset Worker
+# Builds to `/bin/worker/reset.zip`, with the reset build and a Dockerfile
+cd cmd/worker/reset/
+GOARCH=amd64 GOOS=linux go build -o ../../../bin/worker/reset-worker ./...
+cd ../../../
+zip -j --must-match \
+    bin/worker/reset.zip \
+    bin/worker/reset-worker \
+    bin/codebuild/reset \
+    cmd/worker/reset/Dockerfile \
+    cmd/codebuild/reset/default-nuke-config-template.yml
+
 # Build Lambda/CodeBuild Artifact
 cd bin
 zip --must-match \
     build_artifacts.zip \
     lambda/*.zip \
-    codebuild/*.zip
+    codebuild/*.zip \
+    worker/*.zip
 
 # Build Terraform Artifact
 cd ..
@@ -73,4 +86,4 @@ zip -r --must-match \
     -x modules/.terraform/\* modules/*.tfstate* modules/*.tfvars modules/*.zip
 
 # Cleanup 
-rm -rf bin/codebuild bin/lambda
\ No newline at end of file
+rm -rf bin/codebuild bin/lambda bin/worker
\ No newline at end of file
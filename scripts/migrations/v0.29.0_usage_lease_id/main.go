@@ -0,0 +1,223 @@
+/*
+Migration for v0.29.0
+
+Usage is now attributed to the lease which incurred the spend. The daily
+usage of each lease is recorded in the Lease Usage table, keyed by `LeaseId`,
+which lease budgets are checked against. Usage records of a principal have
+a `LeaseCosts` breakdown, for usage reports by lease.
+Previously, lease spend was filtered by principal and account, so repeat leases
+of the same account by the same principal mixed their spend together.
+
+This migration backfills `LeaseId` and `LeaseCosts` on existing usage records,
+and the records of each lease in the Lease Usage table.
+The Leases table only holds the latest lease of each account by a principal,
+so spend is only attributed to that lease from the day it was created.
+Spend of earlier leases is left unattributed, and is not counted towards
+the budget of any lease.
+
+It is intended to be run as a Golang script:
+"go run main.go"
+
+This script requires environment variables to be set for its use:
+"export AWS_CURRENT_REGION=us-east-1"  - The region the database resides in
+"export LEASE_TABLE=Leases"  - Name of the Leases table
+"export USAGE_TABLE=Usage"  - Name of the Usage table
+"export LEASE_USAGE_TABLE=LeaseUsage"  - Name of the Lease Usage table
+*/
+package main
+
+import (
+	"log"
+	"strconv"
+
+	"github.com/Optum/dce/pkg/common"
+	"github.com/Optum/dce/pkg/db"
+	"github.com/Optum/dce/pkg/usage"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+type leaseKey struct {
+	principalID string
+	accountID   string
+}
+
+func main() {
+	// Create DynamoDB Client
+	awsSession := session.Must(session.NewSession())
+	dynDB := dynamodb.New(
+		awsSession,
+		aws.NewConfig().WithRegion(common.GetEnv("AWS_CURRENT_REGION", "us-east-1")),
+	)
+	leaseTableName := common.RequireEnv("LEASE_TABLE")
+	usageTableName := common.RequireEnv("USAGE_TABLE")
+	leaseUsageTableName := common.RequireEnv("LEASE_USAGE_TABLE")
+
+	// Find the latest lease of each account, by principal
+	leases := map[leaseKey]db.Lease{}
+	err := dynDB.ScanPages(&dynamodb.ScanInput{
+		TableName:      aws.String(leaseTableName),
+		ConsistentRead: aws.Bool(true),
+	}, func(page *dynamodb.ScanOutput, lastPage bool) bool {
+		pageLeases := []db.Lease{}
+		err := dynamodbattribute.UnmarshalListOfMaps(page.Items, &pageLeases)
+		if err != nil {
+			log.Fatalf("failed to unmarshal Lease result items, %v", err)
+		}
+		for _, lease := range pageLeases {
+			leases[leaseKey{lease.PrincipalID, lease.AccountID}] = lease
+		}
+		return true
+	})
+	if err != nil {
+		log.Fatalf("Failed to scan leases: %v", err)
+	}
+
+	updated := 0
+	skipped := 0
+	err = dynDB.ScanPages(&dynamodb.ScanInput{
+		TableName:      aws.String(usageTableName),
+		ConsistentRead: aws.Bool(true),
+	}, func(page *dynamodb.ScanOutput, lastPage bool) bool {
+		usages := []usage.Usage{}
+		err := dynamodbattribute.UnmarshalListOfMaps(page.Items, &usages)
+		if err != nil {
+			log.Fatalf("failed to unmarshal Usage result items, %v", err)
+		}
+
+		for _, usg := range usages {
+			if usg.LeaseCosts != nil || usg.PrincipalID == nil || usg.StartDate == nil {
+				continue
+			}
+
+			leaseCosts, leaseAccounts, leaseID := attributeUsage(&usg, leases)
+			if len(leaseCosts) == 0 {
+				skipped++
+				continue
+			}
+
+			err = updateUsage(dynDB, usageTableName, &usg, leaseCosts, leaseID)
+			if err != nil {
+				log.Printf("Failed to update usage for %s on %d: %v", *usg.PrincipalID, *usg.StartDate, err)
+				continue
+			}
+			for id, costAmount := range leaseCosts {
+				err = putLeaseUsage(dynDB, leaseUsageTableName, &usg, id, leaseAccounts[id], costAmount)
+				if err != nil {
+					log.Printf("Failed to put usage for lease %s on %d: %v", id, *usg.StartDate, err)
+				}
+			}
+			updated++
+		}
+		return true
+	})
+	if err != nil {
+		log.Fatalf("Failed to scan usage: %v", err)
+	}
+
+	log.Printf("Attributed %d usage records to leases. %d usage records could not be attributed.", updated, skipped)
+}
+
+// attributeUsage returns the spend of the usage record by lease ID,
+// the account of each lease, and the lease ID of the usage record's account
+func attributeUsage(usg *usage.Usage, leases map[leaseKey]db.Lease) (map[string]float64, map[string]string, string) {
+	// Usage recorded before the spend of each account was tracked
+	// is attributed to a single account
+	accountCosts := usg.AccountCosts
+	if accountCosts == nil && usg.AccountID != nil && usg.CostAmount != nil {
+		accountCosts = map[string]float64{*usg.AccountID: *usg.CostAmount}
+	}
+
+	// Usage is recorded daily, so the lease must have been created by the end of the day
+	endDate := *usg.StartDate + 24*60*60
+	if usg.EndDate != nil {
+		endDate = *usg.EndDate
+	}
+
+	leaseCosts := map[string]float64{}
+	leaseAccounts := map[string]string{}
+	leaseID := ""
+	for accountID, costAmount := range accountCosts {
+		lease, ok := leases[leaseKey{*usg.PrincipalID, accountID}]
+		if !ok || lease.ID == "" || lease.CreatedOn > endDate {
+			continue
+		}
+		leaseCosts[lease.ID] = leaseCosts[lease.ID] + costAmount
+		leaseAccounts[lease.ID] = accountID
+		if usg.AccountID != nil && *usg.AccountID == accountID {
+			leaseID = lease.ID
+		}
+	}
+	return leaseCosts, leaseAccounts, leaseID
+}
+
+// updateUsage sets the lease attribution of the usage record,
+// unless the update_lease_status lambda has already done so
+func updateUsage(dynDB *dynamodb.DynamoDB, usageTableName string, usg *usage.Usage, leaseCosts map[string]float64, leaseID string) error {
+	leaseCostsAttr, err := dynamodbattribute.Marshal(leaseCosts)
+	if err != nil {
+		return err
+	}
+
+	updateExpression := "set LeaseCosts=:leaseCosts"
+	values := map[string]*dynamodb.AttributeValue{
+		":leaseCosts": leaseCostsAttr,
+	}
+	if leaseID != "" {
+		updateExpression = updateExpression + ", LeaseId=:leaseId"
+		values[":leaseId"] = &dynamodb.AttributeValue{S: aws.String(leaseID)}
+	}
+
+	_, err = dynDB.UpdateItem(&dynamodb.UpdateItemInput{
+		TableName: aws.String(usageTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"StartDate": {
+				N: aws.String(strconv.FormatInt(*usg.StartDate, 10)),
+			},
+			"PrincipalId": {
+				S: usg.PrincipalID,
+			},
+		},
+		UpdateExpression:          aws.String(updateExpression),
+		ConditionExpression:       aws.String("attribute_not_exists(LeaseCosts)"),
+		ExpressionAttributeValues: values,
+	})
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+		log.Printf("Usage for %s on %d was already attributed to leases", *usg.PrincipalID, *usg.StartDate)
+		return nil
+	}
+	return err
+}
+
+// putLeaseUsage adds the record of the lease's usage for the day,
+// unless the update_lease_status lambda has already done so
+func putLeaseUsage(dynDB *dynamodb.DynamoDB, leaseUsageTableName string, usg *usage.Usage, leaseID string, accountID string, costAmount float64) error {
+	item, err := dynamodbattribute.MarshalMap(usage.Usage{
+		PrincipalID:  usg.PrincipalID,
+		AccountID:    aws.String(accountID),
+		LeaseID:      aws.String(leaseID),
+		StartDate:    usg.StartDate,
+		EndDate:      usg.EndDate,
+		CostAmount:   aws.Float64(costAmount),
+		CostCurrency: usg.CostCurrency,
+		TimeToLive:   usg.TimeToLive,
+		ServiceCosts: usg.AccountServiceCosts[accountID],
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = dynDB.PutItem(&dynamodb.PutItemInput{
+		TableName:           aws.String(leaseUsageTableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(LeaseId)"),
+	})
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+		log.Printf("Usage for lease %s on %d was already recorded", leaseID, *usg.StartDate)
+		return nil
+	}
+	return err
+}
@@ -1090,23 +1090,6 @@ func TestApi(t *testing.T) {
 				require.Equal(t, "test-user", lease.PrincipalID)
 				require.Equal(t, accountID, lease.AccountID)
 
-				t.Run("STEP: Delete Account (with Lease)", func(t *testing.T) {
-					// Request a lease
-					apiRequest(t, &apiRequestInput{
-						method: "DELETE",
-						url:    apiURL + "/accounts/" + accountID,
-						json: struct {
-							PrincipalID string `json:"principalId"`
-						}{
-							PrincipalID: "test-user",
-						},
-						f: func(r *testutil.R, apiResp *apiResponse) {
-							assert.Equal(r, 409, apiResp.StatusCode)
-						},
-					})
-
-				})
-
 				t.Run("STEP: Delete Lease", func(t *testing.T) {
 					// Delete the lease
 					apiRequest(t, &apiRequestInput{
@@ -1203,22 +1186,33 @@ func TestApi(t *testing.T) {
 						})
 					})
 
-					t.Run("STEP: Delete Account", func(t *testing.T) {
-						// Delete the account
+					t.Run("STEP: Retire Account", func(t *testing.T) {
+						// Retire the account
 						apiRequest(t, &apiRequestInput{
 							method: "DELETE",
 							url:    apiURL + "/accounts/" + accountID,
 							f: func(r *testutil.R, apiResp *apiResponse) {
-								assert.Equal(r, 204, apiResp.StatusCode)
+								assert.Equal(r, 200, apiResp.StatusCode)
+								data := parseResponseJSON(t, apiResp)
+								assert.Equal(r, "Retiring", data["accountStatus"])
 							},
 						})
 
-						// Attempt to get the deleted account (should 404)
+						// The retired account is kept, for auditing
 						apiRequest(t, &apiRequestInput{
 							method: "GET",
 							url:    apiURL + "/accounts/" + accountID,
 							f: func(r *testutil.R, apiResp *apiResponse) {
-								assert.Equal(t, 404, apiResp.StatusCode)
+								assert.Equal(r, 200, apiResp.StatusCode)
+							},
+						})
+
+						// Retiring the account again should fail
+						apiRequest(t, &apiRequestInput{
+							method: "DELETE",
+							url:    apiURL + "/accounts/" + accountID,
+							f: func(r *testutil.R, apiResp *apiResponse) {
+								assert.Equal(r, 409, apiResp.StatusCode)
 							},
 						})
 